@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+	"github.com/threefoldfoundation/tfchain/pkg/botdns"
+	"github.com/threefoldtech/rivine/pkg/cli"
+	"github.com/threefoldtech/rivine/pkg/daemon"
+)
+
+// Commands defines the CLI Commands for the standalone 3bot DNS server.
+type Commands struct {
+	DaemonAddr   string
+	RootEndpoint string
+	UserAgent    string
+	APIPassword  string
+
+	DNSAddr    string
+	Zone       string
+	TTL        uint32
+	Nameserver string
+	Mailbox    string
+}
+
+// Root represents the root (`botdnsd`) command,
+// serving the 3bot DNS zone until the user intervenes.
+func (cmd *Commands) Root(_ *cobra.Command, args []string) error {
+	registry := newHTTPRegistry(cmd.DaemonAddr, cmd.RootEndpoint, cmd.UserAgent, cmd.APIPassword)
+	srv, err := botdns.NewServer(registry, botdns.Config{
+		Zone:       cmd.Zone,
+		TTL:        cmd.TTL,
+		Nameserver: cmd.Nameserver,
+		Mailbox:    cmd.Mailbox,
+	})
+	if err != nil {
+		return err
+	}
+	err = srv.ListenAndServe(cmd.DNSAddr)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Serving 3bot DNS zone %q on %s, resolved using %s...\n", srv.Zone(), srv.Addr(), registry.url)
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, os.Kill)
+	<-sigChan
+	fmt.Println("\rCaught stop signal, quitting...")
+	return srv.Close()
+}
+
+func main() {
+	cmd := new(Commands)
+
+	rootCmd := &cobra.Command{
+		Use:   "botdnsd",
+		Short: "Serve the names of active 3bots as an authoritative DNS zone",
+		Long: `Serve the names of active 3bots as an authoritative DNS zone,
+resolving each query using the API of a (synced) tfchain daemon.
+
+A and AAAA records are served using the IP addresses of a 3bot,
+a CNAME record is served using its first hostname,
+and a TXT record contains the ID and public key of the 3bot.
+Names of unknown or expired 3bots are answered with NXDOMAIN.
+`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         cmd.Root,
+	}
+
+	rootCmd.Flags().StringVar(
+		&cmd.DaemonAddr,
+		"daemon-addr", "localhost:23110",
+		"the address of the tfchain daemon API used to look up 3bot records")
+	rootCmd.Flags().StringVar(
+		&cmd.RootEndpoint,
+		"daemon-endpoint", "/explorer",
+		"the root endpoint of the tfchain daemon API used, /explorer or /consensus")
+	rootCmd.Flags().StringVar(
+		&cmd.UserAgent,
+		"agent", daemon.RivineUserAgent,
+		"the user agent used for the tfchain daemon API")
+	rootCmd.Flags().StringVar(
+		&cmd.APIPassword,
+		"api-password", "",
+		"the optional password of the tfchain daemon API")
+	rootCmd.Flags().StringVar(
+		&cmd.DNSAddr,
+		"dns-addr", ":53",
+		"the (UDP and TCP) address to serve the 3bot DNS zone on")
+	rootCmd.Flags().StringVar(
+		&cmd.Zone,
+		"dns-zone", botdns.DefaultZone,
+		"the DNS zone in which the names of active 3bots are served")
+	rootCmd.Flags().Uint32Var(
+		&cmd.TTL,
+		"dns-ttl", botdns.DefaultTTL,
+		"the TTL (in seconds) of all served 3bot DNS records")
+	rootCmd.Flags().StringVar(
+		&cmd.Nameserver,
+		"dns-nameserver", "",
+		"the primary nameserver defined in the SOA record of the 3bot DNS zone, defaults to ns.<zone>")
+	rootCmd.Flags().StringVar(
+		&cmd.Mailbox,
+		"dns-mailbox", "",
+		"the mailbox (as a DNS name) defined in the SOA record of the 3bot DNS zone, defaults to hostmaster.<zone>")
+
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(cli.ExitCodeGeneral)
+	}
+}
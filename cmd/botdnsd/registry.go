@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/threefoldfoundation/tfchain/pkg/api"
+	"github.com/threefoldfoundation/tfchain/pkg/types"
+
+	rivineapi "github.com/threefoldtech/rivine/pkg/api"
+)
+
+// httpRegistry implements botdns.Registry,
+// looking up 3bot records using the (explorer or consensus) API of a tfchain daemon.
+type httpRegistry struct {
+	url       string
+	userAgent string
+	password  string
+	client    *http.Client
+}
+
+// newHTTPRegistry creates a new registry for a tfchain daemon API, reachable at the given address,
+// using the given root endpoint (e.g. "/explorer" or "/consensus").
+func newHTTPRegistry(address, rootEndpoint, userAgent, password string) *httpRegistry {
+	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+		address = "http://" + address
+	}
+	return &httpRegistry{
+		url:       strings.TrimSuffix(address, "/") + "/" + strings.Trim(rootEndpoint, "/"),
+		userAgent: userAgent,
+		password:  password,
+		client:    &http.Client{Timeout: time.Second * 5},
+	}
+}
+
+// GetRecordForName implements botdns.Registry.GetRecordForName
+func (reg *httpRegistry) GetRecordForName(name types.BotName) (*types.BotRecord, error) {
+	req, err := http.NewRequest("GET", reg.url+"/whois/3bot/"+url.PathEscape(name.String()), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", reg.userAgent)
+	if reg.password != "" {
+		req.SetBasicAuth("", reg.password)
+	}
+	resp, err := reg.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, types.ErrBotNameNotFound
+	case http.StatusPaymentRequired:
+		return nil, types.ErrBotNameExpired
+	default:
+		var errBody rivineapi.Error
+		if err = json.NewDecoder(resp.Body).Decode(&errBody); err != nil {
+			return nil, errors.New(resp.Status)
+		}
+		return nil, errors.New(errBody.Message)
+	}
+	var result api.TransactionDBGetBotRecord
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Record, nil
+}
@@ -0,0 +1,269 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	erc20 "github.com/threefoldfoundation/tfchain/pkg/eth/erc20"
+	"github.com/threefoldtech/rivine/pkg/cli"
+	rivinec "github.com/threefoldtech/rivine/pkg/client"
+)
+
+// createWithdrawsCmd creates the withdraws command and its subcommands,
+// used to inspect and manage the ERC20 -> TFT conversions of the bridge.
+func createWithdrawsCmd(client *CommandLineClient) *cobra.Command {
+	bridgeSubCmds := &bridgeSubCmds{cli: client}
+
+	var (
+		rootCmd = &cobra.Command{
+			Use:   "withdraws",
+			Short: "List the pending ERC20 withdraws (ERC20 -> TFT conversions)",
+			Run:   rivinec.Wrap(bridgeSubCmds.listWithdraws),
+		}
+		listCmd = &cobra.Command{
+			Use:   "list",
+			Short: "List the ERC20 withdraws (ERC20 -> TFT conversions)",
+			Long: `List the ERC20 withdraws (ERC20 -> TFT conversions).
+By default only the pending withdraws are listed,
+use the --state flag to list the withdraws in a specific state,
+or all withdraws using --state all.`,
+			Run: rivinec.Wrap(bridgeSubCmds.listWithdraws),
+		}
+		getCmd = &cobra.Command{
+			Use:   "get <id>",
+			Short: "Get an ERC20 withdraw by its ERC20 transaction hash or tfchain transaction ID",
+			Run:   rivinec.Wrap(bridgeSubCmds.getWithdraw),
+		}
+		retryCmd = &cobra.Command{
+			Use:   "retry <erc20txhash>",
+			Short: "Force the bridge to process an ERC20 withdraw again (requires the API password)",
+			Run:   rivinec.Wrap(bridgeSubCmds.retryWithdraw),
+		}
+		skipCmd = &cobra.Command{
+			Use:   "skip <erc20txhash>",
+			Short: "Force the bridge to no longer process a pending ERC20 withdraw (requires the API password)",
+			Run:   rivinec.Wrap(bridgeSubCmds.skipWithdraw),
+		}
+	)
+	rootCmd.AddCommand(listCmd, getCmd, retryCmd, skipCmd)
+
+	// register flags
+	listCmd.Flags().StringVar(&bridgeSubCmds.listCfg.State, "state", "pending",
+		"list only the withdraws in the given state, one of {pending,seen,matured,submitted,confirmed,failed,all}")
+	rootCmd.PersistentFlags().Var(
+		cli.NewEncodingTypeFlag(cli.EncodingTypeHuman, &bridgeSubCmds.persistentCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
+		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
+
+	return rootCmd
+}
+
+// createActionsCmd creates the actions command and its subcommands,
+// used to inspect and manage the ETH actions (e.g. TFT -> ERC20 conversions) of the bridge.
+func createActionsCmd(client *CommandLineClient) *cobra.Command {
+	bridgeSubCmds := &bridgeSubCmds{cli: client}
+
+	var (
+		rootCmd = &cobra.Command{
+			Use:   "actions",
+			Short: "List the pending ETH actions (TFT -> ERC20 conversions and address registrations)",
+			Run:   rivinec.Wrap(bridgeSubCmds.listActions),
+		}
+		listCmd = &cobra.Command{
+			Use:   "list",
+			Short: "List the ETH actions (TFT -> ERC20 conversions and address registrations)",
+			Long: `List the ETH actions (TFT -> ERC20 conversions and address registrations).
+By default only the pending actions are listed,
+use the --state flag to list the actions in a specific state,
+or all actions using --state all.`,
+			Run: rivinec.Wrap(bridgeSubCmds.listActions),
+		}
+		getCmd = &cobra.Command{
+			Use:   "get <txid>",
+			Short: "Get an ETH action by the ID of the tfchain transaction it originates from",
+			Run:   rivinec.Wrap(bridgeSubCmds.getAction),
+		}
+		retryCmd = &cobra.Command{
+			Use:   "retry <txid>",
+			Short: "Force the bridge to execute an ETH action again, as soon as possible (requires the API password)",
+			Run:   rivinec.Wrap(bridgeSubCmds.retryAction),
+		}
+		skipCmd = &cobra.Command{
+			Use:   "skip <txid>",
+			Short: "Force the bridge to no longer execute a pending ETH action (requires the API password)",
+			Run:   rivinec.Wrap(bridgeSubCmds.skipAction),
+		}
+	)
+	rootCmd.AddCommand(listCmd, getCmd, retryCmd, skipCmd)
+
+	// register flags
+	listCmd.Flags().StringVar(&bridgeSubCmds.listCfg.State, "state", "pending",
+		"list only the actions in the given state, one of {pending,done,dead,all}")
+	rootCmd.PersistentFlags().Var(
+		cli.NewEncodingTypeFlag(cli.EncodingTypeHuman, &bridgeSubCmds.persistentCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
+		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
+
+	return rootCmd
+}
+
+type bridgeSubCmds struct {
+	cli     *CommandLineClient
+	listCfg struct {
+		State string
+	}
+	persistentCfg struct {
+		EncodingType cli.EncodingType
+	}
+}
+
+func (bridgeSubCmds *bridgeSubCmds) listWithdraws() {
+	var resp erc20.BridgeGetWithdraws
+	err := bridgeSubCmds.cli.GetAPI("/bridge/withdraws?state="+url.QueryEscape(bridgeSubCmds.listCfg.State), &resp)
+	if err != nil {
+		cli.DieWithError("error while fetching the withdraws", err)
+	}
+	if bridgeSubCmds.persistentCfg.EncodingType == cli.EncodingTypeJSON {
+		bridgeSubCmds.encodeJSON(resp.Withdraws)
+		return
+	}
+	if len(resp.Withdraws) == 0 {
+		fmt.Println("no withdraws found")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ERC20 TX HASH\tETH HEIGHT\tSTATE\tAMOUNT\tTFT TX ID\tLAST ERROR")
+	for _, wd := range resp.Withdraws {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n",
+			wd.TxHash.String(), wd.BlockHeight, wd.State.String(), wd.Amount.String(), wd.TFTTransactionID.String(), wd.LastError)
+	}
+	w.Flush()
+}
+
+func (bridgeSubCmds *bridgeSubCmds) getWithdraw(id string) {
+	var resp erc20.BridgeGetWithdraw
+	err := bridgeSubCmds.cli.GetAPI("/bridge/withdraws/"+id, &resp)
+	if err != nil {
+		cli.DieWithError("error while fetching the withdraw", err)
+	}
+	bridgeSubCmds.printWithdraw(resp.Withdraw)
+}
+
+func (bridgeSubCmds *bridgeSubCmds) retryWithdraw(txHash string) {
+	var resp erc20.BridgeGetWithdraw
+	err := bridgeSubCmds.cli.PostResp("/bridge/withdraws/"+txHash+"/retry", "", &resp)
+	if err != nil {
+		cli.DieWithError("error while forcing the bridge to retry the withdraw", err)
+	}
+	bridgeSubCmds.printWithdraw(resp.Withdraw)
+}
+
+func (bridgeSubCmds *bridgeSubCmds) skipWithdraw(txHash string) {
+	var resp erc20.BridgeGetWithdraw
+	err := bridgeSubCmds.cli.PostResp("/bridge/withdraws/"+txHash+"/skip", "", &resp)
+	if err != nil {
+		cli.DieWithError("error while forcing the bridge to skip the withdraw", err)
+	}
+	bridgeSubCmds.printWithdraw(resp.Withdraw)
+}
+
+func (bridgeSubCmds *bridgeSubCmds) printWithdraw(wd erc20.Withdraw) {
+	if bridgeSubCmds.persistentCfg.EncodingType == cli.EncodingTypeJSON {
+		bridgeSubCmds.encodeJSON(wd)
+		return
+	}
+	fmt.Printf(`ERC20 transaction hash: %s
+ETH block: %s (height %d)
+Receiver: %s
+Amount: %s
+State: %s
+TFT transaction ID: %s
+Last error: %s
+Last update: %s
+`, wd.TxHash.String(), wd.BlockHash.String(), wd.BlockHeight, wd.Receiver.String(), wd.Amount.String(),
+		wd.State.String(), wd.TFTTransactionID.String(), wd.LastError, time.Unix(wd.LastUpdate, 0).String())
+}
+
+func (bridgeSubCmds *bridgeSubCmds) listActions() {
+	var resp erc20.BridgeGetActions
+	err := bridgeSubCmds.cli.GetAPI("/bridge/actions?state="+url.QueryEscape(bridgeSubCmds.listCfg.State), &resp)
+	if err != nil {
+		cli.DieWithError("error while fetching the actions", err)
+	}
+	if bridgeSubCmds.persistentCfg.EncodingType == cli.EncodingTypeJSON {
+		bridgeSubCmds.encodeJSON(resp.Actions)
+		return
+	}
+	if len(resp.Actions) == 0 {
+		fmt.Println("no actions found")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TFT TX ID\tTFT HEIGHT\tTYPE\tSTATE\tATTEMPTS\tADDRESS\tAMOUNT\tLAST ERROR")
+	for _, action := range resp.Actions {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%d\t%s\t%s\t%s\n",
+			action.TFTTransactionID.String(), action.BlockHeight, action.Type.String(), action.State.String(),
+			action.Attempts, action.Address.String(), action.Amount.String(), action.LastError)
+	}
+	w.Flush()
+}
+
+func (bridgeSubCmds *bridgeSubCmds) getAction(txID string) {
+	var resp erc20.BridgeGetAction
+	err := bridgeSubCmds.cli.GetAPI("/bridge/actions/"+txID, &resp)
+	if err != nil {
+		cli.DieWithError("error while fetching the action", err)
+	}
+	bridgeSubCmds.printAction(resp.Action)
+}
+
+func (bridgeSubCmds *bridgeSubCmds) retryAction(txID string) {
+	var resp erc20.BridgeGetAction
+	err := bridgeSubCmds.cli.PostResp("/bridge/actions/"+txID+"/retry", "", &resp)
+	if err != nil {
+		cli.DieWithError("error while forcing the bridge to retry the action", err)
+	}
+	bridgeSubCmds.printAction(resp.Action)
+}
+
+func (bridgeSubCmds *bridgeSubCmds) skipAction(txID string) {
+	var resp erc20.BridgeGetAction
+	err := bridgeSubCmds.cli.PostResp("/bridge/actions/"+txID+"/skip", "", &resp)
+	if err != nil {
+		cli.DieWithError("error while forcing the bridge to skip the action", err)
+	}
+	bridgeSubCmds.printAction(resp.Action)
+}
+
+func (bridgeSubCmds *bridgeSubCmds) printAction(action erc20.Action) {
+	if bridgeSubCmds.persistentCfg.EncodingType == cli.EncodingTypeJSON {
+		bridgeSubCmds.encodeJSON(action)
+		return
+	}
+	var nextAttempt string
+	if action.State == erc20.ActionStatePending {
+		nextAttempt = time.Unix(action.NextAttempt, 0).String()
+	}
+	fmt.Printf(`TFT transaction ID: %s
+TFT block height: %d
+Type: %s
+Address: %s
+Amount: %s
+State: %s
+Attempts: %d
+Next attempt: %s
+Last error: %s
+Last update: %s
+`, action.TFTTransactionID.String(), action.BlockHeight, action.Type.String(), action.Address.String(), action.Amount.String(),
+		action.State.String(), action.Attempts, nextAttempt, action.LastError, time.Unix(action.LastUpdate, 0).String())
+}
+
+func (bridgeSubCmds *bridgeSubCmds) encodeJSON(v interface{}) {
+	err := json.NewEncoder(os.Stdout).Encode(v)
+	if err != nil {
+		cli.DieWithError("failed to encode result as JSON", err)
+	}
+}
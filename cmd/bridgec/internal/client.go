@@ -25,10 +25,12 @@ type Config struct {
 type CommandLineClient struct {
 	*api.HTTPClient
 
-	Config     *Config
-	RootCmd    *cobra.Command
-	ERC20Cmd   *cobra.Command
-	TFChainCmd *cobra.Command
+	Config       *Config
+	RootCmd      *cobra.Command
+	ERC20Cmd     *cobra.Command
+	TFChainCmd   *cobra.Command
+	WithdrawsCmd *cobra.Command
+	ActionsCmd   *cobra.Command
 }
 
 // NewCommandLineClient creates a new CLI client, which can be run as it is,
@@ -81,6 +83,12 @@ func NewCommandLineClient(address, name, userAgent string) (*CommandLineClient,
 	client.TFChainCmd = createTFChainCommand(client)
 	client.RootCmd.AddCommand(client.TFChainCmd)
 
+	client.WithdrawsCmd = createWithdrawsCmd(client)
+	client.RootCmd.AddCommand(client.WithdrawsCmd)
+
+	client.ActionsCmd = createActionsCmd(client)
+	client.RootCmd.AddCommand(client.ActionsCmd)
+
 	// parse flags
 	client.RootCmd.PersistentFlags().StringVarP(&client.HTTPClient.RootURL, "addr", "a",
 		client.HTTPClient.RootURL, fmt.Sprintf(
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	_ "net/http/pprof"
@@ -12,6 +13,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/bgentry/speakeasy"
 	"github.com/julienschmidt/httprouter"
 	"github.com/threefoldtech/rivine/modules/transactionpool"
 	"github.com/threefoldtech/rivine/pkg/cli"
@@ -21,6 +23,7 @@ import (
 	"github.com/threefoldfoundation/tfchain/pkg/api"
 	"github.com/threefoldfoundation/tfchain/pkg/config"
 	"github.com/threefoldfoundation/tfchain/pkg/eth/erc20"
+	"github.com/threefoldfoundation/tfchain/pkg/metrics"
 	"github.com/threefoldfoundation/tfchain/pkg/persist"
 	rivineapi "github.com/threefoldtech/rivine/pkg/api"
 
@@ -58,8 +61,10 @@ type Commands struct {
 	RootPersistentDir string
 	transactionDB     *persist.TransactionDB
 
-	APIaddr   string
-	UserAgent string
+	APIaddr         string
+	UserAgent       string
+	AuthenticateAPI bool
+	APIPassword     string
 
 	VerboseRivineLogging bool
 }
@@ -94,10 +99,25 @@ func (cmd *Commands) Root(_ *cobra.Command, args []string) (cmdErr error) {
 
 	log.Info("starting bridge", "version", cmd.BlockchainInfo.ChainVersion.String())
 
+	// Check if we require an api password
+	if cmd.AuthenticateAPI {
+		// Prompt user for API password.
+		cmd.APIPassword, err = speakeasy.Ask("Enter API password: ")
+		if err != nil {
+			return fmt.Errorf("failed to ask for API password: %v", err)
+		}
+		if cmd.APIPassword == "" {
+			return errors.New("failed to configure bridge: API password cannot be blank")
+		}
+	} else {
+		cmd.APIPassword = ""
+	}
+
 	log.Info("loading network config, registering types and loading rivine transaction db (0/4)...")
 	switch cmd.BlockchainInfo.NetworkName {
 	case config.NetworkNameStandard:
-		cmd.transactionDB, cmdErr = persist.NewTransactionDB(cmd.rootPerDir(), config.GetStandardnetGenesisMintCondition())
+		cmd.transactionDB, cmdErr = persist.NewTransactionDB(cmd.rootPerDir(), config.GetStandardnetGenesisMintCondition(),
+			tfchaintypes.GetStandardnetGenesisConsensusParameters())
 		if cmdErr != nil {
 			return fmt.Errorf("failed to create tfchain transaction DB for tfchain standard: %v", cmdErr)
 		}
@@ -117,7 +137,8 @@ func (cmd *Commands) Root(_ *cobra.Command, args []string) (cmdErr error) {
 		}
 
 	case config.NetworkNameTest:
-		cmd.transactionDB, cmdErr = persist.NewTransactionDB(cmd.rootPerDir(), config.GetTestnetGenesisMintCondition())
+		cmd.transactionDB, cmdErr = persist.NewTransactionDB(cmd.rootPerDir(), config.GetTestnetGenesisMintCondition(),
+			tfchaintypes.GetTestnetGenesisConsensusParameters())
 		if cmdErr != nil {
 			return fmt.Errorf("failed to create tfchain transaction DB for tfchain testnet: %v", cmdErr)
 		}
@@ -138,7 +159,8 @@ func (cmd *Commands) Root(_ *cobra.Command, args []string) (cmdErr error) {
 		}
 
 	case config.NetworkNameDev:
-		cmd.transactionDB, cmdErr = persist.NewTransactionDB(cmd.rootPerDir(), config.GetDevnetGenesisMintCondition())
+		cmd.transactionDB, cmdErr = persist.NewTransactionDB(cmd.rootPerDir(), config.GetDevnetGenesisMintCondition(),
+			tfchaintypes.GetDevnetGenesisConsensusParameters())
 		if cmdErr != nil {
 			return fmt.Errorf("failed to create tfchain transaction DB for tfchain devnet: %v", cmdErr)
 		}
@@ -170,8 +192,12 @@ func (cmd *Commands) Root(_ *cobra.Command, args []string) (cmdErr error) {
 	fmt.Println("Binding API Address and serving the API...")
 	srv, err := daemon.NewHTTPServer(cmd.APIaddr)
 	if err != nil {
+		cancel()
 		return err
 	}
+	// expose our metrics, without requiring a user agent,
+	// such that they can be scraped by a Prometheus server
+	srv.Handle("/metrics", metrics.Handler())
 	servErrs := make(chan error, 32)
 	go func() {
 		servErrs <- srv.Serve()
@@ -196,9 +222,7 @@ func (cmd *Commands) Root(_ *cobra.Command, args []string) (cmdErr error) {
 			cmdErr = err
 			return
 		}
-		// Blank password as we are not exposing the bridge HTTP API.
-		// TODO: Proper password verification like in the regular daemon
-		rivineapi.RegisterGatewayHTTPHandlers(router, gateway, "")
+		rivineapi.RegisterGatewayHTTPHandlers(router, gateway, cmd.APIPassword)
 		defer func() {
 			log.Info("Closing gateway module...")
 			err := gateway.Close()
@@ -271,6 +295,22 @@ func (cmd *Commands) Root(_ *cobra.Command, args []string) (cmdErr error) {
 		// Register ERC20 http handlers
 		api.RegisterERC20HTTPHandlers(router, erc20Client)
 
+		// Register the bridge http handlers,
+		// forcing the bridge to retry or skip an item requires the API password
+		erc20.RegisterBridgeHTTPHandlers(router, bridged, cmd.APIPassword)
+
+		// register the metrics of the bridge and the transaction db
+		err = bridged.RegisterMetrics(metrics.DefaultRegistry)
+		if err == nil {
+			err = cmd.transactionDB.RegisterMetrics(metrics.DefaultRegistry)
+		}
+		if err != nil {
+			log.Error("Failed to register bridge metrics", "err", err)
+			cancel()
+			cmdErr = err
+			return
+		}
+
 		router.POST("/bridge/stop", func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 			// can't write after we stop the server, so lie a bit.
 			rivineapi.WriteSuccess(w)
@@ -489,6 +529,11 @@ func main() {
 		"user-agent", daemon.RivineUserAgent,
 		"Set custom User-Agent",
 	)
+	cmdRoot.Flags().BoolVar(
+		&cmd.AuthenticateAPI,
+		"authenticate-api", false,
+		"enable API password protection, required to force the bridge to retry or skip a conversion",
+	)
 	cmdRoot.Flags().BoolVarP(&cmd.VerboseRivineLogging, "verboseRivinelogging", "v", false, "enable verboselogging in the logfiles of the rivine modules")
 
 	// execute logic
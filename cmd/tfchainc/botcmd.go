@@ -0,0 +1,388 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/threefoldfoundation/tfchain/cmd/tfchainc/internal"
+	"github.com/threefoldfoundation/tfchain/pkg/types"
+	"github.com/threefoldtech/rivine/pkg/api"
+	"github.com/threefoldtech/rivine/pkg/cli"
+	rivinetypes "github.com/threefoldtech/rivine/types"
+)
+
+const (
+	// defaultBotRenewalScheduleFile is the file used by default
+	// to persist the schedule of the 3bot auto-renewal agent.
+	defaultBotRenewalScheduleFile = "tfchainc-autorenew.json"
+	// botRenewalPendingTimeout is the time a submitted renewal Tx has to be confirmed within,
+	// before it is considered as failed, and the renewal is attempted again.
+	botRenewalPendingTimeout = time.Hour
+)
+
+// createBotCmd creates the root command for 3bot-related actions,
+// that are not simply wallet or explorer actions.
+func createBotCmd(client *internal.CommandLineClient) *cobra.Command {
+	botSubCmds := &botSubCmds{cli: client}
+
+	// define commands
+	var (
+		rootCmd = &cobra.Command{
+			Use:   "bot",
+			Short: "Perform 3bot actions",
+			Long:  "Perform 3bot actions",
+		}
+		autoRenewCmd = &cobra.Command{
+			Use:   "autorenew",
+			Short: "Automatically renew 3bots owned by the wallet",
+			Long: `Watch the given 3bots, owned by the wallet, and renew each of them
+a configurable amount of days prior to its expiration.
+
+The renewal Tx fees are funded using the coins of the wallet,
+and the schedule of all renewals, including any failures, is persisted
+in a JSON file, such that the agent can be restarted at any time.
+Failures are also reported to STDERR.
+`,
+			Run: botSubCmds.autoRenew,
+		}
+		autoRenewStatusCmd = &cobra.Command{
+			Use:   "status",
+			Short: "Print the schedule of the 3bot auto-renewal agent",
+			Long:  "Print the persisted schedule of the 3bot auto-renewal agent.",
+			Run:   botSubCmds.autoRenewStatus,
+		}
+	)
+
+	rootCmd.AddCommand(autoRenewCmd)
+	autoRenewCmd.AddCommand(autoRenewStatusCmd)
+
+	// register flags
+	internal.BotIDArrayFlagVar(
+		autoRenewCmd.Flags(), &botSubCmds.autoRenewCfg.BotIDs, "bot",
+		"ID of a 3bot (owned by the wallet) to renew automatically, can be defined multiple times")
+	autoRenewCmd.Flags().UintVar(
+		&botSubCmds.autoRenewCfg.DaysBefore, "days-before", 7,
+		"renew a 3bot this amount of days prior to its expiration")
+	autoRenewCmd.Flags().Uint8Var(
+		&botSubCmds.autoRenewCfg.Months, "months", 1,
+		fmt.Sprintf("amount of months to renew a 3bot with, limited to %d prepaid months", types.MaxBotPrepaidMonths))
+	autoRenewCmd.Flags().DurationVar(
+		&botSubCmds.autoRenewCfg.Interval, "interval", 10*time.Minute,
+		"interval at which all 3bots are checked")
+	autoRenewCmd.Flags().BoolVar(
+		&botSubCmds.autoRenewCfg.Once, "once", false,
+		"check (and renew if needed) all 3bots only once, instead of running as a daemon")
+	autoRenewCmd.PersistentFlags().StringVar(
+		&botSubCmds.autoRenewCfg.ScheduleFile, "schedule", defaultBotRenewalScheduleFile,
+		"file used to persist the renewal schedule")
+	autoRenewStatusCmd.Flags().Var(
+		cli.NewEncodingTypeFlag(cli.EncodingTypeHuman, &botSubCmds.autoRenewStatusCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
+		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
+
+	return rootCmd
+}
+
+type botSubCmds struct {
+	cli          *internal.CommandLineClient
+	autoRenewCfg struct {
+		BotIDs       []types.BotID
+		DaysBefore   uint
+		Months       uint8
+		Interval     time.Duration
+		Once         bool
+		ScheduleFile string
+	}
+	autoRenewStatusCfg struct {
+		EncodingType cli.EncodingType
+	}
+}
+
+type (
+	// botRenewalSchedule is the persisted schedule of the 3bot auto-renewal agent.
+	botRenewalSchedule struct {
+		Bots map[types.BotID]*botRenewalState `json:"bots"`
+	}
+	// botRenewalState is the persisted renewal state of a single 3bot.
+	botRenewalState struct {
+		// Expiration is the last known expiration date of the 3bot.
+		Expiration types.CompactTimestamp `json:"expiration"`
+		// NextRenewal is the time at (or after) which the 3bot is renewed next.
+		NextRenewal rivinetypes.Timestamp `json:"nextrenewal"`
+		// PendingTransaction is the ID of the submitted renewal Tx, that is not yet confirmed.
+		PendingTransaction *rivinetypes.TransactionID `json:"pendingtransaction,omitempty"`
+		// PendingSince is the time at which the pending renewal Tx was submitted.
+		PendingSince rivinetypes.Timestamp `json:"pendingsince,omitempty"`
+		// LastRenewal is the time at which the last renewal of the 3bot got confirmed.
+		LastRenewal rivinetypes.Timestamp `json:"lastrenewal,omitempty"`
+		// LastError is the error of the last failed renewal attempt, if any,
+		// and Failures the amount of subsequent failed attempts.
+		LastError string `json:"lasterror,omitempty"`
+		Failures  int    `json:"failures,omitempty"`
+	}
+)
+
+func (botSubCmds *botSubCmds) autoRenew(cmd *cobra.Command, args []string) {
+	cfg := botSubCmds.autoRenewCfg
+	if len(cfg.BotIDs) == 0 {
+		cmd.UsageFunc()(cmd)
+		cli.Die("at least one 3bot has to be defined using the --bot flag")
+	}
+	if cfg.Months == 0 || cfg.Months > types.MaxBotPrepaidMonths {
+		cli.Die(fmt.Sprintf("the amount of months has to be within the range [1, %d]", types.MaxBotPrepaidMonths))
+	}
+	// a 3bot can never be renewed if it would exceed the maximum amount of prepaid months
+	if uint64(cfg.DaysBefore)*24*60*60+uint64(cfg.Months)*types.BotMonth > types.MaxBotPrepaidMonthsInSeconds {
+		cli.Die(fmt.Sprintf(
+			"renewing a 3bot with %d month(s), %d day(s) prior to its expiration, exceeds the limit of %d prepaid months",
+			cfg.Months, cfg.DaysBefore, types.MaxBotPrepaidMonths))
+	}
+	if cfg.Interval <= 0 {
+		cli.Die("the check interval has to be positive")
+	}
+
+	schedule, err := loadBotRenewalSchedule(cfg.ScheduleFile)
+	if err != nil {
+		cli.DieWithError("failed to load the renewal schedule", err)
+	}
+	// only keep the state of the 3bots that are (still) configured
+	configured := make(map[types.BotID]*botRenewalState, len(cfg.BotIDs))
+	for _, id := range cfg.BotIDs {
+		state, ok := schedule.Bots[id]
+		if !ok {
+			state = new(botRenewalState)
+		}
+		configured[id] = state
+	}
+	schedule.Bots = configured
+
+	for {
+		for _, id := range cfg.BotIDs {
+			err = botSubCmds.checkBotRenewal(id, schedule.Bots[id])
+			if err != nil {
+				state := schedule.Bots[id]
+				state.LastError = err.Error()
+				state.Failures++
+				fmt.Fprintf(os.Stderr, "[%s] failed to renew 3bot %v (attempt #%d): %v\n",
+					time.Now().Format(time.RFC3339), id, state.Failures, err)
+			}
+		}
+		err = schedule.save(cfg.ScheduleFile)
+		if err != nil {
+			// not fatal, as the schedule can always be restored from the chain
+			fmt.Fprintf(os.Stderr, "[%s] failed to save the renewal schedule: %v\n",
+				time.Now().Format(time.RFC3339), err)
+		}
+		if cfg.Once {
+			return
+		}
+		time.Sleep(cfg.Interval)
+	}
+}
+
+// checkBotRenewal updates the renewal state of the given 3bot,
+// submitting a renewal Tx in case it is time to renew the 3bot.
+func (botSubCmds *botSubCmds) checkBotRenewal(id types.BotID, state *botRenewalState) error {
+	cfg := botSubCmds.autoRenewCfg
+	now := rivinetypes.CurrentTimestamp()
+
+	record, err := internal.NewTransactionDBConsensusClient(botSubCmds.cli).GetRecordForID(id)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the bot record: %v", err)
+	}
+
+	if state.PendingTransaction != nil {
+		if record.Expiration > state.Expiration {
+			// pending renewal got confirmed
+			fmt.Printf("[%s] renewal of 3bot %v confirmed by Tx %v, it now expires at %s\n",
+				time.Now().Format(time.RFC3339), id, *state.PendingTransaction, record.Expiration.SiaTimestamp())
+			state.PendingTransaction = nil
+			state.PendingSince = 0
+			state.LastRenewal = now
+			state.LastError = ""
+			state.Failures = 0
+		} else if now-state.PendingSince < rivinetypes.Timestamp(botRenewalPendingTimeout.Seconds()) {
+			// wait some more for the renewal to get confirmed
+			return nil
+		} else {
+			txID := *state.PendingTransaction
+			state.PendingTransaction = nil
+			state.PendingSince = 0
+			return fmt.Errorf("renewal Tx %v was not confirmed within %v", txID, botRenewalPendingTimeout)
+		}
+	}
+
+	state.Expiration = record.Expiration
+	renewalOffset := types.CompactTimestamp(cfg.DaysBefore) * 24 * 60 * 60
+	if renewalOffset >= record.Expiration {
+		state.NextRenewal = 0
+	} else {
+		state.NextRenewal = (record.Expiration - renewalOffset).SiaTimestamp()
+	}
+	if now < state.NextRenewal {
+		return nil // not yet time to renew
+	}
+
+	// renew the 3bot, respecting the maximum amount of prepaid months,
+	// using the grace period that applies to the next block
+	var cs api.ConsensusGET
+	err = botSubCmds.cli.GetAPI("/consensus", &cs)
+	if err != nil {
+		return fmt.Errorf("failed to get the consensus state: %v", err)
+	}
+	params, err := internal.NewTransactionDBConsensusClient(botSubCmds.cli).GetConsensusParametersAt(cs.Height + 1)
+	if err != nil {
+		return fmt.Errorf("failed to get the consensus parameters: %v", err)
+	}
+	months := cfg.Months
+	if max := record.MaxExtendableMonths(now, params.BotNameGracePeriodAt(cs.Height+1)); months > max {
+		months = max
+	}
+	if months == 0 {
+		return fmt.Errorf("3bot is already prepaid for the maximum of %d months", types.MaxBotPrepaidMonths)
+	}
+	txID, err := botSubCmds.submitBotRenewal(record, months)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("[%s] submitted renewal of 3bot %v with %d month(s) as Tx %v\n",
+		time.Now().Format(time.RFC3339), id, months, txID)
+	state.PendingTransaction = &txID
+	state.PendingSince = now
+	return nil
+}
+
+// submitBotRenewal funds, signs and submits a Tx that extends
+// the expiration date of the given 3bot with the given amount of months.
+func (botSubCmds *botSubCmds) submitBotRenewal(record *types.BotRecord, months uint8) (rivinetypes.TransactionID, error) {
+	walletClient := internal.NewWalletClient(botSubCmds.cli)
+
+	// only 3bots owned by the wallet can be renewed
+	var addresses api.WalletAddressesGET
+	err := botSubCmds.cli.GetAPI("/wallet/addresses", &addresses)
+	if err != nil {
+		return rivinetypes.TransactionID{}, fmt.Errorf("failed to fetch the wallet addresses: %v", err)
+	}
+	owner := record.OwnerCondition().UnlockHash()
+	owned := false
+	for _, uh := range addresses.Addresses {
+		if uh.Cmp(owner) == 0 {
+			owned = true
+			break
+		}
+	}
+	if !owned {
+		return rivinetypes.TransactionID{}, fmt.Errorf("3bot is owned by %v, which is not an address of this wallet", owner)
+	}
+
+	tx := types.BotRecordUpdateTransaction{
+		Identifier:     record.ID,
+		NrOfMonths:     months,
+		TransactionFee: botSubCmds.cli.Config.MinimumTransactionFee,
+	}
+	params, err := internal.NewTransactionDBConsensusClient(botSubCmds.cli).GetActiveConsensusParameters()
+	if err != nil {
+		return rivinetypes.TransactionID{}, err
+	}
+	fee := tx.RequiredBotFee(params)
+	tx.CoinInputs, tx.RefundCoinOutput, err = walletClient.FundCoins(fee.Add(botSubCmds.cli.Config.MinimumTransactionFee))
+	if err != nil {
+		return rivinetypes.TransactionID{}, fmt.Errorf("failed to fund the renewal Tx: %v", err)
+	}
+	rtx := botRecordUpdateTransaction(tx, record, botSubCmds.cli.Config.CurrencyUnits.OneCoin)
+	err = walletClient.GreedySignTx(&rtx)
+	if err != nil {
+		return rivinetypes.TransactionID{}, fmt.Errorf("failed to sign the renewal Tx: %v", err)
+	}
+	txID, err := internal.NewTransactionPoolClient(botSubCmds.cli).AddTransactiom(rtx)
+	if err != nil {
+		return rivinetypes.TransactionID{}, fmt.Errorf("failed to submit the renewal Tx to the Tx Pool: %v", err)
+	}
+	return txID, nil
+}
+
+func (botSubCmds *botSubCmds) autoRenewStatus(cmd *cobra.Command, args []string) {
+	schedule, err := loadBotRenewalSchedule(botSubCmds.autoRenewCfg.ScheduleFile)
+	if err != nil {
+		cli.DieWithError("failed to load the renewal schedule", err)
+	}
+
+	switch botSubCmds.autoRenewStatusCfg.EncodingType {
+	case cli.EncodingTypeHuman:
+		if len(schedule.Bots) == 0 {
+			fmt.Println("no 3bots are scheduled for renewal")
+			return
+		}
+		ids := make([]types.BotID, 0, len(schedule.Bots))
+		for id := range schedule.Bots {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		for _, id := range ids {
+			state := schedule.Bots[id]
+			fmt.Printf("3bot %v:\n", id)
+			fmt.Printf("  expiration:   %s\n", state.Expiration.SiaTimestamp())
+			fmt.Printf("  next renewal: %s\n", state.NextRenewal)
+			if state.PendingTransaction != nil {
+				fmt.Printf("  pending Tx:   %v (since %s)\n", *state.PendingTransaction, state.PendingSince)
+			}
+			if state.LastRenewal != 0 {
+				fmt.Printf("  last renewal: %s\n", state.LastRenewal)
+			}
+			if state.Failures > 0 {
+				fmt.Printf("  failures:     %d (last error: %s)\n", state.Failures, state.LastError)
+			}
+		}
+	case cli.EncodingTypeJSON:
+		err = json.NewEncoder(os.Stdout).Encode(schedule)
+		if err != nil {
+			cli.DieWithError("failed to encode the renewal schedule", err)
+		}
+	}
+}
+
+// loadBotRenewalSchedule loads the renewal schedule from the given file,
+// returning an empty schedule if the file does not exist yet.
+func loadBotRenewalSchedule(path string) (*botRenewalSchedule, error) {
+	schedule := &botRenewalSchedule{Bots: make(map[types.BotID]*botRenewalState)}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return schedule, nil
+		}
+		return nil, err
+	}
+	err = json.Unmarshal(b, schedule)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Bots == nil {
+		schedule.Bots = make(map[types.BotID]*botRenewalState)
+	}
+	for id, state := range schedule.Bots {
+		if state == nil {
+			return nil, errors.New("invalid renewal state for 3bot " + id.String())
+		}
+	}
+	return schedule, nil
+}
+
+// save persists the renewal schedule atomically to the given file.
+func (schedule *botRenewalSchedule) save(path string) error {
+	b, err := json.MarshalIndent(schedule, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpPath := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	err = ioutil.WriteFile(tmpPath, b, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
@@ -32,15 +32,44 @@ or the one for the given block height.
 			Run: consensusSubCmds.getMintCondition,
 		}
 
+		getConsensusParametersCmd = &cobra.Command{
+			Use:   "parameters [height]",
+			Short: "Get the active consensus parameters",
+			Long: `Get the active consensus parameters, such as the 3bot and ERC20 fees,
+either the ones active for the next block,
+or the ones active for the given block height.
+`,
+			Run: consensusSubCmds.getConsensusParameters,
+		}
+
 		getBotRecordCmd = &cobra.Command{
-			Use:   "botrecord (id|pubKey|name)",
+			Use:   "botrecord (id|pubKey|name|address)",
 			Short: "Get the bot record linked to the given info",
 			Long: `Get the bot record linked to the given,
 id, public key or name.
+
+When the --address flag is given, the argument is interpreted as a network address instead,
+and the records of all bots that registered that network address are returned.
 `,
 			Run: rivinecli.Wrap(consensusSubCmds.getBotRecord),
 		}
 
+		getBotNameChildrenCmd = &cobra.Command{
+			Use:   "botnamechildren name",
+			Short: "Get the sub names of the given bot name",
+			Long: `Get the direct sub names of the given bot name,
+which are registered by an active bot.`,
+			Run: rivinecli.Wrap(consensusSubCmds.getBotNameChildren),
+		}
+
+		getBotNameDelegatesCmd = &cobra.Command{
+			Use:   "botnamedelegates name",
+			Short: "Get the delegates of the given bot name",
+			Long: `Get the identifiers of the bots which are allowed,
+by the current owner of the given bot name, to register sub names of it.`,
+			Run: rivinecli.Wrap(consensusSubCmds.getBotNameDelegates),
+		}
+
 		getBotTransactionsCmd = &cobra.Command{
 			Use:   "bottransactions id",
 			Short: "Get the transactions created by the given bot",
@@ -52,7 +81,10 @@ id, public key or name.
 	// add commands as wallet sub commands
 	client.ConsensusCmd.AddCommand(
 		getMintConditionCmd,
+		getConsensusParametersCmd,
 		getBotRecordCmd,
+		getBotNameChildrenCmd,
+		getBotNameDelegatesCmd,
 		getBotTransactionsCmd,
 	)
 
@@ -60,9 +92,21 @@ id, public key or name.
 	getMintConditionCmd.Flags().Var(
 		cli.NewEncodingTypeFlag(0, &consensusSubCmds.getMintConditionCfg.EncodingType, 0), "encoding",
 		cli.EncodingTypeFlagDescription(0))
+	getConsensusParametersCmd.Flags().Var(
+		cli.NewEncodingTypeFlag(0, &consensusSubCmds.getConsensusParametersCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
+		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
 	getBotRecordCmd.Flags().Var(
 		cli.NewEncodingTypeFlag(0, &consensusSubCmds.getBotRecordCfg.EncodingType, 0), "encoding",
 		cli.EncodingTypeFlagDescription(0))
+	getBotRecordCmd.Flags().BoolVar(
+		&consensusSubCmds.getBotRecordCfg.Address, "address", false,
+		"interpret the argument as a network address, returning the records of all bots that registered it")
+	getBotNameChildrenCmd.Flags().Var(
+		cli.NewEncodingTypeFlag(0, &consensusSubCmds.getBotNameChildrenCfg.EncodingType, 0), "encoding",
+		cli.EncodingTypeFlagDescription(0))
+	getBotNameDelegatesCmd.Flags().Var(
+		cli.NewEncodingTypeFlag(0, &consensusSubCmds.getBotNameDelegatesCfg.EncodingType, 0), "encoding",
+		cli.EncodingTypeFlagDescription(0))
 	getBotTransactionsCmd.Flags().Var(
 		cli.NewEncodingTypeFlag(0, &consensusSubCmds.getBotTransactionsCfg.EncodingType, 0), "encoding",
 		cli.EncodingTypeFlagDescription(0))
@@ -73,8 +117,18 @@ type consensusSubCmds struct {
 	getMintConditionCfg struct {
 		EncodingType cli.EncodingType
 	}
+	getConsensusParametersCfg struct {
+		EncodingType cli.EncodingType
+	}
 	getBotRecordCfg struct {
 		EncodingType cli.EncodingType
+		Address      bool
+	}
+	getBotNameChildrenCfg struct {
+		EncodingType cli.EncodingType
+	}
+	getBotNameDelegatesCfg struct {
+		EncodingType cli.EncodingType
 	}
 	getBotTransactionsCfg struct {
 		EncodingType cli.EncodingType
@@ -136,11 +190,74 @@ func (consensusSubCmds *consensusSubCmds) getMintCondition(cmd *cobra.Command, a
 	}
 }
 
-func (consensusSubCmds *consensusSubCmds) getBotRecord(str string) {
+func (consensusSubCmds *consensusSubCmds) getConsensusParameters(cmd *cobra.Command, args []string) {
 	txDBReader := internal.NewTransactionDBConsensusClient(consensusSubCmds.cli)
-	record, err := txDBReader.GetRecordForString(str)
+
+	var (
+		params types.ConsensusParameters
+		err    error
+	)
+
+	switch len(args) {
+	case 0:
+		// get the consensus parameters active for the next block
+		params, err = txDBReader.GetActiveConsensusParameters()
+		if err != nil {
+			cli.DieWithError("failed to get the active consensus parameters", err)
+		}
+
+	case 1:
+		// get the consensus parameters active for a given block height
+		height, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			cmd.UsageFunc()
+			cli.DieWithError("invalid block height given", err)
+		}
+		params, err = txDBReader.GetConsensusParametersAt(rivinetypes.BlockHeight(height))
+		if err != nil {
+			cli.DieWithError("failed to get the consensus parameters at the given block height", err)
+		}
+
+	default:
+		cmd.UsageFunc()
+		cli.Die("Invalid amount of arguments. One optional pos argument can be given, a valid block height.")
+	}
+
+	// encode depending on the encoding flag
+	switch consensusSubCmds.getConsensusParametersCfg.EncodingType {
+	case cli.EncodingTypeHuman:
+		e := json.NewEncoder(os.Stdout)
+		e.SetIndent("", "  ")
+		err = e.Encode(params)
+	case cli.EncodingTypeJSON:
+		err = json.NewEncoder(os.Stdout).Encode(params)
+	}
 	if err != nil {
-		cli.DieWithError("error while fetching the 3bot record", err)
+		cli.DieWithError("failed to encode consensus parameters", err)
+	}
+}
+
+func (consensusSubCmds *consensusSubCmds) getBotRecord(str string) {
+	txDBReader := internal.NewTransactionDBConsensusClient(consensusSubCmds.cli)
+	var (
+		result interface{}
+		err    error
+	)
+	if consensusSubCmds.getBotRecordCfg.Address {
+		var addr types.NetworkAddress
+		err = addr.LoadString(str)
+		if err != nil {
+			cli.DieWithError("failed to parse network address pos arg", err)
+		}
+		result, err = txDBReader.GetRecordsForNetworkAddress(addr)
+		if err != nil {
+			cli.DieWithError("error while fetching the 3bot records", err)
+		}
+	} else {
+		result, err = txDBReader.GetRecordForString(str)
+		if err != nil {
+			cli.DieWithError("error while fetching the 3bot record", err)
+		}
 	}
 
 	// encode depending on the encoding flag
@@ -159,12 +276,82 @@ func (consensusSubCmds *consensusSubCmds) getBotRecord(str string) {
 			return nil
 		}
 	}
-	err = encode(record)
+	err = encode(result)
 	if err != nil {
 		cli.DieWithError("failed to encode 3bot record", err)
 	}
 }
 
+func (consensusSubCmds *consensusSubCmds) getBotNameChildren(str string) {
+	var name types.BotName
+	err := name.LoadString(str)
+	if err != nil {
+		cli.DieWithError("failed to parse 3bot name pos arg", err)
+	}
+
+	txDBReader := internal.NewTransactionDBConsensusClient(consensusSubCmds.cli)
+	result, err := txDBReader.GetChildrenForName(name)
+	if err != nil {
+		cli.DieWithError("error while fetching the 3bot sub names", err)
+	}
+
+	// encode depending on the encoding flag
+	var encode func(interface{}) error
+	switch consensusSubCmds.getBotNameChildrenCfg.EncodingType {
+	case cli.EncodingTypeHuman:
+		e := json.NewEncoder(os.Stdout)
+		e.SetIndent("", "  ")
+		encode = e.Encode
+	case cli.EncodingTypeJSON:
+		encode = json.NewEncoder(os.Stdout).Encode
+	case cli.EncodingTypeHex:
+		encode = func(v interface{}) error {
+			b := siabin.Marshal(v)
+			fmt.Println(hex.EncodeToString(b))
+			return nil
+		}
+	}
+	err = encode(result)
+	if err != nil {
+		cli.DieWithError("failed to encode 3bot sub names", err)
+	}
+}
+
+func (consensusSubCmds *consensusSubCmds) getBotNameDelegates(str string) {
+	var name types.BotName
+	err := name.LoadString(str)
+	if err != nil {
+		cli.DieWithError("failed to parse 3bot name pos arg", err)
+	}
+
+	txDBReader := internal.NewTransactionDBConsensusClient(consensusSubCmds.cli)
+	result, err := txDBReader.GetDelegatesForName(name)
+	if err != nil {
+		cli.DieWithError("error while fetching the 3bot name delegates", err)
+	}
+
+	// encode depending on the encoding flag
+	var encode func(interface{}) error
+	switch consensusSubCmds.getBotNameDelegatesCfg.EncodingType {
+	case cli.EncodingTypeHuman:
+		e := json.NewEncoder(os.Stdout)
+		e.SetIndent("", "  ")
+		encode = e.Encode
+	case cli.EncodingTypeJSON:
+		encode = json.NewEncoder(os.Stdout).Encode
+	case cli.EncodingTypeHex:
+		encode = func(v interface{}) error {
+			b := siabin.Marshal(v)
+			fmt.Println(hex.EncodeToString(b))
+			return nil
+		}
+	}
+	err = encode(result)
+	if err != nil {
+		cli.DieWithError("failed to encode 3bot name delegates", err)
+	}
+}
+
 func (consensusSubCmds *consensusSubCmds) getBotTransactions(str string) {
 	var botID types.BotID
 	err := botID.LoadString(str)
@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/threefoldfoundation/tfchain/cmd/tfchainc/internal"
+	"github.com/threefoldfoundation/tfchain/pkg/persist"
+	"github.com/threefoldfoundation/tfchain/pkg/types"
 	"github.com/threefoldtech/rivine/pkg/cli"
 	rivinecli "github.com/threefoldtech/rivine/pkg/client"
 	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
@@ -32,19 +35,69 @@ or the one for the given block height.
 		}
 
 		getBotRecordCmd = &cobra.Command{
-			Use:   "botrecord (id|pubKey|name)",
+			Use:   "botrecord (id|pubKey|name|address)",
 			Short: "Get the bot record linked to the given info",
 			Long: `Get the bot record linked to the given,
 id, public key or name.
+
+When the --address flag is given, the argument is interpreted as a network address instead,
+and the records of all bots that registered that network address are returned.
 `,
 			Run: rivinecli.Wrap(explorerSubCmds.getBotRecord),
 		}
+
+		listBotsCmd = &cobra.Command{
+			Use:   "bots",
+			Short: "List the registered bots",
+			Long: `List the records of the registered bots, ordered by ID,
+a page at a time. The cursor printed as part of a page
+can be passed using the --cursor flag in order to get the next page.
+
+The listed bots can be filtered using the optional flags,
+e.g. to list the bots expiring within the next 30 days,
+or the bots registered since a given block height.
+`,
+			Run: rivinecli.Wrap(explorerSubCmds.listBots),
+		}
+
+		verifyBotMessageCmd = &cobra.Command{
+			Use:   "verify-message message signature",
+			Short: "Verify a message signed as a 3bot",
+			Long: `Verify that the given message was signed as the 3bot identified by the --bot flag,
+using the wallet's sign-message command. The hex-encoded signature is verified
+against the current public key of the 3bot, or against the public key
+the 3bot had at the block height given using the --height flag.
+
+The message is verified as is, or decoded first in case the --hex flag is given.
+`,
+			Run: rivinecli.Wrap(explorerSubCmds.verifyBotMessage),
+		}
+
+		getBotNameChildrenCmd = &cobra.Command{
+			Use:   "botnamechildren name",
+			Short: "Get the sub names of the given bot name",
+			Long: `Get the direct sub names of the given bot name,
+which are registered by an active bot.`,
+			Run: rivinecli.Wrap(explorerSubCmds.getBotNameChildren),
+		}
+
+		getBotNameDelegatesCmd = &cobra.Command{
+			Use:   "botnamedelegates name",
+			Short: "Get the delegates of the given bot name",
+			Long: `Get the identifiers of the bots which are allowed,
+by the current owner of the given bot name, to register sub names of it.`,
+			Run: rivinecli.Wrap(explorerSubCmds.getBotNameDelegates),
+		}
 	)
 
 	// add commands as wallet sub commands
 	client.ExploreCmd.AddCommand(
 		getMintConditionCmd,
 		getBotRecordCmd,
+		listBotsCmd,
+		verifyBotMessageCmd,
+		getBotNameChildrenCmd,
+		getBotNameDelegatesCmd,
 	)
 
 	// register flags
@@ -54,6 +107,48 @@ id, public key or name.
 	getBotRecordCmd.Flags().Var(
 		cli.NewEncodingTypeFlag(0, &explorerSubCmds.getBotRecordCfg.EncodingType, 0), "encoding",
 		cli.EncodingTypeFlagDescription(0))
+	getBotRecordCmd.Flags().BoolVar(
+		&explorerSubCmds.getBotRecordCfg.Address, "address", false,
+		"interpret the argument as a network address, returning the records of all bots that registered it")
+	listBotsCmd.Flags().Var(
+		cli.NewEncodingTypeFlag(0, &explorerSubCmds.listBotsCfg.EncodingType, 0), "encoding",
+		cli.EncodingTypeFlagDescription(0))
+	listBotsCmd.Flags().Uint32Var(
+		&explorerSubCmds.listBotsCfg.Cursor, "cursor", 0,
+		"list the bots registered after the bot with this ID, as returned as part of the previous page")
+	listBotsCmd.Flags().IntVar(
+		&explorerSubCmds.listBotsCfg.Limit, "limit", 0,
+		"the maximum amount of bots to list, using the default of the daemon if not defined")
+	listBotsCmd.Flags().DurationVar(
+		&explorerSubCmds.listBotsCfg.ExpiresWithin, "expires-within", 0,
+		"only list the bots which expire within the given duration from now (e.g. 720h)")
+	listBotsCmd.Flags().BoolVar(
+		&explorerSubCmds.listBotsCfg.HasName, "has-name", false,
+		"only list the bots that own at least one name")
+	listBotsCmd.Flags().Uint64Var(
+		&explorerSubCmds.listBotsCfg.RegisteredSince, "registered-since", 0,
+		"only list the bots registered at or after the given block height")
+	listBotsCmd.Flags().Uint64Var(
+		&explorerSubCmds.listBotsCfg.RegisteredBefore, "registered-before", 0,
+		"only list the bots registered before the given block height")
+	verifyBotMessageCmd.Flags().StringVar(
+		&explorerSubCmds.verifyBotMessageCfg.Bot, "bot", "",
+		"the (id|publickey|name) of the 3bot the message is signed as (required)")
+	verifyBotMessageCmd.Flags().BoolVar(
+		&explorerSubCmds.verifyBotMessageCfg.Hex, "hex", false,
+		"interpret the message as a hex-encoded byte slice")
+	verifyBotMessageCmd.Flags().Uint64Var(
+		&explorerSubCmds.verifyBotMessageCfg.Height, "height", 0,
+		"verify the signature against the public key of the 3bot at the given block height, instead of its current one")
+	verifyBotMessageCmd.Flags().Var(
+		cli.NewEncodingTypeFlag(0, &explorerSubCmds.verifyBotMessageCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
+		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
+	getBotNameChildrenCmd.Flags().Var(
+		cli.NewEncodingTypeFlag(0, &explorerSubCmds.getBotNameChildrenCfg.EncodingType, 0), "encoding",
+		cli.EncodingTypeFlagDescription(0))
+	getBotNameDelegatesCmd.Flags().Var(
+		cli.NewEncodingTypeFlag(0, &explorerSubCmds.getBotNameDelegatesCfg.EncodingType, 0), "encoding",
+		cli.EncodingTypeFlagDescription(0))
 }
 
 type explorerSubCmds struct {
@@ -63,6 +158,28 @@ type explorerSubCmds struct {
 	}
 	getBotRecordCfg struct {
 		EncodingType cli.EncodingType
+		Address      bool
+	}
+	listBotsCfg struct {
+		EncodingType     cli.EncodingType
+		Cursor           uint32
+		Limit            int
+		ExpiresWithin    time.Duration
+		HasName          bool
+		RegisteredSince  uint64
+		RegisteredBefore uint64
+	}
+	verifyBotMessageCfg struct {
+		Bot          string
+		Hex          bool
+		Height       uint64
+		EncodingType cli.EncodingType
+	}
+	getBotNameChildrenCfg struct {
+		EncodingType cli.EncodingType
+	}
+	getBotNameDelegatesCfg struct {
+		EncodingType cli.EncodingType
 	}
 }
 
@@ -123,9 +240,25 @@ func (explorerSubCmds *explorerSubCmds) getMintCondition(cmd *cobra.Command, arg
 
 func (explorerSubCmds *explorerSubCmds) getBotRecord(str string) {
 	txDBReader := internal.NewTransactionDBExplorerClient(explorerSubCmds.cli)
-	record, err := txDBReader.GetRecordForString(str)
-	if err != nil {
-		cli.DieWithError("error while fetching the 3bot record", err)
+	var (
+		result interface{}
+		err    error
+	)
+	if explorerSubCmds.getBotRecordCfg.Address {
+		var addr types.NetworkAddress
+		err = addr.LoadString(str)
+		if err != nil {
+			cli.DieWithError("failed to parse network address pos arg", err)
+		}
+		result, err = txDBReader.GetRecordsForNetworkAddress(addr)
+		if err != nil {
+			cli.DieWithError("error while fetching the 3bot records", err)
+		}
+	} else {
+		result, err = txDBReader.GetRecordForString(str)
+		if err != nil {
+			cli.DieWithError("error while fetching the 3bot record", err)
+		}
 	}
 
 	// encode depending on the encoding flag
@@ -144,8 +277,166 @@ func (explorerSubCmds *explorerSubCmds) getBotRecord(str string) {
 			return nil
 		}
 	}
-	err = encode(record)
+	err = encode(result)
 	if err != nil {
 		cli.DieWithError("failed to encode 3bot record", err)
 	}
 }
+
+func (explorerSubCmds *explorerSubCmds) listBots() {
+	cfg := explorerSubCmds.listBotsCfg
+	if cfg.Limit < 0 {
+		cli.Die("the amount of bots to list cannot be negative")
+	}
+	filter := persist.BotRecordFilter{
+		HasName:          cfg.HasName,
+		RegisteredSince:  rivinetypes.BlockHeight(cfg.RegisteredSince),
+		RegisteredBefore: rivinetypes.BlockHeight(cfg.RegisteredBefore),
+	}
+	if cfg.ExpiresWithin > 0 {
+		now := time.Now()
+		filter.ExpiresAfter = rivinetypes.Timestamp(now.Unix())
+		filter.ExpiresBefore = rivinetypes.Timestamp(now.Add(cfg.ExpiresWithin).Unix())
+	}
+
+	txDBReader := internal.NewTransactionDBExplorerClient(explorerSubCmds.cli)
+	result, err := txDBReader.GetRecords(types.BotID(cfg.Cursor), cfg.Limit, filter)
+	if err != nil {
+		cli.DieWithError("error while listing the 3bot records", err)
+	}
+
+	// encode depending on the encoding flag
+	var encode func(interface{}) error
+	switch cfg.EncodingType {
+	case cli.EncodingTypeHuman:
+		e := json.NewEncoder(os.Stdout)
+		e.SetIndent("", "  ")
+		encode = e.Encode
+	case cli.EncodingTypeJSON:
+		encode = json.NewEncoder(os.Stdout).Encode
+	case cli.EncodingTypeHex:
+		encode = func(v interface{}) error {
+			b := siabin.Marshal(v)
+			fmt.Println(hex.EncodeToString(b))
+			return nil
+		}
+	}
+	err = encode(result)
+	if err != nil {
+		cli.DieWithError("failed to encode 3bot records", err)
+	}
+}
+
+func (explorerSubCmds *explorerSubCmds) verifyBotMessage(messageStr, signatureStr string) {
+	cfg := explorerSubCmds.verifyBotMessageCfg
+	if cfg.Bot == "" {
+		cli.Die("the 3bot the message is signed as has to be defined using the --bot flag")
+	}
+	message, err := botMessageFromString(messageStr, cfg.Hex)
+	if err != nil {
+		cli.DieWithError("failed to parse the message", err)
+	}
+	var signature rivinetypes.ByteSlice
+	err = signature.LoadString(signatureStr)
+	if err != nil {
+		cli.DieWithError("failed to parse the hex-encoded signature", err)
+	}
+
+	txDBReader := internal.NewTransactionDBExplorerClient(explorerSubCmds.cli)
+	record, err := txDBReader.GetRecordForString(cfg.Bot)
+	if err != nil {
+		cli.DieWithError("error while fetching the 3bot record", err)
+	}
+	var height *rivinetypes.BlockHeight
+	if cfg.Height != 0 {
+		h := rivinetypes.BlockHeight(cfg.Height)
+		height = &h
+	}
+	result, err := txDBReader.VerifyBotMessage(record.ID, message, signature, height)
+	if err != nil {
+		cli.DieWithError("error while verifying the message", err)
+	}
+
+	switch cfg.EncodingType {
+	case cli.EncodingTypeHuman:
+		if !result.Valid {
+			cli.Die(fmt.Sprintf("invalid signature: message is not signed by 3bot %d (public key %s)", record.ID, result.PublicKey.String()))
+		}
+		fmt.Printf("valid signature: message is signed by 3bot %d (public key %s)\n", record.ID, result.PublicKey.String())
+	case cli.EncodingTypeJSON:
+		err = json.NewEncoder(os.Stdout).Encode(result)
+		if err != nil {
+			cli.DieWithError("failed to encode result", err)
+		}
+	}
+}
+
+func (explorerSubCmds *explorerSubCmds) getBotNameChildren(str string) {
+	var name types.BotName
+	err := name.LoadString(str)
+	if err != nil {
+		cli.DieWithError("failed to parse 3bot name pos arg", err)
+	}
+
+	txDBReader := internal.NewTransactionDBExplorerClient(explorerSubCmds.cli)
+	result, err := txDBReader.GetChildrenForName(name)
+	if err != nil {
+		cli.DieWithError("error while fetching the 3bot sub names", err)
+	}
+
+	// encode depending on the encoding flag
+	var encode func(interface{}) error
+	switch explorerSubCmds.getBotNameChildrenCfg.EncodingType {
+	case cli.EncodingTypeHuman:
+		e := json.NewEncoder(os.Stdout)
+		e.SetIndent("", "  ")
+		encode = e.Encode
+	case cli.EncodingTypeJSON:
+		encode = json.NewEncoder(os.Stdout).Encode
+	case cli.EncodingTypeHex:
+		encode = func(v interface{}) error {
+			b := siabin.Marshal(v)
+			fmt.Println(hex.EncodeToString(b))
+			return nil
+		}
+	}
+	err = encode(result)
+	if err != nil {
+		cli.DieWithError("failed to encode 3bot sub names", err)
+	}
+}
+
+func (explorerSubCmds *explorerSubCmds) getBotNameDelegates(str string) {
+	var name types.BotName
+	err := name.LoadString(str)
+	if err != nil {
+		cli.DieWithError("failed to parse 3bot name pos arg", err)
+	}
+
+	txDBReader := internal.NewTransactionDBExplorerClient(explorerSubCmds.cli)
+	result, err := txDBReader.GetDelegatesForName(name)
+	if err != nil {
+		cli.DieWithError("error while fetching the 3bot name delegates", err)
+	}
+
+	// encode depending on the encoding flag
+	var encode func(interface{}) error
+	switch explorerSubCmds.getBotNameDelegatesCfg.EncodingType {
+	case cli.EncodingTypeHuman:
+		e := json.NewEncoder(os.Stdout)
+		e.SetIndent("", "  ")
+		encode = e.Encode
+	case cli.EncodingTypeJSON:
+		encode = json.NewEncoder(os.Stdout).Encode
+	case cli.EncodingTypeHex:
+		encode = func(v interface{}) error {
+			b := siabin.Marshal(v)
+			fmt.Println(hex.EncodeToString(b))
+			return nil
+		}
+	}
+	err = encode(result)
+	if err != nil {
+		cli.DieWithError("failed to encode 3bot name delegates", err)
+	}
+}
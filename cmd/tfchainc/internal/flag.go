@@ -62,6 +62,52 @@ func (flag *botNameArrayFlag) String() string {
 	return strings.Join(vals, ",")
 }
 
+// BotIDArrayFlagVar defines a BotID Array flag with specified name and usage string.
+// The arguments s points to a BotID slice variable in which to store the interpreted values of the flags.
+// The value of each argument will not try to be separated by comma, each value has to be defined as a separate flag.
+func BotIDArrayFlagVar(f *pflag.FlagSet, s *[]types.BotID, name string, usage string) {
+	f.Var(&botIDArrayFlag{ids: s}, name, usage)
+}
+
+type botIDArrayFlag struct {
+	ids     *[]types.BotID
+	changed bool
+}
+
+// Set implements pflag.Value.Set
+func (flag *botIDArrayFlag) Set(val string) error {
+	if !flag.changed {
+		*flag.ids = make([]types.BotID, 0, 1)
+		flag.changed = true
+	}
+	var newID types.BotID
+	err := newID.LoadString(val)
+	if err != nil {
+		return err
+	}
+	for _, id := range *flag.ids {
+		if id == newID {
+			return errors.New(val + " is already set")
+		}
+	}
+	*flag.ids = append(*flag.ids, newID)
+	return nil
+}
+
+// Type implements pflag.Value.Type
+func (flag *botIDArrayFlag) Type() string {
+	return "BotIDArrayFlag"
+}
+
+// String implements pflag.Value.String
+func (flag *botIDArrayFlag) String() string {
+	vals := make([]string, 0, len(*flag.ids))
+	for _, id := range *flag.ids {
+		vals = append(vals, id.String())
+	}
+	return strings.Join(vals, ",")
+}
+
 // NetworkAddressArrayFlagVar defines a NetworkAddress Array flag with specified name and usage string.
 // The arguments s points to a NetworkAddress slice variable in which to store the interpreted values of the flags.
 // The value of each argument will not try to be separated by comma, each value has to be defined as a separate flag.
@@ -115,6 +161,52 @@ func (flag *networkAddressArrayFlag) String() string {
 	return strings.Join(vals, ",")
 }
 
+// BotServiceArrayFlagVar defines a BotService Array flag with specified name and usage string.
+// The arguments s points to a BotService slice variable in which to store the interpreted values of the flags.
+// The value of each argument will not try to be separated by comma, each value has to be defined as a separate flag.
+func BotServiceArrayFlagVar(f *pflag.FlagSet, s *[]types.BotService, name string, usage string) {
+	f.Var(&botServiceArrayFlag{services: s}, name, usage)
+}
+
+type botServiceArrayFlag struct {
+	services *[]types.BotService
+	changed  bool
+}
+
+// Set implements pflag.Value.Set
+func (flag *botServiceArrayFlag) Set(val string) error {
+	if !flag.changed {
+		*flag.services = make([]types.BotService, 0, 1)
+		flag.changed = true
+	}
+	var newService types.BotService
+	err := newService.LoadString(val)
+	if err != nil {
+		return err
+	}
+	for _, service := range *flag.services {
+		if service.Compare(newService) == 0 {
+			return errors.New(val + " is already set")
+		}
+	}
+	*flag.services = append(*flag.services, newService)
+	return nil
+}
+
+// Type implements pflag.Value.Type
+func (flag *botServiceArrayFlag) Type() string {
+	return "BotServiceArrayFlag"
+}
+
+// String implements pflag.Value.String
+func (flag *botServiceArrayFlag) String() string {
+	vals := make([]string, 0, len(*flag.services))
+	for _, service := range *flag.services {
+		vals = append(vals, service.String())
+	}
+	return strings.Join(vals, ",")
+}
+
 // PublicKeyFlagVar defines a PublicKey flag with specified name and usage string.
 // The arguments pk points to a PublicKey variable in which to store the interpreted values of the flag.
 func PublicKeyFlagVar(f *pflag.FlagSet, pk *rivinetypes.PublicKey, name string, usage string) {
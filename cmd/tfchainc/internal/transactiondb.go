@@ -1,11 +1,14 @@
 package internal
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"strconv"
 
 	"github.com/threefoldfoundation/tfchain/pkg/api"
+	"github.com/threefoldfoundation/tfchain/pkg/persist"
 	"github.com/threefoldfoundation/tfchain/pkg/types"
 
 	rapi "github.com/threefoldtech/rivine/pkg/api"
@@ -49,8 +52,20 @@ func NewTransactionDBExplorerClient(cli *CommandLineClient) *TransactionDBClient
 var (
 	// ensure TransactionDBClient implements the MintConditionGetter interface
 	_ types.MintConditionGetter = (*TransactionDBClient)(nil)
+	// ensure TransactionDBClient implements the MintingCapGetter interface
+	_ types.MintingCapGetter = (*TransactionDBClient)(nil)
+	// ensure TransactionDBClient implements the ConsensusParameterGetter interface
+	_ types.ConsensusParameterGetter = (*TransactionDBClient)(nil)
 	// ensure TransactionDBClient implements the BotRecordReadRegistry interface
 	_ types.BotRecordReadRegistry = (*TransactionDBClient)(nil)
+	// ensure TransactionDBClient implements the FarmerConditionGetter interface
+	_ types.FarmerConditionGetter = (*TransactionDBClient)(nil)
+	// ensure TransactionDBClient implements the FoundationConditionGetter interface
+	_ types.FoundationConditionGetter = (*TransactionDBClient)(nil)
+	// ensure TransactionDBClient implements the FarmRecordReadRegistry interface
+	_ types.FarmRecordReadRegistry = (*TransactionDBClient)(nil)
+	// ensure TransactionDBClient implements the CapacityProofReadRegistry interface
+	_ types.CapacityProofReadRegistry = (*TransactionDBClient)(nil)
 )
 
 // GetActiveMintCondition implements types.MintConditionGetter.GetActiveMintCondition
@@ -75,6 +90,56 @@ func (cli *TransactionDBClient) GetMintConditionAt(height rivinetypes.BlockHeigh
 	return result.MintCondition, nil
 }
 
+// GetMintingCapSince implements types.MintingCapGetter.GetMintingCapSince
+func (cli *TransactionDBClient) GetMintingCapSince(height rivinetypes.BlockHeight, since rivinetypes.Timestamp) (rivinetypes.Currency, bool, error) {
+	result, err := cli.getMintingCap(height, since)
+	if err != nil || result.MintingCap == nil {
+		return rivinetypes.Currency{}, false, err
+	}
+	return *result.MintingCap, true, nil
+}
+
+// GetMintedCoinsSince implements types.MintingCapGetter.GetMintedCoinsSince
+func (cli *TransactionDBClient) GetMintedCoinsSince(height rivinetypes.BlockHeight, since rivinetypes.Timestamp) (rivinetypes.Currency, error) {
+	result, err := cli.getMintingCap(height, since)
+	if err != nil {
+		return rivinetypes.Currency{}, err
+	}
+	return result.Minted, nil
+}
+
+func (cli *TransactionDBClient) getMintingCap(height rivinetypes.BlockHeight, since rivinetypes.Timestamp) (api.TransactionDBGetMintingCap, error) {
+	var result api.TransactionDBGetMintingCap
+	err := cli.client.GetAPI(fmt.Sprintf("%s/mintingcap?height=%d&since=%d", cli.rootEndpoint, height, since), &result)
+	if err != nil {
+		return api.TransactionDBGetMintingCap{}, fmt.Errorf(
+			"failed to get minting cap since %d at height %d from daemon: %v", since, height, err)
+	}
+	return result, nil
+}
+
+// GetActiveConsensusParameters implements types.ConsensusParameterGetter.GetActiveConsensusParameters
+func (cli *TransactionDBClient) GetActiveConsensusParameters() (types.ConsensusParameters, error) {
+	var result api.TransactionDBGetConsensusParameters
+	err := cli.client.GetAPI(cli.rootEndpoint+"/parameters", &result)
+	if err != nil {
+		return types.ConsensusParameters{}, fmt.Errorf(
+			"failed to get active consensus parameters from daemon: %v", err)
+	}
+	return result.Parameters, nil
+}
+
+// GetConsensusParametersAt implements types.ConsensusParameterGetter.GetConsensusParametersAt
+func (cli *TransactionDBClient) GetConsensusParametersAt(height rivinetypes.BlockHeight) (types.ConsensusParameters, error) {
+	var result api.TransactionDBGetConsensusParameters
+	err := cli.client.GetAPI(fmt.Sprintf("%s/parameters/%d", cli.rootEndpoint, height), &result)
+	if err != nil {
+		return types.ConsensusParameters{}, fmt.Errorf(
+			"failed to get consensus parameters at height %d from daemon: %v", height, err)
+	}
+	return result.Parameters, nil
+}
+
 // GetRecordForID implements types.BotRecordReadRegistry.GetRecordForID
 func (cli *TransactionDBClient) GetRecordForID(id types.BotID) (*types.BotRecord, error) {
 	var result api.TransactionDBGetBotRecord
@@ -105,6 +170,76 @@ func (cli *TransactionDBClient) GetRecordForName(name types.BotName) (*types.Bot
 	return &result.Record, nil
 }
 
+// GetRecordsForNetworkAddress returns the records of all bots that registered the given network address.
+func (cli *TransactionDBClient) GetRecordsForNetworkAddress(address types.NetworkAddress) ([]types.BotRecord, error) {
+	var result api.TransactionDBGetBotRecords
+	err := cli.client.GetAPI(fmt.Sprintf("%s/3bots/byaddress/%s", cli.rootEndpoint, address.String()), &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bot records for network address %s from daemon: %v", address.String(), err)
+	}
+	return result.Records, nil
+}
+
+// GetRecords returns a page of at most limit bot records, registered after the bot identified by the given cursor
+// and matching the given filter. A limit of 0 lists the default amount of bot records, as defined by the daemon.
+func (cli *TransactionDBClient) GetRecords(cursor types.BotID, limit int, filter persist.BotRecordFilter) (*api.TransactionDBGetBotRecordList, error) {
+	query := url.Values{}
+	if cursor != 0 {
+		query.Set("cursor", cursor.String())
+	}
+	if limit != 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if filter.ExpiresAfter != 0 {
+		query.Set("expiresafter", strconv.FormatUint(uint64(filter.ExpiresAfter), 10))
+	}
+	if filter.ExpiresBefore != 0 {
+		query.Set("expiresbefore", strconv.FormatUint(uint64(filter.ExpiresBefore), 10))
+	}
+	if filter.HasName {
+		query.Set("hasname", "true")
+	}
+	if filter.RegisteredSince != 0 {
+		query.Set("registeredsince", strconv.FormatUint(uint64(filter.RegisteredSince), 10))
+	}
+	if filter.RegisteredBefore != 0 {
+		query.Set("registeredbefore", strconv.FormatUint(uint64(filter.RegisteredBefore), 10))
+	}
+	endpoint := cli.rootEndpoint + "/3bots"
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	var result api.TransactionDBGetBotRecordList
+	err := cli.client.GetAPI(endpoint, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bot records from daemon: %v", err)
+	}
+	return &result, nil
+}
+
+// VerifyBotMessage verifies the given signature of the given message, signed as the bot identified by the given ID.
+// The signature is verified against the current public key of the bot,
+// or against the public key the bot had at the given block height, if defined.
+func (cli *TransactionDBClient) VerifyBotMessage(id types.BotID, message []byte, signature rivinetypes.ByteSlice, height *rivinetypes.BlockHeight) (*api.TransactionDBVerifyBotMessage, error) {
+	b, err := json.Marshal(api.TransactionDBVerifyBotMessagePOST{
+		Message:   message,
+		Signature: signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("%s/3bot/%s/verify", cli.rootEndpoint, id.String())
+	if height != nil {
+		endpoint += fmt.Sprintf("?height=%d", *height)
+	}
+	var result api.TransactionDBVerifyBotMessage
+	err = cli.client.PostResp(endpoint, string(b), &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify message of bot %s: %v", id.String(), err)
+	}
+	return &result, nil
+}
+
 // GetBotTransactionIdentifiers implements types.BotRecordReadRegistry.GetBotTransactionIdentifiers
 func (cli *TransactionDBClient) GetBotTransactionIdentifiers(id types.BotID) ([]rivinetypes.TransactionID, error) {
 	var result api.TransactionDBGetBotTransactions
@@ -115,6 +250,26 @@ func (cli *TransactionDBClient) GetBotTransactionIdentifiers(id types.BotID) ([]
 	return result.Identifiers, nil
 }
 
+// GetDelegatesForName implements types.BotRecordReadRegistry.GetDelegatesForName
+func (cli *TransactionDBClient) GetDelegatesForName(name types.BotName) ([]types.BotID, error) {
+	var result api.TransactionDBGetBotNameDelegates
+	err := cli.client.GetAPI(fmt.Sprintf("%s/whois/3bot/%s/delegates", cli.rootEndpoint, name.String()), &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delegates for botname %s from daemon: %v", name.String(), err)
+	}
+	return result.Delegates, nil
+}
+
+// GetChildrenForName returns all (active) sub names of the given name.
+func (cli *TransactionDBClient) GetChildrenForName(name types.BotName) ([]types.BotName, error) {
+	var result api.TransactionDBGetBotNameChildren
+	err := cli.client.GetAPI(fmt.Sprintf("%s/whois/3bot/%s/children", cli.rootEndpoint, name.String()), &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get children for botname %s from daemon: %v", name.String(), err)
+	}
+	return result.Children, nil
+}
+
 // GetRecordForString gets a bot record for either a ID, (public) key or name,
 // as long as it is referenced to a registered Bot.
 func (cli *TransactionDBClient) GetRecordForString(str string) (*types.BotRecord, error) {
@@ -165,3 +320,85 @@ func (cli *TransactionDBClient) GetTFTTransactionIDForERC20TransactionID(txid ty
 	}
 	return result.TfchainTransactionID, true, nil
 }
+
+// GetFarmerCondition implements types.FarmerConditionGetter.GetFarmerCondition
+func (cli *TransactionDBClient) GetFarmerCondition(farm types.FarmID) (rivinetypes.UnlockConditionProxy, error) {
+	var result api.TransactionDBGetCapacityFarm
+	err := cli.client.GetAPI(fmt.Sprintf("%s/capacity/farms/%d", cli.rootEndpoint, farm), &result)
+	if err != nil {
+		return rivinetypes.UnlockConditionProxy{}, fmt.Errorf(
+			"failed to get farmer condition for farm %d from daemon: %v", farm, err)
+	}
+	return result.FarmerCondition, nil
+}
+
+// GetActiveFoundationCondition implements types.FoundationConditionGetter.GetActiveFoundationCondition
+//
+// The foundation is represented by the coin creators,
+// hence the active mint condition is returned.
+func (cli *TransactionDBClient) GetActiveFoundationCondition() (rivinetypes.UnlockConditionProxy, error) {
+	return cli.GetActiveMintCondition()
+}
+
+// GetFoundationConditionAt implements types.FoundationConditionGetter.GetFoundationConditionAt
+//
+// The foundation is represented by the coin creators,
+// hence the mint condition at the given height is returned.
+func (cli *TransactionDBClient) GetFoundationConditionAt(height rivinetypes.BlockHeight) (rivinetypes.UnlockConditionProxy, error) {
+	return cli.GetMintConditionAt(height)
+}
+
+// GetFarm implements types.FarmRecordReadRegistry.GetFarm
+func (cli *TransactionDBClient) GetFarm(id types.FarmID) (*types.FarmRecord, error) {
+	var result api.TransactionDBGetFarmRecord
+	err := cli.client.GetAPI(fmt.Sprintf("%s/farm/%d", cli.rootEndpoint, id), &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get farm record for ID %d from daemon: %v", id, err)
+	}
+	return &result.Record, nil
+}
+
+// GetFarmForName implements types.FarmRecordReadRegistry.GetFarmForName
+func (cli *TransactionDBClient) GetFarmForName(name string) (*types.FarmRecord, error) {
+	var result api.TransactionDBGetFarmRecord
+	err := cli.client.GetAPI(fmt.Sprintf("%s/farm/%s", cli.rootEndpoint, name), &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get farm record for name %s from daemon: %v", name, err)
+	}
+	return &result.Record, nil
+}
+
+// GetCapacityChallenge implements types.CapacityProofReadRegistry.GetCapacityChallenge
+func (cli *TransactionDBClient) GetCapacityChallenge(height rivinetypes.BlockHeight, node rivinetypes.PublicKey) (types.CapacityChallenge, error) {
+	var result api.TransactionDBGetCapacityChallenge
+	err := cli.client.GetAPI(fmt.Sprintf("%s/capacity/nodes/%s/challenges/%d", cli.rootEndpoint, node.String(), height), &result)
+	if err != nil {
+		return types.CapacityChallenge{}, fmt.Errorf(
+			"failed to get capacity challenge at height %d for node %s from daemon: %v", height, node.String(), err)
+	}
+	return result.Challenge, nil
+}
+
+// GetCapacityRecordForNode implements types.CapacityProofReadRegistry.GetCapacityRecordForNode
+func (cli *TransactionDBClient) GetCapacityRecordForNode(node rivinetypes.PublicKey) (*types.CapacityRecord, error) {
+	var result api.TransactionDBGetCapacityNode
+	err := cli.client.GetAPI(fmt.Sprintf("%s/capacity/nodes/%s", cli.rootEndpoint, node.String()), &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get capacity record for node %s from daemon: %v", node.String(), err)
+	}
+	return &result.Record, nil
+}
+
+// GetCapacityProofResultForNode implements types.CapacityProofReadRegistry.GetCapacityProofResultForNode
+func (cli *TransactionDBClient) GetCapacityProofResultForNode(node rivinetypes.PublicKey, height rivinetypes.BlockHeight) (*types.CapacityProofResult, error) {
+	var result api.TransactionDBGetCapacityChallenge
+	err := cli.client.GetAPI(fmt.Sprintf("%s/capacity/nodes/%s/challenges/%d", cli.rootEndpoint, node.String(), height), &result)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get capacity proof result at height %d for node %s from daemon: %v", height, node.String(), err)
+	}
+	if result.Result == nil {
+		return nil, types.ErrCapacityProofResultNotFound
+	}
+	return result.Result, nil
+}
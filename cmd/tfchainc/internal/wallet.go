@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/threefoldfoundation/tfchain/pkg/api"
+	tftypes "github.com/threefoldfoundation/tfchain/pkg/types"
 
 	"github.com/threefoldtech/rivine/types"
 )
@@ -59,3 +60,18 @@ func (wallet *WalletClient) GreedySignTx(t *types.Transaction) error {
 	}
 	return nil
 }
+
+// SignBotMessage signs the given message as the bot identified by the given ID,
+// using the private key of that bot, which has to be loaded into the wallet.
+func (wallet *WalletClient) SignBotMessage(id tftypes.BotID, message []byte) (*api.WalletSignBotMessage, error) {
+	b, err := json.Marshal(api.WalletSignBotMessagePOST{Message: message})
+	if err != nil {
+		return nil, err
+	}
+	var result api.WalletSignBotMessage
+	err = wallet.client.PostResp(fmt.Sprintf("/wallet/3bot/%s/sign", id.String()), string(b), &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message as bot %s: %v", id.String(), err)
+	}
+	return &result, nil
+}
@@ -34,6 +34,8 @@ func main() {
 	// register root command
 	cliClient.ERC20Cmd = createERC20Cmd(cliClient)
 	cliClient.RootCmd.AddCommand(cliClient.ERC20Cmd)
+	cliClient.RootCmd.AddCommand(createBotCmd(cliClient))
+	cliClient.RootCmd.AddCommand(createMintingCmd(cliClient))
 
 	// no ERC20-Tx Validation is done on client-side
 	nopERC20TxValidator := types.NopERC20TransactionValidator{}
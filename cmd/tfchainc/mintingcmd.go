@@ -0,0 +1,295 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/threefoldfoundation/tfchain/cmd/tfchainc/internal"
+	"github.com/threefoldfoundation/tfchain/pkg/types"
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/cli"
+	rivinecli "github.com/threefoldtech/rivine/pkg/client"
+	rivinetypes "github.com/threefoldtech/rivine/types"
+)
+
+// createMintingCmd creates the root command for the (offline) multi-party coin creation workflow,
+// allowing the coin creators to propose, co-sign and finally publish a coin creation transaction.
+func createMintingCmd(client *internal.CommandLineClient) *cobra.Command {
+	mintingSubCmds := &mintingSubCmds{cli: client}
+
+	// define commands
+	var (
+		rootCmd = &cobra.Command{
+			Use:   "minting",
+			Short: "Propose, co-sign and publish coin creations",
+			Long: `Propose, co-sign and publish coin creations.
+
+A coin creation proposal wraps a CoinCreationTransaction together with the hash
+of the capacity proof it is based on. It is passed around (offline) between the
+coin creators, each of them signing it using the keys of their wallet,
+until enough signatures are collected to fulfill the active mint condition.
+
+All proposal arguments are file paths, '-' can be used to read from STDIN.
+Proposals are always printed to STDOUT.
+`,
+		}
+		proposeCmd = &cobra.Command{
+			Use:   "propose <dest>|<rawCondition> <amount> [<dest>|<rawCondition> <amount>]...",
+			Short: "Propose a new coin creation",
+			Long: `Propose a new coin creation, committing to the hash of a capacity proof,
+which is either computed from the file given using the --proof flag,
+or given directly (as a hex-encoded hash) using the --proof-hash flag.
+
+The hash of the capacity proof is stored as the arbitrary data of the transaction,
+optionally followed by the description given using the --description flag.
+`,
+			Run: mintingSubCmds.propose,
+		}
+		signCmd = &cobra.Command{
+			Use:   "sign <proposal>",
+			Short: "Sign a coin creation proposal",
+			Long:  "Sign a coin creation proposal using all keys of the wallet that are part of the active mint condition.",
+			Run:   rivinecli.Wrap(mintingSubCmds.sign),
+		}
+		mergeCmd = &cobra.Command{
+			Use:   "merge <proposal> <proposal> [<proposal>]...",
+			Short: "Merge the signatures of coin creation proposals",
+			Long: `Merge the signatures of multiple copies of the same coin creation proposal,
+each signed by one or multiple coin creators, into a single proposal.`,
+			Run: mintingSubCmds.merge,
+		}
+		statusCmd = &cobra.Command{
+			Use:   "status <proposal>",
+			Short: "Print the signature status of a coin creation proposal",
+			Long: `Print which keys of the active mint condition have signed the coin creation proposal,
+and how many more signatures are required in order to publish it.`,
+			Run: rivinecli.Wrap(mintingSubCmds.status),
+		}
+		publishCmd = &cobra.Command{
+			Use:   "publish <proposal>",
+			Short: "Publish a fully signed coin creation proposal",
+			Long: `Publish a coin creation proposal, signed by enough keys of the active mint condition,
+as a CoinCreationTransaction to the transaction pool.`,
+			Run: rivinecli.Wrap(mintingSubCmds.publish),
+		}
+	)
+
+	rootCmd.AddCommand(
+		proposeCmd,
+		signCmd,
+		mergeCmd,
+		statusCmd,
+		publishCmd,
+	)
+
+	// register flags
+	proposeCmd.Flags().StringVar(
+		&mintingSubCmds.proposeCfg.ProofFile, "proof", "",
+		"file containing the capacity proof, of which the hash is committed to")
+	proposeCmd.Flags().StringVar(
+		&mintingSubCmds.proposeCfg.ProofHash, "proof-hash", "",
+		"hex-encoded hash of the capacity proof to commit to, used instead of --proof")
+	proposeCmd.Flags().StringVar(
+		&mintingSubCmds.proposeCfg.Description, "description", "",
+		"optional description, stored as part of the arbitrary data after the capacity proof hash")
+	statusCmd.Flags().Var(
+		cli.NewEncodingTypeFlag(cli.EncodingTypeHuman, &mintingSubCmds.statusCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
+		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
+
+	return rootCmd
+}
+
+type mintingSubCmds struct {
+	cli        *internal.CommandLineClient
+	proposeCfg struct {
+		ProofFile   string
+		ProofHash   string
+		Description string
+	}
+	statusCfg struct {
+		EncodingType cli.EncodingType
+	}
+}
+
+func (mintingSubCmds *mintingSubCmds) propose(cmd *cobra.Command, args []string) {
+	cfg := mintingSubCmds.proposeCfg
+	currencyConvertor := mintingSubCmds.cli.CreateCurrencyConvertor()
+
+	// Check that the args are condition + value pairs
+	if len(args) == 0 || len(args)%2 != 0 {
+		cmd.UsageFunc()(cmd)
+		cli.Die("Invalid arguments. Arguments must be of the form <dest>|<rawCondition> <amount> [<dest>|<rawCondition> <amount>]...")
+	}
+	pairs, err := parsePairedOutputs(args, currencyConvertor.ParseCoinString)
+	if err != nil {
+		cmd.UsageFunc()(cmd)
+		cli.Die(err)
+	}
+
+	// compute or parse the capacity proof hash
+	var proofHash crypto.Hash
+	switch {
+	case cfg.ProofFile != "" && cfg.ProofHash != "":
+		cli.Die("only one of the --proof and --proof-hash flags can be defined")
+	case cfg.ProofFile != "":
+		proof, err := ioutil.ReadFile(cfg.ProofFile)
+		if err != nil {
+			cli.DieWithError("failed to read the capacity proof", err)
+		}
+		proofHash = crypto.HashBytes(proof)
+	case cfg.ProofHash != "":
+		err = proofHash.LoadString(cfg.ProofHash)
+		if err != nil {
+			cli.DieWithError("invalid capacity proof hash", err)
+		}
+	default:
+		cmd.UsageFunc()(cmd)
+		cli.Die("the capacity proof has to be defined using either the --proof or --proof-hash flag")
+	}
+
+	tx := types.CoinCreationTransaction{
+		Nonce:     types.RandomTransactionNonce(),
+		MinerFees: []rivinetypes.Currency{mintingSubCmds.cli.Config.MinimumTransactionFee},
+	}
+	for _, pair := range pairs {
+		tx.CoinOutputs = append(tx.CoinOutputs, rivinetypes.CoinOutput{
+			Value:     pair.Value,
+			Condition: pair.Condition,
+		})
+	}
+	printCoinCreationProposal(types.NewCoinCreationProposal(tx, proofHash, cfg.Description))
+}
+
+func (mintingSubCmds *mintingSubCmds) sign(path string) {
+	proposal := loadCoinCreationProposal(path)
+
+	rtx := proposal.Transaction.Transaction()
+	err := internal.NewWalletClient(mintingSubCmds.cli).GreedySignTx(&rtx)
+	if err != nil {
+		cli.DieWithError("failed to sign the coin creation proposal", err)
+	}
+	proposal.Transaction, err = types.CoinCreationTransactionFromTransaction(rtx)
+	if err != nil {
+		cli.DieWithError("failed to convert the signed transaction back into a coin creation transaction", err)
+	}
+	printCoinCreationProposal(proposal)
+}
+
+func (mintingSubCmds *mintingSubCmds) merge(cmd *cobra.Command, args []string) {
+	if len(args) < 2 {
+		cmd.UsageFunc()(cmd)
+		cli.Die("at least two proposals are required in order to merge them")
+	}
+	proposal := loadCoinCreationProposal(args[0])
+	for _, path := range args[1:] {
+		err := proposal.Merge(loadCoinCreationProposal(path))
+		if err != nil {
+			cli.DieWithError(fmt.Sprintf("failed to merge proposal %q", path), err)
+		}
+	}
+	printCoinCreationProposal(proposal)
+}
+
+func (mintingSubCmds *mintingSubCmds) status(path string) {
+	proposal := loadCoinCreationProposal(path)
+	status := mintingSubCmds.signatureStatus(proposal)
+
+	switch mintingSubCmds.statusCfg.EncodingType {
+	case cli.EncodingTypeHuman:
+		fmt.Printf("signed by %d of the %d required keys:\n", len(status.Signed), status.RequiredSignatures)
+		for _, uh := range status.Signed {
+			fmt.Println("  [x]", uh.String())
+		}
+		for _, uh := range status.Unsigned {
+			fmt.Println("  [ ]", uh.String())
+		}
+		if missing := status.MissingSignatures(); missing > 0 {
+			fmt.Printf("%d more signature(s) required\n", missing)
+		} else {
+			fmt.Println("ready to be published")
+		}
+		if status.LockTime != 0 {
+			fmt.Printf("the mint condition is time locked until %d\n", status.LockTime)
+		}
+	case cli.EncodingTypeJSON:
+		err := json.NewEncoder(os.Stdout).Encode(struct {
+			types.CoinCreationSignatureStatus
+			MissingSignatures uint64 `json:"missingsignatures"`
+		}{
+			CoinCreationSignatureStatus: status,
+			MissingSignatures:           status.MissingSignatures(),
+		})
+		if err != nil {
+			cli.DieWithError("failed to encode the signature status", err)
+		}
+	}
+}
+
+func (mintingSubCmds *mintingSubCmds) publish(path string) {
+	proposal := loadCoinCreationProposal(path)
+	status := mintingSubCmds.signatureStatus(proposal)
+	if missing := status.MissingSignatures(); missing > 0 {
+		cli.Die(fmt.Sprintf("cannot publish the coin creation proposal: %d more signature(s) required", missing))
+	}
+
+	txID, err := internal.NewTransactionPoolClient(mintingSubCmds.cli).AddTransactiom(proposal.Transaction.Transaction())
+	if err != nil {
+		cli.DieWithError("failed to publish the coin creation transaction", err)
+	}
+	fmt.Println("published coin creation transaction with ID:")
+	fmt.Println(txID.String())
+}
+
+// signatureStatus returns the signature status of the given proposal,
+// against the mint condition that is currently active.
+func (mintingSubCmds *mintingSubCmds) signatureStatus(proposal types.CoinCreationProposal) types.CoinCreationSignatureStatus {
+	mintCondition, err := internal.NewTransactionDBConsensusClient(mintingSubCmds.cli).GetActiveMintCondition()
+	if err != nil {
+		cli.DieWithError("failed to get the active mint condition", err)
+	}
+	status, err := proposal.SignatureStatus(mintCondition)
+	if err != nil {
+		cli.DieWithError("failed to get the signature status of the coin creation proposal", err)
+	}
+	return status
+}
+
+// loadCoinCreationProposal loads and validates a JSON-encoded coin creation proposal
+// from the given file, or from the STDIN in case the path equals '-'.
+func loadCoinCreationProposal(path string) types.CoinCreationProposal {
+	var (
+		b   []byte
+		err error
+	)
+	if path == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		cli.DieWithError(fmt.Sprintf("failed to read proposal %q", path), err)
+	}
+	var proposal types.CoinCreationProposal
+	err = json.Unmarshal(b, &proposal)
+	if err != nil {
+		cli.DieWithError(fmt.Sprintf("failed to decode proposal %q", path), err)
+	}
+	err = proposal.Validate()
+	if err != nil {
+		cli.DieWithError(fmt.Sprintf("invalid proposal %q", path), err)
+	}
+	return proposal
+}
+
+// printCoinCreationProposal prints the given proposal as (indented) JSON to the STDOUT.
+func printCoinCreationProposal(proposal types.CoinCreationProposal) {
+	e := json.NewEncoder(os.Stdout)
+	e.SetIndent("", "  ")
+	err := e.Encode(proposal)
+	if err != nil {
+		cli.DieWithError("failed to encode the coin creation proposal", err)
+	}
+}
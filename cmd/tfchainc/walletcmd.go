@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -35,6 +36,42 @@ The returned (raw) MinterDefinitionTransaction still has to be signed, prior to
 	`,
 			Run: walletSubCmds.createMinterDefinitionTxCmd,
 		}
+		createMintingCapDefinitionTxCmd = &cobra.Command{
+			Use:   "mintingcapdefinitiontransaction <amount>",
+			Short: "Create a new minting cap definition transaction",
+			Long: `Create a new minting cap definition transaction using the given amount.
+The amount defines the maximum amount of coins, miner fees included, that can be created
+by coin creation transactions within the rolling window of the network.
+
+Amounts have to be given expressed in the OneCoin unit, and without the unit of currency.
+Decimals are possible and have to be defined using the decimal point.
+
+Lowering the minting cap takes effect immediately,
+while raising it only takes full effect once an entire window has passed.
+
+The returned (raw) MintingCapDefinitionTransaction still has to be signed, prior to sending.
+	`,
+			Run: walletSubCmds.createMintingCapDefinitionTxCmd,
+		}
+		createConsensusParameterUpdateTxCmd = &cobra.Command{
+			Use:   "consensusparameterupdatetransaction <effectiveheight> <name> <value> [<name> <value>]...",
+			Short: "Create a new consensus parameter update transaction",
+			Long: `Create a new consensus parameter update transaction using the given name-value pairs.
+The updated consensus parameters apply to all blocks starting from the given (future) effective block height.
+
+The following consensus parameters can be updated:
+
+  botregistrationfee, botmonthlyfee, botnamefee, botnetworkaddressfee, botservicefee,
+  erc20conversionminimum, erc20addressregistrationfee: expressed in the OneCoin unit,
+    and without the unit of currency, decimals have to be defined using the decimal point;
+  txfeecheckheight: the block height from which the minimum transaction fee is enforced;
+  botsubnameactivationheight: the block height from which the 3bot sub name rules apply;
+  botgraceperiodactivationheight: the block height from which the 3bot name grace period applies.
+
+The returned (raw) ConsensusParameterUpdateTransaction still has to be signed, prior to sending.
+	`,
+			Run: walletSubCmds.createConsensusParameterUpdateTxCmd,
+		}
 		createCoinCreationTxCmd = &cobra.Command{
 			Use:   "coincreationtransaction <dest>|<rawCondition> <amount> [<dest>|<rawCondition> <amount>]...",
 			Short: "Create a new coin creation transaction",
@@ -83,10 +120,15 @@ The public key is to be used to get to know the unique ID assigned to your regis
 			Long: `Create, sign and send a 3bot record update transaction, updating an existing 3bot.
 The coin inputs are funded and signed using the wallet of this daemon.
 The Public key linked to the 3bot has to be loaded into the wallet in order to be able to sign.
+Should the 3bot be owned by a condition, the key(s) required to fulfill that condition
+have to be loaded into the wallet instead.
 
-Addresses and names to be removed/added are defined as flags, and at least one
+Addresses, names and services to be removed/added are defined as flags, and at least one
 update is required (defining NrOfMonths to add (and pay) to the 3bot record counts as an update as well).
 
+Services are defined as <name>/<protocol>:<port>[@<version>], e.g. http/tcp:80 or xmpp-client/tcp:5222@1.0,
+where the protocol is either tcp or udp. A service is removed by defining it exactly as it is registered.
+
 > NOTE: a name can only be removed if owned (which implies the 3bot has to be active at the point of the update).
 
 Should you want to prepay more than 1 month at once, this is possible and
@@ -101,6 +143,31 @@ and you'll receive the TxID which will allow you to look it up in an explorer.
 			Run: rivinecli.Wrap(walletSubCmds.sendBotRecordUpdateTxCmd),
 		}
 
+		sendBotKeyRotationTxCmd = &cobra.Command{
+			Use:   "botkeyrotation (id|publickey)",
+			Short: "Create, sign and send a 3bot key rotation transaction",
+			Long: `Create, sign and send a 3bot key rotation transaction, replacing the public key of an existing 3bot.
+The coin inputs are funded and signed using the wallet of this daemon.
+The Public key currently linked to the 3bot has to be loaded into the wallet in order to be able to sign.
+Should the 3bot be owned by a condition, the key(s) required to fulfill that condition
+have to be loaded into the wallet instead.
+
+By default the new public key is generated from this wallet's primary seed,
+however, it is also allowed for you to give a public key that is already loaded in this wallet.
+The new public key cannot be linked to another 3bot already.
+
+The ownership of the 3bot can optionally be given to a condition, using the --owner flag,
+which accepts an address or JSON-encoded (multisig) condition. If not given,
+the 3bot will be owned by its new public key.
+
+All fees are automatically added.
+
+If this command returns without errors, the Tx is signed and sent,
+and you'll receive the TxID and new PublicKey which will allow you to look it up in an explorer.
+`,
+			Run: rivinecli.Wrap(walletSubCmds.sendBotKeyRotationTxCmd),
+		}
+
 		createBotNameTransferTxCmd = &cobra.Command{
 			Use:   "botnametransfer (id|publickey) (id|publickey) names...",
 			Args:  cobra.MinimumNArgs(3),
@@ -108,6 +175,8 @@ and you'll receive the TxID which will allow you to look it up in an explorer.
 			Long: `Create and optionally sign a 3bot name transfer transaction, involving two active 3bots.
 The coin inputs are funded and signed using the wallet of this daemon.
 The Public key linked to the 3bot has to be loaded into the wallet in order to be able to sign.
+Should one of the 3bots be owned by a condition, the fulfillment-based version of the transaction is created,
+allowing the transaction to be signed by the owners of the condition(s), one wallet at a time.
 
 The first positional argument identifies the sender, nad the second positional argument identifies the receiver.
 All other positional arguments (at least one more is required) define the names to be transfered.
@@ -121,6 +190,90 @@ is printed to the STDOUT.
 			Run: walletSubCmds.createBotNameTransferTxCmd,
 		}
 
+		createBotNameSaleOfferTxCmd = &cobra.Command{
+			Use:   "botnamesaleoffer (id|publickey) price expirationheight names...",
+			Args:  cobra.MinimumNArgs(4),
+			Short: "Create and optionally sign a 3bot name sale offer",
+			Long: `Create and optionally sign a 3bot name sale offer, selling one or multiple names of an active 3bot.
+The offer is a 3bot name sale transaction without a receiver, which can be completed by any 3bot,
+using the botnamesale command, prior to (and including) the given expiration (block) height.
+The offer is bound to the current version of the 3bot record, and can no longer be completed once that record is updated.
+The Public key linked to the 3bot has to be loaded into the wallet in order to be able to sign.
+Should the 3bot be owned by a condition, the offer can be signed by the owners of that condition, one wallet at a time.
+
+The first positional argument identifies the sender (seller), the second positional argument defines the price,
+and the third positional argument defines the expiration height of the offer.
+All other positional arguments (at least one more is required) define the names to be sold.
+
+The price is paid to an address generated from this wallet's primary seed,
+unless another address is defined using the --payout flag.
+
+If this command returns without errors, the offer (optionally signed)
+is printed to the STDOUT.
+`,
+			Run: walletSubCmds.createBotNameSaleOfferTxCmd,
+		}
+
+		createBotNameSaleTxCmd = &cobra.Command{
+			Use:   "botnamesale (id|publickey) offer",
+			Args:  cobra.ExactArgs(2),
+			Short: "Complete and optionally sign a 3bot name sale offer",
+			Long: `Complete and optionally sign a (JSON-encoded) 3bot name sale offer, as created using the botnamesaleoffer command.
+The first positional argument identifies the receiver (buyer), and the second positional argument is the offer.
+The price as well as all fees are funded and signed using the wallet of this daemon.
+The Public key linked to the 3bot has to be loaded into the wallet in order to be able to sign.
+
+The names and coins are transferred atomically, meaning that the names are only transferred
+to the receiver if the price is paid to the sender, within the same transaction.
+
+If this command returns without errors, the Tx (optionally signed)
+is printed to the STDOUT, and can be sent using the "wallet send transaction" command.
+`,
+			Run: walletSubCmds.createBotNameSaleTxCmd,
+		}
+
+		sendBotNameDelegationTxCmd = &cobra.Command{
+			Use:   "botnamedelegation (id|publickey) name",
+			Args:  cobra.ExactArgs(2),
+			Short: "Create, sign and send a 3bot name delegation transaction",
+			Long: `Create, sign and send a 3bot name delegation transaction,
+allowing (or no longer allowing) other 3bots to register sub names of a name owned by the given 3bot.
+The coin inputs are funded and signed using the wallet of this daemon.
+The Public key linked to the 3bot has to be loaded into the wallet in order to be able to sign.
+Should the 3bot be owned by a condition, the key(s) required to fulfill that condition
+have to be loaded into the wallet instead.
+
+Delegates are added and removed as flags, identified by their (3bot) ID,
+and at least one delegate has to be added or removed.
+Delegates are bound to the current owner of the name,
+and are therefore void as soon as the name is transferred or expires.
+
+All fees are automatically added.
+
+If this command returns without errors, the Tx is signed and sent,
+and you'll receive the TxID which will allow you to look it up in an explorer.
+`,
+			Run: walletSubCmds.sendBotNameDelegationTxCmd,
+		}
+
+		sendCoinBurnTxCmd = &cobra.Command{
+			Use:   "coinburn amount",
+			Short: "Create, sign and send a coin burn transaction",
+			Long: `Create, sign and send a coin burn transaction, destroying the given amount of coins.
+The coin inputs are funded and signed using the wallet of this daemon.
+A reason is required, and has to be given using the --reason flag.
+
+Should the network define a burn-authorization condition,
+the key(s) required to fulfill that condition have to be loaded into the wallet as well.
+
+The Minimum Miner Fee will be added on top of the given amount automatically.
+
+If this command returns without errors, the Tx is signed and sent,
+and you'll receive the TxID which will allow you to look it up in an explorer.
+`,
+			Run: rivinecli.Wrap(walletSubCmds.sendCoinBurnTxCmd),
+		}
+
 		sendERC20FundsCmd = &cobra.Command{
 			Use:   "erc20funds erc20_address amount",
 			Short: "Convert TFT to ERC20 funds and send those to an ERC20 adddress (minus fees)",
@@ -144,6 +297,19 @@ using the unlocked wallet from the tfchain daemon.
 			Run: walletSubCmds.sendERC20AddressRegistration,
 		}
 
+		signBotMessageCmd = &cobra.Command{
+			Use:   "sign-message message",
+			Short: "Sign a message as a 3bot",
+			Long: `Sign a message as the 3bot identified by the --bot flag,
+proving that the message comes from that 3bot.
+The Public key linked to the 3bot has to be loaded into the wallet in order to be able to sign.
+
+The message is signed as is, or decoded first in case the --hex flag is given.
+The signature can be verified by anyone using the explorer's verify-message command.
+`,
+			Run: rivinecli.Wrap(walletSubCmds.signBotMessage),
+		}
+
 		listERC20AddressesCmd = &cobra.Command{
 			Use:   "erc20addresses",
 			Short: "List all known ERC20 addresses for this wallet",
@@ -159,12 +325,19 @@ from which the ERC20 address is then derived, is known by this wallet.`,
 	// add commands as wallet sub commands
 	client.WalletCmd.RootCmdCreate.AddCommand(
 		createMinterDefinitionTxCmd,
+		createMintingCapDefinitionTxCmd,
+		createConsensusParameterUpdateTxCmd,
 		createCoinCreationTxCmd,
 		createBotNameTransferTxCmd,
+		createBotNameSaleOfferTxCmd,
+		createBotNameSaleTxCmd,
 	)
 	client.WalletCmd.RootCmdSend.AddCommand(
 		sendBotRegistrationTxCmd,
 		sendBotRecordUpdateTxCmd,
+		sendBotKeyRotationTxCmd,
+		sendBotNameDelegationTxCmd,
+		sendCoinBurnTxCmd,
 		sendERC20FundsCmd,
 		sendERC20FundsClaimCmd,
 		sendERC20AddressRegistrationCmd,
@@ -174,6 +347,10 @@ from which the ERC20 address is then derived, is known by this wallet.`,
 		listERC20AddressesCmd,
 	)
 
+	client.WalletCmd.AddCommand(
+		signBotMessageCmd,
+	)
+
 	// register flags
 	internal.NetworkAddressArrayFlagVar(
 		sendBotRegistrationTxCmd.Flags(),
@@ -224,6 +401,18 @@ from which the ERC20 address is then derived, is known by this wallet.`,
 		"remove-name",
 		"remove one or multiple names owned, each name defined as seperate flag arguments",
 	)
+	internal.BotServiceArrayFlagVar(
+		sendBotRecordUpdateTxCmd.Flags(),
+		&walletSubCmds.sendBotRecordUpdateTxCfg.ServicesToAdd,
+		"add-service",
+		"add one or multiple services, each service defined as seperate flag arguments",
+	)
+	internal.BotServiceArrayFlagVar(
+		sendBotRecordUpdateTxCmd.Flags(),
+		&walletSubCmds.sendBotRecordUpdateTxCfg.ServicesToRemove,
+		"remove-service",
+		"remove one or multiple services, each service defined as seperate flag arguments",
+	)
 	sendBotRecordUpdateTxCmd.Flags().Uint8VarP(
 		&walletSubCmds.sendBotRecordUpdateTxCfg.NrOfMonthsToAdd, "add-months", "m", 0,
 		"the amount of months to add and pay, required to be in the inclusive interval [0, 24]")
@@ -231,6 +420,35 @@ from which the ERC20 address is then derived, is known by this wallet.`,
 		cli.NewEncodingTypeFlag(0, &walletSubCmds.sendBotRecordUpdateTxCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
 		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
 
+	internal.PublicKeyFlagVar(
+		sendBotKeyRotationTxCmd.Flags(),
+		&walletSubCmds.sendBotKeyRotationTxCfg.PublicKey,
+		"public-key",
+		"define a new public key to use (of which the private key is loaded in this daemon's wallet)",
+	)
+	sendBotKeyRotationTxCmd.Flags().StringVar(
+		&walletSubCmds.sendBotKeyRotationTxCfg.Owner, "owner", "",
+		"optionally define the new owner of the 3bot, as an address or JSON-encoded (multisig) condition")
+	sendBotKeyRotationTxCmd.Flags().Var(
+		cli.NewEncodingTypeFlag(0, &walletSubCmds.sendBotKeyRotationTxCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
+		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
+
+	internal.BotIDArrayFlagVar(
+		sendBotNameDelegationTxCmd.Flags(),
+		&walletSubCmds.sendBotNameDelegationTxCfg.DelegatesToAdd,
+		"add",
+		"add one or multiple delegates (3bot IDs), each delegate defined as seperate flag arguments",
+	)
+	internal.BotIDArrayFlagVar(
+		sendBotNameDelegationTxCmd.Flags(),
+		&walletSubCmds.sendBotNameDelegationTxCfg.DelegatesToRemove,
+		"remove",
+		"remove one or multiple delegates (3bot IDs), each delegate defined as seperate flag arguments",
+	)
+	sendBotNameDelegationTxCmd.Flags().Var(
+		cli.NewEncodingTypeFlag(0, &walletSubCmds.sendBotNameDelegationTxCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
+		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
+
 	createBotNameTransferTxCmd.Flags().Var(
 		cli.NewEncodingTypeFlag(0, &walletSubCmds.createBotNameTransferTxCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
 		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
@@ -238,6 +456,30 @@ from which the ERC20 address is then derived, is known by this wallet.`,
 		&walletSubCmds.createBotNameTransferTxCfg.Sign, "sign", false,
 		"optionally sign the transaction (as sender/receiver) prior to printing it")
 
+	createBotNameSaleOfferTxCmd.Flags().StringVar(
+		&walletSubCmds.createBotNameSaleOfferTxCfg.PayoutAddress, "payout", "",
+		"optionally define the address the price is paid to, by default an address of this wallet is used")
+	createBotNameSaleOfferTxCmd.Flags().Var(
+		cli.NewEncodingTypeFlag(0, &walletSubCmds.createBotNameSaleOfferTxCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
+		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
+	createBotNameSaleOfferTxCmd.Flags().BoolVar(
+		&walletSubCmds.createBotNameSaleOfferTxCfg.Sign, "sign", false,
+		"optionally sign the offer (as sender) prior to printing it")
+
+	createBotNameSaleTxCmd.Flags().Var(
+		cli.NewEncodingTypeFlag(0, &walletSubCmds.createBotNameSaleTxCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
+		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
+	createBotNameSaleTxCmd.Flags().BoolVar(
+		&walletSubCmds.createBotNameSaleTxCfg.Sign, "sign", false,
+		"optionally sign the transaction (as receiver) prior to printing it")
+
+	sendCoinBurnTxCmd.Flags().StringVar(
+		&walletSubCmds.sendCoinBurnTxCfg.Reason, "reason", "",
+		fmt.Sprintf("the (required) reason of the coin burn, stored on-chain, limited to %d bytes", types.MaxCoinBurnReasonLength))
+	sendCoinBurnTxCmd.Flags().Var(
+		cli.NewEncodingTypeFlag(0, &walletSubCmds.sendCoinBurnTxCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
+		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
+
 	sendERC20FundsCmd.Flags().Var(
 		cli.NewEncodingTypeFlag(0, &walletSubCmds.sendERC20FundsCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
 		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
@@ -250,12 +492,26 @@ from which the ERC20 address is then derived, is known by this wallet.`,
 		cli.NewEncodingTypeFlag(0, &walletSubCmds.sendERC20AddressRegistrationCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
 		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
 
+	signBotMessageCmd.Flags().StringVar(
+		&walletSubCmds.signBotMessageCfg.Bot, "bot", "",
+		"the (id|publickey) of the 3bot to sign the message as (required)")
+	signBotMessageCmd.Flags().BoolVar(
+		&walletSubCmds.signBotMessageCfg.Hex, "hex", false,
+		"interpret the message as a hex-encoded byte slice")
+	signBotMessageCmd.Flags().Var(
+		cli.NewEncodingTypeFlag(0, &walletSubCmds.signBotMessageCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
+		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
+
 	listERC20AddressesCmd.Flags().Var(
 		cli.NewEncodingTypeFlag(0, &walletSubCmds.listERC20AddressRegistrationsCfg.EncodingType, cli.EncodingTypeHuman|cli.EncodingTypeJSON), "encoding",
 		cli.EncodingTypeFlagDescription(cli.EncodingTypeHuman|cli.EncodingTypeJSON))
 
 	cli.ArbitraryDataFlagVar(createMinterDefinitionTxCmd.Flags(), &walletSubCmds.minterDefinitionTxCfg.Description,
 		"description", "optionally add a description to describe the reasons of transfer of minting power, added as arbitrary data")
+	cli.ArbitraryDataFlagVar(createMintingCapDefinitionTxCmd.Flags(), &walletSubCmds.mintingCapDefinitionTxCfg.Description,
+		"description", "optionally add a description to describe the reasons of the (re)definition of the minting cap, added as arbitrary data")
+	cli.ArbitraryDataFlagVar(createConsensusParameterUpdateTxCmd.Flags(), &walletSubCmds.consensusParameterUpdateTxCfg.Description,
+		"description", "optionally add a description to describe the reasons of the consensus parameter update, added as arbitrary data")
 	cli.ArbitraryDataFlagVar(createCoinCreationTxCmd.Flags(), &walletSubCmds.coinCreationTxCfg.Description,
 		"description", "optionally add a description to describe the origins of the coin creation, added as arbitrary data")
 }
@@ -265,6 +521,12 @@ type walletSubCmds struct {
 	minterDefinitionTxCfg struct {
 		Description []byte
 	}
+	mintingCapDefinitionTxCfg struct {
+		Description []byte
+	}
+	consensusParameterUpdateTxCfg struct {
+		Description []byte
+	}
 	coinCreationTxCfg struct {
 		Description []byte
 	}
@@ -282,15 +544,45 @@ type walletSubCmds struct {
 		AddressesToRemove []types.NetworkAddress
 		NamesToAdd        []types.BotName
 		NamesToRemove     []types.BotName
+		ServicesToAdd     []types.BotService
+		ServicesToRemove  []types.BotService
 		NrOfMonthsToAdd   uint8
 		EncodingType      cli.EncodingType
 	}
 
+	sendBotKeyRotationTxCfg struct {
+		PublicKey    rivinetypes.PublicKey
+		Owner        string
+		EncodingType cli.EncodingType
+	}
+
+	sendBotNameDelegationTxCfg struct {
+		DelegatesToAdd    []types.BotID
+		DelegatesToRemove []types.BotID
+		EncodingType      cli.EncodingType
+	}
+
 	createBotNameTransferTxCfg struct {
 		EncodingType cli.EncodingType
 		Sign         bool
 	}
 
+	createBotNameSaleOfferTxCfg struct {
+		PayoutAddress string
+		EncodingType  cli.EncodingType
+		Sign          bool
+	}
+
+	createBotNameSaleTxCfg struct {
+		EncodingType cli.EncodingType
+		Sign         bool
+	}
+
+	sendCoinBurnTxCfg struct {
+		Reason       string
+		EncodingType cli.EncodingType
+	}
+
 	sendERC20FundsCfg struct {
 		EncodingType cli.EncodingType
 	}
@@ -303,6 +595,12 @@ type walletSubCmds struct {
 	listERC20AddressRegistrationsCfg struct {
 		EncodingType cli.EncodingType
 	}
+
+	signBotMessageCfg struct {
+		Bot          string
+		Hex          bool
+		EncodingType cli.EncodingType
+	}
 }
 
 func (walletSubCmds *walletSubCmds) createMinterDefinitionTxCmd(cmd *cobra.Command, args []string) {
@@ -334,6 +632,89 @@ func (walletSubCmds *walletSubCmds) createMinterDefinitionTxCmd(cmd *cobra.Comma
 	json.NewEncoder(os.Stdout).Encode(tx.Transaction())
 }
 
+func (walletSubCmds *walletSubCmds) createMintingCapDefinitionTxCmd(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cmd.UsageFunc()(cmd)
+		cli.Die("Invalid amount of arguments. One argument has to be given: <amount>")
+	}
+
+	// create a minting cap definition tx with a random nonce and the minimum required miner fee
+	tx := types.MintingCapDefinitionTransaction{
+		Nonce:     types.RandomTransactionNonce(),
+		MinerFees: []rivinetypes.Currency{walletSubCmds.cli.Config.MinimumTransactionFee},
+	}
+
+	if n := len(walletSubCmds.mintingCapDefinitionTxCfg.Description); n > 0 {
+		tx.ArbitraryData = make([]byte, n)
+		copy(tx.ArbitraryData[:], walletSubCmds.mintingCapDefinitionTxCfg.Description[:])
+	}
+
+	// parse the given minting cap
+	var err error
+	tx.MintingCap, err = walletSubCmds.cli.CreateCurrencyConvertor().ParseCoinString(args[0])
+	if err != nil {
+		cmd.UsageFunc()(cmd)
+		cli.DieWithError("failed to parse the minting cap", err)
+	}
+
+	// encode the transaction as a JSON-encoded string and print it to the STDOUT
+	json.NewEncoder(os.Stdout).Encode(tx.Transaction())
+}
+
+func (walletSubCmds *walletSubCmds) createConsensusParameterUpdateTxCmd(cmd *cobra.Command, args []string) {
+	if len(args) < 3 || len(args)%2 != 1 {
+		cmd.UsageFunc()(cmd)
+		cli.Die("Invalid arguments. Arguments must be of the form <effectiveheight> <name> <value> [<name> <value>]...")
+	}
+
+	// create a consensus parameter update tx with a random nonce and the minimum required miner fee
+	tx := types.ConsensusParameterUpdateTransaction{
+		Nonce:     types.RandomTransactionNonce(),
+		MinerFees: []rivinetypes.Currency{walletSubCmds.cli.Config.MinimumTransactionFee},
+	}
+
+	if n := len(walletSubCmds.consensusParameterUpdateTxCfg.Description); n > 0 {
+		tx.ArbitraryData = make([]byte, n)
+		copy(tx.ArbitraryData[:], walletSubCmds.consensusParameterUpdateTxCfg.Description[:])
+	}
+
+	// parse the effective block height
+	height, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		cmd.UsageFunc()(cmd)
+		cli.DieWithError("failed to parse the effective block height", err)
+	}
+	tx.EffectiveHeight = rivinetypes.BlockHeight(height)
+
+	// parse the remainder as name-value pairs
+	currencyConvertor := walletSubCmds.cli.CreateCurrencyConvertor()
+	var params types.ConsensusParameters
+	for i := 1; i < len(args); i += 2 {
+		update := types.ConsensusParameterUpdate{Name: types.ConsensusParameterName(args[i])}
+		if update.Name.IsBlockHeight() {
+			var n uint64
+			n, err = strconv.ParseUint(args[i+1], 10, 64)
+			update.Value = rivinetypes.NewCurrency64(n)
+		} else {
+			update.Value, err = currencyConvertor.ParseCoinString(args[i+1])
+		}
+		if err != nil {
+			cmd.UsageFunc()(cmd)
+			cli.DieWithError(fmt.Sprintf("failed to parse the value of consensus parameter %s", update.Name), err)
+		}
+		// ensure the parameter is known
+		err = params.Apply(update)
+		if err != nil {
+			cmd.UsageFunc()(cmd)
+			cli.DieWithError("invalid consensus parameter update", err)
+		}
+		tx.Updates = append(tx.Updates, update)
+	}
+
+	// encode the transaction as a JSON-encoded string and print it to the STDOUT
+	json.NewEncoder(os.Stdout).Encode(tx.Transaction())
+}
+
 func (walletSubCmds *walletSubCmds) createCoinCreationTxCmd(cmd *cobra.Command, args []string) {
 	currencyConvertor := walletSubCmds.cli.CreateCurrencyConvertor()
 
@@ -404,7 +785,11 @@ func (walletSubCmds *walletSubCmds) sendBotRegistrationTxCmd() {
 		},
 	}
 	// compute the additional (bot) fee, such that we can fund it all
-	fee := tx.RequiredBotFee(walletSubCmds.cli.Config.CurrencyUnits.OneCoin)
+	params, err := internal.NewTransactionDBConsensusClient(walletSubCmds.cli).GetActiveConsensusParameters()
+	if err != nil {
+		cli.DieWithError("failed to get the active consensus parameters", err)
+	}
+	fee := tx.RequiredBotFee(params)
 	// fund the coin inputs
 	tx.CoinInputs, tx.RefundCoinOutput, err = walletClient.FundCoins(fee.Add(walletSubCmds.cli.Config.MinimumTransactionFee))
 	if err != nil {
@@ -459,6 +844,13 @@ func (walletSubCmds *walletSubCmds) sendBotRecordUpdateTxCmd(str string) {
 	// start the record update process
 	walletClient := internal.NewWalletClient(walletSubCmds.cli)
 
+	// look up the record, as a bot owned by a condition requires a fulfillment-based update
+	record, err := internal.NewTransactionDBConsensusClient(walletSubCmds.cli).GetRecordForID(id)
+	if err != nil {
+		cli.DieWithError("failed to fetch the bot record", err)
+		return
+	}
+
 	// create the record update Tx
 	tx := types.BotRecordUpdateTransaction{
 		Identifier: id,
@@ -470,11 +862,19 @@ func (walletSubCmds *walletSubCmds) sendBotRecordUpdateTxCmd(str string) {
 			Add:    walletSubCmds.sendBotRecordUpdateTxCfg.NamesToAdd,
 			Remove: walletSubCmds.sendBotRecordUpdateTxCfg.NamesToRemove,
 		},
+		Services: types.BotRecordServiceUpdate{
+			Add:    walletSubCmds.sendBotRecordUpdateTxCfg.ServicesToAdd,
+			Remove: walletSubCmds.sendBotRecordUpdateTxCfg.ServicesToRemove,
+		},
 		NrOfMonths:     walletSubCmds.sendBotRecordUpdateTxCfg.NrOfMonthsToAdd,
 		TransactionFee: walletSubCmds.cli.Config.MinimumTransactionFee,
 	}
 	// compute the additional (bot) fee, such that we can fund it all
-	fee := tx.RequiredBotFee(walletSubCmds.cli.Config.CurrencyUnits.OneCoin)
+	params, err := internal.NewTransactionDBConsensusClient(walletSubCmds.cli).GetActiveConsensusParameters()
+	if err != nil {
+		cli.DieWithError("failed to get the active consensus parameters", err)
+	}
+	fee := tx.RequiredBotFee(params)
 	// fund the coin inputs
 	tx.CoinInputs, tx.RefundCoinOutput, err = walletClient.FundCoins(fee.Add(walletSubCmds.cli.Config.MinimumTransactionFee))
 	if err != nil {
@@ -483,7 +883,7 @@ func (walletSubCmds *walletSubCmds) sendBotRecordUpdateTxCmd(str string) {
 	}
 
 	// sign the Tx
-	rtx := tx.Transaction(walletSubCmds.cli.Config.CurrencyUnits.OneCoin)
+	rtx := botRecordUpdateTransaction(tx, record, walletSubCmds.cli.Config.CurrencyUnits.OneCoin)
 	err = walletClient.GreedySignTx(&rtx)
 	if err != nil {
 		cli.DieWithError("failed to sign the bot record update Tx", err)
@@ -518,6 +918,188 @@ func (walletSubCmds *walletSubCmds) sendBotRecordUpdateTxCmd(str string) {
 	}
 }
 
+// botRecordUpdateTransaction returns the given record update as an (unsigned) transaction,
+// using the transaction version required to apply the update to the given record.
+func botRecordUpdateTransaction(tx types.BotRecordUpdateTransaction, record *types.BotRecord, oneCoin rivinetypes.Currency) rivinetypes.Transaction {
+	if len(tx.Services.Add) > 0 || len(tx.Services.Remove) > 0 {
+		// services can only be updated using the (fulfillment-based) Tx version that supports them
+		return (&types.BotRecordUpdateWithServicesTransaction{
+			Identifier:       tx.Identifier,
+			Addresses:        tx.Addresses,
+			Names:            tx.Names,
+			Services:         tx.Services,
+			NrOfMonths:       tx.NrOfMonths,
+			TransactionFee:   tx.TransactionFee,
+			CoinInputs:       tx.CoinInputs,
+			RefundCoinOutput: tx.RefundCoinOutput,
+		}).Transaction(oneCoin)
+	}
+	if record.Owner != nil {
+		return (&types.BotRecordUpdateWithFulfillmentTransaction{
+			Identifier:       tx.Identifier,
+			Addresses:        tx.Addresses,
+			Names:            tx.Names,
+			NrOfMonths:       tx.NrOfMonths,
+			TransactionFee:   tx.TransactionFee,
+			CoinInputs:       tx.CoinInputs,
+			RefundCoinOutput: tx.RefundCoinOutput,
+		}).Transaction(oneCoin)
+	}
+	return tx.Transaction(oneCoin)
+}
+
+func (walletSubCmds *walletSubCmds) sendBotKeyRotationTxCmd(str string) {
+	id, err := walletSubCmds.botIDFromPosArgStr(str)
+	if err != nil {
+		cli.DieWithError("failed to parse/fetch unique ID", err)
+		return
+	}
+
+	// start the key rotation process
+	walletClient := internal.NewWalletClient(walletSubCmds.cli)
+
+	pk := walletSubCmds.sendBotKeyRotationTxCfg.PublicKey
+	if pk.Algorithm == 0 && len(pk.Key) == 0 {
+		pk, err = walletClient.NewPublicKey()
+		if err != nil {
+			cli.DieWithError("failed to generate new public key", err)
+			return
+		}
+	}
+
+	// create the key rotation Tx
+	tx := types.BotKeyRotationTransaction{
+		Identifier: id,
+		NewIdentification: types.PublicKeySignaturePair{
+			PublicKey: pk,
+		},
+		TransactionFee: walletSubCmds.cli.Config.MinimumTransactionFee,
+	}
+	// optionally hand over the ownership to a condition
+	if str := walletSubCmds.sendBotKeyRotationTxCfg.Owner; str != "" {
+		owner, err := parseConditionString(str)
+		if err != nil {
+			cli.DieWithError("failed to parse the new owner of the bot", err)
+			return
+		}
+		tx.Owner = &owner
+	}
+	// fund the coin inputs, only the regular Tx fee is required
+	tx.CoinInputs, tx.RefundCoinOutput, err = walletClient.FundCoins(walletSubCmds.cli.Config.MinimumTransactionFee)
+	if err != nil {
+		cli.DieWithError("failed to fund the bot key rotation Tx", err)
+		return
+	}
+
+	// sign the Tx, using both the current owner and new key
+	rtx := tx.Transaction()
+	err = walletClient.GreedySignTx(&rtx)
+	if err != nil {
+		cli.DieWithError("failed to sign the bot key rotation Tx", err)
+		return
+	}
+
+	// submit the Tx
+	txPoolClient := internal.NewTransactionPoolClient(walletSubCmds.cli)
+	txID, err := txPoolClient.AddTransactiom(rtx)
+	if err != nil {
+		b, _ := json.Marshal(rtx)
+		fmt.Fprintln(os.Stderr, "bad tx: "+string(b))
+		cli.DieWithError("failed to submit the bot key rotation Tx to the Tx Pool", err)
+		return
+	}
+
+	// encode depending on the encoding flag
+	var encode func(interface{}) error
+	switch walletSubCmds.sendBotKeyRotationTxCfg.EncodingType {
+	case cli.EncodingTypeHuman:
+		e := json.NewEncoder(os.Stdout)
+		e.SetIndent("", "  ")
+		encode = e.Encode
+	case cli.EncodingTypeJSON:
+		encode = json.NewEncoder(os.Stdout).Encode
+	}
+	err = encode(map[string]interface{}{
+		"publickey":     pk,
+		"transactionid": txID,
+	})
+	if err != nil {
+		cli.DieWithError("failed to encode result", err)
+	}
+}
+
+// send botnamedelegation (publickey|id) name
+// arguments in order: owner and the name of which the delegates are updated
+func (walletSubCmds *walletSubCmds) sendBotNameDelegationTxCmd(cmd *cobra.Command, args []string) {
+	id, err := walletSubCmds.botIDFromPosArgStr(args[0])
+	if err != nil {
+		cli.DieWithError("failed to parse/fetch unique ID", err)
+		return
+	}
+	var name types.BotName
+	err = name.LoadString(args[1])
+	if err != nil {
+		cli.DieWithError("failed to parse (pos arg) bot name", err)
+		return
+	}
+
+	walletClient := internal.NewWalletClient(walletSubCmds.cli)
+
+	// create the bot name delegation Tx
+	tx := types.BotNameDelegationTransaction{
+		Owner: types.BotIdentifierFulfillmentPair{
+			Identifier: id,
+		},
+		Name: name,
+		Delegates: types.BotNameDelegatesUpdate{
+			Add:    walletSubCmds.sendBotNameDelegationTxCfg.DelegatesToAdd,
+			Remove: walletSubCmds.sendBotNameDelegationTxCfg.DelegatesToRemove,
+		},
+		TransactionFee: walletSubCmds.cli.Config.MinimumTransactionFee,
+	}
+	// fund the coin inputs, only the regular Tx fee is required
+	tx.CoinInputs, tx.RefundCoinOutput, err = walletClient.FundCoins(walletSubCmds.cli.Config.MinimumTransactionFee)
+	if err != nil {
+		cli.DieWithError("failed to fund the bot name delegation Tx", err)
+		return
+	}
+
+	// sign the Tx
+	rtx := tx.Transaction()
+	err = walletClient.GreedySignTx(&rtx)
+	if err != nil {
+		cli.DieWithError("failed to sign the bot name delegation Tx", err)
+		return
+	}
+
+	// submit the Tx
+	txPoolClient := internal.NewTransactionPoolClient(walletSubCmds.cli)
+	txID, err := txPoolClient.AddTransactiom(rtx)
+	if err != nil {
+		b, _ := json.Marshal(rtx)
+		fmt.Fprintln(os.Stderr, "bad tx: "+string(b))
+		cli.DieWithError("failed to submit the bot name delegation Tx to the Tx Pool", err)
+		return
+	}
+
+	// encode depending on the encoding flag
+	var encode func(interface{}) error
+	switch walletSubCmds.sendBotNameDelegationTxCfg.EncodingType {
+	case cli.EncodingTypeHuman:
+		e := json.NewEncoder(os.Stdout)
+		e.SetIndent("", "  ")
+		encode = e.Encode
+	case cli.EncodingTypeJSON:
+		encode = json.NewEncoder(os.Stdout).Encode
+	}
+	err = encode(map[string]interface{}{
+		"transactionid": txID,
+	})
+	if err != nil {
+		cli.DieWithError("failed to encode result", err)
+	}
+}
+
 // create botnametransfer (publickey|id) (publickey|id) names...
 // arguments in order: sender, receiver and a slice of names (at least one name is required),
 // hence this command requires a minimum of 3 arguments
@@ -557,7 +1139,11 @@ func (walletSubCmds *walletSubCmds) createBotNameTransferTxCmd(cmd *cobra.Comman
 		TransactionFee: walletSubCmds.cli.Config.MinimumTransactionFee,
 	}
 	// compute the additional (bot) fee, such that we can fund it all
-	fee := tx.RequiredBotFee(walletSubCmds.cli.Config.CurrencyUnits.OneCoin)
+	params, err := internal.NewTransactionDBConsensusClient(walletSubCmds.cli).GetActiveConsensusParameters()
+	if err != nil {
+		cli.DieWithError("failed to get the active consensus parameters", err)
+	}
+	fee := tx.RequiredBotFee(params)
 	// fund the coin inputs
 	tx.CoinInputs, tx.RefundCoinOutput, err = walletClient.FundCoins(fee.Add(walletSubCmds.cli.Config.MinimumTransactionFee))
 	if err != nil {
@@ -565,7 +1151,30 @@ func (walletSubCmds *walletSubCmds) createBotNameTransferTxCmd(cmd *cobra.Comman
 		return
 	}
 
-	rtx := tx.Transaction(walletSubCmds.cli.Config.CurrencyUnits.OneCoin)
+	// bots owned by a condition require a fulfillment-based name transfer
+	var ownedByCondition bool
+	txDB := internal.NewTransactionDBConsensusClient(walletSubCmds.cli)
+	for _, id := range []types.BotID{senderID, receiverID} {
+		record, err := txDB.GetRecordForID(id)
+		if err != nil {
+			cli.DieWithError(fmt.Sprintf("failed to fetch the record of bot %d", id), err)
+			return
+		}
+		ownedByCondition = ownedByCondition || record.Owner != nil
+	}
+	var rtx rivinetypes.Transaction
+	if ownedByCondition {
+		rtx = (&types.BotNameTransferWithFulfillmentTransaction{
+			Sender:           types.BotIdentifierFulfillmentPair{Identifier: senderID},
+			Receiver:         types.BotIdentifierFulfillmentPair{Identifier: receiverID},
+			Names:            tx.Names,
+			TransactionFee:   tx.TransactionFee,
+			CoinInputs:       tx.CoinInputs,
+			RefundCoinOutput: tx.RefundCoinOutput,
+		}).Transaction(walletSubCmds.cli.Config.CurrencyUnits.OneCoin)
+	} else {
+		rtx = tx.Transaction(walletSubCmds.cli.Config.CurrencyUnits.OneCoin)
+	}
 
 	if walletSubCmds.createBotNameTransferTxCfg.Sign {
 		// optionally sign the Tx
@@ -592,6 +1201,240 @@ func (walletSubCmds *walletSubCmds) createBotNameTransferTxCmd(cmd *cobra.Comman
 	}
 }
 
+// create botnamesaleoffer (publickey|id) price expirationheight names...
+// arguments in order: sender, price, expiration height and a slice of names (at least one name is required),
+// hence this command requires a minimum of 4 arguments
+func (walletSubCmds *walletSubCmds) createBotNameSaleOfferTxCmd(cmd *cobra.Command, args []string) {
+	senderID, err := walletSubCmds.botIDFromPosArgStr(args[0])
+	if err != nil {
+		cli.DieWithError("failed to parse/fetch unique (sender bot) ID", err)
+		return
+	}
+	price, err := walletSubCmds.cli.CreateCurrencyConvertor().ParseCoinString(args[1])
+	if err != nil {
+		cli.DieWithError("failed to parse price", err)
+		return
+	}
+	expirationHeight, err := strconv.ParseUint(args[2], 10, 64)
+	if err != nil {
+		cli.DieWithError("failed to parse expiration height", err)
+		return
+	}
+
+	walletClient := internal.NewWalletClient(walletSubCmds.cli)
+
+	names := make([]types.BotName, len(args[3:]))
+	for idx, str := range args[3:] {
+		err = names[idx].LoadString(str)
+		if err != nil {
+			cli.DieWithError("failed to parse (pos arg) bot name #"+strconv.Itoa(idx+1), err)
+			return
+		}
+	}
+
+	// define the address the price is paid to
+	var payoutAddress rivinetypes.UnlockHash
+	if walletSubCmds.createBotNameSaleOfferTxCfg.PayoutAddress != "" {
+		err = payoutAddress.LoadString(walletSubCmds.createBotNameSaleOfferTxCfg.PayoutAddress)
+		if err != nil {
+			cli.DieWithError("failed to parse payout address", err)
+			return
+		}
+	} else {
+		pk, err := walletClient.NewPublicKey()
+		if err != nil {
+			cli.DieWithError("failed to generate new public key", err)
+			return
+		}
+		payoutAddress = rivinetypes.NewPubKeyUnlockHash(pk)
+	}
+
+	// the offer is bound to the current version of the record of the sender
+	txIDs, err := internal.NewTransactionDBConsensusClient(walletSubCmds.cli).GetBotTransactionIdentifiers(senderID)
+	if err != nil {
+		cli.DieWithError("failed to fetch the transactions of the sender bot", err)
+		return
+	}
+	if len(txIDs) == 0 {
+		cli.DieWithError("failed to fetch the transactions of the sender bot", errors.New("no transactions found"))
+		return
+	}
+
+	// create the bot name sale Tx, without receiver or coin inputs,
+	// as these are only defined by the receiver completing the offer
+	tx := types.BotNameSaleTransaction{
+		Offer: types.BotNameSaleOffer{
+			Sender: types.BotIdentifierFulfillmentPair{
+				Identifier: senderID,
+			},
+			Names:            names,
+			Price:            price,
+			PayoutAddress:    payoutAddress,
+			ExpirationHeight: rivinetypes.BlockHeight(expirationHeight),
+			SenderVersion:    txIDs[len(txIDs)-1],
+		},
+		TransactionFee: walletSubCmds.cli.Config.MinimumTransactionFee,
+	}
+	rtx := tx.Transaction(walletSubCmds.cli.Config.CurrencyUnits.OneCoin)
+
+	if walletSubCmds.createBotNameSaleOfferTxCfg.Sign {
+		// optionally sign the offer
+		err = walletClient.GreedySignTx(&rtx)
+		if err != nil {
+			cli.DieWithError("failed to sign the bot name sale offer", err)
+			return
+		}
+	}
+
+	// encode depending on the encoding flag
+	var encode func(interface{}) error
+	switch walletSubCmds.createBotNameSaleOfferTxCfg.EncodingType {
+	case cli.EncodingTypeHuman:
+		e := json.NewEncoder(os.Stdout)
+		e.SetIndent("", "  ")
+		encode = e.Encode
+	case cli.EncodingTypeJSON:
+		encode = json.NewEncoder(os.Stdout).Encode
+	}
+	err = encode(rtx)
+	if err != nil {
+		cli.DieWithError("failed to encode result", err)
+	}
+}
+
+// create botnamesale (publickey|id) offer
+// arguments in order: receiver and the (JSON-encoded) offer
+func (walletSubCmds *walletSubCmds) createBotNameSaleTxCmd(cmd *cobra.Command, args []string) {
+	receiverID, err := walletSubCmds.botIDFromPosArgStr(args[0])
+	if err != nil {
+		cli.DieWithError("failed to parse/fetch unique (receiver bot) ID", err)
+		return
+	}
+	var offerTx rivinetypes.Transaction
+	err = offerTx.UnmarshalJSON([]byte(args[1]))
+	if err != nil {
+		cli.DieWithError("failed to decode the bot name sale offer", err)
+		return
+	}
+	tx, err := types.BotNameSaleTransactionFromTransaction(offerTx)
+	if err != nil {
+		cli.DieWithError("invalid bot name sale offer", err)
+		return
+	}
+
+	walletClient := internal.NewWalletClient(walletSubCmds.cli)
+
+	// complete the offer as the receiver
+	tx.Receiver = types.BotIdentifierFulfillmentPair{
+		Identifier: receiverID,
+	}
+	tx.TransactionFee = walletSubCmds.cli.Config.MinimumTransactionFee
+	// compute the additional (bot) fee, such that we can fund it all, together with the price
+	params, err := internal.NewTransactionDBConsensusClient(walletSubCmds.cli).GetActiveConsensusParameters()
+	if err != nil {
+		cli.DieWithError("failed to get the active consensus parameters", err)
+	}
+	fee := tx.RequiredBotFee(params)
+	// fund the coin inputs
+	tx.CoinInputs, tx.RefundCoinOutput, err = walletClient.FundCoins(
+		tx.Offer.Price.Add(fee).Add(walletSubCmds.cli.Config.MinimumTransactionFee))
+	if err != nil {
+		cli.DieWithError("failed to fund the bot name sale Tx", err)
+		return
+	}
+	rtx := tx.Transaction(walletSubCmds.cli.Config.CurrencyUnits.OneCoin)
+
+	if walletSubCmds.createBotNameSaleTxCfg.Sign {
+		// optionally sign the Tx
+		err = walletClient.GreedySignTx(&rtx)
+		if err != nil {
+			cli.DieWithError("failed to sign the bot name sale Tx", err)
+			return
+		}
+	}
+
+	// encode depending on the encoding flag
+	var encode func(interface{}) error
+	switch walletSubCmds.createBotNameSaleTxCfg.EncodingType {
+	case cli.EncodingTypeHuman:
+		e := json.NewEncoder(os.Stdout)
+		e.SetIndent("", "  ")
+		encode = e.Encode
+	case cli.EncodingTypeJSON:
+		encode = json.NewEncoder(os.Stdout).Encode
+	}
+	err = encode(rtx)
+	if err != nil {
+		cli.DieWithError("failed to encode result", err)
+	}
+}
+
+func (walletSubCmds *walletSubCmds) sendCoinBurnTxCmd(strAmount string) {
+	// validate the reason
+	reason := walletSubCmds.sendCoinBurnTxCfg.Reason
+	if reason == "" {
+		cli.Die("a reason is required in order to burn coins")
+		return
+	}
+	if len(reason) > types.MaxCoinBurnReasonLength {
+		cli.Die(fmt.Sprintf("the reason cannot be longer than %d bytes", types.MaxCoinBurnReasonLength))
+		return
+	}
+	// load amount (in TFT)
+	currencyConvertor := walletSubCmds.cli.CreateCurrencyConvertor()
+	amount, err := currencyConvertor.ParseCoinString(strAmount)
+	if err != nil {
+		cli.DieWithError("failed to parse coin (TFT) string", err)
+		return
+	}
+
+	walletClient := internal.NewWalletClient(walletSubCmds.cli)
+
+	// create the Coin Burn Tx
+	tx := types.CoinBurnTransaction{
+		Value:          amount,
+		Reason:         []byte(reason),
+		TransactionFee: walletSubCmds.cli.Config.MinimumTransactionFee,
+	}
+	// fund the coin inputs
+	tx.CoinInputs, tx.RefundCoinOutput, err = walletClient.FundCoins(tx.TransactionFee.Add(tx.Value))
+	if err != nil {
+		cli.DieWithError("failed to fund the Coin Burn Tx", err)
+		return
+	}
+
+	// sign the Tx
+	rtx := tx.Transaction()
+	err = walletClient.GreedySignTx(&rtx)
+	if err != nil {
+		cli.DieWithError("failed to sign the Coin Burn Tx", err)
+		return
+	}
+
+	// submit the Tx
+	txPoolClient := internal.NewTransactionPoolClient(walletSubCmds.cli)
+	txID, err := txPoolClient.AddTransactiom(rtx)
+	if err != nil {
+		b, _ := json.Marshal(rtx)
+		fmt.Fprintln(os.Stderr, "bad tx: "+string(b))
+		cli.DieWithError("failed to submit the Coin Burn Tx to the Tx Pool", err)
+		return
+	}
+
+	// encode depending on the encoding flag
+	switch walletSubCmds.sendCoinBurnTxCfg.EncodingType {
+	case cli.EncodingTypeHuman:
+		fmt.Println("Transaction ID:", txID)
+	case cli.EncodingTypeJSON:
+		err = json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+			"transactionid": txID,
+		})
+		if err != nil {
+			cli.DieWithError("failed to encode result", err)
+		}
+	}
+}
+
 func (walletSubCmds *walletSubCmds) sendERC20Funds(hexAddress, strAmount string) {
 	// load ERC20 address
 	var address types.ERC20Address
@@ -749,8 +1592,13 @@ func (walletSubCmds *walletSubCmds) sendERC20AddressRegistration(_ *cobra.Comman
 		return
 	}
 
-	// compute the hardcoded Tx fee
-	regFee := walletSubCmds.cli.Config.CurrencyUnits.OneCoin.Mul64(types.HardcodedERC20AddressRegistrationFeeOneCoinMultiplier)
+	// get the registration fee, as defined by the active consensus parameters
+	params, err := internal.NewTransactionDBConsensusClient(walletSubCmds.cli).GetActiveConsensusParameters()
+	if err != nil {
+		cli.DieWithError("failed to get the active consensus parameters", err)
+		return
+	}
+	regFee := params.ERC20AddressRegistrationFee
 
 	// create the ERC20 Address Registration Tx
 	tx := types.ERC20AddressRegistrationTransaction{
@@ -760,7 +1608,6 @@ func (walletSubCmds *walletSubCmds) sendERC20AddressRegistration(_ *cobra.Comman
 		TransactionFee:  walletSubCmds.cli.Config.MinimumTransactionFee,
 	}
 	// fund the coin inputs
-	var err error
 	tx.CoinInputs, tx.RefundCoinOutput, err = walletClient.FundCoins(tx.TransactionFee.Add(regFee))
 	if err != nil {
 		cli.DieWithError("failed to fund the ERC20 Address Registration Tx", err)
@@ -847,6 +1694,45 @@ func (walletSubCmds *walletSubCmds) listERC20AddressRegistrations(_ *cobra.Comma
 	}
 }
 
+func (walletSubCmds *walletSubCmds) signBotMessage(str string) {
+	if walletSubCmds.signBotMessageCfg.Bot == "" {
+		cli.Die("the 3bot to sign the message as has to be defined using the --bot flag")
+	}
+	id, err := walletSubCmds.botIDFromPosArgStr(walletSubCmds.signBotMessageCfg.Bot)
+	if err != nil {
+		cli.DieWithError("failed to parse/fetch unique ID of the 3bot", err)
+	}
+	message, err := botMessageFromString(str, walletSubCmds.signBotMessageCfg.Hex)
+	if err != nil {
+		cli.DieWithError("failed to parse the message", err)
+	}
+
+	walletClient := internal.NewWalletClient(walletSubCmds.cli)
+	result, err := walletClient.SignBotMessage(id, message)
+	if err != nil {
+		cli.DieWithError("failed to sign the message", err)
+	}
+
+	switch walletSubCmds.signBotMessageCfg.EncodingType {
+	case cli.EncodingTypeHuman:
+		fmt.Println(result.Signature.String())
+	case cli.EncodingTypeJSON:
+		err = json.NewEncoder(os.Stdout).Encode(result)
+		if err != nil {
+			cli.DieWithError("failed to encode result", err)
+		}
+	}
+}
+
+// botMessageFromString returns the given string as a message to be signed (or verified) as a 3bot,
+// decoding it first if it is a hex-encoded byte slice.
+func botMessageFromString(str string, isHex bool) ([]byte, error) {
+	if !isHex {
+		return []byte(str), nil
+	}
+	return hex.DecodeString(str)
+}
+
 func (walletSubCmds *walletSubCmds) botIDFromPosArgStr(str string) (types.BotID, error) {
 	if len(str) < 16 {
 		// assume bot ID if the less than 16, seems to short for a public key,
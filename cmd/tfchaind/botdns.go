@@ -0,0 +1,70 @@
+package main
+
+import (
+	flag "github.com/spf13/pflag"
+
+	"github.com/threefoldfoundation/tfchain/pkg/botdns"
+)
+
+// BotDNSConfig is all info required to (optionally) serve the 3bot DNS zone.
+// The DNS server is only started if an address is defined.
+type BotDNSConfig struct {
+	Address    string
+	Zone       string
+	TTL        uint32
+	Nameserver string
+	Mailbox    string
+}
+
+// SetFlags defines the BotDNSConfig as flags.
+func (cfg *BotDNSConfig) SetFlags(flags *flag.FlagSet) {
+	flags.StringVar(
+		&cfg.Address,
+		"dns-addr", "",
+		"serve the names of active 3bots as an authoritative DNS zone on this (UDP and TCP) address, disabled if not defined",
+	)
+	flags.StringVar(
+		&cfg.Zone,
+		"dns-zone", botdns.DefaultZone,
+		"the DNS zone in which the names of active 3bots are served",
+	)
+	flags.Uint32Var(
+		&cfg.TTL,
+		"dns-ttl", botdns.DefaultTTL,
+		"the TTL (in seconds) of all served 3bot DNS records",
+	)
+	flags.StringVar(
+		&cfg.Nameserver,
+		"dns-nameserver", "",
+		"the primary nameserver defined in the SOA record of the 3bot DNS zone, defaults to ns.<zone>",
+	)
+	flags.StringVar(
+		&cfg.Mailbox,
+		"dns-mailbox", "",
+		"the mailbox (as a DNS name) defined in the SOA record of the 3bot DNS zone, defaults to hostmaster.<zone>",
+	)
+}
+
+// Enabled returns true if the 3bot DNS server is to be served.
+func (cfg *BotDNSConfig) Enabled() bool {
+	return cfg.Address != ""
+}
+
+// setupBotDNSServer creates and starts the 3bot DNS server,
+// resolving names using the given registry.
+func setupBotDNSServer(cfg BotDNSConfig, registry botdns.Registry) (*botdns.Server, error) {
+	srv, err := botdns.NewServer(registry, botdns.Config{
+		Zone:       cfg.Zone,
+		TTL:        cfg.TTL,
+		Nameserver: cfg.Nameserver,
+		Mailbox:    cfg.Mailbox,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = srv.ListenAndServe(cfg.Address)
+	if err != nil {
+		return nil, err
+	}
+	return srv, nil
+}
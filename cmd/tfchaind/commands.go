@@ -20,6 +20,7 @@ type commands struct {
 	moduleSetFlag daemon.ModuleSetFlag
 
 	erc20Cfg ERC20NodeValidatorConfig
+	dnsCfg   BotDNSConfig
 }
 
 func (cmds *commands) rootCommand(*cobra.Command, []string) {
@@ -51,7 +52,7 @@ func (cmds *commands) rootCommand(*cobra.Command, []string) {
 	cmds.cfg.Config = daemon.ProcessConfig(cmds.cfg.Config)
 
 	// run daemon
-	err = runDaemon(cmds.cfg, cmds.moduleSetFlag.ModuleIdentifiers(), cmds.erc20Cfg)
+	err = runDaemon(cmds.cfg, cmds.moduleSetFlag.ModuleIdentifiers(), cmds.erc20Cfg, cmds.dnsCfg)
 	if err != nil {
 		cli.DieWithError("daemon failed", err)
 	}
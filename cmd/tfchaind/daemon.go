@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/signal"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/threefoldfoundation/tfchain/pkg/api"
 	"github.com/threefoldfoundation/tfchain/pkg/config"
+	"github.com/threefoldfoundation/tfchain/pkg/metrics"
 	"github.com/threefoldfoundation/tfchain/pkg/persist"
 
 	tfchaintypes "github.com/threefoldfoundation/tfchain/pkg/types"
@@ -29,7 +31,13 @@ import (
 	"github.com/threefoldtech/rivine/pkg/daemon"
 )
 
-func runDaemon(cfg ExtendedDaemonConfig, moduleIdentifiers daemon.ModuleIdentifierSet, erc20Cfg ERC20NodeValidatorConfig) error {
+// metricsRegisterer is implemented by the modules which expose metrics,
+// and for which the implementation is optional (e.g. the ERC20 Transaction validator)
+type metricsRegisterer interface {
+	RegisterMetrics(reg *metrics.Registry) error
+}
+
+func runDaemon(cfg ExtendedDaemonConfig, moduleIdentifiers daemon.ModuleIdentifierSet, erc20Cfg ERC20NodeValidatorConfig, dnsCfg BotDNSConfig) error {
 	// Print a startup message.
 	fmt.Println("Loading...")
 	loadStart := time.Now()
@@ -49,6 +57,9 @@ func runDaemon(cfg ExtendedDaemonConfig, moduleIdentifiers daemon.ModuleIdentifi
 	if err != nil {
 		return err
 	}
+	// expose our metrics, without requiring a user agent,
+	// such that they can be scraped by a Prometheus server
+	srv.Handle("/metrics", metrics.Handler())
 	servErrs := make(chan error, 32)
 	go func() {
 		servErrs <- srv.Serve()
@@ -72,7 +83,24 @@ func runDaemon(cfg ExtendedDaemonConfig, moduleIdentifiers daemon.ModuleIdentifi
 			cancel()
 			return
 		}
+		if c, ok := erc20TxValidator.(io.Closer); ok {
+			defer func() {
+				fmt.Println("Closing ERC20 Transaction validator...")
+				err := c.Close()
+				if err != nil {
+					fmt.Println("Error during ERC20 Transaction validator shutdown:", err)
+				}
+			}()
+		}
 		api.RegisterERC20HTTPHandlers(router, erc20TxValidator)
+		if mr, ok := erc20TxValidator.(metricsRegisterer); ok {
+			err = mr.RegisterMetrics(metrics.DefaultRegistry)
+			if err != nil {
+				servErrs <- fmt.Errorf("failed to register ERC20 Transaction validator metrics: %v", err)
+				cancel()
+				return
+			}
+		}
 
 		// create and validate network config, and the transactionDB as well
 		// txdb is on index 0, as it is not manually loaded
@@ -97,6 +125,31 @@ func runDaemon(cfg ExtendedDaemonConfig, moduleIdentifiers daemon.ModuleIdentifi
 			return
 		}
 		api.RegisterTransactionDBHTTPHandlers(router, txdb)
+		err = txdb.RegisterMetrics(metrics.DefaultRegistry)
+		if err != nil {
+			servErrs <- fmt.Errorf("failed to register transaction db metrics: %v", err)
+			cancel()
+			return
+		}
+
+		// optionally serve the names of all active 3bots over DNS,
+		// resolved using the transactionDB, such that answers are updated as blocks are applied or reverted
+		if dnsCfg.Enabled() {
+			fmt.Println("Binding DNS Address and serving the 3bot DNS zone...")
+			dnsSrv, err := setupBotDNSServer(dnsCfg, txdb)
+			if err != nil {
+				servErrs <- fmt.Errorf("failed to serve the 3bot DNS zone: %v", err)
+				cancel()
+				return
+			}
+			defer func() {
+				fmt.Println("Closing 3bot DNS server...")
+				err := dnsSrv.Close()
+				if err != nil {
+					fmt.Println("Error during 3bot DNS server shutdown:", err)
+				}
+			}()
+		}
 
 		// Initialize the Rivine modules
 		var g modules.Gateway
@@ -178,7 +231,7 @@ func runDaemon(cfg ExtendedDaemonConfig, moduleIdentifiers daemon.ModuleIdentifi
 				return
 			}
 			rivineapi.RegisterWalletHTTPHandlers(router, w, cfg.APIPassword)
-			api.RegisterWalletHTTPHandlers(router, w, cfg.APIPassword)
+			api.RegisterWalletHTTPHandlers(router, w, txdb, cfg.APIPassword)
 			defer func() {
 				fmt.Println("Closing wallet...")
 				err := w.Close()
@@ -314,7 +367,7 @@ func setupNetwork(cfg ExtendedDaemonConfig, erc20TxValidator tfchaintypes.ERC20T
 	// which includes the genesis block as well as the bootstrap peers
 	switch cfg.BlockchainInfo.NetworkName {
 	case config.NetworkNameStandard:
-		txdb, err := persist.NewTransactionDB(cfg.RootPersistentDir, config.GetStandardnetGenesisMintCondition())
+		txdb, err := persist.NewTransactionDB(cfg.RootPersistentDir, config.GetStandardnetGenesisMintCondition(), tfchaintypes.GetStandardnetGenesisConsensusParameters())
 		if err != nil {
 			return daemon.NetworkConfig{}, nil, err
 		}
@@ -338,7 +391,7 @@ func setupNetwork(cfg ExtendedDaemonConfig, erc20TxValidator tfchaintypes.ERC20T
 		}, txdb, nil
 
 	case config.NetworkNameTest:
-		txdb, err := persist.NewTransactionDB(cfg.RootPersistentDir, config.GetTestnetGenesisMintCondition())
+		txdb, err := persist.NewTransactionDB(cfg.RootPersistentDir, config.GetTestnetGenesisMintCondition(), tfchaintypes.GetTestnetGenesisConsensusParameters())
 		if err != nil {
 			return daemon.NetworkConfig{}, nil, err
 		}
@@ -362,7 +415,7 @@ func setupNetwork(cfg ExtendedDaemonConfig, erc20TxValidator tfchaintypes.ERC20T
 		}, txdb, nil
 
 	case config.NetworkNameDev:
-		txdb, err := persist.NewTransactionDB(cfg.RootPersistentDir, config.GetDevnetGenesisMintCondition())
+		txdb, err := persist.NewTransactionDB(cfg.RootPersistentDir, config.GetDevnetGenesisMintCondition(), tfchaintypes.GetDevnetGenesisConsensusParameters())
 		if err != nil {
 			return daemon.NetworkConfig{}, nil, err
 		}
@@ -16,6 +16,7 @@ import (
 	tfeth "github.com/threefoldfoundation/tfchain/pkg/eth"
 	"github.com/threefoldfoundation/tfchain/pkg/eth/erc20"
 	"github.com/threefoldfoundation/tfchain/pkg/eth/erc20/contract"
+	"github.com/threefoldfoundation/tfchain/pkg/metrics"
 	tftypes "github.com/threefoldfoundation/tfchain/pkg/types"
 	"github.com/threefoldtech/rivine/build"
 
@@ -32,9 +33,10 @@ const (
 // getting the transactions using the LES/v2 protocol, see the
 // `github.com/threefoldfoundation/tfchain/pkg/eth` for more info.
 type ERC20NodeValidator struct {
-	contract *erc20.BridgeContract
-	lc       *erc20.LightClient
-	abi      abi.ABI
+	contract  *erc20.BridgeContract
+	withdraws *erc20.WithdrawIndex
+	lc        *erc20.LightClient
+	abi       abi.ABI
 }
 
 // NewERC20NodeValidator creates a new INFURA-based ERC20NodeValidator.
@@ -94,51 +96,54 @@ func NewERC20NodeValidator(cfg ERC20NodeValidatorConfig, cancel <-chan struct{})
 	if err != nil {
 		return nil, err
 	}
+	withdraws, err := erc20.NewWithdrawIndex(contract, cfg.DataDir)
+	if err != nil {
+		contract.Close()
+		return nil, fmt.Errorf("failed to create ERC20NodeValidator: error while opening the withdraw index: %v", err)
+	}
 	return &ERC20NodeValidator{
-		lc:       contract.LightClient(),
-		abi:      abi,
-		contract: contract,
+		lc:        contract.LightClient(),
+		abi:       abi,
+		contract:  contract,
+		withdraws: withdraws,
 	}, nil
 }
 
+// Close the withdraw index and the bridge contract (and its light client).
+func (ev *ERC20NodeValidator) Close() error {
+	err := ev.withdraws.Close()
+	if cerr := ev.contract.Close(); cerr != nil && err == nil {
+		err = cerr
+	}
+	return err
+}
+
 // ValidateWithdrawTx implements ERC20TransactionValidator.ValidateWithdrawTx
 func (ev *ERC20NodeValidator) ValidateWithdrawTx(_blockID, txID tftypes.ERC20Hash, expectedAddress tftypes.ERC20Address, expectedAmount types.Currency) error {
-	withdraws, err := ev.contract.GetPastWithdraws(0, nil)
-	for erc20.IsNoPeerErr(err) {
-		time.Sleep(time.Second * 5)
-		log.Debug("Retrying to get past withdraws from peers")
-		withdraws, err = ev.contract.GetPastWithdraws(0, nil)
-	}
+	// look up the withdraw event in our local index,
+	// which is refreshed only should the event not be indexed yet
+	w, found, err := ev.withdraws.Lookup(common.Hash(txID))
 	if err != nil {
-		return err
-	}
-	found := false
-	var blockHash common.Hash
-	for _, w := range withdraws {
-		// looks like we found our transaction
-		if w.TxHash() == common.Hash(txID) {
-			found = true
-			if (_blockID != tftypes.ERC20Hash{}) && common.Hash(_blockID) != w.BlockHash() {
-				// IF a blockID is given, check if its the same. It might be different in case of a fork,
-				// if so just add a statement in the logs.
-				log.Info("Withdraw tx found in different block then specified", "expected", _blockID, "got", w.BlockHash().Hex())
-			}
-			if common.Address(expectedAddress) != w.Receiver() {
-				return fmt.Errorf("Withdraw tx validation failed: invalid receiving address. Want address %s, got address %s", w.Receiver().Hex(), common.Address(expectedAddress).Hex())
-			}
-			if expectedAmount.Cmp(types.NewCurrency(w.Amount())) != 0 {
-				return fmt.Errorf("Withdraw tx validation failed: invalid amount. Want %s, got %s", w.Amount().String(), expectedAmount.String())
-			}
-			// all event validations succeeded
-			// remember block hash from the withdraw event so we can look up the
-			// tx to check if it is old enough
-			blockHash = w.BlockHash()
-			break
-		}
+		return fmt.Errorf("failed to look up withdraw event: %v", err)
 	}
 	if !found {
 		return fmt.Errorf("Withdraw tx validation failed: no matching withdraw event found - invalid tx ID %s", common.Hash(txID).Hex())
 	}
+	if (_blockID != tftypes.ERC20Hash{}) && common.Hash(_blockID) != w.BlockHash() {
+		// IF a blockID is given, check if its the same. It might be different in case of a fork,
+		// if so just add a statement in the logs.
+		log.Info("Withdraw tx found in different block then specified", "expected", _blockID, "got", w.BlockHash().Hex())
+	}
+	if common.Address(expectedAddress) != w.Receiver() {
+		return fmt.Errorf("Withdraw tx validation failed: invalid receiving address. Want address %s, got address %s", w.Receiver().Hex(), common.Address(expectedAddress).Hex())
+	}
+	if expectedAmount.Cmp(types.NewCurrency(w.Amount())) != 0 {
+		return fmt.Errorf("Withdraw tx validation failed: invalid amount. Want %s, got %s", w.Amount().String(), expectedAmount.String())
+	}
+	// all event validations succeeded
+	// remember block hash from the withdraw event so we can look up the
+	// tx to check if it is old enough
+	blockHash := w.BlockHash()
 
 	// Get the transaction
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
@@ -174,6 +179,25 @@ func (ev *ERC20NodeValidator) GetBalanceInfo() (*tftypes.ERC20BalanceInfo, error
 	return ev.lc.GetBalanceInfo()
 }
 
+// RegisterMetrics registers the metrics of the light client
+// and the withdraw index to the given registry.
+func (ev *ERC20NodeValidator) RegisterMetrics(reg *metrics.Registry) error {
+	err := reg.Register(metrics.NewGaugeFunc(
+		"tfchain_erc20_withdraw_index_height",
+		"Height of the last ETH block indexed by the withdraw index of the ERC20 validator.",
+		func() (float64, bool) {
+			height, indexed, err := ev.withdraws.Height()
+			if err != nil || !indexed {
+				return 0, false
+			}
+			return float64(height), true
+		}))
+	if err != nil {
+		return err
+	}
+	return ev.lc.RegisterMetrics(reg)
+}
+
 // Wait implements ERC20TransactionValidator.Wait
 func (ev *ERC20NodeValidator) Wait(ctx context.Context) error {
 	return ev.lc.Wait(ctx)
@@ -37,6 +37,9 @@ func main() {
 	// eth flags
 	cmds.erc20Cfg.SetFlags(rootCommand.Flags())
 
+	// 3bot DNS flags
+	cmds.dnsCfg.SetFlags(rootCommand.Flags())
+
 	// create the other commands
 	rootCommand.AddCommand(&cobra.Command{
 		Use:   "version",
@@ -59,14 +59,33 @@ func RegisterExplorerHTTPHandlers(router rapi.Router, cs modules.ConsensusSet, e
 
 	router.GET("/explorer/mintcondition", NewTransactionDBGetActiveMintConditionHandler(txdb))
 	router.GET("/explorer/mintcondition/:height", NewTransactionDBGetMintConditionAtHandler(txdb))
+	router.GET("/explorer/mint/history", NewTransactionDBGetCoinCreationsHandler(txdb))
+	router.GET("/explorer/supply", NewTransactionDBGetCoinSupplyHandler(txdb))
+	router.GET("/explorer/burns", NewTransactionDBGetCoinBurnsHandler(txdb))
+	router.GET("/explorer/mintingcap", NewTransactionDBGetMintingCapHandler(txdb))
+	router.GET("/explorer/parameters", NewTransactionDBGetActiveConsensusParametersHandler(txdb))
+	router.GET("/explorer/parameters/:height", NewTransactionDBGetConsensusParametersAtHandler(txdb))
 
 	router.GET("/explorer/3bot/:id", NewTransactionDBGetRecordForIDHandler(txdb))
 	router.GET("/explorer/whois/3bot/:name", NewTransactionDBGetRecordForNameHandler(txdb))
 	router.GET("/explorer/3bot/:id/transactions", NewTransactionDBGetBotTransactionsHandler(txdb))
+	router.GET("/explorer/3bot/:id/history", NewTransactionDBGetBotRecordHistoryHandler(txdb))
+	router.POST("/explorer/3bot/:id/verify", NewTransactionDBVerifyBotMessageHandler(txdb))
+	router.GET("/explorer/whois/3bot/:name/history", NewTransactionDBGetBotNameHistoryHandler(txdb))
+	router.GET("/explorer/whois/3bot/:name/children", NewTransactionDBGetBotNameChildrenHandler(txdb))
+	router.GET("/explorer/whois/3bot/:name/delegates", NewTransactionDBGetBotNameDelegatesHandler(txdb))
+	router.GET("/explorer/3bots", NewTransactionDBGetRecordsHandler(txdb))
+	router.GET("/explorer/3bots/byaddress/:address", NewTransactionDBGetRecordsForNetworkAddressHandler(txdb))
 
 	router.GET("/explorer/erc20/addresses/:address", NewTransactionDBGetERC20RelatedAddressHandler(txdb))
 	router.GET("/explorer/erc20/transactions/:txid", NewTransactionDBGetERC20TransactionID(txdb))
 
+	router.GET("/explorer/capacity/nodes/:node", NewTransactionDBGetCapacityNodeHandler(txdb))
+	router.GET("/explorer/capacity/nodes/:node/challenges/:height", NewTransactionDBGetCapacityChallengeHandler(txdb))
+	router.GET("/explorer/capacity/farms/:farm", NewTransactionDBGetCapacityFarmHandler(txdb))
+
+	router.GET("/explorer/farm/:id", NewTransactionDBGetFarmRecordHandler(txdb))
+
 	// tfchain rivine-overwritten endpoints
 
 	router.GET("/explorer/hashes/:hash", NewExplorerHashHandler(explorer, cs, tpool, txdb))
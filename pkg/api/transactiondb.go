@@ -1,7 +1,9 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
 
@@ -21,9 +23,112 @@ type (
 		MintCondition types.UnlockConditionProxy `json:"mintcondition"`
 	}
 
+	// TransactionDBGetCoinCreationList contains a page of listed coin creations,
+	// the total amount of coins created by all coin creations matching the filter (not just the listed ones),
+	// as well as the cursor to use in order to get the next page,
+	// which is only defined if more coin creations could be listed.
+	TransactionDBGetCoinCreationList struct {
+		CoinCreations []TransactionDBCoinCreationListEntry `json:"coincreations"`
+		Total         types.Currency                       `json:"total"`
+		Cursor        types.TransactionShortID             `json:"cursor,omitempty"`
+	}
+	// TransactionDBCoinCreationListEntry contains a listed transaction that created coins.
+	TransactionDBCoinCreationListEntry struct {
+		TransactionID types.TransactionID `json:"txid"`
+		BlockHeight   types.BlockHeight   `json:"blockheight"`
+		Timestamp     types.Timestamp     `json:"timestamp"`
+		// Type of the transaction, one of "mint" or "erc20".
+		Type string `json:"type"`
+		// Value is the total amount of coins created, including the miner fees.
+		Value types.Currency `json:"value"`
+		// Minter is the unlock hash of the fulfilled mint condition, only defined for the "mint" type.
+		Minter        *types.UnlockHash `json:"minter,omitempty"`
+		ArbitraryData types.ByteSlice   `json:"arbitrarydata,omitempty"`
+	}
+
+	// TransactionDBGetCoinBurnList contains a page of listed coin burns,
+	// the total amount of coins burned by all coin burns past the given cursor (not just the listed ones),
+	// as well as the cursor to use in order to get the next page,
+	// which is only defined if more coin burns could be listed.
+	TransactionDBGetCoinBurnList struct {
+		CoinBurns []TransactionDBCoinBurnListEntry `json:"coinburns"`
+		Total     types.Currency                   `json:"total"`
+		Cursor    types.TransactionShortID         `json:"cursor,omitempty"`
+	}
+	// TransactionDBCoinBurnListEntry contains a listed coin burn transaction.
+	TransactionDBCoinBurnListEntry struct {
+		TransactionID types.TransactionID `json:"txid"`
+		BlockHeight   types.BlockHeight   `json:"blockheight"`
+		Timestamp     types.Timestamp     `json:"timestamp"`
+		// Value is the amount of coins burned, excluding the miner fees.
+		Value  types.Currency  `json:"value"`
+		Reason types.ByteSlice `json:"reason"`
+	}
+
+	// TransactionDBGetMintingCap contains the lowest minting cap that applied since the requested time,
+	// only defined if a minting cap applied, as well as the amount of coins minted since that time.
+	TransactionDBGetMintingCap struct {
+		MintingCap *types.Currency `json:"mintingcap,omitempty"`
+		Minted     types.Currency  `json:"minted"`
+	}
+
+	// TransactionDBGetCoinSupply contains the coins created and destroyed,
+	// as well as the total amount of coins in circulation, at the current block height.
+	TransactionDBGetCoinSupply struct {
+		Genesis           types.Currency `json:"genesis"`
+		BlockRewards      types.Currency `json:"blockrewards"`
+		Minted            types.Currency `json:"minted"`
+		ERC20CreatedIn    types.Currency `json:"erc20createdin"`
+		ERC20ConvertedOut types.Currency `json:"erc20convertedout"`
+		Burned            types.Currency `json:"burned"`
+		Total             types.Currency `json:"total"`
+	}
+
+	// TransactionDBGetConsensusParameters contains the consensus parameters,
+	// either the ones active for the next block or the ones active at the given block height.
+	TransactionDBGetConsensusParameters struct {
+		Parameters tftypes.ConsensusParameters `json:"parameters"`
+	}
+
 	// TransactionDBGetBotRecord contains a requested bot record.
 	TransactionDBGetBotRecord struct {
 		Record tftypes.BotRecord `json:"record"`
+		// Status of the bot at the current chain time,
+		// one of "active", "grace" or "released".
+		Status tftypes.BotRecordStatus `json:"status"`
+	}
+
+	// TransactionDBGetBotRecords contains the requested bot records.
+	TransactionDBGetBotRecords struct {
+		Records []tftypes.BotRecord `json:"records"`
+	}
+
+	// TransactionDBGetBotRecordList contains a page of listed bot records,
+	// as well as the cursor to use in order to get the next page,
+	// which is only defined if more bot records could be listed.
+	TransactionDBGetBotRecordList struct {
+		Records []TransactionDBBotRecordListEntry `json:"records"`
+		Cursor  tftypes.BotID                     `json:"cursor,omitempty"`
+	}
+	// TransactionDBBotRecordListEntry contains a listed bot record,
+	// its status at the current chain time and the block height at which it was registered.
+	TransactionDBBotRecordListEntry struct {
+		Record             tftypes.BotRecord       `json:"record"`
+		Status             tftypes.BotRecordStatus `json:"status"`
+		RegistrationHeight types.BlockHeight       `json:"registrationheight"`
+	}
+
+	// TransactionDBVerifyBotMessagePOST contains a message and its signature,
+	// to be verified by a POST call to /transactiondb/3bot/:id/verify.
+	TransactionDBVerifyBotMessagePOST struct {
+		Message   types.ByteSlice `json:"message"`
+		Signature types.ByteSlice `json:"signature"`
+	}
+	// TransactionDBVerifyBotMessage contains the result of a verified 3bot message signature,
+	// as well as the public key of the 3bot it was verified against.
+	TransactionDBVerifyBotMessage struct {
+		Valid     bool            `json:"valid"`
+		PublicKey types.PublicKey `json:"publickey"`
 	}
 
 	// TransactionDBGetBotTransactions contains the requested identifiers
@@ -32,6 +137,27 @@ type (
 		Identifiers []types.TransactionID `json:"ids"`
 	}
 
+	// TransactionDBGetBotRecordHistory contains all versions of a requested bot record.
+	TransactionDBGetBotRecordHistory struct {
+		History []tftypes.BotRecordVersion `json:"history"`
+	}
+
+	// TransactionDBGetBotNameHistory contains the ownership history of a requested bot name.
+	TransactionDBGetBotNameHistory struct {
+		History []tftypes.BotNameOwnership `json:"history"`
+	}
+
+	// TransactionDBGetBotNameChildren contains the (active) sub names of a requested bot name.
+	TransactionDBGetBotNameChildren struct {
+		Children []tftypes.BotName `json:"children"`
+	}
+
+	// TransactionDBGetBotNameDelegates contains the identifiers of the bots
+	// a requested bot name is delegated to.
+	TransactionDBGetBotNameDelegates struct {
+		Delegates []tftypes.BotID `json:"delegates"`
+	}
+
 	// TransactionDBGetERC20RelatedAddress contains the requested ERC20-related addresses.
 	TransactionDBGetERC20RelatedAddress struct {
 		TFTAddress   types.UnlockHash     `json:"tftaddress"`
@@ -43,6 +169,37 @@ type (
 		ERC20TransaxtionID   tftypes.ERC20Hash   `json:"er20txid"`
 		TfchainTransactionID types.TransactionID `json:"tfttxid"`
 	}
+
+	// TransactionDBGetCapacityNode contains the requested (latest) capacity record of a node,
+	// as well as all capacity records registered for that node,
+	// and the results of all capacity challenges of that node.
+	TransactionDBGetCapacityNode struct {
+		Record   tftypes.CapacityRecord        `json:"record"`
+		History  []tftypes.CapacityRecord      `json:"history"`
+		Verified bool                          `json:"verified"`
+		Proofs   []tftypes.CapacityProofResult `json:"proofs"`
+	}
+
+	// TransactionDBGetCapacityChallenge contains the requested capacity challenge of a node,
+	// as well as the result of that challenge, if available already.
+	TransactionDBGetCapacityChallenge struct {
+		Challenge tftypes.CapacityChallenge    `json:"challenge"`
+		Result    *tftypes.CapacityProofResult `json:"result,omitempty"`
+	}
+
+	// TransactionDBGetCapacityFarm contains the condition to be fulfilled
+	// in order to register capacity for the requested farm, as well as
+	// the nodes currently registered for that farm.
+	TransactionDBGetCapacityFarm struct {
+		Farm            tftypes.FarmID             `json:"farm"`
+		FarmerCondition types.UnlockConditionProxy `json:"farmercondition"`
+		Nodes           []types.PublicKey          `json:"nodes"`
+	}
+
+	// TransactionDBGetFarmRecord contains the requested (latest) record of a farm.
+	TransactionDBGetFarmRecord struct {
+		Record tftypes.FarmRecord `json:"record"`
+	}
 )
 
 // RegisterTransactionDBHTTPHandlers registers the handlers for all TransactionDB HTTP endpoints.
@@ -56,13 +213,32 @@ func RegisterTransactionDBHTTPHandlers(router api.Router, txdb *persist.Transact
 
 	router.GET("/consensus/mintcondition", NewTransactionDBGetActiveMintConditionHandler(txdb))
 	router.GET("/consensus/mintcondition/:height", NewTransactionDBGetMintConditionAtHandler(txdb))
+	router.GET("/consensus/mint/history", NewTransactionDBGetCoinCreationsHandler(txdb))
+	router.GET("/consensus/supply", NewTransactionDBGetCoinSupplyHandler(txdb))
+	router.GET("/consensus/burns", NewTransactionDBGetCoinBurnsHandler(txdb))
+	router.GET("/consensus/mintingcap", NewTransactionDBGetMintingCapHandler(txdb))
+	router.GET("/consensus/parameters", NewTransactionDBGetActiveConsensusParametersHandler(txdb))
+	router.GET("/consensus/parameters/:height", NewTransactionDBGetConsensusParametersAtHandler(txdb))
 
 	router.GET("/consensus/3bot/:id", NewTransactionDBGetRecordForIDHandler(txdb))
 	router.GET("/consensus/whois/3bot/:name", NewTransactionDBGetRecordForNameHandler(txdb))
 	router.GET("/consensus/3bot/:id/transactions", NewTransactionDBGetBotTransactionsHandler(txdb))
+	router.GET("/consensus/3bot/:id/history", NewTransactionDBGetBotRecordHistoryHandler(txdb))
+	router.POST("/consensus/3bot/:id/verify", NewTransactionDBVerifyBotMessageHandler(txdb))
+	router.GET("/consensus/3bots", NewTransactionDBGetRecordsHandler(txdb))
+	router.GET("/consensus/3bots/byaddress/:address", NewTransactionDBGetRecordsForNetworkAddressHandler(txdb))
+	router.GET("/consensus/whois/3bot/:name/history", NewTransactionDBGetBotNameHistoryHandler(txdb))
+	router.GET("/consensus/whois/3bot/:name/children", NewTransactionDBGetBotNameChildrenHandler(txdb))
+	router.GET("/consensus/whois/3bot/:name/delegates", NewTransactionDBGetBotNameDelegatesHandler(txdb))
 
 	router.GET("/consensus/erc20/addresses/:address", NewTransactionDBGetERC20RelatedAddressHandler(txdb))
 	router.GET("/consensus/erc20/transactions/:txid", NewTransactionDBGetERC20TransactionID(txdb))
+
+	router.GET("/consensus/capacity/nodes/:node", NewTransactionDBGetCapacityNodeHandler(txdb))
+	router.GET("/consensus/capacity/nodes/:node/challenges/:height", NewTransactionDBGetCapacityChallengeHandler(txdb))
+	router.GET("/consensus/capacity/farms/:farm", NewTransactionDBGetCapacityFarmHandler(txdb))
+
+	router.GET("/consensus/farm/:id", NewTransactionDBGetFarmRecordHandler(txdb))
 }
 
 // NewTransactionDBGetActiveMintConditionHandler creates a handler to handle the API calls to /transactiondb/mintcondition.
@@ -99,7 +275,254 @@ func NewTransactionDBGetMintConditionAtHandler(txdb *persist.TransactionDB) http
 	}
 }
 
+const (
+	// DefaultCoinCreationListLimit is the amount of coin creations listed per page,
+	// in case no limit is defined.
+	DefaultCoinCreationListLimit = 50
+	// MaxCoinCreationListLimit is the maximum amount of coin creations that can be listed per page.
+	MaxCoinCreationListLimit = 1000
+)
+
+// NewTransactionDBGetCoinCreationsHandler creates a handler to handle the API calls to /transactiondb/mint/history.
+// The following (optional) query parameters are supported:
+//   - cursor: the (exclusive) short ID of the transaction after which to start listing;
+//   - limit: the maximum amount of coin creations to list, DefaultCoinCreationListLimit by default;
+//   - type: only list coin creations of the given type, "mint" or "erc20";
+//   - minter: only list coin creations that fulfilled the mint condition with the given unlock hash;
+//   - since, before: only list coin creations created within the given range of block timestamps;
+func NewTransactionDBGetCoinCreationsHandler(txdb *persist.TransactionDB) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		var (
+			cursor uint64
+			limit  = DefaultCoinCreationListLimit
+			filter persist.CoinCreationFilter
+			err    error
+		)
+		if str := req.FormValue("cursor"); str != "" {
+			cursor, err = strconv.ParseUint(str, 10, 64)
+			if err != nil {
+				api.WriteError(w, api.Error{Message: fmt.Sprintf("invalid cursor given: %v", err)}, http.StatusBadRequest)
+				return
+			}
+		}
+		if str := req.FormValue("limit"); str != "" {
+			x, err := strconv.ParseUint(str, 10, 64)
+			if err != nil || x == 0 || x > MaxCoinCreationListLimit {
+				api.WriteError(w, api.Error{Message: fmt.Sprintf(
+					"invalid limit given: has to be a number in the range [1, %d]", MaxCoinCreationListLimit)}, http.StatusBadRequest)
+				return
+			}
+			limit = int(x)
+		}
+		if str := req.FormValue("type"); str != "" {
+			err = filter.Type.LoadString(str)
+			if err != nil {
+				api.WriteError(w, api.Error{Message: fmt.Sprintf("invalid type given: %v", err)}, http.StatusBadRequest)
+				return
+			}
+		}
+		if str := req.FormValue("minter"); str != "" {
+			err = filter.Minter.LoadString(str)
+			if err != nil {
+				api.WriteError(w, api.Error{Message: fmt.Sprintf("invalid minter given: %v", err)}, http.StatusBadRequest)
+				return
+			}
+		}
+		for _, param := range []struct {
+			Name  string
+			Value *uint64
+		}{
+			{"since", (*uint64)(&filter.Since)},
+			{"before", (*uint64)(&filter.Before)},
+		} {
+			if str := req.FormValue(param.Name); str != "" {
+				x, err := strconv.ParseUint(str, 10, 64)
+				if err != nil {
+					api.WriteError(w, api.Error{Message: fmt.Sprintf("invalid %s given: %v", param.Name, err)}, http.StatusBadRequest)
+					return
+				}
+				*param.Value = x
+			}
+		}
+		records, next, total, err := txdb.GetCoinCreations(types.TransactionShortID(cursor), limit, filter)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, http.StatusInternalServerError)
+			return
+		}
+		entries := make([]TransactionDBCoinCreationListEntry, 0, len(records))
+		for _, record := range records {
+			entry := TransactionDBCoinCreationListEntry{
+				TransactionID: record.TransactionID,
+				BlockHeight:   record.BlockHeight,
+				Timestamp:     record.BlockTime,
+				Type:          record.Type.String(),
+				Value:         record.Value,
+				ArbitraryData: record.ArbitraryData,
+			}
+			if record.Type == persist.CoinCreationTypeMint {
+				minter := record.Minter
+				entry.Minter = &minter
+			}
+			entries = append(entries, entry)
+		}
+		api.WriteJSON(w, TransactionDBGetCoinCreationList{
+			CoinCreations: entries,
+			Total:         total,
+			Cursor:        next,
+		})
+	}
+}
+
+// NewTransactionDBGetCoinBurnsHandler creates a handler to handle the API calls to /transactiondb/burns.
+// The following (optional) query parameters are supported:
+//   - cursor: the (exclusive) short ID of the transaction after which to start listing;
+//   - limit: the maximum amount of coin burns to list, DefaultCoinCreationListLimit by default;
+func NewTransactionDBGetCoinBurnsHandler(txdb *persist.TransactionDB) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		var (
+			cursor uint64
+			limit  = DefaultCoinCreationListLimit
+			err    error
+		)
+		if str := req.FormValue("cursor"); str != "" {
+			cursor, err = strconv.ParseUint(str, 10, 64)
+			if err != nil {
+				api.WriteError(w, api.Error{Message: fmt.Sprintf("invalid cursor given: %v", err)}, http.StatusBadRequest)
+				return
+			}
+		}
+		if str := req.FormValue("limit"); str != "" {
+			x, err := strconv.ParseUint(str, 10, 64)
+			if err != nil || x == 0 || x > MaxCoinCreationListLimit {
+				api.WriteError(w, api.Error{Message: fmt.Sprintf(
+					"invalid limit given: has to be a number in the range [1, %d]", MaxCoinCreationListLimit)}, http.StatusBadRequest)
+				return
+			}
+			limit = int(x)
+		}
+		records, next, total, err := txdb.GetCoinBurns(types.TransactionShortID(cursor), limit)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, http.StatusInternalServerError)
+			return
+		}
+		entries := make([]TransactionDBCoinBurnListEntry, 0, len(records))
+		for _, record := range records {
+			entries = append(entries, TransactionDBCoinBurnListEntry{
+				TransactionID: record.TransactionID,
+				BlockHeight:   record.BlockHeight,
+				Timestamp:     record.BlockTime,
+				Value:         record.Value,
+				Reason:        record.Reason,
+			})
+		}
+		api.WriteJSON(w, TransactionDBGetCoinBurnList{
+			CoinBurns: entries,
+			Total:     total,
+			Cursor:    next,
+		})
+	}
+}
+
+// NewTransactionDBGetMintingCapHandler creates a handler to handle the API calls to /transactiondb/mintingcap.
+// The following (optional) query parameters are supported:
+//   - since: the block timestamp since when to get the (lowest) minting cap and minted coins, 0 by default;
+//   - height: only take into account the blocks prior to the given (consensus) block height, all blocks by default;
+func NewTransactionDBGetMintingCapHandler(txdb *persist.TransactionDB) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		var (
+			since  types.Timestamp
+			height = types.BlockHeight(math.MaxUint64)
+		)
+		if str := req.FormValue("since"); str != "" {
+			x, err := strconv.ParseUint(str, 10, 64)
+			if err != nil {
+				api.WriteError(w, api.Error{Message: fmt.Sprintf("invalid since timestamp given: %v", err)}, http.StatusBadRequest)
+				return
+			}
+			since = types.Timestamp(x)
+		}
+		if str := req.FormValue("height"); str != "" {
+			x, err := strconv.ParseUint(str, 10, 64)
+			if err != nil {
+				api.WriteError(w, api.Error{Message: fmt.Sprintf("invalid block height given: %v", err)}, http.StatusBadRequest)
+				return
+			}
+			height = types.BlockHeight(x)
+		}
+		mintingCap, capped, err := txdb.GetMintingCapSince(height, since)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, http.StatusInternalServerError)
+			return
+		}
+		minted, err := txdb.GetMintedCoinsSince(height, since)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, http.StatusInternalServerError)
+			return
+		}
+		resp := TransactionDBGetMintingCap{Minted: minted}
+		if capped {
+			resp.MintingCap = &mintingCap
+		}
+		api.WriteJSON(w, resp)
+	}
+}
+
+// NewTransactionDBGetCoinSupplyHandler creates a handler to handle the API calls to /transactiondb/supply.
+func NewTransactionDBGetCoinSupplyHandler(txdb *persist.TransactionDB) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		supply, err := txdb.GetCoinSupply()
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, http.StatusInternalServerError)
+			return
+		}
+		api.WriteJSON(w, TransactionDBGetCoinSupply{
+			Genesis:           supply.Genesis,
+			BlockRewards:      supply.BlockRewards,
+			Minted:            supply.Minted,
+			ERC20CreatedIn:    supply.ERC20CreatedIn,
+			ERC20ConvertedOut: supply.ERC20ConvertedOut,
+			Burned:            supply.Burned,
+			Total:             supply.Total(),
+		})
+	}
+}
+
+// NewTransactionDBGetActiveConsensusParametersHandler creates a handler to handle the API calls to /transactiondb/parameters.
+func NewTransactionDBGetActiveConsensusParametersHandler(txdb *persist.TransactionDB) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		params, err := txdb.GetActiveConsensusParameters()
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, http.StatusInternalServerError)
+			return
+		}
+		api.WriteJSON(w, TransactionDBGetConsensusParameters{
+			Parameters: params,
+		})
+	}
+}
+
+// NewTransactionDBGetConsensusParametersAtHandler creates a handler to handle the API calls to /transactiondb/parameters/:height.
+func NewTransactionDBGetConsensusParametersAtHandler(txdb *persist.TransactionDB) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		heightStr := ps.ByName("height")
+		height, err := strconv.ParseUint(heightStr, 10, 64)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: fmt.Sprintf("invalid block height given: %v", err)}, http.StatusBadRequest)
+			return
+		}
+		params, err := txdb.GetConsensusParametersAt(types.BlockHeight(height))
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, http.StatusInternalServerError)
+			return
+		}
+		api.WriteJSON(w, TransactionDBGetConsensusParameters{
+			Parameters: params,
+		})
+	}
+}
+
 // NewTransactionDBGetRecordForIDHandler creates a handler to handle the API calls to /transactiondb/3bot/:id.
+// An optional height (query) parameter can be given, in order to get the record as it was at that block height.
 func NewTransactionDBGetRecordForIDHandler(txdb *persist.TransactionDB) httprouter.Handle {
 	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 		var (
@@ -107,6 +530,32 @@ func NewTransactionDBGetRecordForIDHandler(txdb *persist.TransactionDB) httprout
 			record *tftypes.BotRecord
 		)
 		idStr := ps.ByName("id")
+		if heightStr := req.FormValue("height"); heightStr != "" {
+			height, err := strconv.ParseUint(heightStr, 10, 64)
+			if err != nil {
+				api.WriteError(w, api.Error{Message: fmt.Sprintf("invalid block height given: %v", err)}, http.StatusBadRequest)
+				return
+			}
+			id, ok := getBotIDForIdentifier(w, txdb, idStr)
+			if !ok {
+				return
+			}
+			record, err = txdb.GetRecordForIDAt(id, types.BlockHeight(height))
+			if err != nil {
+				api.WriteError(w, api.Error{Message: err.Error()}, threeBotErrorAsHTTPStatusCode(err))
+				return
+			}
+			status, err := txdb.GetBotRecordStatus(record)
+			if err != nil {
+				api.WriteError(w, api.Error{Message: err.Error()}, http.StatusInternalServerError)
+				return
+			}
+			api.WriteJSON(w, TransactionDBGetBotRecord{
+				Record: *record,
+				Status: status,
+			})
+			return
+		}
 		var id tftypes.BotID
 		err = id.LoadString(idStr)
 		if err == nil {
@@ -127,8 +576,14 @@ func NewTransactionDBGetRecordForIDHandler(txdb *persist.TransactionDB) httprout
 			api.WriteError(w, api.Error{Message: err.Error()}, threeBotErrorAsHTTPStatusCode(err))
 			return
 		}
+		status, err := txdb.GetBotRecordStatus(record)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, http.StatusInternalServerError)
+			return
+		}
 		api.WriteJSON(w, TransactionDBGetBotRecord{
 			Record: *record,
+			Status: status,
 		})
 	}
 }
@@ -148,8 +603,127 @@ func NewTransactionDBGetRecordForNameHandler(txdb *persist.TransactionDB) httpro
 			api.WriteError(w, api.Error{Message: err.Error()}, threeBotErrorAsHTTPStatusCode(err))
 			return
 		}
+		status, err := txdb.GetBotRecordStatus(record)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, http.StatusInternalServerError)
+			return
+		}
 		api.WriteJSON(w, TransactionDBGetBotRecord{
 			Record: *record,
+			Status: status,
+		})
+	}
+}
+
+// NewTransactionDBGetRecordsForNetworkAddressHandler creates a handler to handle the API calls to /transactiondb/3bots/byaddress/:address.
+func NewTransactionDBGetRecordsForNetworkAddressHandler(txdb *persist.TransactionDB) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		var addr tftypes.NetworkAddress
+		err := addr.LoadString(ps.ByName("address"))
+		if err != nil {
+			api.WriteError(w, api.Error{Message: fmt.Errorf("invalid network address: %v", err).Error()},
+				http.StatusBadRequest)
+			return
+		}
+		records, err := txdb.GetRecordsForNetworkAddress(addr)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, threeBotErrorAsHTTPStatusCode(err))
+			return
+		}
+		api.WriteJSON(w, TransactionDBGetBotRecords{
+			Records: records,
+		})
+	}
+}
+
+const (
+	// DefaultBotRecordListLimit is the amount of bot records listed per page,
+	// in case no limit is defined by the caller.
+	DefaultBotRecordListLimit = 50
+	// MaxBotRecordListLimit is the maximum amount of bot records that can be listed per page.
+	MaxBotRecordListLimit = 1000
+)
+
+// NewTransactionDBGetRecordsHandler creates a handler to handle the API calls to /transactiondb/3bots.
+// The bots are listed ordered by ID, a page at a time, where the next page can be requested
+// using the cursor returned as part of the previous page.
+// The optional (query) parameters are:
+//   - cursor: the ID of the last bot listed in the previous page;
+//   - limit: the maximum amount of bots to list, DefaultBotRecordListLimit by default;
+//   - expiresafter/expiresbefore: the (inclusive) window of the expiration (unix epoch) timestamp;
+//   - hasname: if true, only bots that own at least one name are listed;
+//   - registeredsince/registeredbefore: the range of the registration block height, the latter being exclusive.
+func NewTransactionDBGetRecordsHandler(txdb *persist.TransactionDB) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		var (
+			cursor tftypes.BotID
+			limit  = DefaultBotRecordListLimit
+			filter persist.BotRecordFilter
+		)
+		if str := req.FormValue("cursor"); str != "" {
+			x, err := strconv.ParseUint(str, 10, 32)
+			if err != nil {
+				api.WriteError(w, api.Error{Message: fmt.Sprintf("invalid cursor given: %v", err)}, http.StatusBadRequest)
+				return
+			}
+			cursor = tftypes.BotID(x)
+		}
+		if str := req.FormValue("limit"); str != "" {
+			x, err := strconv.ParseUint(str, 10, 64)
+			if err != nil || x == 0 || x > MaxBotRecordListLimit {
+				api.WriteError(w, api.Error{Message: fmt.Sprintf(
+					"invalid limit given: has to be a number in the range [1, %d]", MaxBotRecordListLimit)}, http.StatusBadRequest)
+				return
+			}
+			limit = int(x)
+		}
+		for _, param := range []struct {
+			Name  string
+			Value *uint64
+		}{
+			{"expiresafter", (*uint64)(&filter.ExpiresAfter)},
+			{"expiresbefore", (*uint64)(&filter.ExpiresBefore)},
+			{"registeredsince", (*uint64)(&filter.RegisteredSince)},
+			{"registeredbefore", (*uint64)(&filter.RegisteredBefore)},
+		} {
+			if str := req.FormValue(param.Name); str != "" {
+				x, err := strconv.ParseUint(str, 10, 64)
+				if err != nil {
+					api.WriteError(w, api.Error{Message: fmt.Sprintf("invalid %s given: %v", param.Name, err)}, http.StatusBadRequest)
+					return
+				}
+				*param.Value = x
+			}
+		}
+		if str := req.FormValue("hasname"); str != "" {
+			hasName, err := strconv.ParseBool(str)
+			if err != nil {
+				api.WriteError(w, api.Error{Message: fmt.Sprintf("invalid hasname given: %v", err)}, http.StatusBadRequest)
+				return
+			}
+			filter.HasName = hasName
+		}
+		listings, next, err := txdb.GetRecords(cursor, limit, filter)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, threeBotErrorAsHTTPStatusCode(err))
+			return
+		}
+		entries := make([]TransactionDBBotRecordListEntry, 0, len(listings))
+		for _, listing := range listings {
+			status, err := txdb.GetBotRecordStatus(&listing.Record)
+			if err != nil {
+				api.WriteError(w, api.Error{Message: err.Error()}, http.StatusInternalServerError)
+				return
+			}
+			entries = append(entries, TransactionDBBotRecordListEntry{
+				Record:             listing.Record,
+				Status:             status,
+				RegistrationHeight: listing.RegistrationHeight,
+			})
+		}
+		api.WriteJSON(w, TransactionDBGetBotRecordList{
+			Records: entries,
+			Cursor:  next,
 		})
 	}
 }
@@ -177,13 +751,166 @@ func NewTransactionDBGetBotTransactionsHandler(txdb *persist.TransactionDB) http
 	}
 }
 
+// NewTransactionDBGetBotRecordHistoryHandler creates a handler to handle the API calls to /transactiondb/3bot/:id/history.
+func NewTransactionDBGetBotRecordHistoryHandler(txdb *persist.TransactionDB) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		id, ok := getBotIDForIdentifier(w, txdb, ps.ByName("id"))
+		if !ok {
+			return
+		}
+		history, err := txdb.GetBotRecordHistory(id)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: fmt.Errorf("failed to get record history for BotID: %v", err).Error()},
+				threeBotErrorAsHTTPStatusCode(err))
+			return
+		}
+		api.WriteJSON(w, TransactionDBGetBotRecordHistory{
+			History: history,
+		})
+	}
+}
+
+// NewTransactionDBGetBotNameHistoryHandler creates a handler to handle the API calls to /transactiondb/whois/3bot/:name/history.
+func NewTransactionDBGetBotNameHistoryHandler(txdb *persist.TransactionDB) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		var name tftypes.BotName
+		err := name.LoadString(ps.ByName("name"))
+		if err != nil {
+			api.WriteError(w, api.Error{Message: fmt.Errorf("invalid botname: %v", err).Error()},
+				http.StatusBadRequest)
+			return
+		}
+		history, err := txdb.GetBotNameHistory(name)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, threeBotErrorAsHTTPStatusCode(err))
+			return
+		}
+		api.WriteJSON(w, TransactionDBGetBotNameHistory{
+			History: history,
+		})
+	}
+}
+
+// NewTransactionDBGetBotNameChildrenHandler creates a handler to handle the API calls to /transactiondb/whois/3bot/:name/children.
+func NewTransactionDBGetBotNameChildrenHandler(txdb *persist.TransactionDB) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		var name tftypes.BotName
+		err := name.LoadString(ps.ByName("name"))
+		if err != nil {
+			api.WriteError(w, api.Error{Message: fmt.Errorf("invalid botname: %v", err).Error()},
+				http.StatusBadRequest)
+			return
+		}
+		children, err := txdb.GetChildrenForName(name)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, threeBotErrorAsHTTPStatusCode(err))
+			return
+		}
+		api.WriteJSON(w, TransactionDBGetBotNameChildren{
+			Children: children,
+		})
+	}
+}
+
+// NewTransactionDBGetBotNameDelegatesHandler creates a handler to handle the API calls to /transactiondb/whois/3bot/:name/delegates.
+func NewTransactionDBGetBotNameDelegatesHandler(txdb *persist.TransactionDB) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		var name tftypes.BotName
+		err := name.LoadString(ps.ByName("name"))
+		if err != nil {
+			api.WriteError(w, api.Error{Message: fmt.Errorf("invalid botname: %v", err).Error()},
+				http.StatusBadRequest)
+			return
+		}
+		delegates, err := txdb.GetDelegatesForName(name)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, threeBotErrorAsHTTPStatusCode(err))
+			return
+		}
+		api.WriteJSON(w, TransactionDBGetBotNameDelegates{
+			Delegates: delegates,
+		})
+	}
+}
+
+// NewTransactionDBVerifyBotMessageHandler creates a handler to handle the API calls to /transactiondb/3bot/:id/verify.
+// The signature is verified against the current public key of the 3bot, unless
+// an optional height (query) parameter is given, in which case it is verified
+// against the public key the 3bot had at that block height.
+func NewTransactionDBVerifyBotMessageHandler(txdb *persist.TransactionDB) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		var body TransactionDBVerifyBotMessagePOST
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			api.WriteError(w, api.Error{Message: "error decoding the supplied message and signature: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		id, ok := getBotIDForIdentifier(w, txdb, ps.ByName("id"))
+		if !ok {
+			return
+		}
+		var (
+			err    error
+			record *tftypes.BotRecord
+		)
+		// the height is read from the URL query, as the body contains the JSON-encoded message
+		if heightStr := req.URL.Query().Get("height"); heightStr != "" {
+			var height uint64
+			height, err = strconv.ParseUint(heightStr, 10, 64)
+			if err != nil {
+				api.WriteError(w, api.Error{Message: fmt.Sprintf("invalid block height given: %v", err)}, http.StatusBadRequest)
+				return
+			}
+			record, err = txdb.GetRecordForIDAt(id, types.BlockHeight(height))
+		} else {
+			record, err = txdb.GetRecordForID(id)
+		}
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, threeBotErrorAsHTTPStatusCode(err))
+			return
+		}
+		err = tftypes.VerifyBotMessageSignature(id, body.Message, record.PublicKey, body.Signature)
+		if err != nil && err != tftypes.ErrInvalidBotMessageSignature {
+			api.WriteError(w, api.Error{Message: err.Error()}, http.StatusBadRequest)
+			return
+		}
+		api.WriteJSON(w, TransactionDBVerifyBotMessage{
+			Valid:     err == nil,
+			PublicKey: record.PublicKey,
+		})
+	}
+}
+
+// getBotIDForIdentifier interprets the given identifier as a BotID,
+// or as the PublicKey of a bot in case it isn't a valid BotID.
+// If no BotID can be found, an error is written to the response and false is returned.
+func getBotIDForIdentifier(w http.ResponseWriter, txdb *persist.TransactionDB, str string) (tftypes.BotID, bool) {
+	var id tftypes.BotID
+	err := id.LoadString(str)
+	if err == nil {
+		return id, true
+	}
+	var pubKey types.PublicKey
+	err = pubKey.LoadString(str)
+	if err != nil {
+		api.WriteError(w, api.Error{Message: fmt.Errorf("id has to be a valid PublicKey or BotID: %v", err).Error()},
+			http.StatusBadRequest)
+		return 0, false
+	}
+	record, err := txdb.GetRecordForKey(pubKey)
+	if err != nil {
+		api.WriteError(w, api.Error{Message: err.Error()}, threeBotErrorAsHTTPStatusCode(err))
+		return 0, false
+	}
+	return record.ID, true
+}
+
 // threeBotErrorAsHTTPStatusCode converts a 3bot error to an http status code.
 // if it is not an applicable 3bot error, an internal server error code is returned
 func threeBotErrorAsHTTPStatusCode(err error) int {
 	switch err {
 	case tftypes.ErrBotNotFound, tftypes.ErrBotNameNotFound, tftypes.ErrBotKeyNotFound:
 		return http.StatusNotFound
-	case tftypes.ErrBotNameExpired:
+	case tftypes.ErrBotNameExpired, tftypes.ErrBotNameInGracePeriod:
 		return http.StatusPaymentRequired
 	default:
 		return http.StatusInternalServerError
@@ -262,3 +989,134 @@ func NewTransactionDBGetERC20TransactionID(txdb *persist.TransactionDB) httprout
 		})
 	}
 }
+
+// NewTransactionDBGetCapacityNodeHandler creates a handler to handle the API calls to /transactiondb/capacity/nodes/:node.
+func NewTransactionDBGetCapacityNodeHandler(txdb *persist.TransactionDB) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		var node types.PublicKey
+		err := node.LoadString(ps.ByName("node"))
+		if err != nil {
+			api.WriteError(w, api.Error{Message: fmt.Sprintf("node has to be a valid PublicKey: %v", err)}, http.StatusBadRequest)
+			return
+		}
+		history, err := txdb.GetCapacityRecordHistoryForNode(node)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, capacityErrorAsHTTPStatusCode(err))
+			return
+		}
+		proofs, err := txdb.GetCapacityProofResultsForNode(node)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, capacityErrorAsHTTPStatusCode(err))
+			return
+		}
+		api.WriteJSON(w, TransactionDBGetCapacityNode{
+			Record:   history[len(history)-1],
+			History:  history,
+			Verified: tftypes.CapacityProofVerified(proofs),
+			Proofs:   proofs,
+		})
+	}
+}
+
+// NewTransactionDBGetCapacityChallengeHandler creates a handler to handle the API calls to /transactiondb/capacity/nodes/:node/challenges/:height.
+func NewTransactionDBGetCapacityChallengeHandler(txdb *persist.TransactionDB) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		var node types.PublicKey
+		err := node.LoadString(ps.ByName("node"))
+		if err != nil {
+			api.WriteError(w, api.Error{Message: fmt.Sprintf("node has to be a valid PublicKey: %v", err)}, http.StatusBadRequest)
+			return
+		}
+		heightStr := ps.ByName("height")
+		height, err := strconv.ParseUint(heightStr, 10, 64)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: fmt.Sprintf("invalid block height given: %v", err)}, http.StatusBadRequest)
+			return
+		}
+		challenge, err := txdb.GetCapacityChallenge(types.BlockHeight(height), node)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, capacityErrorAsHTTPStatusCode(err))
+			return
+		}
+		result, err := txdb.GetCapacityProofResultForNode(node, types.BlockHeight(height))
+		if err != nil {
+			if err != tftypes.ErrCapacityProofResultNotFound {
+				api.WriteError(w, api.Error{Message: err.Error()}, capacityErrorAsHTTPStatusCode(err))
+				return
+			}
+			result = nil // no result (yet)
+		}
+		api.WriteJSON(w, TransactionDBGetCapacityChallenge{
+			Challenge: challenge,
+			Result:    result,
+		})
+	}
+}
+
+// NewTransactionDBGetCapacityFarmHandler creates a handler to handle the API calls to /transactiondb/capacity/farms/:farm.
+func NewTransactionDBGetCapacityFarmHandler(txdb *persist.TransactionDB) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		var farm tftypes.FarmID
+		err := farm.LoadString(ps.ByName("farm"))
+		if err != nil {
+			api.WriteError(w, api.Error{Message: fmt.Sprintf("invalid farm given: %v", err)}, http.StatusBadRequest)
+			return
+		}
+		condition, err := txdb.GetFarmerCondition(farm)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, capacityErrorAsHTTPStatusCode(err))
+			return
+		}
+		nodes, err := txdb.GetNodesForFarm(farm)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, capacityErrorAsHTTPStatusCode(err))
+			return
+		}
+		api.WriteJSON(w, TransactionDBGetCapacityFarm{
+			Farm:            farm,
+			FarmerCondition: condition,
+			Nodes:           nodes,
+		})
+	}
+}
+
+// NewTransactionDBGetFarmRecordHandler creates a handler to handle the API calls to /transactiondb/farm/:id.
+// The farm can be identified by its (numeric) ID or by its name.
+func NewTransactionDBGetFarmRecordHandler(txdb *persist.TransactionDB) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		var (
+			farm   tftypes.FarmID
+			record *tftypes.FarmRecord
+		)
+		idStr := ps.ByName("id")
+		err := farm.LoadString(idStr)
+		if err == nil {
+			record, err = txdb.GetFarm(farm)
+		} else {
+			err = tftypes.ValidateFarmName(idStr)
+			if err != nil {
+				api.WriteError(w, api.Error{Message: "id has to be a valid FarmID or farm name"}, http.StatusBadRequest)
+				return
+			}
+			record, err = txdb.GetFarmForName(idStr)
+		}
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, capacityErrorAsHTTPStatusCode(err))
+			return
+		}
+		api.WriteJSON(w, TransactionDBGetFarmRecord{
+			Record: *record,
+		})
+	}
+}
+
+// capacityErrorAsHTTPStatusCode converts a capacity error to an http status code.
+// if it is not an applicable capacity error, an internal server error code is returned
+func capacityErrorAsHTTPStatusCode(err error) int {
+	switch err {
+	case tftypes.ErrFarmNotFound, tftypes.ErrFarmNameNotFound, tftypes.ErrNodeNotFound, tftypes.ErrCapacityChallengeNotFound:
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -1,8 +1,12 @@
 package api
 
 import (
+	"encoding/json"
 	"net/http"
 
+	"github.com/threefoldfoundation/tfchain/pkg/persist"
+	tftypes "github.com/threefoldfoundation/tfchain/pkg/types"
+
 	"github.com/julienschmidt/httprouter"
 	"github.com/threefoldtech/rivine/modules"
 	"github.com/threefoldtech/rivine/pkg/api"
@@ -22,19 +26,36 @@ type (
 	WalletPublicKeyGET struct {
 		PublicKey types.PublicKey `json:"publickey"`
 	}
+
+	// WalletSignBotMessagePOST contains the message to be signed
+	// by a POST call to /wallet/3bot/:id/sign.
+	WalletSignBotMessagePOST struct {
+		Message types.ByteSlice `json:"message"`
+	}
+	// WalletSignBotMessage contains the signature of a message signed as a 3bot,
+	// as well as the (current) public key of that 3bot, used to create the signature.
+	WalletSignBotMessage struct {
+		ID        tftypes.BotID   `json:"id"`
+		PublicKey types.PublicKey `json:"publickey"`
+		Signature types.ByteSlice `json:"signature"`
+	}
 )
 
 // RegisterWalletHTTPHandlers registers the (tfchain-specific) handlers for all Wallet HTTP endpoints.
-func RegisterWalletHTTPHandlers(router api.Router, wallet modules.Wallet, requiredPassword string) {
+func RegisterWalletHTTPHandlers(router api.Router, wallet modules.Wallet, txdb *persist.TransactionDB, requiredPassword string) {
 	if wallet == nil {
 		panic("no wallet API given")
 	}
+	if txdb == nil {
+		panic("no transaction DB given")
+	}
 	if router == nil {
 		panic("no httprouter Router given")
 	}
 
 	router.GET("/wallet/publickey", api.RequirePasswordHandler(NewWalletGetPublicKeyHandler(wallet), requiredPassword))
 	router.GET("/wallet/fund/coins", api.RequirePasswordHandler(NewWalletFundCoinsHandler(wallet), requiredPassword))
+	router.POST("/wallet/3bot/:id/sign", api.RequirePasswordHandler(NewWalletSignBotMessageHandler(wallet, txdb), requiredPassword))
 }
 
 // NewWalletFundCoinsHandler creates a handler to handle the API calls to /wallet/fund/coins?amount=.
@@ -107,6 +128,44 @@ func NewWalletGetPublicKeyHandler(wallet modules.Wallet) httprouter.Handle {
 	}
 }
 
+// NewWalletSignBotMessageHandler creates a handler to handle API calls to /wallet/3bot/:id/sign.
+// The message is signed as the 3bot identified by the given ID or public key,
+// using the private key paired with the public key of the 3bot's record,
+// which has to be loaded into this wallet.
+func NewWalletSignBotMessageHandler(wallet modules.Wallet, txdb *persist.TransactionDB) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
+		var body WalletSignBotMessagePOST
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			api.WriteError(w, api.Error{Message: "error decoding the supplied message: " + err.Error()}, http.StatusBadRequest)
+			return
+		}
+		id, ok := getBotIDForIdentifier(w, txdb, ps.ByName("id"))
+		if !ok {
+			return
+		}
+		record, err := txdb.GetRecordForID(id)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, threeBotErrorAsHTTPStatusCode(err))
+			return
+		}
+		_, sk, err := wallet.GetKey(types.NewPubKeyUnlockHash(record.PublicKey))
+		if err != nil {
+			api.WriteError(w, api.Error{Message: "failed to get the private key of the 3bot: " + err.Error()}, walletErrorToHTTPStatus(err))
+			return
+		}
+		signature, err := tftypes.SignBotMessage(id, body.Message, record.PublicKey, sk)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: "failed to sign the message: " + err.Error()}, http.StatusInternalServerError)
+			return
+		}
+		api.WriteJSON(w, WalletSignBotMessage{
+			ID:        id,
+			PublicKey: record.PublicKey,
+			Signature: signature,
+		})
+	}
+}
+
 func walletErrorToHTTPStatus(err error) int {
 	if err == modules.ErrLockedWallet {
 		return http.StatusForbidden
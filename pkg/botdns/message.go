@@ -0,0 +1,255 @@
+package botdns
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+)
+
+// The DNS wire format, as defined in RFC 1035,
+// limited to what is required by an authoritative server for a single zone.
+
+const (
+	typeA     uint16 = 1
+	typeCNAME uint16 = 5
+	typeSOA   uint16 = 6
+	typeTXT   uint16 = 16
+	typeAAAA  uint16 = 28
+	typeANY   uint16 = 255
+
+	classINET uint16 = 1
+	classANY  uint16 = 255
+
+	rcodeSuccess        uint16 = 0
+	rcodeFormatError    uint16 = 1
+	rcodeServerFailure  uint16 = 2
+	rcodeNameError      uint16 = 3 // NXDOMAIN
+	rcodeNotImplemented uint16 = 4
+	rcodeRefused        uint16 = 5
+
+	flagResponse      uint16 = 1 << 15
+	flagAuthoritative uint16 = 1 << 10
+	flagTruncated     uint16 = 1 << 9
+	flagRecursion     uint16 = 1 << 8
+	maskOpcode        uint16 = 0xF << 11
+
+	headerLength = 12
+	// maxUDPMessageLength is the maximum length of a DNS message sent over UDP,
+	// when no larger size is negotiated (which this server doesn't support).
+	maxUDPMessageLength = 512
+	maxLabelLength      = 63
+	maxNameLength       = 255
+	maxTXTStringLength  = 255
+)
+
+var (
+	errMessageTooShort   = errors.New("DNS message too short")
+	errNotAQuery         = errors.New("DNS message is not a query")
+	errInvalidQuestion   = errors.New("DNS message requires exactly one question")
+	errInvalidLabel      = errors.New("invalid label in DNS name")
+	errNameTooLong       = errors.New("DNS name too long")
+	errCompressedInQuery = errors.New("compressed DNS names are not supported in questions")
+)
+
+type (
+	header struct {
+		ID      uint16
+		Flags   uint16
+		QDCount uint16
+		ANCount uint16
+		NSCount uint16
+		ARCount uint16
+	}
+
+	question struct {
+		// Name is the fully qualified name, in lower case and without the trailing dot
+		Name  string
+		Type  uint16
+		Class uint16
+	}
+
+	resourceRecord struct {
+		Name string
+		Type uint16
+		TTL  uint32
+		Data []byte
+	}
+
+	message struct {
+		Header    header
+		Question  *question
+		Answers   []resourceRecord
+		Authority []resourceRecord
+	}
+)
+
+// parseQuery parses the header and (single) question of a DNS query.
+// The header is returned even if the question could not be parsed,
+// such that the error can still be reported to the client.
+func parseQuery(b []byte) (header, *question, error) {
+	if len(b) < headerLength {
+		return header{}, nil, errMessageTooShort
+	}
+	h := header{
+		ID:      binary.BigEndian.Uint16(b[0:]),
+		Flags:   binary.BigEndian.Uint16(b[2:]),
+		QDCount: binary.BigEndian.Uint16(b[4:]),
+		ANCount: binary.BigEndian.Uint16(b[6:]),
+		NSCount: binary.BigEndian.Uint16(b[8:]),
+		ARCount: binary.BigEndian.Uint16(b[10:]),
+	}
+	if h.Flags&flagResponse != 0 {
+		return h, nil, errNotAQuery
+	}
+	if h.QDCount != 1 {
+		return h, nil, errInvalidQuestion
+	}
+	name, offset, err := parseName(b, headerLength)
+	if err != nil {
+		return h, nil, err
+	}
+	if len(b) < offset+4 {
+		return h, nil, errMessageTooShort
+	}
+	return h, &question{
+		Name:  name,
+		Type:  binary.BigEndian.Uint16(b[offset:]),
+		Class: binary.BigEndian.Uint16(b[offset+2:]),
+	}, nil
+}
+
+// parseName parses an uncompressed DNS name, starting at the given offset,
+// returning it in lower case and without trailing dot, as well as the offset following the name.
+func parseName(b []byte, offset int) (string, int, error) {
+	var labels []string
+	length := 0
+	for {
+		if offset >= len(b) {
+			return "", 0, errMessageTooShort
+		}
+		n := int(b[offset])
+		offset++
+		if n == 0 {
+			break
+		}
+		if n&0xC0 != 0 {
+			return "", 0, errCompressedInQuery
+		}
+		if offset+n > len(b) {
+			return "", 0, errMessageTooShort
+		}
+		length += n + 1
+		if length > maxNameLength {
+			return "", 0, errNameTooLong
+		}
+		labels = append(labels, strings.ToLower(string(b[offset:offset+n])))
+		offset += n
+	}
+	return strings.Join(labels, "."), offset, nil
+}
+
+// appendName appends the given name, without trailing dot, in its uncompressed wire format.
+func appendName(b []byte, name string) ([]byte, error) {
+	if len(name) > 0 {
+		if len(name)+2 > maxNameLength {
+			return nil, errNameTooLong
+		}
+		for _, label := range strings.Split(name, ".") {
+			if len(label) == 0 || len(label) > maxLabelLength {
+				return nil, errInvalidLabel
+			}
+			b = append(b, byte(len(label)))
+			b = append(b, label...)
+		}
+	}
+	return append(b, 0), nil
+}
+
+// pack encodes the message into its wire format,
+// truncating it (dropping all records) should it exceed the given maximum length.
+func (m *message) pack(maxLength int) ([]byte, error) {
+	b, err := m.appendTo(make([]byte, 0, maxUDPMessageLength))
+	if err != nil {
+		return nil, err
+	}
+	if maxLength > 0 && len(b) > maxLength {
+		truncated := message{Header: m.Header, Question: m.Question}
+		truncated.Header.Flags |= flagTruncated
+		return truncated.appendTo(make([]byte, 0, maxUDPMessageLength))
+	}
+	return b, nil
+}
+
+func (m *message) appendTo(b []byte) ([]byte, error) {
+	h := m.Header
+	h.QDCount, h.ANCount, h.NSCount, h.ARCount = 0, uint16(len(m.Answers)), uint16(len(m.Authority)), 0
+	if m.Question != nil {
+		h.QDCount = 1
+	}
+	for _, v := range []uint16{h.ID, h.Flags, h.QDCount, h.ANCount, h.NSCount, h.ARCount} {
+		b = appendUint16(b, v)
+	}
+	var err error
+	if m.Question != nil {
+		b, err = appendName(b, m.Question.Name)
+		if err != nil {
+			return nil, err
+		}
+		b = appendUint16(b, m.Question.Type)
+		b = appendUint16(b, m.Question.Class)
+	}
+	for _, rrs := range [][]resourceRecord{m.Answers, m.Authority} {
+		for _, rr := range rrs {
+			b, err = appendName(b, rr.Name)
+			if err != nil {
+				return nil, err
+			}
+			b = appendUint16(b, rr.Type)
+			b = appendUint16(b, classINET)
+			b = appendUint32(b, rr.TTL)
+			b = appendUint16(b, uint16(len(rr.Data)))
+			b = append(b, rr.Data...)
+		}
+	}
+	return b, nil
+}
+
+// txtData encodes the given strings as the RDATA of a TXT record,
+// splitting each string that exceeds the maximum TXT string length.
+func txtData(strs ...string) []byte {
+	var b []byte
+	for _, str := range strs {
+		for len(str) > maxTXTStringLength {
+			b = append(b, maxTXTStringLength)
+			b = append(b, str[:maxTXTStringLength]...)
+			str = str[maxTXTStringLength:]
+		}
+		b = append(b, byte(len(str)))
+		b = append(b, str...)
+	}
+	return b
+}
+
+// soaData encodes the RDATA of a SOA record.
+func soaData(nameserver, mailbox string, serial, refresh, retry, expire, minimum uint32) ([]byte, error) {
+	b, err := appendName(nil, nameserver)
+	if err != nil {
+		return nil, err
+	}
+	b, err = appendName(b, mailbox)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range []uint32{serial, refresh, retry, expire, minimum} {
+		b = appendUint32(b, v)
+	}
+	return b, nil
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
@@ -0,0 +1,399 @@
+// Package botdns implements an authoritative DNS server,
+// resolving the names of (active) 3bots into the network addresses
+// registered in their records, within a single configurable zone.
+package botdns
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/threefoldfoundation/tfchain/pkg/types"
+	rivinetypes "github.com/threefoldtech/rivine/types"
+)
+
+const (
+	// DefaultZone is the zone served by default.
+	DefaultZone = "3bot"
+	// DefaultTTL is the TTL (in seconds) used by default for all served records,
+	// kept low as the records change as soon as blocks are applied or reverted.
+	DefaultTTL = 60
+
+	tcpTimeout = 10 * time.Second
+)
+
+// Registry is used to look up the 3bot record for a given name.
+// It should return types.ErrBotNameNotFound, types.ErrBotNameExpired or types.ErrBotNameInGracePeriod
+// in case no active 3bot owns the given name.
+type Registry interface {
+	GetRecordForName(name types.BotName) (*types.BotRecord, error)
+}
+
+// Config defines the (optional) configuration of a DNS Server.
+type Config struct {
+	// Zone served by this server, DefaultZone is used if not defined.
+	Zone string
+	// TTL (in seconds) of all served records, DefaultTTL is used if not defined.
+	TTL uint32
+	// Nameserver is the primary nameserver used for the SOA record of the zone,
+	// "ns.<zone>" is used if not defined.
+	Nameserver string
+	// Mailbox of the person responsible for the zone, used for the SOA record of the zone,
+	// formatted as a DNS name, "hostmaster.<zone>" is used if not defined.
+	Mailbox string
+}
+
+// Server is an authoritative DNS server for a single zone,
+// resolving the names of active 3bots, using the given registry.
+// A and AAAA records are served using the IP addresses of a 3bot,
+// while a CNAME record is served using its first hostname, only if the 3bot has no IP addresses,
+// and a TXT record contains the 3bot's ID and public key.
+//
+// The registry is consulted for each query, and no answers are cached by the server itself,
+// such that answers are updated as soon as the registry is.
+type Server struct {
+	zone       string
+	ttl        uint32
+	nameserver string
+	mailbox    string
+	registry   Registry
+
+	// now returns the current time, used to check if a 3bot record is expired
+	now func() rivinetypes.Timestamp
+
+	mu        sync.Mutex
+	packet    net.PacketConn
+	listener  net.Listener
+	wg        sync.WaitGroup
+	closed    bool
+	closeOnce sync.Once
+}
+
+// NewServer creates a new (authoritative) DNS server for 3bot names.
+func NewServer(registry Registry, cfg Config) (*Server, error) {
+	if registry == nil {
+		return nil, errors.New("no 3bot registry given")
+	}
+	zone := strings.ToLower(strings.Trim(cfg.Zone, "."))
+	if zone == "" {
+		zone = DefaultZone
+	}
+	if _, err := appendName(nil, zone); err != nil {
+		return nil, fmt.Errorf("invalid zone %q: %v", cfg.Zone, err)
+	}
+	s := &Server{
+		zone:       zone,
+		ttl:        cfg.TTL,
+		nameserver: strings.Trim(cfg.Nameserver, "."),
+		mailbox:    strings.Trim(cfg.Mailbox, "."),
+		registry:   registry,
+		now:        rivinetypes.CurrentTimestamp,
+	}
+	if s.ttl == 0 {
+		s.ttl = DefaultTTL
+	}
+	if s.nameserver == "" {
+		s.nameserver = "ns." + zone
+	}
+	if s.mailbox == "" {
+		s.mailbox = "hostmaster." + zone
+	}
+	return s, nil
+}
+
+// Zone returns the zone served by this server.
+func (s *Server) Zone() string {
+	return s.zone
+}
+
+// ListenAndServe binds the given address, for both UDP and TCP,
+// and serves all DNS queries received on it in the background, until the server is closed.
+func (s *Server) ListenAndServe(address string) error {
+	packet, err := net.ListenPacket("udp", address)
+	if err != nil {
+		return fmt.Errorf("failed to bind UDP address %s: %v", address, err)
+	}
+	// bind the same port for TCP, which matters should the given port be 0
+	listener, err := net.Listen("tcp", packet.LocalAddr().String())
+	if err != nil {
+		packet.Close()
+		return fmt.Errorf("failed to bind TCP address %s: %v", address, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || s.packet != nil {
+		packet.Close()
+		listener.Close()
+		return errors.New("DNS server is already closed or serving")
+	}
+	s.packet, s.listener = packet, listener
+	s.wg.Add(2)
+	go s.serveUDP(packet)
+	go s.serveTCP(listener)
+	return nil
+}
+
+// Addr returns the (UDP) address this server is bound to,
+// or nil if the server is not serving.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.packet == nil {
+		return nil
+	}
+	return s.packet.LocalAddr()
+}
+
+// Close stops the server, waiting until all served queries are handled.
+func (s *Server) Close() (err error) {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		if s.packet != nil {
+			err = s.packet.Close()
+			if lerr := s.listener.Close(); err == nil {
+				err = lerr
+			}
+		}
+		s.mu.Unlock()
+		s.wg.Wait()
+	})
+	return
+}
+
+func (s *Server) serveUDP(conn net.PacketConn) {
+	defer s.wg.Done()
+	buf := make([]byte, 4096)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if s.isClosed() {
+				return
+			}
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			return
+		}
+		resp := s.handleQuery(buf[:n], maxUDPMessageLength)
+		if resp != nil {
+			conn.WriteTo(resp, addr)
+		}
+	}
+}
+
+func (s *Server) serveTCP(listener net.Listener) {
+	defer s.wg.Done()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return
+			}
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			return
+		}
+		s.wg.Add(1)
+		go s.serveTCPConn(conn)
+	}
+}
+
+func (s *Server) serveTCPConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer conn.Close()
+	var length [2]byte
+	for !s.isClosed() {
+		conn.SetDeadline(time.Now().Add(tcpTimeout))
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return
+		}
+		query := make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, query); err != nil {
+			return
+		}
+		resp := s.handleQuery(query, 0)
+		if resp == nil {
+			return
+		}
+		if _, err := conn.Write(append(appendUint16(nil, uint16(len(resp))), resp...)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// handleQuery handles a single (wire-encoded) DNS query,
+// returning the wire-encoded response, or nil if no response can be given.
+func (s *Server) handleQuery(query []byte, maxLength int) []byte {
+	h, q, err := parseQuery(query)
+	if err == errMessageTooShort && len(query) < headerLength {
+		return nil // not even a header to respond to
+	}
+	if h.Flags&flagResponse != 0 {
+		return nil // never respond to responses
+	}
+	resp := message{
+		Header: header{
+			ID:    h.ID,
+			Flags: flagResponse | (h.Flags & (maskOpcode | flagRecursion)),
+		},
+		Question: q,
+	}
+	switch {
+	case err != nil:
+		resp.Header.Flags |= rcodeFormatError
+	case h.Flags&maskOpcode != 0:
+		// only standard queries are supported
+		resp.Header.Flags |= rcodeNotImplemented
+	default:
+		var rcode uint16
+		rcode, resp.Answers, resp.Authority = s.answer(q)
+		resp.Header.Flags |= rcode
+		if rcode == rcodeSuccess || rcode == rcodeNameError {
+			resp.Header.Flags |= flagAuthoritative
+		}
+	}
+	b, err := resp.pack(maxLength)
+	if err != nil {
+		// should never happen, as all names are validated, but just in case
+		resp = message{Header: resp.Header, Question: q}
+		resp.Header.Flags = (resp.Header.Flags &^ 0xF &^ flagAuthoritative) | rcodeServerFailure
+		b, err = resp.pack(maxLength)
+		if err != nil {
+			return nil
+		}
+	}
+	return b
+}
+
+// answer resolves the given question, returning the response code,
+// as well as the records for the answer and authority sections.
+func (s *Server) answer(q *question) (uint16, []resourceRecord, []resourceRecord) {
+	if q.Class != classINET && q.Class != classANY {
+		return rcodeRefused, nil, nil
+	}
+	if q.Name == s.zone {
+		// apex of our zone, only the SOA record is served
+		soa, err := s.soaRecord()
+		if err != nil {
+			return rcodeServerFailure, nil, nil
+		}
+		if q.Type == typeSOA || q.Type == typeANY {
+			return rcodeSuccess, []resourceRecord{soa}, nil
+		}
+		return rcodeSuccess, nil, []resourceRecord{soa}
+	}
+	if !strings.HasSuffix(q.Name, "."+s.zone) {
+		// we are not authoritative for any name outside of our zone
+		return rcodeRefused, nil, nil
+	}
+
+	rcode, answers := s.resolveBotName(q)
+	if rcode != rcodeSuccess && rcode != rcodeNameError {
+		return rcode, nil, nil
+	}
+	if len(answers) > 0 {
+		return rcode, answers, nil
+	}
+	// negative answer, add SOA record for negative caching
+	soa, err := s.soaRecord()
+	if err != nil {
+		return rcodeServerFailure, nil, nil
+	}
+	return rcode, nil, []resourceRecord{soa}
+}
+
+func (s *Server) resolveBotName(q *question) (uint16, []resourceRecord) {
+	var name types.BotName
+	err := name.LoadString(strings.TrimSuffix(q.Name, "."+s.zone))
+	if err != nil {
+		return rcodeNameError, nil
+	}
+	record, err := s.registry.GetRecordForName(name)
+	switch err {
+	case nil:
+	case types.ErrBotNameNotFound, types.ErrBotNameExpired, types.ErrBotNameInGracePeriod:
+		return rcodeNameError, nil
+	default:
+		return rcodeServerFailure, nil
+	}
+	if record.IsExpired(s.now()) {
+		return rcodeNameError, nil
+	}
+
+	var (
+		ipv4, ipv6 []resourceRecord
+		cname      *resourceRecord
+	)
+	for _, addr := range record.Addresses.Addresses() {
+		switch addr.Type() {
+		case types.NetworkAddressIPv4:
+			ipv4 = append(ipv4, resourceRecord{Name: q.Name, Type: typeA, TTL: s.ttl, Data: addr.IP().To4()})
+		case types.NetworkAddressIPv6:
+			ipv6 = append(ipv6, resourceRecord{Name: q.Name, Type: typeAAAA, TTL: s.ttl, Data: addr.IP().To16()})
+		case types.NetworkAddressHostname:
+			if cname != nil {
+				continue // only a single CNAME record is allowed per name
+			}
+			data, err := appendName(nil, strings.ToLower(strings.TrimSuffix(addr.String(), ".")))
+			if err != nil {
+				continue
+			}
+			cname = &resourceRecord{Name: q.Name, Type: typeCNAME, TTL: s.ttl, Data: data}
+		}
+	}
+	txt := resourceRecord{
+		Name: q.Name,
+		Type: typeTXT,
+		TTL:  s.ttl,
+		Data: txtData("id="+record.ID.String(), "publickey="+record.PublicKey.String()),
+	}
+
+	var answers []resourceRecord
+	switch q.Type {
+	case typeA:
+		answers = ipv4
+		if len(answers) == 0 && len(ipv6) == 0 && cname != nil {
+			answers = []resourceRecord{*cname}
+		}
+	case typeAAAA:
+		answers = ipv6
+		if len(answers) == 0 && len(ipv4) == 0 && cname != nil {
+			answers = []resourceRecord{*cname}
+		}
+	case typeCNAME:
+		if len(ipv4) == 0 && len(ipv6) == 0 && cname != nil {
+			answers = []resourceRecord{*cname}
+		}
+	case typeTXT:
+		answers = []resourceRecord{txt}
+	case typeANY:
+		answers = append(append(ipv4, ipv6...), txt)
+		if len(ipv4) == 0 && len(ipv6) == 0 && cname != nil {
+			answers = append(answers, *cname)
+		}
+	}
+	return rcodeSuccess, answers
+}
+
+func (s *Server) soaRecord() (resourceRecord, error) {
+	// the zone changes with every block, hence the current time is used as serial
+	data, err := soaData(s.nameserver, s.mailbox, uint32(s.now()), 3600, 600, 86400, s.ttl)
+	if err != nil {
+		return resourceRecord{}, err
+	}
+	return resourceRecord{Name: s.zone, Type: typeSOA, TTL: s.ttl, Data: data}, nil
+}
@@ -0,0 +1,286 @@
+package botdns
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/threefoldfoundation/tfchain/pkg/types"
+	rivinetypes "github.com/threefoldtech/rivine/types"
+)
+
+func TestServerAnswers(t *testing.T) {
+	srv := newTestServer(t)
+
+	testCases := []struct {
+		Name    string
+		Type    uint16
+		RCode   uint16
+		Answers []resourceRecord
+	}{
+		// IP addresses are served as A and AAAA records
+		{"mybot.3bot", typeA, rcodeSuccess, []resourceRecord{
+			{Name: "mybot.3bot", Type: typeA, TTL: DefaultTTL, Data: []byte{93, 184, 216, 34}},
+		}},
+		{"MyBot.3Bot", typeAAAA, rcodeSuccess, []resourceRecord{
+			{Name: "mybot.3bot", Type: typeAAAA, TTL: DefaultTTL, Data: net.ParseIP("2001:db8::1").To16()},
+		}},
+		// the ID and public key are served as a TXT record
+		{"mybot.3bot", typeTXT, rcodeSuccess, []resourceRecord{
+			{Name: "mybot.3bot", Type: typeTXT, TTL: DefaultTTL, Data: txtData(
+				"id=1", "publickey=ed25519:00bde9571b30e1742c41fcca8c730183402d967df5b17b5f4ced22c677806614")},
+		}},
+		// hostnames are served as CNAME records,
+		// also for A queries when no IP addresses are registered
+		{"sub.hostbot.3bot", typeA, rcodeNameError, nil},
+		{"hello.hostbot.3bot", typeA, rcodeSuccess, []resourceRecord{
+			{Name: "hello.hostbot.3bot", Type: typeCNAME, TTL: DefaultTTL, Data: mustAppendName(t, "example.org")},
+		}},
+		// names registered by no or an expired bot do not exist
+		{"unknown.3bot", typeA, rcodeNameError, nil},
+		{"expiredbot.3bot", typeA, rcodeNameError, nil},
+		// invalid bot names do not exist either
+		{"a.3bot", typeA, rcodeNameError, nil},
+		// existing names without records of the requested type have no answers
+		{"hello.hostbot.3bot", typeAAAA, rcodeSuccess, []resourceRecord{
+			{Name: "hello.hostbot.3bot", Type: typeCNAME, TTL: DefaultTTL, Data: mustAppendName(t, "example.org")},
+		}},
+		{"mybot.3bot", typeCNAME, rcodeSuccess, nil},
+		// names outside of the zone are refused
+		{"mybot.example.org", typeA, rcodeRefused, nil},
+	}
+	for idx, testCase := range testCases {
+		resp := srv.handleQuery(newTestQuery(t, uint16(idx), testCase.Name, testCase.Type), maxUDPMessageLength)
+		h, answers, authority := parseTestResponse(t, resp)
+		if h.ID != uint16(idx) {
+			t.Error(idx, "unexpected ID:", h.ID)
+		}
+		if rcode := h.Flags & 0xF; rcode != testCase.RCode {
+			t.Error(idx, "unexpected response code:", rcode, "!=", testCase.RCode)
+			continue
+		}
+		if len(answers) != len(testCase.Answers) {
+			t.Error(idx, "unexpected answers:", answers, "!=", testCase.Answers)
+			continue
+		}
+		for aidx, answer := range answers {
+			expected := testCase.Answers[aidx]
+			if answer.Name != expected.Name || answer.Type != expected.Type || answer.TTL != expected.TTL || !bytes.Equal(answer.Data, expected.Data) {
+				t.Error(idx, aidx, "unexpected answer:", answer, "!=", expected)
+			}
+		}
+		// negative answers within our zone contain the SOA record
+		if testCase.RCode != rcodeRefused && len(testCase.Answers) == 0 {
+			if len(authority) != 1 || authority[0].Type != typeSOA || authority[0].Name != "3bot" {
+				t.Error(idx, "unexpected authority:", authority)
+			}
+		}
+	}
+}
+
+func TestServerUpdatesWithRegistry(t *testing.T) {
+	srv := newTestServer(t)
+	registry := srv.registry.(*testRegistry)
+
+	query := newTestQuery(t, 1, "newbot.3bot", typeA)
+	h, _, _ := parseTestResponse(t, srv.handleQuery(query, maxUDPMessageLength))
+	if h.Flags&0xF != rcodeNameError {
+		t.Fatal("unexpected response code for unregistered name:", h.Flags&0xF)
+	}
+
+	// the answer changes as soon as the registry does (e.g. as a block is applied)
+	registry.records["newbot"] = testBotRecord(t, `{
+	"id": 4,
+	"addresses": ["10.0.0.1"],
+	"names": ["newbot"],
+	"publickey": "ed25519:11bde9571b30e1742c41fcca8c730183402d967df5b17b5f4ced22c677806614",
+	"expiration": 1700000000
+}`)
+	h, answers, _ := parseTestResponse(t, srv.handleQuery(query, maxUDPMessageLength))
+	if h.Flags&0xF != rcodeSuccess || len(answers) != 1 || !bytes.Equal(answers[0].Data, []byte{10, 0, 0, 1}) {
+		t.Fatal("unexpected response for registered name:", h.Flags&0xF, answers)
+	}
+
+	// as well as when the bot expires
+	srv.now = func() rivinetypes.Timestamp { return 1700000000 }
+	h, _, _ = parseTestResponse(t, srv.handleQuery(query, maxUDPMessageLength))
+	if h.Flags&0xF != rcodeNameError {
+		t.Fatal("unexpected response code for expired bot:", h.Flags&0xF)
+	}
+}
+
+func TestServerListenAndServe(t *testing.T) {
+	srv := newTestServer(t)
+	err := srv.ListenAndServe("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+
+	query := newTestQuery(t, 42, "mybot.3bot", typeA)
+
+	// query over UDP
+	conn, err := net.Dial("udp", srv.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_, err = conn.Write(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, maxUDPMessageLength)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, answers, _ := parseTestResponse(t, buf[:n])
+	if h.ID != 42 || h.Flags&flagAuthoritative == 0 || len(answers) != 1 {
+		t.Fatal("unexpected UDP response:", h, answers)
+	}
+
+	// query over TCP
+	tcpConn, err := net.Dial("tcp", srv.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tcpConn.Close()
+	_, err = tcpConn.Write(append(appendUint16(nil, uint16(len(query))), query...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var length [2]byte
+	_, err = tcpConn.Read(length[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf = make([]byte, binary.BigEndian.Uint16(length[:]))
+	_, err = tcpConn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, answers, _ = parseTestResponse(t, buf)
+	if h.ID != 42 || len(answers) != 1 {
+		t.Fatal("unexpected TCP response:", h, answers)
+	}
+}
+
+type testRegistry struct {
+	records map[string]types.BotRecord
+}
+
+func (reg *testRegistry) GetRecordForName(name types.BotName) (*types.BotRecord, error) {
+	record, ok := reg.records[name.String()]
+	if !ok {
+		return nil, types.ErrBotNameNotFound
+	}
+	return &record, nil
+}
+
+func newTestServer(t *testing.T) *Server {
+	srv, err := NewServer(&testRegistry{
+		records: map[string]types.BotRecord{
+			"mybot": testBotRecord(t, `{
+	"id": 1,
+	"addresses": ["93.184.216.34", "2001:db8::1", "example.com"],
+	"names": ["mybot"],
+	"publickey": "ed25519:00bde9571b30e1742c41fcca8c730183402d967df5b17b5f4ced22c677806614",
+	"expiration": 1600000000
+}`),
+			"hello.hostbot": testBotRecord(t, `{
+	"id": 2,
+	"addresses": ["example.org"],
+	"names": ["hello.hostbot"],
+	"publickey": "ed25519:01bde9571b30e1742c41fcca8c730183402d967df5b17b5f4ced22c677806614",
+	"expiration": 1600000000
+}`),
+			"expiredbot": testBotRecord(t, `{
+	"id": 3,
+	"addresses": ["93.184.216.35"],
+	"names": ["expiredbot"],
+	"publickey": "ed25519:02bde9571b30e1742c41fcca8c730183402d967df5b17b5f4ced22c677806614",
+	"expiration": 1500000000
+}`),
+		},
+	}, Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.now = func() rivinetypes.Timestamp { return 1550000000 }
+	return srv
+}
+
+func testBotRecord(t *testing.T, str string) types.BotRecord {
+	var record types.BotRecord
+	err := json.Unmarshal([]byte(str), &record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return record
+}
+
+func mustAppendName(t *testing.T, name string) []byte {
+	b, err := appendName(nil, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func newTestQuery(t *testing.T, id uint16, name string, qtype uint16) []byte {
+	b := appendUint16(nil, id)
+	b = appendUint16(b, flagRecursion)
+	b = append(b, 0, 1, 0, 0, 0, 0, 0, 0)
+	for _, label := range strings.Split(name, ".") {
+		b = append(b, byte(len(label)))
+		b = append(b, label...)
+	}
+	b = append(b, 0)
+	b = appendUint16(b, qtype)
+	return appendUint16(b, classINET)
+}
+
+// parseTestResponse parses a response as packed by this server (without compression)
+func parseTestResponse(t *testing.T, b []byte) (header, []resourceRecord, []resourceRecord) {
+	t.Helper()
+	h, _, err := parseQuery(append(append([]byte{}, b[:2]...), append([]byte{0, 0}, b[4:]...)...))
+	if err != nil {
+		t.Fatal("failed to parse response header and question:", err)
+	}
+	h.Flags = binary.BigEndian.Uint16(b[2:])
+	if h.Flags&flagResponse == 0 {
+		t.Fatal("message is not a response")
+	}
+	_, offset, err := parseName(b, headerLength)
+	if err != nil {
+		t.Fatal(err)
+	}
+	offset += 4
+	parseRecords := func(count uint16) []resourceRecord {
+		var rrs []resourceRecord
+		for i := uint16(0); i < count; i++ {
+			var rr resourceRecord
+			rr.Name, offset, err = parseName(b, offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr.Type = binary.BigEndian.Uint16(b[offset:])
+			rr.TTL = binary.BigEndian.Uint32(b[offset+4:])
+			length := int(binary.BigEndian.Uint16(b[offset+8:]))
+			offset += 10
+			rr.Data = b[offset : offset+length]
+			offset += length
+			rrs = append(rrs, rr)
+		}
+		return rrs
+	}
+	answers := parseRecords(h.ANCount)
+	authority := parseRecords(h.NSCount)
+	if offset != len(b) {
+		t.Fatal("unexpected trailing bytes in response")
+	}
+	return h, answers, authority
+}
@@ -0,0 +1,82 @@
+// Package capacity provides a (simulated) node, which can respond
+// to the capacity challenges issued by the tfchain network,
+// proving that it is still online and provides the capacity registered for it.
+package capacity
+
+import (
+	"bytes"
+	"errors"
+
+	tftypes "github.com/threefoldfoundation/tfchain/pkg/types"
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// Node is a node identified by an ed25519 key pair,
+// able to respond to the capacity challenges issued to it.
+type Node struct {
+	sk       crypto.SecretKey
+	pk       types.PublicKey
+	capacity tftypes.CapacitySpecification
+}
+
+// NewNode creates a new node, identified by the given secret key,
+// reporting the given capacity when responding to a challenge.
+func NewNode(sk crypto.SecretKey, capacity tftypes.CapacitySpecification) *Node {
+	return &Node{
+		sk:       sk,
+		pk:       types.Ed25519PublicKey(sk.PublicKey()),
+		capacity: capacity,
+	}
+}
+
+// PublicKey returns the public key that identifies this node.
+func (n *Node) PublicKey() types.PublicKey {
+	return n.pk
+}
+
+// Capacity returns the capacity this node reports when responding to a challenge.
+func (n *Node) Capacity() tftypes.CapacitySpecification {
+	return n.capacity
+}
+
+// SetCapacity sets the capacity this node reports when responding to a challenge,
+// which can for example be used to simulate a node that lost part of its capacity.
+func (n *Node) SetCapacity(capacity tftypes.CapacitySpecification) {
+	n.capacity = capacity
+}
+
+// Challenge returns the challenge issued to this node by the challenge block
+// identified by the given height and ID.
+func (n *Node) Challenge(height types.BlockHeight, blockID types.BlockID) tftypes.CapacityChallenge {
+	return tftypes.NewCapacityChallenge(height, blockID, n.pk)
+}
+
+// Respond creates a capacity proof transaction, responding to the given challenge,
+// reporting the current capacity of this node. The node fulfillment is signed by this node,
+// while the coin inputs, used to pay the given transaction fee, still have to be signed by the caller.
+func (n *Node) Respond(challenge tftypes.CapacityChallenge, fee types.Currency, coinInputs []types.CoinInput, refund *types.CoinOutput) (types.Transaction, error) {
+	if challenge.Node.Algorithm != n.pk.Algorithm || !bytes.Equal(challenge.Node.Key, n.pk.Key) {
+		return types.Transaction{}, errors.New("challenge was issued to another node")
+	}
+	cptx := tftypes.CapacityProofTransaction{
+		Node:             n.pk,
+		ChallengeHeight:  challenge.BlockHeight,
+		Capacity:         n.capacity,
+		NodeFulfillment:  types.NewFulfillment(types.NewSingleSignatureFulfillment(n.pk)),
+		TransactionFee:   fee,
+		CoinInputs:       coinInputs,
+		RefundCoinOutput: refund,
+	}
+	tx := cptx.Transaction()
+	extension := tx.Extension.(*tftypes.CapacityProofTransactionExtension)
+	err := extension.NodeFulfillment.Sign(types.FulfillmentSignContext{
+		ExtraObjects: []interface{}{tftypes.CapacityProofSignatureSpecifier, challenge.Seed},
+		Transaction:  tx,
+		Key:          n.sk,
+	})
+	if err != nil {
+		return types.Transaction{}, err
+	}
+	return tx, nil
+}
@@ -0,0 +1,383 @@
+package capacity
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/threefoldfoundation/tfchain/pkg/config"
+	"github.com/threefoldfoundation/tfchain/pkg/persist"
+	tftypes "github.com/threefoldfoundation/tfchain/pkg/types"
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestNodeCapacityChallenges(t *testing.T) {
+	chain := newSimulatedChain(t)
+	defer chain.close()
+
+	capacity := tftypes.CapacitySpecification{CRU: 4, MRU: 16, HRU: 2000, SRU: 250}
+	node := NewNode(newTestSecretKey(2), capacity)
+
+	// register a farm and the capacity of the node
+	chain.mineBlock(chain.newFarmRegistrationTransaction("myfarm"))
+	chain.mineBlock(chain.newCapacityRegistrationTransaction(1, node.PublicKey(), capacity))
+	chain.mineBlocksUntil(tftypes.CapacityProofChallengeInterval)
+
+	// respond to the first challenge
+	challenge := chain.challenge(node)
+	chain.mineBlock(chain.respond(node, challenge))
+	result := chain.result(node, challenge.BlockHeight)
+	if result == nil || !result.Passed || result.Capacity != capacity {
+		t.Fatal("unexpected result for first challenge:", result)
+	}
+
+	// a response can only be given once
+	_, err := chain.validate(chain.respond(node, challenge))
+	if err == nil {
+		t.Fatal("succeeded to respond twice to the same challenge")
+	}
+
+	// respond to the second challenge, reporting less capacity than registered
+	chain.mineBlocksUntil(2 * tftypes.CapacityProofChallengeInterval)
+	node.SetCapacity(tftypes.CapacitySpecification{CRU: 2, MRU: 16, HRU: 2000, SRU: 250})
+	challenge = chain.challenge(node)
+	chain.mineBlock(chain.respond(node, challenge))
+	result = chain.result(node, challenge.BlockHeight)
+	if result == nil || result.Passed {
+		t.Fatal("unexpected result for second challenge:", result)
+	}
+	if !chain.verified(node) {
+		t.Fatal("node lost its verified status after a single failure")
+	}
+
+	// do not respond to the third challenge, until its window is closed
+	chain.mineBlocksUntil(3 * tftypes.CapacityProofChallengeInterval)
+	node.SetCapacity(capacity)
+	challenge = chain.challenge(node)
+	chain.mineBlocksUntil(3*tftypes.CapacityProofChallengeInterval + tftypes.CapacityProofResponseWindow)
+	if result = chain.result(node, challenge.BlockHeight); result != nil {
+		t.Fatal("unexpected result while challenge window is still open:", result)
+	}
+	chain.mineBlock()
+	result = chain.result(node, challenge.BlockHeight)
+	if result == nil || result.Passed || result.TransactionID != (types.TransactionID{}) {
+		t.Fatal("unexpected result for third challenge:", result)
+	}
+	_, err = chain.validate(chain.respond(node, challenge))
+	if err == nil {
+		t.Fatal("succeeded to respond to a closed challenge")
+	}
+	if !chain.verified(node) {
+		t.Fatal("node lost its verified status after two failures")
+	}
+
+	// do not respond to the fourth challenge either, losing the verified status
+	chain.mineBlocksUntil(4*tftypes.CapacityProofChallengeInterval + tftypes.CapacityProofResponseWindow + 1)
+	if chain.verified(node) {
+		t.Fatal("node is still verified after three consecutive failures")
+	}
+
+	// reverting the block that closed the window, should revert the failure
+	chain.revertBlocks(1)
+	if !chain.verified(node) {
+		t.Fatal("node is not verified after the failure is reverted")
+	}
+	if result = chain.result(node, 4*tftypes.CapacityProofChallengeInterval); result != nil {
+		t.Fatal("unexpected result for reverted challenge:", result)
+	}
+	chain.mineBlock()
+
+	// pass the fifth challenge, regaining the verified status
+	chain.mineBlocksUntil(5 * tftypes.CapacityProofChallengeInterval)
+	challenge = chain.challenge(node)
+	chain.mineBlocks(tftypes.CapacityProofResponseWindow - 1)
+	chain.mineBlock(chain.respond(node, challenge))
+	if !chain.verified(node) {
+		t.Fatal("node did not regain its verified status")
+	}
+
+	// reverting the block of the response, should revert the result
+	chain.revertBlocks(1)
+	if result = chain.result(node, challenge.BlockHeight); result != nil {
+		t.Fatal("unexpected result for reverted response:", result)
+	}
+	// reverting the challenge block, should revert the challenge
+	chain.revertBlocks(tftypes.CapacityProofResponseWindow)
+	_, err = chain.txdb.GetCapacityChallenge(challenge.BlockHeight, node.PublicKey())
+	if err != tftypes.ErrCapacityChallengeNotFound {
+		t.Fatal("unexpected error for reverted challenge:", err)
+	}
+}
+
+func TestNodeRespondToChallengeOfOtherNode(t *testing.T) {
+	capacity := tftypes.CapacitySpecification{CRU: 1}
+	nodeA := NewNode(newTestSecretKey(2), capacity)
+	nodeB := NewNode(newTestSecretKey(3), capacity)
+	_, err := nodeA.Respond(nodeB.Challenge(720, types.BlockID{1}), types.NewCurrency64(1), nil, nil)
+	if err == nil {
+		t.Fatal("node responded to a challenge issued to another node")
+	}
+}
+
+// simulatedChain is a minimal in-memory chain, feeding its blocks
+// to a TransactionDB as if it were a real consensus set.
+type simulatedChain struct {
+	modules.ConsensusSet
+
+	t             *testing.T
+	dir           string
+	txdb          *persist.TransactionDB
+	subscriber    modules.ConsensusSetSubscriber
+	blocks        []types.Block
+	key           crypto.SecretKey
+	coinInputs    uint64
+	constants     types.TransactionValidationConstants
+	minimumTxFee  types.Currency
+	registrations []types.TransactionVersion
+}
+
+func newSimulatedChain(t *testing.T) *simulatedChain {
+	chain := &simulatedChain{
+		t:   t,
+		key: newTestSecretKey(1),
+	}
+	genesis := config.GetDevnetGenesis()
+	chain.minimumTxFee = genesis.MinimumTransactionFee
+	chain.constants = types.TransactionValidationConstants{
+		BlockSizeLimit:         genesis.BlockSizeLimit,
+		ArbitraryDataSizeLimit: genesis.ArbitraryDataSizeLimit,
+		MinimumMinerFee:        genesis.MinimumTransactionFee,
+	}
+
+	var err error
+	chain.dir, err = ioutil.TempDir("", "tfchain-capacity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.txdb, err = persist.NewTransactionDB(chain.dir, chain.condition(), tftypes.GetDevnetGenesisConsensusParameters())
+	if err != nil {
+		os.RemoveAll(chain.dir)
+		t.Fatal(err)
+	}
+	chain.register(tftypes.TransactionVersionFarmRegistration, tftypes.FarmRegistrationTransactionController{
+		FoundationConditionGetter: chain.txdb,
+		Registry:                  chain.txdb,
+	})
+	chain.register(tftypes.TransactionVersionCapacityRegistration, tftypes.CapacityRegistrationTransactionController{
+		FarmerConditionGetter: chain.txdb,
+	})
+	chain.register(tftypes.TransactionVersionCapacityProof, tftypes.CapacityProofTransactionController{
+		Registry: chain.txdb,
+	})
+	err = chain.txdb.SubscribeToConsensusSet(chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// apply the genesis block
+	chain.mineBlock()
+	return chain
+}
+
+func (chain *simulatedChain) register(version types.TransactionVersion, controller types.TransactionController) {
+	types.RegisterTransactionVersion(version, controller)
+	chain.registrations = append(chain.registrations, version)
+}
+
+func (chain *simulatedChain) close() {
+	for _, version := range chain.registrations {
+		types.RegisterTransactionVersion(version, nil)
+	}
+	err := chain.txdb.Close()
+	if err != nil {
+		chain.t.Error(err)
+	}
+	os.RemoveAll(chain.dir)
+}
+
+// ConsensusSetSubscribe implements modules.ConsensusSet.ConsensusSetSubscribe
+func (chain *simulatedChain) ConsensusSetSubscribe(subscriber modules.ConsensusSetSubscriber, start modules.ConsensusChangeID, cancel <-chan struct{}) error {
+	chain.subscriber = subscriber
+	return nil
+}
+
+// Unsubscribe implements modules.ConsensusSet.Unsubscribe
+func (chain *simulatedChain) Unsubscribe(subscriber modules.ConsensusSetSubscriber) {
+	chain.subscriber = nil
+}
+
+// height returns the height of the current block
+func (chain *simulatedChain) height() types.BlockHeight {
+	return types.BlockHeight(len(chain.blocks) - 1)
+}
+
+func (chain *simulatedChain) condition() types.UnlockConditionProxy {
+	return types.NewCondition(types.NewUnlockHashCondition(
+		types.NewPubKeyUnlockHash(types.Ed25519PublicKey(chain.key.PublicKey()))))
+}
+
+// validate signs the coin inputs of the given transaction,
+// and validates it as part of the next block
+func (chain *simulatedChain) validate(tx types.Transaction) (types.Transaction, error) {
+	for idx := range tx.CoinInputs {
+		err := tx.CoinInputs[idx].Fulfillment.Sign(types.FulfillmentSignContext{
+			ExtraObjects: []interface{}{uint64(idx)},
+			Transaction:  tx,
+			Key:          chain.key,
+		})
+		if err != nil {
+			return tx, err
+		}
+	}
+	return tx, tx.ValidateTransaction(types.ValidationContext{
+		Confirmed:   true,
+		BlockHeight: chain.height() + 1,
+		BlockTime:   types.Timestamp(chain.height() + 1),
+	}, chain.constants)
+}
+
+// mineBlock validates the given transactions and
+// applies a new block containing them to the TransactionDB
+func (chain *simulatedChain) mineBlock(txs ...types.Transaction) {
+	block := types.Block{
+		Timestamp: types.Timestamp(len(chain.blocks)),
+	}
+	if len(chain.blocks) > 0 {
+		block.ParentID = chain.blocks[len(chain.blocks)-1].ID()
+	}
+	for _, tx := range txs {
+		tx, err := chain.validate(tx)
+		if err != nil {
+			chain.t.Fatalf("invalid transaction at height %d: %v", len(chain.blocks), err)
+		}
+		block.Transactions = append(block.Transactions, tx)
+	}
+	chain.blocks = append(chain.blocks, block)
+	chain.subscriber.ProcessConsensusChange(modules.ConsensusChange{
+		ID:            modules.ConsensusChangeID(block.ID()),
+		AppliedBlocks: []types.Block{block},
+		Synced:        true,
+	})
+}
+
+func (chain *simulatedChain) mineBlocks(n int) {
+	for i := 0; i < n; i++ {
+		chain.mineBlock()
+	}
+}
+
+// mineBlocksUntil mines empty blocks until the given height is reached
+func (chain *simulatedChain) mineBlocksUntil(height types.BlockHeight) {
+	for chain.height() < height {
+		chain.mineBlock()
+	}
+}
+
+// revertBlocks reverts the last n blocks from the TransactionDB
+func (chain *simulatedChain) revertBlocks(n int) {
+	reverted := make([]types.Block, 0, n)
+	for i := len(chain.blocks) - 1; i >= len(chain.blocks)-n; i-- {
+		reverted = append(reverted, chain.blocks[i])
+	}
+	chain.blocks = chain.blocks[:len(chain.blocks)-n]
+	chain.subscriber.ProcessConsensusChange(modules.ConsensusChange{
+		ID:             modules.ConsensusChangeID(chain.blocks[len(chain.blocks)-1].ID()),
+		RevertedBlocks: reverted,
+		Synced:         true,
+	})
+}
+
+func (chain *simulatedChain) challenge(node *Node) tftypes.CapacityChallenge {
+	challenge, err := chain.txdb.GetCapacityChallenge(chain.height(), node.PublicKey())
+	if err != nil {
+		chain.t.Fatal(err)
+	}
+	expected := node.Challenge(chain.height(), chain.blocks[chain.height()].ID())
+	if challenge.Seed != expected.Seed {
+		chain.t.Fatal("node derived another challenge than the TransactionDB:", expected, "!=", challenge)
+	}
+	return challenge
+}
+
+func (chain *simulatedChain) respond(node *Node, challenge tftypes.CapacityChallenge) types.Transaction {
+	tx, err := node.Respond(challenge, chain.minimumTxFee, chain.newCoinInputs(), nil)
+	if err != nil {
+		chain.t.Fatal(err)
+	}
+	return tx
+}
+
+func (chain *simulatedChain) result(node *Node, height types.BlockHeight) *tftypes.CapacityProofResult {
+	result, err := chain.txdb.GetCapacityProofResultForNode(node.PublicKey(), height)
+	if err == tftypes.ErrCapacityProofResultNotFound {
+		return nil
+	}
+	if err != nil {
+		chain.t.Fatal(err)
+	}
+	return result
+}
+
+func (chain *simulatedChain) verified(node *Node) bool {
+	results, err := chain.txdb.GetCapacityProofResultsForNode(node.PublicKey())
+	if err != nil {
+		chain.t.Fatal(err)
+	}
+	return tftypes.CapacityProofVerified(results)
+}
+
+func (chain *simulatedChain) newFarmRegistrationTransaction(name string) types.Transaction {
+	frtx := tftypes.FarmRegistrationTransaction{
+		Name:                  name,
+		Owner:                 chain.condition(),
+		AuthorizedAddresses:   []types.UnlockHash{chain.condition().UnlockHash()},
+		FoundationFulfillment: types.NewFulfillment(types.NewSingleSignatureFulfillment(types.Ed25519PublicKey(chain.key.PublicKey()))),
+		TransactionFee:        chain.minimumTxFee,
+		CoinInputs:            chain.newCoinInputs(),
+	}
+	return chain.signExtension(frtx.Transaction())
+}
+
+func (chain *simulatedChain) newCapacityRegistrationTransaction(farm tftypes.FarmID, node types.PublicKey, capacity tftypes.CapacitySpecification) types.Transaction {
+	crtx := tftypes.CapacityRegistrationTransaction{
+		Farm:              farm,
+		Node:              node,
+		Capacity:          capacity,
+		FarmerFulfillment: types.NewFulfillment(types.NewSingleSignatureFulfillment(types.Ed25519PublicKey(chain.key.PublicKey()))),
+		TransactionFee:    chain.minimumTxFee,
+		CoinInputs:        chain.newCoinInputs(),
+	}
+	return chain.signExtension(crtx.Transaction())
+}
+
+func (chain *simulatedChain) signExtension(tx types.Transaction) types.Transaction {
+	err := tx.SignExtension(func(fulfillment *types.UnlockFulfillmentProxy, condition types.UnlockConditionProxy, eo ...interface{}) error {
+		return fulfillment.Sign(types.FulfillmentSignContext{
+			ExtraObjects: eo,
+			Transaction:  tx,
+			Key:          chain.key,
+		})
+	})
+	if err != nil {
+		chain.t.Fatal(err)
+	}
+	return tx
+}
+
+func (chain *simulatedChain) newCoinInputs() []types.CoinInput {
+	chain.coinInputs++
+	return []types.CoinInput{
+		{
+			ParentID:    types.CoinOutputID(crypto.HashObject(fmt.Sprintf("coin input #%d", chain.coinInputs))),
+			Fulfillment: types.NewFulfillment(types.NewSingleSignatureFulfillment(types.Ed25519PublicKey(chain.key.PublicKey()))),
+		},
+	}
+}
+
+func newTestSecretKey(seed byte) crypto.SecretKey {
+	sk, _ := crypto.GenerateKeyPairDeterministic([crypto.EntropySize]byte{seed})
+	return sk
+}
@@ -8,8 +8,20 @@ import (
 type DaemonNetworkConfig struct {
 	FoundationPoolAddress types.UnlockHash
 	ERC20FeePoolAddress   types.UnlockHash
+	// BurnCondition is the optional burn-authorization condition,
+	// which has to be fulfilled by every coin burn transaction if defined.
+	// If undefined (nil) anyone can burn their own coins.
+	BurnCondition types.UnlockConditionProxy
+	// MintingCapWindow is the rolling window, in seconds of block time,
+	// to which the minting cap applies. Minting caps are disabled if it is 0.
+	// No minting cap applies until one is defined by a minting cap definition transaction.
+	MintingCapWindow types.Timestamp
 }
 
+// DefaultMintingCapWindow is the rolling window (of 30 days)
+// to which the minting cap applies on all official networks.
+const DefaultMintingCapWindow = types.Timestamp(30 * 24 * 60 * 60)
+
 // GetStandardDaemonNetworkConfig returns the standard network config for the daemon
 func GetStandardDaemonNetworkConfig() DaemonNetworkConfig {
 	return DaemonNetworkConfig{
@@ -17,6 +29,7 @@ func GetStandardDaemonNetworkConfig() DaemonNetworkConfig {
 		FoundationPoolAddress: unlockHashFromHex("017267221ef1947bb18506e390f1f9446b995acfb6d08d8e39508bb974d9830b8cb8fdca788e34"),
 		// TODO: define final address
 		ERC20FeePoolAddress: unlockHashFromHex("017267221ef1947bb18506e390f1f9446b995acfb6d08d8e39508bb974d9830b8cb8fdca788e34"),
+		MintingCapWindow:    DefaultMintingCapWindow,
 	}
 }
 
@@ -27,6 +40,7 @@ func GetTestnetDaemonNetworkConfig() DaemonNetworkConfig {
 		FoundationPoolAddress: unlockHashFromHex("016148ac9b17828e0933796eaca94418a376f2aa3fefa15685cea5fa462093f0150e09067f7512"),
 		// TODO: define final address
 		ERC20FeePoolAddress: unlockHashFromHex("016148ac9b17828e0933796eaca94418a376f2aa3fefa15685cea5fa462093f0150e09067f7512"),
+		MintingCapWindow:    DefaultMintingCapWindow,
 	}
 }
 
@@ -39,5 +53,6 @@ func GetDevnetDaemonNetworkConfig() DaemonNetworkConfig {
 		// belongs to wallet with mnemonic:
 		// carbon boss inject cover mountain fetch fiber fit tornado cloth wing dinosaur proof joy intact fabric thumb rebel borrow poet chair network expire else
 		ERC20FeePoolAddress: unlockHashFromHex("015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e6791584fbdac553e6f"),
+		MintingCapWindow:    DefaultMintingCapWindow,
 	}
 }
@@ -0,0 +1,231 @@
+package erc20
+
+import (
+	"fmt"
+	"net/http"
+
+	tfchaintypes "github.com/threefoldfoundation/tfchain/pkg/types"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/threefoldtech/rivine/pkg/api"
+	"github.com/threefoldtech/rivine/types"
+)
+
+type (
+	// BridgeGetWithdraws contains the requested withdraws (ERC20 -> TFT conversions).
+	BridgeGetWithdraws struct {
+		Withdraws []Withdraw `json:"withdraws"`
+	}
+	// BridgeGetWithdraw contains a requested withdraw (ERC20 -> TFT conversion).
+	BridgeGetWithdraw struct {
+		Withdraw Withdraw `json:"withdraw"`
+	}
+
+	// BridgeGetActions contains the requested ETH actions (e.g. TFT -> ERC20 conversions).
+	BridgeGetActions struct {
+		Actions []Action `json:"actions"`
+	}
+	// BridgeGetAction contains a requested ETH action (e.g. a TFT -> ERC20 conversion).
+	BridgeGetAction struct {
+		Action Action `json:"action"`
+	}
+)
+
+// RegisterBridgeHTTPHandlers registers the handlers for all Bridge HTTP endpoints.
+//
+// The endpoints which force the bridge to retry or skip a withdraw or action
+// require the given password, and are disabled should no password be given.
+func RegisterBridgeHTTPHandlers(router api.Router, bridge *Bridge, requiredPassword string) {
+	if bridge == nil {
+		panic("no bridge given")
+	}
+	if router == nil {
+		panic("no httprouter Router given")
+	}
+
+	router.GET("/bridge/withdraws", NewBridgeGetWithdrawsHandler(bridge))
+	router.GET("/bridge/withdraws/:id", NewBridgeGetWithdrawHandler(bridge))
+	router.POST("/bridge/withdraws/:id/retry", requireForcePassword(NewBridgeRetryWithdrawHandler(bridge), requiredPassword))
+	router.POST("/bridge/withdraws/:id/skip", requireForcePassword(NewBridgeSkipWithdrawHandler(bridge), requiredPassword))
+
+	router.GET("/bridge/actions", NewBridgeGetActionsHandler(bridge))
+	router.GET("/bridge/actions/:txid", NewBridgeGetActionHandler(bridge))
+	router.POST("/bridge/actions/:txid/retry", requireForcePassword(NewBridgeRetryActionHandler(bridge), requiredPassword))
+	router.POST("/bridge/actions/:txid/skip", requireForcePassword(NewBridgeSkipActionHandler(bridge), requiredPassword))
+}
+
+// requireForcePassword ensures that the given handler can only be used
+// if the bridge API is password-protected, and the correct password is given.
+func requireForcePassword(h httprouter.Handle, requiredPassword string) httprouter.Handle {
+	if requiredPassword == "" {
+		return func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+			api.WriteError(w, api.Error{Message: "forced bridge actions require the bridge API to be password protected (--authenticate-api)"}, http.StatusForbidden)
+		}
+	}
+	return api.RequirePasswordHandler(h, requiredPassword)
+}
+
+// NewBridgeGetWithdrawsHandler creates a handler to handle the API calls to /bridge/withdraws?state=.
+// By default only the withdraws which are still pending are returned,
+// the state query parameter can be used to filter on a specific state instead, or `all` to return all withdraws.
+func NewBridgeGetWithdrawsHandler(bridge *Bridge) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		filter := func(wd Withdraw) bool { return wd.State.Pending() }
+		switch stateStr := req.FormValue("state"); stateStr {
+		case "", "pending":
+		case "all":
+			filter = nil
+		default:
+			var state WithdrawState
+			err := state.LoadString(stateStr)
+			if err != nil {
+				api.WriteError(w, api.Error{Message: fmt.Sprintf("invalid state given: %v", err)}, http.StatusBadRequest)
+				return
+			}
+			filter = func(wd Withdraw) bool { return wd.State == state }
+		}
+		withdraws, err := bridge.withdraws.List(filter)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, http.StatusInternalServerError)
+			return
+		}
+		api.WriteJSON(w, BridgeGetWithdraws{Withdraws: withdraws})
+	}
+}
+
+// NewBridgeGetWithdrawHandler creates a handler to handle the API calls to /bridge/withdraws/:id,
+// where the id is either the ERC20 transaction hash or the ID of the tfchain coin creation transaction.
+func NewBridgeGetWithdrawHandler(bridge *Bridge) httprouter.Handle {
+	return func(w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
+		idStr := ps.ByName("id")
+		var (
+			wd  Withdraw
+			err error
+		)
+		if tfchaintypes.IsERC20Hash(idStr) {
+			var txHash tfchaintypes.ERC20Hash
+			err = txHash.LoadString(idStr)
+			if err != nil {
+				api.WriteError(w, api.Error{Message: fmt.Sprintf("invalid ERC20 transaction hash given: %v", err)}, http.StatusBadRequest)
+				return
+			}
+			wd, err = bridge.GetWithdraw(txHash)
+		} else {
+			var txID types.TransactionID
+			err = txID.LoadString(idStr)
+			if err != nil {
+				api.WriteError(w, api.Error{Message: "id has to be a valid ERC20 transaction hash or tfchain transaction ID"}, http.StatusBadRequest)
+				return
+			}
+			wd, err = bridge.GetWithdrawForTFTTransactionID(txID)
+		}
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, bridgeErrorAsHTTPStatusCode(err))
+			return
+		}
+		api.WriteJSON(w, BridgeGetWithdraw{Withdraw: wd})
+	}
+}
+
+// NewBridgeRetryWithdrawHandler creates a handler to handle the API calls to /bridge/withdraws/:id/retry,
+// where the id is the ERC20 transaction hash.
+func NewBridgeRetryWithdrawHandler(bridge *Bridge) httprouter.Handle {
+	return newBridgeForceWithdrawHandler(bridge.RetryWithdraw)
+}
+
+// NewBridgeSkipWithdrawHandler creates a handler to handle the API calls to /bridge/withdraws/:id/skip,
+// where the id is the ERC20 transaction hash.
+func NewBridgeSkipWithdrawHandler(bridge *Bridge) httprouter.Handle {
+	return newBridgeForceWithdrawHandler(bridge.SkipWithdraw)
+}
+
+func newBridgeForceWithdrawHandler(force func(tfchaintypes.ERC20Hash) (Withdraw, error)) httprouter.Handle {
+	return func(w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
+		var txHash tfchaintypes.ERC20Hash
+		err := txHash.LoadString(ps.ByName("id"))
+		if err != nil {
+			api.WriteError(w, api.Error{Message: fmt.Sprintf("invalid ERC20 transaction hash given: %v", err)}, http.StatusBadRequest)
+			return
+		}
+		wd, err := force(txHash)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, bridgeErrorAsHTTPStatusCode(err))
+			return
+		}
+		api.WriteJSON(w, BridgeGetWithdraw{Withdraw: wd})
+	}
+}
+
+// NewBridgeGetActionsHandler creates a handler to handle the API calls to /bridge/actions?state=.
+// By default only the actions which are still pending are returned,
+// the state query parameter can be used to filter on a specific state instead, or `all` to return all actions.
+func NewBridgeGetActionsHandler(bridge *Bridge) httprouter.Handle {
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		filter := func(a Action) bool { return a.State == ActionStatePending }
+		switch stateStr := req.FormValue("state"); stateStr {
+		case "":
+		case "all":
+			filter = nil
+		default:
+			var state ActionState
+			err := state.UnmarshalText([]byte(stateStr))
+			if err != nil {
+				api.WriteError(w, api.Error{Message: fmt.Sprintf("invalid state given: %v", err)}, http.StatusBadRequest)
+				return
+			}
+			filter = func(a Action) bool { return a.State == state }
+		}
+		actions, err := bridge.outbox.List(filter)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, http.StatusInternalServerError)
+			return
+		}
+		api.WriteJSON(w, BridgeGetActions{Actions: actions})
+	}
+}
+
+// NewBridgeGetActionHandler creates a handler to handle the API calls to /bridge/actions/:txid,
+// where the txid is the ID of the tfchain transaction which resulted in the action.
+func NewBridgeGetActionHandler(bridge *Bridge) httprouter.Handle {
+	return newBridgeActionHandler(bridge.GetAction)
+}
+
+// NewBridgeRetryActionHandler creates a handler to handle the API calls to /bridge/actions/:txid/retry,
+// where the txid is the ID of the tfchain transaction which resulted in the action.
+func NewBridgeRetryActionHandler(bridge *Bridge) httprouter.Handle {
+	return newBridgeActionHandler(bridge.RetryAction)
+}
+
+// NewBridgeSkipActionHandler creates a handler to handle the API calls to /bridge/actions/:txid/skip,
+// where the txid is the ID of the tfchain transaction which resulted in the action.
+func NewBridgeSkipActionHandler(bridge *Bridge) httprouter.Handle {
+	return newBridgeActionHandler(bridge.SkipAction)
+}
+
+func newBridgeActionHandler(f func(types.TransactionID) (Action, error)) httprouter.Handle {
+	return func(w http.ResponseWriter, _ *http.Request, ps httprouter.Params) {
+		var txID types.TransactionID
+		err := txID.LoadString(ps.ByName("txid"))
+		if err != nil {
+			api.WriteError(w, api.Error{Message: fmt.Sprintf("invalid transaction ID given: %v", err)}, http.StatusBadRequest)
+			return
+		}
+		action, err := f(txID)
+		if err != nil {
+			api.WriteError(w, api.Error{Message: err.Error()}, bridgeErrorAsHTTPStatusCode(err))
+			return
+		}
+		api.WriteJSON(w, BridgeGetAction{Action: action})
+	}
+}
+
+func bridgeErrorAsHTTPStatusCode(err error) int {
+	switch err {
+	case ErrWithdrawNotFound, ErrActionNotFound:
+		return http.StatusNotFound
+	case ErrWithdrawConfirmed, ErrWithdrawNotPending, ErrActionDone, ErrActionNotPending:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,42 @@
+package erc20
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestRegisterBridgeHTTPHandlers(t *testing.T) {
+	// registering should not panic due to conflicting routes
+	RegisterBridgeHTTPHandlers(httprouter.New(), &Bridge{}, "foo")
+}
+
+func TestRequireForcePassword(t *testing.T) {
+	var called bool
+	h := func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+		called = true
+	}
+
+	// without a password configured, forced actions are disabled
+	rec := httptest.NewRecorder()
+	requireForcePassword(h, "")(rec, httptest.NewRequest("POST", "/bridge/actions/foo/retry", nil), nil)
+	if called || rec.Code != http.StatusForbidden {
+		t.Errorf("expected forced action to be forbidden, got status %d (called: %v)", rec.Code, called)
+	}
+
+	// with a password configured, the password is required
+	rec = httptest.NewRecorder()
+	requireForcePassword(h, "foo")(rec, httptest.NewRequest("POST", "/bridge/actions/foo/retry", nil), nil)
+	if called || rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected forced action to be unauthorized, got status %d (called: %v)", rec.Code, called)
+	}
+	req := httptest.NewRequest("POST", "/bridge/actions/foo/retry", nil)
+	req.SetBasicAuth("", "foo")
+	rec = httptest.NewRecorder()
+	requireForcePassword(h, "foo")(rec, req, nil)
+	if !called {
+		t.Errorf("expected forced action to be allowed, got status %d", rec.Code)
+	}
+}
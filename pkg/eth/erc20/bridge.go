@@ -26,6 +26,11 @@ const (
 	EthBlockDelay = 30
 )
 
+// SubmittedWithdrawTimeout is the time a submitted withdraw's coin creation transaction
+// can be missing from both the transaction pool and the transaction db,
+// before it is considered lost and submitted again.
+const SubmittedWithdrawTimeout = 30 * time.Minute
+
 // Bridge is a high lvl structure which listens on contract events and bridge-related
 // tfchain transactions, and handles them
 type Bridge struct {
@@ -36,12 +41,22 @@ type Bridge struct {
 	persistDir string
 	persist    persistence
 	buffer     *blockBuffer
+	withdraws  *withdrawStore
+
+	// blocks which left the buffer, but could not be processed yet
+	unprocessed []unprocessedBlock
+
+	outbox       *outbox
+	outboxSignal chan struct{}
+	outboxMut    sync.Mutex // protects read-modify-write updates of outbox actions
 
 	bcInfo   types.BlockchainInfo
 	chainCts types.ChainConstants
 
 	bridgeContract *BridgeContract
 
+	metrics *bridgeMetrics
+
 	mut sync.Mutex
 }
 
@@ -60,6 +75,7 @@ func NewBridge(cs modules.ConsensusSet, txdb *persist.TransactionDB, tp modules.
 		bcInfo:         bcInfo,
 		chainCts:       chainCts,
 		bridgeContract: contract,
+		metrics:        newBridgeMetrics(),
 	}
 
 	err = bridge.initPersist()
@@ -67,7 +83,19 @@ func NewBridge(cs modules.ConsensusSet, txdb *persist.TransactionDB, tp modules.
 		return nil, errors.New("bridge persistence startup failed: " + err.Error())
 	}
 
+	bridge.withdraws, err = openWithdrawStore(datadir)
+	if err != nil {
+		return nil, errors.New("bridge withdraw store startup failed: " + err.Error())
+	}
+	bridge.outbox, err = openOutbox(datadir)
+	if err != nil {
+		bridge.withdraws.Close()
+		return nil, errors.New("bridge outbox startup failed: " + err.Error())
+	}
+	bridge.outboxSignal = make(chan struct{}, 1)
+
 	bridge.buffer = newBlockBuffer(TFTBlockDelay)
+	bridge.metrics.tfchainHeight.Set(float64(bridge.persist.Height))
 
 	return bridge, nil
 }
@@ -95,9 +123,35 @@ func (bridge *Bridge) Close() error {
 	defer bridge.mut.Unlock()
 	err := bridge.bridgeContract.Close()
 	bridge.cs.Unsubscribe(bridge)
+	if werr := bridge.withdraws.Close(); werr != nil && err == nil {
+		err = werr
+	}
+	if oerr := bridge.outbox.Close(); oerr != nil && err == nil {
+		err = oerr
+	}
 	return err
 }
 
+// GetWithdraws returns all withdraws known by the bridge,
+// sorted by the ETH block height they were created at.
+func (bridge *Bridge) GetWithdraws() ([]Withdraw, error) {
+	return bridge.withdraws.List(nil)
+}
+
+// GetPendingWithdraws returns all withdraws which still have to be
+// (or are in the process of being) converted into TFT by the bridge.
+func (bridge *Bridge) GetPendingWithdraws() ([]Withdraw, error) {
+	return bridge.withdraws.List(func(w Withdraw) bool {
+		return w.State.Pending()
+	})
+}
+
+// GetWithdraw returns the withdraw known for the given ERC20 transaction hash,
+// ErrWithdrawNotFound is returned in case the bridge does not know this withdraw.
+func (bridge *Bridge) GetWithdraw(txHash tfchaintypes.ERC20Hash) (Withdraw, error) {
+	return bridge.withdraws.Get(txHash)
+}
+
 func (bridge *Bridge) mint(receiver tfchaintypes.ERC20Address, amount types.Currency, txID types.TransactionID) error {
 	// check if we already know this ID
 	known, err := bridge.bridgeContract.IsMintTxID(txID.String())
@@ -111,9 +165,7 @@ func (bridge *Bridge) mint(receiver tfchaintypes.ERC20Address, amount types.Curr
 	return bridge.bridgeContract.Mint(receiver, amount.Big(), txID.String())
 }
 
-func (bridge *Bridge) registerWithdrawalAddress(key types.PublicKey) error {
-	// convert public key to unlockhash to eth address
-	erc20addr := tfchaintypes.ERC20AddressFromUnlockHash(types.NewPubKeyUnlockHash(key))
+func (bridge *Bridge) registerWithdrawalAddress(erc20addr tfchaintypes.ERC20Address) error {
 	// check if we already know this withdraw address
 	known, err := bridge.bridgeContract.IsWithdrawalAddress(erc20addr)
 	if err != nil {
@@ -126,6 +178,199 @@ func (bridge *Bridge) registerWithdrawalAddress(key types.PublicKey) error {
 	return bridge.bridgeContract.RegisterWithdrawalAddress(erc20addr)
 }
 
+// executeAction executes the given action on the ETH network.
+// Executing an action which was already executed is a no-op.
+func (bridge *Bridge) executeAction(action Action) error {
+	switch action.Type {
+	case ActionTypeMint:
+		return bridge.mint(action.Address, action.Amount, action.TFTTransactionID)
+	case ActionTypeRegisterWithdrawalAddress:
+		return bridge.registerWithdrawalAddress(action.Address)
+	default:
+		return fmt.Errorf("unknown action type %s", action.Type.String())
+	}
+}
+
+// processOutbox executes all actions in the outbox which are due,
+// continuing with the remaining actions should one of them fail.
+func (bridge *Bridge) processOutbox() {
+	now := time.Now()
+	actions, err := bridge.outbox.List(func(a Action) bool {
+		return a.Due(now)
+	})
+	if err != nil {
+		log.Error("Failed to list due actions in outbox", "err", err)
+		return
+	}
+	for _, listed := range actions {
+		// the operator might have skipped (or retried) the action since it was listed
+		action, due := bridge.getDueAction(listed.TFTTransactionID, now)
+		if !due {
+			continue
+		}
+		err = bridge.executeAction(action)
+		bridge.metrics.countAction(action, err)
+		bridge.outboxMut.Lock()
+		if current, gerr := bridge.outbox.Get(action.TFTTransactionID); err != nil && gerr == nil && current.State != ActionStatePending {
+			// the operator changed the state of the action while we were executing it
+			bridge.outboxMut.Unlock()
+			continue
+		}
+		if err != nil {
+			action.markFailed(err, time.Now())
+			if action.State == ActionStateDead {
+				log.Error("Action failed too many times, giving up", "type", action.Type.String(), "txid", action.TFTTransactionID.String(), "attempts", action.Attempts, "err", err)
+			} else {
+				log.Error("Action failed, retrying later", "type", action.Type.String(), "txid", action.TFTTransactionID.String(), "attempts", action.Attempts, "err", err)
+			}
+		} else {
+			action.State, action.LastError = ActionStateDone, ""
+			log.Info("Executed action on eth network", "type", action.Type.String(), "txid", action.TFTTransactionID.String())
+		}
+		if err := bridge.outbox.Update(&action); err != nil {
+			log.Error("Failed to update action in outbox", "txid", action.TFTTransactionID.String(), "err", err)
+		}
+		bridge.outboxMut.Unlock()
+	}
+}
+
+// getDueAction returns the current state of the action for the given tfchain transaction ID,
+// as stored in the outbox, and whether or not that action is (still) due.
+func (bridge *Bridge) getDueAction(txID types.TransactionID, now time.Time) (Action, bool) {
+	bridge.outboxMut.Lock()
+	defer bridge.outboxMut.Unlock()
+	action, err := bridge.outbox.Get(txID)
+	if err != nil {
+		log.Error("Failed to get action from outbox", "txid", txID.String(), "err", err)
+		return Action{}, false
+	}
+	return action, action.Due(now)
+}
+
+// GetActions returns all ETH actions known by the bridge,
+// sorted by the tfchain block height of the transaction they originate from.
+func (bridge *Bridge) GetActions() ([]Action, error) {
+	return bridge.outbox.List(nil)
+}
+
+// GetAction returns the ETH action for the given tfchain transaction ID,
+// ErrActionNotFound is returned in case the bridge does not know this action.
+func (bridge *Bridge) GetAction(txID types.TransactionID) (Action, error) {
+	return bridge.outbox.Get(txID)
+}
+
+// GetWithdrawForTFTTransactionID returns the withdraw which was converted into TFT
+// using the coin creation transaction with the given ID,
+// ErrWithdrawNotFound is returned in case the bridge does not know such a withdraw.
+func (bridge *Bridge) GetWithdrawForTFTTransactionID(txID types.TransactionID) (Withdraw, error) {
+	withdraws, err := bridge.withdraws.List(func(w Withdraw) bool {
+		return w.TFTTransactionID == txID
+	})
+	if err != nil {
+		return Withdraw{}, err
+	}
+	if len(withdraws) == 0 {
+		return Withdraw{}, ErrWithdrawNotFound
+	}
+	return withdraws[0], nil
+}
+
+// RetryWithdraw forces the bridge to process the withdraw with the given ERC20 transaction hash again,
+// even if it failed or was skipped. A withdraw which is already confirmed cannot be retried.
+func (bridge *Bridge) RetryWithdraw(txHash tfchaintypes.ERC20Hash) (Withdraw, error) {
+	bridge.mut.Lock()
+	defer bridge.mut.Unlock()
+	w, err := bridge.withdraws.Get(txHash)
+	if err != nil {
+		return Withdraw{}, err
+	}
+	if w.State == WithdrawStateConfirmed {
+		return w, ErrWithdrawConfirmed
+	}
+	// it will be marked as matured again at the next ETH head
+	w.State, w.LastError = WithdrawStateSeen, ""
+	err = bridge.withdraws.Update(&w)
+	if err != nil {
+		return w, err
+	}
+	log.Info("Withdraw will be retried, as forced by the operator", "txHash", w.TxHash.String())
+	return w, nil
+}
+
+// SkipWithdraw forces the bridge to no longer process the pending withdraw with the given ERC20 transaction hash,
+// marking it as failed instead.
+func (bridge *Bridge) SkipWithdraw(txHash tfchaintypes.ERC20Hash) (Withdraw, error) {
+	bridge.mut.Lock()
+	defer bridge.mut.Unlock()
+	w, err := bridge.withdraws.Get(txHash)
+	if err != nil {
+		return Withdraw{}, err
+	}
+	if !w.State.Pending() {
+		return w, ErrWithdrawNotPending
+	}
+	bridge.markWithdrawFailed(&w, errSkippedByOperator)
+	log.Info("Withdraw skipped, as forced by the operator", "txHash", w.TxHash.String())
+	return w, nil
+}
+
+// RetryAction forces the bridge to execute the ETH action for the given tfchain transaction ID again,
+// as soon as possible, even if it was moved to the dead state. An action which is already done cannot be retried.
+func (bridge *Bridge) RetryAction(txID types.TransactionID) (Action, error) {
+	bridge.outboxMut.Lock()
+	defer bridge.outboxMut.Unlock()
+	action, err := bridge.outbox.Get(txID)
+	if err != nil {
+		return Action{}, err
+	}
+	if action.State == ActionStateDone {
+		return action, ErrActionDone
+	}
+	action.State, action.Attempts, action.NextAttempt, action.LastError = ActionStatePending, 0, 0, ""
+	err = bridge.outbox.Update(&action)
+	if err != nil {
+		return action, err
+	}
+	log.Info("Action will be retried, as forced by the operator", "type", action.Type.String(), "txid", action.TFTTransactionID.String())
+	select {
+	case bridge.outboxSignal <- struct{}{}:
+	default:
+	}
+	return action, nil
+}
+
+// SkipAction forces the bridge to no longer execute the pending ETH action for the given tfchain transaction ID,
+// moving it to the dead state instead.
+func (bridge *Bridge) SkipAction(txID types.TransactionID) (Action, error) {
+	bridge.outboxMut.Lock()
+	defer bridge.outboxMut.Unlock()
+	action, err := bridge.outbox.Get(txID)
+	if err != nil {
+		return Action{}, err
+	}
+	if action.State != ActionStatePending {
+		return action, ErrActionNotPending
+	}
+	action.State, action.LastError = ActionStateDead, errSkippedByOperator.Error()
+	err = bridge.outbox.Update(&action)
+	if err != nil {
+		return action, err
+	}
+	log.Info("Action skipped, as forced by the operator", "type", action.Type.String(), "txid", action.TFTTransactionID.String())
+	return action, nil
+}
+
+// Errors returned when forcing the bridge to retry or skip
+// a withdraw or action which is in a state that doesn't allow it.
+var (
+	ErrWithdrawConfirmed  = errors.New("withdraw is already confirmed")
+	ErrWithdrawNotPending = errors.New("withdraw is no longer pending")
+	ErrActionDone         = errors.New("action is already done")
+	ErrActionNotPending   = errors.New("action is no longer pending")
+)
+
+var errSkippedByOperator = errors.New("skipped by operator")
+
 // GetClient returns bridgecontract lightclient
 func (bridge *Bridge) GetClient() *LightClient {
 	return bridge.bridgeContract.LightClient()
@@ -139,6 +384,21 @@ func (bridge *Bridge) Start(cs modules.ConsensusSet, txdb *persist.TransactionDB
 	}
 	log.Info("Subscribed to tfchain consensus set")
 
+	// process the actions in our outbox, for as long as the bridge is running
+	go func() {
+		ticker := time.NewTicker(outboxPollInterval)
+		defer ticker.Stop()
+		for {
+			bridge.processOutbox()
+			select {
+			case <-cancel:
+				return
+			case <-ticker.C:
+			case <-bridge.outboxSignal:
+			}
+		}
+	}()
+
 	heads := make(chan *ethtypes.Header)
 
 	go bridge.bridgeContract.Loop(heads)
@@ -148,70 +408,47 @@ func (bridge *Bridge) Start(cs modules.ConsensusSet, txdb *persist.TransactionDB
 	go bridge.bridgeContract.SubscribeMint()
 	go bridge.bridgeContract.SubscribeRegisterWithdrawAddress()
 
+	// log all withdraws we still have to process from a previous run,
+	// these will be processed again as soon as we receive a new head
+	pending, err := bridge.GetPendingWithdraws()
+	if err != nil {
+		return fmt.Errorf("bridged: failed to load pending withdraws: %v", err)
+	}
+	for _, w := range pending {
+		log.Info("Replaying pending withdraw", "txHash", w.TxHash.String(), "height", w.BlockHeight, "state", w.State.String())
+	}
+
 	withdrawChan := make(chan WithdrawEvent)
 	go bridge.bridgeContract.SubscribeWithdraw(withdrawChan, bridge.persist.EthHeight)
 	go func() {
-		txMap := make(map[tfchaintypes.ERC20Hash]WithdrawEvent)
 		for {
 			select {
 			// Remember new withdraws
 			case we := <-withdrawChan:
-				// Check if the withdraw is valid
-				_, found, err := txdb.GetTFTAddressForERC20Address(tfchaintypes.ERC20Address(we.receiver))
+				_, added, err := bridge.withdraws.Add(newWithdrawFromEvent(we))
 				if err != nil {
-					log.Error(fmt.Sprintf("Retrieving TFT address for registered ERC20 address %v errored: %v", we.receiver, err))
+					log.Error("Failed to store withdraw event", "txHash", we.TxHash(), "err", err)
 					continue
 				}
-				if !found {
-					log.Error(fmt.Sprintf("Failed to retrieve TFT address for registered ERC20 Withdrawal address %v", we.receiver))
+				if !added {
+					// already known (e.g. seen again as we rescan from a lagging height)
 					continue
 				}
-				// remember the withdraw
-				txMap[tfchaintypes.ERC20Hash(we.txHash)] = we
+				// Check if the withdraw is valid,
+				// it is only marked as failed once matured, as our txdb might still be lagging behind
+				_, found, err := txdb.GetTFTAddressForERC20Address(tfchaintypes.ERC20Address(we.receiver))
+				if err != nil {
+					log.Error(fmt.Sprintf("Retrieving TFT address for registered ERC20 address %v errored: %v", we.receiver, err))
+				} else if !found {
+					log.Warn(fmt.Sprintf("Failed to retrieve TFT address for registered ERC20 Withdrawal address %v", we.receiver))
+				}
 				log.Info("Remembering withdraw event", "txHash", we.TxHash(), "height", we.BlockHeight())
 
 			// If we get a new head, check every withdraw we have to see if it has matured
 			case head := <-heads:
 				bridge.mut.Lock()
-				for id := range txMap {
-					we := txMap[id]
-					if head.Number.Uint64() >= we.blockHeight+EthBlockDelay {
-						log.Info("Attempting to create an ERC20 withdraw tx", "ethTx", we.TxHash())
-						// we waited long enough, create transaction and push it
-						uh, found, err := txdb.GetTFTAddressForERC20Address(tfchaintypes.ERC20Address(we.receiver))
-						if err != nil {
-							log.Error(fmt.Sprintf("Retrieving TFT address for registered ERC20 address %v errored: %v", we.receiver, err))
-							continue
-						}
-						if !found {
-							log.Error(fmt.Sprintf("Failed to retrieve TFT address for registered ERC20 Withdrawal address %v", we.receiver))
-							continue
-						}
-
-						tx := tfchaintypes.ERC20CoinCreationTransaction{}
-						tx.Address = uh
-
-						// define the txFee
-						tx.TransactionFee = bridge.chainCts.MinimumTransactionFee
-
-						// define the value, which is the value withdrawn minus the fees
-						tx.Value = types.NewCurrency(we.amount).Sub(tx.TransactionFee)
-
-						// fill in the other info
-						tx.TransactionID = tfchaintypes.ERC20Hash(we.txHash)
-						tx.BlockID = tfchaintypes.ERC20Hash(we.blockHash)
-
-						if err := bridge.commitWithdrawTransaction(tx); err != nil {
-							log.Error("Failed to create ERC20 Withdraw transaction", "err", err)
-							continue
-						}
-
-						log.Info("Created ERC20 -> TFT transaction", "txid", tx.Transaction().ID())
-
-						// forget about our tx
-						delete(txMap, id)
-					}
-				}
+				bridge.processMaturedWithdraws(txdb, head.Number.Uint64())
+				bridge.metrics.observeHead(head.Number.Uint64())
 
 				bridge.persist.EthHeight = head.Number.Uint64() - EthBlockDelay
 				// Check for underflow
@@ -228,3 +465,145 @@ func (bridge *Bridge) Start(cs modules.ConsensusSet, txdb *persist.TransactionDB
 	}()
 	return nil
 }
+
+// processMaturedWithdraws creates and commits a coin creation transaction
+// for all pending withdraws which matured at the given ETH block height.
+func (bridge *Bridge) processMaturedWithdraws(txdb *persist.TransactionDB, height uint64) {
+	withdraws, err := bridge.withdraws.List(func(w Withdraw) bool {
+		return w.State.Pending() && height >= w.BlockHeight+EthBlockDelay
+	})
+	if err != nil {
+		log.Error("Failed to list pending withdraws", "err", err)
+		return
+	}
+	for _, w := range withdraws {
+		if w.State == WithdrawStateSeen {
+			w.State = WithdrawStateMatured
+			if err := bridge.withdraws.Update(&w); err != nil {
+				log.Error("Failed to update withdraw state", "txHash", w.TxHash.String(), "err", err)
+			}
+		}
+		// the withdraw might already be converted, by an earlier submission
+		// or (while syncing) by this bridge during a previous lifetime
+		txID, found, err := txdb.GetTFTTransactionIDForERC20TransactionID(w.TxHash)
+		if err != nil {
+			log.Error("Failed to look up TFT transaction for withdraw", "txHash", w.TxHash.String(), "err", err)
+			continue
+		}
+		if found {
+			if err := bridge.confirmWithdraw(w.TxHash, txID); err != nil {
+				log.Error("Failed to update withdraw state", "txHash", w.TxHash.String(), "err", err)
+			}
+			continue
+		}
+		// a submitted withdraw is only submitted again once its transaction got lost
+		if w.State == WithdrawStateSubmitted {
+			if !bridge.isSubmittedWithdrawLost(w, time.Now()) {
+				continue
+			}
+			log.Warn("Coin creation transaction of submitted withdraw got lost, submitting it again",
+				"ethTx", w.TxHash.String(), "txid", w.TFTTransactionID.String())
+		}
+
+		log.Info("Attempting to create an ERC20 withdraw tx", "ethTx", w.TxHash.String())
+		// we waited long enough, create transaction and push it
+		uh, found, err := txdb.GetTFTAddressForERC20Address(w.Receiver)
+		if err != nil {
+			log.Error(fmt.Sprintf("Retrieving TFT address for registered ERC20 address %v errored: %v", w.Receiver, err))
+			continue
+		}
+		if !found {
+			log.Error(fmt.Sprintf("Failed to retrieve TFT address for registered ERC20 Withdrawal address %v", w.Receiver))
+			bridge.markWithdrawFailed(&w, fmt.Errorf("no TFT address registered for ERC20 address %v", w.Receiver))
+			continue
+		}
+
+		tx := tfchaintypes.ERC20CoinCreationTransaction{}
+		tx.Address = uh
+
+		// define the txFee
+		tx.TransactionFee = bridge.chainCts.MinimumTransactionFee
+
+		// define the value, which is the value withdrawn minus the fees
+		tx.Value = w.Amount.Sub(tx.TransactionFee)
+
+		// fill in the other info
+		tx.TransactionID = w.TxHash
+		tx.BlockID = w.BlockHash
+
+		err = bridge.commitWithdrawTransaction(tx)
+		bridge.metrics.countWithdrawCommit(err)
+		if err != nil {
+			log.Error("Failed to create ERC20 Withdraw transaction", "err", err)
+			w.LastError = err.Error()
+			if err := bridge.withdraws.Update(&w); err != nil {
+				log.Error("Failed to update withdraw state", "txHash", w.TxHash.String(), "err", err)
+			}
+			continue
+		}
+
+		w.TFTTransactionID = tx.Transaction().ID()
+		log.Info("Created ERC20 -> TFT transaction", "txid", w.TFTTransactionID)
+
+		// remember we submitted our tx,
+		// it will be marked as confirmed once we see it in a tfchain block
+		w.State, w.LastError = WithdrawStateSubmitted, ""
+		if err := bridge.withdraws.Update(&w); err != nil {
+			log.Error("Failed to update withdraw state", "txHash", w.TxHash.String(), "err", err)
+		}
+	}
+}
+
+// markWithdrawFailed marks a withdraw as failed, storing the reason why.
+func (bridge *Bridge) markWithdrawFailed(w *Withdraw, reason error) {
+	w.State, w.LastError = WithdrawStateFailed, reason.Error()
+	if err := bridge.withdraws.Update(w); err != nil {
+		log.Error("Failed to update withdraw state", "txHash", w.TxHash.String(), "err", err)
+	}
+}
+
+// confirmWithdraw marks the withdraw, linked to the given ERC20 transaction hash, as confirmed,
+// should the bridge know about it.
+func (bridge *Bridge) confirmWithdraw(txHash tfchaintypes.ERC20Hash, txID types.TransactionID) error {
+	w, err := bridge.withdraws.Get(txHash)
+	if err == ErrWithdrawNotFound {
+		// not (yet) known by this bridge, this can happen while syncing,
+		// in which case it will be confirmed once it matured
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if w.State == WithdrawStateConfirmed {
+		return nil
+	}
+	w.State, w.TFTTransactionID, w.LastError = WithdrawStateConfirmed, txID, ""
+	return bridge.withdraws.Update(&w)
+}
+
+// unconfirmWithdraw moves the withdraw, linked to the given ERC20 transaction hash,
+// back to the matured state, should it be confirmed by the given (reverted) coin creation transaction.
+func (bridge *Bridge) unconfirmWithdraw(txHash tfchaintypes.ERC20Hash, txID types.TransactionID) error {
+	w, err := bridge.withdraws.Get(txHash)
+	if err == ErrWithdrawNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if w.State != WithdrawStateConfirmed || w.TFTTransactionID != txID {
+		return nil
+	}
+	w.State, w.TFTTransactionID, w.LastError = WithdrawStateMatured, types.TransactionID{}, ""
+	return bridge.withdraws.Update(&w)
+}
+
+// isSubmittedWithdrawLost returns true if the coin creation transaction of the given submitted withdraw,
+// not found in the transaction db, is not in the transaction pool either,
+// and the withdraw was not updated for at least the SubmittedWithdrawTimeout.
+func (bridge *Bridge) isSubmittedWithdrawLost(w Withdraw, now time.Time) bool {
+	if _, err := bridge.tp.Transaction(w.TFTTransactionID); err == nil {
+		return false
+	}
+	return now.Sub(time.Unix(w.LastUpdate, 0)) >= SubmittedWithdrawTimeout
+}
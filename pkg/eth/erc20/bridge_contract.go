@@ -169,6 +169,15 @@ func (bridge *BridgeContract) Refresh(head *types.Header) error {
 	return nil
 }
 
+// CachedStats returns the head, balance and gas price as cached by the last successful Refresh,
+// nil values are returned in case no refresh has succeeded yet.
+func (bridge *BridgeContract) CachedStats() (head *types.Header, balance *big.Int, price *big.Int) {
+	bridge.lock.RLock()
+	head, balance, price = bridge.head, bridge.balance, bridge.price
+	bridge.lock.RUnlock()
+	return
+}
+
 // Loop subscribes to new eth heads. If a new head is received, it is passed on the given channel,
 // after which the internal stats are updated if no update is already in progress
 func (bridge *BridgeContract) Loop(ch chan<- *types.Header) {
@@ -269,6 +269,11 @@ func (lc *LightClient) Synchronising() bool {
 	return downloader != nil && downloader.Synchronising()
 }
 
+// PeerCount returns the amount of peers the ethereum client is connected to
+func (lc *LightClient) PeerCount() int {
+	return lc.stack.Server().PeerCount()
+}
+
 // IsNoPeerErr checks if an error is means an ethereum client could not execute
 // a call because it has no valid peers
 func IsNoPeerErr(err error) bool {
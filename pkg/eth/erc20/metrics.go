@@ -0,0 +1,172 @@
+package erc20
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/threefoldfoundation/tfchain/pkg/metrics"
+)
+
+// labels used for the result of the bridge counters
+const (
+	metricsResultSuccess = "success"
+	metricsResultFailure = "failure"
+)
+
+// bridgeMetrics groups all metrics which are updated by the bridge as it processes
+// the tfchain and ETH chains, the metrics which can be computed at collection time
+// are registered as gauge functions instead, see (*Bridge).RegisterMetrics.
+type bridgeMetrics struct {
+	tfchainHeight *metrics.Gauge
+	ethHeight     *metrics.Gauge
+	lastHeadTime  *metrics.Gauge
+
+	actions   *metrics.CounterVec
+	withdraws *metrics.CounterVec
+}
+
+func newBridgeMetrics() *bridgeMetrics {
+	return &bridgeMetrics{
+		tfchainHeight: metrics.NewGauge(
+			"tfchain_bridge_tfchain_height",
+			"Height of the last tfchain block processed by the bridge."),
+		ethHeight: metrics.NewGauge(
+			"tfchain_bridge_eth_height",
+			"Height of the last ETH head processed by the bridge."),
+		lastHeadTime: metrics.NewGauge(
+			"tfchain_bridge_eth_last_head_timestamp_seconds",
+			"Time (unix epoch) at which the bridge processed its last ETH head, useful to detect a stalled bridge."),
+		actions: metrics.NewCounterVec(
+			"tfchain_bridge_actions_total",
+			"Amount of ETH actions (as a result of ERC20 conversions and address registrations) executed by the bridge.",
+			"type", "result"),
+		withdraws: metrics.NewCounterVec(
+			"tfchain_bridge_withdraws_committed_total",
+			"Amount of ERC20 coin creation transactions (as a result of ERC20 withdraws) committed by the bridge.",
+			"result"),
+	}
+}
+
+func (m *bridgeMetrics) observeHead(height uint64) {
+	m.ethHeight.Set(float64(height))
+	m.lastHeadTime.Set(float64(time.Now().Unix()))
+}
+
+func (m *bridgeMetrics) countAction(action Action, err error) {
+	m.actions.WithLabelValues(action.Type.String(), metricsResult(err)).Inc()
+}
+
+func (m *bridgeMetrics) countWithdrawCommit(err error) {
+	m.withdraws.WithLabelValues(metricsResult(err)).Inc()
+}
+
+func metricsResult(err error) string {
+	if err != nil {
+		return metricsResultFailure
+	}
+	return metricsResultSuccess
+}
+
+// RegisterMetrics registers all metrics of the bridge,
+// as well as those of its light client, to the given registry.
+func (bridge *Bridge) RegisterMetrics(reg *metrics.Registry) error {
+	err := reg.Register(
+		bridge.metrics.tfchainHeight,
+		bridge.metrics.ethHeight,
+		bridge.metrics.lastHeadTime,
+		bridge.metrics.actions,
+		bridge.metrics.withdraws,
+		metrics.NewGaugeFunc(
+			"tfchain_bridge_eth_balance_ether",
+			"ETH balance of the bridge account, as cached at the last processed ETH head.",
+			func() (float64, bool) {
+				_, balance, _ := bridge.bridgeContract.CachedStats()
+				if balance == nil {
+					return 0, false
+				}
+				return weiToFloat(balance, 1e18), true
+			}),
+		metrics.NewGaugeFunc(
+			"tfchain_bridge_eth_gas_price_gwei",
+			"Suggested ETH gas price, as cached at the last processed ETH head.",
+			func() (float64, bool) {
+				_, _, price := bridge.bridgeContract.CachedStats()
+				if price == nil {
+					return 0, false
+				}
+				return weiToFloat(price, 1e9), true
+			}),
+		metrics.NewGaugeFunc(
+			"tfchain_bridge_withdraws_pending",
+			"Amount of ERC20 withdraws which are not yet confirmed on the tfchain network.",
+			func() (float64, bool) {
+				withdraws, err := bridge.GetPendingWithdraws()
+				if err != nil {
+					return 0, false
+				}
+				return float64(len(withdraws)), true
+			}),
+		metrics.NewGaugeFunc(
+			"tfchain_bridge_actions_pending",
+			"Amount of ETH actions which still have to be (re)tried by the bridge.",
+			bridge.countActionsFunc(ActionStatePending)),
+		metrics.NewGaugeFunc(
+			"tfchain_bridge_actions_dead",
+			"Amount of ETH actions which failed too many times, requiring manual intervention.",
+			bridge.countActionsFunc(ActionStateDead)),
+	)
+	if err != nil {
+		return err
+	}
+	return bridge.bridgeContract.LightClient().RegisterMetrics(reg)
+}
+
+func (bridge *Bridge) countActionsFunc(state ActionState) func() (float64, bool) {
+	return func() (float64, bool) {
+		actions, err := bridge.outbox.List(func(a Action) bool {
+			return a.State == state
+		})
+		if err != nil {
+			return 0, false
+		}
+		return float64(len(actions)), true
+	}
+}
+
+// RegisterMetrics registers the peer count and sync status of the light client to the given registry.
+func (lc *LightClient) RegisterMetrics(reg *metrics.Registry) error {
+	return reg.Register(
+		metrics.NewGaugeFunc(
+			"tfchain_eth_peers",
+			"Amount of peers the ETH light client is connected to.",
+			func() (float64, bool) {
+				return float64(lc.PeerCount()), true
+			}),
+		metrics.NewGaugeFunc(
+			"tfchain_eth_current_block",
+			"Height of the current block of the ETH light client.",
+			func() (float64, bool) {
+				status, err := lc.GetStatus()
+				if err != nil {
+					return 0, false
+				}
+				return float64(status.CurrentBlock), true
+			}),
+		metrics.NewGaugeFunc(
+			"tfchain_eth_sync_lag_blocks",
+			"Amount of blocks the ETH light client is behind on the highest known block.",
+			func() (float64, bool) {
+				status, err := lc.GetStatus()
+				if err != nil {
+					return 0, false
+				}
+				return float64(status.HighestBlock - status.CurrentBlock), true
+			}),
+	)
+}
+
+// weiToFloat converts the given amount of wei to a float, expressed in the given unit
+func weiToFloat(wei *big.Int, unit float64) float64 {
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(unit)).Float64()
+	return f
+}
@@ -0,0 +1,300 @@
+package erc20
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	tfchaintypes "github.com/threefoldfoundation/tfchain/pkg/types"
+
+	"github.com/threefoldtech/rivine/persist"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+
+	bolt "github.com/rivine/bbolt"
+)
+
+const (
+	outboxDBFile = "outbox.db"
+
+	// OutboxMinBackoff is the time to wait before retrying a failed action for the first time,
+	// doubled for every consecutive failure, up to OutboxMaxBackoff.
+	OutboxMinBackoff = time.Second * 30
+	// OutboxMaxBackoff is the maximum time to wait before retrying a failed action.
+	OutboxMaxBackoff = time.Hour
+	// OutboxMaxAttempts is the amount of times an action is attempted,
+	// before it is moved to the dead state, requiring manual intervention.
+	OutboxMaxAttempts = 16
+
+	// outboxPollInterval is the interval at which the outbox is checked for actions to (re)try
+	outboxPollInterval = time.Second * 15
+)
+
+var (
+	outboxDBMetadata = persist.Metadata{
+		Header:  "Bridge Outbox",
+		Version: "0.0.1",
+	}
+
+	// bucketActions stores all ETH actions the bridge has to take (or has taken),
+	// mapping the tfchain transaction ID to a rivbin-encoded Action
+	bucketActions = []byte("actions")
+)
+
+// ActionType defines the type of an ETH action to be taken by the bridge.
+type ActionType uint8
+
+// The different types of actions the bridge can take on the ETH network.
+const (
+	// ActionTypeMint mints ERC20 tokens, as the result of an ERC20 convert transaction.
+	ActionTypeMint ActionType = iota
+	// ActionTypeRegisterWithdrawalAddress registers an ERC20 withdrawal address,
+	// as the result of an ERC20 address registration transaction.
+	ActionTypeRegisterWithdrawalAddress
+)
+
+// String implements fmt.Stringer.String
+func (at ActionType) String() string {
+	switch at {
+	case ActionTypeMint:
+		return "mint"
+	case ActionTypeRegisterWithdrawalAddress:
+		return "register"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(at))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.MarshalText
+func (at ActionType) MarshalText() ([]byte, error) {
+	return []byte(at.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.UnmarshalText
+func (at *ActionType) UnmarshalText(b []byte) error {
+	for t := ActionTypeMint; t <= ActionTypeRegisterWithdrawalAddress; t++ {
+		if t.String() == string(b) {
+			*at = t
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown action type %q", string(b))
+}
+
+// ActionState defines the state of an ETH action to be taken by the bridge.
+type ActionState uint8
+
+// The different states an action can be in.
+const (
+	// ActionStatePending is the state of an action which still has to be (re)tried.
+	ActionStatePending ActionState = iota
+	// ActionStateDone is the state of an action which was successfully executed,
+	// or which was found to be already executed.
+	ActionStateDone
+	// ActionStateDead is the state of an action which failed too many times,
+	// and will no longer be tried by the bridge on its own.
+	ActionStateDead
+)
+
+// String implements fmt.Stringer.String
+func (as ActionState) String() string {
+	switch as {
+	case ActionStatePending:
+		return "pending"
+	case ActionStateDone:
+		return "done"
+	case ActionStateDead:
+		return "dead"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(as))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.MarshalText
+func (as ActionState) MarshalText() ([]byte, error) {
+	return []byte(as.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.UnmarshalText
+func (as *ActionState) UnmarshalText(b []byte) error {
+	for s := ActionStatePending; s <= ActionStateDead; s++ {
+		if s.String() == string(b) {
+			*as = s
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown action state %q", string(b))
+}
+
+// Action is an ETH action the bridge has to take,
+// as a result of a bridge-related tfchain transaction.
+type Action struct {
+	// TFTTransactionID is the ID of the tfchain transaction which resulted in this action
+	TFTTransactionID types.TransactionID `json:"tfttxid"`
+	// BlockHeight is the tfchain block height the transaction was included in
+	BlockHeight types.BlockHeight `json:"blockheight"`
+
+	Type ActionType `json:"type"`
+	// Address is the receiver of the minted tokens,
+	// or the withdrawal address to register, depending on the action type
+	Address tfchaintypes.ERC20Address `json:"address"`
+	// Amount of tokens to mint, only used for mint actions
+	Amount types.Currency `json:"amount"`
+
+	State ActionState `json:"state"`
+	// Attempts is the amount of times the action has been tried (and failed)
+	Attempts uint32 `json:"attempts"`
+	// NextAttempt is the (unix epoch) time the action will be tried again
+	NextAttempt int64 `json:"nextattempt"`
+	// LastError is the last error encountered while trying the action
+	LastError string `json:"lasterror,omitempty"`
+	// LastUpdate is the (unix epoch) time the action was last updated
+	LastUpdate int64 `json:"lastupdate"`
+}
+
+// Due returns true if the pending action is to be tried (again) at the given time.
+func (a Action) Due(now time.Time) bool {
+	return a.State == ActionStatePending && a.NextAttempt <= now.Unix()
+}
+
+// markFailed registers a failed attempt for the action,
+// scheduling the next attempt using an exponential backoff,
+// or moving it to the dead state if it failed too many times.
+func (a *Action) markFailed(err error, now time.Time) {
+	a.Attempts++
+	a.LastError = err.Error()
+	if a.Attempts >= OutboxMaxAttempts {
+		a.State = ActionStateDead
+		return
+	}
+	backoff := OutboxMinBackoff
+	for i := uint32(1); i < a.Attempts && backoff < OutboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > OutboxMaxBackoff {
+		backoff = OutboxMaxBackoff
+	}
+	a.NextAttempt = now.Add(backoff).Unix()
+}
+
+// ErrActionNotFound is returned in case an action is not known by the bridge.
+var ErrActionNotFound = errors.New("action not found")
+
+// outbox is a persistent (bolt) store of all ETH actions the bridge has to take.
+type outbox struct {
+	db *persist.BoltDatabase
+}
+
+// openOutbox opens the outbox within the given directory,
+// creating it if it doesn't exist yet.
+func openOutbox(dir string) (*outbox, error) {
+	db, err := persist.OpenDatabase(outboxDBMetadata, filepath.Join(dir, outboxDBFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outbox: %v", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketActions)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create actions bucket: %v", err)
+	}
+	return &outbox{db: db}, nil
+}
+
+// Close the outbox.
+func (ob *outbox) Close() error {
+	return ob.db.Close()
+}
+
+// Add an action to the outbox, returning false if an action
+// for the same tfchain transaction was already known.
+func (ob *outbox) Add(a Action) (added bool, err error) {
+	err = ob.db.Update(func(tx *bolt.Tx) error {
+		_, err := getAction(tx, a.TFTTransactionID)
+		if err == nil {
+			return nil
+		}
+		if err != ErrActionNotFound {
+			return err
+		}
+		added = true
+		return putAction(tx, a)
+	})
+	return
+}
+
+// Update the given action in the outbox, updating its LastUpdate timestamp as well.
+func (ob *outbox) Update(a *Action) error {
+	a.LastUpdate = time.Now().Unix()
+	return ob.db.Update(func(tx *bolt.Tx) error {
+		return putAction(tx, *a)
+	})
+}
+
+// Get the action for the given tfchain transaction ID.
+func (ob *outbox) Get(txID types.TransactionID) (a Action, err error) {
+	err = ob.db.View(func(tx *bolt.Tx) (err error) {
+		a, err = getAction(tx, txID)
+		return
+	})
+	return
+}
+
+// List all actions for which the filter returns true,
+// sorted by tfchain block height. All actions are returned if no filter is given.
+func (ob *outbox) List(filter func(Action) bool) (actions []Action, err error) {
+	err = ob.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketActions)
+		if bucket == nil {
+			return errors.New("corrupt outbox: actions bucket does not exist")
+		}
+		return bucket.ForEach(func(_, v []byte) error {
+			var a Action
+			err := rivbin.Unmarshal(v, &a)
+			if err != nil {
+				return fmt.Errorf("corrupt outbox: failed to decode action: %v", err)
+			}
+			if filter == nil || filter(a) {
+				actions = append(actions, a)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].BlockHeight < actions[j].BlockHeight
+	})
+	return actions, nil
+}
+
+func getAction(tx *bolt.Tx, txID types.TransactionID) (a Action, err error) {
+	bucket := tx.Bucket(bucketActions)
+	if bucket == nil {
+		err = errors.New("corrupt outbox: actions bucket does not exist")
+		return
+	}
+	b := bucket.Get(txID[:])
+	if len(b) == 0 {
+		err = ErrActionNotFound
+		return
+	}
+	err = rivbin.Unmarshal(b, &a)
+	if err != nil {
+		err = fmt.Errorf("corrupt outbox: failed to decode action %s: %v", txID.String(), err)
+	}
+	return
+}
+
+func putAction(tx *bolt.Tx, a Action) error {
+	bucket := tx.Bucket(bucketActions)
+	if bucket == nil {
+		return errors.New("corrupt outbox: actions bucket does not exist")
+	}
+	return bucket.Put(a.TFTTransactionID[:], rivbin.Marshal(a))
+}
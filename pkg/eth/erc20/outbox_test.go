@@ -0,0 +1,157 @@
+package erc20
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestActionMarkFailed(t *testing.T) {
+	now := time.Unix(1000, 0)
+	action := Action{}
+	expectedBackoffs := []time.Duration{
+		OutboxMinBackoff,
+		OutboxMinBackoff * 2,
+		OutboxMinBackoff * 4,
+		OutboxMinBackoff * 8,
+	}
+	for idx, expectedBackoff := range expectedBackoffs {
+		action.markFailed(errors.New("foo"), now)
+		if action.State != ActionStatePending {
+			t.Fatalf("#%d: unexpected state %s", idx, action.State.String())
+		}
+		if backoff := time.Unix(action.NextAttempt, 0).Sub(now); backoff != expectedBackoff {
+			t.Errorf("#%d: unexpected backoff %v, expected %v", idx, backoff, expectedBackoff)
+		}
+		if action.Due(now) {
+			t.Errorf("#%d: failed action should not be due immediately", idx)
+		}
+		if !action.Due(now.Add(expectedBackoff)) {
+			t.Errorf("#%d: failed action should be due after its backoff", idx)
+		}
+	}
+	for action.State == ActionStatePending {
+		action.markFailed(errors.New("foo"), now)
+		if backoff := time.Unix(action.NextAttempt, 0).Sub(now); backoff > OutboxMaxBackoff {
+			t.Errorf("backoff %v exceeds the max backoff", backoff)
+		}
+	}
+	if action.Attempts != OutboxMaxAttempts {
+		t.Errorf("expected action to be dead after %d attempts, was after %d", OutboxMaxAttempts, action.Attempts)
+	}
+	if action.State != ActionStateDead || action.LastError != "foo" {
+		t.Errorf("unexpected dead action: %v", action)
+	}
+	if action.Due(now.Add(OutboxMaxBackoff * 2)) {
+		t.Error("dead action should never be due")
+	}
+}
+
+func TestOutbox(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tfchain-outbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ob, err := openOutbox(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ob.Close()
+
+	actions := []Action{
+		{TFTTransactionID: types.TransactionID{1}, BlockHeight: 20, Type: ActionTypeMint, Amount: types.NewCurrency64(42)},
+		{TFTTransactionID: types.TransactionID{2}, BlockHeight: 10, Type: ActionTypeRegisterWithdrawalAddress},
+	}
+	for idx, action := range actions {
+		added, err := ob.Add(action)
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		if !added {
+			t.Error(idx, "expected action to be added")
+		}
+	}
+
+	action := actions[0]
+	action.State = ActionStateDone
+	if err = ob.Update(&action); err != nil {
+		t.Fatal(err)
+	}
+	// adding a known action should not reset it
+	added, err := ob.Add(actions[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added {
+		t.Error("expected known action not to be added again")
+	}
+
+	due, err := ob.List(func(a Action) bool { return a.Due(time.Now()) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(due) != 1 || due[0].TFTTransactionID != actions[1].TFTTransactionID {
+		t.Errorf("unexpected due actions: %v", due)
+	}
+	all, err := ob.List(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].BlockHeight != 10 || all[1].State != ActionStateDone {
+		t.Errorf("unexpected actions: %v", all)
+	}
+	if _, err = ob.Get(types.TransactionID{3}); err != ErrActionNotFound {
+		t.Errorf("expected ErrActionNotFound, got %v", err)
+	}
+}
+
+func TestBridgeGetDueAction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tfchain-outbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ob, err := openOutbox(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ob.Close()
+	bridge := &Bridge{outbox: ob}
+
+	now := time.Now()
+	action := Action{TFTTransactionID: types.TransactionID{1}, BlockHeight: 10, Type: ActionTypeMint, Amount: types.NewCurrency64(42)}
+	if _, err = ob.Add(action); err != nil {
+		t.Fatal(err)
+	}
+	if _, due := bridge.getDueAction(action.TFTTransactionID, now); !due {
+		t.Error("expected pending action to be due")
+	}
+
+	// an action skipped by the operator after the due actions were listed is no longer due
+	if _, err = bridge.SkipAction(action.TFTTransactionID); err != nil {
+		t.Fatal(err)
+	}
+	if current, due := bridge.getDueAction(action.TFTTransactionID, now); due || current.State != ActionStateDead {
+		t.Errorf("expected skipped action not to be due: %v", current)
+	}
+
+	// an action retried by the operator is due again, using its reset state
+	if _, err = bridge.RetryAction(action.TFTTransactionID); err != nil {
+		t.Fatal(err)
+	}
+	if current, due := bridge.getDueAction(action.TFTTransactionID, now); !due || current.Attempts != 0 {
+		t.Errorf("expected retried action to be due: %v", current)
+	}
+
+	// unknown actions are never due
+	if _, due := bridge.getDueAction(types.TransactionID{2}, now); due {
+		t.Error("expected unknown action not to be due")
+	}
+}
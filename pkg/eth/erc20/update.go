@@ -1,21 +1,44 @@
 package erc20
 
 import (
+	"fmt"
+
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
 
 	tfchaintypes "github.com/threefoldfoundation/tfchain/pkg/types"
 )
 
 // ProcessConsensusChange implements modules.ConsensusSetSubscriber,
 // used to apply/revert blocks.
+//
+// Actions to be taken on the ETH network are not executed directly,
+// but are stored in the outbox of the bridge instead, such that a failing action
+// can be retried later, without blocking the processing of other blocks and transactions.
 func (bridge *Bridge) ProcessConsensusChange(css modules.ConsensusChange) {
 	bridge.mut.Lock()
 	defer bridge.mut.Unlock()
 
 	// In case there is a reverted block remove it from the buffer
-	for range css.RevertedBlocks {
+	for _, block := range css.RevertedBlocks {
 		bridge.buffer.rewindBlock()
+
+		// withdraws confirmed by a reverted block are no longer confirmed,
+		// such that they are processed again once matured
+		for _, tx := range block.Transactions {
+			if tx.Version != tfchaintypes.TransactionVersionERC20CoinCreation {
+				continue
+			}
+			txCoinCreation, err := tfchaintypes.ERC20CoinCreationTransactionFromTransaction(tx)
+			if err != nil {
+				log.Error("Found a TFT ERC20 coin creation transaction version, but can't create the right transaction for it")
+				continue
+			}
+			if err = bridge.unconfirmWithdraw(txCoinCreation.TransactionID, tx.ID()); err != nil {
+				log.Error("Failed to revert withdraw confirmation", "ethTx", txCoinCreation.TransactionID.String(), "err", err)
+			}
+		}
 	}
 	for _, block := range css.AppliedBlocks {
 		height, _ := bridge.cs.BlockHeightOfBlock(block)
@@ -29,39 +52,117 @@ func (bridge *Bridge) ProcessConsensusChange(css modules.ConsensusChange) {
 			continue
 		}
 
-		for _, tx := range oldBlock.Transactions {
-			if tx.Version == tfchaintypes.TransactionVersionERC20Conversion {
-				log.Warn("Found convert transacton")
-				txConvert, err := tfchaintypes.ERC20ConvertTransactionFromTransaction(tx)
-				if err != nil {
-					log.Error("Found a TFT convert transaction version, but can't create a conversion transaction from it")
-					return
-				}
-				// Send the mint transaction, this requires gas
-				if err = bridge.mint(txConvert.Address, txConvert.Value, tx.ID()); err != nil {
-					log.Error("Failed to push mint transaction", "error", err)
-					return
-				}
-				log.Info("Created mint transaction on eth network")
-			} else if tx.Version == tfchaintypes.TransactionVersionERC20AddressRegistration {
-				log.Warn("Found erc20 address registration")
-				txRegistration, err := tfchaintypes.ERC20AddressRegistrationTransactionFromTransaction(tx)
-				if err != nil {
-					log.Error("Found a TFT ERC20 Address registration transaction version, but can't create the right transaction for it")
-					return
-				}
-				// send the address registration transaction
-				if err = bridge.registerWithdrawalAddress(txRegistration.PublicKey); err != nil {
-					log.Error("Failed to push withdrawal address registration transaction", "err", err)
-					return
-				}
-				log.Info("Registered withdrawal address on eth network")
-			}
+		// the height of the block we are processing,
+		// which lags TFTBlockDelay blocks behind the block we just received
+		oldHeight := types.BlockHeight(0)
+		if height > TFTBlockDelay {
+			oldHeight = height - TFTBlockDelay
 		}
+		bridge.unprocessed = append(bridge.unprocessed, unprocessedBlock{
+			bufferedBlock: oldBlock,
+			height:        oldHeight,
+		})
+	}
+
+	// process all blocks that left the buffer, in order,
+	// a block that fails to be processed, and all blocks following it,
+	// remain unprocessed until the next consensus change (or restart),
+	// as the bridge height is only advanced for processed blocks
+	for len(bridge.unprocessed) > 0 {
+		ub := bridge.unprocessed[0]
+		if err := bridge.processBlock(ub.bufferedBlock, ub.height); err != nil {
+			log.Error("Failed to process TfChain block, it will be processed again later", "block", ub.height, "err", err)
+			return
+		}
+		bridge.unprocessed = bridge.unprocessed[1:]
 
 		// update stats
 		bridge.persist.Height++
-		bridge.persist.RecentChange = oldBlock.ConsensusChangeID
-		bridge.save()
+		bridge.persist.RecentChange = ub.ConsensusChangeID
+		if err := bridge.save(); err != nil {
+			log.Error("Failed to save bridge persistency", "err", err)
+		}
+		bridge.metrics.tfchainHeight.Set(float64(bridge.persist.Height))
+	}
+}
+
+// unprocessedBlock is a block which left the buffer,
+// but which has not yet been (successfully) processed.
+type unprocessedBlock struct {
+	*bufferedBlock
+	height types.BlockHeight
+}
+
+// processBlock queues the actions required for the bridge-related transactions of the given block,
+// returning an error if any of them could not be queued.
+func (bridge *Bridge) processBlock(block *bufferedBlock, height types.BlockHeight) error {
+	for _, tx := range block.Transactions {
+		if tx.Version == tfchaintypes.TransactionVersionERC20Conversion {
+			log.Warn("Found convert transacton")
+			txConvert, err := tfchaintypes.ERC20ConvertTransactionFromTransaction(tx)
+			if err != nil {
+				log.Error("Found a TFT convert transaction version, but can't create a conversion transaction from it")
+				continue
+			}
+			// queue the mint transaction, this requires gas
+			err = bridge.queueAction(Action{
+				TFTTransactionID: tx.ID(),
+				BlockHeight:      height,
+				Type:             ActionTypeMint,
+				Address:          txConvert.Address,
+				Amount:           txConvert.Value,
+			})
+			if err != nil {
+				return err
+			}
+		} else if tx.Version == tfchaintypes.TransactionVersionERC20AddressRegistration {
+			log.Warn("Found erc20 address registration")
+			txRegistration, err := tfchaintypes.ERC20AddressRegistrationTransactionFromTransaction(tx)
+			if err != nil {
+				log.Error("Found a TFT ERC20 Address registration transaction version, but can't create the right transaction for it")
+				continue
+			}
+			// queue the address registration transaction,
+			// converting the public key to unlockhash to eth address
+			err = bridge.queueAction(Action{
+				TFTTransactionID: tx.ID(),
+				BlockHeight:      height,
+				Type:             ActionTypeRegisterWithdrawalAddress,
+				Address:          tfchaintypes.ERC20AddressFromUnlockHash(types.NewPubKeyUnlockHash(txRegistration.PublicKey)),
+			})
+			if err != nil {
+				return err
+			}
+		} else if tx.Version == tfchaintypes.TransactionVersionERC20CoinCreation {
+			txCoinCreation, err := tfchaintypes.ERC20CoinCreationTransactionFromTransaction(tx)
+			if err != nil {
+				log.Error("Found a TFT ERC20 coin creation transaction version, but can't create the right transaction for it")
+				continue
+			}
+			// mark the withdraw as confirmed, should we know about it
+			if err = bridge.confirmWithdraw(txCoinCreation.TransactionID, tx.ID()); err != nil {
+				log.Error("Failed to mark withdraw as confirmed", "ethTx", txCoinCreation.TransactionID.String(), "err", err)
+			}
+		}
+	}
+	return nil
+}
+
+// queueAction adds an action to the outbox, and notifies the outbox worker about it.
+// Adding an action which is already known is a no-op.
+func (bridge *Bridge) queueAction(action Action) error {
+	added, err := bridge.outbox.Add(action)
+	if err != nil {
+		return fmt.Errorf("failed to queue %s action for tx %s: %v", action.Type.String(), action.TFTTransactionID.String(), err)
+	}
+	if !added {
+		// already known (e.g. reprocessed after a restart)
+		return nil
+	}
+	log.Info("Queued action", "type", action.Type.String(), "txid", action.TFTTransactionID.String())
+	select {
+	case bridge.outboxSignal <- struct{}{}:
+	default:
 	}
+	return nil
 }
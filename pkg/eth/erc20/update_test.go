@@ -0,0 +1,89 @@
+package erc20
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	tfchaintypes "github.com/threefoldfoundation/tfchain/pkg/types"
+
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// heightConsensusSet is a consensus set which only knows the heights of the blocks it was given
+type heightConsensusSet struct {
+	modules.ConsensusSet
+	heights map[types.BlockID]types.BlockHeight
+}
+
+func (cs heightConsensusSet) BlockHeightOfBlock(block types.Block) (types.BlockHeight, bool) {
+	height, ok := cs.heights[block.ID()]
+	return height, ok
+}
+
+func TestProcessConsensusChangeRetriesFailedBlocks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tfchain-bridge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cs := heightConsensusSet{heights: make(map[types.BlockID]types.BlockHeight)}
+	bridge := &Bridge{
+		cs:           cs,
+		persistDir:   dir,
+		buffer:       newBlockBuffer(TFTBlockDelay),
+		outboxSignal: make(chan struct{}, 1),
+		metrics:      newBridgeMetrics(),
+	}
+	bridge.outbox, err = openOutbox(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	convertTx := (&tfchaintypes.ERC20ConvertTransaction{
+		Address:        tfchaintypes.ERC20Address{1},
+		Value:          types.NewCurrency64(42),
+		TransactionFee: types.NewCurrency64(1),
+		CoinInputs:     []types.CoinInput{{}},
+	}).Transaction()
+	applyBlock := func(height types.BlockHeight, txs ...types.Transaction) {
+		block := types.Block{Timestamp: types.Timestamp(height), Transactions: txs}
+		cs.heights[block.ID()] = height
+		bridge.ProcessConsensusChange(modules.ConsensusChange{
+			ID:            modules.ConsensusChangeID{byte(height)},
+			AppliedBlocks: []types.Block{block},
+		})
+	}
+
+	// the first block, containing a conversion, cannot be processed as the outbox is unavailable
+	applyBlock(1, convertTx)
+	if err = bridge.outbox.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for height := types.BlockHeight(2); height <= TFTBlockDelay+1; height++ {
+		applyBlock(height)
+	}
+	if bridge.persist.Height != 0 || bridge.persist.RecentChange != (modules.ConsensusChangeID{}) {
+		t.Fatalf("bridge height advanced for an unprocessed block: %d", bridge.persist.Height)
+	}
+
+	// once the outbox is available again, the block is processed together with the next one
+	bridge.outbox, err = openOutbox(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bridge.outbox.Close()
+	applyBlock(TFTBlockDelay + 2)
+	if bridge.persist.Height != 2 || bridge.persist.RecentChange != (modules.ConsensusChangeID{2}) {
+		t.Fatalf("unexpected bridge state: %v", bridge.persist)
+	}
+	action, err := bridge.outbox.Get(convertTx.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if action.Type != ActionTypeMint || action.BlockHeight != 1 {
+		t.Errorf("unexpected queued action: %v", action)
+	}
+}
@@ -0,0 +1,309 @@
+package erc20
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/threefoldtech/rivine/persist"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+
+	bolt "github.com/rivine/bbolt"
+)
+
+const (
+	withdrawIndexDBFile = "withdrawindex.db"
+
+	// WithdrawIndexReorgDepth is the amount of ETH blocks, counting back from
+	// the last indexed block, that are re-indexed on every refresh of the WithdrawIndex,
+	// such that withdraw events which are removed or moved due to a reorg are updated as well.
+	WithdrawIndexReorgDepth = 64
+)
+
+var (
+	withdrawIndexDBMetadata = persist.Metadata{
+		Header:  "ERC20 Withdraw Index",
+		Version: "0.0.1",
+	}
+
+	bucketWithdrawIndexInternal         = []byte("internal")
+	bucketWithdrawIndexInternalKeyStats = []byte("stats") // stored as a single struct, see `withdrawIndexStats`
+
+	// bucketWithdrawEvents maps the ERC20 transaction hash to the withdraw event info
+	bucketWithdrawEvents = []byte("withdrawevents")
+	// bucketWithdrawEventHeights contains a key for each indexed event,
+	// composed of the (sortable) block height and the ERC20 transaction hash,
+	// allowing us to efficiently remove all events starting from a given height
+	bucketWithdrawEventHeights = []byte("withdrawheights")
+)
+
+type withdrawIndexStats struct {
+	// Indexed is true if at least one range was indexed,
+	// Height is the last ETH block height indexed, only defined if Indexed is true
+	Indexed bool
+	Height  uint64
+}
+
+// WithdrawIndex is a persistent index of all withdraw events of the bridge contract,
+// keyed by the hash of the ERC20 transaction which created the event.
+// The index is filled incrementally, starting from the last indexed height,
+// re-indexing the last WithdrawIndexReorgDepth blocks in order to handle reorgs.
+type WithdrawIndex struct {
+	contract *BridgeContract
+	db       *persist.BoltDatabase
+	mut      sync.Mutex
+}
+
+// NewWithdrawIndex opens (or creates) a WithdrawIndex within the given directory,
+// indexing the withdraw events of the given bridge contract.
+func NewWithdrawIndex(contract *BridgeContract, dir string) (*WithdrawIndex, error) {
+	if contract == nil {
+		return nil, errors.New("no bridge contract given")
+	}
+	db, err := persist.OpenDatabase(withdrawIndexDBMetadata, filepath.Join(dir, withdrawIndexDBFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open withdraw index: %v", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{bucketWithdrawIndexInternal, bucketWithdrawEvents, bucketWithdrawEventHeights} {
+			_, err := tx.CreateBucketIfNotExists(bucket)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create withdraw index buckets: %v", err)
+	}
+	return &WithdrawIndex{
+		contract: contract,
+		db:       db,
+	}, nil
+}
+
+// Close the WithdrawIndex.
+func (wi *WithdrawIndex) Close() error {
+	return wi.db.Close()
+}
+
+// Lookup the withdraw event created by the ERC20 transaction with the given hash.
+// The index is refreshed first, should the event not be indexed yet.
+func (wi *WithdrawIndex) Lookup(txHash common.Hash) (WithdrawEvent, bool, error) {
+	we, found, err := wi.get(txHash)
+	if err != nil || found {
+		return we, found, err
+	}
+	err = wi.Refresh()
+	if err != nil {
+		return WithdrawEvent{}, false, err
+	}
+	return wi.get(txHash)
+}
+
+// Height returns the last ETH block height indexed,
+// false is returned in case nothing was indexed yet.
+func (wi *WithdrawIndex) Height() (height uint64, indexed bool, err error) {
+	err = wi.db.View(func(tx *bolt.Tx) error {
+		stats, err := getWithdrawIndexStats(tx)
+		height, indexed = stats.Height, stats.Indexed
+		return err
+	})
+	return
+}
+
+// Refresh the index, indexing all withdraw events up to the current ETH chain head.
+func (wi *WithdrawIndex) Refresh() error {
+	wi.mut.Lock()
+	defer wi.mut.Unlock()
+
+	head, err := wi.headHeight()
+	if err != nil {
+		return fmt.Errorf("failed to get ETH chain head: %v", err)
+	}
+	lastHeight, indexed, err := wi.Height()
+	if err != nil {
+		return err
+	}
+	var start uint64
+	if indexed {
+		if lastHeight >= head {
+			return nil // nothing to do
+		}
+		if lastHeight > WithdrawIndexReorgDepth {
+			start = lastHeight - WithdrawIndexReorgDepth
+		}
+	}
+
+	log.Debug("Indexing withdraw events", "start", start, "end", head)
+	withdraws, err := wi.contract.GetPastWithdraws(start, &head)
+	if err != nil {
+		return fmt.Errorf("failed to get withdraw events: %v", err)
+	}
+	return wi.db.Update(func(tx *bolt.Tx) error {
+		err := removeWithdrawEventsFrom(tx, start)
+		if err != nil {
+			return err
+		}
+		for _, we := range withdraws {
+			err = putWithdrawEvent(tx, we)
+			if err != nil {
+				return err
+			}
+		}
+		return putWithdrawIndexStats(tx, withdrawIndexStats{
+			Indexed: true,
+			Height:  head,
+		})
+	})
+}
+
+func (wi *WithdrawIndex) headHeight() (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+	head, err := wi.contract.lc.HeaderByNumber(ctx, nil)
+	for IsNoPeerErr(err) {
+		time.Sleep(retryDelay)
+		log.Debug("Retrying to get the ETH chain head")
+		head, err = wi.contract.lc.HeaderByNumber(ctx, nil)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return head.Number.Uint64(), nil
+}
+
+func (wi *WithdrawIndex) get(txHash common.Hash) (we WithdrawEvent, found bool, err error) {
+	err = wi.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketWithdrawEvents)
+		if bucket == nil {
+			return errors.New("corrupt withdraw index: withdraw events bucket does not exist")
+		}
+		b := bucket.Get(txHash[:])
+		if len(b) == 0 {
+			return nil
+		}
+		found = true
+		we, err = decodeWithdrawEvent(txHash, b)
+		return err
+	})
+	return
+}
+
+func getWithdrawIndexStats(tx *bolt.Tx) (stats withdrawIndexStats, err error) {
+	bucket := tx.Bucket(bucketWithdrawIndexInternal)
+	if bucket == nil {
+		err = errors.New("corrupt withdraw index: internal bucket does not exist")
+		return
+	}
+	b := bucket.Get(bucketWithdrawIndexInternalKeyStats)
+	if len(b) == 0 {
+		return // nothing indexed yet
+	}
+	err = rivbin.Unmarshal(b, &stats)
+	if err != nil {
+		err = fmt.Errorf("corrupt withdraw index: failed to decode stats: %v", err)
+	}
+	return
+}
+
+func putWithdrawIndexStats(tx *bolt.Tx, stats withdrawIndexStats) error {
+	bucket := tx.Bucket(bucketWithdrawIndexInternal)
+	if bucket == nil {
+		return errors.New("corrupt withdraw index: internal bucket does not exist")
+	}
+	return bucket.Put(bucketWithdrawIndexInternalKeyStats, rivbin.Marshal(stats))
+}
+
+func putWithdrawEvent(tx *bolt.Tx, we WithdrawEvent) error {
+	eventBucket := tx.Bucket(bucketWithdrawEvents)
+	if eventBucket == nil {
+		return errors.New("corrupt withdraw index: withdraw events bucket does not exist")
+	}
+	heightBucket := tx.Bucket(bucketWithdrawEventHeights)
+	if heightBucket == nil {
+		return errors.New("corrupt withdraw index: withdraw heights bucket does not exist")
+	}
+	err := eventBucket.Put(we.txHash[:], encodeWithdrawEvent(we))
+	if err != nil {
+		return fmt.Errorf("failed to store withdraw event %s: %v", we.txHash.Hex(), err)
+	}
+	return heightBucket.Put(withdrawEventHeightKey(we.blockHeight, we.txHash), []byte{})
+}
+
+// removeWithdrawEventsFrom removes all withdraw events indexed at the given height or higher.
+func removeWithdrawEventsFrom(tx *bolt.Tx, height uint64) error {
+	eventBucket := tx.Bucket(bucketWithdrawEvents)
+	if eventBucket == nil {
+		return errors.New("corrupt withdraw index: withdraw events bucket does not exist")
+	}
+	heightBucket := tx.Bucket(bucketWithdrawEventHeights)
+	if heightBucket == nil {
+		return errors.New("corrupt withdraw index: withdraw heights bucket does not exist")
+	}
+	var keys [][]byte
+	cursor := heightBucket.Cursor()
+	for k, _ := cursor.Seek(withdrawEventHeightKey(height, common.Hash{})); k != nil; k, _ = cursor.Next() {
+		keys = append(keys, append([]byte(nil), k...))
+	}
+	for _, k := range keys {
+		err := eventBucket.Delete(k[8:])
+		if err != nil {
+			return err
+		}
+		err = heightBucket.Delete(k)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func withdrawEventHeightKey(height uint64, txHash common.Hash) []byte {
+	key := make([]byte, 8+common.HashLength)
+	binary.BigEndian.PutUint64(key[:8], height)
+	copy(key[8:], txHash[:])
+	return key
+}
+
+func encodeWithdrawEvent(we WithdrawEvent) []byte {
+	return rivbin.Marshal(indexedWithdrawEvent{
+		Receiver:    we.receiver,
+		Amount:      types.NewCurrency(we.amount),
+		BlockHash:   we.blockHash,
+		BlockHeight: we.blockHeight,
+	})
+}
+
+func decodeWithdrawEvent(txHash common.Hash, b []byte) (WithdrawEvent, error) {
+	var iwe indexedWithdrawEvent
+	err := rivbin.Unmarshal(b, &iwe)
+	if err != nil {
+		return WithdrawEvent{}, fmt.Errorf("corrupt withdraw index: failed to decode withdraw event %s: %v", txHash.Hex(), err)
+	}
+	return WithdrawEvent{
+		receiver:    iwe.Receiver,
+		amount:      iwe.Amount.Big(),
+		txHash:      txHash,
+		blockHash:   iwe.BlockHash,
+		blockHeight: iwe.BlockHeight,
+	}, nil
+}
+
+// indexedWithdrawEvent is the persistent form of a WithdrawEvent,
+// the tx hash is not included, as it is used as the key
+type indexedWithdrawEvent struct {
+	Receiver    common.Address
+	Amount      types.Currency
+	BlockHash   common.Hash
+	BlockHeight uint64
+}
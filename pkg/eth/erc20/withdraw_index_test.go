@@ -0,0 +1,81 @@
+package erc20
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	bolt "github.com/rivine/bbolt"
+)
+
+func TestWithdrawIndexStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tfchain-withdrawindex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	wi, err := NewWithdrawIndex(&BridgeContract{}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wi.Close()
+
+	if _, indexed, err := wi.Height(); err != nil || indexed {
+		t.Fatalf("expected nothing to be indexed yet: %v, %v", indexed, err)
+	}
+
+	events := []WithdrawEvent{
+		{receiver: common.HexToAddress("0x1"), amount: big.NewInt(42), txHash: common.HexToHash("0x10"), blockHash: common.HexToHash("0x3"), blockHeight: 10},
+		{receiver: common.HexToAddress("0x4"), amount: big.NewInt(7), txHash: common.HexToHash("0x11"), blockHash: common.HexToHash("0x6"), blockHeight: 20},
+		{receiver: common.HexToAddress("0x7"), amount: big.NewInt(1), txHash: common.HexToHash("0x12"), blockHash: common.HexToHash("0x9"), blockHeight: 30},
+	}
+	err = wi.db.Update(func(tx *bolt.Tx) error {
+		for _, we := range events {
+			if err := putWithdrawEvent(tx, we); err != nil {
+				return err
+			}
+		}
+		return putWithdrawIndexStats(tx, withdrawIndexStats{Indexed: true, Height: 30})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if height, indexed, err := wi.Height(); err != nil || !indexed || height != 30 {
+		t.Fatalf("unexpected index height: %d, %v, %v", height, indexed, err)
+	}
+
+	for idx, expected := range events {
+		we, found, err := wi.get(expected.txHash)
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		if !found {
+			t.Fatal(idx, "withdraw event not found")
+		}
+		if we.Receiver() != expected.receiver || we.Amount().Cmp(expected.amount) != 0 ||
+			we.BlockHash() != expected.blockHash || we.BlockHeight() != expected.blockHeight || we.TxHash() != expected.txHash {
+			t.Errorf("%d: unexpected withdraw event %v, expected %v", idx, we, expected)
+		}
+	}
+
+	// remove all events starting from height 20, as would be done for a reorg
+	err = wi.db.Update(func(tx *bolt.Tx) error {
+		return removeWithdrawEventsFrom(tx, 20)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for idx, we := range events {
+		_, found, err := wi.get(we.txHash)
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		if expected := we.blockHeight < 20; found != expected {
+			t.Errorf("%d: unexpected found result %v for event at height %d", idx, found, we.blockHeight)
+		}
+	}
+}
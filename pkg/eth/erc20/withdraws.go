@@ -0,0 +1,251 @@
+package erc20
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	tfchaintypes "github.com/threefoldfoundation/tfchain/pkg/types"
+
+	"github.com/threefoldtech/rivine/persist"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+
+	bolt "github.com/rivine/bbolt"
+)
+
+const (
+	withdrawsDBFile = "withdraws.db"
+)
+
+var (
+	withdrawsDBMetadata = persist.Metadata{
+		Header:  "Bridge Withdraws",
+		Version: "0.0.1",
+	}
+
+	// bucketWithdraws stores all withdraws known by the bridge,
+	// mapping the ERC20 transaction hash to a rivbin-encoded Withdraw
+	bucketWithdraws = []byte("withdraws")
+)
+
+// WithdrawState defines the state an ERC20 withdraw is in,
+// from the point of view of the bridge.
+type WithdrawState uint8
+
+// The different states a withdraw can be in.
+const (
+	// WithdrawStateSeen is the state of a withdraw event which was noticed
+	// by the bridge, but is not yet old enough to be acted upon.
+	WithdrawStateSeen WithdrawState = iota
+	// WithdrawStateMatured is the state of a withdraw event which has sufficient
+	// confirmations on the ETH network, but for which no coin creation transaction
+	// has been committed to the tfchain transaction pool yet.
+	WithdrawStateMatured
+	// WithdrawStateSubmitted is the state of a withdraw for which
+	// a coin creation transaction has been committed to the tfchain transaction pool.
+	WithdrawStateSubmitted
+	// WithdrawStateConfirmed is the state of a withdraw for which
+	// the coin creation transaction has been included in a tfchain block.
+	WithdrawStateConfirmed
+	// WithdrawStateFailed is the state of a withdraw which cannot be processed by the bridge.
+	WithdrawStateFailed
+)
+
+// String implements fmt.Stringer.String
+func (s WithdrawState) String() string {
+	switch s {
+	case WithdrawStateSeen:
+		return "seen"
+	case WithdrawStateMatured:
+		return "matured"
+	case WithdrawStateSubmitted:
+		return "submitted"
+	case WithdrawStateConfirmed:
+		return "confirmed"
+	case WithdrawStateFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(s))
+	}
+}
+
+// LoadString loads the state from its string representation.
+func (s *WithdrawState) LoadString(str string) error {
+	for state := WithdrawStateSeen; state <= WithdrawStateFailed; state++ {
+		if state.String() == str {
+			*s = state
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown withdraw state %q", str)
+}
+
+// MarshalText implements encoding.TextMarshaler.MarshalText
+func (s WithdrawState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.UnmarshalText
+func (s *WithdrawState) UnmarshalText(b []byte) error {
+	return s.LoadString(string(b))
+}
+
+// Pending returns true if the bridge still has to act upon a withdraw in this state.
+func (s WithdrawState) Pending() bool {
+	return s < WithdrawStateConfirmed
+}
+
+// Withdraw is the persistent form of a WithdrawEvent,
+// tracking the progress the bridge made in processing it.
+type Withdraw struct {
+	TxHash      tfchaintypes.ERC20Hash    `json:"txhash"`
+	BlockHash   tfchaintypes.ERC20Hash    `json:"blockhash"`
+	BlockHeight uint64                    `json:"blockheight"`
+	Receiver    tfchaintypes.ERC20Address `json:"receiver"`
+	Amount      types.Currency            `json:"amount"`
+
+	State WithdrawState `json:"state"`
+	// TFTTransactionID is the ID of the coin creation transaction,
+	// only defined once the withdraw has been submitted
+	TFTTransactionID types.TransactionID `json:"tfttxid"`
+	// LastError is the last error encountered while processing the withdraw
+	LastError string `json:"lasterror,omitempty"`
+	// LastUpdate is the (unix epoch) time the state was last updated
+	LastUpdate int64 `json:"lastupdate"`
+}
+
+// newWithdrawFromEvent creates a new Withdraw, in the seen state, from a WithdrawEvent.
+func newWithdrawFromEvent(we WithdrawEvent) Withdraw {
+	return Withdraw{
+		TxHash:      tfchaintypes.ERC20Hash(we.txHash),
+		BlockHash:   tfchaintypes.ERC20Hash(we.blockHash),
+		BlockHeight: we.blockHeight,
+		Receiver:    tfchaintypes.ERC20Address(we.receiver),
+		Amount:      types.NewCurrency(we.amount),
+		State:       WithdrawStateSeen,
+		LastUpdate:  time.Now().Unix(),
+	}
+}
+
+// ErrWithdrawNotFound is returned in case a withdraw is not known by the bridge.
+var ErrWithdrawNotFound = errors.New("withdraw not found")
+
+// withdrawStore is a persistent (bolt) store of all withdraws known by the bridge.
+type withdrawStore struct {
+	db *persist.BoltDatabase
+}
+
+// openWithdrawStore opens the withdraw store within the given directory,
+// creating it if it doesn't exist yet.
+func openWithdrawStore(dir string) (*withdrawStore, error) {
+	db, err := persist.OpenDatabase(withdrawsDBMetadata, filepath.Join(dir, withdrawsDBFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open withdraw store: %v", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketWithdraws)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create withdraws bucket: %v", err)
+	}
+	return &withdrawStore{db: db}, nil
+}
+
+// Close the withdraw store.
+func (ws *withdrawStore) Close() error {
+	return ws.db.Close()
+}
+
+// Add a withdraw to the store, returning false if the withdraw was already known,
+// in which case the already stored withdraw is returned.
+func (ws *withdrawStore) Add(w Withdraw) (stored Withdraw, added bool, err error) {
+	err = ws.db.Update(func(tx *bolt.Tx) error {
+		stored, err = getWithdraw(tx, w.TxHash)
+		if err == nil {
+			return nil
+		}
+		if err != ErrWithdrawNotFound {
+			return err
+		}
+		stored, added = w, true
+		return putWithdraw(tx, w)
+	})
+	return
+}
+
+// Update the given withdraw in the store, updating its LastUpdate timestamp as well.
+func (ws *withdrawStore) Update(w *Withdraw) error {
+	w.LastUpdate = time.Now().Unix()
+	return ws.db.Update(func(tx *bolt.Tx) error {
+		return putWithdraw(tx, *w)
+	})
+}
+
+// Get the withdraw for the given ERC20 transaction hash.
+func (ws *withdrawStore) Get(txHash tfchaintypes.ERC20Hash) (w Withdraw, err error) {
+	err = ws.db.View(func(tx *bolt.Tx) (err error) {
+		w, err = getWithdraw(tx, txHash)
+		return
+	})
+	return
+}
+
+// List all withdraws for which the filter returns true,
+// sorted by ETH block height. All withdraws are returned if no filter is given.
+func (ws *withdrawStore) List(filter func(Withdraw) bool) (withdraws []Withdraw, err error) {
+	err = ws.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketWithdraws)
+		if bucket == nil {
+			return errors.New("corrupt withdraw store: withdraws bucket does not exist")
+		}
+		return bucket.ForEach(func(_, v []byte) error {
+			var w Withdraw
+			err := rivbin.Unmarshal(v, &w)
+			if err != nil {
+				return fmt.Errorf("corrupt withdraw store: failed to decode withdraw: %v", err)
+			}
+			if filter == nil || filter(w) {
+				withdraws = append(withdraws, w)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(withdraws, func(i, j int) bool {
+		return withdraws[i].BlockHeight < withdraws[j].BlockHeight
+	})
+	return withdraws, nil
+}
+
+func getWithdraw(tx *bolt.Tx, txHash tfchaintypes.ERC20Hash) (w Withdraw, err error) {
+	bucket := tx.Bucket(bucketWithdraws)
+	if bucket == nil {
+		err = errors.New("corrupt withdraw store: withdraws bucket does not exist")
+		return
+	}
+	b := bucket.Get(txHash[:])
+	if len(b) == 0 {
+		err = ErrWithdrawNotFound
+		return
+	}
+	err = rivbin.Unmarshal(b, &w)
+	if err != nil {
+		err = fmt.Errorf("corrupt withdraw store: failed to decode withdraw %s: %v", txHash.String(), err)
+	}
+	return
+}
+
+func putWithdraw(tx *bolt.Tx, w Withdraw) error {
+	bucket := tx.Bucket(bucketWithdraws)
+	if bucket == nil {
+		return errors.New("corrupt withdraw store: withdraws bucket does not exist")
+	}
+	return bucket.Put(w.TxHash[:], rivbin.Marshal(w))
+}
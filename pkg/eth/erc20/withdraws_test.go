@@ -0,0 +1,203 @@
+package erc20
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	tfchaintypes "github.com/threefoldfoundation/tfchain/pkg/types"
+	"github.com/threefoldtech/rivine/modules"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func Test_withdrawStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tfchain-withdraws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ws, err := openWithdrawStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := []WithdrawEvent{
+		{receiver: common.HexToAddress("0x1"), amount: big.NewInt(42), txHash: common.HexToHash("0x2"), blockHash: common.HexToHash("0x3"), blockHeight: 10},
+		{receiver: common.HexToAddress("0x4"), amount: big.NewInt(7), txHash: common.HexToHash("0x5"), blockHash: common.HexToHash("0x6"), blockHeight: 5},
+	}
+	for idx, we := range events {
+		_, added, err := ws.Add(newWithdrawFromEvent(we))
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		if !added {
+			t.Error(idx, "expected withdraw to be added")
+		}
+	}
+
+	// update the state of the first withdraw
+	w, err := ws.Get(newWithdrawFromEvent(events[0]).TxHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Amount.Cmp(types.NewCurrency64(42)) != 0 {
+		t.Errorf("unexpected amount: %s", w.Amount.String())
+	}
+	w.State = WithdrawStateSubmitted
+	w.TFTTransactionID = types.TransactionID{1, 2, 3}
+	if err = ws.Update(&w); err != nil {
+		t.Fatal(err)
+	}
+
+	// adding a known withdraw should not reset its state
+	stored, added, err := ws.Add(newWithdrawFromEvent(events[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added {
+		t.Error("expected known withdraw not to be added again")
+	}
+	if stored.State != WithdrawStateSubmitted || stored.TFTTransactionID != w.TFTTransactionID {
+		t.Errorf("unexpected stored withdraw: %v", stored)
+	}
+
+	// ensure the withdraws survive a restart
+	if err = ws.Close(); err != nil {
+		t.Fatal(err)
+	}
+	ws, err = openWithdrawStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	withdraws, err := ws.List(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(withdraws) != 2 {
+		t.Fatalf("expected 2 withdraws, got %d", len(withdraws))
+	}
+	if withdraws[0].BlockHeight != 5 || withdraws[1].BlockHeight != 10 {
+		t.Errorf("withdraws not sorted by height: %v", withdraws)
+	}
+	if withdraws[1].State != WithdrawStateSubmitted {
+		t.Errorf("unexpected state for withdraw: %s", withdraws[1].State.String())
+	}
+
+	_, err = ws.Get(newWithdrawFromEvent(WithdrawEvent{txHash: common.HexToHash("0x7"), amount: big.NewInt(0)}).TxHash)
+	if err != ErrWithdrawNotFound {
+		t.Errorf("expected ErrWithdrawNotFound, got %v", err)
+	}
+}
+
+func TestBridgeUnconfirmWithdraw(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tfchain-withdraws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ws, err := openWithdrawStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	bridge := &Bridge{withdraws: ws}
+
+	w := newWithdrawFromEvent(WithdrawEvent{receiver: common.HexToAddress("0x1"), amount: big.NewInt(42), txHash: common.HexToHash("0x2"), blockHash: common.HexToHash("0x3"), blockHeight: 10})
+	if _, _, err = ws.Add(w); err != nil {
+		t.Fatal(err)
+	}
+	txID := types.TransactionID{1, 2, 3}
+	if err = bridge.confirmWithdraw(w.TxHash, txID); err != nil {
+		t.Fatal(err)
+	}
+
+	// reverting another coin creation transaction does not affect the withdraw
+	if err = bridge.unconfirmWithdraw(w.TxHash, types.TransactionID{4}); err != nil {
+		t.Fatal(err)
+	}
+	if w, err = ws.Get(w.TxHash); err != nil {
+		t.Fatal(err)
+	}
+	if w.State != WithdrawStateConfirmed {
+		t.Errorf("unexpected state for withdraw: %s", w.State.String())
+	}
+
+	// reverting the confirming coin creation transaction makes the withdraw pending again
+	if err = bridge.unconfirmWithdraw(w.TxHash, txID); err != nil {
+		t.Fatal(err)
+	}
+	if w, err = ws.Get(w.TxHash); err != nil {
+		t.Fatal(err)
+	}
+	if w.State != WithdrawStateMatured || w.TFTTransactionID != (types.TransactionID{}) {
+		t.Errorf("unexpected reverted withdraw: %v", w)
+	}
+
+	// unknown withdraws are ignored
+	if err = bridge.unconfirmWithdraw(tfchaintypes.ERC20Hash{5}, txID); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWithdrawStateString(t *testing.T) {
+	for state := WithdrawStateSeen; state <= WithdrawStateFailed; state++ {
+		var loaded WithdrawState
+		if err := loaded.LoadString(state.String()); err != nil {
+			t.Error(state, err)
+			continue
+		}
+		if loaded != state {
+			t.Errorf("unexpected loaded state %d, expected %d", loaded, state)
+		}
+	}
+	var state WithdrawState
+	if err := state.LoadString("foo"); err == nil {
+		t.Error("expected unknown state to fail to load")
+	}
+}
+
+// poolWithTransactions is a transaction pool which only knows the transactions it was given
+type poolWithTransactions struct {
+	modules.TransactionPool
+	txs map[types.TransactionID]types.Transaction
+}
+
+func (tp poolWithTransactions) Transaction(id types.TransactionID) (types.Transaction, error) {
+	tx, ok := tp.txs[id]
+	if !ok {
+		return types.Transaction{}, modules.ErrTransactionNotFound
+	}
+	return tx, nil
+}
+
+func TestBridgeIsSubmittedWithdrawLost(t *testing.T) {
+	bridge := &Bridge{tp: poolWithTransactions{txs: map[types.TransactionID]types.Transaction{
+		{1}: {},
+	}}}
+	now := time.Now()
+	submitted := now.Add(-SubmittedWithdrawTimeout).Unix()
+
+	testCases := []struct {
+		Withdraw Withdraw
+		Lost     bool
+	}{
+		// still in the transaction pool
+		{Withdraw{State: WithdrawStateSubmitted, TFTTransactionID: types.TransactionID{1}, LastUpdate: submitted}, false},
+		// missing, but only for a short while
+		{Withdraw{State: WithdrawStateSubmitted, TFTTransactionID: types.TransactionID{2}, LastUpdate: now.Unix()}, false},
+		// missing for at least the timeout
+		{Withdraw{State: WithdrawStateSubmitted, TFTTransactionID: types.TransactionID{2}, LastUpdate: submitted}, true},
+	}
+	for idx, testCase := range testCases {
+		if lost := bridge.isSubmittedWithdrawLost(testCase.Withdraw, now); lost != testCase.Lost {
+			t.Errorf("#%d: unexpected lost state: %v", idx, lost)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+// Package metrics provides a minimal set of metric types (counters and gauges),
+// which can be exposed over HTTP using the Prometheus text exposition format,
+// such that the tfchain daemons can be monitored (and alerted on) by a Prometheus server.
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// The metric types supported by this package,
+// as defined by the Prometheus text exposition format.
+const (
+	TypeCounter = "counter"
+	TypeGauge   = "gauge"
+)
+
+// Metric is a single metric family, which can be registered to a Registry.
+type Metric interface {
+	// Name of the metric (family).
+	Name() string
+	// Help describes the metric.
+	Help() string
+	// Type of the metric, one of {counter, gauge}.
+	Type() string
+	// Samples returns the current values of the metric.
+	Samples() []Sample
+}
+
+// Sample is a single value of a metric, optionally identified by labels.
+type Sample struct {
+	Labels []Label
+	Value  float64
+}
+
+// Label is a name-value pair identifying a sample within a metric family.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Counter is a metric which value can only increase.
+type Counter struct {
+	name, help string
+	value      uint64
+}
+
+// NewCounter creates a new Counter.
+func NewCounter(name, help string) *Counter {
+	return &Counter{name: name, help: help}
+}
+
+// Inc increases the counter by one.
+func (c *Counter) Inc() {
+	atomic.AddUint64(&c.value, 1)
+}
+
+// Add the given value to the counter.
+func (c *Counter) Add(n uint64) {
+	atomic.AddUint64(&c.value, n)
+}
+
+// Value returns the current value of the counter.
+func (c *Counter) Value() uint64 {
+	return atomic.LoadUint64(&c.value)
+}
+
+// Name implements Metric.Name
+func (c *Counter) Name() string { return c.name }
+
+// Help implements Metric.Help
+func (c *Counter) Help() string { return c.help }
+
+// Type implements Metric.Type
+func (c *Counter) Type() string { return TypeCounter }
+
+// Samples implements Metric.Samples
+func (c *Counter) Samples() []Sample {
+	return []Sample{{Value: float64(c.Value())}}
+}
+
+// CounterVec is a counter metric family, partitioned by a fixed set of labels.
+type CounterVec struct {
+	name, help string
+	labelNames []string
+
+	mu       sync.RWMutex
+	counters map[string]*labeledCounter
+}
+
+type labeledCounter struct {
+	labels  []Label
+	counter Counter
+}
+
+// NewCounterVec creates a new CounterVec, partitioned by the given label names.
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		counters:   make(map[string]*labeledCounter),
+	}
+}
+
+// WithLabelValues returns the counter for the given label values,
+// creating it if it doesn't exist yet. The amount of values has to equal
+// the amount of label names the CounterVec was created with.
+func (cv *CounterVec) WithLabelValues(values ...string) *Counter {
+	if len(values) != len(cv.labelNames) {
+		panic(fmt.Sprintf("metric %s: expected %d label values, got %d", cv.name, len(cv.labelNames), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	cv.mu.RLock()
+	lc, ok := cv.counters[key]
+	cv.mu.RUnlock()
+	if ok {
+		return &lc.counter
+	}
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	if lc, ok = cv.counters[key]; ok {
+		return &lc.counter
+	}
+	lc = &labeledCounter{labels: make([]Label, len(values))}
+	for i, value := range values {
+		lc.labels[i] = Label{Name: cv.labelNames[i], Value: value}
+	}
+	cv.counters[key] = lc
+	return &lc.counter
+}
+
+// Name implements Metric.Name
+func (cv *CounterVec) Name() string { return cv.name }
+
+// Help implements Metric.Help
+func (cv *CounterVec) Help() string { return cv.help }
+
+// Type implements Metric.Type
+func (cv *CounterVec) Type() string { return TypeCounter }
+
+// Samples implements Metric.Samples
+func (cv *CounterVec) Samples() []Sample {
+	cv.mu.RLock()
+	keys := make([]string, 0, len(cv.counters))
+	for key := range cv.counters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	samples := make([]Sample, 0, len(keys))
+	for _, key := range keys {
+		lc := cv.counters[key]
+		samples = append(samples, Sample{Labels: lc.labels, Value: float64(lc.counter.Value())})
+	}
+	cv.mu.RUnlock()
+	return samples
+}
+
+// Gauge is a metric which value can arbitrarily go up and down.
+type Gauge struct {
+	name, help string
+	bits       uint64
+}
+
+// NewGauge creates a new Gauge.
+func NewGauge(name, help string) *Gauge {
+	return &Gauge{name: name, help: help}
+}
+
+// Set the gauge to the given value.
+func (g *Gauge) Set(value float64) {
+	atomic.StoreUint64(&g.bits, math.Float64bits(value))
+}
+
+// SetBool sets the gauge to 1 if the given value is true, and to 0 otherwise.
+func (g *Gauge) SetBool(value bool) {
+	if value {
+		g.Set(1)
+	} else {
+		g.Set(0)
+	}
+}
+
+// Value returns the current value of the gauge.
+func (g *Gauge) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&g.bits))
+}
+
+// Name implements Metric.Name
+func (g *Gauge) Name() string { return g.name }
+
+// Help implements Metric.Help
+func (g *Gauge) Help() string { return g.help }
+
+// Type implements Metric.Type
+func (g *Gauge) Type() string { return TypeGauge }
+
+// Samples implements Metric.Samples
+func (g *Gauge) Samples() []Sample {
+	return []Sample{{Value: g.Value()}}
+}
+
+// GaugeFunc is a gauge which value is computed at collection time,
+// using the given function. No sample is exposed for the gauge
+// at times the function returns false.
+type GaugeFunc struct {
+	name, help string
+	fn         func() (float64, bool)
+}
+
+// NewGaugeFunc creates a new GaugeFunc.
+func NewGaugeFunc(name, help string, fn func() (float64, bool)) *GaugeFunc {
+	if fn == nil {
+		panic(fmt.Sprintf("metric %s: no gauge function given", name))
+	}
+	return &GaugeFunc{name: name, help: help, fn: fn}
+}
+
+// Name implements Metric.Name
+func (gf *GaugeFunc) Name() string { return gf.name }
+
+// Help implements Metric.Help
+func (gf *GaugeFunc) Help() string { return gf.help }
+
+// Type implements Metric.Type
+func (gf *GaugeFunc) Type() string { return TypeGauge }
+
+// Samples implements Metric.Samples
+func (gf *GaugeFunc) Samples() []Sample {
+	value, ok := gf.fn()
+	if !ok {
+		return nil
+	}
+	return []Sample{{Value: value}}
+}
+
+// Registry is a collection of metrics, which can be exposed together.
+type Registry struct {
+	mu      sync.RWMutex
+	metrics map[string]Metric
+}
+
+// NewRegistry creates a new (empty) Registry.
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]Metric)}
+}
+
+// DefaultRegistry is the registry used by the package-level functions,
+// and is the registry exposed by the tfchain daemons.
+var DefaultRegistry = NewRegistry()
+
+// Register the given metrics, returning an error if
+// a metric with the same name was already registered.
+func (r *Registry) Register(metrics ...Metric) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, metric := range metrics {
+		if _, ok := r.metrics[metric.Name()]; ok {
+			return fmt.Errorf("metric %s is already registered", metric.Name())
+		}
+	}
+	for _, metric := range metrics {
+		r.metrics[metric.Name()] = metric
+	}
+	return nil
+}
+
+// MustRegister registers the given metrics, panicking should it fail.
+func (r *Registry) MustRegister(metrics ...Metric) {
+	if err := r.Register(metrics...); err != nil {
+		panic(err)
+	}
+}
+
+// Unregister the metric with the given name, returning false if no such metric was registered.
+func (r *Registry) Unregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.metrics[name]
+	delete(r.metrics, name)
+	return ok
+}
+
+// WriteTo writes all registered metrics, sorted by name,
+// to the given writer, using the Prometheus text exposition format.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.RLock()
+	metrics := make([]Metric, 0, len(r.metrics))
+	for _, metric := range r.metrics {
+		metrics = append(metrics, metric)
+	}
+	r.mu.RUnlock()
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].Name() < metrics[j].Name()
+	})
+
+	var buf bytes.Buffer
+	for _, metric := range metrics {
+		fmt.Fprintf(&buf, "# HELP %s %s\n", metric.Name(), escapeHelp(metric.Help()))
+		fmt.Fprintf(&buf, "# TYPE %s %s\n", metric.Name(), metric.Type())
+		for _, sample := range metric.Samples() {
+			buf.WriteString(metric.Name())
+			if len(sample.Labels) > 0 {
+				buf.WriteByte('{')
+				for i, label := range sample.Labels {
+					if i > 0 {
+						buf.WriteByte(',')
+					}
+					fmt.Fprintf(&buf, "%s=\"%s\"", label.Name, escapeLabelValue(label.Value))
+				}
+				buf.WriteByte('}')
+			}
+			buf.WriteByte(' ')
+			buf.WriteString(formatValue(sample.Value))
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.WriteTo(w)
+}
+
+// Handler returns an HTTP handler which exposes all registered metrics,
+// using the Prometheus text exposition format.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteTo(w)
+	})
+}
+
+// MustRegister registers the given metrics to the DefaultRegistry, panicking should it fail.
+func MustRegister(metrics ...Metric) {
+	DefaultRegistry.MustRegister(metrics...)
+}
+
+// Handler returns an HTTP handler which exposes all metrics registered to the DefaultRegistry.
+func Handler() http.Handler {
+	return DefaultRegistry.Handler()
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+var (
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelValueEscaper.Replace(s)
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistryWriteTo(t *testing.T) {
+	registry := NewRegistry()
+
+	counter := NewCounter("test_counter_total", "a test counter")
+	counter.Add(2)
+	counter.Inc()
+
+	counterVec := NewCounterVec("test_labeled_total", "a labeled\ntest counter", "type", "action")
+	counterVec.WithLabelValues("foo", "applied").Inc()
+	counterVec.WithLabelValues("foo", "applied").Inc()
+	counterVec.WithLabelValues("b\"ar", "reverted").Inc()
+
+	gauge := NewGauge("test_gauge", "a test gauge")
+	gauge.Set(4.5)
+
+	enabled := false
+	gaugeFunc := NewGaugeFunc("test_gauge_func", "a test gauge func", func() (float64, bool) {
+		return math.Inf(1), enabled
+	})
+
+	registry.MustRegister(counter, counterVec, gauge, gaugeFunc)
+	if err := registry.Register(NewGauge("test_gauge", "duplicate")); err == nil {
+		t.Error("expected registering a duplicate metric to fail")
+	}
+
+	var buf bytes.Buffer
+	if _, err := registry.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := `# HELP test_counter_total a test counter
+# TYPE test_counter_total counter
+test_counter_total 3
+# HELP test_gauge a test gauge
+# TYPE test_gauge gauge
+test_gauge 4.5
+# HELP test_gauge_func a test gauge func
+# TYPE test_gauge_func gauge
+# HELP test_labeled_total a labeled\ntest counter
+# TYPE test_labeled_total counter
+test_labeled_total{type="b\"ar",action="reverted"} 1
+test_labeled_total{type="foo",action="applied"} 2
+`
+	if output := buf.String(); output != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", output, expected)
+	}
+
+	enabled = true
+	rec := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if !strings.Contains(rec.Body.String(), "test_gauge_func +Inf\n") {
+		t.Errorf("expected gauge func sample in output:\n%s", rec.Body.String())
+	}
+	if contentType := rec.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain") {
+		t.Errorf("unexpected content type: %s", contentType)
+	}
+
+	if !registry.Unregister("test_gauge") || registry.Unregister("test_gauge") {
+		t.Error("unexpected unregister result")
+	}
+}
+
+func TestCounterVecInvalidLabelValues(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for an invalid amount of label values")
+		}
+	}()
+	NewCounterVec("test_total", "test", "type").WithLabelValues("foo", "bar")
+}
@@ -0,0 +1,98 @@
+package persist
+
+import (
+	"github.com/threefoldfoundation/tfchain/pkg/metrics"
+	"github.com/threefoldfoundation/tfchain/pkg/types"
+
+	rivinetypes "github.com/threefoldtech/rivine/types"
+)
+
+// labels used for the action of the transactions counter
+const (
+	metricsActionApplied  = "applied"
+	metricsActionReverted = "reverted"
+)
+
+// metricsTransactionTypes maps all transaction versions tracked by
+// the transactions counter of the TransactionDB to their metric label
+var metricsTransactionTypes = map[rivinetypes.TransactionVersion]string{
+	types.TransactionVersionMinterDefinition:               "minter_definition",
+	types.TransactionVersionCoinCreation:                   "coin_creation",
+	types.TransactionVersionCoinBurn:                       "coin_burn",
+	types.TransactionVersionMintingCapDefinition:           "minting_cap_definition",
+	types.TransactionVersionConsensusParameterUpdate:       "consensus_parameter_update",
+	types.TransactionVersionBotRegistration:                "bot_registration",
+	types.TransactionVersionBotRecordUpdate:                "bot_record_update",
+	types.TransactionVersionBotNameTransfer:                "bot_name_transfer",
+	types.TransactionVersionBotKeyRotation:                 "bot_key_rotation",
+	types.TransactionVersionBotRecordUpdateWithFulfillment: "bot_record_update_with_fulfillment",
+	types.TransactionVersionBotNameTransferWithFulfillment: "bot_name_transfer_with_fulfillment",
+	types.TransactionVersionBotNameSale:                    "bot_name_sale",
+	types.TransactionVersionBotNameDelegation:              "bot_name_delegation",
+	types.TransactionVersionBotRecordUpdateWithServices:    "bot_record_update_with_services",
+	types.TransactionVersionERC20Conversion:                "erc20_conversion",
+	types.TransactionVersionERC20CoinCreation:              "erc20_coin_creation",
+	types.TransactionVersionERC20AddressRegistration:       "erc20_address_registration",
+	types.TransactionVersionCapacityRegistration:           "capacity_registration",
+	types.TransactionVersionFarmRegistration:               "farm_registration",
+	types.TransactionVersionFarmUpdate:                     "farm_update",
+	types.TransactionVersionCapacityProof:                  "capacity_proof",
+}
+
+// transactionDBMetrics groups all metrics exposed by the TransactionDB,
+// these are only updated once a consensus change has been successfully committed.
+type transactionDBMetrics struct {
+	blockHeight  *metrics.Gauge
+	chainTime    *metrics.Gauge
+	synced       *metrics.Gauge
+	transactions *metrics.CounterVec
+}
+
+func newTransactionDBMetrics() *transactionDBMetrics {
+	return &transactionDBMetrics{
+		blockHeight: metrics.NewGauge(
+			"tfchain_txdb_block_height",
+			"Block height up to which the transaction database has processed the consensus set."),
+		chainTime: metrics.NewGauge(
+			"tfchain_txdb_chain_time_seconds",
+			"Timestamp (unix epoch) of the last block processed by the transaction database."),
+		synced: metrics.NewGauge(
+			"tfchain_txdb_synced",
+			"1 if the transaction database is synced with the consensus set, 0 otherwise."),
+		transactions: metrics.NewCounterVec(
+			"tfchain_txdb_transactions_total",
+			"Amount of tfchain-specific transactions applied and reverted by the transaction database.",
+			"type", "action"),
+	}
+}
+
+func (m *transactionDBMetrics) updateStats(stats transactionDBStats) {
+	m.blockHeight.Set(float64(stats.BlockHeight))
+	m.chainTime.Set(float64(stats.ChainTime))
+	m.synced.SetBool(stats.Synced)
+}
+
+// countTransactions increases the transactions counter
+// for all tracked transactions found in the given blocks
+func (m *transactionDBMetrics) countTransactions(blocks []rivinetypes.Block, action string) {
+	for _, block := range blocks {
+		for _, tx := range block.Transactions {
+			txType, ok := metricsTransactionTypes[tx.Version]
+			if !ok {
+				continue
+			}
+			m.transactions.WithLabelValues(txType, action).Inc()
+		}
+	}
+}
+
+// RegisterMetrics registers all metrics of the TransactionDB to the given registry,
+// such that they can be exposed (e.g. to be scraped by Prometheus).
+func (txdb *TransactionDB) RegisterMetrics(reg *metrics.Registry) error {
+	return reg.Register(
+		txdb.metrics.blockHeight,
+		txdb.metrics.chainTime,
+		txdb.metrics.synced,
+		txdb.metrics.transactions,
+	)
+}
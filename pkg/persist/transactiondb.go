@@ -1,12 +1,14 @@
 package persist
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path"
+	"sort"
 
 	"github.com/threefoldfoundation/tfchain/pkg/persist/internal"
 	"github.com/threefoldfoundation/tfchain/pkg/types"
@@ -49,24 +51,50 @@ const (
 
 // internal bucket database keys used for the transactionDB
 var (
-	bucketInternal         = []byte("internal")
-	bucketInternalKeyStats = []byte("stats") // stored as a single struct, see `transactionDBStats`
+	bucketInternal          = []byte("internal")
+	bucketInternalKeyStats  = []byte("stats")  // stored as a single struct, see `transactionDBStats`
+	bucketInternalKeySupply = []byte("supply") // stored as a single struct, see `CoinSupply`
 
 	// getBucketMintConditionPerHeightRangeKey is used to compute the keys
 	// of the values in this bucket
 	bucketMintConditions = []byte("mintconditions")
+	bucketCoinCreations  = []byte("coincreations") // short txID => CoinCreationRecord
+	bucketCoinBurns      = []byte("coinburns")     // short txID => CoinBurnRecord
+	bucketMintingCaps    = []byte("mintingcaps")   // short txID => MintingCapRecord
+
+	// getConsensusParametersAt is used to compute the consensus parameters
+	// from the updates stored in this bucket
+	bucketConsensusParameters = []byte("consensusparameters") // effective height + short txID => []ConsensusParameterUpdate
 
 	// buckets for the 3bot feature
-	bucketBotRecords               = []byte("botrecords")      // ID => name
-	bucketBotKeyToIDMapping        = []byte("botkeys")         // Key => ID
-	bucketBotNameToIDMapping       = []byte("botnames")        // Name => ID
-	bucketBotRecordImplicitUpdates = []byte("botimplupdates")  // txID => implicitBotRecordUpdate
-	bucketBotTransactions          = []byte("bottransactions") // ID => []txID
+	bucketBotRecords               = []byte("botrecords")       // ID => name
+	bucketBotKeyToIDMapping        = []byte("botkeys")          // Key => ID
+	bucketBotNameToIDMapping       = []byte("botnames")         // Name => ID
+	bucketBotAddressToIDsMapping   = []byte("botaddresses")     // NetworkAddress => (ID => nil)
+	bucketBotRecordImplicitUpdates = []byte("botimplupdates")   // txID => implicitBotRecordUpdate
+	bucketBotTransactions          = []byte("bottransactions")  // ID => []txID
+	bucketBotRecordHistory         = []byte("botrecordhistory") // ID => (short txID => BotRecordVersion)
+	bucketBotNameHistory           = []byte("botnamehistory")   // Name => (short txID => BotNameOwnership)
+	bucketBotNameChildren          = []byte("botnamechildren")  // Name => (child Name => nil)
+	bucketBotNameDelegates         = []byte("botnamedelegates") // Name => (owner ID => (delegate ID => nil))
+	bucketBotRegistrations         = []byte("botregistrations") // ID (big endian) => registration height
 
 	// buckets for the ERC20-bridge feature
 	bucketERC20ToTFTAddresses = []byte("addresses_erc20_to_tft") // erc20 => TFT
 	bucketTFTToERC20Addresses = []byte("addresses_tft_to_erc20") // TFT => erc20
 	bucketERC20TransactionIDs = []byte("erc20_transactionids")   // stores all unique ERC20 transaction ids used for erc20=>TFT exchanges
+
+	// buckets for the capacity registration feature
+	bucketCapacityNodes = []byte("capacitynodes") // node key => (short txID => CapacityRecord)
+	bucketCapacityFarms = []byte("capacityfarms") // farm ID => (short txID => node key)
+
+	// buckets for the proof of capacity feature
+	bucketCapacityChallenges = []byte("capacitychallenges") // challenge height => block ID
+	bucketCapacityProofs     = []byte("capacityproofs")     // node key => (challenge height => CapacityProofResult)
+
+	// buckets for the farm registry feature
+	bucketFarmRecords         = []byte("farmrecords") // farm ID => (short txID => FarmRecord)
+	bucketFarmNameToIDMapping = []byte("farmnames")   // name => farm ID
 )
 
 type (
@@ -85,9 +113,115 @@ type (
 		db    *persist.BoltDatabase
 		stats transactionDBStats
 
+		// genesisConsensusParameters are the consensus parameters that apply
+		// prior to any of the consensus parameter updates stored in the DB
+		genesisConsensusParameters types.ConsensusParameters
+
+		metrics *transactionDBMetrics
+
 		subscriber *transactionDBCSSubscriber
 	}
 
+	// BotRecordFilter is used to filter the bot records listed by (*TransactionDB).GetRecords.
+	// The zero value of each property disables the filter it defines.
+	BotRecordFilter struct {
+		// ExpiresAfter and ExpiresBefore define the (inclusive) window
+		// in which the expiration date of a listed bot has to be.
+		ExpiresAfter  rivinetypes.Timestamp
+		ExpiresBefore rivinetypes.Timestamp
+		// HasName only lists bots that (still) own at least one name.
+		HasName bool
+		// RegisteredSince and RegisteredBefore define the range of (consensus)
+		// block heights in which a listed bot has to be registered,
+		// with RegisteredBefore being exclusive.
+		RegisteredSince  rivinetypes.BlockHeight
+		RegisteredBefore rivinetypes.BlockHeight
+	}
+
+	// BotRecordListing is a bot record as listed by (*TransactionDB).GetRecords,
+	// along with the (consensus) block height at which the bot was registered.
+	BotRecordListing struct {
+		Record             types.BotRecord
+		RegistrationHeight rivinetypes.BlockHeight
+	}
+
+	// CoinCreationType defines the type of transaction that created coins,
+	// as recorded by the TransactionDB.
+	CoinCreationType uint8
+
+	// CoinCreationRecord is the record of a transaction that created coins,
+	// as listed by (*TransactionDB).GetCoinCreations.
+	CoinCreationRecord struct {
+		TransactionID rivinetypes.TransactionID
+		// BlockHeight is the (consensus) block height of the block that contains the transaction.
+		BlockHeight rivinetypes.BlockHeight
+		BlockTime   rivinetypes.Timestamp
+		Type        CoinCreationType
+		// Value is the total amount of coins created by the transaction,
+		// including its miner fees, as those are created by the transaction as well.
+		Value rivinetypes.Currency
+		// Minter is the unlock hash of the mint condition that was fulfilled in order to create the coins,
+		// only defined for coin creation transactions.
+		Minter rivinetypes.UnlockHash
+		// ArbitraryData is the optional data, usually the reason, attached to the transaction.
+		ArbitraryData []byte
+	}
+
+	// CoinCreationFilter is used to filter the records listed by (*TransactionDB).GetCoinCreations.
+	// The zero value of each property disables the filter it defines.
+	CoinCreationFilter struct {
+		Type CoinCreationType
+		// Minter only lists coin creation transactions which fulfilled the
+		// mint condition with the given unlock hash.
+		Minter rivinetypes.UnlockHash
+		// Since and Before define the range of block timestamps
+		// in which a listed record was created, with Before being exclusive.
+		Since  rivinetypes.Timestamp
+		Before rivinetypes.Timestamp
+	}
+
+	// CoinBurnRecord is the record of a coin burn transaction,
+	// as listed by (*TransactionDB).GetCoinBurns.
+	CoinBurnRecord struct {
+		TransactionID rivinetypes.TransactionID
+		// BlockHeight is the (consensus) block height of the block that contains the transaction.
+		BlockHeight rivinetypes.BlockHeight
+		BlockTime   rivinetypes.Timestamp
+		// Value is the amount of coins burned, excluding the miner fees.
+		Value  rivinetypes.Currency
+		Reason []byte
+	}
+
+	// MintingCapRecord is the record of a minting cap definition transaction,
+	// as used by (*TransactionDB).GetMintingCapSince.
+	MintingCapRecord struct {
+		TransactionID rivinetypes.TransactionID
+		// BlockHeight is the (consensus) block height of the block that contains the transaction.
+		BlockHeight rivinetypes.BlockHeight
+		BlockTime   rivinetypes.Timestamp
+		MintingCap  rivinetypes.Currency
+	}
+
+	// CoinSupply tracks the coins that were created and destroyed
+	// by all blocks and transactions applied to the TransactionDB.
+	CoinSupply struct {
+		// Genesis are the coins allocated by the genesis block.
+		Genesis rivinetypes.Currency
+		// BlockRewards are the coins created as part of the miner payouts,
+		// excluding the transaction fees, as those were created (or spent) by the transactions.
+		BlockRewards rivinetypes.Currency
+		// Minted are the coins created by coin creation transactions.
+		Minted rivinetypes.Currency
+		// ERC20CreatedIn are the coins created by ERC20 coin creation transactions,
+		// in exchange for ERC20 funds sent to the bridge.
+		ERC20CreatedIn rivinetypes.Currency
+		// ERC20ConvertedOut are the coins destroyed by ERC20 convert transactions,
+		// in exchange for ERC20 funds.
+		ERC20ConvertedOut rivinetypes.Currency
+		// Burned are the coins destroyed by coin burn transactions.
+		Burned rivinetypes.Currency
+	}
+
 	// implements modules.ConsensusSetSubscriber,
 	// such that the TransactionDB does not have to publicly implement
 	// the ConsensusSetSubscriber interface, allowing us to "force"
@@ -105,19 +239,68 @@ type (
 	}
 )
 
+// The types of transactions that create coins.
+const (
+	CoinCreationTypeMint CoinCreationType = iota + 1
+	CoinCreationTypeERC20
+)
+
+// String returns the CoinCreationType as a string.
+func (ct CoinCreationType) String() string {
+	switch ct {
+	case CoinCreationTypeMint:
+		return "mint"
+	case CoinCreationTypeERC20:
+		return "erc20"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(ct))
+	}
+}
+
+// LoadString loads the CoinCreationType from a string.
+func (ct *CoinCreationType) LoadString(str string) error {
+	switch str {
+	case "mint":
+		*ct = CoinCreationTypeMint
+	case "erc20":
+		*ct = CoinCreationTypeERC20
+	default:
+		return fmt.Errorf("unknown coin creation type %q", str)
+	}
+	return nil
+}
+
+// Total returns the total amount of coins in circulation.
+func (supply CoinSupply) Total() rivinetypes.Currency {
+	return supply.Genesis.Add(supply.BlockRewards).Add(supply.Minted).Add(supply.ERC20CreatedIn).Sub(supply.ERC20ConvertedOut).Sub(supply.Burned)
+}
+
 var (
 	// ensure TransactionDB implements the MintConditionGetter interface
 	_ types.MintConditionGetter = (*TransactionDB)(nil)
+	// ensure TransactionDB implements the MintingCapGetter interface
+	_ types.MintingCapGetter = (*TransactionDB)(nil)
+	// ensure TransactionDB implements the ConsensusParameterGetter interface
+	_ types.ConsensusParameterGetter = (*TransactionDB)(nil)
 	// ensure TransactionDB implements the BotRecordReadRegistry interface
 	_ types.BotRecordReadRegistry = (*TransactionDB)(nil)
 	// ensure TransactionDB implements the ERC20Registry interface
 	_ types.ERC20Registry = (*TransactionDB)(nil)
+	// ensure TransactionDB implements the FarmerConditionGetter interface
+	_ types.FarmerConditionGetter = (*TransactionDB)(nil)
+	// ensure TransactionDB implements the FoundationConditionGetter interface
+	_ types.FoundationConditionGetter = (*TransactionDB)(nil)
+	// ensure TransactionDB implements the FarmRecordReadRegistry interface
+	_ types.FarmRecordReadRegistry = (*TransactionDB)(nil)
+	// ensure TransactionDB implements the CapacityProofReadRegistry interface
+	_ types.CapacityProofReadRegistry = (*TransactionDB)(nil)
 )
 
 // NewTransactionDB creates a new TransactionDB, using the given file (path) to store the (single) persistent BoltDB file.
 // A new db will be created if it doesn't exist yet, if it does exist it should be ensured that the given genesis mint condition
 // equals the already stored genesis mint condition.
-func NewTransactionDB(rootDir string, genesisMintCondition rivinetypes.UnlockConditionProxy) (*TransactionDB, error) {
+// The genesis consensus parameters apply until updated by a ConsensusParameterUpdate Transaction.
+func NewTransactionDB(rootDir string, genesisMintCondition rivinetypes.UnlockConditionProxy, genesisConsensusParameters types.ConsensusParameters) (*TransactionDB, error) {
 	persistDir := path.Join(rootDir, TransactionDBDir)
 	// Create the directory if it doesn't exist.
 	err := os.MkdirAll(persistDir, 0700)
@@ -125,11 +308,15 @@ func NewTransactionDB(rootDir string, genesisMintCondition rivinetypes.UnlockCon
 		return nil, err
 	}
 
-	txdb := new(TransactionDB)
+	txdb := &TransactionDB{
+		genesisConsensusParameters: genesisConsensusParameters,
+		metrics:                    newTransactionDBMetrics(),
+	}
 	err = txdb.openDB(path.Join(persistDir, TransactionDBFilename), genesisMintCondition)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open the transaction DB: %v", err)
 	}
+	txdb.metrics.updateStats(txdb.stats)
 	return txdb, nil
 }
 
@@ -138,6 +325,26 @@ func (txdb *TransactionDB) GetLastConsensusChangeID() modules.ConsensusChangeID
 	return txdb.stats.ConsensusChangeID
 }
 
+// GetChainTime returns the timestamp of the last block applied to the TransactionDB,
+// which is used as the current time to define the status of 3bots.
+func (txdb *TransactionDB) GetChainTime() rivinetypes.Timestamp {
+	return txdb.stats.ChainTime
+}
+
+// GetBotRecordStatus returns the status of the bot, as indicated by the given record,
+// at the current chain time and using the grace period that applies to the next block.
+func (txdb *TransactionDB) GetBotRecordStatus(record *types.BotRecord) (status types.BotRecordStatus, err error) {
+	err = txdb.db.View(func(tx *bolt.Tx) error {
+		gracePeriod, err := txdb.getBotNameGracePeriodAt(tx, txdb.stats.BlockHeight)
+		if err != nil {
+			return err
+		}
+		status = record.Status(txdb.stats.ChainTime, gracePeriod)
+		return nil
+	})
+	return
+}
+
 // SubscribeToConsensusSet subscribes the TransactionDB to the given ConsensusSet,
 // allowing it to stay in sync with the blockchain, and also making it automatically unsubscribe
 // from the consensus set when the TransactionDB is closed (using (*TransactionDB).Close).
@@ -192,43 +399,38 @@ func (txdb *TransactionDB) GetActiveMintCondition() (rivinetypes.UnlockCondition
 }
 
 // GetMintConditionAt implements types.MintConditionGetter.GetMintConditionAt
-func (txdb *TransactionDB) GetMintConditionAt(height rivinetypes.BlockHeight) (rivinetypes.UnlockConditionProxy, error) {
-	var b []byte
-	err := txdb.db.View(func(tx *bolt.Tx) (err error) {
-		mintConditionsBucket := tx.Bucket(bucketMintConditions)
-		if mintConditionsBucket == nil {
-			return errors.New("corrupt transaction DB: mint conditions bucket does not exist")
-		}
+func (txdb *TransactionDB) GetMintConditionAt(height rivinetypes.BlockHeight) (mintCondition rivinetypes.UnlockConditionProxy, err error) {
+	err = txdb.db.View(func(tx *bolt.Tx) (err error) {
+		mintCondition, err = getMintConditionAt(tx, height)
+		return err
+	})
+	return
+}
 
-		cursor := mintConditionsBucket.Cursor()
+func getMintConditionAt(tx *bolt.Tx, height rivinetypes.BlockHeight) (rivinetypes.UnlockConditionProxy, error) {
+	mintConditionsBucket := tx.Bucket(bucketMintConditions)
+	if mintConditionsBucket == nil {
+		return rivinetypes.UnlockConditionProxy{}, errors.New("corrupt transaction DB: mint conditions bucket does not exist")
+	}
 
-		var k []byte
-		k, b = cursor.Seek(internal.EncodeBlockheight(height))
+	cursor := mintConditionsBucket.Cursor()
+
+	k, b := cursor.Seek(internal.EncodeBlockheight(height))
+	if len(k) == 0 {
+		// could be that we're past the last key, let's try the last key first
+		k, b = cursor.Last()
 		if len(k) == 0 {
-			// could be that we're past the last key, let's try the last key first
-			k, b = cursor.Last()
-			if len(k) == 0 {
-				return errors.New("corrupt transaction DB: no matching mint condition could be found")
-			}
-			return nil
-		}
-		foundHeight := internal.DecodeBlockheight(k)
-		if foundHeight <= height {
-			return nil
+			return rivinetypes.UnlockConditionProxy{}, errors.New("corrupt transaction DB: no matching mint condition could be found")
 		}
+	} else if foundHeight := internal.DecodeBlockheight(k); foundHeight > height {
 		k, b = cursor.Prev()
 		if len(k) == 0 {
-			return errors.New("corrupt transaction DB: no matching mint condition could be found")
+			return rivinetypes.UnlockConditionProxy{}, errors.New("corrupt transaction DB: no matching mint condition could be found")
 		}
-		return nil
-
-	})
-	if err != nil {
-		return rivinetypes.UnlockConditionProxy{}, err
 	}
 
 	var mintCondition rivinetypes.UnlockConditionProxy
-	err = siabin.Unmarshal(b, &mintCondition)
+	err := siabin.Unmarshal(b, &mintCondition)
 	if err != nil {
 		return rivinetypes.UnlockConditionProxy{}, fmt.Errorf("corrupt transaction DB: failed to decode found mint condition: %v", err)
 	}
@@ -236,6 +438,252 @@ func (txdb *TransactionDB) GetMintConditionAt(height rivinetypes.BlockHeight) (r
 	return mintCondition, nil
 }
 
+// GetCoinCreations returns the transactions that created coins (and thus extended the coin supply)
+// matching the given filter, ordered by the (short) transaction ID, and thus by block height,
+// starting after the given (exclusive) cursor, or from the start in case the cursor is 0.
+// If more records (could) match the filter, the cursor of the next page is returned as well.
+// The total amount of coins created by all matching transactions, not just the listed ones, is returned as well.
+func (txdb *TransactionDB) GetCoinCreations(cursor rivinetypes.TransactionShortID, limit int, filter CoinCreationFilter) (records []CoinCreationRecord, next rivinetypes.TransactionShortID, total rivinetypes.Currency, err error) {
+	if limit <= 0 {
+		return nil, 0, rivinetypes.Currency{}, errors.New("the amount of coin creations to list has to be positive")
+	}
+	err = txdb.db.View(func(tx *bolt.Tx) error {
+		coinCreationsBucket := tx.Bucket(bucketCoinCreations)
+		if coinCreationsBucket == nil {
+			return errors.New("corrupt transaction DB: coin creations bucket does not exist")
+		}
+		c := coinCreationsBucket.Cursor()
+		var (
+			k, v       []byte
+			lastListed sortableTransactionShortID
+		)
+		if cursor == 0 {
+			k, v = c.First()
+		} else {
+			cursorKey := rivbin.Marshal(sortableTransactionShortID(cursor))
+			k, v = c.Seek(cursorKey)
+			if bytes.Equal(k, cursorKey) {
+				k, v = c.Next()
+			}
+		}
+		for ; k != nil; k, v = c.Next() {
+			var record CoinCreationRecord
+			err := rivbin.Unmarshal(v, &record)
+			if err != nil {
+				return fmt.Errorf("corrupt transaction DB: failed to decode coin creation record: %v", err)
+			}
+			if !filter.matchesRecord(record) {
+				continue
+			}
+			total = total.Add(record.Value)
+			if len(records) == limit {
+				// another record matches the filter, hence a cursor is returned for the next page,
+				// the iteration continues however, as to compute the total of all matching records
+				next = rivinetypes.TransactionShortID(lastListed)
+				continue
+			}
+			err = rivbin.Unmarshal(k, &lastListed)
+			if err != nil {
+				return fmt.Errorf("corrupt transaction DB: failed to decode coin creation key: %v", err)
+			}
+			records = append(records, record)
+		}
+		return nil
+	})
+	return
+}
+
+// matchesRecord returns true if the given coin creation record matches the filter.
+func (filter CoinCreationFilter) matchesRecord(record CoinCreationRecord) bool {
+	if filter.Type != 0 && record.Type != filter.Type {
+		return false
+	}
+	if filter.Minter.Type != 0 && record.Minter.Cmp(filter.Minter) != 0 {
+		return false
+	}
+	if record.BlockTime < filter.Since {
+		return false
+	}
+	if filter.Before != 0 && record.BlockTime >= filter.Before {
+		return false
+	}
+	return true
+}
+
+// GetCoinBurns returns the coin burn transactions, ordered by the (short) transaction ID, and thus by block height,
+// starting after the given (exclusive) cursor, or from the start in case the cursor is 0.
+// If more records exist, the cursor of the next page is returned as well.
+// The total amount of coins burned by all transactions past the cursor, not just the listed ones, is returned as well.
+func (txdb *TransactionDB) GetCoinBurns(cursor rivinetypes.TransactionShortID, limit int) (records []CoinBurnRecord, next rivinetypes.TransactionShortID, total rivinetypes.Currency, err error) {
+	if limit <= 0 {
+		return nil, 0, rivinetypes.Currency{}, errors.New("the amount of coin burns to list has to be positive")
+	}
+	err = txdb.db.View(func(tx *bolt.Tx) error {
+		coinBurnsBucket := tx.Bucket(bucketCoinBurns)
+		if coinBurnsBucket == nil {
+			return errors.New("corrupt transaction DB: coin burns bucket does not exist")
+		}
+		c := coinBurnsBucket.Cursor()
+		var (
+			k, v       []byte
+			lastListed sortableTransactionShortID
+		)
+		if cursor == 0 {
+			k, v = c.First()
+		} else {
+			cursorKey := rivbin.Marshal(sortableTransactionShortID(cursor))
+			k, v = c.Seek(cursorKey)
+			if bytes.Equal(k, cursorKey) {
+				k, v = c.Next()
+			}
+		}
+		for ; k != nil; k, v = c.Next() {
+			var record CoinBurnRecord
+			err := rivbin.Unmarshal(v, &record)
+			if err != nil {
+				return fmt.Errorf("corrupt transaction DB: failed to decode coin burn record: %v", err)
+			}
+			total = total.Add(record.Value)
+			if len(records) == limit {
+				// another record exists, hence a cursor is returned for the next page,
+				// the iteration continues however, as to compute the total of all records
+				next = rivinetypes.TransactionShortID(lastListed)
+				continue
+			}
+			err = rivbin.Unmarshal(k, &lastListed)
+			if err != nil {
+				return fmt.Errorf("corrupt transaction DB: failed to decode coin burn key: %v", err)
+			}
+			records = append(records, record)
+		}
+		return nil
+	})
+	return
+}
+
+// GetMintingCapSince implements types.MintingCapGetter.GetMintingCapSince
+func (txdb *TransactionDB) GetMintingCapSince(height rivinetypes.BlockHeight, since rivinetypes.Timestamp) (mintingCap rivinetypes.Currency, capped bool, err error) {
+	err = txdb.db.View(func(tx *bolt.Tx) error {
+		mintingCapsBucket := tx.Bucket(bucketMintingCaps)
+		if mintingCapsBucket == nil {
+			return errors.New("corrupt transaction DB: minting caps bucket does not exist")
+		}
+		// walk back in time, until (and including) the minting cap that was already applied at the given time
+		c := mintingCapsBucket.Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			var record MintingCapRecord
+			err := rivbin.Unmarshal(v, &record)
+			if err != nil {
+				return fmt.Errorf("corrupt transaction DB: failed to decode minting cap record: %v", err)
+			}
+			if record.BlockHeight >= height {
+				continue
+			}
+			if !capped || record.MintingCap.Cmp(mintingCap) < 0 {
+				mintingCap, capped = record.MintingCap, true
+			}
+			if record.BlockTime < since {
+				return nil
+			}
+		}
+		return nil
+	})
+	return
+}
+
+// GetMintedCoinsSince implements types.MintingCapGetter.GetMintedCoinsSince
+func (txdb *TransactionDB) GetMintedCoinsSince(height rivinetypes.BlockHeight, since rivinetypes.Timestamp) (minted rivinetypes.Currency, err error) {
+	err = txdb.db.View(func(tx *bolt.Tx) error {
+		coinCreationsBucket := tx.Bucket(bucketCoinCreations)
+		if coinCreationsBucket == nil {
+			return errors.New("corrupt transaction DB: coin creations bucket does not exist")
+		}
+		// walk back in time, until the first coin creation created before the given time
+		c := coinCreationsBucket.Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			var record CoinCreationRecord
+			err := rivbin.Unmarshal(v, &record)
+			if err != nil {
+				return fmt.Errorf("corrupt transaction DB: failed to decode coin creation record: %v", err)
+			}
+			if record.BlockHeight >= height {
+				continue
+			}
+			if record.BlockTime < since {
+				return nil
+			}
+			if record.Type == CoinCreationTypeMint {
+				minted = minted.Add(record.Value)
+			}
+		}
+		return nil
+	})
+	return
+}
+
+// GetActiveConsensusParameters implements types.ConsensusParameterGetter.GetActiveConsensusParameters
+func (txdb *TransactionDB) GetActiveConsensusParameters() (params types.ConsensusParameters, err error) {
+	err = txdb.db.View(func(tx *bolt.Tx) (err error) {
+		// the height of the TransactionDB equals the (consensus) height of the next block
+		params, err = txdb.getConsensusParametersAt(tx, txdb.stats.BlockHeight)
+		return err
+	})
+	return
+}
+
+// GetConsensusParametersAt implements types.ConsensusParameterGetter.GetConsensusParametersAt
+func (txdb *TransactionDB) GetConsensusParametersAt(height rivinetypes.BlockHeight) (params types.ConsensusParameters, err error) {
+	err = txdb.db.View(func(tx *bolt.Tx) (err error) {
+		params, err = txdb.getConsensusParametersAt(tx, height)
+		return err
+	})
+	return
+}
+
+// getBotNameGracePeriodAt returns the grace period of expired bots that applies at the given block height.
+func (txdb *TransactionDB) getBotNameGracePeriodAt(tx *bolt.Tx, height rivinetypes.BlockHeight) (types.CompactTimestamp, error) {
+	params, err := txdb.getConsensusParametersAt(tx, height)
+	if err != nil {
+		return 0, err
+	}
+	return params.BotNameGracePeriodAt(height), nil
+}
+
+func (txdb *TransactionDB) getConsensusParametersAt(tx *bolt.Tx, height rivinetypes.BlockHeight) (types.ConsensusParameters, error) {
+	consensusParametersBucket := tx.Bucket(bucketConsensusParameters)
+	if consensusParametersBucket == nil {
+		return types.ConsensusParameters{}, errors.New("corrupt transaction DB: consensus parameters bucket does not exist")
+	}
+	// apply all updates effective at the given height on top of the genesis parameters,
+	// in the order they became effective, and within the same height, in the order they were applied
+	params := txdb.genesisConsensusParameters
+	c := consensusParametersBucket.Cursor()
+	for k, v := c.First(); k != nil && internal.DecodeBlockheight(k) <= height; k, v = c.Next() {
+		var updates []types.ConsensusParameterUpdate
+		err := rivbin.Unmarshal(v, &updates)
+		if err != nil {
+			return types.ConsensusParameters{}, fmt.Errorf("corrupt transaction DB: failed to decode consensus parameter updates: %v", err)
+		}
+		for _, update := range updates {
+			err = params.Apply(update)
+			if err != nil {
+				return types.ConsensusParameters{}, fmt.Errorf("corrupt transaction DB: failed to apply consensus parameter update: %v", err)
+			}
+		}
+	}
+	return params, nil
+}
+
+// GetCoinSupply returns the coin supply, as created and destroyed
+// by all blocks and transactions applied to the TransactionDB.
+func (txdb *TransactionDB) GetCoinSupply() (supply CoinSupply, err error) {
+	err = txdb.db.View(func(tx *bolt.Tx) (err error) {
+		supply, err = getCoinSupply(tx)
+		return err
+	})
+	return
+}
+
 // GetRecordForID returns the record mapped to the given BotID.
 func (txdb *TransactionDB) GetRecordForID(id types.BotID) (record *types.BotRecord, err error) {
 	err = txdb.db.View(func(tx *bolt.Tx) (err error) {
@@ -318,15 +766,159 @@ func (txdb *TransactionDB) GetRecordForName(name types.BotName) (record *types.B
 		if err != nil {
 			return err
 		}
-		if record.Expiration.SiaTimestamp() <= txdb.stats.ChainTime {
-			// a botname automatically expires as soon as the last 3bot that owned it expired as well
+		// the height of the TransactionDB equals the (consensus) height of the next block
+		gracePeriod, err := txdb.getBotNameGracePeriodAt(tx, txdb.stats.BlockHeight)
+		if err != nil {
+			return err
+		}
+		if record.IsReleased(txdb.stats.ChainTime, gracePeriod) {
+			// a botname automatically expires as soon as the grace period
+			// of the last 3bot that owned it has passed as well
 			return types.ErrBotNameExpired
 		}
+		if record.IsExpired(txdb.stats.ChainTime) {
+			// the botname remains reserved for the expired 3bot during its (activated) grace period
+			return types.ErrBotNameInGracePeriod
+		}
+		return nil
+	})
+	return
+}
+
+// GetRecordsForNetworkAddress returns the records of all bots that registered the given network address,
+// ordered by their unique ID. No records are returned, nor an error, if no bot registered the given address.
+func (txdb *TransactionDB) GetRecordsForNetworkAddress(address types.NetworkAddress) (records []types.BotRecord, err error) {
+	err = txdb.db.View(func(tx *bolt.Tx) error {
+		ids, err := getBotIDsForNetworkAddress(tx, address)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			record, err := getRecordForID(tx, id)
+			if err != nil {
+				return fmt.Errorf("corrupt transaction DB: failed to get record of bot %d mapped to address %v: %v", id, address, err)
+			}
+			records = append(records, *record)
+		}
+		return nil
+	})
+	return
+}
+
+// GetDelegatesForName returns the identifiers of all bots the given Name is delegated to,
+// by the active bot that currently owns that Name, ordered by their unique ID.
+// No identifiers are returned, nor an error, if the Name isn't owned by an active bot.
+func (txdb *TransactionDB) GetDelegatesForName(name types.BotName) (ids []types.BotID, err error) {
+	err = txdb.db.View(func(tx *bolt.Tx) error {
+		owner, err := getActiveBotIDForName(tx, name, txdb.stats.ChainTime)
+		if err != nil {
+			if err == types.ErrBotNameNotFound || err == types.ErrBotNameExpired {
+				return nil // a name without an active owner has no delegates
+			}
+			return err
+		}
+		ids, err = getBotNameDelegates(tx, name, owner)
+		return err
+	})
+	return
+}
+
+// GetChildrenForName returns all sub names that have the given Name as their parent,
+// and which are owned by an active bot. No names are returned, nor an error, if no such sub names exist.
+func (txdb *TransactionDB) GetChildrenForName(name types.BotName) (children []types.BotName, err error) {
+	err = txdb.db.View(func(tx *bolt.Tx) error {
+		names, err := getBotNameChildren(tx, name)
+		if err != nil {
+			return err
+		}
+		for _, child := range names {
+			_, err = getActiveBotIDForName(tx, child, txdb.stats.ChainTime)
+			if err == types.ErrBotNameExpired {
+				continue // only sub names of active bots are returned
+			}
+			if err != nil {
+				return fmt.Errorf("corrupt transaction DB: failed to get owner of child %v of name %v: %v", child, name, err)
+			}
+			children = append(children, child)
+		}
+		return nil
+	})
+	return
+}
+
+// GetRecords returns, ordered by their unique ID, the records of at most limit bots
+// that were registered after the bot identified by the given cursor and which match the given filter.
+// A cursor of 0 lists the bots starting from the first registered bot.
+//
+// If more bots could match the filter, the ID of the last listed bot is returned as the cursor
+// to be used for the next page, otherwise the returned cursor is 0.
+func (txdb *TransactionDB) GetRecords(cursor types.BotID, limit int, filter BotRecordFilter) (listings []BotRecordListing, next types.BotID, err error) {
+	if limit <= 0 {
+		return nil, 0, errors.New("the amount of bot records to list has to be positive")
+	}
+	if cursor == types.MaxBotID {
+		return nil, 0, nil // no bot can be registered after the last possible bot
+	}
+	err = txdb.db.View(func(tx *bolt.Tx) error {
+		registrationBucket := tx.Bucket(bucketBotRegistrations)
+		if registrationBucket == nil {
+			return errors.New("corrupt transaction DB: bot registrations bucket does not exist")
+		}
+		gracePeriod, err := txdb.getBotNameGracePeriodAt(tx, txdb.stats.BlockHeight)
+		if err != nil {
+			return err
+		}
+		c := registrationBucket.Cursor()
+		// the bucket is sorted by ID, and thus also by registration height,
+		// given bot IDs are assigned incrementally
+		for k, v := c.Seek(encodeSortableBotID(cursor + 1)); k != nil; k, v = c.Next() {
+			var listing BotRecordListing
+			err := rivbin.Unmarshal(v, &listing.RegistrationHeight)
+			if err != nil {
+				return fmt.Errorf("corrupt transaction DB: failed to decode bot registration height: %v", err)
+			}
+			if filter.RegisteredBefore != 0 && listing.RegistrationHeight >= filter.RegisteredBefore {
+				break // all bots that follow are registered too late as well
+			}
+			if listing.RegistrationHeight < filter.RegisteredSince {
+				continue
+			}
+			if len(listings) == limit {
+				// another bot could match the filter, hence a cursor is returned for the next page
+				next = listings[limit-1].Record.ID
+				break
+			}
+			record, err := getRecordForID(tx, decodeSortableBotID(k))
+			if err != nil {
+				return fmt.Errorf("corrupt transaction DB: failed to get record for registered bot: %v", err)
+			}
+			if !filter.matchesRecord(record, txdb.stats.ChainTime, gracePeriod) {
+				continue
+			}
+			listing.Record = *record
+			listings = append(listings, listing)
+		}
 		return nil
 	})
 	return
 }
 
+// matchesRecord returns true if the given record matches the expiration and name filters,
+// using the given chain time and grace period to define whether or not the names of the bot are released.
+func (filter BotRecordFilter) matchesRecord(record *types.BotRecord, chainTime rivinetypes.Timestamp, gracePeriod types.CompactTimestamp) bool {
+	expiration := record.Expiration.SiaTimestamp()
+	if filter.ExpiresAfter != 0 && expiration < filter.ExpiresAfter {
+		return false
+	}
+	if filter.ExpiresBefore != 0 && expiration > filter.ExpiresBefore {
+		return false
+	}
+	if filter.HasName && (record.Names.Len() == 0 || record.IsReleased(chainTime, gracePeriod)) {
+		return false
+	}
+	return true
+}
+
 // GetBotTransactionIdentifiers returns the identifiers of all transactions that created and updated the given bot's record.
 //
 // The transaction identifiers are returned in the (stable) order as defined by the blockchain.
@@ -338,6 +930,44 @@ func (txdb *TransactionDB) GetBotTransactionIdentifiers(id types.BotID) (ids []r
 	return
 }
 
+// GetRecordForIDAt returns the record mapped to the given BotID,
+// as it was at the given block height, including all transactions of that block.
+func (txdb *TransactionDB) GetRecordForIDAt(id types.BotID, height rivinetypes.BlockHeight) (record *types.BotRecord, err error) {
+	err = txdb.db.View(func(tx *bolt.Tx) error {
+		version, err := getBotRecordVersionAt(tx, id, height)
+		if err != nil {
+			return err
+		}
+		record = &version.Record
+		return nil
+	})
+	return
+}
+
+// GetBotRecordHistory returns all versions of the record mapped to the given BotID,
+// one version for each transaction that created or modified the record.
+//
+// The versions are returned in the (stable) order as defined by the blockchain.
+func (txdb *TransactionDB) GetBotRecordHistory(id types.BotID) (versions []types.BotRecordVersion, err error) {
+	err = txdb.db.View(func(tx *bolt.Tx) (err error) {
+		versions, err = getBotRecordVersions(tx, id)
+		return
+	})
+	return
+}
+
+// GetBotNameHistory returns the ownership history of the given name,
+// one entry for each transaction that assigned or released the name.
+//
+// The entries are returned in the (stable) order as defined by the blockchain.
+func (txdb *TransactionDB) GetBotNameHistory(name types.BotName) (history []types.BotNameOwnership, err error) {
+	err = txdb.db.View(func(tx *bolt.Tx) (err error) {
+		history, err = getBotNameOwnershipHistory(tx, name)
+		return
+	})
+	return
+}
+
 // GetERC20AddressForTFTAddress returns the mapped ERC20 address for the given TFT Address,
 // iff the TFT Address has registered an ERC20 address explicitly.
 func (txdb *TransactionDB) GetERC20AddressForTFTAddress(uh rivinetypes.UnlockHash) (addr types.ERC20Address, found bool, err error) {
@@ -368,15 +998,149 @@ func (txdb *TransactionDB) GetTFTTransactionIDForERC20TransactionID(id types.ERC
 	return
 }
 
-// Close the transaction DB,
-// meaning the db will be unsubscribed from the consensus set,
-// as well the threadgroup will be stopped and the internal bolt db will be closed.
-func (txdb *TransactionDB) Close() error {
-	if txdb.db == nil {
-		return errors.New("transactionDB is already closed or was never created")
+// GetFarmerCondition implements types.FarmerConditionGetter.GetFarmerCondition
+//
+// The farmer condition is derived from the authorized addresses of the farm's (latest) record.
+func (txdb *TransactionDB) GetFarmerCondition(farm types.FarmID) (rivinetypes.UnlockConditionProxy, error) {
+	record, err := txdb.GetFarm(farm)
+	if err != nil {
+		return rivinetypes.UnlockConditionProxy{}, err
 	}
+	return record.FarmerCondition()
+}
 
-	// unsubscribe from the consensus set, if subscribed at all
+// GetCapacityChallenge implements types.CapacityProofReadRegistry.GetCapacityChallenge
+func (txdb *TransactionDB) GetCapacityChallenge(height rivinetypes.BlockHeight, node rivinetypes.PublicKey) (challenge types.CapacityChallenge, err error) {
+	err = txdb.db.View(func(tx *bolt.Tx) error {
+		challengesBucket := tx.Bucket(bucketCapacityChallenges)
+		if challengesBucket == nil {
+			return errors.New("corrupt transaction DB: capacity challenges bucket does not exist")
+		}
+		b := challengesBucket.Get(internal.EncodeBlockheight(height))
+		if len(b) == 0 {
+			return types.ErrCapacityChallengeNotFound
+		}
+		var blockID rivinetypes.BlockID
+		err := rivbin.Unmarshal(b, &blockID)
+		if err != nil {
+			return fmt.Errorf("corrupt transaction DB: error while parsing stored capacity challenge at height %d: %v", height, err)
+		}
+		challenge = types.NewCapacityChallenge(height, blockID, node)
+		return nil
+	})
+	return
+}
+
+// GetCapacityProofResultForNode implements types.CapacityProofReadRegistry.GetCapacityProofResultForNode
+func (txdb *TransactionDB) GetCapacityProofResultForNode(node rivinetypes.PublicKey, height rivinetypes.BlockHeight) (result *types.CapacityProofResult, err error) {
+	err = txdb.db.View(func(tx *bolt.Tx) (err error) {
+		result, err = getCapacityProofResultForNode(tx, node, height)
+		return
+	})
+	return
+}
+
+// GetCapacityProofResultsForNode returns the results of all challenges that are closed
+// or were responded to by the given node. No results is not an error.
+//
+// The results are returned ordered by challenge height.
+func (txdb *TransactionDB) GetCapacityProofResultsForNode(node rivinetypes.PublicKey) (results []types.CapacityProofResult, err error) {
+	err = txdb.db.View(func(tx *bolt.Tx) (err error) {
+		results, err = getCapacityProofResultsForNode(tx, node)
+		return
+	})
+	return
+}
+
+// GetActiveFoundationCondition implements types.FoundationConditionGetter.GetActiveFoundationCondition
+//
+// The foundation is represented by the coin creators,
+// hence the active mint condition is returned.
+func (txdb *TransactionDB) GetActiveFoundationCondition() (rivinetypes.UnlockConditionProxy, error) {
+	return txdb.GetActiveMintCondition()
+}
+
+// GetFoundationConditionAt implements types.FoundationConditionGetter.GetFoundationConditionAt
+//
+// The foundation is represented by the coin creators,
+// hence the mint condition at the given height is returned.
+func (txdb *TransactionDB) GetFoundationConditionAt(height rivinetypes.BlockHeight) (rivinetypes.UnlockConditionProxy, error) {
+	return txdb.GetMintConditionAt(height)
+}
+
+// GetFarm returns the (latest) record of the farm mapped to the given FarmID.
+func (txdb *TransactionDB) GetFarm(id types.FarmID) (record *types.FarmRecord, err error) {
+	err = txdb.db.View(func(tx *bolt.Tx) (err error) {
+		record, err = getLatestFarmRecord(tx, id)
+		return
+	})
+	return
+}
+
+// GetFarmForName returns the (latest) record of the farm mapped to the given name.
+func (txdb *TransactionDB) GetFarmForName(name string) (record *types.FarmRecord, err error) {
+	err = txdb.db.View(func(tx *bolt.Tx) error {
+		nameBucket := tx.Bucket(bucketFarmNameToIDMapping)
+		if nameBucket == nil {
+			return errors.New("corrupt transaction DB: farm name bucket does not exist")
+		}
+		b := nameBucket.Get([]byte(name))
+		if len(b) == 0 {
+			return types.ErrFarmNameNotFound
+		}
+		var id types.FarmID
+		err := rivbin.Unmarshal(b, &id)
+		if err != nil {
+			return fmt.Errorf("corrupt transaction DB: error while parsing stored farm ID for name %q: %v", name, err)
+		}
+		record, err = getLatestFarmRecord(tx, id)
+		return err
+	})
+	return
+}
+
+// GetCapacityRecordForNode returns the latest capacity record registered for the given node.
+func (txdb *TransactionDB) GetCapacityRecordForNode(node rivinetypes.PublicKey) (record *types.CapacityRecord, err error) {
+	err = txdb.db.View(func(tx *bolt.Tx) (err error) {
+		record, err = getLatestCapacityRecordForNode(tx, node)
+		return
+	})
+	return
+}
+
+// GetCapacityRecordHistoryForNode returns all capacity records registered for the given node.
+//
+// The records are returned in the (stable) order as defined by the blockchain,
+// meaning the last record is the active record of the node.
+func (txdb *TransactionDB) GetCapacityRecordHistoryForNode(node rivinetypes.PublicKey) (records []types.CapacityRecord, err error) {
+	err = txdb.db.View(func(tx *bolt.Tx) (err error) {
+		records, err = getCapacityRecordsForNode(tx, node)
+		return
+	})
+	return
+}
+
+// GetNodesForFarm returns the keys of all nodes that are currently registered for the given farm.
+// A node is only considered to be part of a farm as long as its latest capacity record links to that farm.
+//
+// The nodes are returned in the order they were (first) registered for the given farm.
+func (txdb *TransactionDB) GetNodesForFarm(farm types.FarmID) (nodes []rivinetypes.PublicKey, err error) {
+	err = txdb.db.View(func(tx *bolt.Tx) (err error) {
+		nodes, err = getNodesForFarm(tx, farm)
+		return
+	})
+	return
+}
+
+// Close the transaction DB,
+// meaning the db will be unsubscribed from the consensus set,
+// as well the threadgroup will be stopped and the internal bolt db will be closed.
+func (txdb *TransactionDB) Close() error {
+	if txdb.db == nil {
+		return errors.New("transactionDB is already closed or was never created")
+	}
+
+	// unsubscribe from the consensus set, if subscribed at all
 	if txdb.subscriber != nil {
 		txdb.subscriber.unsubscribe()
 		txdb.subscriber = nil
@@ -401,7 +1165,7 @@ func (txdb *TransactionDB) openDB(filename string, genesisMintCondition rivinety
 	var (
 		dbMetadata = persist.Metadata{
 			Header:  "TFChain Transaction Database",
-			Version: "1.1.2.1",
+			Version: "1.1.3",
 		}
 	)
 
@@ -419,7 +1183,7 @@ func (txdb *TransactionDB) openDB(filename string, genesisMintCondition rivinety
 		txdb.db.Metadata = dbMetadata
 		err = txdb.db.SaveMetadata()
 		if err != nil {
-			return fmt.Errorf("error while saving the v1.1.2 metadata in the tfchain transaction database: %v", err)
+			return fmt.Errorf("error while saving the v1.1.3 metadata in the tfchain transaction database: %v", err)
 		}
 	}
 	return txdb.db.Update(func(tx *bolt.Tx) (err error) {
@@ -478,6 +1242,17 @@ func (txdb *TransactionDB) migrateDB(filename string) error {
 		return fmt.Errorf("error opening tfchain transaction v1.1.0 database: %v", err)
 	}
 
+	// try to open the v1.1.2.1 DB, the last version prior to the capacity registration feature
+	dbMetadata.Version = "1.1.2.1"
+	txdb.db, err = persist.OpenDatabase(dbMetadata, filename)
+	if err == nil {
+		// migrate from a v1.1.2.1 DB
+		return txdb.db.Update(txdb.migrateV1121DB)
+	}
+	if err != persist.ErrBadVersion {
+		return fmt.Errorf("error opening tfchain transaction v1.1.2.1 database: %v", err)
+	}
+
 	// try to open the initial v1.2.0 DB (never released, but already out in field for dev purposes)
 	dbMetadata.Version = "1.2.0"
 	txdb.db, err = persist.OpenDatabase(dbMetadata, filename)
@@ -551,8 +1326,52 @@ func (txdb *TransactionDB) migrateV120DB(tx *bolt.Tx) error {
 		}
 	}
 
-	// migration process is finished
-	return nil
+	// Continue the migration process towards the newest version
+	return txdb.migrateV1121DB(tx)
+}
+
+func (txdb *TransactionDB) migrateV1121DB(tx *bolt.Tx) error {
+	// the history of 3bot records and names can only be derived from the blockchain itself,
+	// hence the TransactionDB is recreated (including all buckets of the capacity and farm features),
+	// such that it resyncs with the consensus set from scratch
+	return txdb.recreateDB(tx)
+}
+
+// recreateDB deletes all buckets and creates the DB again,
+// using the genesis mint condition that was stored in the original DB.
+// As the consensus change ID is reset as well, the TransactionDB
+// will resync with the consensus set from the beginning once subscribed.
+func (txdb *TransactionDB) recreateDB(tx *bolt.Tx) error {
+	mintConditionsBucket := tx.Bucket(bucketMintConditions)
+	if mintConditionsBucket == nil {
+		return errors.New("corrupt transaction DB: mint conditions bucket does not exist")
+	}
+	b := mintConditionsBucket.Get(internal.EncodeBlockheight(0))
+	if len(b) == 0 {
+		return errors.New("genesis mint condition could not be found in existing transaction db")
+	}
+	var genesisMintCondition rivinetypes.UnlockConditionProxy
+	err := siabin.Unmarshal(b, &genesisMintCondition)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal genesis mint condition from existing transaction db: %v", err)
+	}
+
+	// collect the buckets first, as they cannot be deleted while iterating over them
+	var buckets [][]byte
+	err = tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+		buckets = append(buckets, append([]byte(nil), name...))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, bucket := range buckets {
+		err = tx.DeleteBucket(bucket)
+		if err != nil {
+			return fmt.Errorf("failed to delete bucket %s: %v", bucket, err)
+		}
+	}
+	return txdb.createDB(tx, genesisMintCondition)
 }
 
 // dbInitialized returns true if the database appears to be initialized, false
@@ -577,6 +1396,22 @@ func (txdb *TransactionDB) createDB(tx *bolt.Tx, genesisMintCondition rivinetype
 		bucketERC20ToTFTAddresses,
 		bucketTFTToERC20Addresses,
 		bucketERC20TransactionIDs,
+		bucketCapacityNodes,
+		bucketCapacityFarms,
+		bucketFarmRecords,
+		bucketFarmNameToIDMapping,
+		bucketCapacityChallenges,
+		bucketCapacityProofs,
+		bucketBotRecordHistory,
+		bucketBotNameHistory,
+		bucketBotAddressToIDsMapping,
+		bucketBotNameChildren,
+		bucketBotNameDelegates,
+		bucketBotRegistrations,
+		bucketCoinCreations,
+		bucketCoinBurns,
+		bucketMintingCaps,
+		bucketConsensusParameters,
 	}
 	for _, bucket := range buckets {
 		_, err = tx.CreateBucket(bucket)
@@ -594,6 +1429,10 @@ func (txdb *TransactionDB) createDB(tx *bolt.Tx, genesisMintCondition rivinetype
 		return fmt.Errorf("failed to store transaction db (height=%d; changeID=%x) as a stat: %v",
 			txdb.stats.BlockHeight, txdb.stats.ConsensusChangeID, err)
 	}
+	err = internalBucket.Put(bucketInternalKeySupply, siabin.Marshal(CoinSupply{}))
+	if err != nil {
+		return fmt.Errorf("failed to store the initial coin supply: %v", err)
+	}
 
 	// store the genesis mint condition
 	mintConditionsBucket := tx.Bucket(bucketMintConditions)
@@ -656,7 +1495,13 @@ func (txdb *TransactionDB) processConsensusChange(css modules.ConsensusChange) {
 	})
 	if err != nil {
 		build.Critical("transactionDB update failed:", err)
+		return
 	}
+
+	// update the metrics, now that the consensus change has been committed
+	txdb.metrics.countTransactions(css.RevertedBlocks, metricsActionReverted)
+	txdb.metrics.countTransactions(css.AppliedBlocks, metricsActionApplied)
+	txdb.metrics.updateStats(txdb.stats)
 }
 
 // revert all the given blocks using the given writable bolt Transaction,
@@ -675,7 +1520,16 @@ func (txdb *TransactionDB) revertBlocks(tx *bolt.Tx, blocks []rivinetypes.Block)
 
 	// collect all one-per-block mint conditions
 	for _, block := range blocks {
-		for i := range block.Transactions {
+		// revert the capacity challenges defined by the block,
+		// using the consensus block height, as the TransactionDB counts the genesis block as height 1
+		err = txdb.revertCapacityChallenges(tx, txdb.stats.BlockHeight-1)
+		if err != nil {
+			return err
+		}
+
+		// revert the transactions in the reverse order as they were applied,
+		// as the (sequential) IDs of registered bots depend on that order
+		for i := len(block.Transactions) - 1; i >= 0; i-- {
 			rtx = &block.Transactions[i]
 			if rtx.Version == rivinetypes.TransactionVersionOne {
 				continue // ignore most common Tx
@@ -689,16 +1543,40 @@ func (txdb *TransactionDB) revertBlocks(tx *bolt.Tx, blocks []rivinetypes.Block)
 			switch rtx.Version {
 			case types.TransactionVersionBotRegistration:
 				err = txdb.revertBotRegistrationTx(tx, ctx, rtx)
-			case types.TransactionVersionBotRecordUpdate:
+			case types.TransactionVersionBotRecordUpdate, types.TransactionVersionBotRecordUpdateWithFulfillment, types.TransactionVersionBotRecordUpdateWithServices:
 				err = txdb.revertRecordUpdateTx(tx, ctx, rtx)
-			case types.TransactionVersionBotNameTransfer:
+			case types.TransactionVersionBotNameTransfer, types.TransactionVersionBotNameTransferWithFulfillment, types.TransactionVersionBotNameSale:
 				err = txdb.revertBotNameTransferTx(tx, ctx, rtx)
-
+			case types.TransactionVersionBotKeyRotation:
+				err = txdb.revertBotKeyRotationTx(tx, ctx, rtx)
+			case types.TransactionVersionBotNameDelegation:
+				err = txdb.revertBotNameDelegationTx(tx, ctx, rtx)
+
+			case types.TransactionVersionCoinCreation:
+				err = txdb.revertCoinCreationTx(tx, ctx, rtx)
+			case types.TransactionVersionCoinBurn:
+				err = txdb.revertCoinBurnTx(tx, ctx, rtx)
+			case types.TransactionVersionMintingCapDefinition:
+				err = txdb.revertMintingCapTx(tx, ctx, rtx)
+			case types.TransactionVersionConsensusParameterUpdate:
+				err = txdb.revertConsensusParameterUpdateTx(tx, ctx, rtx)
+
+			case types.TransactionVersionERC20Conversion:
+				err = txdb.revertERC20ConvertTx(tx, ctx, rtx)
 			case types.TransactionVersionERC20CoinCreation:
 				err = txdb.revertERC20CoinCreationTx(tx, ctx, rtx)
 			case types.TransactionVersionERC20AddressRegistration:
 				err = txdb.revertERC20AddressRegistrationTx(tx, ctx, rtx)
 
+			case types.TransactionVersionCapacityRegistration:
+				err = txdb.revertCapacityRegistrationTx(tx, ctx, rtx)
+			case types.TransactionVersionFarmRegistration:
+				err = txdb.revertFarmRegistrationTx(tx, ctx, rtx)
+			case types.TransactionVersionFarmUpdate:
+				err = txdb.revertFarmUpdateTx(tx, ctx, rtx)
+			case types.TransactionVersionCapacityProof:
+				err = txdb.revertCapacityProofTx(tx, ctx, rtx)
+
 			case types.TransactionVersionMinterDefinition:
 				err = txdb.revertMintConditionTx(tx, rtx)
 			}
@@ -707,6 +1585,16 @@ func (txdb *TransactionDB) revertBlocks(tx *bolt.Tx, blocks []rivinetypes.Block)
 			}
 		}
 
+		// revert the coins issued by the block itself
+		err = updateCoinSupply(tx, func(supply *CoinSupply) {
+			genesis, rewards := blockCoinIssuance(block, txdb.stats.BlockHeight)
+			supply.Genesis = supply.Genesis.Sub(genesis)
+			supply.BlockRewards = supply.BlockRewards.Sub(rewards)
+		})
+		if err != nil {
+			return err
+		}
+
 		// decrease block height (store later)
 		txdb.stats.BlockHeight--
 		// not super accurate, should be accurate enough and will fix itself when new blocks get applied
@@ -735,6 +1623,16 @@ func (txdb *TransactionDB) applyBlocks(tx *bolt.Tx, blocks []rivinetypes.Block)
 		txdb.stats.BlockHeight++
 		txdb.stats.ChainTime = block.Timestamp
 
+		// apply the coins issued by the block itself
+		err = updateCoinSupply(tx, func(supply *CoinSupply) {
+			genesis, rewards := blockCoinIssuance(block, txdb.stats.BlockHeight)
+			supply.Genesis = supply.Genesis.Add(genesis)
+			supply.BlockRewards = supply.BlockRewards.Add(rewards)
+		})
+		if err != nil {
+			return err
+		}
+
 		for i := range block.Transactions {
 			rtx = &block.Transactions[i]
 			if rtx.Version == rivinetypes.TransactionVersionOne {
@@ -749,16 +1647,40 @@ func (txdb *TransactionDB) applyBlocks(tx *bolt.Tx, blocks []rivinetypes.Block)
 			switch rtx.Version {
 			case types.TransactionVersionBotRegistration:
 				err = txdb.applyBotRegistrationTx(tx, ctx, rtx)
-			case types.TransactionVersionBotRecordUpdate:
+			case types.TransactionVersionBotRecordUpdate, types.TransactionVersionBotRecordUpdateWithFulfillment, types.TransactionVersionBotRecordUpdateWithServices:
 				err = txdb.applyRecordUpdateTx(tx, ctx, rtx)
-			case types.TransactionVersionBotNameTransfer:
+			case types.TransactionVersionBotNameTransfer, types.TransactionVersionBotNameTransferWithFulfillment, types.TransactionVersionBotNameSale:
 				err = txdb.applyBotNameTransferTx(tx, ctx, rtx)
-
+			case types.TransactionVersionBotKeyRotation:
+				err = txdb.applyBotKeyRotationTx(tx, ctx, rtx)
+			case types.TransactionVersionBotNameDelegation:
+				err = txdb.applyBotNameDelegationTx(tx, ctx, rtx)
+
+			case types.TransactionVersionCoinCreation:
+				err = txdb.applyCoinCreationTx(tx, ctx, rtx)
+			case types.TransactionVersionCoinBurn:
+				err = txdb.applyCoinBurnTx(tx, ctx, rtx)
+			case types.TransactionVersionMintingCapDefinition:
+				err = txdb.applyMintingCapTx(tx, ctx, rtx)
+			case types.TransactionVersionConsensusParameterUpdate:
+				err = txdb.applyConsensusParameterUpdateTx(tx, ctx, rtx)
+
+			case types.TransactionVersionERC20Conversion:
+				err = txdb.applyERC20ConvertTx(tx, ctx, rtx)
 			case types.TransactionVersionERC20CoinCreation:
 				err = txdb.applyERC20CoinCreationTx(tx, ctx, rtx)
 			case types.TransactionVersionERC20AddressRegistration:
 				err = txdb.applyERC20AddressRegistrationTx(tx, ctx, rtx)
 
+			case types.TransactionVersionCapacityRegistration:
+				err = txdb.applyCapacityRegistrationTx(tx, ctx, rtx)
+			case types.TransactionVersionFarmRegistration:
+				err = txdb.applyFarmRegistrationTx(tx, ctx, rtx)
+			case types.TransactionVersionFarmUpdate:
+				err = txdb.applyFarmUpdateTx(tx, ctx, rtx)
+			case types.TransactionVersionCapacityProof:
+				err = txdb.applyCapacityProofTx(tx, ctx, rtx)
+
 			case types.TransactionVersionMinterDefinition:
 				err = txdb.applyMintConditionTx(tx, rtx)
 			}
@@ -766,6 +1688,13 @@ func (txdb *TransactionDB) applyBlocks(tx *bolt.Tx, blocks []rivinetypes.Block)
 				return err
 			}
 		}
+
+		// apply the capacity challenges defined by the block,
+		// using the consensus block height, as the TransactionDB counts the genesis block as height 1
+		err = txdb.applyCapacityChallenges(tx, txdb.stats.BlockHeight-1, block.ID())
+		if err != nil {
+			return err
+		}
 	}
 
 	// all good
@@ -779,7 +1708,7 @@ func (txdb *TransactionDB) applyMintConditionTx(tx *bolt.Tx, rtx *rivinetypes.Tr
 	}
 	mdtx, err := types.MinterDefinitionTransactionFromTransaction(*rtx)
 	if err != nil {
-		return fmt.Errorf("unexpected error while unpacking the minter def. tx type: %v" + err.Error())
+		return fmt.Errorf("unexpected error while unpacking the minter def. tx type: %v", err)
 	}
 	err = mintConditionsBucket.Put(internal.EncodeBlockheight(txdb.stats.BlockHeight), siabin.Marshal(mdtx.MintCondition))
 	if err != nil {
@@ -804,6 +1733,105 @@ func (txdb *TransactionDB) revertMintConditionTx(tx *bolt.Tx, rtx *rivinetypes.T
 	return nil
 }
 
+func (txdb *TransactionDB) applyCoinCreationTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	// the coin creation Tx fulfills the mint condition that was active prior to its block,
+	// which is stored at the (consensus) block height of the previous block
+	mintCondition, err := getMintConditionAt(tx, ctx.BlockHeight-1)
+	if err != nil {
+		return fmt.Errorf("failed to get the mint condition fulfilled by coin creation Tx %v: %v", rtx.ID(), err)
+	}
+	record := newCoinCreationRecord(ctx, rtx, CoinCreationTypeMint)
+	record.Minter = mintCondition.UnlockHash()
+	err = applyCoinCreationRecord(tx, ctx.TransactionShortID(), record)
+	if err != nil {
+		return err
+	}
+	return updateCoinSupply(tx, func(supply *CoinSupply) {
+		supply.Minted = supply.Minted.Add(record.Value)
+	})
+}
+
+func (txdb *TransactionDB) revertCoinCreationTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	err := revertCoinCreationRecord(tx, ctx.TransactionShortID())
+	if err != nil {
+		return err
+	}
+	return updateCoinSupply(tx, func(supply *CoinSupply) {
+		supply.Minted = supply.Minted.Sub(newCoinCreationRecord(ctx, rtx, CoinCreationTypeMint).Value)
+	})
+}
+
+func (txdb *TransactionDB) applyMintingCapTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	mintingCapsBucket := tx.Bucket(bucketMintingCaps)
+	if mintingCapsBucket == nil {
+		return errors.New("corrupt transaction DB: minting caps bucket does not exist")
+	}
+	mcdtx, err := types.MintingCapDefinitionTransactionFromTransaction(*rtx)
+	if err != nil {
+		return fmt.Errorf("unexpected error while unpacking the minting cap def. tx type: %v", err)
+	}
+	err = mintingCapsBucket.Put(rivbin.Marshal(ctx.TransactionShortID()), rivbin.Marshal(MintingCapRecord{
+		TransactionID: rtx.ID(),
+		BlockHeight:   ctx.BlockHeight - 1,
+		BlockTime:     ctx.BlockTime,
+		MintingCap:    mcdtx.MintingCap,
+	}))
+	if err != nil {
+		return fmt.Errorf("failed to put minting cap of tx %v: %v", rtx.ID(), err)
+	}
+	return nil
+}
+
+func (txdb *TransactionDB) revertMintingCapTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	mintingCapsBucket := tx.Bucket(bucketMintingCaps)
+	if mintingCapsBucket == nil {
+		return errors.New("corrupt transaction DB: minting caps bucket does not exist")
+	}
+	err := mintingCapsBucket.Delete(rivbin.Marshal(ctx.TransactionShortID()))
+	if err != nil {
+		return fmt.Errorf("failed to delete minting cap of tx %v: %v", rtx.ID(), err)
+	}
+	return nil
+}
+
+func (txdb *TransactionDB) applyConsensusParameterUpdateTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	consensusParametersBucket := tx.Bucket(bucketConsensusParameters)
+	if consensusParametersBucket == nil {
+		return errors.New("corrupt transaction DB: consensus parameters bucket does not exist")
+	}
+	cputx, err := types.ConsensusParameterUpdateTransactionFromTransaction(*rtx)
+	if err != nil {
+		return fmt.Errorf("unexpected error while unpacking the consensus parameter update tx type: %v", err)
+	}
+	err = consensusParametersBucket.Put(consensusParameterUpdateKey(cputx.EffectiveHeight, ctx), rivbin.Marshal(cputx.Updates))
+	if err != nil {
+		return fmt.Errorf("failed to put consensus parameter updates of tx %v: %v", rtx.ID(), err)
+	}
+	return nil
+}
+
+func (txdb *TransactionDB) revertConsensusParameterUpdateTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	consensusParametersBucket := tx.Bucket(bucketConsensusParameters)
+	if consensusParametersBucket == nil {
+		return errors.New("corrupt transaction DB: consensus parameters bucket does not exist")
+	}
+	cputx, err := types.ConsensusParameterUpdateTransactionFromTransaction(*rtx)
+	if err != nil {
+		return fmt.Errorf("unexpected error while unpacking the consensus parameter update tx type: %v", err)
+	}
+	err = consensusParametersBucket.Delete(consensusParameterUpdateKey(cputx.EffectiveHeight, ctx))
+	if err != nil {
+		return fmt.Errorf("failed to delete consensus parameter updates of tx %v: %v", rtx.ID(), err)
+	}
+	return nil
+}
+
+// consensusParameterUpdateKey returns the key used to store consensus parameter updates,
+// sorted by the (consensus) height from which they are effective, and the transaction that defined them.
+func consensusParameterUpdateKey(effectiveHeight rivinetypes.BlockHeight, ctx transactionContext) []byte {
+	return append(internal.EncodeBlockheight(effectiveHeight), rivbin.Marshal(ctx.TransactionShortID())...)
+}
+
 type transactionContext struct {
 	BlockHeight  rivinetypes.BlockHeight
 	BlockTime    rivinetypes.Timestamp
@@ -864,11 +1892,34 @@ func (txdb *TransactionDB) applyBotRegistrationTx(tx *bolt.Tx, ctx transactionCo
 			return fmt.Errorf("error while storing name %s to bot id %d mapping: %v", name.String(), id, err)
 		}
 	}
+	// store all address mappings
+	for _, addr := range brtx.Addresses {
+		err = applyAddressToIDMapping(tx, addr, id)
+		if err != nil {
+			return fmt.Errorf("error while storing address %s to bot id %d mapping: %v", addr.String(), id, err)
+		}
+	}
 	// apply the transactionID to the list of transactionIDs for the given bot
 	err = applyBotTransaction(tx, id, ctx.TransactionShortID(), rtx.ID())
 	if err != nil {
 		return fmt.Errorf("error while applying transaction for bot %d: %v", id, err)
 	}
+	// index the bot, such that it can be listed
+	err = applyBotRegistration(tx, ctx, id)
+	if err != nil {
+		return fmt.Errorf("error while indexing registration of bot %d: %v", id, err)
+	}
+	// store the initial version of the record, as well as the ownership of all its names
+	err = applyBotRecordVersion(tx, ctx, rtx.ID(), record)
+	if err != nil {
+		return fmt.Errorf("error while storing version of record for bot %d: %v", id, err)
+	}
+	for _, name := range brtx.Names {
+		err = applyBotNameOwnership(tx, ctx, rtx.ID(), name, id)
+		if err != nil {
+			return fmt.Errorf("error while storing ownership of name %s by bot %d: %v", name.String(), id, err)
+		}
+	}
 	// all information is applied
 	return nil
 }
@@ -898,6 +1949,13 @@ func (txdb *TransactionDB) revertBotRegistrationTx(tx *bolt.Tx, ctx transactionC
 			return fmt.Errorf("error while deleting name %s to bot id %d mapping: %v", name.String(), id, err)
 		}
 	}
+	// delete the address->ID mappings
+	for _, addr := range brtx.Addresses {
+		err = revertAddressToIDMapping(tx, addr, id)
+		if err != nil {
+			return fmt.Errorf("error while deleting address %s to bot id %d mapping: %v", addr.String(), id, err)
+		}
+	}
 	// delete the publicKey->ID mapping,
 	// doing it last as this is the initial check that happens when registering a bot,
 	// as to ensure we only have one bot per public key
@@ -910,6 +1968,22 @@ func (txdb *TransactionDB) revertBotRegistrationTx(tx *bolt.Tx, ctx transactionC
 	if err != nil {
 		return fmt.Errorf("error while reverting transaction for bot %d: %v", id, err)
 	}
+	// delete the bot from the registration index
+	err = revertBotRegistration(tx, id)
+	if err != nil {
+		return fmt.Errorf("error while deleting registration index of bot %d: %v", id, err)
+	}
+	// delete the initial version of the record, as well as the ownership of all its names
+	err = revertBotRecordVersion(tx, id, ctx.TransactionShortID())
+	if err != nil {
+		return fmt.Errorf("error while deleting version of record for bot %d: %v", id, err)
+	}
+	for _, name := range brtx.Names {
+		err = revertBotNameOwnership(tx, name, ctx.TransactionShortID())
+		if err != nil {
+			return fmt.Errorf("error while deleting ownership of name %s by bot %d: %v", name.String(), id, err)
+		}
+	}
 	// decrease the sequence counter of the bucket
 	err = recordBucket.SetSequence(rbSequence - 1)
 	if err != nil {
@@ -919,12 +1993,56 @@ func (txdb *TransactionDB) revertBotRegistrationTx(tx *bolt.Tx, ctx transactionC
 	return nil
 }
 
+// botRecordUpdateTransactionFromTransaction unpacks a bot record update tx,
+// regardless of whether it is authorized using a signature or a fulfillment,
+// and whether or not it updates the services of the bot.
+func botRecordUpdateTransactionFromTransaction(rtx rivinetypes.Transaction) (types.BotRecordUpdateTransaction, error) {
+	switch rtx.Version {
+	case types.TransactionVersionBotRecordUpdateWithFulfillment:
+		brutx, err := types.BotRecordUpdateWithFulfillmentTransactionFromTransaction(rtx)
+		if err != nil {
+			return types.BotRecordUpdateTransaction{}, err
+		}
+		return brutx.AsBotRecordUpdateTransaction(), nil
+	case types.TransactionVersionBotRecordUpdateWithServices:
+		brutx, err := types.BotRecordUpdateWithServicesTransactionFromTransaction(rtx)
+		if err != nil {
+			return types.BotRecordUpdateTransaction{}, err
+		}
+		return brutx.AsBotRecordUpdateTransaction(), nil
+	default:
+		return types.BotRecordUpdateTransactionFromTransaction(rtx)
+	}
+}
+
+// botNameTransferTransactionFromTransaction unpacks a bot name transfer tx,
+// regardless of whether it is authorized using signatures or fulfillments,
+// or whether the names are sold rather than transferred for free.
+func botNameTransferTransactionFromTransaction(rtx rivinetypes.Transaction) (types.BotNameTransferTransaction, error) {
+	switch rtx.Version {
+	case types.TransactionVersionBotNameTransferWithFulfillment:
+		bnttx, err := types.BotNameTransferWithFulfillmentTransactionFromTransaction(rtx)
+		if err != nil {
+			return types.BotNameTransferTransaction{}, err
+		}
+		return bnttx.AsBotNameTransferTransaction(), nil
+	case types.TransactionVersionBotNameSale:
+		bnstx, err := types.BotNameSaleTransactionFromTransaction(rtx)
+		if err != nil {
+			return types.BotNameTransferTransaction{}, err
+		}
+		return bnstx.AsBotNameTransferTransaction(), nil
+	default:
+		return types.BotNameTransferTransactionFromTransaction(rtx)
+	}
+}
+
 func (txdb *TransactionDB) applyRecordUpdateTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
 	recordBucket := tx.Bucket(bucketBotRecords)
 	if recordBucket == nil {
 		return errors.New("corrupt transaction DB: bot record bucket does not exist")
 	}
-	brutx, err := types.BotRecordUpdateTransactionFromTransaction(*rtx)
+	brutx, err := botRecordUpdateTransactionFromTransaction(*rtx)
 	if err != nil {
 		return fmt.Errorf("unexpected error while unpacking the bot record update tx type: %v", err)
 	}
@@ -940,11 +2058,16 @@ func (txdb *TransactionDB) applyRecordUpdateTx(tx *bolt.Tx, ctx transactionConte
 		return fmt.Errorf("failed to unmarshal found bot record: %v", err)
 	}
 
-	// check if bot is still active,
-	// if the bot is still active we expect that the Tx defines the names to remove
+	// check if the names of the bot are released,
+	// if the bot is still active (or within its grace period) we expect that the Tx defines the names to remove
 	// otherwise we require that all names should be removed
+	// the height of the TransactionDB, while applying a block, equals the (consensus) height of the next block
+	gracePeriod, err := txdb.getBotNameGracePeriodAt(tx, ctx.BlockHeight-1)
+	if err != nil {
+		return fmt.Errorf("failed to get the grace period of expired bots: %v", err)
+	}
 	var namesInRecordRemovedImplicitly []types.BotName
-	if record.IsExpired(ctx.BlockTime) {
+	if record.IsReleased(ctx.BlockTime, gracePeriod) {
 		namesInRecordRemovedImplicitly = record.Names.Difference(types.BotNameSortedSet{}) // A \ {} = A
 		// store the implicit update that will happen due to the invalid period prior to this Tx,
 		// this will help is in reverting the record back to its original state,
@@ -958,8 +2081,8 @@ func (txdb *TransactionDB) applyRecordUpdateTx(tx *bolt.Tx, ctx transactionConte
 		}
 	}
 
-	// update it (will also reset names of an inactive bot)
-	err = brutx.UpdateBotRecord(ctx.BlockTime, &record)
+	// update it (will also reset names of a bot that is no longer within its grace period)
+	err = brutx.UpdateBotRecord(ctx.BlockTime, gracePeriod, &record)
 	if err != nil {
 		return fmt.Errorf("failed to update bot record: %v", err)
 	}
@@ -974,10 +2097,17 @@ func (txdb *TransactionDB) applyRecordUpdateTx(tx *bolt.Tx, ctx transactionConte
 		// otherwise remove all names that previously active,
 		// as we can assume that an update of a record update HAS to make it active again
 		for _, name := range namesInRecordRemovedImplicitly {
-			err = revertNameToIDMappingIfOwnedByBot(tx, name, record.ID)
+			removed, err := revertNameToIDMappingIfOwnedByBot(tx, name, record.ID)
 			if err != nil {
 				return fmt.Errorf("failed to update bot record: error while tx-removing mapping of name %v: %v", name, err)
 			}
+			if !removed {
+				continue // name is owned by another bot in the meantime
+			}
+			err = applyBotNameOwnership(tx, ctx, rtx.ID(), name, 0)
+			if err != nil {
+				return fmt.Errorf("failed to update bot record: error while storing release of name %v: %v", name, err)
+			}
 		}
 	} else {
 		// if the bot was active, we apply the removals as defined by the Tx
@@ -986,6 +2116,24 @@ func (txdb *TransactionDB) applyRecordUpdateTx(tx *bolt.Tx, ctx transactionConte
 			if err != nil {
 				return fmt.Errorf("failed to update bot record: error while record-removing mapping of name %v: %v", name, err)
 			}
+			err = applyBotNameOwnership(tx, ctx, rtx.ID(), name, 0)
+			if err != nil {
+				return fmt.Errorf("failed to update bot record: error while storing release of name %v: %v", name, err)
+			}
+		}
+	}
+
+	// update the address mappings, removing first, in the same order as the record was updated
+	for _, addr := range brutx.Addresses.Remove {
+		err = revertAddressToIDMapping(tx, addr, record.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update bot record: error while removing mapping of address %v: %v", addr, err)
+		}
+	}
+	for _, addr := range brutx.Addresses.Add {
+		err = applyAddressToIDMapping(tx, addr, record.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update bot record: error while mapping address %v to ID %v: %v", addr, record.ID, err)
 		}
 	}
 
@@ -995,6 +2143,10 @@ func (txdb *TransactionDB) applyRecordUpdateTx(tx *bolt.Tx, ctx transactionConte
 		if err != nil {
 			return fmt.Errorf("failed to update bot record: error while name %v to ID %v: %v", name, record.ID, err)
 		}
+		err = applyBotNameOwnership(tx, ctx, rtx.ID(), name, record.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update bot record: error while storing ownership of name %v by ID %v: %v", name, record.ID, err)
+		}
 	}
 
 	// apply the transactionID to the list of transactionIDs for the given bot
@@ -1003,6 +2155,12 @@ func (txdb *TransactionDB) applyRecordUpdateTx(tx *bolt.Tx, ctx transactionConte
 		return fmt.Errorf("error while applying transaction for bot %d: %v", record.ID, err)
 	}
 
+	// store the updated version of the record
+	err = applyBotRecordVersion(tx, ctx, rtx.ID(), record)
+	if err != nil {
+		return fmt.Errorf("error while storing version of record for bot %d: %v", record.ID, err)
+	}
+
 	// all information is applied
 	return nil
 }
@@ -1012,7 +2170,7 @@ func (txdb *TransactionDB) revertRecordUpdateTx(tx *bolt.Tx, ctx transactionCont
 	if recordBucket == nil {
 		return errors.New("corrupt transaction DB: bot record bucket does not exist")
 	}
-	brutx, err := types.BotRecordUpdateTransactionFromTransaction(*rtx)
+	brutx, err := botRecordUpdateTransactionFromTransaction(*rtx)
 	if err != nil {
 		return fmt.Errorf("unexpected error while unpacking the bot record update tx type: %v", err)
 	}
@@ -1065,6 +2223,11 @@ func (txdb *TransactionDB) revertRecordUpdateTx(tx *bolt.Tx, ctx transactionCont
 					return fmt.Errorf("failed to revert bot record: :"+
 						"failed to add back mapping of expired bot's name %v to its ID %d: %v", name, record.ID, err)
 				}
+				err = revertBotNameOwnership(tx, name, ctx.TransactionShortID())
+				if err != nil {
+					return fmt.Errorf("failed to revert bot record: "+
+						"failed to delete release of expired bot's name %v: %v", name, err)
+				}
 			}
 
 			// delete the implicit record update, it is no longer required
@@ -1081,12 +2244,30 @@ func (txdb *TransactionDB) revertRecordUpdateTx(tx *bolt.Tx, ctx transactionCont
 		return fmt.Errorf("error while updating record for bot %d: %v", brutx.Identifier, err)
 	}
 
+	// revert the address mappings, in the reverse order as they were applied
+	for _, addr := range brutx.Addresses.Add {
+		err = revertAddressToIDMapping(tx, addr, record.ID)
+		if err != nil {
+			return fmt.Errorf("failed to revert update bot record: error while removing mapping of address %v: %v", addr, err)
+		}
+	}
+	for _, addr := range brutx.Addresses.Remove {
+		err = applyAddressToIDMapping(tx, addr, record.ID)
+		if err != nil {
+			return fmt.Errorf("failed to revert update bot record: error while revert mapping of address %v that was removed: %v", addr, err)
+		}
+	}
+
 	// revert all names that were added
 	for _, name := range brutx.Names.Add {
 		err = revertNameToIDMapping(tx, name)
 		if err != nil {
 			return fmt.Errorf("failed to update bot record: error while name %v to ID %v: %v", name, record.ID, err)
 		}
+		err = revertBotNameOwnership(tx, name, ctx.TransactionShortID())
+		if err != nil {
+			return fmt.Errorf("failed to revert update bot record: error while deleting ownership of name %v: %v", name, err)
+		}
 	}
 
 	// apply all names again that were removed,
@@ -1096,6 +2277,10 @@ func (txdb *TransactionDB) revertRecordUpdateTx(tx *bolt.Tx, ctx transactionCont
 		if err != nil {
 			return fmt.Errorf("failed to revert update bot record: error while revert mapping of name %v that was removed: %v", name, err)
 		}
+		err = revertBotNameOwnership(tx, name, ctx.TransactionShortID())
+		if err != nil {
+			return fmt.Errorf("failed to revert update bot record: error while deleting release of name %v: %v", name, err)
+		}
 	}
 
 	// revert the transactionID from the list of transactionIDs for the given bot
@@ -1104,6 +2289,12 @@ func (txdb *TransactionDB) revertRecordUpdateTx(tx *bolt.Tx, ctx transactionCont
 		return fmt.Errorf("error while reverting transaction for bot %d: %v", record.ID, err)
 	}
 
+	// delete the updated version of the record
+	err = revertBotRecordVersion(tx, record.ID, ctx.TransactionShortID())
+	if err != nil {
+		return fmt.Errorf("error while deleting version of record for bot %d: %v", record.ID, err)
+	}
+
 	// all information is applied
 	return nil
 }
@@ -1113,7 +2304,7 @@ func (txdb *TransactionDB) applyBotNameTransferTx(tx *bolt.Tx, ctx transactionCo
 	if recordBucket == nil {
 		return errors.New("corrupt transaction DB: bot record bucket does not exist")
 	}
-	bnttx, err := types.BotNameTransferTransactionFromTransaction(*rtx)
+	bnttx, err := botNameTransferTransactionFromTransaction(*rtx)
 	if err != nil {
 		return fmt.Errorf("unexpected error while unpacking the bot name transfer tx type: %v", err)
 	}
@@ -1144,6 +2335,11 @@ func (txdb *TransactionDB) applyBotNameTransferTx(tx *bolt.Tx, ctx transactionCo
 	if err != nil {
 		return fmt.Errorf("error while applying transaction for sender bot %d: %v", record.ID, err)
 	}
+	// store the updated version of the record of the sender bot
+	err = applyBotRecordVersion(tx, ctx, rtx.ID(), record)
+	if err != nil {
+		return fmt.Errorf("error while storing version of record for sender bot %d: %v", record.ID, err)
+	}
 
 	// get the receiver bot record
 	b = recordBucket.Get(rivbin.Marshal(bnttx.Receiver.Identifier))
@@ -1171,6 +2367,10 @@ func (txdb *TransactionDB) applyBotNameTransferTx(tx *bolt.Tx, ctx transactionCo
 		if err != nil {
 			return fmt.Errorf("failed to update bot record: error while mapping name %v to ID %v: %v", name, record.ID, err)
 		}
+		err = applyBotNameOwnership(tx, ctx, rtx.ID(), name, record.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update bot record: error while storing ownership of name %v by ID %v: %v", name, record.ID, err)
+		}
 	}
 
 	// apply the transactionID to the list of transactionIDs for the receiver bot
@@ -1178,6 +2378,11 @@ func (txdb *TransactionDB) applyBotNameTransferTx(tx *bolt.Tx, ctx transactionCo
 	if err != nil {
 		return fmt.Errorf("error while applying transaction for receiver bot %d: %v", record.ID, err)
 	}
+	// store the updated version of the record of the receiver bot
+	err = applyBotRecordVersion(tx, ctx, rtx.ID(), record)
+	if err != nil {
+		return fmt.Errorf("error while storing version of record for receiver bot %d: %v", record.ID, err)
+	}
 
 	// update went fine
 	return nil
@@ -1187,7 +2392,7 @@ func (txdb *TransactionDB) revertBotNameTransferTx(tx *bolt.Tx, ctx transactionC
 	if recordBucket == nil {
 		return errors.New("corrupt transaction DB: bot record bucket does not exist")
 	}
-	bnttx, err := types.BotNameTransferTransactionFromTransaction(*rtx)
+	bnttx, err := botNameTransferTransactionFromTransaction(*rtx)
 	if err != nil {
 		return fmt.Errorf("unexpected error while unpacking the bot name transfer tx type: %v", err)
 	}
@@ -1218,6 +2423,11 @@ func (txdb *TransactionDB) revertBotNameTransferTx(tx *bolt.Tx, ctx transactionC
 	if err != nil {
 		return fmt.Errorf("error while reverting transaction for receiver bot %d: %v", record.ID, err)
 	}
+	// delete the updated version of the record of the receiver bot
+	err = revertBotRecordVersion(tx, record.ID, ctx.TransactionShortID())
+	if err != nil {
+		return fmt.Errorf("error while deleting version of record for receiver bot %d: %v", record.ID, err)
+	}
 
 	// get the sender bot record
 	b = recordBucket.Get(rivbin.Marshal(bnttx.Sender.Identifier))
@@ -1245,6 +2455,10 @@ func (txdb *TransactionDB) revertBotNameTransferTx(tx *bolt.Tx, ctx transactionC
 		if err != nil {
 			return fmt.Errorf("failed to update bot record: error while mapping name %v to ID %v: %v", name, record.ID, err)
 		}
+		err = revertBotNameOwnership(tx, name, ctx.TransactionShortID())
+		if err != nil {
+			return fmt.Errorf("failed to revert bot record: error while deleting ownership of name %v: %v", name, err)
+		}
 	}
 
 	// revert the transactionID from the list of transactionIDs for the sender bot
@@ -1252,11 +2466,193 @@ func (txdb *TransactionDB) revertBotNameTransferTx(tx *bolt.Tx, ctx transactionC
 	if err != nil {
 		return fmt.Errorf("error while reverting transaction for sender bot %d: %v", record.ID, err)
 	}
+	// delete the updated version of the record of the sender bot
+	err = revertBotRecordVersion(tx, record.ID, ctx.TransactionShortID())
+	if err != nil {
+		return fmt.Errorf("error while deleting version of record for sender bot %d: %v", record.ID, err)
+	}
+
+	// revert went fine
+	return nil
+}
+
+func (txdb *TransactionDB) applyBotKeyRotationTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	recordBucket := tx.Bucket(bucketBotRecords)
+	if recordBucket == nil {
+		return errors.New("corrupt transaction DB: bot record bucket does not exist")
+	}
+	bkrtx, err := types.BotKeyRotationTransactionFromTransaction(*rtx)
+	if err != nil {
+		return fmt.Errorf("unexpected error while unpacking the bot key rotation tx type: %v", err)
+	}
+
+	// get the bot record
+	b := recordBucket.Get(rivbin.Marshal(bkrtx.Identifier))
+	if len(b) == 0 {
+		return errors.New("no bot record found for the specified identifier")
+	}
+	var record types.BotRecord
+	err = rivbin.Unmarshal(b, &record)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal found bot record: %v", err)
+	}
+	previousKey := record.PublicKey
+
+	// update it
+	err = bkrtx.UpdateBotRecord(&record)
+	if err != nil {
+		return fmt.Errorf("failed to update bot record: %v", err)
+	}
+
+	// save it
+	err = recordBucket.Put(rivbin.Marshal(record.ID), rivbin.Marshal(record))
+	if err != nil {
+		return fmt.Errorf("error while updating record for bot %d: %v", record.ID, err)
+	}
+
+	// replace the pubKey->ID mapping
+	err = revertKeyToIDMapping(tx, previousKey)
+	if err != nil {
+		return fmt.Errorf("error while deleting pubKey %s to bot id %d mapping: %v", previousKey, record.ID, err)
+	}
+	err = applyKeyToIDMapping(tx, record.PublicKey, record.ID)
+	if err != nil {
+		return fmt.Errorf("error while storing pubKey %s to bot id %d mapping: %v", record.PublicKey, record.ID, err)
+	}
+
+	// apply the transactionID to the list of transactionIDs for the given bot
+	err = applyBotTransaction(tx, record.ID, ctx.TransactionShortID(), rtx.ID())
+	if err != nil {
+		return fmt.Errorf("error while applying transaction for bot %d: %v", record.ID, err)
+	}
+
+	// store the updated version of the record
+	err = applyBotRecordVersion(tx, ctx, rtx.ID(), record)
+	if err != nil {
+		return fmt.Errorf("error while storing version of record for bot %d: %v", record.ID, err)
+	}
+
+	// update went fine
+	return nil
+}
+func (txdb *TransactionDB) revertBotKeyRotationTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	recordBucket := tx.Bucket(bucketBotRecords)
+	if recordBucket == nil {
+		return errors.New("corrupt transaction DB: bot record bucket does not exist")
+	}
+	bkrtx, err := types.BotKeyRotationTransactionFromTransaction(*rtx)
+	if err != nil {
+		return fmt.Errorf("unexpected error while unpacking the bot key rotation tx type: %v", err)
+	}
+
+	// get the bot record
+	b := recordBucket.Get(rivbin.Marshal(bkrtx.Identifier))
+	if len(b) == 0 {
+		return errors.New("no bot record found for the specified identifier")
+	}
+	var record types.BotRecord
+	err = rivbin.Unmarshal(b, &record)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal found bot record: %v", err)
+	}
+
+	// the Tx doesn't define the previous key and owner,
+	// hence we take it from the version of the record prior to this Tx
+	previousVersion, err := getBotRecordVersionBefore(tx, record.ID, ctx.TransactionShortID())
+	if err != nil {
+		return fmt.Errorf("failed to get the previous key of bot %d: %v", record.ID, err)
+	}
+	record.PublicKey = previousVersion.Record.PublicKey
+	record.Owner = previousVersion.Record.Owner
+
+	// save it
+	err = recordBucket.Put(rivbin.Marshal(record.ID), rivbin.Marshal(record))
+	if err != nil {
+		return fmt.Errorf("error while updating record for bot %d: %v", record.ID, err)
+	}
+
+	// restore the pubKey->ID mapping
+	err = revertKeyToIDMapping(tx, bkrtx.NewIdentification.PublicKey)
+	if err != nil {
+		return fmt.Errorf("error while deleting pubKey %s to bot id %d mapping: %v", bkrtx.NewIdentification.PublicKey, record.ID, err)
+	}
+	err = applyKeyToIDMapping(tx, record.PublicKey, record.ID)
+	if err != nil {
+		return fmt.Errorf("error while storing pubKey %s to bot id %d mapping: %v", record.PublicKey, record.ID, err)
+	}
+
+	// revert the transactionID from the list of transactionIDs for the given bot
+	err = revertBotTransaction(tx, record.ID, ctx.TransactionShortID())
+	if err != nil {
+		return fmt.Errorf("error while reverting transaction for bot %d: %v", record.ID, err)
+	}
+
+	// delete the updated version of the record
+	err = revertBotRecordVersion(tx, record.ID, ctx.TransactionShortID())
+	if err != nil {
+		return fmt.Errorf("error while deleting version of record for bot %d: %v", record.ID, err)
+	}
 
 	// revert went fine
 	return nil
 }
 
+func (txdb *TransactionDB) applyBotNameDelegationTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	bndtx, err := types.BotNameDelegationTransactionFromTransaction(*rtx)
+	if err != nil {
+		return fmt.Errorf("unexpected error while unpacking the bot name delegation tx type: %v", err)
+	}
+	owner := bndtx.Owner.Identifier
+	// update the delegates of the name, removing first, as the update is validated in that order
+	for _, id := range bndtx.Delegates.Remove {
+		err = revertBotNameDelegate(tx, bndtx.Name, owner, id)
+		if err != nil {
+			return fmt.Errorf("error while removing delegate %d of name %v: %v", id, bndtx.Name, err)
+		}
+	}
+	for _, id := range bndtx.Delegates.Add {
+		err = applyBotNameDelegate(tx, bndtx.Name, owner, id)
+		if err != nil {
+			return fmt.Errorf("error while adding delegate %d to name %v: %v", id, bndtx.Name, err)
+		}
+	}
+	// apply the transactionID to the list of transactionIDs for the owner bot
+	err = applyBotTransaction(tx, owner, ctx.TransactionShortID(), rtx.ID())
+	if err != nil {
+		return fmt.Errorf("error while applying transaction for bot %d: %v", owner, err)
+	}
+	// all information is applied
+	return nil
+}
+
+func (txdb *TransactionDB) revertBotNameDelegationTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	bndtx, err := types.BotNameDelegationTransactionFromTransaction(*rtx)
+	if err != nil {
+		return fmt.Errorf("unexpected error while unpacking the bot name delegation tx type: %v", err)
+	}
+	owner := bndtx.Owner.Identifier
+	// revert the update of the delegates of the name, in the reverse order as it was applied
+	for _, id := range bndtx.Delegates.Add {
+		err = revertBotNameDelegate(tx, bndtx.Name, owner, id)
+		if err != nil {
+			return fmt.Errorf("error while removing added delegate %d of name %v: %v", id, bndtx.Name, err)
+		}
+	}
+	for _, id := range bndtx.Delegates.Remove {
+		err = applyBotNameDelegate(tx, bndtx.Name, owner, id)
+		if err != nil {
+			return fmt.Errorf("error while adding removed delegate %d to name %v: %v", id, bndtx.Name, err)
+		}
+	}
+	// revert the transactionID from the list of transactionIDs for the owner bot
+	err = revertBotTransaction(tx, owner, ctx.TransactionShortID())
+	if err != nil {
+		return fmt.Errorf("error while reverting transaction for bot %d: %v", owner, err)
+	}
+	// all information is reverted
+	return nil
+}
+
 func (txdb *TransactionDB) applyERC20AddressRegistrationTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
 	etartx, err := types.ERC20AddressRegistrationTransactionFromTransaction(*rtx)
 	if err != nil {
@@ -1286,78 +2682,750 @@ func (txdb *TransactionDB) applyERC20CoinCreationTx(tx *bolt.Tx, ctx transaction
 	if err != nil {
 		return fmt.Errorf("unexpected error while unpacking the ERC20 Coin Creation Tx type: %v", err)
 	}
-	return applyERC20TransactionID(tx, etcctx.TransactionID, rtx.ID())
-}
-
-func (txdb *TransactionDB) revertERC20CoinCreationTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
-	etcctx, err := types.ERC20CoinCreationTransactionFromTransaction(*rtx)
+	err = applyERC20TransactionID(tx, etcctx.TransactionID, rtx.ID())
+	if err != nil {
+		return err
+	}
+	record := newCoinCreationRecord(ctx, rtx, CoinCreationTypeERC20)
+	err = applyCoinCreationRecord(tx, ctx.TransactionShortID(), record)
+	if err != nil {
+		return err
+	}
+	return updateCoinSupply(tx, func(supply *CoinSupply) {
+		supply.ERC20CreatedIn = supply.ERC20CreatedIn.Add(record.Value)
+	})
+}
+
+func (txdb *TransactionDB) revertERC20CoinCreationTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	etcctx, err := types.ERC20CoinCreationTransactionFromTransaction(*rtx)
+	if err != nil {
+		return fmt.Errorf("unexpected error while unpacking the ERC20 Coin Creation Tx type: %v", err)
+	}
+	err = revertERC20TransactionID(tx, etcctx.TransactionID)
+	if err != nil {
+		return err
+	}
+	err = revertCoinCreationRecord(tx, ctx.TransactionShortID())
+	if err != nil {
+		return err
+	}
+	return updateCoinSupply(tx, func(supply *CoinSupply) {
+		supply.ERC20CreatedIn = supply.ERC20CreatedIn.Sub(newCoinCreationRecord(ctx, rtx, CoinCreationTypeERC20).Value)
+	})
+}
+
+func (txdb *TransactionDB) applyCoinBurnTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	cbtx, err := types.CoinBurnTransactionFromTransaction(*rtx)
+	if err != nil {
+		return fmt.Errorf("unexpected error while unpacking the coin burn tx type: %v", err)
+	}
+	coinBurnsBucket := tx.Bucket(bucketCoinBurns)
+	if coinBurnsBucket == nil {
+		return errors.New("corrupt transaction DB: coin burns bucket does not exist")
+	}
+	err = coinBurnsBucket.Put(rivbin.Marshal(ctx.TransactionShortID()), rivbin.Marshal(CoinBurnRecord{
+		TransactionID: rtx.ID(),
+		BlockHeight:   ctx.BlockHeight - 1,
+		BlockTime:     ctx.BlockTime,
+		Value:         cbtx.Value,
+		Reason:        cbtx.Reason,
+	}))
+	if err != nil {
+		return fmt.Errorf("failed to store the coin burn record: %v", err)
+	}
+	return updateCoinSupply(tx, func(supply *CoinSupply) {
+		supply.Burned = supply.Burned.Add(cbtx.Value)
+	})
+}
+
+func (txdb *TransactionDB) revertCoinBurnTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	cbtx, err := types.CoinBurnTransactionFromTransaction(*rtx)
+	if err != nil {
+		return fmt.Errorf("unexpected error while unpacking the coin burn tx type: %v", err)
+	}
+	coinBurnsBucket := tx.Bucket(bucketCoinBurns)
+	if coinBurnsBucket == nil {
+		return errors.New("corrupt transaction DB: coin burns bucket does not exist")
+	}
+	err = coinBurnsBucket.Delete(rivbin.Marshal(ctx.TransactionShortID()))
+	if err != nil {
+		return fmt.Errorf("failed to delete the coin burn record: %v", err)
+	}
+	return updateCoinSupply(tx, func(supply *CoinSupply) {
+		supply.Burned = supply.Burned.Sub(cbtx.Value)
+	})
+}
+
+func (txdb *TransactionDB) applyERC20ConvertTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	etctx, err := types.ERC20ConvertTransactionFromTransaction(*rtx)
+	if err != nil {
+		return fmt.Errorf("unexpected error while unpacking the ERC20 Convert Tx type: %v", err)
+	}
+	return updateCoinSupply(tx, func(supply *CoinSupply) {
+		supply.ERC20ConvertedOut = supply.ERC20ConvertedOut.Add(etctx.Value)
+	})
+}
+
+func (txdb *TransactionDB) revertERC20ConvertTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	etctx, err := types.ERC20ConvertTransactionFromTransaction(*rtx)
+	if err != nil {
+		return fmt.Errorf("unexpected error while unpacking the ERC20 Convert Tx type: %v", err)
+	}
+	return updateCoinSupply(tx, func(supply *CoinSupply) {
+		supply.ERC20ConvertedOut = supply.ERC20ConvertedOut.Sub(etctx.Value)
+	})
+}
+
+func (txdb *TransactionDB) applyCapacityRegistrationTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	crtx, err := types.CapacityRegistrationTransactionFromTransaction(*rtx)
+	if err != nil {
+		return fmt.Errorf("unexpected error while unpacking the capacity registration tx type: %v", err)
+	}
+	return applyCapacityRecord(tx, ctx.TransactionShortID(), types.CapacityRecord{
+		Node:          crtx.Node,
+		Farm:          crtx.Farm,
+		Capacity:      crtx.Capacity,
+		TransactionID: rtx.ID(),
+		BlockHeight:   ctx.BlockHeight,
+	})
+}
+
+func (txdb *TransactionDB) revertCapacityRegistrationTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	crtx, err := types.CapacityRegistrationTransactionFromTransaction(*rtx)
+	if err != nil {
+		return fmt.Errorf("unexpected error while unpacking the capacity registration tx type: %v", err)
+	}
+	return revertCapacityRecord(tx, ctx.TransactionShortID(), crtx.Node, crtx.Farm)
+}
+
+func (txdb *TransactionDB) applyCapacityProofTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	cptx, err := types.CapacityProofTransactionFromTransaction(*rtx)
+	if err != nil {
+		return fmt.Errorf("unexpected error while unpacking the capacity proof tx type: %v", err)
+	}
+	record, err := getLatestCapacityRecordForNode(tx, cptx.Node)
+	if err != nil {
+		return fmt.Errorf("error while fetching capacity record of node %v: %v", cptx.Node, err)
+	}
+	return applyCapacityProofResult(tx, rivbin.Marshal(cptx.Node), types.CapacityProofResult{
+		ChallengeHeight: cptx.ChallengeHeight,
+		Passed:          cptx.Capacity.Covers(record.Capacity),
+		Capacity:        cptx.Capacity,
+		TransactionID:   rtx.ID(),
+	})
+}
+
+func (txdb *TransactionDB) revertCapacityProofTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	cptx, err := types.CapacityProofTransactionFromTransaction(*rtx)
+	if err != nil {
+		return fmt.Errorf("unexpected error while unpacking the capacity proof tx type: %v", err)
+	}
+	return revertCapacityProofResult(tx, rivbin.Marshal(cptx.Node), cptx.ChallengeHeight)
+}
+
+// applyCapacityChallenges stores the block as a capacity challenge if it is a challenge block,
+// and records a failed result for all challenged nodes that did not respond to the challenge
+// of which the response window closes with this block.
+func (txdb *TransactionDB) applyCapacityChallenges(tx *bolt.Tx, height rivinetypes.BlockHeight, blockID rivinetypes.BlockID) error {
+	if types.IsCapacityChallengeHeight(height) {
+		challengesBucket := tx.Bucket(bucketCapacityChallenges)
+		if challengesBucket == nil {
+			return errors.New("corrupt transaction DB: capacity challenges bucket does not exist")
+		}
+		err := challengesBucket.Put(internal.EncodeBlockheight(height), rivbin.Marshal(blockID))
+		if err != nil {
+			return fmt.Errorf("error while storing capacity challenge at height %d: %v", height, err)
+		}
+	}
+	challengeHeight, ok := closedCapacityChallengeHeight(height)
+	if !ok {
+		return nil
+	}
+	nodesBucket := tx.Bucket(bucketCapacityNodes)
+	if nodesBucket == nil {
+		return errors.New("corrupt transaction DB: capacity nodes bucket does not exist")
+	}
+	// only nodes registered prior to the challenge block are challenged,
+	// the challenge block is stored at the next TransactionDB height
+	challengeKey := rivbin.Marshal(newSortableTransactionShortID(challengeHeight+1, 0))
+	return nodesBucket.ForEach(func(nodeKey, _ []byte) error {
+		if k, _ := nodesBucket.Bucket(nodeKey).Cursor().First(); k == nil || bytes.Compare(k, challengeKey) >= 0 {
+			return nil // node was not yet registered at the time of the challenge
+		}
+		_, err := getCapacityProofResultForNodeKey(tx, nodeKey, challengeHeight)
+		if err == nil {
+			return nil // node responded in time
+		}
+		if err != types.ErrCapacityProofResultNotFound {
+			return err
+		}
+		return applyCapacityProofResult(tx, nodeKey, types.CapacityProofResult{
+			ChallengeHeight: challengeHeight,
+			Passed:          false,
+		})
+	})
+}
+
+// revertCapacityChallenges reverts all information stored by applyCapacityChallenges for the block at the given height.
+func (txdb *TransactionDB) revertCapacityChallenges(tx *bolt.Tx, height rivinetypes.BlockHeight) error {
+	if challengeHeight, ok := closedCapacityChallengeHeight(height); ok {
+		proofsBucket := tx.Bucket(bucketCapacityProofs)
+		if proofsBucket == nil {
+			return errors.New("corrupt transaction DB: capacity proofs bucket does not exist")
+		}
+		// collect the nodes first, as a bucket cannot be modified while iterating over it
+		var nodeKeys [][]byte
+		err := proofsBucket.ForEach(func(nodeKey, _ []byte) error {
+			result, err := getCapacityProofResultForNodeKey(tx, nodeKey, challengeHeight)
+			if err == types.ErrCapacityProofResultNotFound {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if result.TransactionID == (rivinetypes.TransactionID{}) {
+				// only results recorded for nodes that did not respond are to be reverted
+				nodeKeys = append(nodeKeys, nodeKey)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, nodeKey := range nodeKeys {
+			err = revertCapacityProofResult(tx, nodeKey, challengeHeight)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if types.IsCapacityChallengeHeight(height) {
+		challengesBucket := tx.Bucket(bucketCapacityChallenges)
+		if challengesBucket == nil {
+			return errors.New("corrupt transaction DB: capacity challenges bucket does not exist")
+		}
+		err := challengesBucket.Delete(internal.EncodeBlockheight(height))
+		if err != nil {
+			return fmt.Errorf("error while deleting capacity challenge at height %d: %v", height, err)
+		}
+	}
+	return nil
+}
+
+// closedCapacityChallengeHeight returns the height of the challenge
+// of which the response window closes with the block at the given height, if any.
+func closedCapacityChallengeHeight(height rivinetypes.BlockHeight) (rivinetypes.BlockHeight, bool) {
+	if height <= types.CapacityProofResponseWindow {
+		return 0, false
+	}
+	challengeHeight := height - types.CapacityProofResponseWindow - 1
+	return challengeHeight, types.IsCapacityChallengeHeight(challengeHeight)
+}
+
+func (txdb *TransactionDB) applyFarmRegistrationTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	recordsBucket := tx.Bucket(bucketFarmRecords)
+	if recordsBucket == nil {
+		return errors.New("corrupt transaction DB: farm records bucket does not exist")
+	}
+	frtx, err := types.FarmRegistrationTransactionFromTransaction(*rtx)
+	if err != nil {
+		return fmt.Errorf("unexpected error while unpacking the farm registration tx type: %v", err)
+	}
+	// get the unique ID for the farm, using bolt's auto incrementing feature
+	sequenceIndex, err := recordsBucket.NextSequence()
+	if err != nil {
+		return fmt.Errorf("error while getting auto incrementing sequence farm ID: %v", err)
+	}
+	if sequenceIndex > types.MaxFarmID {
+		return errors.New("error while getting auto incrementing sequence farm ID: value exceeds 32 bit")
+	}
+	id := types.FarmID(sequenceIndex)
+	// create the record
+	record, err := frtx.FarmRecord(id)
+	if err != nil {
+		return fmt.Errorf("error while creating record for farm %d: %v", id, err)
+	}
+	// store the record and the name mapping, assuming the consensus validated that
+	// the registration Tx is completely valid
+	err = applyFarmRecord(tx, ctx.TransactionShortID(), record)
+	if err != nil {
+		return err
+	}
+	return applyFarmNameToIDMapping(tx, record.Name, id)
+}
+
+func (txdb *TransactionDB) revertFarmRegistrationTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	recordsBucket := tx.Bucket(bucketFarmRecords)
+	if recordsBucket == nil {
+		return errors.New("corrupt transaction DB: farm records bucket does not exist")
+	}
+	frtx, err := types.FarmRegistrationTransactionFromTransaction(*rtx)
+	if err != nil {
+		return fmt.Errorf("unexpected error while unpacking the farm registration tx type: %v", err)
+	}
+	// the ID should be equal to the current bucket sequence, given it was incremented by the registration process
+	rbSequence := recordsBucket.Sequence()
+	id := types.FarmID(rbSequence)
+	// delete the record (history) and name mapping
+	err = recordsBucket.DeleteBucket(rivbin.Marshal(id))
+	if err != nil {
+		return fmt.Errorf("error while deleting record for farm %d: %v", id, err)
+	}
+	err = revertFarmNameToIDMapping(tx, frtx.Name)
+	if err != nil {
+		return err
+	}
+	// decrease the sequence counter of the bucket
+	err = recordsBucket.SetSequence(rbSequence - 1)
+	if err != nil {
+		return fmt.Errorf("error while decrementing the sequence counter of farm records bucket: %v", err)
+	}
+	// all information is reverted
+	return nil
+}
+
+func (txdb *TransactionDB) applyFarmUpdateTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	futx, err := types.FarmUpdateTransactionFromTransaction(*rtx)
+	if err != nil {
+		return fmt.Errorf("unexpected error while unpacking the farm update tx type: %v", err)
+	}
+	record, err := getLatestFarmRecord(tx, futx.Identifier)
+	if err != nil {
+		return fmt.Errorf("error while fetching record for farm %d: %v", futx.Identifier, err)
+	}
+	previousName := record.Name
+	err = futx.UpdateFarmRecord(record)
+	if err != nil {
+		return fmt.Errorf("error while updating record for farm %d: %v", futx.Identifier, err)
+	}
+	err = applyFarmRecord(tx, ctx.TransactionShortID(), *record)
+	if err != nil {
+		return err
+	}
+	if record.Name == previousName {
+		return nil // name mapping remains unchanged
+	}
+	err = revertFarmNameToIDMapping(tx, previousName)
+	if err != nil {
+		return err
+	}
+	return applyFarmNameToIDMapping(tx, record.Name, record.ID)
+}
+
+func (txdb *TransactionDB) revertFarmUpdateTx(tx *bolt.Tx, ctx transactionContext, rtx *rivinetypes.Transaction) error {
+	futx, err := types.FarmUpdateTransactionFromTransaction(*rtx)
+	if err != nil {
+		return fmt.Errorf("unexpected error while unpacking the farm update tx type: %v", err)
+	}
+	// delete the record created by this update, restoring the previous record as the latest one
+	err = revertFarmRecord(tx, ctx.TransactionShortID(), futx.Identifier)
+	if err != nil {
+		return err
+	}
+	if futx.Name == "" {
+		return nil // name mapping remains unchanged
+	}
+	record, err := getLatestFarmRecord(tx, futx.Identifier)
+	if err != nil {
+		return fmt.Errorf("error while fetching record for farm %d: %v", futx.Identifier, err)
+	}
+	err = revertFarmNameToIDMapping(tx, futx.Name)
+	if err != nil {
+		return err
+	}
+	return applyFarmNameToIDMapping(tx, record.Name, record.ID)
+}
+
+// apply/revert the Key->ID mapping for a 3bot
+func applyKeyToIDMapping(tx *bolt.Tx, key rivinetypes.PublicKey, id types.BotID) error {
+	mappingBucket := tx.Bucket(bucketBotKeyToIDMapping)
+	if mappingBucket == nil {
+		return errors.New("corrupt transaction DB: bot key bucket does not exist")
+	}
+	return mappingBucket.Put(rivbin.Marshal(key), rivbin.Marshal(id))
+}
+func revertKeyToIDMapping(tx *bolt.Tx, key rivinetypes.PublicKey) error {
+	mappingBucket := tx.Bucket(bucketBotKeyToIDMapping)
+	if mappingBucket == nil {
+		return errors.New("corrupt transaction DB: bot key bucket does not exist")
+	}
+	return mappingBucket.Delete(rivbin.Marshal(key))
+}
+
+// apply/revert the Name->ID mapping for a 3bot,
+// keeping the Name->children mapping of its parent in sync as well
+func applyNameToIDMapping(tx *bolt.Tx, name types.BotName, id types.BotID) error {
+	mappingBucket := tx.Bucket(bucketBotNameToIDMapping)
+	if mappingBucket == nil {
+		return errors.New("corrupt transaction DB: bot name bucket does not exist")
+	}
+	err := mappingBucket.Put(rivbin.Marshal(name), rivbin.Marshal(id))
+	if err != nil {
+		return err
+	}
+	return applyNameToParentMapping(tx, name)
+}
+func revertNameToIDMapping(tx *bolt.Tx, name types.BotName) error {
+	mappingBucket := tx.Bucket(bucketBotNameToIDMapping)
+	if mappingBucket == nil {
+		return errors.New("corrupt transaction DB: bot name bucket does not exist")
+	}
+	err := mappingBucket.Delete(rivbin.Marshal(name))
+	if err != nil {
+		return err
+	}
+	return revertNameToParentMapping(tx, name)
+}
+func revertNameToIDMappingIfOwnedByBot(tx *bolt.Tx, name types.BotName, id types.BotID) (bool, error) {
+	mappingBucket := tx.Bucket(bucketBotNameToIDMapping)
+	if mappingBucket == nil {
+		return false, errors.New("corrupt transaction DB: bot name bucket does not exist")
+	}
+	b := mappingBucket.Get(rivbin.Marshal(name))
+	if len(b) == 0 {
+		return false, nil // might be deleted by another bot, who took over ownership
+	}
+	var mappedID types.BotID
+	err := rivbin.Unmarshal(b, &mappedID)
+	if err != nil {
+		return false, fmt.Errorf("corrupt BotID used as key in mapping of bot name %v", name)
+	}
+	if mappedID != id {
+		return false, nil // ID no longer owned by this bot, ignore removal request in the mapping context
+	}
+	// delete name (mapping), as it was still owned by this bot
+	err = mappingBucket.Delete(rivbin.Marshal(name))
+	if err != nil {
+		return false, err
+	}
+	return true, revertNameToParentMapping(tx, name)
+}
+func applyNameToIDMappingIfAvailable(tx *bolt.Tx, name types.BotName, id types.BotID) error {
+	mappingBucket := tx.Bucket(bucketBotNameToIDMapping)
+	if mappingBucket == nil {
+		return errors.New("corrupt transaction DB: bot name bucket does not exist")
+	}
+	b := mappingBucket.Get(rivbin.Marshal(name))
+	if len(b) != 0 {
+		return nil // already taken
+	}
+	err := mappingBucket.Put(rivbin.Marshal(name), rivbin.Marshal(id))
+	if err != nil {
+		return err
+	}
+	return applyNameToParentMapping(tx, name)
+}
+func getActiveBotIDForName(tx *bolt.Tx, name types.BotName, chainTime rivinetypes.Timestamp) (types.BotID, error) {
+	mappingBucket := tx.Bucket(bucketBotNameToIDMapping)
+	if mappingBucket == nil {
+		return 0, errors.New("corrupt transaction DB: bot name bucket does not exist")
+	}
+	b := mappingBucket.Get(rivbin.Marshal(name))
+	if len(b) == 0 {
+		return 0, types.ErrBotNameNotFound
+	}
+	var id types.BotID
+	err := rivbin.Unmarshal(b, &id)
+	if err != nil {
+		return 0, err
+	}
+	record, err := getRecordForID(tx, id)
+	if err != nil {
+		return 0, err
+	}
+	if record.IsExpired(chainTime) {
+		return 0, types.ErrBotNameExpired
+	}
+	return id, nil
+}
+
+// apply/revert/get the Name->children mapping for 3bot names,
+// such that all (registered) sub names of a name can be listed
+func applyNameToParentMapping(tx *bolt.Tx, name types.BotName) error {
+	parent, ok := name.Parent()
+	if !ok {
+		return nil // top-level names have no parent
+	}
+	childrenBucket := tx.Bucket(bucketBotNameChildren)
+	if childrenBucket == nil {
+		return errors.New("corrupt transaction DB: bot name children bucket does not exist")
+	}
+	parentBucket, err := childrenBucket.CreateBucketIfNotExists(rivbin.Marshal(parent))
+	if err != nil {
+		return fmt.Errorf("corrupt transaction DB: failed to create/get name %v children inner bucket: %v", parent, err)
+	}
+	return parentBucket.Put(rivbin.Marshal(name), []byte{})
+}
+func revertNameToParentMapping(tx *bolt.Tx, name types.BotName) error {
+	parent, ok := name.Parent()
+	if !ok {
+		return nil // top-level names have no parent
+	}
+	childrenBucket := tx.Bucket(bucketBotNameChildren)
+	if childrenBucket == nil {
+		return errors.New("corrupt transaction DB: bot name children bucket does not exist")
+	}
+	parentBucket := childrenBucket.Bucket(rivbin.Marshal(parent))
+	if parentBucket == nil {
+		return nil // nothing to revert
+	}
+	err := parentBucket.Delete(rivbin.Marshal(name))
+	if err != nil {
+		return err
+	}
+	// delete the inner bucket once it is empty, such that a name without children doesn't take up any space
+	if k, _ := parentBucket.Cursor().First(); k == nil {
+		return childrenBucket.DeleteBucket(rivbin.Marshal(parent))
+	}
+	return nil
+}
+func getBotNameChildren(tx *bolt.Tx, name types.BotName) ([]types.BotName, error) {
+	childrenBucket := tx.Bucket(bucketBotNameChildren)
+	if childrenBucket == nil {
+		return nil, errors.New("corrupt transaction DB: bot name children bucket does not exist")
+	}
+	parentBucket := childrenBucket.Bucket(rivbin.Marshal(name))
+	if parentBucket == nil {
+		return nil, nil // no children is acceptable
+	}
+	var children []types.BotName
+	err := parentBucket.ForEach(func(k, _ []byte) (err error) {
+		var child types.BotName
+		err = rivbin.Unmarshal(k, &child)
+		children = append(children, child)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("corrupt transaction DB: error while parsing stored child of name %v: %v", name, err)
+	}
+	// names are encoded with a length prefix, and thus not sorted lexicographically by bolt
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].Compare(children[j]) < 0
+	})
+	return children, nil
+}
+
+// apply/revert/get the delegates of a 3bot name, stored per owner of that name,
+// such that delegations made by a previous owner of the name are no longer used
+func applyBotNameDelegate(tx *bolt.Tx, name types.BotName, owner, delegate types.BotID) error {
+	delegatesBucket := tx.Bucket(bucketBotNameDelegates)
+	if delegatesBucket == nil {
+		return errors.New("corrupt transaction DB: bot name delegates bucket does not exist")
+	}
+	nameBucket, err := delegatesBucket.CreateBucketIfNotExists(rivbin.Marshal(name))
+	if err != nil {
+		return fmt.Errorf("corrupt transaction DB: failed to create/get name %v delegates inner bucket: %v", name, err)
+	}
+	ownerBucket, err := nameBucket.CreateBucketIfNotExists(rivbin.Marshal(owner))
+	if err != nil {
+		return fmt.Errorf("corrupt transaction DB: failed to create/get name %v delegates inner bucket of owner %d: %v", name, owner, err)
+	}
+	return ownerBucket.Put(rivbin.Marshal(delegate), []byte{})
+}
+func revertBotNameDelegate(tx *bolt.Tx, name types.BotName, owner, delegate types.BotID) error {
+	delegatesBucket := tx.Bucket(bucketBotNameDelegates)
+	if delegatesBucket == nil {
+		return errors.New("corrupt transaction DB: bot name delegates bucket does not exist")
+	}
+	nameBucket := delegatesBucket.Bucket(rivbin.Marshal(name))
+	if nameBucket == nil {
+		return fmt.Errorf("corrupt transaction DB: name %v delegates inner bucket does not exist", name)
+	}
+	ownerBucket := nameBucket.Bucket(rivbin.Marshal(owner))
+	if ownerBucket == nil {
+		return fmt.Errorf("corrupt transaction DB: name %v delegates inner bucket of owner %d does not exist", name, owner)
+	}
+	err := ownerBucket.Delete(rivbin.Marshal(delegate))
+	if err != nil {
+		return err
+	}
+	// delete the inner buckets once they are empty, such that a name without delegates doesn't take up any space
+	if k, _ := ownerBucket.Cursor().First(); k != nil {
+		return nil
+	}
+	err = nameBucket.DeleteBucket(rivbin.Marshal(owner))
+	if err != nil {
+		return err
+	}
+	if k, _ := nameBucket.Cursor().First(); k == nil {
+		return delegatesBucket.DeleteBucket(rivbin.Marshal(name))
+	}
+	return nil
+}
+func getBotNameDelegates(tx *bolt.Tx, name types.BotName, owner types.BotID) ([]types.BotID, error) {
+	delegatesBucket := tx.Bucket(bucketBotNameDelegates)
+	if delegatesBucket == nil {
+		return nil, errors.New("corrupt transaction DB: bot name delegates bucket does not exist")
+	}
+	nameBucket := delegatesBucket.Bucket(rivbin.Marshal(name))
+	if nameBucket == nil {
+		return nil, nil // no delegates is acceptable
+	}
+	ownerBucket := nameBucket.Bucket(rivbin.Marshal(owner))
+	if ownerBucket == nil {
+		return nil, nil // no delegates is acceptable
+	}
+	var ids []types.BotID
+	err := ownerBucket.ForEach(func(k, _ []byte) (err error) {
+		var id types.BotID
+		err = rivbin.Unmarshal(k, &id)
+		ids = append(ids, id)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("corrupt transaction DB: error while parsing stored delegate of name %v: %v", name, err)
+	}
+	// IDs are encoded in little endian, and thus not sorted naturally by bolt
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	return ids, nil
+}
+
+// apply/revert/get the Address->IDs mapping for 3bots,
+// as unlike names, a network address can be registered by multiple 3bots
+func applyAddressToIDMapping(tx *bolt.Tx, addr types.NetworkAddress, id types.BotID) error {
+	mappingBucket := tx.Bucket(bucketBotAddressToIDsMapping)
+	if mappingBucket == nil {
+		return errors.New("corrupt transaction DB: bot address bucket does not exist")
+	}
+	addrBucket, err := mappingBucket.CreateBucketIfNotExists(rivbin.Marshal(addr))
+	if err != nil {
+		return fmt.Errorf("corrupt transaction DB: failed to create/get address %v inner bucket: %v", addr, err)
+	}
+	return addrBucket.Put(rivbin.Marshal(id), []byte{})
+}
+func revertAddressToIDMapping(tx *bolt.Tx, addr types.NetworkAddress, id types.BotID) error {
+	mappingBucket := tx.Bucket(bucketBotAddressToIDsMapping)
+	if mappingBucket == nil {
+		return errors.New("corrupt transaction DB: bot address bucket does not exist")
+	}
+	addrBucket := mappingBucket.Bucket(rivbin.Marshal(addr))
+	if addrBucket == nil {
+		return fmt.Errorf("corrupt transaction DB: address %v inner bucket does not exist", addr)
+	}
+	err := addrBucket.Delete(rivbin.Marshal(id))
+	if err != nil {
+		return err
+	}
+	// delete the inner bucket once it is empty, such that an unused address doesn't take up any space
+	if k, _ := addrBucket.Cursor().First(); k == nil {
+		return mappingBucket.DeleteBucket(rivbin.Marshal(addr))
+	}
+	return nil
+}
+func getBotIDsForNetworkAddress(tx *bolt.Tx, addr types.NetworkAddress) ([]types.BotID, error) {
+	mappingBucket := tx.Bucket(bucketBotAddressToIDsMapping)
+	if mappingBucket == nil {
+		return nil, errors.New("corrupt transaction DB: bot address bucket does not exist")
+	}
+	addrBucket := mappingBucket.Bucket(rivbin.Marshal(addr))
+	if addrBucket == nil {
+		return nil, nil // no bots is acceptable
+	}
+	var ids []types.BotID
+	err := addrBucket.ForEach(func(k, _ []byte) (err error) {
+		var id types.BotID
+		err = rivbin.Unmarshal(k, &id)
+		ids = append(ids, id)
+		return
+	})
 	if err != nil {
-		return fmt.Errorf("unexpected error while unpacking the ERC20 Coin Creation Tx type: %v", err)
+		return nil, fmt.Errorf("corrupt transaction DB: error while parsing stored ID for address %v: %v", addr, err)
 	}
-	return revertERC20TransactionID(tx, etcctx.TransactionID)
+	// IDs are encoded in little endian, and thus not sorted naturally by bolt
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	return ids, nil
 }
 
-// apply/revert the Key->ID mapping for a 3bot
-func applyKeyToIDMapping(tx *bolt.Tx, key rivinetypes.PublicKey, id types.BotID) error {
-	mappingBucket := tx.Bucket(bucketBotKeyToIDMapping)
-	if mappingBucket == nil {
-		return errors.New("corrupt transaction DB: bot key bucket does not exist")
+// newCoinCreationRecord creates a record for the given coin creation Tx,
+// all coins of which, including the miner fees, are created by the Tx itself.
+func newCoinCreationRecord(ctx transactionContext, rtx *rivinetypes.Transaction, ct CoinCreationType) CoinCreationRecord {
+	record := CoinCreationRecord{
+		TransactionID: rtx.ID(),
+		BlockHeight:   ctx.BlockHeight - 1,
+		BlockTime:     ctx.BlockTime,
+		Type:          ct,
+		ArbitraryData: rtx.ArbitraryData,
 	}
-	return mappingBucket.Put(rivbin.Marshal(key), rivbin.Marshal(id))
-}
-func revertKeyToIDMapping(tx *bolt.Tx, key rivinetypes.PublicKey) error {
-	mappingBucket := tx.Bucket(bucketBotKeyToIDMapping)
-	if mappingBucket == nil {
-		return errors.New("corrupt transaction DB: bot key bucket does not exist")
+	for _, co := range rtx.CoinOutputs {
+		record.Value = record.Value.Add(co.Value)
 	}
-	return mappingBucket.Delete(rivbin.Marshal(key))
+	for _, fee := range rtx.MinerFees {
+		record.Value = record.Value.Add(fee)
+	}
+	return record
 }
 
-// apply/revert the Name->ID mapping for a 3bot
-func applyNameToIDMapping(tx *bolt.Tx, name types.BotName, id types.BotID) error {
-	mappingBucket := tx.Bucket(bucketBotNameToIDMapping)
-	if mappingBucket == nil {
-		return errors.New("corrupt transaction DB: bot name bucket does not exist")
+func applyCoinCreationRecord(tx *bolt.Tx, shortTxID sortableTransactionShortID, record CoinCreationRecord) error {
+	coinCreationsBucket := tx.Bucket(bucketCoinCreations)
+	if coinCreationsBucket == nil {
+		return errors.New("corrupt transaction DB: coin creations bucket does not exist")
 	}
-	return mappingBucket.Put(rivbin.Marshal(name), rivbin.Marshal(id))
+	return coinCreationsBucket.Put(rivbin.Marshal(shortTxID), rivbin.Marshal(record))
 }
-func revertNameToIDMapping(tx *bolt.Tx, name types.BotName) error {
-	mappingBucket := tx.Bucket(bucketBotNameToIDMapping)
-	if mappingBucket == nil {
-		return errors.New("corrupt transaction DB: bot name bucket does not exist")
+func revertCoinCreationRecord(tx *bolt.Tx, shortTxID sortableTransactionShortID) error {
+	coinCreationsBucket := tx.Bucket(bucketCoinCreations)
+	if coinCreationsBucket == nil {
+		return errors.New("corrupt transaction DB: coin creations bucket does not exist")
 	}
-	return mappingBucket.Delete(rivbin.Marshal(name))
+	return coinCreationsBucket.Delete(rivbin.Marshal(shortTxID))
 }
-func revertNameToIDMappingIfOwnedByBot(tx *bolt.Tx, name types.BotName, id types.BotID) error {
-	mappingBucket := tx.Bucket(bucketBotNameToIDMapping)
-	if mappingBucket == nil {
-		return errors.New("corrupt transaction DB: bot name bucket does not exist")
+
+func getCoinSupply(tx *bolt.Tx) (CoinSupply, error) {
+	internalBucket := tx.Bucket(bucketInternal)
+	if internalBucket == nil {
+		return CoinSupply{}, errors.New("corrupt transaction DB: internal bucket does not exist")
 	}
-	b := mappingBucket.Get(rivbin.Marshal(name))
+	b := internalBucket.Get(bucketInternalKeySupply)
 	if len(b) == 0 {
-		return nil // might be deleted by another bot, who took over ownership
+		return CoinSupply{}, errors.New("corrupt transaction DB: coin supply could not be found")
 	}
-	var mappedID types.BotID
-	err := rivbin.Unmarshal(b, &mappedID)
+	var supply CoinSupply
+	err := siabin.Unmarshal(b, &supply)
 	if err != nil {
-		return fmt.Errorf("corrupt BotID used as key in mapping of bot name %v", name)
+		return CoinSupply{}, fmt.Errorf("corrupt transaction DB: failed to decode coin supply: %v", err)
 	}
-	if mappedID != id {
-		return nil // ID no longer owned by this bot, ignore removal request in the mapping context
+	return supply, nil
+}
+
+// updateCoinSupply updates the stored coin supply using the given function.
+func updateCoinSupply(tx *bolt.Tx, update func(*CoinSupply)) error {
+	supply, err := getCoinSupply(tx)
+	if err != nil {
+		return err
 	}
-	// delete name (mapping), as it was still owned by this bot
-	return mappingBucket.Delete(rivbin.Marshal(name))
+	update(&supply)
+	return tx.Bucket(bucketInternal).Put(bucketInternalKeySupply, siabin.Marshal(supply))
 }
-func applyNameToIDMappingIfAvailable(tx *bolt.Tx, name types.BotName, id types.BotID) error {
-	mappingBucket := tx.Bucket(bucketBotNameToIDMapping)
-	if mappingBucket == nil {
-		return errors.New("corrupt transaction DB: bot name bucket does not exist")
+
+// blockCoinIssuance returns the coins issued by the given block itself, given the height the TransactionDB assigns to it:
+// the coin outputs of the genesis block (height 1), and the block rewards of all other blocks,
+// being the miner payouts that are not paid for by the (miner fees of the) transactions of the block.
+func blockCoinIssuance(block rivinetypes.Block, height rivinetypes.BlockHeight) (genesis, rewards rivinetypes.Currency) {
+	if height == 1 {
+		for _, tx := range block.Transactions {
+			for _, co := range tx.CoinOutputs {
+				genesis = genesis.Add(co.Value)
+			}
+		}
+		return
 	}
-	b := mappingBucket.Get(rivbin.Marshal(name))
-	if len(b) != 0 {
-		return nil // already taken
+	var fees rivinetypes.Currency
+	for _, tx := range block.Transactions {
+		for _, fee := range tx.MinerFees {
+			fees = fees.Add(fee)
+		}
 	}
-	return mappingBucket.Put(rivbin.Marshal(name), rivbin.Marshal(id))
+	for _, mp := range block.MinerPayouts {
+		rewards = rewards.Add(mp.Value)
+	}
+	if rewards.Cmp(fees) <= 0 {
+		return genesis, rivinetypes.Currency{}
+	}
+	return genesis, rewards.Sub(fees)
 }
 
 // sortableTransactionShortID wraps around the rivinetypes.TransactionShortID,
@@ -1425,7 +3493,7 @@ func revertBotTransaction(tx *bolt.Tx, id types.BotID, shortTxID sortableTransac
 		return errors.New("corrupt transaction DB: implicit bot transactions bucket does not exist")
 	}
 	botBucket := txBucket.Bucket(rivbin.Marshal(id))
-	if botBucket != nil {
+	if botBucket == nil {
 		return fmt.Errorf("corrupt transaction DB: bot %d inner bucket does not exist", id)
 	}
 	return botBucket.Delete(rivbin.Marshal(shortTxID))
@@ -1452,6 +3520,215 @@ func getBotTransactions(tx *bolt.Tx, id types.BotID) ([]rivinetypes.TransactionI
 	return txIDs, nil
 }
 
+// apply/revert the registration index of a 3bot,
+// stored using the consensus block height, as the TransactionDB counts the genesis block as height 1
+
+func applyBotRegistration(tx *bolt.Tx, ctx transactionContext, id types.BotID) error {
+	registrationBucket := tx.Bucket(bucketBotRegistrations)
+	if registrationBucket == nil {
+		return errors.New("corrupt transaction DB: bot registrations bucket does not exist")
+	}
+	return registrationBucket.Put(encodeSortableBotID(id), rivbin.Marshal(ctx.BlockHeight-1))
+}
+func revertBotRegistration(tx *bolt.Tx, id types.BotID) error {
+	registrationBucket := tx.Bucket(bucketBotRegistrations)
+	if registrationBucket == nil {
+		return errors.New("corrupt transaction DB: bot registrations bucket does not exist")
+	}
+	return registrationBucket.Delete(encodeSortableBotID(id))
+}
+
+// encodeSortableBotID encodes a BotID in big endian,
+// such that boltdb can use it for natural ordering,
+// unlike the (little endian) rivbin encoding used for the other bot buckets.
+func encodeSortableBotID(id types.BotID) []byte {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(id))
+	return b[:]
+}
+func decodeSortableBotID(b []byte) types.BotID {
+	return types.BotID(binary.BigEndian.Uint32(b))
+}
+
+// apply/revert/get the versions of a 3bot record,
+// stored using the consensus block height, as the TransactionDB counts the genesis block as height 1
+
+func applyBotRecordVersion(tx *bolt.Tx, ctx transactionContext, txID rivinetypes.TransactionID, record types.BotRecord) error {
+	historyBucket := tx.Bucket(bucketBotRecordHistory)
+	if historyBucket == nil {
+		return errors.New("corrupt transaction DB: bot record history bucket does not exist")
+	}
+	botBucket, err := historyBucket.CreateBucketIfNotExists(rivbin.Marshal(record.ID))
+	if err != nil {
+		return fmt.Errorf("corrupt transaction DB: failed to create/get bot %d history inner bucket: %v", record.ID, err)
+	}
+	return botBucket.Put(rivbin.Marshal(ctx.TransactionShortID()), rivbin.Marshal(types.BotRecordVersion{
+		Record:        record,
+		TransactionID: txID,
+		BlockHeight:   ctx.BlockHeight - 1,
+		BlockTime:     ctx.BlockTime,
+	}))
+}
+func revertBotRecordVersion(tx *bolt.Tx, id types.BotID, shortTxID sortableTransactionShortID) error {
+	historyBucket := tx.Bucket(bucketBotRecordHistory)
+	if historyBucket == nil {
+		return errors.New("corrupt transaction DB: bot record history bucket does not exist")
+	}
+	botBucket := historyBucket.Bucket(rivbin.Marshal(id))
+	if botBucket == nil {
+		return fmt.Errorf("corrupt transaction DB: bot %d history inner bucket does not exist", id)
+	}
+	err := botBucket.Delete(rivbin.Marshal(shortTxID))
+	if err != nil {
+		return err
+	}
+	// delete the inner bucket once it is empty, such that a reverted bot remains unknown
+	if k, _ := botBucket.Cursor().First(); k == nil {
+		return historyBucket.DeleteBucket(rivbin.Marshal(id))
+	}
+	return nil
+}
+func getBotRecordVersions(tx *bolt.Tx, id types.BotID) ([]types.BotRecordVersion, error) {
+	historyBucket := tx.Bucket(bucketBotRecordHistory)
+	if historyBucket == nil {
+		return nil, errors.New("corrupt transaction DB: bot record history bucket does not exist")
+	}
+	botBucket := historyBucket.Bucket(rivbin.Marshal(id))
+	if botBucket == nil {
+		return nil, types.ErrBotNotFound
+	}
+	var versions []types.BotRecordVersion
+	err := botBucket.ForEach(func(_, v []byte) (err error) {
+		var version types.BotRecordVersion
+		err = rivbin.Unmarshal(v, &version)
+		versions = append(versions, version)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("corrupt transaction DB: error while parsing stored record version for bot %d: %v", id, err)
+	}
+	return versions, nil
+}
+func getBotRecordVersionAt(tx *bolt.Tx, id types.BotID, height rivinetypes.BlockHeight) (*types.BotRecordVersion, error) {
+	historyBucket := tx.Bucket(bucketBotRecordHistory)
+	if historyBucket == nil {
+		return nil, errors.New("corrupt transaction DB: bot record history bucket does not exist")
+	}
+	botBucket := historyBucket.Bucket(rivbin.Marshal(id))
+	if botBucket == nil {
+		return nil, types.ErrBotNotFound
+	}
+	// seek the first version defined after the block at the given height,
+	// such that the previous version is the one that was active at that height
+	cursor := botBucket.Cursor()
+	k, _ := cursor.Seek(rivbin.Marshal(newSortableTransactionShortID(height+2, 0)))
+	var b []byte
+	if k == nil {
+		_, b = cursor.Last()
+	} else {
+		_, b = cursor.Prev()
+	}
+	if len(b) == 0 {
+		return nil, types.ErrBotNotFound // bot did not exist yet at the given height
+	}
+	version := new(types.BotRecordVersion)
+	err := rivbin.Unmarshal(b, version)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt transaction DB: error while parsing stored record version for bot %d: %v", id, err)
+	}
+	return version, nil
+}
+
+// getBotRecordVersionBefore returns the version of the record
+// that precedes the version created by the transaction identified by the given short ID
+func getBotRecordVersionBefore(tx *bolt.Tx, id types.BotID, shortTxID sortableTransactionShortID) (*types.BotRecordVersion, error) {
+	historyBucket := tx.Bucket(bucketBotRecordHistory)
+	if historyBucket == nil {
+		return nil, errors.New("corrupt transaction DB: bot record history bucket does not exist")
+	}
+	botBucket := historyBucket.Bucket(rivbin.Marshal(id))
+	if botBucket == nil {
+		return nil, types.ErrBotNotFound
+	}
+	cursor := botBucket.Cursor()
+	k, _ := cursor.Seek(rivbin.Marshal(shortTxID))
+	var b []byte
+	if k == nil {
+		_, b = cursor.Last()
+	} else {
+		_, b = cursor.Prev()
+	}
+	if len(b) == 0 {
+		return nil, fmt.Errorf("corrupt transaction DB: no record version found for bot %d prior to short txID %d", id, shortTxID)
+	}
+	version := new(types.BotRecordVersion)
+	err := rivbin.Unmarshal(b, version)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt transaction DB: error while parsing stored record version for bot %d: %v", id, err)
+	}
+	return version, nil
+}
+
+// apply/revert/get the ownership history of a 3bot name,
+// stored using the consensus block height, as the TransactionDB counts the genesis block as height 1
+
+func applyBotNameOwnership(tx *bolt.Tx, ctx transactionContext, txID rivinetypes.TransactionID, name types.BotName, id types.BotID) error {
+	historyBucket := tx.Bucket(bucketBotNameHistory)
+	if historyBucket == nil {
+		return errors.New("corrupt transaction DB: bot name history bucket does not exist")
+	}
+	nameBucket, err := historyBucket.CreateBucketIfNotExists(rivbin.Marshal(name))
+	if err != nil {
+		return fmt.Errorf("corrupt transaction DB: failed to create/get name %v history inner bucket: %v", name, err)
+	}
+	return nameBucket.Put(rivbin.Marshal(ctx.TransactionShortID()), rivbin.Marshal(types.BotNameOwnership{
+		ID:            id,
+		TransactionID: txID,
+		BlockHeight:   ctx.BlockHeight - 1,
+		BlockTime:     ctx.BlockTime,
+	}))
+}
+func revertBotNameOwnership(tx *bolt.Tx, name types.BotName, shortTxID sortableTransactionShortID) error {
+	historyBucket := tx.Bucket(bucketBotNameHistory)
+	if historyBucket == nil {
+		return errors.New("corrupt transaction DB: bot name history bucket does not exist")
+	}
+	nameBucket := historyBucket.Bucket(rivbin.Marshal(name))
+	if nameBucket == nil {
+		return nil // nothing to revert, can happen for names released implicitly
+	}
+	err := nameBucket.Delete(rivbin.Marshal(shortTxID))
+	if err != nil {
+		return err
+	}
+	// delete the inner bucket once it is empty, such that a name that was never owned remains unknown
+	if k, _ := nameBucket.Cursor().First(); k == nil {
+		return historyBucket.DeleteBucket(rivbin.Marshal(name))
+	}
+	return nil
+}
+func getBotNameOwnershipHistory(tx *bolt.Tx, name types.BotName) ([]types.BotNameOwnership, error) {
+	historyBucket := tx.Bucket(bucketBotNameHistory)
+	if historyBucket == nil {
+		return nil, errors.New("corrupt transaction DB: bot name history bucket does not exist")
+	}
+	nameBucket := historyBucket.Bucket(rivbin.Marshal(name))
+	if nameBucket == nil {
+		return nil, types.ErrBotNameNotFound
+	}
+	var history []types.BotNameOwnership
+	err := nameBucket.ForEach(func(_, v []byte) (err error) {
+		var ownership types.BotNameOwnership
+		err = rivbin.Unmarshal(v, &ownership)
+		history = append(history, ownership)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("corrupt transaction DB: error while parsing stored ownership of name %v: %v", name, err)
+	}
+	return history, nil
+}
+
 func applyImplicitBotRecordUpdate(tx *bolt.Tx, txID rivinetypes.TransactionID, update implicitBotRecordUpdate) error {
 	updateBucket := tx.Bucket(bucketBotRecordImplicitUpdates)
 	if updateBucket == nil {
@@ -1708,3 +3985,315 @@ func getTfchainTransactionIDForERC20TransactionID(tx *bolt.Tx, id types.ERC20Has
 	}
 	return txid, true, nil
 }
+
+// apply/revert/get the capacity records of nodes, indexed by node and by farm
+
+func applyCapacityRecord(tx *bolt.Tx, shortTxID sortableTransactionShortID, record types.CapacityRecord) error {
+	nodesBucket := tx.Bucket(bucketCapacityNodes)
+	if nodesBucket == nil {
+		return errors.New("corrupt transaction DB: capacity nodes bucket does not exist")
+	}
+	farmsBucket := tx.Bucket(bucketCapacityFarms)
+	if farmsBucket == nil {
+		return errors.New("corrupt transaction DB: capacity farms bucket does not exist")
+	}
+	nodeKey := rivbin.Marshal(record.Node)
+	nodeBucket, err := nodesBucket.CreateBucketIfNotExists(nodeKey)
+	if err != nil {
+		return fmt.Errorf("corrupt transaction DB: failed to create/get node %v inner bucket: %v", record.Node, err)
+	}
+	farmBucket, err := farmsBucket.CreateBucketIfNotExists(rivbin.Marshal(record.Farm))
+	if err != nil {
+		return fmt.Errorf("corrupt transaction DB: failed to create/get farm %d inner bucket: %v", record.Farm, err)
+	}
+	key := rivbin.Marshal(shortTxID)
+	err = nodeBucket.Put(key, rivbin.Marshal(record))
+	if err != nil {
+		return fmt.Errorf("error while storing capacity record of node %v: %v", record.Node, err)
+	}
+	err = farmBucket.Put(key, nodeKey)
+	if err != nil {
+		return fmt.Errorf("error while storing node %v for farm %d: %v", record.Node, record.Farm, err)
+	}
+	return nil
+}
+func revertCapacityRecord(tx *bolt.Tx, shortTxID sortableTransactionShortID, node rivinetypes.PublicKey, farm types.FarmID) error {
+	nodesBucket := tx.Bucket(bucketCapacityNodes)
+	if nodesBucket == nil {
+		return errors.New("corrupt transaction DB: capacity nodes bucket does not exist")
+	}
+	farmsBucket := tx.Bucket(bucketCapacityFarms)
+	if farmsBucket == nil {
+		return errors.New("corrupt transaction DB: capacity farms bucket does not exist")
+	}
+	nodeKey, farmKey := rivbin.Marshal(node), rivbin.Marshal(farm)
+	nodeBucket := nodesBucket.Bucket(nodeKey)
+	if nodeBucket == nil {
+		return fmt.Errorf("corrupt transaction DB: node %v inner bucket does not exist", node)
+	}
+	farmBucket := farmsBucket.Bucket(farmKey)
+	if farmBucket == nil {
+		return fmt.Errorf("corrupt transaction DB: farm %d inner bucket does not exist", farm)
+	}
+	key := rivbin.Marshal(shortTxID)
+	err := nodeBucket.Delete(key)
+	if err != nil {
+		return fmt.Errorf("error while deleting capacity record of node %v: %v", node, err)
+	}
+	err = farmBucket.Delete(key)
+	if err != nil {
+		return fmt.Errorf("error while deleting node %v for farm %d: %v", node, farm, err)
+	}
+	// delete the inner buckets once they are empty, such that unknown nodes and farms remain unknown
+	if k, _ := nodeBucket.Cursor().First(); k == nil {
+		err = nodesBucket.DeleteBucket(nodeKey)
+		if err != nil {
+			return fmt.Errorf("error while deleting node %v inner bucket: %v", node, err)
+		}
+	}
+	if k, _ := farmBucket.Cursor().First(); k == nil {
+		err = farmsBucket.DeleteBucket(farmKey)
+		if err != nil {
+			return fmt.Errorf("error while deleting farm %d inner bucket: %v", farm, err)
+		}
+	}
+	return nil
+}
+func getLatestCapacityRecordForNode(tx *bolt.Tx, node rivinetypes.PublicKey) (*types.CapacityRecord, error) {
+	nodesBucket := tx.Bucket(bucketCapacityNodes)
+	if nodesBucket == nil {
+		return nil, errors.New("corrupt transaction DB: capacity nodes bucket does not exist")
+	}
+	nodeBucket := nodesBucket.Bucket(rivbin.Marshal(node))
+	if nodeBucket == nil {
+		return nil, types.ErrNodeNotFound
+	}
+	k, v := nodeBucket.Cursor().Last()
+	if k == nil {
+		return nil, types.ErrNodeNotFound
+	}
+	record := new(types.CapacityRecord)
+	err := rivbin.Unmarshal(v, record)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt transaction DB: error while parsing stored capacity record for node %v: %v", node, err)
+	}
+	return record, nil
+}
+func getCapacityRecordsForNode(tx *bolt.Tx, node rivinetypes.PublicKey) ([]types.CapacityRecord, error) {
+	nodesBucket := tx.Bucket(bucketCapacityNodes)
+	if nodesBucket == nil {
+		return nil, errors.New("corrupt transaction DB: capacity nodes bucket does not exist")
+	}
+	nodeBucket := nodesBucket.Bucket(rivbin.Marshal(node))
+	if nodeBucket == nil {
+		return nil, types.ErrNodeNotFound
+	}
+	var records []types.CapacityRecord
+	err := nodeBucket.ForEach(func(_, v []byte) (err error) {
+		var record types.CapacityRecord
+		err = rivbin.Unmarshal(v, &record)
+		records = append(records, record)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("corrupt transaction DB: error while parsing stored capacity record for node %v: %v", node, err)
+	}
+	return records, nil
+}
+func getNodesForFarm(tx *bolt.Tx, farm types.FarmID) ([]rivinetypes.PublicKey, error) {
+	farmsBucket := tx.Bucket(bucketCapacityFarms)
+	if farmsBucket == nil {
+		return nil, errors.New("corrupt transaction DB: capacity farms bucket does not exist")
+	}
+	farmBucket := farmsBucket.Bucket(rivbin.Marshal(farm))
+	if farmBucket == nil {
+		return nil, nil // no nodes is acceptable
+	}
+	var nodes []rivinetypes.PublicKey
+	visited := make(map[string]struct{})
+	err := farmBucket.ForEach(func(_, v []byte) error {
+		if _, ok := visited[string(v)]; ok {
+			return nil // node was already checked
+		}
+		visited[string(v)] = struct{}{}
+		var node rivinetypes.PublicKey
+		err := rivbin.Unmarshal(v, &node)
+		if err != nil {
+			return fmt.Errorf("corrupt transaction DB: error while parsing stored node for farm %d: %v", farm, err)
+		}
+		// only return the node if it is still linked to this farm
+		record, err := getLatestCapacityRecordForNode(tx, node)
+		if err != nil {
+			return err
+		}
+		if record.Farm == farm {
+			nodes = append(nodes, node)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// apply/revert/get the records of farms, keeping the full history per farm
+
+func applyFarmRecord(tx *bolt.Tx, shortTxID sortableTransactionShortID, record types.FarmRecord) error {
+	recordsBucket := tx.Bucket(bucketFarmRecords)
+	if recordsBucket == nil {
+		return errors.New("corrupt transaction DB: farm records bucket does not exist")
+	}
+	farmBucket, err := recordsBucket.CreateBucketIfNotExists(rivbin.Marshal(record.ID))
+	if err != nil {
+		return fmt.Errorf("corrupt transaction DB: failed to create/get farm %d inner bucket: %v", record.ID, err)
+	}
+	err = farmBucket.Put(rivbin.Marshal(shortTxID), rivbin.Marshal(record))
+	if err != nil {
+		return fmt.Errorf("error while storing record for farm %d: %v", record.ID, err)
+	}
+	return nil
+}
+func revertFarmRecord(tx *bolt.Tx, shortTxID sortableTransactionShortID, id types.FarmID) error {
+	recordsBucket := tx.Bucket(bucketFarmRecords)
+	if recordsBucket == nil {
+		return errors.New("corrupt transaction DB: farm records bucket does not exist")
+	}
+	farmBucket := recordsBucket.Bucket(rivbin.Marshal(id))
+	if farmBucket == nil {
+		return fmt.Errorf("corrupt transaction DB: farm %d inner bucket does not exist", id)
+	}
+	err := farmBucket.Delete(rivbin.Marshal(shortTxID))
+	if err != nil {
+		return fmt.Errorf("error while deleting record for farm %d: %v", id, err)
+	}
+	return nil
+}
+func getLatestFarmRecord(tx *bolt.Tx, id types.FarmID) (*types.FarmRecord, error) {
+	recordsBucket := tx.Bucket(bucketFarmRecords)
+	if recordsBucket == nil {
+		return nil, errors.New("corrupt transaction DB: farm records bucket does not exist")
+	}
+	farmBucket := recordsBucket.Bucket(rivbin.Marshal(id))
+	if farmBucket == nil {
+		return nil, types.ErrFarmNotFound
+	}
+	k, v := farmBucket.Cursor().Last()
+	if k == nil {
+		return nil, types.ErrFarmNotFound
+	}
+	record := new(types.FarmRecord)
+	err := rivbin.Unmarshal(v, record)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt transaction DB: error while parsing stored record for farm %d: %v", id, err)
+	}
+	return record, nil
+}
+
+// apply/revert the Name->ID mapping for a farm
+func applyFarmNameToIDMapping(tx *bolt.Tx, name string, id types.FarmID) error {
+	mappingBucket := tx.Bucket(bucketFarmNameToIDMapping)
+	if mappingBucket == nil {
+		return errors.New("corrupt transaction DB: farm name bucket does not exist")
+	}
+	err := mappingBucket.Put([]byte(name), rivbin.Marshal(id))
+	if err != nil {
+		return fmt.Errorf("error while storing farm name %q to farm id %d mapping: %v", name, id, err)
+	}
+	return nil
+}
+func revertFarmNameToIDMapping(tx *bolt.Tx, name string) error {
+	mappingBucket := tx.Bucket(bucketFarmNameToIDMapping)
+	if mappingBucket == nil {
+		return errors.New("corrupt transaction DB: farm name bucket does not exist")
+	}
+	err := mappingBucket.Delete([]byte(name))
+	if err != nil {
+		return fmt.Errorf("error while deleting farm name %q mapping: %v", name, err)
+	}
+	return nil
+}
+
+// apply/revert/get the capacity proof results of nodes
+
+func applyCapacityProofResult(tx *bolt.Tx, nodeKey []byte, result types.CapacityProofResult) error {
+	proofsBucket := tx.Bucket(bucketCapacityProofs)
+	if proofsBucket == nil {
+		return errors.New("corrupt transaction DB: capacity proofs bucket does not exist")
+	}
+	nodeBucket, err := proofsBucket.CreateBucketIfNotExists(nodeKey)
+	if err != nil {
+		return fmt.Errorf("corrupt transaction DB: failed to create/get node %x proofs inner bucket: %v", nodeKey, err)
+	}
+	err = nodeBucket.Put(internal.EncodeBlockheight(result.ChallengeHeight), rivbin.Marshal(result))
+	if err != nil {
+		return fmt.Errorf("error while storing capacity proof result of node %x for challenge at height %d: %v", nodeKey, result.ChallengeHeight, err)
+	}
+	return nil
+}
+func revertCapacityProofResult(tx *bolt.Tx, nodeKey []byte, challengeHeight rivinetypes.BlockHeight) error {
+	proofsBucket := tx.Bucket(bucketCapacityProofs)
+	if proofsBucket == nil {
+		return errors.New("corrupt transaction DB: capacity proofs bucket does not exist")
+	}
+	nodeBucket := proofsBucket.Bucket(nodeKey)
+	if nodeBucket == nil {
+		return fmt.Errorf("corrupt transaction DB: node %x proofs inner bucket does not exist", nodeKey)
+	}
+	err := nodeBucket.Delete(internal.EncodeBlockheight(challengeHeight))
+	if err != nil {
+		return fmt.Errorf("error while deleting capacity proof result of node %x for challenge at height %d: %v", nodeKey, challengeHeight, err)
+	}
+	// delete the inner bucket once it is empty, such that unknown nodes remain unknown
+	if k, _ := nodeBucket.Cursor().First(); k == nil {
+		err = proofsBucket.DeleteBucket(nodeKey)
+		if err != nil {
+			return fmt.Errorf("error while deleting node %x proofs inner bucket: %v", nodeKey, err)
+		}
+	}
+	return nil
+}
+func getCapacityProofResultForNode(tx *bolt.Tx, node rivinetypes.PublicKey, challengeHeight rivinetypes.BlockHeight) (*types.CapacityProofResult, error) {
+	return getCapacityProofResultForNodeKey(tx, rivbin.Marshal(node), challengeHeight)
+}
+func getCapacityProofResultForNodeKey(tx *bolt.Tx, nodeKey []byte, challengeHeight rivinetypes.BlockHeight) (*types.CapacityProofResult, error) {
+	proofsBucket := tx.Bucket(bucketCapacityProofs)
+	if proofsBucket == nil {
+		return nil, errors.New("corrupt transaction DB: capacity proofs bucket does not exist")
+	}
+	nodeBucket := proofsBucket.Bucket(nodeKey)
+	if nodeBucket == nil {
+		return nil, types.ErrCapacityProofResultNotFound
+	}
+	b := nodeBucket.Get(internal.EncodeBlockheight(challengeHeight))
+	if len(b) == 0 {
+		return nil, types.ErrCapacityProofResultNotFound
+	}
+	result := new(types.CapacityProofResult)
+	err := rivbin.Unmarshal(b, result)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt transaction DB: error while parsing stored capacity proof result of node %x: %v", nodeKey, err)
+	}
+	return result, nil
+}
+func getCapacityProofResultsForNode(tx *bolt.Tx, node rivinetypes.PublicKey) ([]types.CapacityProofResult, error) {
+	proofsBucket := tx.Bucket(bucketCapacityProofs)
+	if proofsBucket == nil {
+		return nil, errors.New("corrupt transaction DB: capacity proofs bucket does not exist")
+	}
+	nodeBucket := proofsBucket.Bucket(rivbin.Marshal(node))
+	if nodeBucket == nil {
+		return nil, nil // no results is acceptable
+	}
+	var results []types.CapacityProofResult
+	err := nodeBucket.ForEach(func(_, v []byte) (err error) {
+		var result types.CapacityProofResult
+		err = rivbin.Unmarshal(v, &result)
+		results = append(results, result)
+		return
+	})
+	if err != nil {
+		return nil, fmt.Errorf("corrupt transaction DB: error while parsing stored capacity proof result for node %v: %v", node, err)
+	}
+	return results, nil
+}
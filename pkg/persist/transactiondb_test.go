@@ -1,14 +1,19 @@
 package persist
 
 import (
+	"io/ioutil"
+	"os"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/modules"
 	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
 	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
 	rivinetypes "github.com/threefoldtech/rivine/types"
 
+	"github.com/threefoldfoundation/tfchain/pkg/config"
 	"github.com/threefoldfoundation/tfchain/pkg/types"
 )
 
@@ -93,6 +98,1276 @@ func TestImplicitBotRecordUpdate_RivineMarshaling(t *testing.T) {
 	}
 }
 
+func TestBotRecordAndNameHistory(t *testing.T) {
+	chain := newTestBotChain(t)
+	defer chain.close()
+
+	nameA, nameB := mustNewBotName(t, "aaaaa.bbbbb"), mustNewBotName(t, "ccccc.ddddd")
+	// register bot 1 with nameA and bot 2 without names (height 1)
+	chain.applyBlock(
+		(&types.BotRegistrationTransaction{
+			Names:          []types.BotName{nameA},
+			NrOfMonths:     1,
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+			Identification: types.PublicKeySignaturePair{PublicKey: newTestPublicKey(1)},
+		}).Transaction(chain.oneCoin),
+		(&types.BotRegistrationTransaction{
+			NrOfMonths:     1,
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+			Identification: types.PublicKeySignaturePair{PublicKey: newTestPublicKey(2)},
+		}).Transaction(chain.oneCoin),
+	)
+	// bot 1 swaps nameA for nameB (height 2)
+	chain.applyBlock((&types.BotRecordUpdateTransaction{
+		Identifier: 1,
+		Names: types.BotRecordNameUpdate{
+			Add:    []types.BotName{nameB},
+			Remove: []types.BotName{nameA},
+		},
+		TransactionFee: chain.txFee,
+		CoinInputs:     chain.coinInputs,
+	}).Transaction(chain.oneCoin))
+	// bot 1 transfers nameB to bot 2 (height 3)
+	chain.applyBlock((&types.BotNameTransferTransaction{
+		Sender:         types.BotIdentifierSignaturePair{Identifier: 1},
+		Receiver:       types.BotIdentifierSignaturePair{Identifier: 2},
+		Names:          []types.BotName{nameB},
+		TransactionFee: chain.txFee,
+		CoinInputs:     chain.coinInputs,
+	}).Transaction(chain.oneCoin))
+
+	versions, err := chain.txdb.GetBotRecordHistory(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 3 {
+		t.Fatal("unexpected amount of versions for bot 1:", versions)
+	}
+	for idx, version := range versions {
+		if version.BlockHeight != rivinetypes.BlockHeight(idx+1) || version.BlockTime != chain.blocks[idx+1].Timestamp {
+			t.Error(idx, "unexpected block height and time:", version.BlockHeight, version.BlockTime)
+		}
+		if version.TransactionID != chain.blocks[idx+1].Transactions[0].ID() {
+			t.Error(idx, "unexpected transaction ID:", version.TransactionID)
+		}
+	}
+
+	expectedNames := map[rivinetypes.BlockHeight][]types.BotName{
+		1:   {nameA},
+		2:   {nameB},
+		3:   nil,
+		100: nil,
+	}
+	for height, names := range expectedNames {
+		record, err := chain.txdb.GetRecordForIDAt(1, height)
+		if err != nil {
+			t.Error(height, err)
+			continue
+		}
+		recordNames := record.Names.Difference(types.BotNameSortedSet{})
+		if len(recordNames) != len(names) {
+			t.Error(height, "unexpected names:", recordNames, "!=", names)
+			continue
+		}
+		for idx, name := range names {
+			if recordNames[idx].String() != name.String() {
+				t.Error(height, idx, "unexpected name:", recordNames[idx], "!=", name)
+			}
+		}
+	}
+	_, err = chain.txdb.GetRecordForIDAt(1, 0)
+	if err != types.ErrBotNotFound {
+		t.Error("unexpected error for record of bot 1 prior to its registration:", err)
+	}
+
+	expectedOwners := map[string][]types.BotID{
+		nameA.String(): {1, 0},
+		nameB.String(): {1, 2},
+	}
+	for name, owners := range expectedOwners {
+		history, err := chain.txdb.GetBotNameHistory(mustNewBotName(t, name))
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		if len(history) != len(owners) {
+			t.Fatal(name, "unexpected name history:", history)
+		}
+		for idx, owner := range owners {
+			if history[idx].ID != owner {
+				t.Error(name, idx, "unexpected owner:", history[idx].ID, "!=", owner)
+			}
+		}
+	}
+
+	// reverting the update and transfer, should only leave the initial history
+	chain.revertBlock()
+	chain.revertBlock()
+	versions, err = chain.txdb.GetBotRecordHistory(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 {
+		t.Fatal("unexpected amount of versions for bot 1 after revert:", versions)
+	}
+	versions, err = chain.txdb.GetBotRecordHistory(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 {
+		t.Fatal("unexpected amount of versions for bot 2 after revert:", versions)
+	}
+	history, err := chain.txdb.GetBotNameHistory(nameA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].ID != 1 {
+		t.Fatal("unexpected history for name A after revert:", history)
+	}
+	_, err = chain.txdb.GetBotNameHistory(nameB)
+	if err != types.ErrBotNameNotFound {
+		t.Fatal("unexpected error for history of name B after revert:", err)
+	}
+}
+
+func newTestPublicKey(seed byte) rivinetypes.PublicKey {
+	_, pk := crypto.GenerateKeyPairDeterministic([crypto.EntropySize]byte{seed})
+	return rivinetypes.Ed25519PublicKey(pk)
+}
+
+func TestBotNetworkAddressMapping(t *testing.T) {
+	chain := newTestBotChain(t)
+	defer chain.close()
+
+	addrA, addrB := mustNewNetworkAddress(t, "example.org"), mustNewNetworkAddress(t, "127.0.0.1")
+	// register bot 1 with addrA and bot 2 with both addresses (height 1)
+	chain.applyBlock(
+		(&types.BotRegistrationTransaction{
+			Addresses:      []types.NetworkAddress{addrA},
+			NrOfMonths:     1,
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+			Identification: types.PublicKeySignaturePair{PublicKey: newTestPublicKey(1)},
+		}).Transaction(chain.oneCoin),
+		(&types.BotRegistrationTransaction{
+			Addresses:      []types.NetworkAddress{addrA, addrB},
+			NrOfMonths:     1,
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+			Identification: types.PublicKeySignaturePair{PublicKey: newTestPublicKey(2)},
+		}).Transaction(chain.oneCoin),
+	)
+	// bot 2 swaps addrA for nothing, while bot 1 adds addrB (height 2)
+	chain.applyBlock(
+		(&types.BotRecordUpdateTransaction{
+			Identifier:     2,
+			Addresses:      types.BotRecordAddressUpdate{Remove: []types.NetworkAddress{addrA}},
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+		}).Transaction(chain.oneCoin),
+		(&types.BotRecordUpdateTransaction{
+			Identifier:     1,
+			Addresses:      types.BotRecordAddressUpdate{Add: []types.NetworkAddress{addrB}},
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+		}).Transaction(chain.oneCoin),
+	)
+
+	checkIDs := func(addr types.NetworkAddress, expected ...types.BotID) {
+		t.Helper()
+		records, err := chain.txdb.GetRecordsForNetworkAddress(addr)
+		if err != nil {
+			t.Fatal(addr, err)
+		}
+		if len(records) != len(expected) {
+			t.Fatal(addr, "unexpected records:", records)
+		}
+		for idx, id := range expected {
+			if records[idx].ID != id {
+				t.Error(addr, idx, "unexpected bot:", records[idx].ID, "!=", id)
+			}
+		}
+	}
+	checkIDs(addrA, 1)
+	checkIDs(addrB, 1, 2)
+
+	// reverting the updates should restore the original mappings
+	chain.revertBlock()
+	checkIDs(addrA, 1, 2)
+	checkIDs(addrB, 2)
+
+	// reverting the registrations should remove all mappings
+	chain.revertBlock()
+	checkIDs(addrA)
+	checkIDs(addrB)
+}
+
+func TestBotKeyRotation(t *testing.T) {
+	chain := newTestBotChain(t)
+	defer chain.close()
+
+	keys := []rivinetypes.PublicKey{newTestPublicKey(1), newTestPublicKey(2), newTestPublicKey(3)}
+	// register bot 1 (height 1)
+	chain.applyBlock(
+		(&types.BotRegistrationTransaction{
+			Names:          []types.BotName{mustNewBotName(t, "aaaaa.bbbbb")},
+			NrOfMonths:     1,
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+			Identification: types.PublicKeySignaturePair{PublicKey: keys[0]},
+		}).Transaction(chain.oneCoin),
+	)
+	// rotate its key twice (height 2 and 3)
+	for _, key := range keys[1:] {
+		chain.applyBlock(
+			(&types.BotKeyRotationTransaction{
+				Identifier:        1,
+				NewIdentification: types.PublicKeySignaturePair{PublicKey: key},
+				TransactionFee:    chain.txFee,
+				CoinInputs:        chain.coinInputs,
+			}).Transaction(),
+		)
+	}
+
+	checkKey := func(active int) {
+		t.Helper()
+		record, err := chain.txdb.GetRecordForID(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if record.PublicKey.String() != keys[active].String() {
+			t.Fatal("unexpected public key:", record.PublicKey.String(), "!=", keys[active].String())
+		}
+		for idx, key := range keys {
+			record, err := chain.txdb.GetRecordForKey(key)
+			if idx == active {
+				if err != nil {
+					t.Fatal(idx, err)
+				}
+				if record.ID != 1 {
+					t.Fatal(idx, "unexpected bot:", record.ID)
+				}
+			} else if err != types.ErrBotKeyNotFound {
+				t.Fatal(idx, "unexpected error for inactive key:", err)
+			}
+		}
+	}
+	checkKey(2)
+
+	// the names should still be linked to the bot
+	record, err := chain.txdb.GetRecordForName(mustNewBotName(t, "aaaaa.bbbbb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.ID != 1 {
+		t.Fatal("unexpected bot:", record.ID)
+	}
+
+	// reverting the rotations should restore the previous keys, one by one
+	chain.revertBlock()
+	checkKey(1)
+	chain.revertBlock()
+	checkKey(0)
+}
+
+func TestBotOwnerCondition(t *testing.T) {
+	chain := newTestBotChain(t)
+	defer chain.close()
+
+	keys := []rivinetypes.PublicKey{newTestPublicKey(1), newTestPublicKey(2), newTestPublicKey(3)}
+	owner := rivinetypes.NewCondition(rivinetypes.NewMultiSignatureCondition(rivinetypes.UnlockHashSlice{
+		rivinetypes.NewPubKeyUnlockHash(keys[0]),
+		rivinetypes.NewPubKeyUnlockHash(keys[1]),
+		rivinetypes.NewPubKeyUnlockHash(keys[2]),
+	}, 2))
+	// register bot 1 (height 1)
+	chain.applyBlock(
+		(&types.BotRegistrationTransaction{
+			Names:          []types.BotName{mustNewBotName(t, "aaaaa.bbbbb")},
+			NrOfMonths:     1,
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+			Identification: types.PublicKeySignaturePair{PublicKey: keys[0]},
+		}).Transaction(chain.oneCoin),
+	)
+	// hand over the ownership to a multisig condition (height 2)
+	chain.applyBlock(
+		(&types.BotKeyRotationTransaction{
+			Identifier:        1,
+			NewIdentification: types.PublicKeySignaturePair{PublicKey: keys[1]},
+			Owner:             &owner,
+			TransactionFee:    chain.txFee,
+			CoinInputs:        chain.coinInputs,
+		}).Transaction(),
+	)
+	// update the bot using a fulfillment of that condition (height 3)
+	chain.applyBlock(
+		(&types.BotRecordUpdateWithFulfillmentTransaction{
+			Identifier:     1,
+			Addresses:      types.BotRecordAddressUpdate{Add: []types.NetworkAddress{mustNewNetworkAddress(t, "example.org")}},
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+		}).Transaction(chain.oneCoin),
+	)
+
+	checkRecord := func(expectedOwner *rivinetypes.UnlockConditionProxy, expectedAddresses int) {
+		t.Helper()
+		record, err := chain.txdb.GetRecordForID(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expectedOwner == nil {
+			if record.Owner != nil {
+				t.Fatal("unexpected owner:", record.Owner)
+			}
+		} else if record.Owner == nil || !record.Owner.Equal(expectedOwner.Condition) {
+			t.Fatal("unexpected owner:", record.Owner)
+		}
+		if n := record.Addresses.Len(); n != expectedAddresses {
+			t.Fatal("unexpected amount of addresses:", n, "!=", expectedAddresses)
+		}
+	}
+	checkRecord(&owner, 1)
+
+	// reverting the update should keep the owner, but remove the address
+	chain.revertBlock()
+	checkRecord(&owner, 0)
+	// reverting the rotation should restore the ownership of the public key
+	chain.revertBlock()
+	checkRecord(nil, 0)
+}
+
+func TestBotRecordServices(t *testing.T) {
+	chain := newTestBotChain(t)
+	defer chain.close()
+
+	keys := []rivinetypes.PublicKey{newTestPublicKey(1), newTestPublicKey(2)}
+	owner := rivinetypes.NewCondition(rivinetypes.NewUnlockHashCondition(rivinetypes.NewPubKeyUnlockHash(keys[1])))
+	services := []types.BotService{
+		{Name: "http", Protocol: types.BotServiceProtocolTCP, Port: 80, Version: "1.1"},
+		{Name: "dns", Protocol: types.BotServiceProtocolUDP, Port: 53},
+	}
+	// register bot 1 (height 1)
+	chain.applyBlock(
+		(&types.BotRegistrationTransaction{
+			Names:          []types.BotName{mustNewBotName(t, "aaaaa.bbbbb")},
+			NrOfMonths:     1,
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+			Identification: types.PublicKeySignaturePair{PublicKey: keys[0]},
+		}).Transaction(chain.oneCoin),
+	)
+	// hand over the ownership to an unlock hash condition (height 2),
+	// such that both the owner and services are stored as part of the record
+	chain.applyBlock(
+		(&types.BotKeyRotationTransaction{
+			Identifier:        1,
+			NewIdentification: types.PublicKeySignaturePair{PublicKey: keys[0]},
+			Owner:             &owner,
+			TransactionFee:    chain.txFee,
+			CoinInputs:        chain.coinInputs,
+		}).Transaction(),
+	)
+	// add two services (height 3)
+	chain.applyBlock(
+		(&types.BotRecordUpdateWithServicesTransaction{
+			Identifier:     1,
+			Services:       types.BotRecordServiceUpdate{Add: services},
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+		}).Transaction(chain.oneCoin),
+	)
+	// remove one of them again (height 4)
+	chain.applyBlock(
+		(&types.BotRecordUpdateWithServicesTransaction{
+			Identifier:     1,
+			Services:       types.BotRecordServiceUpdate{Remove: services[1:]},
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+		}).Transaction(chain.oneCoin),
+	)
+
+	checkRecord := func(expectedServices ...types.BotService) {
+		t.Helper()
+		record, err := chain.txdb.GetRecordForID(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if record.Owner == nil || !record.Owner.Equal(owner.Condition) {
+			t.Fatal("unexpected owner:", record.Owner)
+		}
+		recordServices := record.Services.Services()
+		if len(recordServices) != len(expectedServices) {
+			t.Fatal("unexpected services:", recordServices, "!=", expectedServices)
+		}
+		for _, expected := range expectedServices {
+			found := false
+			for _, service := range recordServices {
+				if service.Equals(expected) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Fatal("service", expected.String(), "not found in record services:", recordServices)
+			}
+		}
+	}
+	checkRecord(services[0])
+
+	// reverting the removal should restore the removed service
+	chain.revertBlock()
+	checkRecord(services...)
+	// reverting the addition should remove all services
+	chain.revertBlock()
+	checkRecord()
+}
+
+func TestBotNameGracePeriod(t *testing.T) {
+	chain := newTestBotChain(t)
+	defer chain.close()
+
+	name := mustNewBotName(t, "aaaaa")
+	// register bot 1 (height 1)
+	chain.applyBlock(
+		(&types.BotRegistrationTransaction{
+			Names:          []types.BotName{name},
+			NrOfMonths:     1,
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+			Identification: types.PublicKeySignaturePair{PublicKey: newTestPublicKey(1)},
+		}).Transaction(chain.oneCoin),
+	)
+	record, err := chain.txdb.GetRecordForID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiration := record.Expiration
+
+	checkName := func(expectedErr error, expectedExpiration types.CompactTimestamp) {
+		t.Helper()
+		_, err := chain.txdb.GetRecordForName(name)
+		if err != expectedErr {
+			t.Fatal("unexpected error while looking up name:", err, "!=", expectedErr)
+		}
+		record, err := chain.txdb.GetRecordForID(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if record.Expiration != expectedExpiration {
+			t.Fatal("unexpected expiration:", record.Expiration, "!=", expectedExpiration)
+		}
+	}
+	renew := func() rivinetypes.Transaction {
+		return (&types.BotRecordUpdateTransaction{
+			Identifier:     1,
+			NrOfMonths:     1,
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+		}).Transaction(chain.oneCoin)
+	}
+
+	// once expired, the name is reserved during the grace period (height 2)
+	chain.timeShift = types.BotMonth
+	chain.applyBlock()
+	checkName(types.ErrBotNameInGracePeriod, expiration)
+	if status, err := chain.txdb.GetBotRecordStatus(record); err != nil || status != types.BotRecordStatusGrace {
+		t.Fatal("unexpected status:", status, err)
+	}
+
+	// renewing the bot within that period keeps its names,
+	// extending the expiration starting from the time it expired (height 3)
+	chain.applyBlock(renew())
+	checkName(nil, expiration+types.BotMonth)
+	chain.revertBlock()
+	checkName(types.ErrBotNameInGracePeriod, expiration)
+
+	// once the grace period has passed, the name is released (height 3)
+	chain.timeShift = types.BotMonth + types.BotNameGracePeriod
+	chain.applyBlock()
+	checkName(types.ErrBotNameExpired, expiration)
+
+	// renewing the bot now, no longer keeps its names (height 4)
+	chain.applyBlock(renew())
+	record, err = chain.txdb.GetRecordForID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Names.Len() != 0 {
+		t.Fatal("unexpected names of bot renewed after its grace period:", record.Names)
+	}
+	_, err = chain.txdb.GetRecordForName(name)
+	if err != types.ErrBotNameNotFound {
+		t.Fatal("unexpected error while looking up released name:", err)
+	}
+	// unless the renewal is reverted
+	chain.revertBlock()
+	checkName(types.ErrBotNameExpired, expiration)
+}
+
+func TestBotNameClaimPriorToGracePeriodActivation(t *testing.T) {
+	params := types.GetDevnetGenesisConsensusParameters()
+	params.BotGracePeriodActivationHeight = 10
+	chain := newTestBotChainWithParameters(t, params)
+	defer chain.close()
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionBotRecordUpdate, types.BotUpdateRecordTransactionController{
+		Registry:                 chain.txdb,
+		OneCoin:                  chain.oneCoin,
+		ConsensusParameterGetter: chain.txdb,
+	})
+
+	name := mustNewBotName(t, "aaaaa")
+	// register bot 1 with the name, and bot 2 without it (height 1)
+	chain.applyBlock(
+		(&types.BotRegistrationTransaction{
+			Names:          []types.BotName{name},
+			NrOfMonths:     1,
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+			Identification: types.PublicKeySignaturePair{PublicKey: newTestPublicKey(1)},
+		}).Transaction(chain.oneCoin),
+		(&types.BotRegistrationTransaction{
+			Addresses:      []types.NetworkAddress{mustNewNetworkAddress(t, "example.org")},
+			NrOfMonths:     2,
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+			Identification: types.PublicKeySignaturePair{PublicKey: newTestPublicKey(2)},
+		}).Transaction(chain.oneCoin),
+	)
+
+	// prior to the activation of the grace period,
+	// the name is released as soon as bot 1 expires (height 2)
+	chain.timeShift = types.BotMonth
+	chain.applyBlock()
+	_, err := chain.txdb.GetRecordForName(name)
+	if err != types.ErrBotNameExpired {
+		t.Fatal("unexpected error while looking up name of expired bot:", err)
+	}
+	record, err := chain.txdb.GetRecordForID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status, err := chain.txdb.GetBotRecordStatus(record); err != nil || status != types.BotRecordStatusReleased {
+		t.Fatal("unexpected status:", status, err)
+	}
+
+	// such that bot 2 can claim it right after the expiration of bot 1 (height 3)
+	sk, _ := crypto.GenerateKeyPairDeterministic([crypto.EntropySize]byte{2})
+	claim := (&types.BotRecordUpdateTransaction{
+		Identifier:     2,
+		Names:          types.BotRecordNameUpdate{Add: []types.BotName{name}},
+		TransactionFee: chain.txFee,
+		CoinInputs:     chain.coinInputs,
+	}).Transaction(chain.oneCoin)
+	err = claim.SignExtension(func(fulfillment *rivinetypes.UnlockFulfillmentProxy, condition rivinetypes.UnlockConditionProxy, extraObjects ...interface{}) error {
+		return fulfillment.Sign(rivinetypes.FulfillmentSignContext{
+			ExtraObjects: extraObjects,
+			Transaction:  claim,
+			Key:          sk[:],
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = claim.ValidateTransaction(rivinetypes.ValidationContext{
+		Confirmed:   true,
+		BlockHeight: rivinetypes.BlockHeight(len(chain.blocks)),
+		BlockTime:   chain.txdb.GetChainTime() + 120,
+	}, rivinetypes.TransactionValidationConstants{
+		BlockSizeLimit:         config.GetDevnetGenesis().BlockSizeLimit,
+		ArbitraryDataSizeLimit: config.GetDevnetGenesis().ArbitraryDataSizeLimit,
+		MinimumMinerFee:        chain.txFee,
+	})
+	if err != nil {
+		t.Fatal("failed to validate claim of released name prior to the activation of the grace period:", err)
+	}
+	chain.applyBlock(claim)
+	record, err = chain.txdb.GetRecordForName(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.ID != 2 {
+		t.Fatal("unexpected owner of claimed name:", record.ID)
+	}
+}
+
+func TestBotRecordListing(t *testing.T) {
+	chain := newTestBotChain(t)
+	defer chain.close()
+
+	register := func(seed byte, months uint8, names ...types.BotName) rivinetypes.Transaction {
+		return (&types.BotRegistrationTransaction{
+			Names:          names,
+			NrOfMonths:     months,
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+			Identification: types.PublicKeySignaturePair{PublicKey: newTestPublicKey(seed)},
+		}).Transaction(chain.oneCoin)
+	}
+	// register bots 1 and 2 (height 1), bot 3 (height 2) and bot 4 (height 3)
+	chain.applyBlock(
+		register(1, 1, mustNewBotName(t, "aaaaa")),
+		register(2, 3),
+	)
+	chain.applyBlock(register(3, 2, mustNewBotName(t, "bbbbb")))
+	chain.applyBlock(register(4, 1))
+
+	checkListing := func(cursor types.BotID, limit int, filter BotRecordFilter, expectedIDs []types.BotID, expectedNext types.BotID) {
+		t.Helper()
+		listings, next, err := chain.txdb.GetRecords(cursor, limit, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(listings) != len(expectedIDs) {
+			t.Fatal("unexpected amount of listed bots:", len(listings), "!=", len(expectedIDs))
+		}
+		for idx, listing := range listings {
+			if listing.Record.ID != expectedIDs[idx] {
+				t.Error(idx, "unexpected listed bot:", listing.Record.ID, "!=", expectedIDs[idx])
+			}
+		}
+		if next != expectedNext {
+			t.Fatal("unexpected cursor:", next, "!=", expectedNext)
+		}
+	}
+
+	// all bots are listed, ordered by ID
+	checkListing(0, 10, BotRecordFilter{}, []types.BotID{1, 2, 3, 4}, 0)
+	listings, _, err := chain.txdb.GetRecords(0, 10, BotRecordFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for idx, expectedHeight := range []rivinetypes.BlockHeight{1, 1, 2, 3} {
+		if height := listings[idx].RegistrationHeight; height != expectedHeight {
+			t.Error(idx, "unexpected registration height:", height, "!=", expectedHeight)
+		}
+	}
+
+	// pages are continued using the returned cursor
+	checkListing(0, 2, BotRecordFilter{}, []types.BotID{1, 2}, 2)
+	checkListing(2, 2, BotRecordFilter{}, []types.BotID{3, 4}, 0)
+	checkListing(4, 2, BotRecordFilter{}, nil, 0)
+
+	// bots can be filtered by name, registration height and expiration date
+	checkListing(0, 10, BotRecordFilter{HasName: true}, []types.BotID{1, 3}, 0)
+	checkListing(0, 1, BotRecordFilter{HasName: true}, []types.BotID{1}, 1)
+	checkListing(0, 10, BotRecordFilter{RegisteredSince: 2}, []types.BotID{3, 4}, 0)
+	checkListing(0, 10, BotRecordFilter{RegisteredBefore: 2}, []types.BotID{1, 2}, 0)
+	checkListing(0, 10, BotRecordFilter{RegisteredSince: 2, RegisteredBefore: 3}, []types.BotID{3}, 0)
+	checkListing(0, 10, BotRecordFilter{
+		ExpiresAfter: listings[2].Record.Expiration.SiaTimestamp(),
+	}, []types.BotID{2, 3}, 0)
+	checkListing(0, 10, BotRecordFilter{
+		ExpiresAfter:  listings[2].Record.Expiration.SiaTimestamp(),
+		ExpiresBefore: listings[2].Record.Expiration.SiaTimestamp(),
+	}, []types.BotID{3}, 0)
+
+	// reverted bots are no longer listed
+	chain.revertBlock()
+	checkListing(0, 10, BotRecordFilter{}, []types.BotID{1, 2, 3}, 0)
+
+	// the amount of bots to list has to be positive
+	_, _, err = chain.txdb.GetRecords(0, 0, BotRecordFilter{})
+	if err == nil {
+		t.Fatal("succeeded to list zero bots")
+	}
+}
+
+func TestBotNameSale(t *testing.T) {
+	chain := newTestBotChain(t)
+	defer chain.close()
+
+	name := mustNewBotName(t, "aaaaa.bbbbb")
+	// register bot 1 with a name and bot 2 without names (height 1)
+	chain.applyBlock(
+		(&types.BotRegistrationTransaction{
+			Names:          []types.BotName{name},
+			NrOfMonths:     1,
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+			Identification: types.PublicKeySignaturePair{PublicKey: newTestPublicKey(1)},
+		}).Transaction(chain.oneCoin),
+		(&types.BotRegistrationTransaction{
+			Addresses:      []types.NetworkAddress{mustNewNetworkAddress(t, "example.org")},
+			NrOfMonths:     1,
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+			Identification: types.PublicKeySignaturePair{PublicKey: newTestPublicKey(2)},
+		}).Transaction(chain.oneCoin),
+	)
+	// bot 2 buys the name of bot 1 (height 2)
+	chain.applyBlock((&types.BotNameSaleTransaction{
+		Offer: types.BotNameSaleOffer{
+			Sender:           types.BotIdentifierFulfillmentPair{Identifier: 1},
+			Names:            []types.BotName{name},
+			Price:            chain.oneCoin.Mul64(100),
+			PayoutAddress:    rivinetypes.NewPubKeyUnlockHash(newTestPublicKey(1)),
+			ExpirationHeight: 10,
+		},
+		Receiver:       types.BotIdentifierFulfillmentPair{Identifier: 2},
+		TransactionFee: chain.txFee,
+		CoinInputs:     chain.coinInputs,
+	}).Transaction(chain.oneCoin))
+
+	checkOwner := func(expectedID types.BotID) {
+		t.Helper()
+		record, err := chain.txdb.GetRecordForName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if record.ID != expectedID {
+			t.Fatal("unexpected owner of sold name:", record.ID, "!=", expectedID)
+		}
+	}
+	checkOwner(2)
+
+	// reverting the sale should give the name back to bot 1
+	chain.revertBlock()
+	checkOwner(1)
+}
+
+func TestBotNameDelegation(t *testing.T) {
+	chain := newTestBotChain(t)
+	defer chain.close()
+
+	parent := mustNewBotName(t, "aaaaa")
+	child := mustNewBotName(t, "bbbbb.aaaaa")
+	// register bot 1 with the parent name and bot 2 without names (height 1)
+	chain.applyBlock(
+		(&types.BotRegistrationTransaction{
+			Names:          []types.BotName{parent},
+			NrOfMonths:     1,
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+			Identification: types.PublicKeySignaturePair{PublicKey: newTestPublicKey(1)},
+		}).Transaction(chain.oneCoin),
+		(&types.BotRegistrationTransaction{
+			Addresses:      []types.NetworkAddress{mustNewNetworkAddress(t, "example.org")},
+			NrOfMonths:     1,
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+			Identification: types.PublicKeySignaturePair{PublicKey: newTestPublicKey(2)},
+		}).Transaction(chain.oneCoin),
+	)
+	// bot 1 delegates the parent name to bot 2 (height 2)
+	chain.applyBlock((&types.BotNameDelegationTransaction{
+		Owner:          types.BotIdentifierFulfillmentPair{Identifier: 1},
+		Name:           parent,
+		Delegates:      types.BotNameDelegatesUpdate{Add: []types.BotID{2}},
+		TransactionFee: chain.txFee,
+		CoinInputs:     chain.coinInputs,
+	}).Transaction())
+	// bot 2 registers a sub name of the parent name (height 3)
+	chain.applyBlock((&types.BotRecordUpdateTransaction{
+		Identifier: 2,
+		Names: types.BotRecordNameUpdate{
+			Add: []types.BotName{child},
+		},
+		TransactionFee: chain.txFee,
+		CoinInputs:     chain.coinInputs,
+	}).Transaction(chain.oneCoin))
+
+	checkDelegates := func(expected ...types.BotID) {
+		t.Helper()
+		delegates, err := chain.txdb.GetDelegatesForName(parent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(delegates) != len(expected) || (len(expected) > 0 && !reflect.DeepEqual(delegates, expected)) {
+			t.Fatal("unexpected delegates:", delegates, "!=", expected)
+		}
+	}
+	checkChildren := func(expected ...types.BotName) {
+		t.Helper()
+		children, err := chain.txdb.GetChildrenForName(parent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(children) != len(expected) || (len(expected) > 0 && !reflect.DeepEqual(children, expected)) {
+			t.Fatal("unexpected children:", children, "!=", expected)
+		}
+	}
+	checkDelegates(2)
+	checkChildren(child)
+
+	// reverting the sub name registration removes the child
+	chain.revertBlock()
+	checkDelegates(2)
+	checkChildren()
+
+	// reverting the delegation removes the delegate
+	chain.revertBlock()
+	checkDelegates()
+}
+
+// testBotChain applies and reverts blocks directly to a TransactionDB,
+// without validating the 3bot transactions they contain
+type testBotChain struct {
+	t          *testing.T
+	dir        string
+	txdb       *TransactionDB
+	blocks     []rivinetypes.Block
+	oneCoin    rivinetypes.Currency
+	txFee      rivinetypes.Currency
+	coinInputs []rivinetypes.CoinInput
+	// timeShift is added to the timestamp of all blocks applied from now on
+	timeShift rivinetypes.Timestamp
+}
+
+func TestCoinCreationHistoryAndSupply(t *testing.T) {
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionCoinCreation, types.CoinCreationTransactionController{})
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionMinterDefinition, types.MinterDefinitionTransactionController{})
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionERC20Conversion, types.ERC20ConvertTransactionController{})
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionERC20CoinCreation, types.ERC20CoinCreationTransactionController{})
+	defer func() {
+		rivinetypes.RegisterTransactionVersion(types.TransactionVersionCoinCreation, nil)
+		rivinetypes.RegisterTransactionVersion(types.TransactionVersionMinterDefinition, nil)
+		rivinetypes.RegisterTransactionVersion(types.TransactionVersionERC20Conversion, nil)
+		rivinetypes.RegisterTransactionVersion(types.TransactionVersionERC20CoinCreation, nil)
+	}()
+
+	chain := newTestBotChain(t)
+	defer chain.close()
+
+	mintFulfillment := rivinetypes.NewFulfillment(rivinetypes.NewSingleSignatureFulfillment(newTestPublicKey(1)))
+	mint := func(nonce byte, coins uint64, reason string) rivinetypes.Transaction {
+		return (&types.CoinCreationTransaction{
+			Nonce:           types.TransactionNonce{nonce},
+			MintFulfillment: mintFulfillment,
+			CoinOutputs:     []rivinetypes.CoinOutput{{Value: chain.oneCoin.Mul64(coins)}},
+			MinerFees:       []rivinetypes.Currency{chain.txFee},
+			ArbitraryData:   []byte(reason),
+		}).Transaction()
+	}
+	newMinter := rivinetypes.NewUnlockHash(rivinetypes.UnlockTypePubKey, crypto.HashObject(newTestPublicKey(2)))
+
+	// mint 100 TFT (height 1), change the minter and exchange 50 TFT from ERC20 (height 2),
+	// mint 10 TFT as the new minter and exchange 20 TFT to ERC20 (height 3)
+	chain.applyBlock(mint(1, 100, "initial minting"))
+	chain.applyBlock(
+		(&types.MinterDefinitionTransaction{
+			Nonce:           types.TransactionNonce{2},
+			MintFulfillment: mintFulfillment,
+			MintCondition:   rivinetypes.NewCondition(rivinetypes.NewUnlockHashCondition(newMinter)),
+			MinerFees:       []rivinetypes.Currency{chain.txFee},
+		}).Transaction(),
+		(&types.ERC20CoinCreationTransaction{
+			Value:          chain.oneCoin.Mul64(50),
+			TransactionFee: chain.txFee,
+			TransactionID:  types.ERC20Hash{1},
+		}).Transaction(),
+	)
+	chain.applyBlock(
+		mint(3, 10, "second minting"),
+		(&types.ERC20ConvertTransaction{
+			Value:          chain.oneCoin.Mul64(20),
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+		}).Transaction(),
+	)
+	// a block paying out 1 TFT of block rewards on top of the fee of its transaction
+	rewardBlock := rivinetypes.Block{
+		Timestamp:    rivinetypes.Timestamp(1550000000 + len(chain.blocks)*120),
+		MinerPayouts: []rivinetypes.MinerPayout{{Value: chain.oneCoin.Add(chain.txFee)}},
+		Transactions: []rivinetypes.Transaction{{
+			Version:    rivinetypes.TransactionVersionOne,
+			CoinInputs: chain.coinInputs,
+			MinerFees:  []rivinetypes.Currency{chain.txFee},
+		}},
+	}
+	chain.blocks = append(chain.blocks, rewardBlock)
+	chain.txdb.processConsensusChange(modules.ConsensusChange{AppliedBlocks: []rivinetypes.Block{rewardBlock}})
+
+	supply, err := chain.txdb.GetCoinSupply()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSupply := CoinSupply{
+		BlockRewards:      chain.oneCoin,
+		Minted:            chain.oneCoin.Mul64(110).Add(chain.txFee.Mul64(2)),
+		ERC20CreatedIn:    chain.oneCoin.Mul64(50).Add(chain.txFee),
+		ERC20ConvertedOut: chain.oneCoin.Mul64(20),
+	}
+	if !reflect.DeepEqual(supply, expectedSupply) {
+		t.Fatal("unexpected coin supply:", supply, "!=", expectedSupply)
+	}
+	if total := supply.Total(); !total.Equals(chain.oneCoin.Mul64(141).Add(chain.txFee.Mul64(3))) {
+		t.Fatal("unexpected total coin supply:", total.String())
+	}
+
+	checkCoinCreations := func(cursor rivinetypes.TransactionShortID, limit int, filter CoinCreationFilter, expectedHeights []rivinetypes.BlockHeight, expectedTotal rivinetypes.Currency) rivinetypes.TransactionShortID {
+		t.Helper()
+		records, next, total, err := chain.txdb.GetCoinCreations(cursor, limit, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != len(expectedHeights) {
+			t.Fatal("unexpected amount of coin creations:", len(records), "!=", len(expectedHeights))
+		}
+		for idx, record := range records {
+			if record.BlockHeight != expectedHeights[idx] {
+				t.Error(idx, "unexpected coin creation height:", record.BlockHeight, "!=", expectedHeights[idx])
+			}
+		}
+		if !total.Equals(expectedTotal) {
+			t.Fatal("unexpected total of coin creations:", total.String(), "!=", expectedTotal.String())
+		}
+		return next
+	}
+
+	// all coin creations are listed, ordered by height
+	checkCoinCreations(0, 10, CoinCreationFilter{}, []rivinetypes.BlockHeight{1, 2, 3}, supply.Minted.Add(supply.ERC20CreatedIn))
+	records, _, _, err := chain.txdb.GetCoinCreations(0, 10, CoinCreationFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if records[0].Type != CoinCreationTypeMint || string(records[0].ArbitraryData) != "initial minting" || records[0].TransactionID != chain.blocks[1].Transactions[0].ID() {
+		t.Fatal("unexpected first coin creation record:", records[0])
+	}
+	if records[1].Type != CoinCreationTypeERC20 || records[1].Minter.Type != 0 {
+		t.Fatal("unexpected ERC20 coin creation record:", records[1])
+	}
+	if records[2].Minter.Cmp(newMinter) != 0 {
+		t.Fatal("unexpected minter of the second coin creation:", records[2].Minter)
+	}
+
+	// pages are continued using the returned cursor, totals are computed over all pages
+	next := checkCoinCreations(0, 2, CoinCreationFilter{}, []rivinetypes.BlockHeight{1, 2}, supply.Minted.Add(supply.ERC20CreatedIn))
+	if next == 0 {
+		t.Fatal("expected a cursor for the next page")
+	}
+	next = checkCoinCreations(next, 2, CoinCreationFilter{}, []rivinetypes.BlockHeight{3}, chain.oneCoin.Mul64(10).Add(chain.txFee))
+	if next != 0 {
+		t.Fatal("unexpected cursor for the last page:", next)
+	}
+
+	// coin creations can be filtered by type, minter and time
+	checkCoinCreations(0, 10, CoinCreationFilter{Type: CoinCreationTypeMint}, []rivinetypes.BlockHeight{1, 3}, supply.Minted)
+	checkCoinCreations(0, 10, CoinCreationFilter{Type: CoinCreationTypeERC20}, []rivinetypes.BlockHeight{2}, supply.ERC20CreatedIn)
+	checkCoinCreations(0, 10, CoinCreationFilter{Minter: newMinter}, []rivinetypes.BlockHeight{3}, chain.oneCoin.Mul64(10).Add(chain.txFee))
+	checkCoinCreations(0, 10, CoinCreationFilter{Since: chain.blocks[2].Timestamp}, []rivinetypes.BlockHeight{2, 3}, supply.ERC20CreatedIn.Add(chain.oneCoin.Mul64(10)).Add(chain.txFee))
+	checkCoinCreations(0, 10, CoinCreationFilter{Before: chain.blocks[2].Timestamp}, []rivinetypes.BlockHeight{1}, chain.oneCoin.Mul64(100).Add(chain.txFee))
+
+	// reverting all blocks reverts the history and supply as well
+	for len(chain.blocks) > 1 {
+		chain.revertBlock()
+	}
+	supply, err = chain.txdb.GetCoinSupply()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !supply.Total().IsZero() {
+		t.Fatal("unexpected coin supply after reverting all blocks:", supply)
+	}
+	checkCoinCreations(0, 10, CoinCreationFilter{}, nil, rivinetypes.Currency{})
+}
+
+func TestCoinBurnHistoryAndSupply(t *testing.T) {
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionCoinBurn, types.CoinBurnTransactionController{})
+	defer rivinetypes.RegisterTransactionVersion(types.TransactionVersionCoinBurn, nil)
+
+	chain := newTestBotChain(t)
+	defer chain.close()
+
+	burn := func(coins uint64, reason string) rivinetypes.Transaction {
+		return (&types.CoinBurnTransaction{
+			Value:          chain.oneCoin.Mul64(coins),
+			Reason:         []byte(reason),
+			TransactionFee: chain.txFee,
+			CoinInputs:     chain.coinInputs,
+		}).Transaction()
+	}
+
+	// burn 10 TFT (height 1), and 5 + 2 TFT (height 2)
+	chain.applyBlock(burn(10, "unclaimed airdrop"))
+	chain.applyBlock(burn(5, "lost keys"), burn(2, "lost keys"))
+
+	supply, err := chain.txdb.GetCoinSupply()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !supply.Burned.Equals(chain.oneCoin.Mul64(17)) {
+		t.Fatal("unexpected amount of burned coins:", supply.Burned.String())
+	}
+
+	checkCoinBurns := func(cursor rivinetypes.TransactionShortID, limit int, expectedValues []uint64, expectedTotal uint64) rivinetypes.TransactionShortID {
+		t.Helper()
+		records, next, total, err := chain.txdb.GetCoinBurns(cursor, limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != len(expectedValues) {
+			t.Fatal("unexpected amount of coin burns:", len(records), "!=", len(expectedValues))
+		}
+		for idx, record := range records {
+			if !record.Value.Equals(chain.oneCoin.Mul64(expectedValues[idx])) {
+				t.Error(idx, "unexpected burned value:", record.Value.String())
+			}
+		}
+		if !total.Equals(chain.oneCoin.Mul64(expectedTotal)) {
+			t.Fatal("unexpected total of coin burns:", total.String())
+		}
+		return next
+	}
+
+	// all coin burns are listed, ordered by height, and can be paged,
+	// totals are computed starting from the cursor
+	checkCoinBurns(0, 10, []uint64{10, 5, 2}, 17)
+	records, _, _, err := chain.txdb.GetCoinBurns(0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(records[0].Reason) != "unclaimed airdrop" || records[0].BlockHeight != 1 || records[0].TransactionID != chain.blocks[1].Transactions[0].ID() {
+		t.Fatal("unexpected first coin burn record:", records[0])
+	}
+	next := checkCoinBurns(0, 2, []uint64{10, 5}, 17)
+	if next == 0 {
+		t.Fatal("expected a cursor for the next page")
+	}
+	next = checkCoinBurns(next, 2, []uint64{2}, 2)
+	if next != 0 {
+		t.Fatal("unexpected cursor for the last page:", next)
+	}
+
+	// reverting a block reverts its burns as well
+	chain.revertBlock()
+	supply, err = chain.txdb.GetCoinSupply()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !supply.Burned.Equals(chain.oneCoin.Mul64(10)) {
+		t.Fatal("unexpected amount of burned coins after reverting a block:", supply.Burned.String())
+	}
+	checkCoinBurns(0, 10, []uint64{10}, 10)
+}
+
+func TestMintingCapAndMintedCoinsSince(t *testing.T) {
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionCoinCreation, types.CoinCreationTransactionController{})
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionMintingCapDefinition, types.MintingCapDefinitionTransactionController{})
+	defer func() {
+		rivinetypes.RegisterTransactionVersion(types.TransactionVersionCoinCreation, nil)
+		rivinetypes.RegisterTransactionVersion(types.TransactionVersionMintingCapDefinition, nil)
+	}()
+
+	chain := newTestBotChain(t)
+	defer chain.close()
+
+	mintFulfillment := rivinetypes.NewFulfillment(rivinetypes.NewSingleSignatureFulfillment(newTestPublicKey(1)))
+	mint := func(nonce byte, coins uint64) rivinetypes.Transaction {
+		return (&types.CoinCreationTransaction{
+			Nonce:           types.TransactionNonce{nonce},
+			MintFulfillment: mintFulfillment,
+			CoinOutputs:     []rivinetypes.CoinOutput{{Value: chain.oneCoin.Mul64(coins)}},
+			MinerFees:       []rivinetypes.Currency{chain.txFee},
+		}).Transaction()
+	}
+	defineCap := func(nonce byte, coins uint64) rivinetypes.Transaction {
+		return (&types.MintingCapDefinitionTransaction{
+			Nonce:           types.TransactionNonce{nonce},
+			MintFulfillment: mintFulfillment,
+			MintingCap:      chain.oneCoin.Mul64(coins),
+			MinerFees:       []rivinetypes.Currency{chain.txFee},
+		}).Transaction()
+	}
+
+	// mint 100 TFT (height 1), cap minting at 500 TFT (height 2),
+	// mint 10 TFT and raise the cap to 1000 TFT (height 3), lower the cap to 200 TFT (height 4)
+	chain.applyBlock(mint(1, 100))
+	chain.applyBlock(defineCap(2, 500))
+	chain.applyBlock(mint(3, 10), defineCap(4, 1000))
+	chain.applyBlock(defineCap(5, 200))
+	blockTime := func(height int) rivinetypes.Timestamp {
+		return chain.blocks[height].Timestamp
+	}
+	const allBlocks = rivinetypes.BlockHeight(100)
+
+	checkMintingCap := func(height rivinetypes.BlockHeight, since rivinetypes.Timestamp, expectedCapped bool, expectedCap uint64) {
+		t.Helper()
+		mintingCap, capped, err := chain.txdb.GetMintingCapSince(height, since)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if capped != expectedCapped {
+			t.Fatal("unexpected capped state:", capped, "!=", expectedCapped)
+		}
+		if capped && !mintingCap.Equals(chain.oneCoin.Mul64(expectedCap)) {
+			t.Fatal("unexpected minting cap:", mintingCap.String())
+		}
+	}
+	// only blocks prior to the given height are taken into account
+	checkMintingCap(2, 0, false, 0)
+	checkMintingCap(3, 0, true, 500)
+	// the lowest cap that applied since the given time is returned
+	checkMintingCap(allBlocks, 0, true, 200)
+	checkMintingCap(4, blockTime(3), true, 500)
+	// a raised cap only applies once the previous cap falls outside the window
+	checkMintingCap(4, blockTime(3)+1, true, 1000)
+	checkMintingCap(allBlocks, blockTime(4), true, 200)
+
+	checkMinted := func(height rivinetypes.BlockHeight, since rivinetypes.Timestamp, expectedCoins uint64, expectedFees uint64) {
+		t.Helper()
+		minted, err := chain.txdb.GetMintedCoinsSince(height, since)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := chain.oneCoin.Mul64(expectedCoins).Add(chain.txFee.Mul64(expectedFees))
+		if !minted.Equals(expected) {
+			t.Fatal("unexpected minted coins:", minted.String(), "!=", expected.String())
+		}
+	}
+	checkMinted(allBlocks, 0, 110, 2)
+	checkMinted(allBlocks, blockTime(3), 10, 1)
+	checkMinted(3, blockTime(3), 0, 0)
+	checkMinted(allBlocks, blockTime(4), 0, 0)
+
+	// reverting a block reverts its minting cap as well
+	chain.revertBlock()
+	checkMintingCap(allBlocks, 0, true, 500)
+	checkMintingCap(allBlocks, blockTime(3)+1, true, 1000)
+}
+
+func TestConsensusParameterUpdates(t *testing.T) {
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionConsensusParameterUpdate, types.ConsensusParameterUpdateTransactionController{})
+	defer rivinetypes.RegisterTransactionVersion(types.TransactionVersionConsensusParameterUpdate, nil)
+
+	chain := newTestBotChain(t)
+	defer chain.close()
+
+	genesisParams := types.GetDevnetGenesisConsensusParameters()
+	mintFulfillment := rivinetypes.NewFulfillment(rivinetypes.NewSingleSignatureFulfillment(newTestPublicKey(1)))
+	update := func(nonce byte, effectiveHeight rivinetypes.BlockHeight, updates ...types.ConsensusParameterUpdate) rivinetypes.Transaction {
+		return (&types.ConsensusParameterUpdateTransaction{
+			Nonce:           types.TransactionNonce{nonce},
+			MintFulfillment: mintFulfillment,
+			EffectiveHeight: effectiveHeight,
+			Updates:         updates,
+			MinerFees:       []rivinetypes.Currency{chain.txFee},
+		}).Transaction()
+	}
+
+	// set the monthly bot fee to 5 TFT from height 3 (height 1),
+	// set it to 7 TFT and enforce the minimum transaction fee from height 10, both from height 4 (height 2)
+	chain.applyBlock(update(1, 3,
+		types.ConsensusParameterUpdate{Name: types.ConsensusParameterBotMonthlyFee, Value: chain.oneCoin.Mul64(5)}))
+	chain.applyBlock(update(2, 4,
+		types.ConsensusParameterUpdate{Name: types.ConsensusParameterBotMonthlyFee, Value: chain.oneCoin.Mul64(7)},
+		types.ConsensusParameterUpdate{Name: types.ConsensusParameterTransactionFeeCheckHeight, Value: rivinetypes.NewCurrency64(10)}))
+
+	checkParams := func(height rivinetypes.BlockHeight, expectedMonthlyFee rivinetypes.Currency, expectedFeeCheckHeight rivinetypes.BlockHeight) {
+		t.Helper()
+		params, err := chain.txdb.GetConsensusParametersAt(height)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !params.BotMonthlyFee.Equals(expectedMonthlyFee) {
+			t.Fatal("unexpected monthly bot fee:", params.BotMonthlyFee.String(), "!=", expectedMonthlyFee.String())
+		}
+		if params.TransactionFeeCheckHeight != expectedFeeCheckHeight {
+			t.Fatal("unexpected transaction fee check height:", params.TransactionFeeCheckHeight, "!=", expectedFeeCheckHeight)
+		}
+		if !params.BotRegistrationFee.Equals(genesisParams.BotRegistrationFee) {
+			t.Fatal("unexpected bot registration fee:", params.BotRegistrationFee.String())
+		}
+	}
+	// updates only apply from their effective height
+	checkParams(2, genesisParams.BotMonthlyFee, genesisParams.TransactionFeeCheckHeight)
+	checkParams(3, chain.oneCoin.Mul64(5), genesisParams.TransactionFeeCheckHeight)
+	checkParams(4, chain.oneCoin.Mul64(7), 10)
+	checkParams(100, chain.oneCoin.Mul64(7), 10)
+
+	// the active parameters are the ones that apply to the next block (height 3)
+	params, err := chain.txdb.GetActiveConsensusParameters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !params.BotMonthlyFee.Equals(chain.oneCoin.Mul64(5)) {
+		t.Fatal("unexpected active monthly bot fee:", params.BotMonthlyFee.String())
+	}
+
+	// reverting a block reverts its updates as well
+	chain.revertBlock()
+	checkParams(4, chain.oneCoin.Mul64(5), genesisParams.TransactionFeeCheckHeight)
+}
+
+func newTestBotChain(t *testing.T) *testBotChain {
+	return newTestBotChainWithParameters(t, types.GetDevnetGenesisConsensusParameters())
+}
+
+func newTestBotChainWithParameters(t *testing.T, params types.ConsensusParameters) *testBotChain {
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionBotRegistration, types.BotRegistrationTransactionController{})
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionBotRecordUpdate, types.BotUpdateRecordTransactionController{})
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionBotNameTransfer, types.BotNameTransferTransactionController{})
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionBotKeyRotation, types.BotKeyRotationTransactionController{})
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionBotRecordUpdateWithFulfillment, types.BotUpdateRecordWithFulfillmentTransactionController{})
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionBotNameTransferWithFulfillment, types.BotNameTransferWithFulfillmentTransactionController{})
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionBotNameSale, types.BotNameSaleTransactionController{})
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionBotNameDelegation, types.BotNameDelegationTransactionController{})
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionBotRecordUpdateWithServices, types.BotUpdateRecordWithServicesTransactionController{})
+
+	dir, err := ioutil.TempDir("", "tfchain-txdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	txdb, err := NewTransactionDB(dir, rivinetypes.NewCondition(rivinetypes.NewUnlockHashCondition(rivinetypes.UnlockHash{})), params)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	chain := &testBotChain{
+		t:          t,
+		dir:        dir,
+		txdb:       txdb,
+		oneCoin:    config.GetCurrencyUnits().OneCoin,
+		txFee:      config.GetDevnetGenesis().MinimumTransactionFee,
+		coinInputs: []rivinetypes.CoinInput{{}},
+	}
+	// apply the genesis block (height 0)
+	chain.applyBlock()
+	return chain
+}
+
+func (chain *testBotChain) close() {
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionBotRegistration, nil)
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionBotRecordUpdate, nil)
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionBotNameTransfer, nil)
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionBotKeyRotation, nil)
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionBotRecordUpdateWithFulfillment, nil)
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionBotNameTransferWithFulfillment, nil)
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionBotNameSale, nil)
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionBotNameDelegation, nil)
+	rivinetypes.RegisterTransactionVersion(types.TransactionVersionBotRecordUpdateWithServices, nil)
+	err := chain.txdb.Close()
+	if err != nil {
+		chain.t.Error(err)
+	}
+	os.RemoveAll(chain.dir)
+}
+
+func (chain *testBotChain) applyBlock(txs ...rivinetypes.Transaction) {
+	block := rivinetypes.Block{
+		Timestamp:    rivinetypes.Timestamp(1550000000+len(chain.blocks)*120) + chain.timeShift,
+		Transactions: txs,
+	}
+	chain.blocks = append(chain.blocks, block)
+	chain.txdb.processConsensusChange(modules.ConsensusChange{AppliedBlocks: []rivinetypes.Block{block}})
+}
+
+func (chain *testBotChain) revertBlock() {
+	block := chain.blocks[len(chain.blocks)-1]
+	chain.blocks = chain.blocks[:len(chain.blocks)-1]
+	chain.txdb.processConsensusChange(modules.ConsensusChange{RevertedBlocks: []rivinetypes.Block{block}})
+}
+
+func mustNewNetworkAddress(t *testing.T, str string) types.NetworkAddress {
+	t.Helper()
+	addr, err := types.NewNetworkAddress(str)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addr
+}
+
 func mustNewBotName(t *testing.T, str string) types.BotName {
 	t.Helper()
 	name, err := types.NewBotName(str)
@@ -21,6 +21,9 @@ const (
 	MaxNamesPerBot = 5
 	// MaxAddressesPerBot defines the maximum amount of addresses allowed per unique bot.
 	MaxAddressesPerBot = 10
+	// MaxDelegatesPerBotName defines the maximum amount of bots a name can be delegated to,
+	// by its owner, at any given time.
+	MaxDelegatesPerBotName = 10
 )
 
 const (
@@ -32,6 +35,14 @@ const (
 	// MaxBotPrepaidMonthsInSeconds defines the amount of time that is allowed to be maximum
 	// paid upfront, which is the equavalent of roughly 2 years.
 	MaxBotPrepaidMonthsInSeconds = MaxBotPrepaidMonths * BotMonth
+	// BotNameGracePeriod defines the period, expressed in seconds, following the expiration of a 3bot,
+	// during which its names remain reserved for that 3bot. Only once this period has passed,
+	// are the names released and can they be claimed by any other 3bot.
+	//
+	// It cannot be greater than a BotMonth, as renewing a 3bot during its grace period
+	// extends its expiration time starting from the time it expired, rather than the current time,
+	// which has to make the 3bot active again, even when only a single month is paid for.
+	BotNameGracePeriod = BotMonth
 )
 
 var (
@@ -102,9 +113,39 @@ type (
 		Names      BotNameSortedSet        `json:"names,omitempty"`
 		PublicKey  types.PublicKey         `json:"publickey"`
 		Expiration CompactTimestamp        `json:"expiration"`
+		// Owner is the optional condition that has to be fulfilled in order to modify this record,
+		// if not defined the record is owned by (and thus modified using a signature of) its PublicKey.
+		Owner *types.UnlockConditionProxy `json:"owner,omitempty"`
+		// Services offered by the 3bot, reachable on its network addresses.
+		Services BotServiceSortedSet `json:"services,omitempty"`
 	}
 )
 
+const (
+	// botRecordExtensionFlag is the bit of the merged addr+name length,
+	// used to indicate that a record is owned by a condition and/or defines services,
+	// in which case an extension byte follows the merged addr+name length.
+	// Names only require 3 bits as no more than 5 names can be linked to a single bot.
+	botRecordExtensionFlag = 1 << 7
+	// botRecordOwnerFlag is the bit of the extension byte,
+	// used to indicate that a record is owned by a condition rather than its public key.
+	// The other bits of the extension byte contain the amount of services.
+	botRecordOwnerFlag = 1 << 7
+)
+
+// MarshalJSON implements json.Marshaler.MarshalJSON,
+// omitting the services of the record in case it has none.
+func (record BotRecord) MarshalJSON() ([]byte, error) {
+	type botRecord BotRecord // used to prevent recursion
+	return json.Marshal(struct {
+		botRecord
+		Services []BotService `json:"services,omitempty"`
+	}{
+		botRecord: botRecord(record),
+		Services:  record.Services.Services(),
+	})
+}
+
 // MarshalSia implements SiaMarshaler.MarshalSia,
 // alias of MarshalRivine for backwards-compatibility reasons.
 func (record BotRecord) MarshalSia(w io.Writer) error {
@@ -121,16 +162,25 @@ func (record *BotRecord) UnmarshalSia(r io.Reader) error {
 func (record BotRecord) MarshalRivine(w io.Writer) error {
 	enc := rivbin.NewEncoder(w)
 
-	// encode the ID and merged addr+name length
-	err := enc.EncodeAll(
-		record.ID,
-		uint8(record.Addresses.Len())|(uint8(record.Names.Len())<<4),
-	)
+	// encode the ID and merged addr+name length (and extension flag)
+	pairLength := uint8(record.Addresses.Len()) | (uint8(record.Names.Len()) << 4)
+	var err error
+	if record.Owner == nil && record.Services.Len() == 0 {
+		err = enc.EncodeAll(record.ID, pairLength)
+	} else {
+		// encode the services length and owner flag in an additional extension byte,
+		// such that only records with an owner or services pay for it
+		extension := uint8(record.Services.Len())
+		if record.Owner != nil {
+			extension |= botRecordOwnerFlag
+		}
+		err = enc.EncodeAll(record.ID, pairLength|botRecordExtensionFlag, extension)
+	}
 	if err != nil {
 		return err
 	}
 
-	// encode all addresses and names, one after the other
+	// encode all addresses, names and services, one after the other
 	_, err = record.Addresses.BinaryEncode(w)
 	if err != nil {
 		return err
@@ -139,12 +189,24 @@ func (record BotRecord) MarshalRivine(w io.Writer) error {
 	if err != nil {
 		return err
 	}
+	_, err = record.Services.BinaryEncode(w)
+	if err != nil {
+		return err
+	}
 
 	// encode the public key and the expiration date
 	err = enc.EncodeAll(record.PublicKey, record.Expiration)
 	if err != nil {
 		return fmt.Errorf("BotRecord: MarshalRivine: publicKey+expiration: %v", err)
 	}
+
+	// encode the owner condition, only if defined
+	if record.Owner != nil {
+		err = enc.Encode(*record.Owner)
+		if err != nil {
+			return fmt.Errorf("BotRecord: MarshalRivine: owner: %v", err)
+		}
+	}
 	return nil
 }
 
@@ -157,7 +219,15 @@ func (record *BotRecord) UnmarshalRivine(r io.Reader) error {
 	if err != nil {
 		return err
 	}
-	addrLen, nameLen := pairLength&15, pairLength>>4
+	addrLen, nameLen := pairLength&15, (pairLength&^botRecordExtensionFlag)>>4
+	// decode the extension byte, only if defined
+	var extension uint8
+	if pairLength&botRecordExtensionFlag != 0 {
+		err = decoder.Decode(&extension)
+		if err != nil {
+			return err
+		}
+	}
 	// decode all addresses
 	err = record.Addresses.BinaryDecode(r, int(addrLen))
 	if err != nil {
@@ -170,14 +240,39 @@ func (record *BotRecord) UnmarshalRivine(r io.Reader) error {
 		return err
 	}
 
+	// decode all services
+	err = record.Services.BinaryDecode(r, int(extension&^botRecordOwnerFlag))
+	if err != nil {
+		return err
+	}
+
 	// decode the remaining properties
 	err = decoder.DecodeAll(&record.PublicKey, &record.Expiration)
 	if err != nil {
 		return err
 	}
+
+	// decode the owner condition, only if defined
+	record.Owner = nil
+	if extension&botRecordOwnerFlag != 0 {
+		record.Owner = new(types.UnlockConditionProxy)
+		err = decoder.Decode(record.Owner)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// OwnerCondition returns the condition that has to be fulfilled in order to modify this record,
+// which is the Owner condition if defined, and the unlock hash condition of its PublicKey otherwise.
+func (record *BotRecord) OwnerCondition() types.UnlockConditionProxy {
+	if record.Owner != nil {
+		return *record.Owner
+	}
+	return types.NewCondition(types.NewUnlockHashCondition(types.NewPubKeyUnlockHash(record.PublicKey)))
+}
+
 // AddNames adds one or multiple unique (DNS) names to this 3bot record.
 func (record *BotRecord) AddNames(names ...BotName) error {
 	if record.Names.Len()+len(names) > MaxNamesPerBot {
@@ -238,14 +333,80 @@ func (record *BotRecord) RemoveNetworkAddresses(addresses ...NetworkAddress) err
 	return nil
 }
 
+// AddServices adds one or multiple unique services to this 3bot record.
+func (record *BotRecord) AddServices(services ...BotService) error {
+	if record.Services.Len()+len(services) > MaxServicesPerBot {
+		return ErrTooManyBotServices
+	}
+	var err error
+	for _, service := range services {
+		err = record.Services.AddService(service)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveServices removes one or multiple services from this 3bot record.
+func (record *BotRecord) RemoveServices(services ...BotService) error {
+	var err error
+	for _, service := range services {
+		err = record.Services.RemoveService(service)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // IsExpired returns if this record indicate the bot is expired.
 func (record *BotRecord) IsExpired(blockTime types.Timestamp) bool {
 	return record.Expiration.SiaTimestamp() <= blockTime
 }
 
-// ExtendExpirationDate extends the expiration day of this 3bot record based on the block time
-// and the months to add.
-func (record *BotRecord) ExtendExpirationDate(blockTime types.Timestamp, addedMonths uint8) error {
+// IsReleased returns if this record indicate the bot is expired
+// for longer than the given grace period, releasing the names it owned.
+// See (*ConsensusParameters).BotNameGracePeriodAt for the grace period that applies.
+func (record *BotRecord) IsReleased(blockTime types.Timestamp, gracePeriod CompactTimestamp) bool {
+	return (record.Expiration + gracePeriod).SiaTimestamp() <= blockTime
+}
+
+// Status returns the status of the bot, as indicated by this record, at the given block time,
+// using the given grace period.
+func (record *BotRecord) Status(blockTime types.Timestamp, gracePeriod CompactTimestamp) BotRecordStatus {
+	if !record.IsExpired(blockTime) {
+		return BotRecordStatusActive
+	}
+	if !record.IsReleased(blockTime, gracePeriod) {
+		return BotRecordStatusGrace
+	}
+	return BotRecordStatusReleased
+}
+
+// MaxExtendableMonths returns the maximum amount of months the expiration date of the record
+// can be extended with at the given block time and grace period, as limited by MaxBotPrepaidMonths.
+// Zero is returned if the record cannot be extended at all at the given block time.
+func (record *BotRecord) MaxExtendableMonths(blockTime types.Timestamp, gracePeriod CompactTimestamp) uint8 {
+	bts := SiaTimestampAsCompactTimestamp(blockTime)
+	base := record.Expiration
+	if record.IsReleased(blockTime, gracePeriod) {
+		base = bts
+	}
+	limit := bts + MaxBotPrepaidMonthsInSeconds
+	if base >= limit {
+		return 0
+	}
+	months := (limit - base) / BotMonth
+	if months > MaxBotPrepaidMonths {
+		return MaxBotPrepaidMonths
+	}
+	return uint8(months)
+}
+
+// ExtendExpirationDate extends the expiration day of this 3bot record based on the block time,
+// the grace period and the months to add.
+func (record *BotRecord) ExtendExpirationDate(blockTime types.Timestamp, gracePeriod CompactTimestamp, addedMonths uint8) error {
 	if addedMonths == 0 {
 		return errors.New("at least one month is required in order to extend a bot's expiration date")
 	}
@@ -253,10 +414,11 @@ func (record *BotRecord) ExtendExpirationDate(blockTime types.Timestamp, addedMo
 		return ErrBotExpirationExtendOverflow
 	}
 	bts := SiaTimestampAsCompactTimestamp(blockTime)
-	if record.IsExpired(blockTime) {
-		// set the block time as the base time if the record's last recorded timestamp is in the past
+	if record.IsReleased(blockTime, gracePeriod) {
+		// set the block time as the base time if the record's last recorded timestamp
+		// is further in the past than the grace period
 		record.Expiration = bts
-	} // otherwise we extend based on the current graph
+	} // otherwise we extend based on the current graph, paying for the grace period as well
 	newExpirationDate := record.Expiration + BotMonth*CompactTimestamp(addedMonths)
 	if newExpirationDate-bts > MaxBotPrepaidMonthsInSeconds {
 		return ErrBotExpirationExtendOverflow
@@ -265,6 +427,163 @@ func (record *BotRecord) ExtendExpirationDate(blockTime types.Timestamp, addedMo
 	return nil
 }
 
+// BotRecordStatus defines the status of a 3bot, at a given point in time.
+type BotRecordStatus uint8
+
+// The different statuses a 3bot can be in.
+const (
+	// BotRecordStatusActive is the status of a 3bot that is not expired.
+	BotRecordStatusActive BotRecordStatus = iota
+	// BotRecordStatusGrace is the status of a 3bot that is expired,
+	// but is still within its grace period. Its names are reserved
+	// and can only be kept by renewing the 3bot.
+	BotRecordStatusGrace
+	// BotRecordStatusReleased is the status of a 3bot that is expired
+	// for longer than its grace period. Its names are released and can be
+	// claimed by any 3bot.
+	BotRecordStatusReleased
+)
+
+// String returns the status as a string.
+func (status BotRecordStatus) String() string {
+	switch status {
+	case BotRecordStatusActive:
+		return "active"
+	case BotRecordStatusGrace:
+		return "grace"
+	case BotRecordStatusReleased:
+		return "released"
+	default:
+		return strconv.FormatUint(uint64(status), 10)
+	}
+}
+
+// LoadString loads a BotRecordStatus from a string.
+func (status *BotRecordStatus) LoadString(str string) error {
+	switch str {
+	case "active":
+		*status = BotRecordStatusActive
+	case "grace":
+		*status = BotRecordStatusGrace
+	case "released":
+		*status = BotRecordStatusReleased
+	default:
+		return fmt.Errorf("unknown bot record status %q", str)
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.MarshalJSON
+func (status BotRecordStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(status.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.UnmarshalJSON
+func (status *BotRecordStatus) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err != nil {
+		return err
+	}
+	return status.LoadString(str)
+}
+
+type (
+	// BotRecordVersion is a version of a BotRecord, as it was defined by
+	// a transaction that created or modified that record.
+	// The TransactionDB stores a version for every such transaction,
+	// such that the record can be looked up as it was at any point in time.
+	BotRecordVersion struct {
+		// Record as it was defined by the transaction.
+		Record BotRecord `json:"record"`
+		// TransactionID of the transaction that defined this version.
+		TransactionID types.TransactionID `json:"txid"`
+		// BlockHeight of the block that contains the transaction.
+		BlockHeight types.BlockHeight `json:"blockheight"`
+		// BlockTime of the block that contains the transaction.
+		BlockTime types.Timestamp `json:"blocktime"`
+	}
+
+	// BotNameOwnership defines the 3bot that owns a name,
+	// starting from the transaction that assigned (or released) that name.
+	//
+	// Note that a name also becomes unavailable when the 3bot that owns it expires,
+	// which is not recorded as a change of ownership, as it is not defined by a transaction.
+	BotNameOwnership struct {
+		// ID of the 3bot that owns the name, 0 in case the name was released.
+		ID BotID `json:"id"`
+		// TransactionID of the transaction that assigned (or released) the name.
+		TransactionID types.TransactionID `json:"txid"`
+		// BlockHeight of the block that contains the transaction.
+		BlockHeight types.BlockHeight `json:"blockheight"`
+		// BlockTime of the block that contains the transaction.
+		BlockTime types.Timestamp `json:"blocktime"`
+	}
+)
+
+// MarshalSia implements SiaMarshaler.MarshalSia,
+// alias of MarshalRivine for backwards-compatibility reasons.
+func (version BotRecordVersion) MarshalSia(w io.Writer) error {
+	return version.MarshalRivine(w)
+}
+
+// UnmarshalSia implements SiaUnmarshaler.UnmarshalSia,
+// alias of UnmarshalRivine for backwards-compatibility reasons.
+func (version *BotRecordVersion) UnmarshalSia(r io.Reader) error {
+	return version.UnmarshalRivine(r)
+}
+
+// MarshalRivine implements RivineMarshaler.MarshalRivine
+func (version BotRecordVersion) MarshalRivine(w io.Writer) error {
+	return rivbin.NewEncoder(w).EncodeAll(
+		version.Record,
+		version.TransactionID,
+		version.BlockHeight,
+		version.BlockTime,
+	)
+}
+
+// UnmarshalRivine implements RivineUnmarshaler.UnmarshalRivine
+func (version *BotRecordVersion) UnmarshalRivine(r io.Reader) error {
+	return rivbin.NewDecoder(r).DecodeAll(
+		&version.Record,
+		&version.TransactionID,
+		&version.BlockHeight,
+		&version.BlockTime,
+	)
+}
+
+// MarshalSia implements SiaMarshaler.MarshalSia,
+// alias of MarshalRivine for backwards-compatibility reasons.
+func (ownership BotNameOwnership) MarshalSia(w io.Writer) error {
+	return ownership.MarshalRivine(w)
+}
+
+// UnmarshalSia implements SiaUnmarshaler.UnmarshalSia,
+// alias of UnmarshalRivine for backwards-compatibility reasons.
+func (ownership *BotNameOwnership) UnmarshalSia(r io.Reader) error {
+	return ownership.UnmarshalRivine(r)
+}
+
+// MarshalRivine implements RivineMarshaler.MarshalRivine
+func (ownership BotNameOwnership) MarshalRivine(w io.Writer) error {
+	return rivbin.NewEncoder(w).EncodeAll(
+		ownership.ID,
+		ownership.TransactionID,
+		ownership.BlockHeight,
+		ownership.BlockTime,
+	)
+}
+
+// UnmarshalRivine implements RivineUnmarshaler.UnmarshalRivine
+func (ownership *BotNameOwnership) UnmarshalRivine(r io.Reader) error {
+	return rivbin.NewDecoder(r).DecodeAll(
+		&ownership.ID,
+		&ownership.TransactionID,
+		&ownership.BlockHeight,
+		&ownership.BlockTime,
+	)
+}
+
 type (
 	// BotID defines the identifier type for 3bots,
 	// each 3bot has a unique identifier using this type.
@@ -366,6 +685,16 @@ func (bn *BotName) UnmarshalJSON(b []byte) error {
 	return bn.LoadString(str)
 }
 
+// Parent returns the parent of this BotName, which is the name without its first (dot-separated) label.
+// False is returned if this BotName is a top-level name, and thus has no parent.
+func (bn BotName) Parent() (BotName, bool) {
+	index := bytes.IndexByte(bn.name, '.')
+	if index == -1 {
+		return BotName{}, false
+	}
+	return BotName{name: bn.name[index+1:]}, true
+}
+
 // Equals returns true if this BotName and the given BotName are equal (case insensitive).
 func (bn BotName) Equals(obn BotName) bool {
 	return bn.Compare(obn) == 0
@@ -412,6 +741,15 @@ func (bnss *BotNameSortedSet) AddName(name BotName) error {
 	return nil
 }
 
+// Contains returns true if the given bot name is part of this sorted set of bot names.
+func (bnss BotNameSortedSet) Contains(name BotName) bool {
+	limit := bnss.slice.Len()
+	index := sort.Search(limit, func(i int) bool {
+		return bnss.slice[i].Compare(name) >= 0
+	})
+	return index < limit && bnss.slice[index].Equals(name)
+}
+
 // RemoveName removes an existing bot name from this sorted set of bot names,
 // returning an error if the name did not yet exist in this sorted set.
 func (bnss *BotNameSortedSet) RemoveName(name BotName) error {
@@ -0,0 +1,68 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/types"
+)
+
+var (
+	// SpecifierBotMessage is the specifier used to domain-separate the signature of a 3bot message,
+	// such that a signed message can never be used as the signature of a transaction.
+	SpecifierBotMessage = types.Specifier{'b', 'o', 't', ' ', 'm', 'e', 's', 's', 'a', 'g', 'e'}
+)
+
+var (
+	// ErrInvalidBotMessageSignature is the error returned in case
+	// the signature of a 3bot message is not valid for the given public key.
+	ErrInvalidBotMessageSignature = errors.New("invalid 3bot message signature")
+)
+
+// BotMessageSignatureHash returns the hash that is signed in order to sign a message as the given 3bot.
+// The ID of the 3bot is part of the hash, such that the signature of a message
+// cannot be reused to claim that the message comes from another 3bot using the same public key.
+func BotMessageSignatureHash(id BotID, message []byte) crypto.Hash {
+	return crypto.HashAll(SpecifierBotMessage, id, message)
+}
+
+// SignBotMessage signs the given message as the given 3bot, using the given (private) key,
+// which is expected to be the private key paired with the public key of the 3bot's record.
+func SignBotMessage(id BotID, message []byte, publicKey types.PublicKey, key types.ByteSlice) (types.ByteSlice, error) {
+	switch publicKey.Algorithm {
+	case types.SignatureAlgoEd25519:
+		var sk crypto.SecretKey
+		if len(key) != len(sk) {
+			return nil, fmt.Errorf("invalid ed25519 private key size: %d", len(key))
+		}
+		copy(sk[:], key)
+		sig := crypto.SignHash(BotMessageSignatureHash(id, message), sk)
+		return types.ByteSlice(sig[:]), nil
+	default:
+		return nil, fmt.Errorf("unsupported public key algorithm %v", publicKey.Algorithm)
+	}
+}
+
+// VerifyBotMessageSignature verifies that the given signature was created
+// by signing the given message as the given 3bot, using the given public key.
+func VerifyBotMessageSignature(id BotID, message []byte, publicKey types.PublicKey, signature types.ByteSlice) error {
+	switch publicKey.Algorithm {
+	case types.SignatureAlgoEd25519:
+		var (
+			pk  crypto.PublicKey
+			sig crypto.Signature
+		)
+		if len(publicKey.Key) != len(pk) || len(signature) != len(sig) {
+			return ErrInvalidBotMessageSignature
+		}
+		copy(pk[:], publicKey.Key)
+		copy(sig[:], signature)
+		if crypto.VerifyHash(BotMessageSignatureHash(id, message), pk, sig) != nil {
+			return ErrInvalidBotMessageSignature
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported public key algorithm %v", publicKey.Algorithm)
+	}
+}
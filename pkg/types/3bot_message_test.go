@@ -0,0 +1,53 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestBotMessageSignatureHash(t *testing.T) {
+	// blake2b-256 hash of the specifier, the bot ID and the (length-prefixed) message
+	const expected = "d5626d3a8e64996c606966d0d870d033689c2b4788948198d9ce77f70f372a8c"
+	if hash := BotMessageSignatureHash(42, []byte("hello world")); hash.String() != expected {
+		t.Fatal("unexpected bot message signature hash:", hash.String(), "!=", expected)
+	}
+}
+
+func TestSignAndVerifyBotMessage(t *testing.T) {
+	message := []byte("hello world")
+	signature, err := SignBotMessage(42, message, cryptoKeyPair.PublicKey, cryptoKeyPair.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expected = "5358393e1bea24ad94ec03a2c15adedb7cd4f054708ca0a173ad0ec2c7c1937759f850c4c766edd1a9d0219134e520090fd0dc5c4b3957b7ae85e441a3c9f709"
+	if signature.String() != expected {
+		t.Fatal("unexpected bot message signature:", signature.String(), "!=", expected)
+	}
+	err = VerifyBotMessageSignature(42, message, cryptoKeyPair.PublicKey, signature)
+	if err != nil {
+		t.Fatal("failed to verify bot message signature:", err)
+	}
+
+	// the signature is only valid for the same bot, message and public key
+	err = VerifyBotMessageSignature(43, message, cryptoKeyPair.PublicKey, signature)
+	if err != ErrInvalidBotMessageSignature {
+		t.Fatal("unexpected error while verifying the signature for another bot:", err)
+	}
+	err = VerifyBotMessageSignature(42, []byte("hello world!"), cryptoKeyPair.PublicKey, signature)
+	if err != ErrInvalidBotMessageSignature {
+		t.Fatal("unexpected error while verifying the signature for another message:", err)
+	}
+	otherKey := types.PublicKey{
+		Algorithm: types.SignatureAlgoEd25519,
+		Key:       hbs("00bde9571b30e1742c41fcca8c730183402d967df5b17b5f4ced22c677806614"),
+	}
+	err = VerifyBotMessageSignature(42, message, otherKey, signature)
+	if err != ErrInvalidBotMessageSignature {
+		t.Fatal("unexpected error while verifying the signature using another public key:", err)
+	}
+	err = VerifyBotMessageSignature(42, message, cryptoKeyPair.PublicKey, signature[:32])
+	if err != ErrInvalidBotMessageSignature {
+		t.Fatal("unexpected error while verifying a truncated signature:", err)
+	}
+}
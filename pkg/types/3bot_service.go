@@ -0,0 +1,496 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+)
+
+const (
+	// MaxServicesPerBot defines the maximum amount of services allowed per unique bot.
+	MaxServicesPerBot = 8
+	// MaxLengthBotServiceName defines the maximum length a 3bot service name can have,
+	// which is the same maximum length as defined for service names in RFC 6335.
+	MaxLengthBotServiceName = 15
+	// MaxLengthBotServiceVersion defines the maximum length a 3bot service (protocol) version can have.
+	MaxLengthBotServiceVersion = 31
+)
+
+const (
+	// RegexpBotServiceName is used to validate a (raw) 3bot service name (string),
+	// following the syntax of service names as defined in RFC 6335 (e.g. http, xmpp-client).
+	// Note that a service name is also required to contain at least one letter.
+	RegexpBotServiceName = `^[a-z0-9]+(\-[a-z0-9]+)*$`
+	// RegexpBotServiceVersion is used to validate a (raw) 3bot service version (string).
+	RegexpBotServiceVersion = `^[A-Za-z0-9]([A-Za-z0-9\.\-\+_]*)$`
+)
+
+var (
+	rexBotServiceName    = regexp.MustCompile(RegexpBotServiceName)
+	rexBotServiceLetter  = regexp.MustCompile(`[a-z]`)
+	rexBotServiceVersion = regexp.MustCompile(RegexpBotServiceVersion)
+)
+
+var (
+	// ErrTooManyBotServices is the error returned in case a bot which has more than 8
+	// services defined is attempted to be (un)marshaled, or in case an amount of services
+	// to be added to the bot's record would overflow this limit of 8.
+	ErrTooManyBotServices = errors.New("a 3bot can have a maximum of 8 services")
+	// ErrBotServiceNotUnique is the error returned in case a 3bot service is added,
+	// while a service with the same name and protocol is already registered in this 3bot.
+	ErrBotServiceNotUnique = errors.New("the service is already registerd with this 3bot")
+	// ErrBotServiceDoesNotExist is the error returned in case a 3bot service is removed
+	// that is not registered (exactly as given) in this 3bot.
+	ErrBotServiceDoesNotExist = errors.New("the service is not registerd with this 3bot")
+	// ErrInvalidBotService is the error returned in case a to-be-created (or decoded)
+	// 3bot service is invalid.
+	ErrInvalidBotService = errors.New("invalid bot service")
+)
+
+// BotServiceProtocol defines the (transport) protocol of a 3bot service,
+// comparable to the protocol label of a DNS SRV record.
+type BotServiceProtocol uint8
+
+// The transport protocols supported for 3bot services.
+const (
+	BotServiceProtocolTCP BotServiceProtocol = iota
+	BotServiceProtocolUDP
+)
+
+// maxBotServiceProtocol is the largest protocol value that can be encoded,
+// as only 3 bits are reserved for it in the binary encoding of a BotService.
+const maxBotServiceProtocol = 7
+
+// String implements fmt.Stringer.String
+func (p BotServiceProtocol) String() string {
+	switch p {
+	case BotServiceProtocolTCP:
+		return "tcp"
+	case BotServiceProtocolUDP:
+		return "udp"
+	default:
+		return strconv.FormatUint(uint64(p), 10)
+	}
+}
+
+// LoadString loads a BotServiceProtocol from a (case insensitive) string.
+func (p *BotServiceProtocol) LoadString(str string) error {
+	switch strings.ToLower(str) {
+	case "tcp":
+		*p = BotServiceProtocolTCP
+	case "udp":
+		*p = BotServiceProtocolUDP
+	default:
+		return fmt.Errorf("unknown bot service protocol %q", str)
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.MarshalJSON
+func (p BotServiceProtocol) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.UnmarshalJSON
+func (p *BotServiceProtocol) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err != nil {
+		return err
+	}
+	return p.LoadString(str)
+}
+
+// BotService defines a service offered by a 3bot, on a given port and using a given protocol,
+// comparable to how a DNS SRV record defines a service.
+// The service is reachable on the network addresses of the 3bot that defines it.
+type BotService struct {
+	// Name of the service, a lower case name of up to 15 characters (e.g. http, xmpp-client).
+	Name string `json:"name"`
+	// Protocol used to reach the service.
+	Protocol BotServiceProtocol `json:"protocol"`
+	// Port on which the service can be reached.
+	Port uint16 `json:"port"`
+	// Version is the optional version of the (application) protocol spoken by the service.
+	Version string `json:"version,omitempty"`
+}
+
+// Validate returns an error if this BotService is not valid.
+func (bs BotService) Validate() error {
+	if len(bs.Name) == 0 || len(bs.Name) > MaxLengthBotServiceName ||
+		!rexBotServiceName.MatchString(bs.Name) || !rexBotServiceLetter.MatchString(bs.Name) {
+		return fmt.Errorf("%v: invalid name %q", ErrInvalidBotService, bs.Name)
+	}
+	if bs.Protocol != BotServiceProtocolTCP && bs.Protocol != BotServiceProtocolUDP {
+		return fmt.Errorf("%v: unknown protocol %d", ErrInvalidBotService, bs.Protocol)
+	}
+	if bs.Port == 0 {
+		return fmt.Errorf("%v: port is required", ErrInvalidBotService)
+	}
+	if len(bs.Version) > MaxLengthBotServiceVersion ||
+		(bs.Version != "" && !rexBotServiceVersion.MatchString(bs.Version)) {
+		return fmt.Errorf("%v: invalid version %q", ErrInvalidBotService, bs.Version)
+	}
+	return nil
+}
+
+// String returns this BotService in a (human-readable) string format:
+// `<name>/<protocol>:<port>[@<version>]`, e.g. `http/tcp:80@1.1`.
+func (bs BotService) String() string {
+	str := bs.Name + "/" + bs.Protocol.String() + ":" + strconv.FormatUint(uint64(bs.Port), 10)
+	if bs.Version != "" {
+		str += "@" + bs.Version
+	}
+	return str
+}
+
+// LoadString loads (and validates) a BotService from a human-readable string,
+// in the format as returned by the String method.
+func (bs *BotService) LoadString(str string) error {
+	var service BotService
+	if index := strings.IndexByte(str, '@'); index != -1 {
+		service.Version = str[index+1:]
+		if service.Version == "" {
+			return fmt.Errorf("%v: %q defines an empty version", ErrInvalidBotService, str)
+		}
+		str = str[:index]
+	}
+	index := strings.IndexByte(str, ':')
+	if index == -1 {
+		return fmt.Errorf("%v: %q does not define a port", ErrInvalidBotService, str)
+	}
+	port, err := strconv.ParseUint(str[index+1:], 10, 16)
+	if err != nil {
+		return fmt.Errorf("%v: invalid port: %v", ErrInvalidBotService, err)
+	}
+	service.Port = uint16(port)
+	str = str[:index]
+	index = strings.IndexByte(str, '/')
+	if index == -1 {
+		return fmt.Errorf("%v: %q does not define a protocol", ErrInvalidBotService, str)
+	}
+	err = service.Protocol.LoadString(str[index+1:])
+	if err != nil {
+		return fmt.Errorf("%v: %v", ErrInvalidBotService, err)
+	}
+	service.Name = strings.ToLower(str[:index])
+	err = service.Validate()
+	if err != nil {
+		return err
+	}
+	*bs = service
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.UnmarshalJSON,
+// validating the service once decoded.
+func (bs *BotService) UnmarshalJSON(b []byte) error {
+	type botService BotService // used to prevent recursion
+	var service botService
+	err := json.Unmarshal(b, &service)
+	if err != nil {
+		return err
+	}
+	service.Name = strings.ToLower(service.Name)
+	err = BotService(service).Validate()
+	if err != nil {
+		return err
+	}
+	*bs = BotService(service)
+	return nil
+}
+
+// botServiceVersionFlag is the bit of the first byte of an encoded BotService,
+// used to indicate that the service defines a version.
+const botServiceVersionFlag = 1 << 7
+
+// MarshalSia implements SiaMarshaler.MarshalSia,
+// alias of MarshalRivine for backwards-compatibility reasons.
+func (bs BotService) MarshalSia(w io.Writer) error {
+	return bs.MarshalRivine(w)
+}
+
+// UnmarshalSia implements SiaUnmarshaler.UnmarshalSia,
+// alias of UnmarshalRivine for backwards-compatibility reasons.
+func (bs *BotService) UnmarshalSia(r io.Reader) error {
+	return bs.UnmarshalRivine(r)
+}
+
+// MarshalRivine implements RivineMarshaler.MarshalRivine
+//
+// The length of the name (4 bits), the protocol (3 bits) and
+// whether or not a version is defined (1 bit) are encoded in a single prefix byte,
+// followed by the raw name, the port and the (optional) version.
+func (bs BotService) MarshalRivine(w io.Writer) error {
+	if len(bs.Name) > MaxLengthBotServiceName || bs.Protocol > maxBotServiceProtocol {
+		return ErrInvalidBotService
+	}
+	prefix := uint8(len(bs.Name)) | (uint8(bs.Protocol) << 4)
+	if bs.Version != "" {
+		prefix |= botServiceVersionFlag
+	}
+	err := rivbin.MarshalUint8(w, prefix)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write([]byte(bs.Name))
+	if err != nil {
+		return err
+	}
+	enc := rivbin.NewEncoder(w)
+	err = enc.Encode(bs.Port)
+	if err != nil {
+		return err
+	}
+	if bs.Version != "" {
+		return enc.Encode(bs.Version)
+	}
+	return nil
+}
+
+// UnmarshalRivine implements RivineUnmarshaler.UnmarshalRivine
+func (bs *BotService) UnmarshalRivine(r io.Reader) error {
+	prefix, err := rivbin.UnmarshalUint8(r)
+	if err != nil {
+		return err
+	}
+	name := make([]byte, prefix&15)
+	_, err = io.ReadFull(r, name)
+	if err != nil {
+		return err
+	}
+	service := BotService{
+		Name:     string(name),
+		Protocol: BotServiceProtocol((prefix &^ botServiceVersionFlag) >> 4),
+	}
+	dec := rivbin.NewDecoder(r)
+	err = dec.Decode(&service.Port)
+	if err != nil {
+		return err
+	}
+	if prefix&botServiceVersionFlag != 0 {
+		err = dec.Decode(&service.Version)
+		if err != nil {
+			return err
+		}
+	}
+	err = service.Validate()
+	if err != nil {
+		return err
+	}
+	*bs = service
+	return nil
+}
+
+// Equals returns true if this BotService and the given BotService are exactly equal.
+func (bs BotService) Equals(obs BotService) bool {
+	return bs == obs
+}
+
+// Compare returns an integer comparing two bot services by name and protocol,
+// which together define the uniqueness of a service within a single 3bot.
+// The result will be 0 if a==b, -1 if a < b, and +1 if a > b.
+func (bs BotService) Compare(obs BotService) int {
+	if c := strings.Compare(bs.Name, obs.Name); c != 0 {
+		return c
+	}
+	if bs.Protocol < obs.Protocol {
+		return -1
+	}
+	if bs.Protocol > obs.Protocol {
+		return 1
+	}
+	return 0
+}
+
+type (
+	// BotServiceSortedSet represents a sorted set of bot services,
+	// unique by their name and protocol.
+	//
+	// A BotServiceSortedSet only exposes a copy of its elements,
+	// as all it aims for is to ensure the set consists only of unique elements.
+	BotServiceSortedSet struct {
+		slice botServiceSlice
+	}
+	botServiceSlice []BotService
+)
+
+// Len returns the amount of services in this sorted set.
+func (bsss BotServiceSortedSet) Len() int {
+	return bsss.slice.Len()
+}
+
+// Services returns a copy of all services in this sorted set, in sorted order.
+func (bsss BotServiceSortedSet) Services() []BotService {
+	if bsss.slice.Len() == 0 {
+		return nil
+	}
+	services := make([]BotService, bsss.slice.Len())
+	copy(services, bsss.slice)
+	return services
+}
+
+// AddService adds a new (unique) service to this sorted set of services,
+// returning an error if the service is invalid or if a service
+// with the same name and protocol already exists within this sorted set.
+func (bsss *BotServiceSortedSet) AddService(service BotService) error {
+	err := service.Validate()
+	if err != nil {
+		return err
+	}
+	// binary search through our slice,
+	// and if not found return the index where to insert the service as well
+	limit := bsss.slice.Len()
+	index := sort.Search(limit, func(i int) bool {
+		return bsss.slice[i].Compare(service) >= 0
+	})
+	if index < limit && bsss.slice[index].Compare(service) == 0 {
+		return ErrBotServiceNotUnique
+	}
+	// insert the new service in the correct place
+	bsss.slice = append(bsss.slice, BotService{})
+	copy(bsss.slice[index+1:], bsss.slice[index:])
+	bsss.slice[index] = service
+	return nil
+}
+
+// RemoveService removes an existing service from this sorted set of services,
+// returning an error if the service (with the exact same properties) does not exist in this sorted set.
+func (bsss *BotServiceSortedSet) RemoveService(service BotService) error {
+	limit := bsss.slice.Len()
+	index := sort.Search(limit, func(i int) bool {
+		return bsss.slice[i].Compare(service) >= 0
+	})
+	if index >= limit || !bsss.slice[index].Equals(service) {
+		return ErrBotServiceDoesNotExist
+	}
+	copy(bsss.slice[index:], bsss.slice[index+1:])
+	bsss.slice[bsss.slice.Len()-1] = BotService{}
+	bsss.slice = bsss.slice[:bsss.slice.Len()-1]
+	return nil
+}
+
+// MarshalJSON implements encoding/json.Marshaler.MarshalJSON
+func (bsss BotServiceSortedSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(bsss.slice)
+}
+
+// UnmarshalJSON implements encoding/json.Unmarshaler.UnmarshalJSON
+func (bsss *BotServiceSortedSet) UnmarshalJSON(data []byte) error {
+	// decode the slice
+	var slice botServiceSlice
+	err := json.Unmarshal(data, &slice)
+	if err != nil {
+		return err
+	}
+	return bsss.loadSlice(slice)
+}
+
+// MarshalSia implements siabin.SiaMarshaler.MarshalSia
+func (bsss BotServiceSortedSet) MarshalSia(w io.Writer) error {
+	return siabin.NewEncoder(w).Encode(bsss.slice)
+}
+
+// UnmarshalSia implements siabin.SiaUnmarshaler.UnmarshalSia
+func (bsss *BotServiceSortedSet) UnmarshalSia(r io.Reader) error {
+	// decode the slice
+	var slice botServiceSlice
+	err := siabin.NewDecoder(r).Decode(&slice)
+	if err != nil {
+		return err
+	}
+	return bsss.loadSlice(slice)
+}
+
+// MarshalRivine implements rivbin.RivineMarshaler.MarshalRivine
+func (bsss BotServiceSortedSet) MarshalRivine(w io.Writer) error {
+	return rivbin.NewEncoder(w).Encode(bsss.slice)
+}
+
+// UnmarshalRivine implements rivbin.RivineUnmarshaler.UnmarshalRivine
+func (bsss *BotServiceSortedSet) UnmarshalRivine(r io.Reader) error {
+	// decode the slice
+	var slice botServiceSlice
+	err := rivbin.NewDecoder(r).Decode(&slice)
+	if err != nil {
+		return err
+	}
+	return bsss.loadSlice(slice)
+}
+
+// BinaryEncode can be used instead of MarshalRivine, should one want to
+// encode the length prefix in a way other than the standard tfchain-slice approach.
+// The encoding of the length has to happen prior to calling this method.
+func (bsss BotServiceSortedSet) BinaryEncode(w io.Writer) (int, error) {
+	var (
+		err     error
+		encoder = rivbin.NewEncoder(w)
+	)
+	for _, service := range bsss.slice {
+		err = encoder.Encode(service)
+		if err != nil {
+			return -1, err
+		}
+	}
+	return bsss.slice.Len(), nil
+}
+
+// BinaryDecode can be used instead of UnmarshalRivine, should one need to
+// decode the length prefix in a way other than the standard tfchain-slice approach.
+// The decoding of the length has to happen prior to calling this method.
+func (bsss *BotServiceSortedSet) BinaryDecode(r io.Reader, length int) error {
+	var (
+		err     error
+		decoder = rivbin.NewDecoder(r)
+	)
+	// allocate suffecient memory (and erase) our internal slice
+	bsss.slice = make(botServiceSlice, 0, length)
+	// add the elements on by one, guaranteeing the services are in order and unique
+	for i := 0; i < length; i++ {
+		var service BotService
+		err = decoder.Decode(&service)
+		if err != nil {
+			return err
+		}
+		err = bsss.AddService(service)
+		if err != nil {
+			return fmt.Errorf("error while unmarshaling service %v: %v", service, err)
+		}
+	}
+	return nil
+}
+
+func (bsss *BotServiceSortedSet) loadSlice(slice botServiceSlice) error {
+	// allocate suffecient memory (and erase) our internal slice
+	bsss.slice = make(botServiceSlice, 0, len(slice))
+	// add the elements on by one, guaranteeing the services are in order and unique
+	for _, service := range slice {
+		err := bsss.AddService(service)
+		if err != nil {
+			return fmt.Errorf("error while unmarshaling service %v: %v", service, err)
+		}
+	}
+	return nil
+}
+
+// Len implements sort.Interface.Len
+func (slice botServiceSlice) Len() int {
+	return len(slice)
+}
+
+// Less implements sort.Interface.Less
+func (slice botServiceSlice) Less(i, j int) bool {
+	return slice[i].Compare(slice[j]) == -1
+}
+
+// Swap implements sort.Interface.Swap
+func (slice botServiceSlice) Swap(i, j int) {
+	slice[i], slice[j] = slice[j], slice[i]
+}
@@ -0,0 +1,273 @@
+package types
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+)
+
+func TestBotServiceLoadStringString(t *testing.T) {
+	testCases := []struct {
+		Input    string
+		Expected BotService
+		Output   string
+	}{
+		{"http/tcp:80", BotService{Name: "http", Protocol: BotServiceProtocolTCP, Port: 80}, "http/tcp:80"},
+		{"HTTP/TCP:8080@1.1", BotService{Name: "http", Protocol: BotServiceProtocolTCP, Port: 8080, Version: "1.1"}, "http/tcp:8080@1.1"},
+		{"xmpp-client/udp:5222@v2.0-beta+1", BotService{Name: "xmpp-client", Protocol: BotServiceProtocolUDP, Port: 5222, Version: "v2.0-beta+1"}, "xmpp-client/udp:5222@v2.0-beta+1"},
+		{"s3/tcp:65535", BotService{Name: "s3", Protocol: BotServiceProtocolTCP, Port: 65535}, "s3/tcp:65535"},
+	}
+	for idx, testCase := range testCases {
+		var service BotService
+		err := service.LoadString(testCase.Input)
+		if err != nil {
+			t.Error(idx, "unexpected error:", err)
+			continue
+		}
+		if !service.Equals(testCase.Expected) {
+			t.Error(idx, "unexpected service:", service, "!=", testCase.Expected)
+		}
+		if str := service.String(); str != testCase.Output {
+			t.Error(idx, "unexpected string:", str, "!=", testCase.Output)
+		}
+	}
+}
+
+func TestBotServiceLoadInvalidStrings(t *testing.T) {
+	testCases := []string{
+		"",
+		"http",
+		"http/tcp",
+		"http:80",
+		"http/sctp:80",
+		"http/tcp:0",
+		"http/tcp:65536",
+		"/tcp:80",
+		"-http/tcp:80",
+		"http-/tcp:80",
+		"ht--tp/tcp:80",
+		"80/tcp:80",               // at least one letter is required
+		"a-very-long-name/tcp:80", // max 15 characters
+		"http/tcp:80@",            // an empty version has to be omitted
+		"http/tcp:80@1 1",         // no spaces allowed
+		"http/tcp:80@.1",          // version has to start with an alphanumeric character
+		"http/tcp:80@1.0.0-a-very-very-long-prerelease", // max 31 characters
+	}
+	for idx, str := range testCases {
+		var service BotService
+		err := service.LoadString(str)
+		if err == nil {
+			t.Error(idx, "expected error for loading invalid string", str, "but received none")
+		}
+	}
+}
+
+func TestBotServiceJSON(t *testing.T) {
+	const str = `{"name":"http","protocol":"tcp","port":80,"version":"1.1"}`
+	var service BotService
+	err := json.Unmarshal([]byte(str), &service)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (BotService{Name: "http", Protocol: BotServiceProtocolTCP, Port: 80, Version: "1.1"}); !service.Equals(expected) {
+		t.Fatal("unexpected service:", service, "!=", expected)
+	}
+	b, err := json.Marshal(service)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != str {
+		t.Fatal("unexpected JSON:", string(b), "!=", str)
+	}
+
+	// invalid services cannot be decoded
+	for _, invalid := range []string{
+		`{"name":"http","protocol":"sctp","port":80}`,
+		`{"name":"http","protocol":"tcp"}`,
+		`{"name":"http_s","protocol":"tcp","port":443}`,
+	} {
+		err = json.Unmarshal([]byte(invalid), &service)
+		if err == nil {
+			t.Error("succeeded to decode invalid service", invalid)
+		}
+	}
+}
+
+func TestBotServiceBinaryEncoding(t *testing.T) {
+	testCases := []struct {
+		Service BotService
+		Hex     string
+	}{
+		// prefix (name length, protocol, version flag), raw name and port
+		{BotService{Name: "http", Protocol: BotServiceProtocolTCP, Port: 80}, "04" + "68747470" + "5000"},
+		{BotService{Name: "dns", Protocol: BotServiceProtocolUDP, Port: 53}, "13" + "646e73" + "3500"},
+		// followed by the version, only if defined
+		{BotService{Name: "http", Protocol: BotServiceProtocolTCP, Port: 443, Version: "2"}, "84" + "68747470" + "bb01" + "0232"},
+	}
+	for idx, testCase := range testCases {
+		b := rivbin.Marshal(testCase.Service)
+		if result := hex.EncodeToString(b); result != testCase.Hex {
+			t.Error(idx, "unexpected binary encoding:", result, "!=", testCase.Hex)
+			continue
+		}
+		var service BotService
+		err := rivbin.Unmarshal(b, &service)
+		if err != nil {
+			t.Error(idx, err)
+			continue
+		}
+		if !service.Equals(testCase.Service) {
+			t.Error(idx, "unexpected decoded service:", service, "!=", testCase.Service)
+		}
+	}
+
+	// invalid services cannot be decoded
+	b, err := hex.DecodeString("04" + "48545450" + "5000") // upper case name
+	if err != nil {
+		t.Fatal(err)
+	}
+	var service BotService
+	err = rivbin.Unmarshal(b, &service)
+	if err == nil {
+		t.Fatal("succeeded to decode an invalid service:", service)
+	}
+}
+
+func TestBotServiceSortedSet(t *testing.T) {
+	var bsss BotServiceSortedSet
+	services := []BotService{
+		{Name: "xmpp-client", Protocol: BotServiceProtocolTCP, Port: 5222},
+		{Name: "http", Protocol: BotServiceProtocolUDP, Port: 443},
+		{Name: "http", Protocol: BotServiceProtocolTCP, Port: 80, Version: "1.1"},
+	}
+	for idx, service := range services {
+		err := bsss.AddService(service)
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+	}
+	if s := bsss.Len(); s != 3 {
+		t.Fatal("unexpected set length:", s)
+	}
+
+	// services are sorted by name and protocol
+	sorted := bsss.Services()
+	for idx, expected := range []BotService{services[2], services[1], services[0]} {
+		if !sorted[idx].Equals(expected) {
+			t.Error(idx, "unexpected service:", sorted[idx], "!=", expected)
+		}
+	}
+
+	// a service is unique by its name and protocol
+	err := bsss.AddService(BotService{Name: "http", Protocol: BotServiceProtocolTCP, Port: 8080})
+	if err != ErrBotServiceNotUnique {
+		t.Fatal("unexpected error while adding a non-unique service:", err)
+	}
+	// but only removed when all its properties are equal
+	err = bsss.RemoveService(BotService{Name: "http", Protocol: BotServiceProtocolTCP, Port: 80})
+	if err != ErrBotServiceDoesNotExist {
+		t.Fatal("unexpected error while removing a service with a different version:", err)
+	}
+	err = bsss.RemoveService(services[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := bsss.Len(); s != 2 {
+		t.Fatal("unexpected set length:", s)
+	}
+
+	// JSON and binary encoding preserve the set
+	b, err := json.Marshal(bsss)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded BotServiceSortedSet
+	err = json.Unmarshal(b, &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rivbin.Marshal(decoded), rivbin.Marshal(bsss)) {
+		t.Fatal("unexpected JSON-decoded set:", decoded, "!=", bsss)
+	}
+	decoded = BotServiceSortedSet{}
+	err = rivbin.Unmarshal(rivbin.Marshal(bsss), &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rivbin.Marshal(decoded), rivbin.Marshal(bsss)) {
+		t.Fatal("unexpected binary-decoded set:", decoded, "!=", bsss)
+	}
+}
+
+func TestBotRecordServicesBinaryEncoding(t *testing.T) {
+	b, err := hex.DecodeString(minimalHexEncodedBinaryBotRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var record BotRecord
+	err = rivbin.Unmarshal(b, &record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a record without services is not expected to list them in JSON
+	jb, err := json.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(jb, []byte(`"services"`)) {
+		t.Fatal("unexpected services in JSON record:", string(jb))
+	}
+
+	err = record.AddServices(
+		BotService{Name: "http", Protocol: BotServiceProtocolTCP, Port: 80},
+		BotService{Name: "dns", Protocol: BotServiceProtocolUDP, Port: 53},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the services are encoded right after the names, with their amount in the extension byte
+	expected := `00000000` + // first bot, index 0
+		`81` + // 1 => 0 names and 1 addr, extension flag
+		`02` + // 2 services, no owner
+		`117F000001` + // IPv4 => 127.0.0.1
+		`13646e733500` + // dns/udp:53
+		`04687474705000` + // http/tcp:80
+		`014683705f729a65e9e133e1719d05ad8ac45a14e44fcf6c85de19e5ac7fcd2e9d` + // ed25519 pub key
+		`7AF905` // some data
+	if result := rivbin.Marshal(record); !bytes.Equal(result, mustDecodeHex(t, expected)) {
+		t.Fatalf("unexpected binary encoding of record with services: %x", result)
+	}
+
+	var decodedRecord BotRecord
+	err = rivbin.Unmarshal(rivbin.Marshal(record), &decodedRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rivbin.Marshal(decodedRecord.Services), rivbin.Marshal(record.Services)) || decodedRecord.Owner != nil {
+		t.Fatal("unexpected decoded record:", decodedRecord)
+	}
+
+	// a record can have no more than 8 services
+	for _, name := range []string{"aaaaa", "bbbbb", "ccccc", "ddddd", "eeeee", "fffff"} {
+		err = record.AddServices(BotService{Name: name, Protocol: BotServiceProtocolTCP, Port: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = record.AddServices(BotService{Name: "ggggg", Protocol: BotServiceProtocolTCP, Port: 1})
+	if err != ErrTooManyBotServices {
+		t.Fatal("unexpected error while adding a 9th service:", err)
+	}
+}
+
+func mustDecodeHex(t *testing.T, str string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(str)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
 )
 
 func TestBotIDLoadEmptyString(t *testing.T) {
@@ -226,6 +227,186 @@ func TestMinimalHexEncodedBinaryBotRecord(t *testing.T) {
 	}
 }
 
+func TestBotRecordOwnerBinaryEncoding(t *testing.T) {
+	b, err := hex.DecodeString(minimalHexEncodedBinaryBotRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var record BotRecord
+	err = rivbin.Unmarshal(b, &record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a record without owner should encode exactly as before
+	if result := rivbin.Marshal(record); !bytes.Equal(b, result) {
+		t.Fatalf("unexpected binary encoding of record without owner: %x != %x", result, b)
+	}
+	if uh := record.OwnerCondition().UnlockHash(); uh.Cmp(types.NewPubKeyUnlockHash(record.PublicKey)) != 0 {
+		t.Fatal("unexpected owner condition of record without owner:", uh.String())
+	}
+
+	// a record with owner should encode and decode the owner as well
+	record.Owner = &types.UnlockConditionProxy{
+		Condition: types.NewMultiSignatureCondition(types.UnlockHashSlice{
+			types.NewPubKeyUnlockHash(record.PublicKey),
+			types.NewPubKeyUnlockHash(cryptoKeyPair.PublicKey),
+		}, 2),
+	}
+	var decodedRecord BotRecord
+	err = rivbin.Unmarshal(rivbin.Marshal(record), &decodedRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decodedRecord.Owner == nil || !decodedRecord.Owner.Equal(record.Owner.Condition) {
+		t.Fatal("unexpected decoded owner:", decodedRecord.Owner)
+	}
+	if !reflect.DeepEqual(record.Addresses, decodedRecord.Addresses) || decodedRecord.Expiration != record.Expiration {
+		t.Fatal("unexpected decoded record:", decodedRecord)
+	}
+
+	// decoding a record without owner should reset the owner
+	err = rivbin.Unmarshal(b, &decodedRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decodedRecord.Owner != nil {
+		t.Fatal("unexpected owner of decoded record:", decodedRecord.Owner)
+	}
+}
+
+func TestBotRecordStatusAndRenewal(t *testing.T) {
+	const expiration = CompactTimestamp(1550000040)
+	newRecord := func() BotRecord {
+		record := botRecordFromJSON(t, `{
+	"id": 1,
+	"names": ["example"],
+	"publickey": "ed25519:00bde9571b30e1742c41fcca8c730183402d967df5b17b5f4ced22c677806614",
+	"expiration": 1550000040
+}`)
+		if record.Expiration != expiration {
+			t.Fatal("unexpected record expiration:", record.Expiration)
+		}
+		return record
+	}
+
+	testCases := []struct {
+		BlockTime   types.Timestamp
+		GracePeriod CompactTimestamp
+		Status      BotRecordStatus
+		String      string
+	}{
+		{expiration.SiaTimestamp() - 1, BotNameGracePeriod, BotRecordStatusActive, "active"},
+		{expiration.SiaTimestamp(), BotNameGracePeriod, BotRecordStatusGrace, "grace"},
+		{(expiration + BotNameGracePeriod).SiaTimestamp() - 1, BotNameGracePeriod, BotRecordStatusGrace, "grace"},
+		{(expiration + BotNameGracePeriod).SiaTimestamp(), BotNameGracePeriod, BotRecordStatusReleased, "released"},
+		// prior to the activation of the grace period, the names are released as soon as the bot expires
+		{expiration.SiaTimestamp() - 1, 0, BotRecordStatusActive, "active"},
+		{expiration.SiaTimestamp(), 0, BotRecordStatusReleased, "released"},
+	}
+	for idx, testCase := range testCases {
+		record := newRecord()
+		status := record.Status(testCase.BlockTime, testCase.GracePeriod)
+		if status != testCase.Status {
+			t.Error(idx, "unexpected status:", status, "!=", testCase.Status)
+			continue
+		}
+		b, err := json.Marshal(status)
+		if err != nil {
+			t.Error(idx, err)
+			continue
+		}
+		if expected := `"` + testCase.String + `"`; string(b) != expected {
+			t.Error(idx, "unexpected JSON-encoded status:", string(b), "!=", expected)
+		}
+		var decodedStatus BotRecordStatus
+		err = json.Unmarshal(b, &decodedStatus)
+		if err != nil || decodedStatus != status {
+			t.Error(idx, "unexpected JSON-decoded status:", decodedStatus, err)
+		}
+	}
+
+	// renewing a bot within its grace period keeps its names,
+	// and extends the expiration starting from the time the bot expired
+	record := newRecord()
+	blockTime := (expiration + BotMonth/2).SiaTimestamp()
+	update := BotRecordUpdateTransaction{NrOfMonths: 1}
+	err := update.UpdateBotRecord(blockTime, BotNameGracePeriod, &record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Names.Len() != 1 || record.Expiration != expiration+BotMonth || record.IsExpired(blockTime) {
+		t.Fatal("unexpected record renewed within grace period:", record)
+	}
+	// prior to the activation of the grace period, the same renewal resets its names,
+	// and extends the expiration starting from the current time
+	record = newRecord()
+	err = update.UpdateBotRecord(blockTime, 0, &record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Names.Len() != 0 || record.Expiration != SiaTimestampAsCompactTimestamp(blockTime)+BotMonth {
+		t.Fatal("unexpected record renewed prior to the activation of the grace period:", record)
+	}
+
+	// renewing a bot after its grace period resets its names,
+	// and extends the expiration starting from the current time
+	record = newRecord()
+	blockTime = (expiration + BotNameGracePeriod).SiaTimestamp()
+	err = update.UpdateBotRecord(blockTime, BotNameGracePeriod, &record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Names.Len() != 0 || record.Expiration != SiaTimestampAsCompactTimestamp(blockTime)+BotMonth {
+		t.Fatal("unexpected record renewed after grace period:", record)
+	}
+
+	// an expired bot has to be renewed, also within its grace period
+	record = newRecord()
+	update = BotRecordUpdateTransaction{Addresses: BotRecordAddressUpdate{Add: []NetworkAddress{mustNewNetworkAddress(t, "example.org")}}}
+	err = update.UpdateBotRecord(expiration.SiaTimestamp(), BotNameGracePeriod, &record)
+	if err == nil {
+		t.Fatal("succeeded to update an expired bot without renewing it")
+	}
+}
+
+func TestBotRecordMaxExtendableMonths(t *testing.T) {
+	const expiration = CompactTimestamp(1550000040)
+	testCases := []struct {
+		BlockTime types.Timestamp
+		Months    uint8
+	}{
+		// an active bot can be extended up to the maximum amount of prepaid months from now
+		{(expiration - BotMonth).SiaTimestamp(), MaxBotPrepaidMonths - 1},
+		{(expiration - BotMonth*MaxBotPrepaidMonths).SiaTimestamp(), 0},
+		{(expiration - BotMonth*MaxBotPrepaidMonths - 60).SiaTimestamp(), 0},
+		// while an expired bot can always be extended with the maximum amount of prepaid months
+		{expiration.SiaTimestamp(), MaxBotPrepaidMonths},
+		{(expiration + BotNameGracePeriod/2).SiaTimestamp(), MaxBotPrepaidMonths},
+		{(expiration + BotNameGracePeriod).SiaTimestamp(), MaxBotPrepaidMonths},
+	}
+	for idx, testCase := range testCases {
+		record := BotRecord{ID: 1, Expiration: expiration}
+		months := record.MaxExtendableMonths(testCase.BlockTime, BotNameGracePeriod)
+		if months != testCase.Months {
+			t.Error(idx, "unexpected amount of extendable months:", months, "!=", testCase.Months)
+			continue
+		}
+		if months == 0 {
+			continue
+		}
+		// the record can be extended with the returned amount of months, but not with more
+		err := record.ExtendExpirationDate(testCase.BlockTime, BotNameGracePeriod, months)
+		if err != nil {
+			t.Error(idx, "failed to extend record with the extendable months:", err)
+		}
+		record.Expiration = expiration
+		err = record.ExtendExpirationDate(testCase.BlockTime, BotNameGracePeriod, months+1)
+		if err == nil {
+			t.Error(idx, "succeeded to extend record with more than the extendable months")
+		}
+	}
+}
+
 func TestBotNameSortedSet(t *testing.T) {
 	var bnss BotNameSortedSet
 	if s := bnss.Len(); s != 0 {
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/threefoldtech/rivine/types"
@@ -38,6 +39,59 @@ func validateUniquenessOfBotNames(names []BotName) error {
 	return nil
 }
 
+// validateBotSubNames validates that the bot with the given identifier is authorized to own the given names,
+// which is always the case for a top-level name. A sub name (e.g. foo.example) on the other hand
+// requires the parent name (e.g. example) to be owned by the same bot, as part of the given (resulting) names of that bot,
+// or to be delegated to that bot by the active bot that owns the parent name.
+// The given identifier is 0 for a bot that is still to be registered, which as such can only register
+// sub names of the names it registers itself, as delegates are identified by their (existing) identifier.
+// A delegated sub name can therefore only be claimed by a registered bot, using an update, transfer or sale.
+func validateBotSubNames(registry BotRecordReadRegistry, id BotID, names BotNameSortedSet, subNames ...BotName) error {
+	for _, name := range subNames {
+		parent, ok := name.Parent()
+		if !ok || names.Contains(parent) {
+			continue // top-level name, or the parent is owned by the bot itself
+		}
+		_, err := registry.GetRecordForName(parent)
+		switch err {
+		case nil:
+		case ErrBotNameNotFound, ErrBotNameExpired, ErrBotNameInGracePeriod:
+			return fmt.Errorf("invalid bot name %v: %v", name, ErrBotParentNameNotFound)
+		default:
+			return fmt.Errorf("unexpected error while looking up owner of parent bot name %v: %v", parent, err)
+		}
+		delegates, err := registry.GetDelegatesForName(parent)
+		if err != nil {
+			return fmt.Errorf("unexpected error while looking up delegates of parent bot name %v: %v", parent, err)
+		}
+		if !isBotIDInSlice(id, delegates) {
+			return fmt.Errorf("invalid bot name %v: %v", name, ErrBotSubNameNotAuthorized)
+		}
+	}
+	return nil
+}
+
+func isBotIDInSlice(id BotID, ids []BotID) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
+
+func validateUniquenessOfBotIDs(ids []BotID) error {
+	dm := make(map[BotID]struct{}, len(ids))
+	var exists bool
+	for _, id := range ids {
+		if _, exists = dm[id]; exists {
+			return fmt.Errorf("bot ID %d is not unique within the given slice", id)
+		}
+		dm[id] = struct{}{}
+	}
+	return nil
+}
+
 func validateBotSignature(t types.Transaction, publicKey types.PublicKey, signature types.ByteSlice, ctx types.ValidationContext, extraObjects ...interface{}) error {
 	condition := types.NewCondition(types.NewUnlockHashCondition(types.NewPubKeyUnlockHash(publicKey)))
 	// and a matching single-signature fulfillment
@@ -53,3 +107,47 @@ func validateBotSignature(t types.Transaction, publicKey types.PublicKey, signat
 		Transaction:  t,
 	})
 }
+
+// validateBotRecordSignature validates the given signature for the given record,
+// which is only possible for a record that is owned by its public key.
+func validateBotRecordSignature(t types.Transaction, record *BotRecord, signature types.ByteSlice, ctx types.ValidationContext, extraObjects ...interface{}) error {
+	if record.Owner != nil {
+		return ErrBotOwnedByCondition
+	}
+	return validateBotSignature(t, record.PublicKey, signature, ctx, extraObjects...)
+}
+
+// validateBotRecordFulfillment validates the given fulfillment fulfills the owner condition of the given record.
+func validateBotRecordFulfillment(t types.Transaction, record *BotRecord, fulfillment types.UnlockFulfillmentProxy, ctx types.ValidationContext, extraObjects ...interface{}) error {
+	err := fulfillment.IsStandardFulfillment(ctx)
+	if err != nil {
+		return fmt.Errorf("bot owner fulfillment is not standard: %v", err)
+	}
+	return record.OwnerCondition().Fulfill(fulfillment, types.FulfillContext{
+		ExtraObjects: extraObjects,
+		BlockHeight:  ctx.BlockHeight,
+		BlockTime:    ctx.BlockTime,
+		Transaction:  t,
+	})
+}
+
+// validateBotOwnerCondition validates the given condition can be used as the owner of a bot record,
+// which is the case for standard PubKey-UnlockHash and MultiSignature conditions.
+func validateBotOwnerCondition(condition types.UnlockConditionProxy, ctx types.ValidationContext) error {
+	err := condition.IsStandardCondition(ctx)
+	if err != nil {
+		return fmt.Errorf("bot owner condition is not standard: %v", err)
+	}
+	switch ct := condition.ConditionType(); ct {
+	case types.ConditionTypeMultiSignature:
+		return nil
+	case types.ConditionTypeUnlockHash:
+		// only valid for unlock hash type 1 (PubKey)
+		if condition.UnlockHash().Type == types.UnlockTypePubKey {
+			return nil
+		}
+		return errors.New("unlockHash conditions can be used as bot owner conditions, if the unlock hash type is PubKey")
+	default:
+		return fmt.Errorf("condition type %d cannot be used as a bot owner condition", ct)
+	}
+}
@@ -105,8 +105,9 @@ const (
 func TestValidateBotSignature_Correct(t *testing.T) {
 	// define tfchain-specific transaction versions
 	types.RegisterTransactionVersion(TransactionVersionBotRegistration, BotRegistrationTransactionController{
-		Registry: nil,
-		OneCoin:  config.GetCurrencyUnits().OneCoin,
+		Registry:                 nil,
+		OneCoin:                  config.GetCurrencyUnits().OneCoin,
+		ConsensusParameterGetter: staticConsensusParameterGetter(GetDevnetGenesisConsensusParameters()),
 	})
 	defer types.RegisterTransactionVersion(TransactionVersionBotRegistration, nil)
 
@@ -142,8 +143,9 @@ func TestValidateBotSignature_Correct(t *testing.T) {
 func TestValidateBotSignature_Error(t *testing.T) {
 	// define tfchain-specific transaction versions
 	types.RegisterTransactionVersion(TransactionVersionBotRegistration, BotRegistrationTransactionController{
-		Registry: nil,
-		OneCoin:  config.GetCurrencyUnits().OneCoin,
+		Registry:                 nil,
+		OneCoin:                  config.GetCurrencyUnits().OneCoin,
+		ConsensusParameterGetter: staticConsensusParameterGetter(GetDevnetGenesisConsensusParameters()),
 	})
 	defer types.RegisterTransactionVersion(TransactionVersionBotRegistration, nil)
 
@@ -185,8 +187,9 @@ func TestValidateBotSignature_Error(t *testing.T) {
 func TestValidateBotSignature_InvalidPublicKey(t *testing.T) {
 	// define tfchain-specific transaction versions
 	types.RegisterTransactionVersion(TransactionVersionBotRegistration, BotRegistrationTransactionController{
-		Registry: nil,
-		OneCoin:  config.GetCurrencyUnits().OneCoin,
+		Registry:                 nil,
+		OneCoin:                  config.GetCurrencyUnits().OneCoin,
+		ConsensusParameterGetter: staticConsensusParameterGetter(GetDevnetGenesisConsensusParameters()),
 	})
 	defer types.RegisterTransactionVersion(TransactionVersionBotRegistration, nil)
 
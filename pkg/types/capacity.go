@@ -0,0 +1,164 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+const (
+	// MinFarmID defines the minimum value a FarmID can have,
+	// in other words the smallest identifier value a farm can have.
+	MinFarmID = 1
+	// MaxFarmID defines the maximum value a FarmID can have,
+	// in other words the biggest identifier value a farm can have.
+	MaxFarmID = math.MaxUint32
+)
+
+var (
+	// ErrNilCapacity is the error returned in case a capacity specification
+	// is validated, which does not define any capacity unit at all.
+	ErrNilCapacity = errors.New("capacity specification has to define at least one capacity unit")
+	// ErrInvalidCapacity is the error returned in case a capacity specification
+	// is validated, which defines memory or storage capacity without defining any compute capacity.
+	ErrInvalidCapacity = errors.New("capacity specification has to define at least one compute unit (CRU)")
+)
+
+type (
+	// FarmID defines the identifier type for farms,
+	// each farm has a unique identifier.
+	FarmID uint32
+)
+
+// LoadString loads a FarmID from a string.
+func (id *FarmID) LoadString(str string) error {
+	x, err := strconv.ParseUint(str, 10, 32)
+	if err != nil {
+		return fmt.Errorf("FarmID: %v", err)
+	}
+	if x < MinFarmID {
+		return fmt.Errorf("farmID has to be at least %d", MinFarmID)
+	}
+	*id = FarmID(x)
+	return nil
+}
+
+// String implements fmt.Stringer.String
+func (id FarmID) String() string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+type (
+	// CapacitySpecification defines the entire capacity of a node,
+	// expressed in the different resource units of the threefold grid.
+	CapacitySpecification struct {
+		// CRU defines the compute resource units, the amount of (virtual) CPU cores.
+		CRU uint64 `json:"cru"`
+		// MRU defines the memory resource units, the amount of memory in GB.
+		MRU uint64 `json:"mru"`
+		// HRU defines the HDD resource units, the amount of HDD storage in GB.
+		HRU uint64 `json:"hru"`
+		// SRU defines the SSD resource units, the amount of SSD storage in GB.
+		SRU uint64 `json:"sru"`
+	}
+)
+
+// Validate the capacity specification, returning an error if the specification is invalid.
+func (cs CapacitySpecification) Validate() error {
+	if cs.IsNil() {
+		return ErrNilCapacity
+	}
+	if cs.CRU == 0 {
+		return ErrInvalidCapacity
+	}
+	return nil
+}
+
+// IsNil returns true if no capacity is defined at all.
+func (cs CapacitySpecification) IsNil() bool {
+	return cs == CapacitySpecification{}
+}
+
+// String implements fmt.Stringer.String
+func (cs CapacitySpecification) String() string {
+	return fmt.Sprintf("CRU=%d MRU=%d HRU=%d SRU=%d", cs.CRU, cs.MRU, cs.HRU, cs.SRU)
+}
+
+// MarshalSia implements SiaMarshaler.MarshalSia,
+// alias of MarshalRivine for backwards-compatibility reasons.
+func (cs CapacitySpecification) MarshalSia(w io.Writer) error {
+	return cs.MarshalRivine(w)
+}
+
+// UnmarshalSia implements SiaUnmarshaler.UnmarshalSia,
+// alias of UnmarshalRivine for backwards-compatibility reasons.
+func (cs *CapacitySpecification) UnmarshalSia(r io.Reader) error {
+	return cs.UnmarshalRivine(r)
+}
+
+// MarshalRivine implements RivineMarshaler.MarshalRivine
+func (cs CapacitySpecification) MarshalRivine(w io.Writer) error {
+	return rivbin.NewEncoder(w).EncodeAll(cs.CRU, cs.MRU, cs.HRU, cs.SRU)
+}
+
+// UnmarshalRivine implements RivineUnmarshaler.UnmarshalRivine
+func (cs *CapacitySpecification) UnmarshalRivine(r io.Reader) error {
+	return rivbin.NewDecoder(r).DecodeAll(&cs.CRU, &cs.MRU, &cs.HRU, &cs.SRU)
+}
+
+type (
+	// CapacityRecord is the record type used to store a capacity registration of a node in the TransactionDB.
+	// Each capacity registration of a node creates a new record, the latest record defines the active
+	// capacity and farm of that node, while the others define the registration history of that node.
+	CapacityRecord struct {
+		// Node defines the public key that identifies the node
+		Node types.PublicKey `json:"node"`
+		// Farm the node belongs to
+		Farm FarmID `json:"farm"`
+		// Capacity that is registered for the node
+		Capacity CapacitySpecification `json:"capacity"`
+		// TransactionID of the transaction that registered this capacity
+		TransactionID types.TransactionID `json:"txid"`
+		// BlockHeight of the block that contains the registration transaction
+		BlockHeight types.BlockHeight `json:"blockheight"`
+	}
+)
+
+// MarshalSia implements SiaMarshaler.MarshalSia,
+// alias of MarshalRivine for backwards-compatibility reasons.
+func (record CapacityRecord) MarshalSia(w io.Writer) error {
+	return record.MarshalRivine(w)
+}
+
+// UnmarshalSia implements SiaUnmarshaler.UnmarshalSia,
+// alias of UnmarshalRivine for backwards-compatibility reasons.
+func (record *CapacityRecord) UnmarshalSia(r io.Reader) error {
+	return record.UnmarshalRivine(r)
+}
+
+// MarshalRivine implements RivineMarshaler.MarshalRivine
+func (record CapacityRecord) MarshalRivine(w io.Writer) error {
+	return rivbin.NewEncoder(w).EncodeAll(
+		record.Node,
+		record.Farm,
+		record.Capacity,
+		record.TransactionID,
+		record.BlockHeight,
+	)
+}
+
+// UnmarshalRivine implements RivineUnmarshaler.UnmarshalRivine
+func (record *CapacityRecord) UnmarshalRivine(r io.Reader) error {
+	return rivbin.NewDecoder(r).DecodeAll(
+		&record.Node,
+		&record.Farm,
+		&record.Capacity,
+		&record.TransactionID,
+		&record.BlockHeight,
+	)
+}
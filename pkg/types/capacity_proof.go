@@ -0,0 +1,150 @@
+package types
+
+import (
+	"errors"
+	"io"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+const (
+	// CapacityProofChallengeInterval defines the interval (in blocks) at which
+	// all registered nodes are challenged to prove their capacity.
+	// Every block with a height that is a multiple of this interval is a challenge block.
+	CapacityProofChallengeInterval = 720
+	// CapacityProofResponseWindow defines the amount of blocks, following a challenge block,
+	// during which a node can respond to that challenge. A node that did not respond
+	// within this window is considered to have failed the challenge.
+	CapacityProofResponseWindow = 60
+	// CapacityProofMaxConsecutiveFailures defines the amount of consecutive challenges
+	// a node can fail before it loses its verified status.
+	CapacityProofMaxConsecutiveFailures = 3
+)
+
+var (
+	// SpecifierCapacityChallenge is the specifier used to derive the seed of a capacity challenge.
+	SpecifierCapacityChallenge = types.Specifier{'c', 'a', 'p', 'a', 'c', 'i', 't', 'y', ' ', 'c', 'h', 'a', 'l', 'l'}
+)
+
+var (
+	// ErrCapacityChallengeNotFound is the error returned in case
+	// no capacity challenge exists for a given block height.
+	ErrCapacityChallengeNotFound = errors.New("capacity challenge not found")
+	// ErrCapacityProofResultNotFound is the error returned in case
+	// no capacity proof result exists for a given node and challenge.
+	ErrCapacityProofResultNotFound = errors.New("capacity proof result not found")
+)
+
+// IsCapacityChallengeHeight returns true if the block at the given height is a capacity challenge block.
+func IsCapacityChallengeHeight(height types.BlockHeight) bool {
+	return height > 0 && height%CapacityProofChallengeInterval == 0
+}
+
+type (
+	// CapacityChallenge defines the challenge a node has to respond to,
+	// in order to prove it still provides the capacity registered for it.
+	// It is derived deterministically from the ID of a challenge block and the key of the node,
+	// such that anyone can compute it, while no node can respond to it ahead of time.
+	CapacityChallenge struct {
+		// BlockHeight is the height of the challenge block.
+		BlockHeight types.BlockHeight `json:"blockheight"`
+		// BlockID is the ID of the challenge block.
+		BlockID types.BlockID `json:"blockid"`
+		// Node is the public key of the challenged node.
+		Node types.PublicKey `json:"node"`
+		// Seed is the unique value the node has to sign in order to respond to this challenge.
+		Seed crypto.Hash `json:"seed"`
+	}
+)
+
+// NewCapacityChallenge derives the capacity challenge for the given node,
+// from the challenge block identified by the given height and ID.
+func NewCapacityChallenge(height types.BlockHeight, blockID types.BlockID, node types.PublicKey) CapacityChallenge {
+	return CapacityChallenge{
+		BlockHeight: height,
+		BlockID:     blockID,
+		Node:        node,
+		Seed:        crypto.HashAll(SpecifierCapacityChallenge, blockID, node),
+	}
+}
+
+// IsOpen returns true if a response to this challenge
+// can still be included in a block at the given height.
+func (cc CapacityChallenge) IsOpen(height types.BlockHeight) bool {
+	return height > cc.BlockHeight && height <= cc.BlockHeight+CapacityProofResponseWindow
+}
+
+// Covers returns true if this capacity specification provides
+// at least the capacity of the given specification, for each resource unit.
+func (cs CapacitySpecification) Covers(other CapacitySpecification) bool {
+	return cs.CRU >= other.CRU && cs.MRU >= other.MRU && cs.HRU >= other.HRU && cs.SRU >= other.SRU
+}
+
+type (
+	// CapacityProofResult is the record type used to store the outcome
+	// of a capacity challenge for a single node in the TransactionDB.
+	CapacityProofResult struct {
+		// ChallengeHeight is the height of the challenge block.
+		ChallengeHeight types.BlockHeight `json:"challengeheight"`
+		// Passed is true if the node responded in time,
+		// reporting at least the capacity registered for it.
+		Passed bool `json:"passed"`
+		// Capacity is the capacity reported by the node,
+		// nil in case the node did not respond.
+		Capacity CapacitySpecification `json:"capacity"`
+		// TransactionID is the ID of the transaction which contains the response,
+		// nil in case the node did not respond.
+		TransactionID types.TransactionID `json:"txid"`
+	}
+)
+
+// MarshalSia implements SiaMarshaler.MarshalSia,
+// alias of MarshalRivine for backwards-compatibility reasons.
+func (result CapacityProofResult) MarshalSia(w io.Writer) error {
+	return result.MarshalRivine(w)
+}
+
+// UnmarshalSia implements SiaUnmarshaler.UnmarshalSia,
+// alias of UnmarshalRivine for backwards-compatibility reasons.
+func (result *CapacityProofResult) UnmarshalSia(r io.Reader) error {
+	return result.UnmarshalRivine(r)
+}
+
+// MarshalRivine implements RivineMarshaler.MarshalRivine
+func (result CapacityProofResult) MarshalRivine(w io.Writer) error {
+	return rivbin.NewEncoder(w).EncodeAll(
+		result.ChallengeHeight,
+		result.Passed,
+		result.Capacity,
+		result.TransactionID,
+	)
+}
+
+// UnmarshalRivine implements RivineUnmarshaler.UnmarshalRivine
+func (result *CapacityProofResult) UnmarshalRivine(r io.Reader) error {
+	return rivbin.NewDecoder(r).DecodeAll(
+		&result.ChallengeHeight,
+		&result.Passed,
+		&result.Capacity,
+		&result.TransactionID,
+	)
+}
+
+// CapacityProofVerified returns true if the node, to which the given results belong,
+// is to be considered verified. A node loses its verified status as soon as it failed
+// CapacityProofMaxConsecutiveFailures challenges in a row, and regains it by passing a challenge.
+//
+// The results are expected to be ordered by challenge height.
+func CapacityProofVerified(results []CapacityProofResult) bool {
+	if len(results) < CapacityProofMaxConsecutiveFailures {
+		return true
+	}
+	for _, result := range results[len(results)-CapacityProofMaxConsecutiveFailures:] {
+		if result.Passed {
+			return true
+		}
+	}
+	return false
+}
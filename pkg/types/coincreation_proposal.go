@@ -0,0 +1,220 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+var (
+	// ErrCoinCreationProposalMismatch is the error returned in case two coin creation proposals,
+	// that are to be merged, do not propose the same coin creation.
+	ErrCoinCreationProposalMismatch = errors.New("coin creation proposals propose different coin creations")
+)
+
+// CoinCreationProposal wraps a coin creation transaction, as proposed by one of the coin creators,
+// together with the hash of the capacity proof it is based on. The proposal is passed around (offline)
+// between the coin creators, each of them adding their signature to the mint fulfillment of the transaction,
+// until enough signatures are collected to fulfill the active mint condition.
+//
+// The capacity proof hash is committed to as part of the arbitrary data of the transaction,
+// such that it is covered by all signatures, and recorded on-chain once the transaction is published.
+type CoinCreationProposal struct {
+	Transaction       CoinCreationTransaction `json:"transaction"`
+	CapacityProofHash crypto.Hash             `json:"capacityproofhash"`
+}
+
+// CapacityProofArbitraryData returns the arbitrary data of a proposed coin creation transaction,
+// being the hex-encoded capacity proof hash, optionally followed by a space and the given description.
+func CapacityProofArbitraryData(proofHash crypto.Hash, description string) []byte {
+	data := []byte(proofHash.String())
+	if description != "" {
+		data = append(data, ' ')
+		data = append(data, description...)
+	}
+	return data
+}
+
+// NewCoinCreationProposal creates a new (unsigned) proposal for the given coin creation transaction,
+// committing to the given capacity proof hash using the arbitrary data of the transaction.
+func NewCoinCreationProposal(tx CoinCreationTransaction, proofHash crypto.Hash, description string) CoinCreationProposal {
+	tx.ArbitraryData = CapacityProofArbitraryData(proofHash, description)
+	tx.MintFulfillment = types.UnlockFulfillmentProxy{}
+	return CoinCreationProposal{
+		Transaction:       tx,
+		CapacityProofHash: proofHash,
+	}
+}
+
+// Validate validates the proposal, ensuring that its transaction commits to its capacity proof hash.
+// It does not validate the signatures of the mint fulfillment, see SignatureStatus for that.
+func (proposal *CoinCreationProposal) Validate() error {
+	if len(proposal.Transaction.CoinOutputs) == 0 {
+		return errors.New("proposed coin creation does not create any coins")
+	}
+	if !bytes.HasPrefix(proposal.Transaction.ArbitraryData, []byte(proposal.CapacityProofHash.String())) {
+		return errors.New("proposed coin creation does not commit to the capacity proof hash of the proposal")
+	}
+	return nil
+}
+
+// Merge merges the signatures collected by the other proposal into this proposal,
+// both of which have to propose the exact same coin creation.
+func (proposal *CoinCreationProposal) Merge(other CoinCreationProposal) error {
+	if proposal.CapacityProofHash != other.CapacityProofHash {
+		return ErrCoinCreationProposalMismatch
+	}
+	a, b := proposal.Transaction, other.Transaction
+	a.MintFulfillment, b.MintFulfillment = types.UnlockFulfillmentProxy{}, types.UnlockFulfillmentProxy{}
+	if !bytes.Equal(siabin.Marshal(a), siabin.Marshal(b)) {
+		return ErrCoinCreationProposalMismatch
+	}
+
+	pairs, err := mintFulfillmentSignaturePairs(proposal.Transaction.MintFulfillment)
+	if err != nil {
+		return err
+	}
+	otherPairs, err := mintFulfillmentSignaturePairs(other.Transaction.MintFulfillment)
+	if err != nil {
+		return err
+	}
+	if len(otherPairs) == 0 {
+		return nil // nothing to merge
+	}
+	if len(pairs) == 0 {
+		proposal.Transaction.MintFulfillment = other.Transaction.MintFulfillment
+		return nil
+	}
+	if proposal.Transaction.MintFulfillment.FulfillmentType() != other.Transaction.MintFulfillment.FulfillmentType() {
+		return fmt.Errorf("cannot merge a mint fulfillment of type %d with one of type %d",
+			proposal.Transaction.MintFulfillment.FulfillmentType(), other.Transaction.MintFulfillment.FulfillmentType())
+	}
+	if proposal.Transaction.MintFulfillment.FulfillmentType() == types.FulfillmentTypeSingleSignature {
+		if !bytes.Equal(siabin.Marshal(pairs[0].PublicKey), siabin.Marshal(otherPairs[0].PublicKey)) {
+			return errors.New("cannot merge two single signature mint fulfillments signed by different keys")
+		}
+		return nil // both proposals are signed by the same key
+	}
+
+	// merge the pairs of both multi signature fulfillments, signed by different keys
+	signed := make(map[types.UnlockHash]struct{}, len(pairs))
+	for _, pair := range pairs {
+		signed[types.NewPubKeyUnlockHash(pair.PublicKey)] = struct{}{}
+	}
+	for _, pair := range otherPairs {
+		if _, ok := signed[types.NewPubKeyUnlockHash(pair.PublicKey)]; ok {
+			continue
+		}
+		pairs = append(pairs, pair)
+	}
+	proposal.Transaction.MintFulfillment = types.NewFulfillment(&types.MultiSignatureFulfillment{Pairs: pairs})
+	return nil
+}
+
+// CoinCreationSignatureStatus reports which of the keys of a mint condition
+// have (validly) signed a proposed coin creation.
+type CoinCreationSignatureStatus struct {
+	// Signed and Unsigned list the unlock hashes of the keys of the mint condition,
+	// that did and did not validly sign the proposal.
+	Signed   []types.UnlockHash `json:"signed"`
+	Unsigned []types.UnlockHash `json:"unsigned"`
+	// RequiredSignatures is the minimum amount of signatures required by the mint condition.
+	RequiredSignatures uint64 `json:"requiredsignatures"`
+	// LockTime, if non-zero, defines the block height or timestamp
+	// until which the mint condition is locked.
+	LockTime uint64 `json:"locktime,omitempty"`
+}
+
+// MissingSignatures returns the amount of signatures still required to fulfill the mint condition.
+func (status CoinCreationSignatureStatus) MissingSignatures() uint64 {
+	if n := uint64(len(status.Signed)); n < status.RequiredSignatures {
+		return status.RequiredSignatures - n
+	}
+	return 0
+}
+
+// SignatureStatus returns which of the keys of the given mint condition
+// have validly signed the proposed coin creation.
+func (proposal *CoinCreationProposal) SignatureStatus(mintCondition types.UnlockConditionProxy) (CoinCreationSignatureStatus, error) {
+	var status CoinCreationSignatureStatus
+	condition := mintCondition.Condition
+	if tlc, ok := condition.(*types.TimeLockCondition); ok {
+		status.LockTime = tlc.LockTime
+		condition = tlc.Condition
+	}
+	var keys []types.UnlockHash
+	switch tc := condition.(type) {
+	case *types.UnlockHashCondition:
+		keys = []types.UnlockHash{tc.TargetUnlockHash}
+		status.RequiredSignatures = 1
+	case *types.MultiSignatureCondition:
+		keys = tc.UnlockHashes
+		status.RequiredSignatures = tc.MinimumSignatureCount
+	default:
+		return CoinCreationSignatureStatus{}, fmt.Errorf("unsupported mint condition type %d", mintCondition.ConditionType())
+	}
+
+	pairs, err := mintFulfillmentSignaturePairs(proposal.Transaction.MintFulfillment)
+	if err != nil {
+		return CoinCreationSignatureStatus{}, err
+	}
+	validlySigned := make(map[types.UnlockHash]struct{}, len(pairs))
+	tx := proposal.Transaction.Transaction()
+	for _, pair := range pairs {
+		var extraObjects []interface{}
+		if proposal.Transaction.MintFulfillment.FulfillmentType() == types.FulfillmentTypeMultiSignature {
+			// the public key is signed as well as part of a multi signature fulfillment
+			extraObjects = append(extraObjects, pair.PublicKey)
+		}
+		sigHash, err := CoinCreationTransactionController{}.SignatureHash(tx, extraObjects...)
+		if err != nil {
+			return CoinCreationSignatureStatus{}, err
+		}
+		if verifySignatureHash(pair.PublicKey, sigHash, pair.Signature) == nil {
+			validlySigned[types.NewPubKeyUnlockHash(pair.PublicKey)] = struct{}{}
+		}
+	}
+	for _, key := range keys {
+		if _, ok := validlySigned[key]; ok {
+			status.Signed = append(status.Signed, key)
+		} else {
+			status.Unsigned = append(status.Unsigned, key)
+		}
+	}
+	return status, nil
+}
+
+// mintFulfillmentSignaturePairs returns the public key and signature pairs of a (signed) mint fulfillment.
+func mintFulfillmentSignaturePairs(fulfillment types.UnlockFulfillmentProxy) ([]types.PublicKeySignaturePair, error) {
+	switch tf := fulfillment.Fulfillment.(type) {
+	case nil, *types.NilFulfillment:
+		return nil, nil
+	case *types.SingleSignatureFulfillment:
+		return []types.PublicKeySignaturePair{{PublicKey: tf.PublicKey, Signature: tf.Signature}}, nil
+	case *types.MultiSignatureFulfillment:
+		return append([]types.PublicKeySignaturePair(nil), tf.Pairs...), nil
+	default:
+		return nil, fmt.Errorf("unsupported mint fulfillment type %d", fulfillment.FulfillmentType())
+	}
+}
+
+// verifySignatureHash verifies the given signature of the given hash, using the given public key.
+func verifySignatureHash(publicKey types.PublicKey, hash crypto.Hash, signature types.ByteSlice) error {
+	if publicKey.Algorithm != types.SignatureAlgoEd25519 {
+		return fmt.Errorf("unsupported public key algorithm %v", publicKey.Algorithm)
+	}
+	var (
+		pk  crypto.PublicKey
+		sig crypto.Signature
+	)
+	if len(publicKey.Key) != len(pk) || len(signature) != len(sig) {
+		return errors.New("invalid ed25519 public key or signature size")
+	}
+	copy(pk[:], publicKey.Key)
+	copy(sig[:], signature)
+	return crypto.VerifyHash(hash, pk, sig)
+}
@@ -0,0 +1,140 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestCoinCreationProposalSignAndMerge(t *testing.T) {
+	types.RegisterTransactionVersion(TransactionVersionCoinCreation, CoinCreationTransactionController{})
+	defer types.RegisterTransactionVersion(TransactionVersionCoinCreation, nil)
+
+	// a 2-of-3 mint condition
+	keyPairs := []types.KeyPair{cryptoKeyPair, newTestKeyPair(1), newTestKeyPair(2)}
+	var unlockHashes []types.UnlockHash
+	for _, kp := range keyPairs {
+		unlockHashes = append(unlockHashes, types.NewPubKeyUnlockHash(kp.PublicKey))
+	}
+	mintCondition := types.NewCondition(types.NewMultiSignatureCondition(unlockHashes, 2))
+
+	proofHash := crypto.HashObject("capacity proof")
+	newProposal := func() CoinCreationProposal {
+		return NewCoinCreationProposal(CoinCreationTransaction{
+			Nonce: TransactionNonce{1, 2, 3, 4, 5, 6, 7, 8},
+			CoinOutputs: []types.CoinOutput{{
+				Value:     types.NewCurrency64(100000000000),
+				Condition: types.NewCondition(types.NewUnlockHashCondition(unlockHashes[0])),
+			}},
+			MinerFees: []types.Currency{types.NewCurrency64(100000000)},
+		}, proofHash, "monthly minting")
+	}
+	sign := func(proposal *CoinCreationProposal, kp types.KeyPair) {
+		t.Helper()
+		if proposal.Transaction.MintFulfillment.FulfillmentType() == types.FulfillmentTypeNil {
+			proposal.Transaction.MintFulfillment = types.NewFulfillment(&types.MultiSignatureFulfillment{})
+		}
+		err := proposal.Transaction.MintFulfillment.Sign(types.FulfillmentSignContext{
+			Transaction: proposal.Transaction.Transaction(),
+			Key:         kp,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkStatus := func(proposal CoinCreationProposal, expectedSigned []types.UnlockHash, expectedMissing uint64) {
+		t.Helper()
+		status, err := proposal.SignatureStatus(mintCondition)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(status.Signed) != len(expectedSigned) {
+			t.Fatal("unexpected signed keys:", status.Signed, "!=", expectedSigned)
+		}
+		for idx, uh := range expectedSigned {
+			if status.Signed[idx].Cmp(uh) != 0 {
+				t.Error(idx, "unexpected signed key:", status.Signed[idx], "!=", uh)
+			}
+		}
+		if len(status.Signed)+len(status.Unsigned) != len(unlockHashes) {
+			t.Fatal("unexpected amount of listed keys:", status)
+		}
+		if missing := status.MissingSignatures(); missing != expectedMissing {
+			t.Fatal("unexpected amount of missing signatures:", missing, "!=", expectedMissing)
+		}
+	}
+
+	// the proposal commits to the capacity proof hash
+	proposal := newProposal()
+	if expected := proofHash.String() + " monthly minting"; string(proposal.Transaction.ArbitraryData) != expected {
+		t.Fatal("unexpected arbitrary data:", string(proposal.Transaction.ArbitraryData), "!=", expected)
+	}
+	err := proposal.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStatus(proposal, nil, 2)
+
+	// each signer signs its own copy of the proposal, which are merged afterwards
+	sign(&proposal, keyPairs[0])
+	checkStatus(proposal, unlockHashes[:1], 1)
+	other := newProposal()
+	sign(&other, keyPairs[2])
+	checkStatus(other, unlockHashes[2:], 1)
+	err = proposal.Merge(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStatus(proposal, []types.UnlockHash{unlockHashes[0], unlockHashes[2]}, 0)
+	// merging the same signatures again is a no-op
+	err = proposal.Merge(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pairs, err := mintFulfillmentSignaturePairs(proposal.Transaction.MintFulfillment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 2 {
+		t.Fatal("unexpected amount of signature pairs after merging twice:", len(pairs))
+	}
+	// a proposal with enough signatures fulfills the mint condition
+	err = mintCondition.Fulfill(proposal.Transaction.MintFulfillment, types.FulfillContext{
+		Transaction: proposal.Transaction.Transaction(),
+	})
+	if err != nil {
+		t.Fatal("merged proposal does not fulfill the mint condition:", err)
+	}
+
+	// proposals for different coin creations cannot be merged
+	other = newProposal()
+	other.Transaction.Nonce[0]++
+	sign(&other, keyPairs[1])
+	err = proposal.Merge(other)
+	if err != ErrCoinCreationProposalMismatch {
+		t.Fatal("unexpected error while merging proposals for different coin creations:", err)
+	}
+	other = NewCoinCreationProposal(newProposal().Transaction, crypto.HashObject("another proof"), "monthly minting")
+	err = proposal.Merge(other)
+	if err != ErrCoinCreationProposalMismatch {
+		t.Fatal("unexpected error while merging proposals for different capacity proofs:", err)
+	}
+
+	// signatures of a modified coin creation are not valid
+	proposal.Transaction.CoinOutputs[0].Value = types.NewCurrency64(200000000000)
+	checkStatus(proposal, nil, 2)
+	// nor is a proposal valid that doesn't commit to its capacity proof
+	proposal.Transaction.ArbitraryData = []byte("monthly minting")
+	if proposal.Validate() == nil {
+		t.Fatal("succeeded to validate a proposal that does not commit to its capacity proof hash")
+	}
+}
+
+func newTestKeyPair(seed byte) types.KeyPair {
+	sk, pk := crypto.GenerateKeyPairDeterministic([crypto.EntropySize]byte{seed})
+	return types.KeyPair{
+		PublicKey:  types.Ed25519PublicKey(pk),
+		PrivateKey: types.ByteSlice(sk[:]),
+	}
+}
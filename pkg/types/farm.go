@@ -0,0 +1,170 @@
+package types
+
+import (
+	"errors"
+	"io"
+	"regexp"
+	"sort"
+
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+const (
+	// MaxAuthorizedAddressesPerFarm defines the maximum amount of
+	// authorized (farmer) addresses allowed per farm.
+	MaxAuthorizedAddressesPerFarm = 10
+)
+
+const (
+	// RegexpFarmName is used to validate a farm name (string).
+	RegexpFarmName = `^[A-Za-z0-9]([A-Za-z0-9\-_.]{0,62}[A-Za-z0-9])?$`
+	// MaxLengthFarmName defines the maximum length a farm name can have.
+	MaxLengthFarmName = 64
+)
+
+var (
+	rexFarmName = regexp.MustCompile(RegexpFarmName)
+)
+
+var (
+	// ErrInvalidFarmName is the error returned in case a farm name
+	// does not match the RegexpFarmName regular expression.
+	ErrInvalidFarmName = errors.New("invalid farm name")
+	// ErrTooManyAuthorizedAddresses is the error returned in case a farm would end up
+	// with more than 10 authorized addresses.
+	ErrTooManyAuthorizedAddresses = errors.New("a farm can have a maximum of 10 authorized addresses")
+	// ErrNoAuthorizedAddresses is the error returned in case a farm would end up
+	// without any authorized address.
+	ErrNoAuthorizedAddresses = errors.New("a farm requires at least one authorized address")
+	// ErrAuthorizedAddressNotUnique is the error returned in case an authorized address
+	// is added that is already authorized for this farm.
+	ErrAuthorizedAddressNotUnique = errors.New("the address is already authorized for this farm")
+	// ErrAuthorizedAddressDoesNotExist is the error returned in case an authorized address
+	// is removed that is not authorized for this farm.
+	ErrAuthorizedAddressDoesNotExist = errors.New("the address is not authorized for this farm")
+	// ErrInvalidAuthorizedAddress is the error returned in case an address is authorized,
+	// which is not a public key unlock hash.
+	ErrInvalidAuthorizedAddress = errors.New("an authorized address has to be a public key unlock hash")
+)
+
+// ValidateFarmName validates the given farm name,
+// returning an error if it is not a valid farm name.
+func ValidateFarmName(name string) error {
+	if len(name) > MaxLengthFarmName || !rexFarmName.MatchString(name) {
+		return ErrInvalidFarmName
+	}
+	return nil
+}
+
+type (
+	// FarmRecord is the record type used to store a unique farm in the TransactionDB.
+	// Per farm there is one FarmRecord. Once a record is created it is never deleted,
+	// but it can be modified by the owner of the farm using the FarmUpdate Transaction.
+	FarmRecord struct {
+		// ID of the farm, assigned by the TransactionDB at registration.
+		ID FarmID `json:"id"`
+		// Name of the farm, unique for all farms.
+		Name string `json:"name"`
+		// Owner defines the condition that has to be fulfilled in order to update the farm.
+		Owner types.UnlockConditionProxy `json:"owner"`
+		// AuthorizedAddresses defines the addresses that are authorized to
+		// register capacity for the farm, any one of them can do so.
+		AuthorizedAddresses types.UnlockHashSlice `json:"authorizedaddresses"`
+	}
+)
+
+// MarshalSia implements SiaMarshaler.MarshalSia,
+// alias of MarshalRivine for backwards-compatibility reasons.
+func (record FarmRecord) MarshalSia(w io.Writer) error {
+	return record.MarshalRivine(w)
+}
+
+// UnmarshalSia implements SiaUnmarshaler.UnmarshalSia,
+// alias of UnmarshalRivine for backwards-compatibility reasons.
+func (record *FarmRecord) UnmarshalSia(r io.Reader) error {
+	return record.UnmarshalRivine(r)
+}
+
+// MarshalRivine implements RivineMarshaler.MarshalRivine
+func (record FarmRecord) MarshalRivine(w io.Writer) error {
+	return rivbin.NewEncoder(w).EncodeAll(
+		record.ID,
+		record.Name,
+		record.Owner,
+		record.AuthorizedAddresses,
+	)
+}
+
+// UnmarshalRivine implements RivineUnmarshaler.UnmarshalRivine
+func (record *FarmRecord) UnmarshalRivine(r io.Reader) error {
+	return rivbin.NewDecoder(r).DecodeAll(
+		&record.ID,
+		&record.Name,
+		&record.Owner,
+		&record.AuthorizedAddresses,
+	)
+}
+
+// AddAuthorizedAddresses adds one or multiple unique addresses
+// to the authorized addresses of this farm record.
+func (record *FarmRecord) AddAuthorizedAddresses(addresses ...types.UnlockHash) error {
+	if len(record.AuthorizedAddresses)+len(addresses) > MaxAuthorizedAddressesPerFarm {
+		return ErrTooManyAuthorizedAddresses
+	}
+	// copy the slice, as to not modify a slice shared with another record
+	authorizedAddresses := append(types.UnlockHashSlice(nil), record.AuthorizedAddresses...)
+	for _, address := range addresses {
+		if address.Type != types.UnlockTypePubKey {
+			return ErrInvalidAuthorizedAddress
+		}
+		if authorizedAddressIndex(authorizedAddresses, address) >= 0 {
+			return ErrAuthorizedAddressNotUnique
+		}
+		authorizedAddresses = append(authorizedAddresses, address)
+	}
+	sort.Sort(authorizedAddresses)
+	record.AuthorizedAddresses = authorizedAddresses
+	return nil
+}
+
+// RemoveAuthorizedAddresses removes one or multiple unique addresses
+// from the authorized addresses of this farm record.
+func (record *FarmRecord) RemoveAuthorizedAddresses(addresses ...types.UnlockHash) error {
+	// copy the slice, as to not modify a slice shared with another record
+	authorizedAddresses := append(types.UnlockHashSlice(nil), record.AuthorizedAddresses...)
+	for _, address := range addresses {
+		idx := authorizedAddressIndex(authorizedAddresses, address)
+		if idx < 0 {
+			return ErrAuthorizedAddressDoesNotExist
+		}
+		authorizedAddresses = append(authorizedAddresses[:idx], authorizedAddresses[idx+1:]...)
+	}
+	record.AuthorizedAddresses = authorizedAddresses
+	return nil
+}
+
+func authorizedAddressIndex(addresses types.UnlockHashSlice, address types.UnlockHash) int {
+	for idx, authorizedAddress := range addresses {
+		if authorizedAddress.Cmp(address) == 0 {
+			return idx
+		}
+	}
+	return -1
+}
+
+// FarmerCondition returns the condition that has to be fulfilled
+// in order to register capacity for this farm, which can be fulfilled
+// by any one of the authorized addresses of this farm.
+func (record *FarmRecord) FarmerCondition() (types.UnlockConditionProxy, error) {
+	switch len(record.AuthorizedAddresses) {
+	case 0:
+		return types.UnlockConditionProxy{}, ErrNoAuthorizedAddresses
+	case 1:
+		return types.NewCondition(types.NewUnlockHashCondition(record.AuthorizedAddresses[0])), nil
+	default:
+		addresses := make(types.UnlockHashSlice, len(record.AuthorizedAddresses))
+		copy(addresses, record.AuthorizedAddresses)
+		return types.NewCondition(types.NewMultiSignatureCondition(addresses, 1)), nil
+	}
+}
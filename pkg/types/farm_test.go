@@ -0,0 +1,105 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestValidateFarmName(t *testing.T) {
+	validNames := []string{
+		"a",
+		"farm1",
+		"ThreeFold-Farm_01.be",
+		"0123456789012345678901234567890123456789012345678901234567890123",
+	}
+	for idx, name := range validNames {
+		err := ValidateFarmName(name)
+		if err != nil {
+			t.Error(idx, name, err)
+		}
+	}
+	invalidNames := []string{
+		"",
+		"-farm",
+		"farm.",
+		"my farm",
+		"01234567890123456789012345678901234567890123456789012345678901234",
+	}
+	for idx, name := range invalidNames {
+		err := ValidateFarmName(name)
+		if err != ErrInvalidFarmName {
+			t.Error(idx, name, err)
+		}
+	}
+}
+
+func TestFarmRecordAuthorizedAddresses(t *testing.T) {
+	addrA := unlockHashFromHex("015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e6791584fbdac553e6f")
+	addrB := unlockHashFromHex("01370af706b547dd4e562a047e6265d7e7750771f9bff633b1a12dbd59b11712c6ef65edb1690d")
+
+	var record FarmRecord
+	if _, err := record.FarmerCondition(); err != ErrNoAuthorizedAddresses {
+		t.Fatal("unexpected error:", err)
+	}
+
+	err := record.AddAuthorizedAddresses(addrA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	condition, err := record.FarmerCondition()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := condition.ConditionType(); ct != types.ConditionTypeUnlockHash {
+		t.Fatal("unexpected condition type:", ct)
+	}
+	if uh := condition.UnlockHash(); uh.Cmp(addrA) != 0 {
+		t.Fatal("unexpected unlock hash:", uh.String())
+	}
+
+	if err = record.AddAuthorizedAddresses(addrA); err != ErrAuthorizedAddressNotUnique {
+		t.Fatal("unexpected error:", err)
+	}
+	if err = record.AddAuthorizedAddresses(types.UnlockHash{Type: types.UnlockTypeMultiSig}); err != ErrInvalidAuthorizedAddress {
+		t.Fatal("unexpected error:", err)
+	}
+
+	err = record.AddAuthorizedAddresses(addrB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(record.AuthorizedAddresses) != 2 {
+		t.Fatal("unexpected authorized addresses:", record.AuthorizedAddresses)
+	}
+	condition, err = record.FarmerCondition()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := condition.ConditionType(); ct != types.ConditionTypeMultiSignature {
+		t.Fatal("unexpected condition type:", ct)
+	}
+
+	if err = record.RemoveAuthorizedAddresses(addrA, addrA); err != ErrAuthorizedAddressDoesNotExist {
+		t.Fatal("unexpected error:", err)
+	}
+	if len(record.AuthorizedAddresses) != 2 {
+		t.Fatal("failed removal modified the record:", record.AuthorizedAddresses)
+	}
+	err = record.RemoveAuthorizedAddresses(addrA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(record.AuthorizedAddresses) != 1 || record.AuthorizedAddresses[0].Cmp(addrB) != 0 {
+		t.Fatal("unexpected authorized addresses:", record.AuthorizedAddresses)
+	}
+
+	addresses := make([]types.UnlockHash, MaxAuthorizedAddressesPerFarm)
+	for idx := range addresses {
+		addresses[idx] = types.UnlockHash{Type: types.UnlockTypePubKey}
+		addresses[idx].Hash[0] = byte(idx + 1)
+	}
+	if err = record.AddAuthorizedAddresses(addresses...); err != ErrTooManyAuthorizedAddresses {
+		t.Fatal("unexpected error:", err)
+	}
+}
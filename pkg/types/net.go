@@ -172,6 +172,22 @@ func (na NetworkAddress) String() string {
 	}
 }
 
+// Type returns the type of this NetworkAddress.
+func (na NetworkAddress) Type() NetworkAddressType {
+	return na.t
+}
+
+// IP returns this NetworkAddress as an IP address,
+// or nil in case this NetworkAddress is a hostname.
+func (na NetworkAddress) IP() net.IP {
+	switch na.t {
+	case NetworkAddressIPv4, NetworkAddressIPv6:
+		return net.ParseIP(na.String())
+	default: // NetworkAddressHostname
+		return nil
+	}
+}
+
 // LoadString loads the NetworkAddress from a human-readable string.
 func (na *NetworkAddress) LoadString(str string) (err error) {
 	*na, err = NewNetworkAddress(str)
@@ -214,8 +230,8 @@ func (na NetworkAddress) Compare(ona NetworkAddress) int {
 type (
 	// NetworkAddressSortedSet represents a sorted set of (unique) network addresses.
 	//
-	// A NetworkAddressSortedSet does not expose it elements, as this is not a feature-requirement of tfchain,
-	// all it aims for is to ensure the set consists only of unique elements.
+	// A NetworkAddressSortedSet only exposes a copy of its elements,
+	// as all it aims for is to ensure the set consists only of unique elements.
 	NetworkAddressSortedSet struct {
 		slice networkAddressSlice
 	}
@@ -227,6 +243,16 @@ func (nass NetworkAddressSortedSet) Len() int {
 	return nass.slice.Len()
 }
 
+// Addresses returns a copy of all network addresses in this sorted set, in sorted order.
+func (nass NetworkAddressSortedSet) Addresses() []NetworkAddress {
+	if nass.slice.Len() == 0 {
+		return nil
+	}
+	addresses := make([]NetworkAddress, nass.slice.Len())
+	copy(addresses, nass.slice)
+	return addresses
+}
+
 // AddAddress adds a new (unique) network address to this sorted set of network addresses,
 // returning an error if the address already exists within this sorted set.
 func (nass *NetworkAddressSortedSet) AddAddress(address NetworkAddress) error {
@@ -40,27 +40,27 @@ var (
 // different transaction-related data from required by Tfchain transactions.
 type TFChainReadDB interface {
 	MintConditionGetter
+	MintingCapGetter
+	ConsensusParameterGetter
 	BotRecordReadRegistry
 	ERC20Registry
+	FarmerConditionGetter
+	FoundationConditionGetter
+	FarmRecordReadRegistry
+	CapacityProofReadRegistry
 }
 
 // RegisterTransactionTypesForStandardNetwork registers he transaction controllers
 // for all transaction versions supported on the standard network.
 func RegisterTransactionTypesForStandardNetwork(db TFChainReadDB, erc20TxValidator ERC20TransactionValidator, oneCoin types.Currency, cfg config.DaemonNetworkConfig) {
-	const (
-		secondsInOneDay                         = 86400 + config.StandardNetworkBlockFrequency // round up
-		daysFromStartOfBlockchainUntil2ndOfJuly = 74
-		txnFeeCheckBlockHeight                  = daysFromStartOfBlockchainUntil2ndOfJuly *
-			(secondsInOneDay / config.StandardNetworkBlockFrequency)
-	)
 	// overwrite rivine-defined transaction versions
 	types.RegisterTransactionVersion(types.TransactionVersionZero, LegacyTransactionController{
-		LegacyTransactionController:    types.LegacyTransactionController{},
-		TransactionFeeCheckBlockHeight: txnFeeCheckBlockHeight,
+		LegacyTransactionController: types.LegacyTransactionController{},
+		ConsensusParameterGetter:    db,
 	})
 	types.RegisterTransactionVersion(types.TransactionVersionOne, DefaultTransactionController{
-		DefaultTransactionController:   types.DefaultTransactionController{},
-		TransactionFeeCheckBlockHeight: txnFeeCheckBlockHeight,
+		DefaultTransactionController: types.DefaultTransactionController{},
+		ConsensusParameterGetter:     db,
 	})
 
 	// define tfchain-specific transaction versions
@@ -70,54 +70,109 @@ func RegisterTransactionTypesForStandardNetwork(db TFChainReadDB, erc20TxValidat
 	})
 	types.RegisterTransactionVersion(TransactionVersionCoinCreation, CoinCreationTransactionController{
 		MintConditionGetter: db,
+		MintingCapGetter:    db,
+		MintingCapWindow:    cfg.MintingCapWindow,
+	})
+	types.RegisterTransactionVersion(TransactionVersionMintingCapDefinition, MintingCapDefinitionTransactionController{
+		MintConditionGetter: db,
+		MintingCapWindow:    cfg.MintingCapWindow,
+	})
+	types.RegisterTransactionVersion(TransactionVersionConsensusParameterUpdate, ConsensusParameterUpdateTransactionController{
+		MintConditionGetter: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionCoinBurn, CoinBurnTransactionController{
+		BurnCondition: cfg.BurnCondition,
 	})
 
 	types.RegisterTransactionVersion(TransactionVersionBotRegistration, BotRegistrationTransactionController{
-		Registry:            db,
-		RegistryPoolAddress: cfg.FoundationPoolAddress,
-		OneCoin:             oneCoin,
+		Registry:                 db,
+		RegistryPoolAddress:      cfg.FoundationPoolAddress,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: db,
 	})
 	types.RegisterTransactionVersion(TransactionVersionBotRecordUpdate, BotUpdateRecordTransactionController{
-		Registry:            db,
-		RegistryPoolAddress: cfg.FoundationPoolAddress,
-		OneCoin:             oneCoin,
+		Registry:                 db,
+		RegistryPoolAddress:      cfg.FoundationPoolAddress,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: db,
 	})
 	types.RegisterTransactionVersion(TransactionVersionBotNameTransfer, BotNameTransferTransactionController{
-		Registry:            db,
-		RegistryPoolAddress: cfg.FoundationPoolAddress,
-		OneCoin:             oneCoin,
+		Registry:                 db,
+		RegistryPoolAddress:      cfg.FoundationPoolAddress,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionBotRecordUpdateWithFulfillment, BotUpdateRecordWithFulfillmentTransactionController{
+		Registry:                 db,
+		RegistryPoolAddress:      cfg.FoundationPoolAddress,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionBotNameTransferWithFulfillment, BotNameTransferWithFulfillmentTransactionController{
+		Registry:                 db,
+		RegistryPoolAddress:      cfg.FoundationPoolAddress,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionBotNameSale, BotNameSaleTransactionController{
+		Registry:                 db,
+		RegistryPoolAddress:      cfg.FoundationPoolAddress,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionBotNameDelegation, BotNameDelegationTransactionController{
+		Registry: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionBotRecordUpdateWithServices, BotUpdateRecordWithServicesTransactionController{
+		Registry:                 db,
+		RegistryPoolAddress:      cfg.FoundationPoolAddress,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionBotKeyRotation, BotKeyRotationTransactionController{
+		Registry: db,
 	})
 
-	types.RegisterTransactionVersion(TransactionVersionERC20Conversion, ERC20ConvertTransactionController{})
+	types.RegisterTransactionVersion(TransactionVersionERC20Conversion, ERC20ConvertTransactionController{
+		ConsensusParameterGetter: db,
+	})
 	types.RegisterTransactionVersion(TransactionVersionERC20CoinCreation, ERC20CoinCreationTransactionController{
 		Registry:    db,
 		OneCoin:     oneCoin,
 		TxValidator: erc20TxValidator,
 	})
 	types.RegisterTransactionVersion(TransactionVersionERC20AddressRegistration, ERC20AddressRegistrationTransactionController{
-		Registry:             db,
-		OneCoin:              oneCoin,
-		BridgeFeePoolAddress: cfg.ERC20FeePoolAddress,
+		Registry:                 db,
+		ConsensusParameterGetter: db,
+		BridgeFeePoolAddress:     cfg.ERC20FeePoolAddress,
+	})
+
+	types.RegisterTransactionVersion(TransactionVersionCapacityRegistration, CapacityRegistrationTransactionController{
+		FarmerConditionGetter: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionFarmRegistration, FarmRegistrationTransactionController{
+		FoundationConditionGetter: db,
+		Registry:                  db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionFarmUpdate, FarmUpdateTransactionController{
+		Registry: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionCapacityProof, CapacityProofTransactionController{
+		Registry: db,
 	})
 }
 
 // RegisterTransactionTypesForTestNetwork registers he transaction controllers
 // for all transaction versions supported on the test network.
 func RegisterTransactionTypesForTestNetwork(db TFChainReadDB, erc20TxValidator ERC20TransactionValidator, oneCoin types.Currency, cfg config.DaemonNetworkConfig) {
-	const (
-		secondsInOneDay                         = 86400 + config.TestNetworkBlockFrequency // round up
-		daysFromStartOfBlockchainUntil2ndOfJuly = 90
-		txnFeeCheckBlockHeight                  = daysFromStartOfBlockchainUntil2ndOfJuly *
-			(secondsInOneDay / config.TestNetworkBlockFrequency)
-	)
 	// overwrite rivine-defined transaction versions
 	types.RegisterTransactionVersion(types.TransactionVersionZero, LegacyTransactionController{
-		LegacyTransactionController:    types.LegacyTransactionController{},
-		TransactionFeeCheckBlockHeight: txnFeeCheckBlockHeight,
+		LegacyTransactionController: types.LegacyTransactionController{},
+		ConsensusParameterGetter:    db,
 	})
 	types.RegisterTransactionVersion(types.TransactionVersionOne, DefaultTransactionController{
-		DefaultTransactionController:   types.DefaultTransactionController{},
-		TransactionFeeCheckBlockHeight: txnFeeCheckBlockHeight,
+		DefaultTransactionController: types.DefaultTransactionController{},
+		ConsensusParameterGetter:     db,
 	})
 
 	// define tfchain-specific transaction versions
@@ -127,34 +182,95 @@ func RegisterTransactionTypesForTestNetwork(db TFChainReadDB, erc20TxValidator E
 	})
 	types.RegisterTransactionVersion(TransactionVersionCoinCreation, CoinCreationTransactionController{
 		MintConditionGetter: db,
+		MintingCapGetter:    db,
+		MintingCapWindow:    cfg.MintingCapWindow,
+	})
+	types.RegisterTransactionVersion(TransactionVersionMintingCapDefinition, MintingCapDefinitionTransactionController{
+		MintConditionGetter: db,
+		MintingCapWindow:    cfg.MintingCapWindow,
+	})
+	types.RegisterTransactionVersion(TransactionVersionConsensusParameterUpdate, ConsensusParameterUpdateTransactionController{
+		MintConditionGetter: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionCoinBurn, CoinBurnTransactionController{
+		BurnCondition: cfg.BurnCondition,
 	})
 
 	types.RegisterTransactionVersion(TransactionVersionBotRegistration, BotRegistrationTransactionController{
-		Registry:            db,
-		RegistryPoolAddress: cfg.FoundationPoolAddress,
-		OneCoin:             oneCoin,
+		Registry:                 db,
+		RegistryPoolAddress:      cfg.FoundationPoolAddress,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: db,
 	})
 	types.RegisterTransactionVersion(TransactionVersionBotRecordUpdate, BotUpdateRecordTransactionController{
-		Registry:            db,
-		RegistryPoolAddress: cfg.FoundationPoolAddress,
-		OneCoin:             oneCoin,
+		Registry:                 db,
+		RegistryPoolAddress:      cfg.FoundationPoolAddress,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: db,
 	})
 	types.RegisterTransactionVersion(TransactionVersionBotNameTransfer, BotNameTransferTransactionController{
-		Registry:            db,
-		RegistryPoolAddress: cfg.FoundationPoolAddress,
-		OneCoin:             oneCoin,
+		Registry:                 db,
+		RegistryPoolAddress:      cfg.FoundationPoolAddress,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionBotRecordUpdateWithFulfillment, BotUpdateRecordWithFulfillmentTransactionController{
+		Registry:                 db,
+		RegistryPoolAddress:      cfg.FoundationPoolAddress,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionBotNameTransferWithFulfillment, BotNameTransferWithFulfillmentTransactionController{
+		Registry:                 db,
+		RegistryPoolAddress:      cfg.FoundationPoolAddress,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionBotNameSale, BotNameSaleTransactionController{
+		Registry:                 db,
+		RegistryPoolAddress:      cfg.FoundationPoolAddress,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionBotNameDelegation, BotNameDelegationTransactionController{
+		Registry: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionBotRecordUpdateWithServices, BotUpdateRecordWithServicesTransactionController{
+		Registry:                 db,
+		RegistryPoolAddress:      cfg.FoundationPoolAddress,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionBotKeyRotation, BotKeyRotationTransactionController{
+		Registry: db,
 	})
 
-	types.RegisterTransactionVersion(TransactionVersionERC20Conversion, ERC20ConvertTransactionController{})
+	types.RegisterTransactionVersion(TransactionVersionERC20Conversion, ERC20ConvertTransactionController{
+		ConsensusParameterGetter: db,
+	})
 	types.RegisterTransactionVersion(TransactionVersionERC20CoinCreation, ERC20CoinCreationTransactionController{
 		Registry:    db,
 		OneCoin:     oneCoin,
 		TxValidator: erc20TxValidator,
 	})
 	types.RegisterTransactionVersion(TransactionVersionERC20AddressRegistration, ERC20AddressRegistrationTransactionController{
-		Registry:             db,
-		OneCoin:              oneCoin,
-		BridgeFeePoolAddress: cfg.ERC20FeePoolAddress,
+		Registry:                 db,
+		ConsensusParameterGetter: db,
+		BridgeFeePoolAddress:     cfg.ERC20FeePoolAddress,
+	})
+
+	types.RegisterTransactionVersion(TransactionVersionCapacityRegistration, CapacityRegistrationTransactionController{
+		FarmerConditionGetter: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionFarmRegistration, FarmRegistrationTransactionController{
+		FoundationConditionGetter: db,
+		Registry:                  db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionFarmUpdate, FarmUpdateTransactionController{
+		Registry: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionCapacityProof, CapacityProofTransactionController{
+		Registry: db,
 	})
 }
 
@@ -163,12 +279,12 @@ func RegisterTransactionTypesForTestNetwork(db TFChainReadDB, erc20TxValidator E
 func RegisterTransactionTypesForDevNetwork(db TFChainReadDB, erc20TxValidator ERC20TransactionValidator, oneCoin types.Currency, cfg config.DaemonNetworkConfig) {
 	// overwrite rivine-defined transaction versions
 	types.RegisterTransactionVersion(types.TransactionVersionZero, LegacyTransactionController{
-		LegacyTransactionController:    types.LegacyTransactionController{},
-		TransactionFeeCheckBlockHeight: 0,
+		LegacyTransactionController: types.LegacyTransactionController{},
+		ConsensusParameterGetter:    db,
 	})
 	types.RegisterTransactionVersion(types.TransactionVersionOne, DefaultTransactionController{
-		DefaultTransactionController:   types.DefaultTransactionController{},
-		TransactionFeeCheckBlockHeight: 0,
+		DefaultTransactionController: types.DefaultTransactionController{},
+		ConsensusParameterGetter:     db,
 	})
 
 	// define tfchain-specific transaction versions
@@ -178,34 +294,95 @@ func RegisterTransactionTypesForDevNetwork(db TFChainReadDB, erc20TxValidator ER
 	})
 	types.RegisterTransactionVersion(TransactionVersionCoinCreation, CoinCreationTransactionController{
 		MintConditionGetter: db,
+		MintingCapGetter:    db,
+		MintingCapWindow:    cfg.MintingCapWindow,
+	})
+	types.RegisterTransactionVersion(TransactionVersionMintingCapDefinition, MintingCapDefinitionTransactionController{
+		MintConditionGetter: db,
+		MintingCapWindow:    cfg.MintingCapWindow,
+	})
+	types.RegisterTransactionVersion(TransactionVersionConsensusParameterUpdate, ConsensusParameterUpdateTransactionController{
+		MintConditionGetter: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionCoinBurn, CoinBurnTransactionController{
+		BurnCondition: cfg.BurnCondition,
 	})
 
 	types.RegisterTransactionVersion(TransactionVersionBotRegistration, BotRegistrationTransactionController{
-		Registry:            db,
-		RegistryPoolAddress: cfg.FoundationPoolAddress,
-		OneCoin:             oneCoin,
+		Registry:                 db,
+		RegistryPoolAddress:      cfg.FoundationPoolAddress,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: db,
 	})
 	types.RegisterTransactionVersion(TransactionVersionBotRecordUpdate, BotUpdateRecordTransactionController{
-		Registry:            db,
-		RegistryPoolAddress: cfg.FoundationPoolAddress,
-		OneCoin:             oneCoin,
+		Registry:                 db,
+		RegistryPoolAddress:      cfg.FoundationPoolAddress,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: db,
 	})
 	types.RegisterTransactionVersion(TransactionVersionBotNameTransfer, BotNameTransferTransactionController{
-		Registry:            db,
-		RegistryPoolAddress: cfg.FoundationPoolAddress,
-		OneCoin:             oneCoin,
+		Registry:                 db,
+		RegistryPoolAddress:      cfg.FoundationPoolAddress,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionBotRecordUpdateWithFulfillment, BotUpdateRecordWithFulfillmentTransactionController{
+		Registry:                 db,
+		RegistryPoolAddress:      cfg.FoundationPoolAddress,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionBotNameTransferWithFulfillment, BotNameTransferWithFulfillmentTransactionController{
+		Registry:                 db,
+		RegistryPoolAddress:      cfg.FoundationPoolAddress,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionBotNameSale, BotNameSaleTransactionController{
+		Registry:                 db,
+		RegistryPoolAddress:      cfg.FoundationPoolAddress,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionBotNameDelegation, BotNameDelegationTransactionController{
+		Registry: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionBotRecordUpdateWithServices, BotUpdateRecordWithServicesTransactionController{
+		Registry:                 db,
+		RegistryPoolAddress:      cfg.FoundationPoolAddress,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionBotKeyRotation, BotKeyRotationTransactionController{
+		Registry: db,
 	})
 
-	types.RegisterTransactionVersion(TransactionVersionERC20Conversion, ERC20ConvertTransactionController{})
+	types.RegisterTransactionVersion(TransactionVersionERC20Conversion, ERC20ConvertTransactionController{
+		ConsensusParameterGetter: db,
+	})
 	types.RegisterTransactionVersion(TransactionVersionERC20CoinCreation, ERC20CoinCreationTransactionController{
 		Registry:    db,
 		OneCoin:     oneCoin,
 		TxValidator: erc20TxValidator,
 	})
 	types.RegisterTransactionVersion(TransactionVersionERC20AddressRegistration, ERC20AddressRegistrationTransactionController{
-		Registry:             db,
-		OneCoin:              oneCoin,
-		BridgeFeePoolAddress: cfg.ERC20FeePoolAddress,
+		Registry:                 db,
+		ConsensusParameterGetter: db,
+		BridgeFeePoolAddress:     cfg.ERC20FeePoolAddress,
+	})
+
+	types.RegisterTransactionVersion(TransactionVersionCapacityRegistration, CapacityRegistrationTransactionController{
+		FarmerConditionGetter: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionFarmRegistration, FarmRegistrationTransactionController{
+		FoundationConditionGetter: db,
+		Registry:                  db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionFarmUpdate, FarmUpdateTransactionController{
+		Registry: db,
+	})
+	types.RegisterTransactionVersion(TransactionVersionCapacityProof, CapacityProofTransactionController{
+		Registry: db,
 	})
 }
 
@@ -232,7 +409,9 @@ type (
 	// implemented on top of the regular DefaultTransactionController.
 	DefaultTransactionController struct {
 		types.DefaultTransactionController
-		TransactionFeeCheckBlockHeight types.BlockHeight
+		// ConsensusParameterGetter is used to get the consensus parameters at the context-defined block height,
+		// which define the block height from which the MinimumTransactionFee is checked.
+		ConsensusParameterGetter ConsensusParameterGetter
 	}
 	// LegacyTransactionController wraps around Rivine's LegacyTransactionController,
 	// as to ensure that we use check the MinimumTransactionFee,
@@ -242,7 +421,9 @@ type (
 	// implemented on top of the regular LegacyTransactionController.
 	LegacyTransactionController struct {
 		types.LegacyTransactionController
-		TransactionFeeCheckBlockHeight types.BlockHeight
+		// ConsensusParameterGetter is used to get the consensus parameters at the context-defined block height,
+		// which define the block height from which the MinimumTransactionFee is checked.
+		ConsensusParameterGetter ConsensusParameterGetter
 	}
 
 	// CoinCreationTransactionController defines a tfchain-specific transaction controller,
@@ -254,6 +435,12 @@ type (
 		// The found MintCondition defines the condition that has to be fulfilled
 		// in order to mint new coins into existence (in the form of non-backed coin outputs).
 		MintConditionGetter MintConditionGetter
+		// MintingCapGetter is used to get the minting cap and the coins minted
+		// within the minting cap window, prior to the context-defined block height.
+		MintingCapGetter MintingCapGetter
+		// MintingCapWindow defines the rolling window (in seconds of block time)
+		// to which the minting cap applies, minting caps are disabled if it is 0.
+		MintingCapWindow types.Timestamp
 	}
 
 	// MinterDefinitionTransactionController defines a tfchain-specific transaction controller,
@@ -307,11 +494,9 @@ var (
 
 // ValidateTransaction implements TransactionValidator.ValidateTransaction
 func (dtc DefaultTransactionController) ValidateTransaction(t types.Transaction, ctx types.ValidationContext, constants types.TransactionValidationConstants) error {
-	if ctx.Confirmed && ctx.BlockHeight < dtc.TransactionFeeCheckBlockHeight {
-		// as to ensure the miner fee is at least bigger than 0,
-		// we however only want to put this restriction within the consensus set,
-		// the stricter miner fee checks should apply immediately to the transaction pool logic
-		constants.MinimumMinerFee = types.NewCurrency64(1)
+	err := checkTransactionFeeHeight(dtc.ConsensusParameterGetter, ctx, &constants)
+	if err != nil {
+		return err
 	}
 	return types.DefaultTransactionValidation(t, ctx, constants)
 }
@@ -320,13 +505,31 @@ func (dtc DefaultTransactionController) ValidateTransaction(t types.Transaction,
 
 // ValidateTransaction implements TransactionValidator.ValidateTransaction
 func (ltc LegacyTransactionController) ValidateTransaction(t types.Transaction, ctx types.ValidationContext, constants types.TransactionValidationConstants) error {
-	if ctx.Confirmed && ctx.BlockHeight < ltc.TransactionFeeCheckBlockHeight {
-		// as to ensure the miner fee is at least bigger than 0,
-		// we however only want to put this restriction within the consensus set,
+	err := checkTransactionFeeHeight(ltc.ConsensusParameterGetter, ctx, &constants)
+	if err != nil {
+		return err
+	}
+	return types.DefaultTransactionValidation(t, ctx, constants)
+}
+
+// checkTransactionFeeHeight lowers the minimum miner fee to 1,
+// in case a confirmed transaction is part of a block prior to the
+// (consensus-defined) block height from which the minimum miner fee is checked.
+func checkTransactionFeeHeight(getter ConsensusParameterGetter, ctx types.ValidationContext, constants *types.TransactionValidationConstants) error {
+	if !ctx.Confirmed {
 		// the stricter miner fee checks should apply immediately to the transaction pool logic
+		return nil
+	}
+	params, err := getter.GetConsensusParametersAt(ctx.BlockHeight)
+	if err != nil {
+		return fmt.Errorf("failed to get the consensus parameters at block height %d: %v", ctx.BlockHeight, err)
+	}
+	if ctx.BlockHeight < params.TransactionFeeCheckHeight {
+		// as to ensure the miner fee is at least bigger than 0,
+		// we however only want to put this restriction within the consensus set
 		constants.MinimumMinerFee = types.NewCurrency64(1)
 	}
-	return types.DefaultTransactionValidation(t, ctx, constants)
+	return nil
 }
 
 // CoinCreationTransactionController
@@ -429,6 +632,11 @@ func (cctc CoinCreationTransactionController) ValidateTransaction(t types.Transa
 	if cctx.Nonce == (TransactionNonce{}) {
 		return errors.New("nil nonce is not allowed for a coin creation transaction")
 	}
+	// ensure the minting cap of the network is respected
+	err = validateMintingCap(cctc.MintingCapGetter, cctc.MintingCapWindow, cctx, ctx)
+	if err != nil {
+		return err
+	}
 
 	// validate the rest of the content
 	err = types.ArbitraryDataFits(cctx.ArbitraryData, constants.ArbitraryDataSizeLimit)
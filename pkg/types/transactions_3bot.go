@@ -1,6 +1,7 @@
 package types
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/json"
 	"errors"
@@ -26,27 +27,64 @@ const (
 	// for a Tx used to transfer one or multiple names from the active
 	// 3bot that up to the point of that Tx to another 3bot.
 	TransactionVersionBotNameTransfer
+	// TransactionVersionBotKeyRotation defines the Transaction version
+	// for a Tx used to replace the public key of an existing 3bot,
+	// authorized by both the current owner and the new public key.
+	TransactionVersionBotKeyRotation
+	// TransactionVersionBotRecordUpdateWithFulfillment defines the Transaction version
+	// for a Tx used to update a 3bot Record by the owner, authorized using
+	// a fulfillment of the owner condition rather than a single signature.
+	TransactionVersionBotRecordUpdateWithFulfillment
+	// TransactionVersionBotNameTransferWithFulfillment defines the Transaction version
+	// for a Tx used to transfer one or multiple names from one 3bot to another,
+	// authorized using fulfillments of the owner conditions rather than single signatures.
+	TransactionVersionBotNameTransferWithFulfillment
+	// TransactionVersionBotNameSale defines the Transaction version
+	// for a Tx used to sell one or multiple names from one 3bot to another,
+	// paid for by the receiving 3bot within the same Tx.
+	TransactionVersionBotNameSale
+	// TransactionVersionBotNameDelegation defines the Transaction version
+	// for a Tx used to delegate (or revoke) the right to own sub names of a name,
+	// by the 3bot that owns that name, to (or from) other 3bots.
+	TransactionVersionBotNameDelegation
+	// TransactionVersionBotRecordUpdateWithServices defines the Transaction version
+	// for a Tx used to update a 3bot Record by the owner, including the services it offers,
+	// authorized using a fulfillment of the owner condition.
+	TransactionVersionBotRecordUpdateWithServices
 )
 
 // 3bot Multiplier fees that have to be multiplied with the OneCoin definition,
 // in order to know the amount in the used chain currency (TFT).
+// These define the genesis fees of all networks, which can be updated on-chain
+// using a ConsensusParameterUpdate Transaction.
 const (
 	BotFeePerAdditionalNameMultiplier           = 50
 	BotFeeForNetworkAddressInfoChangeMultiplier = 20
 	BotRegistrationFeeMultiplier                = 90
 	BotMonthlyFeeMultiplier                     = 10
+	BotFeePerAdditionalServiceMultiplier        = 10
 )
 
 var (
 	SpecifierBotRegistrationTransaction = types.Specifier{'b', 'o', 't', ' ', 'r', 'e', 'g', 'i', 's', 't', 'e', 'r', ' ', 't', 'x'}
 	SpecifierBotRecordUpdateTransaction = types.Specifier{'b', 'o', 't', ' ', 'r', 'e', 'c', 'u', 'p', 'd', 'a', 't', 'e', ' ', 't', 'x'}
 	SpecifierBotNameTransferTransaction = types.Specifier{'b', 'o', 't', ' ', 'n', 'a', 'm', 'e', 't', 'r', 'a', 'n', 's', ' ', 't', 'x'}
+	SpecifierBotKeyRotationTransaction  = types.Specifier{'b', 'o', 't', ' ', 'k', 'e', 'y', 'r', 'o', 't', 'a', 't', 'e', ' ', 't', 'x'}
+
+	SpecifierBotRecordUpdateWithFulfillmentTransaction = types.Specifier{'b', 'o', 't', ' ', 'f', 'u', 'l', 'r', 'e', 'c', 'u', 'p', 'd', ' ', 't', 'x'}
+	SpecifierBotNameTransferWithFulfillmentTransaction = types.Specifier{'b', 'o', 't', ' ', 'f', 'u', 'l', 'n', 'a', 'm', 'e', 't', 'r', ' ', 't', 'x'}
+	SpecifierBotNameSaleTransaction                    = types.Specifier{'b', 'o', 't', ' ', 'n', 'a', 'm', 'e', 's', 'a', 'l', 'e', ' ', 't', 'x'}
+	SpecifierBotNameDelegationTransaction              = types.Specifier{'b', 'o', 't', ' ', 'n', 'a', 'm', 'e', 'd', 'e', 'l', 'e', 'g', ' ', 't', 'x'}
+	SpecifierBotRecordUpdateWithServicesTransaction    = types.Specifier{'b', 'o', 't', ' ', 's', 'v', 'c', 'r', 'e', 'c', 'u', 'p', 'd', ' ', 't', 'x'}
 )
 
 // Bot validation errors
 var (
 	ErrBotKeyAlreadyRegistered  = errors.New("bot key is already registered")
 	ErrBotNameAlreadyRegistered = errors.New("bot name is already registered")
+	ErrBotOwnedByCondition      = errors.New("bot is owned by a condition, and can only be modified using a fulfillment")
+	ErrBotParentNameNotFound    = errors.New("parent of bot (sub) name is not owned by an active bot")
+	ErrBotSubNameNotAuthorized  = errors.New("bot (sub) name requires the bot to own, or be a delegate of, its parent name")
 )
 
 type (
@@ -95,14 +133,14 @@ type (
 
 // RequiredBotFee computes the required Bot Fee, that is to be applied as a required
 // additional fee on top of the regular required (minimum) Tx fee.
-func (brtxe *BotRegistrationTransactionExtension) RequiredBotFee(oneCoin types.Currency) types.Currency {
+func (brtxe *BotRegistrationTransactionExtension) RequiredBotFee(params ConsensusParameters) types.Currency {
 	// a static registration fee has to be paid
-	fee := oneCoin.Mul64(BotRegistrationFeeMultiplier)
+	fee := params.BotRegistrationFee
 	// the amount of desired months also has to be paid
-	fee = fee.Add(ComputeMonthlyBotFees(brtxe.NrOfMonths, oneCoin))
+	fee = fee.Add(ComputeMonthlyBotFees(brtxe.NrOfMonths, params))
 	// if more than one name is defined it also has to be paid
 	if n := len(brtxe.Names); n > 1 {
-		fee = fee.Add(oneCoin.Mul64(uint64(n-1) * BotFeePerAdditionalNameMultiplier))
+		fee = fee.Add(params.BotNameFee.Mul64(uint64(n - 1)))
 	}
 	// no fee has to be paid for the used network addresses during registration
 	// return the total fees
@@ -205,13 +243,13 @@ func (brtx *BotRegistrationTransaction) Transaction(oneCoin types.Currency) type
 
 // RequiredBotFee computes the required Bot Fee, that is to be applied as a required
 // additional fee on top of the regular required (minimum) Tx fee.
-func (brtx *BotRegistrationTransaction) RequiredBotFee(oneCoin types.Currency) types.Currency {
+func (brtx *BotRegistrationTransaction) RequiredBotFee(params ConsensusParameters) types.Currency {
 	return (&BotRegistrationTransactionExtension{
 		Addresses:      brtx.Addresses,
 		Names:          brtx.Names,
 		NrOfMonths:     brtx.NrOfMonths,
 		Identification: brtx.Identification,
-	}).RequiredBotFee(oneCoin)
+	}).RequiredBotFee(params)
 }
 
 // MarshalSia implements SiaMarshaler.MarshalSia,
@@ -362,6 +400,13 @@ type (
 		// no more than 5 names can be linked to a single 3bot record.
 		Names BotRecordNameUpdate `json:"names,omitempty"`
 
+		// Services can be used to add and/or remove services
+		// to/from the existing 3bot record. Services can only be updated
+		// using a BotRecordUpdateWithServicesTransaction (0x98), and are thus not part
+		// of the (binary or JSON) encoding of this Tx. It is defined here only,
+		// such that the update logic can be shared between all record update versions.
+		Services BotRecordServiceUpdate `json:"-"`
+
 		// NrOfMonths defines the optional amount of months that
 		// is desired to be paid upfront in this update. Note that the amount of
 		// months defined here defines how much additional fees are to be paid.
@@ -398,6 +443,12 @@ type (
 		Add    []BotName `json:"add,omitempty"`
 		Remove []BotName `json:"remove,omitempty"`
 	}
+	// BotRecordServiceUpdate contains all information required for an update
+	// to the services of a bot's record.
+	BotRecordServiceUpdate struct {
+		Add    []BotService `json:"add,omitempty"`
+		Remove []BotService `json:"remove,omitempty"`
+	}
 	// BotRecordUpdateTransactionExtension defines the BotRecordUpdateTransaction Extension Data
 	BotRecordUpdateTransactionExtension struct {
 		Identifier    BotID
@@ -410,19 +461,19 @@ type (
 
 // RequiredBotFee computes the required Bot Fee, that is to be applied as a required
 // additional fee on top of the regular required (minimum) Tx fee.
-func (brutxe *BotRecordUpdateTransactionExtension) RequiredBotFee(oneCoin types.Currency) (fee types.Currency) {
+func (brutxe *BotRecordUpdateTransactionExtension) RequiredBotFee(params ConsensusParameters) (fee types.Currency) {
 	// all additional months have to be paid
 	if brutxe.NrOfMonths > 0 {
-		fee = fee.Add(ComputeMonthlyBotFees(brutxe.NrOfMonths, oneCoin))
+		fee = fee.Add(ComputeMonthlyBotFees(brutxe.NrOfMonths, params))
 	}
 	// a Tx that modifies the network address info of a 3bot record also has to be paid
 	if len(brutxe.AddressUpdate.Add) > 0 || len(brutxe.AddressUpdate.Remove) > 0 {
-		fee = fee.Add(oneCoin.Mul64(BotFeeForNetworkAddressInfoChangeMultiplier))
+		fee = fee.Add(params.BotNetworkAddressFee)
 	}
 	// each additional name has to be paid as well
 	// (regardless of the fact that the 3bot has a name or not)
 	if n := len(brutxe.NameUpdate.Add); n > 0 {
-		fee = fee.Add(oneCoin.Mul64(BotFeePerAdditionalNameMultiplier * uint64(n)))
+		fee = fee.Add(params.BotNameFee.Mul64(uint64(n)))
 	}
 	// return the total fees
 	return fee
@@ -527,36 +578,43 @@ func (brutx *BotRecordUpdateTransaction) Transaction(oneCoin types.Currency) typ
 
 // RequiredBotFee computes the required Bot Fee, that is to be applied as a required
 // additional fee on top of the regular required (minimum) Tx fee.
-func (brutx *BotRecordUpdateTransaction) RequiredBotFee(oneCoin types.Currency) (fee types.Currency) {
-	return (&BotRecordUpdateTransactionExtension{
+func (brutx *BotRecordUpdateTransaction) RequiredBotFee(params ConsensusParameters) (fee types.Currency) {
+	fee = (&BotRecordUpdateTransactionExtension{
 		Identifier:    brutx.Identifier,
 		Signature:     brutx.Signature,
 		AddressUpdate: brutx.Addresses,
 		NameUpdate:    brutx.Names,
 		NrOfMonths:    brutx.NrOfMonths,
-	}).RequiredBotFee(oneCoin)
+	}).RequiredBotFee(params)
+	// each additional service has to be paid as well
+	if n := len(brutx.Services.Add); n > 0 {
+		fee = fee.Add(ComputeBotServiceFees(n, params))
+	}
+	return fee
 }
 
-// UpdateBotRecord updates the given record, within the context of the given blockTime,
+// UpdateBotRecord updates the given record, within the context of the given blockTime and grace period,
 // using the information of this BotRecordUpdateTransaction.
 //
 // This method should only be called once for the given record,
 // as it has no way of checking whether or not it already updated the given record.
-func (brutx *BotRecordUpdateTransaction) UpdateBotRecord(blockTime types.Timestamp, record *BotRecord) error {
+func (brutx *BotRecordUpdateTransaction) UpdateBotRecord(blockTime types.Timestamp, gracePeriod CompactTimestamp, record *BotRecord) error {
 	var err error
 
-	// if the record indicate the bot is expired, we ensure to reset the names,
-	// and also make sure the NrOfMonths is greater than 0
+	// if the record indicate the bot is expired, we make sure the NrOfMonths is greater than 0,
+	// and ensure to reset the names in case the bot is also no longer within its grace period
 	if record.IsExpired(blockTime) {
 		if brutx.NrOfMonths == 0 {
 			return errors.New("record update Tx does not make bot active, while bot is already expired")
 		}
-		record.ResetNames()
+		if record.IsReleased(blockTime, gracePeriod) {
+			record.ResetNames()
+		}
 	}
 
 	// update the expiration time
 	if brutx.NrOfMonths != 0 {
-		err = record.ExtendExpirationDate(blockTime, brutx.NrOfMonths)
+		err = record.ExtendExpirationDate(blockTime, gracePeriod, brutx.NrOfMonths)
 		if err != nil {
 			return err
 		}
@@ -589,6 +647,17 @@ func (brutx *BotRecordUpdateTransaction) UpdateBotRecord(blockTime types.Timesta
 		return err
 	}
 
+	// remove all services first, afterwards add the new services.
+	// By removing first we ensure that a service can be replaced (e.g. using another port) within a single Tx.
+	err = record.RemoveServices(brutx.Services.Remove...) // passing a nil slice is valid
+	if err != nil {
+		return err
+	}
+	err = record.AddServices(brutx.Services.Add...) // passing a nil slice is valid
+	if err != nil {
+		return err
+	}
+
 	// all good
 	return nil
 }
@@ -629,6 +698,17 @@ func (brutx *BotRecordUpdateTransaction) RevertBotRecordUpdate(record *BotRecord
 		return err
 	}
 
+	// remove all services that were added
+	err = record.RemoveServices(brutx.Services.Add...) // passing a nil slice is valid
+	if err != nil {
+		return err
+	}
+	// add all services that were removed
+	err = record.AddServices(brutx.Services.Remove...) // passing a nil slice is valid
+	if err != nil {
+		return err
+	}
+
 	// all good
 	return nil
 }
@@ -647,6 +727,11 @@ func (brutx *BotRecordUpdateTransaction) UnmarshalSia(r io.Reader) error {
 
 // MarshalRivine implements RivineMarshaler.MarshalRivine
 func (brutx BotRecordUpdateTransaction) MarshalRivine(w io.Writer) error {
+	// services cannot be encoded using this version
+	if len(brutx.Services.Add) > 0 || len(brutx.Services.Remove) > 0 {
+		return errors.New("bot services can only be updated using a bot record update (with services) Tx")
+	}
+
 	// collect length of all the name/addr slices
 	addrAddLen, addrRemoveLen := len(brutx.Addresses.Add), len(brutx.Addresses.Remove)
 	nameAddLen, nameRemoveLen := len(brutx.Names.Add), len(brutx.Names.Remove)
@@ -873,8 +958,8 @@ type (
 
 // RequiredBotFee computes the required Bot Fee, that is to be applied as a required
 // additional fee on top of the regular required (minimum) Tx fee.
-func (bnttxe *BotNameTransferTransactionExtension) RequiredBotFee(oneCoin types.Currency) types.Currency {
-	return oneCoin.Mul64(BotFeePerAdditionalNameMultiplier * uint64(len(bnttxe.Names)))
+func (bnttxe *BotNameTransferTransactionExtension) RequiredBotFee(params ConsensusParameters) types.Currency {
+	return params.BotNameFee.Mul64(uint64(len(bnttxe.Names)))
 }
 
 // BotNameTransferTransactionFromTransaction creates a BotNameTransferTransaction,
@@ -970,12 +1055,12 @@ func (bnttx *BotNameTransferTransaction) Transaction(oneCoin types.Currency) typ
 
 // RequiredBotFee computes the required Bot Fee, that is to be applied as a required
 // additional fee on top of the regular required (minimum) Tx fee.
-func (bnttx *BotNameTransferTransaction) RequiredBotFee(oneCoin types.Currency) types.Currency {
+func (bnttx *BotNameTransferTransaction) RequiredBotFee(params ConsensusParameters) types.Currency {
 	return (&BotNameTransferTransactionExtension{
 		Sender:   bnttx.Sender,
 		Receiver: bnttx.Receiver,
 		Names:    bnttx.Names,
-	}).RequiredBotFee(oneCoin)
+	}).RequiredBotFee(params)
 }
 
 // UpdateReceiverBotRecord updates the given (receiver bot) record, within the context of the given blockTime,
@@ -1148,10 +1233,253 @@ func (bnttx *BotNameTransferTransaction) UnmarshalRivine(r io.Reader) error {
 	return nil
 }
 
+type (
+	// BotKeyRotationTransaction defines the Transaction (with version 0x93)
+	// used to replace the public key and/or owner of an existing 3bot,
+	// such that a 3bot (and its names) isn't lost forever should its key be compromised.
+	BotKeyRotationTransaction struct {
+		// Identifier of the 3bot, used to find the 3bot record of which the key is to be rotated.
+		Identifier BotID `json:"id"`
+
+		// OwnerFulfillment is used to proof the ownership of the 3bot record to be updated,
+		// and has to fulfill the (current) owner condition of the 3bot linked
+		// to the given (3bot) identifier.
+		OwnerFulfillment types.UnlockFulfillmentProxy `json:"ownerfulfillment"`
+
+		// NewIdentification defines the new public key of the 3bot,
+		// as well as the signature used to proof the ownership of that new key.
+		// The new public key cannot be linked to any other 3bot yet.
+		NewIdentification PublicKeySignaturePair `json:"newidentification"`
+		// Owner optionally defines the new owner condition of the 3bot,
+		// if not defined the 3bot will be owned by its new public key.
+		Owner *types.UnlockConditionProxy `json:"owner,omitempty"`
+
+		// TransactionFee defines the regular Tx fee.
+		TransactionFee types.Currency `json:"txfee"`
+
+		// CoinInputs are only used for the required (regular) Tx fee.
+		// At least one CoinInput is required.
+		CoinInputs []types.CoinInput `json:"coininputs"`
+		// RefundCoinOutput is an optional coin output that can be used
+		// to refund coins paid as inputs for the required fees.
+		RefundCoinOutput *types.CoinOutput `json:"refundcoinoutput,omitempty"`
+	}
+	// BotKeyRotationTransactionExtension defines the BotKeyRotationTransaction Extension Data
+	BotKeyRotationTransactionExtension struct {
+		Identifier        BotID
+		OwnerFulfillment  types.UnlockFulfillmentProxy
+		NewIdentification PublicKeySignaturePair
+		Owner             *types.UnlockConditionProxy
+	}
+)
+
+// BotKeyRotationTransactionFromTransaction creates a BotKeyRotationTransaction,
+// using a regular in-memory tfchain transaction.
+//
+// Past the (tx) Version validation it piggy-backs onto the
+// `BotKeyRotationTransactionFromTransactionData` constructor.
+func BotKeyRotationTransactionFromTransaction(tx types.Transaction) (BotKeyRotationTransaction, error) {
+	if tx.Version != TransactionVersionBotKeyRotation {
+		return BotKeyRotationTransaction{}, fmt.Errorf(
+			"a bot key rotation transaction requires tx version %d",
+			TransactionVersionBotKeyRotation)
+	}
+	return BotKeyRotationTransactionFromTransactionData(types.TransactionData{
+		CoinInputs:        tx.CoinInputs,
+		CoinOutputs:       tx.CoinOutputs,
+		BlockStakeInputs:  tx.BlockStakeInputs,
+		BlockStakeOutputs: tx.BlockStakeOutputs,
+		MinerFees:         tx.MinerFees,
+		ArbitraryData:     tx.ArbitraryData,
+		Extension:         tx.Extension,
+	})
+}
+
+// BotKeyRotationTransactionFromTransactionData creates a BotKeyRotationTransaction,
+// using the TransactionData from a regular in-memory tfchain transaction.
+func BotKeyRotationTransactionFromTransactionData(txData types.TransactionData) (BotKeyRotationTransaction, error) {
+	// validate the Transaction Data
+	err := validateBotInMemoryTransactionDataRequirements(txData)
+	if err != nil {
+		return BotKeyRotationTransaction{}, fmt.Errorf("BotKeyRotationTransaction: %v", err)
+	}
+
+	// (tx) extension (data) is expected to be a pointer to a valid BotKeyRotationTransactionExtension,
+	// which contains all the properties unique to a 3bot (key rotation) Tx
+	extensionData, ok := txData.Extension.(*BotKeyRotationTransactionExtension)
+	if !ok {
+		return BotKeyRotationTransaction{}, errors.New("invalid extension data for a BotKeyRotationTransaction")
+	}
+
+	// create the BotKeyRotationTransaction and return it,
+	// all should be good (at least the common requirements, it might still be invalid for version-specific reasons)
+	tx := BotKeyRotationTransaction{
+		Identifier:        extensionData.Identifier,
+		OwnerFulfillment:  extensionData.OwnerFulfillment,
+		NewIdentification: extensionData.NewIdentification,
+		Owner:             extensionData.Owner,
+		TransactionFee:    txData.MinerFees[0],
+		CoinInputs:        txData.CoinInputs,
+	}
+	if len(txData.CoinOutputs) == 1 {
+		// take refund coin output
+		tx.RefundCoinOutput = &txData.CoinOutputs[0]
+	}
+	return tx, nil
+}
+
+// TransactionData returns this BotKeyRotationTransaction
+// as regular tfchain transaction data.
+func (bkrtx *BotKeyRotationTransaction) TransactionData() types.TransactionData {
+	txData := types.TransactionData{
+		CoinInputs: bkrtx.CoinInputs,
+		MinerFees:  []types.Currency{bkrtx.TransactionFee},
+		Extension: &BotKeyRotationTransactionExtension{
+			Identifier:        bkrtx.Identifier,
+			OwnerFulfillment:  bkrtx.OwnerFulfillment,
+			NewIdentification: bkrtx.NewIdentification,
+			Owner:             bkrtx.Owner,
+		},
+	}
+	if bkrtx.RefundCoinOutput != nil {
+		txData.CoinOutputs = append(txData.CoinOutputs, *bkrtx.RefundCoinOutput)
+	}
+	return txData
+}
+
+// Transaction returns this BotKeyRotationTransaction
+// as regular tfchain transaction, using TransactionVersionBotKeyRotation as the type.
+func (bkrtx *BotKeyRotationTransaction) Transaction() types.Transaction {
+	tx := types.Transaction{
+		Version:    TransactionVersionBotKeyRotation,
+		CoinInputs: bkrtx.CoinInputs,
+		MinerFees:  []types.Currency{bkrtx.TransactionFee},
+		Extension: &BotKeyRotationTransactionExtension{
+			Identifier:        bkrtx.Identifier,
+			OwnerFulfillment:  bkrtx.OwnerFulfillment,
+			NewIdentification: bkrtx.NewIdentification,
+			Owner:             bkrtx.Owner,
+		},
+	}
+	if bkrtx.RefundCoinOutput != nil {
+		tx.CoinOutputs = append(tx.CoinOutputs, *bkrtx.RefundCoinOutput)
+	}
+	return tx
+}
+
+// UpdateBotRecord updates the given record,
+// replacing its public key and owner with the new public key and owner defined by this BotKeyRotationTransaction.
+func (bkrtx *BotKeyRotationTransaction) UpdateBotRecord(record *BotRecord) error {
+	if record.ID != bkrtx.Identifier {
+		return fmt.Errorf("bot key rotation is defined for bot %d, not bot %d", bkrtx.Identifier, record.ID)
+	}
+	keyChanged := record.PublicKey.Algorithm != bkrtx.NewIdentification.PublicKey.Algorithm ||
+		!bytes.Equal(record.PublicKey.Key, bkrtx.NewIdentification.PublicKey.Key)
+	var ownerChanged bool
+	if record.Owner == nil || bkrtx.Owner == nil {
+		ownerChanged = record.Owner != bkrtx.Owner
+	} else {
+		ownerChanged = !record.Owner.Equal(bkrtx.Owner.Condition)
+	}
+	if !keyChanged && !ownerChanged {
+		return errors.New("the public key and/or owner of the bot has to be different from its current public key and owner")
+	}
+	record.PublicKey = bkrtx.NewIdentification.PublicKey
+	record.Owner = nil
+	if bkrtx.Owner != nil {
+		owner := *bkrtx.Owner
+		record.Owner = &owner
+	}
+	return nil
+}
+
+// MarshalSia implements SiaMarshaler.MarshalSia,
+// alias of MarshalRivine for backwards-compatibility reasons.
+func (bkrtx BotKeyRotationTransaction) MarshalSia(w io.Writer) error {
+	return bkrtx.MarshalRivine(w)
+}
+
+// UnmarshalSia implements SiaUnmarshaler.UnmarshalSia,
+// alias of UnmarshalRivine for backwards-compatibility reasons.
+func (bkrtx *BotKeyRotationTransaction) UnmarshalSia(r io.Reader) error {
+	return bkrtx.UnmarshalRivine(r)
+}
+
+// MarshalRivine implements RivineMarshaler.MarshalRivine
+func (bkrtx BotKeyRotationTransaction) MarshalRivine(w io.Writer) error {
+	enc := rivbin.NewEncoder(w)
+	err := enc.EncodeAll(
+		bkrtx.Identifier,
+		bkrtx.OwnerFulfillment,
+		bkrtx.NewIdentification,
+	)
+	if err != nil {
+		return err
+	}
+	err = encodeOptionalBotOwner(enc, bkrtx.Owner)
+	if err != nil {
+		return err
+	}
+	return enc.EncodeAll(
+		bkrtx.TransactionFee,
+		bkrtx.CoinInputs,
+		bkrtx.RefundCoinOutput,
+	)
+}
+
+// UnmarshalRivine implements RivineUnmarshaler.UnmarshalRivine
+func (bkrtx *BotKeyRotationTransaction) UnmarshalRivine(r io.Reader) error {
+	dec := rivbin.NewDecoder(r)
+	err := dec.DecodeAll(
+		&bkrtx.Identifier,
+		&bkrtx.OwnerFulfillment,
+		&bkrtx.NewIdentification,
+	)
+	if err != nil {
+		return err
+	}
+	bkrtx.Owner, err = decodeOptionalBotOwner(dec)
+	if err != nil {
+		return err
+	}
+	return dec.DecodeAll(
+		&bkrtx.TransactionFee,
+		&bkrtx.CoinInputs,
+		&bkrtx.RefundCoinOutput,
+	)
+}
+
+// encodeOptionalBotOwner encodes a flag indicating whether or not the owner is defined,
+// followed by the owner condition itself should it be defined.
+func encodeOptionalBotOwner(enc *rivbin.Encoder, owner *types.UnlockConditionProxy) error {
+	if owner == nil {
+		return enc.Encode(false)
+	}
+	return enc.EncodeAll(true, *owner)
+}
+
+// decodeOptionalBotOwner decodes an optional owner condition,
+// encoded using encodeOptionalBotOwner.
+func decodeOptionalBotOwner(dec *rivbin.Decoder) (*types.UnlockConditionProxy, error) {
+	var defined bool
+	err := dec.Decode(&defined)
+	if err != nil || !defined {
+		return nil, err
+	}
+	owner := new(types.UnlockConditionProxy)
+	err = dec.Decode(owner)
+	if err != nil {
+		return nil, err
+	}
+	return owner, nil
+}
+
 // Specifiers used to ensure the bot-signatures are unique within each Tx.
 var (
 	BotSignatureSpecifierSender   = [...]byte{'s', 'e', 'n', 'd', 'e', 'r'}
 	BotSignatureSpecifierReceiver = [...]byte{'r', 'e', 'c', 'e', 'i', 'v', 'e', 'r'}
+	BotSignatureSpecifierOwner    = [...]byte{'o', 'w', 'n', 'e', 'r'}
+	BotSignatureSpecifierNewKey   = [...]byte{'n', 'e', 'w', 'k', 'e', 'y'}
 )
 
 type (
@@ -1168,6 +1496,10 @@ type (
 		//
 		// The transaction identifiers are returned in the (stable) order as defined by the blockchain.
 		GetBotTransactionIdentifiers(id BotID) ([]types.TransactionID, error)
+		// GetDelegatesForName returns the identifiers of all bots the given Name is delegated to,
+		// by the active bot that currently owns that Name. No identifiers are returned,
+		// nor an error, if the Name isn't owned by an active bot.
+		GetDelegatesForName(name BotName) ([]BotID, error)
 	}
 )
 
@@ -1177,6 +1509,10 @@ var (
 	ErrBotKeyNotFound  = errors.New("3bot public key not found")
 	ErrBotNameNotFound = errors.New("3bot name not found")
 	ErrBotNameExpired  = errors.New("3bot name expired")
+	// ErrBotNameInGracePeriod is returned for a name owned by a 3bot that is expired,
+	// but still within its grace period, reserving the name for that 3bot.
+	// It is only returned once the grace period is activated, see BotGracePeriodActivationHeight.
+	ErrBotNameInGracePeriod = errors.New("3bot name is reserved, as its 3bot is within its grace period")
 )
 
 // 3bot Tx controllers
@@ -1188,20 +1524,23 @@ type (
 		Registry            BotRecordReadRegistry
 		RegistryPoolAddress types.UnlockHash
 		OneCoin             types.Currency
+		// ConsensusParameterGetter is used to get the consensus parameters at a given block height,
+		// which define the bot fees to be paid to the registry pool address.
+		ConsensusParameterGetter ConsensusParameterGetter
 	}
 )
 
 var (
 	// ensure at compile time that BotRegistrationTransactionController
 	// implements the desired interfaces
-	_ types.TransactionController                = BotRegistrationTransactionController{}
-	_ types.TransactionExtensionSigner           = BotRegistrationTransactionController{}
-	_ types.TransactionValidator                 = BotRegistrationTransactionController{}
-	_ types.BlockStakeOutputValidator            = BotRegistrationTransactionController{}
-	_ types.TransactionSignatureHasher           = BotRegistrationTransactionController{}
-	_ types.TransactionIDEncoder                 = BotRegistrationTransactionController{}
-	_ types.TransactionCustomMinerPayoutGetter   = BotRegistrationTransactionController{}
-	_ types.TransactionCommonExtensionDataGetter = BotRegistrationTransactionController{}
+	_ types.TransactionController                      = BotRegistrationTransactionController{}
+	_ types.TransactionExtensionSigner                 = BotRegistrationTransactionController{}
+	_ types.TransactionValidator                       = BotRegistrationTransactionController{}
+	_ types.BlockStakeOutputValidator                  = BotRegistrationTransactionController{}
+	_ types.TransactionSignatureHasher                 = BotRegistrationTransactionController{}
+	_ types.TransactionIDEncoder                       = BotRegistrationTransactionController{}
+	_ types.TransactionCustomMinerPayoutAtHeightGetter = BotRegistrationTransactionController{}
+	_ types.TransactionCommonExtensionDataGetter       = BotRegistrationTransactionController{}
 )
 
 // EncodeTransactionData implements TransactionController.EncodeTransactionData
@@ -1343,6 +1682,9 @@ func (brtc BotRegistrationTransactionController) ValidateTransaction(t types.Tra
 		if err == nil {
 			return ErrBotNameAlreadyRegistered
 		}
+		if err == ErrBotNameInGracePeriod {
+			return err
+		}
 		if err != ErrBotNameNotFound {
 			return fmt.Errorf(
 				"unexpected error while validating non-existence of bot's name %v: %v",
@@ -1350,6 +1692,26 @@ func (brtc BotRegistrationTransactionController) ValidateTransaction(t types.Tra
 		}
 	}
 
+	// validate that the bot is authorized to register all sub names,
+	// once the sub name rules are activated
+	params, err := getConsensusParametersForContext(brtc.ConsensusParameterGetter, ctx)
+	if err != nil {
+		return fmt.Errorf("invalid bot registration Tx: %v", err)
+	}
+	if isActivatedForContext(params.BotSubNameActivationHeight, ctx) {
+		var names BotNameSortedSet
+		for _, name := range brtx.Names {
+			err = names.AddName(name)
+			if err != nil {
+				return fmt.Errorf("invalid bot registration Tx: %v", err)
+			}
+		}
+		err = validateBotSubNames(brtc.Registry, 0, names, brtx.Names...)
+		if err != nil {
+			return fmt.Errorf("invalid bot registration Tx: %v", err)
+		}
+	}
+
 	// validate the miner fee
 	if brtx.TransactionFee.Cmp(constants.MinimumMinerFee) == -1 {
 		return types.ErrTooSmallMinerFee
@@ -1417,16 +1779,21 @@ func (brtc BotRegistrationTransactionController) EncodeTransactionIDInput(w io.W
 	return rivbin.NewEncoder(w).EncodeAll(SpecifierBotRegistrationTransaction, brtx)
 }
 
-// GetCustomMinerPayouts implements TransactionCustomMinerPayoutGetter.GetCustomMinerPayouts
-func (brtc BotRegistrationTransactionController) GetCustomMinerPayouts(extension interface{}) ([]types.MinerPayout, error) {
+// GetCustomMinerPayoutsAt implements TransactionCustomMinerPayoutAtHeightGetter.GetCustomMinerPayoutsAt
+func (brtc BotRegistrationTransactionController) GetCustomMinerPayoutsAt(extension interface{}, height types.BlockHeight) ([]types.MinerPayout, error) {
 	// (tx) extension (data) is expected to be a pointer to a valid BotRegistrationTransactionExtension
 	brtxExtension, ok := extension.(*BotRegistrationTransactionExtension)
 	if !ok {
 		return nil, errors.New("invalid extension data for a Bot Registration Transaction")
 	}
+	// the bot fees are defined by the consensus parameters that apply to the block at the given height
+	params, err := brtc.ConsensusParameterGetter.GetConsensusParametersAt(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the consensus parameters at block height %d: %v", height, err)
+	}
 	return []types.MinerPayout{
 		{
-			Value:      brtxExtension.RequiredBotFee(brtc.OneCoin),
+			Value:      brtxExtension.RequiredBotFee(params),
 			UnlockHash: brtc.RegistryPoolAddress,
 		},
 	}, nil
@@ -1453,19 +1820,22 @@ type (
 		Registry            BotRecordReadRegistry
 		RegistryPoolAddress types.UnlockHash
 		OneCoin             types.Currency
+		// ConsensusParameterGetter is used to get the consensus parameters at a given block height,
+		// which define the bot fees to be paid to the registry pool address.
+		ConsensusParameterGetter ConsensusParameterGetter
 	}
 )
 
 var (
 	// ensure at compile time that BotUpdateRecordTransactionController
 	// implements the desired interfaces
-	_ types.TransactionController              = BotUpdateRecordTransactionController{}
-	_ types.TransactionExtensionSigner         = BotUpdateRecordTransactionController{}
-	_ types.TransactionValidator               = BotUpdateRecordTransactionController{}
-	_ types.BlockStakeOutputValidator          = BotUpdateRecordTransactionController{}
-	_ types.TransactionSignatureHasher         = BotUpdateRecordTransactionController{}
-	_ types.TransactionIDEncoder               = BotUpdateRecordTransactionController{}
-	_ types.TransactionCustomMinerPayoutGetter = BotUpdateRecordTransactionController{}
+	_ types.TransactionController                      = BotUpdateRecordTransactionController{}
+	_ types.TransactionExtensionSigner                 = BotUpdateRecordTransactionController{}
+	_ types.TransactionValidator                       = BotUpdateRecordTransactionController{}
+	_ types.BlockStakeOutputValidator                  = BotUpdateRecordTransactionController{}
+	_ types.TransactionSignatureHasher                 = BotUpdateRecordTransactionController{}
+	_ types.TransactionIDEncoder                       = BotUpdateRecordTransactionController{}
+	_ types.TransactionCustomMinerPayoutAtHeightGetter = BotUpdateRecordTransactionController{}
 )
 
 // EncodeTransactionData implements TransactionController.EncodeTransactionData
@@ -1560,30 +1930,52 @@ func (brutc BotUpdateRecordTransactionController) ValidateTransaction(t types.Tr
 	}
 
 	// validate the signature of the to-be-updated bot
-	err = validateBotSignature(t, record.PublicKey, brutx.Signature, ctx, BotSignatureSpecifierSender)
+	err = validateBotRecordSignature(t, record, brutx.Signature, ctx, BotSignatureSpecifierSender)
 	if err != nil {
 		return fmt.Errorf("failed to fulfill bot record update condition: %v", err)
 	}
 
+	// validate the update itself
+	return validateBotRecordUpdate(brutc.Registry, brutc.ConsensusParameterGetter, &brutx, record, ctx)
+}
+
+// validateBotRecordUpdate validates the given update can be applied to the given record,
+// logic shared by all bot record update transaction versions.
+func validateBotRecordUpdate(registry BotRecordReadRegistry, getter ConsensusParameterGetter, brutx *BotRecordUpdateTransaction, record *BotRecord, ctx types.ValidationContext) error {
 	// at least something has to be updated, a nop-update is not allowed
 	if brutx.NrOfMonths == 0 &&
 		len(brutx.Addresses.Add) == 0 && len(brutx.Addresses.Remove) == 0 &&
-		len(brutx.Names.Add) == 0 && len(brutx.Names.Remove) == 0 {
-		return errors.New("bot record updates requires nrOfMonths, a name or address to be defined")
+		len(brutx.Names.Add) == 0 && len(brutx.Names.Remove) == 0 &&
+		len(brutx.Services.Add) == 0 && len(brutx.Services.Remove) == 0 {
+		return errors.New("bot record updates requires nrOfMonths, a name, address or service to be defined")
+	}
+
+	params, err := getConsensusParametersForContext(getter, ctx)
+	if err != nil {
+		return fmt.Errorf("bot cannot be updated: %v", err)
 	}
 
 	// ensure all to-be-added names are available
-	err = areBotNamesAvailable(brutc.Registry, brutx.Names.Add...)
+	err = areBotNamesAvailable(registry, brutx.Names.Add...)
 	if err != nil {
 		return fmt.Errorf("bot cannot be updated: areBotNamesAvailable: %v", err)
 	}
 
 	// try to update the record, to spot any errors should that happen for real
-	err = brutx.UpdateBotRecord(ctx.BlockTime, record)
+	err = brutx.UpdateBotRecord(ctx.BlockTime, params.BotNameGracePeriodAt(getBlockHeightForContext(ctx)), record)
 	if err != nil {
 		return fmt.Errorf("bot cannot be updated: UpdateBotRecord: %v", err)
 	}
 
+	// ensure the bot is authorized to own all to-be-added sub names,
+	// once the sub name rules are activated
+	if isActivatedForContext(params.BotSubNameActivationHeight, ctx) {
+		err = validateBotSubNames(registry, record.ID, record.Names, brutx.Names.Add...)
+		if err != nil {
+			return fmt.Errorf("bot cannot be updated: %v", err)
+		}
+	}
+
 	// update Tx is valid
 	return nil
 }
@@ -1604,13 +1996,18 @@ func (brutc BotUpdateRecordTransactionController) SignatureHash(t types.Transact
 	if err != nil {
 		return crypto.Hash{}, fmt.Errorf("failed to use tx as a BotRecordUpdateTx: %v", err)
 	}
+	return botRecordUpdateSignatureHash(t.Version, SpecifierBotRecordUpdateTransaction, &brutx, extraObjects...), nil
+}
 
+// botRecordUpdateSignatureHash computes the signature hash of a bot record update,
+// logic shared by all bot record update transaction versions.
+func botRecordUpdateSignatureHash(version types.TransactionVersion, specifier types.Specifier, brutx *BotRecordUpdateTransaction, extraObjects ...interface{}) crypto.Hash {
 	h := crypto.NewHash()
 	enc := rivbin.NewEncoder(h)
 
 	enc.EncodeAll(
-		t.Version,
-		SpecifierBotRecordUpdateTransaction,
+		version,
+		specifier,
 		brutx.Identifier,
 	)
 
@@ -1623,6 +2020,11 @@ func (brutc BotUpdateRecordTransactionController) SignatureHash(t types.Transact
 		brutx.Names,
 		brutx.NrOfMonths,
 	)
+	// services are only part of the signature of the version that supports them,
+	// such that the signatures of the other versions remain unchanged
+	if version == TransactionVersionBotRecordUpdateWithServices {
+		enc.Encode(brutx.Services)
+	}
 
 	enc.Encode(len(brutx.CoinInputs))
 	for _, ci := range brutx.CoinInputs {
@@ -1636,7 +2038,7 @@ func (brutc BotUpdateRecordTransactionController) SignatureHash(t types.Transact
 
 	var hash crypto.Hash
 	h.Sum(hash[:0])
-	return hash, nil
+	return hash
 }
 
 // EncodeTransactionIDInput implements TransactionIDEncoder.EncodeTransactionIDInput
@@ -1648,16 +2050,21 @@ func (brutc BotUpdateRecordTransactionController) EncodeTransactionIDInput(w io.
 	return rivbin.NewEncoder(w).EncodeAll(SpecifierBotRecordUpdateTransaction, brutx)
 }
 
-// GetCustomMinerPayouts implements TransactionCustomMinerPayoutGetter.GetCustomMinerPayouts
-func (brutc BotUpdateRecordTransactionController) GetCustomMinerPayouts(extension interface{}) ([]types.MinerPayout, error) {
+// GetCustomMinerPayoutsAt implements TransactionCustomMinerPayoutAtHeightGetter.GetCustomMinerPayoutsAt
+func (brutc BotUpdateRecordTransactionController) GetCustomMinerPayoutsAt(extension interface{}, height types.BlockHeight) ([]types.MinerPayout, error) {
 	// (tx) extension (data) is expected to be a pointer to a valid BotRecordUpdateTransactionExtension
 	brutxExtension, ok := extension.(*BotRecordUpdateTransactionExtension)
 	if !ok {
 		return nil, errors.New("invalid extension data for a Bot RecordUpdate Transaction")
 	}
+	// the bot fees are defined by the consensus parameters that apply to the block at the given height
+	params, err := brutc.ConsensusParameterGetter.GetConsensusParametersAt(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the consensus parameters at block height %d: %v", height, err)
+	}
 	return []types.MinerPayout{
 		{
-			Value:      brutxExtension.RequiredBotFee(brutc.OneCoin),
+			Value:      brutxExtension.RequiredBotFee(params),
 			UnlockHash: brutc.RegistryPoolAddress,
 		},
 	}, nil
@@ -1671,19 +2078,22 @@ type (
 		Registry            BotRecordReadRegistry
 		RegistryPoolAddress types.UnlockHash
 		OneCoin             types.Currency
+		// ConsensusParameterGetter is used to get the consensus parameters at a given block height,
+		// which define the bot fees to be paid to the registry pool address.
+		ConsensusParameterGetter ConsensusParameterGetter
 	}
 )
 
 var (
 	// ensure at compile time that BotNameTransferTransactionController
 	// implements the desired interfaces
-	_ types.TransactionController              = BotNameTransferTransactionController{}
-	_ types.TransactionExtensionSigner         = BotNameTransferTransactionController{}
-	_ types.TransactionValidator               = BotNameTransferTransactionController{}
-	_ types.BlockStakeOutputValidator          = BotNameTransferTransactionController{}
-	_ types.TransactionSignatureHasher         = BotNameTransferTransactionController{}
-	_ types.TransactionIDEncoder               = BotNameTransferTransactionController{}
-	_ types.TransactionCustomMinerPayoutGetter = BotNameTransferTransactionController{}
+	_ types.TransactionController                      = BotNameTransferTransactionController{}
+	_ types.TransactionExtensionSigner                 = BotNameTransferTransactionController{}
+	_ types.TransactionValidator                       = BotNameTransferTransactionController{}
+	_ types.BlockStakeOutputValidator                  = BotNameTransferTransactionController{}
+	_ types.TransactionSignatureHasher                 = BotNameTransferTransactionController{}
+	_ types.TransactionIDEncoder                       = BotNameTransferTransactionController{}
+	_ types.TransactionCustomMinerPayoutAtHeightGetter = BotNameTransferTransactionController{}
 )
 
 // EncodeTransactionData implements TransactionController.EncodeTransactionData
@@ -1805,23 +2215,30 @@ func (bnttc BotNameTransferTransactionController) ValidateTransaction(t types.Tr
 	}
 
 	// validate the signature of the sender
-	err = validateBotSignature(t, recordSender.PublicKey, bnttx.Sender.Signature, ctx, BotSignatureSpecifierSender)
+	err = validateBotRecordSignature(t, recordSender, bnttx.Sender.Signature, ctx, BotSignatureSpecifierSender)
 	if err != nil {
 		return fmt.Errorf("failed to fulfill bot record name transfer condition of the sender: %v", err)
 	}
 	// validate the signature of the receiver
-	err = validateBotSignature(t, recordReceiver.PublicKey, bnttx.Receiver.Signature, ctx, BotSignatureSpecifierReceiver)
+	err = validateBotRecordSignature(t, recordReceiver, bnttx.Receiver.Signature, ctx, BotSignatureSpecifierReceiver)
 	if err != nil {
 		return fmt.Errorf("failed to fulfill bot record name transfer condition of the receiver: %v", err)
 	}
 
+	// validate the transfer itself
+	return validateBotNameTransfer(bnttc.Registry, bnttc.ConsensusParameterGetter, &bnttx, recordSender, recordReceiver, ctx)
+}
+
+// validateBotNameTransfer validates the given name transfer can be applied to the given sender and receiver records,
+// logic shared by all bot name transfer transaction versions.
+func validateBotNameTransfer(registry BotRecordReadRegistry, getter ConsensusParameterGetter, bnttx *BotNameTransferTransaction, recordSender, recordReceiver *BotRecord, ctx types.ValidationContext) error {
 	// at least one name has to be transferred
 	if len(bnttx.Names) == 0 {
 		return errors.New("a bot name transfer transaction has to transfer at least one name")
 	}
 
 	// try to update the sender bot (if the sender bot is expired, an error is returned as well)
-	err = bnttx.UpdateSenderBotRecord(ctx.BlockTime, recordSender)
+	err := bnttx.UpdateSenderBotRecord(ctx.BlockTime, recordSender)
 	if err != nil {
 		return fmt.Errorf("sender bot (%v) cannot be updated by name transfer: %v", bnttx.Sender.Identifier, err)
 	}
@@ -1834,6 +2251,19 @@ func (bnttc BotNameTransferTransactionController) ValidateTransaction(t types.Tr
 		return fmt.Errorf("receiver bot (%v) cannot be updated by name transfer: %v", bnttx.Receiver.Identifier, err)
 	}
 
+	// ensure the receiver bot is authorized to own all transferred sub names,
+	// once the sub name rules are activated
+	params, err := getConsensusParametersForContext(getter, ctx)
+	if err != nil {
+		return fmt.Errorf("receiver bot (%v) cannot be updated by name transfer: %v", bnttx.Receiver.Identifier, err)
+	}
+	if isActivatedForContext(params.BotSubNameActivationHeight, ctx) {
+		err = validateBotSubNames(registry, recordReceiver.ID, recordReceiver.Names, bnttx.Names...)
+		if err != nil {
+			return fmt.Errorf("receiver bot (%v) cannot be updated by name transfer: %v", bnttx.Receiver.Identifier, err)
+		}
+	}
+
 	// given all names originate from the sender,
 	// we do not require availability checks of names, as no names will be available at this point
 
@@ -1857,13 +2287,18 @@ func (bnttc BotNameTransferTransactionController) SignatureHash(t types.Transact
 	if err != nil {
 		return crypto.Hash{}, fmt.Errorf("failed to use tx as a BotNameTransferTx: %v", err)
 	}
+	return botNameTransferSignatureHash(t.Version, SpecifierBotNameTransferTransaction, &bnttx, extraObjects...), nil
+}
 
+// botNameTransferSignatureHash computes the signature hash of a bot name transfer,
+// logic shared by all bot name transfer transaction versions.
+func botNameTransferSignatureHash(version types.TransactionVersion, specifier types.Specifier, bnttx *BotNameTransferTransaction, extraObjects ...interface{}) crypto.Hash {
 	h := crypto.NewHash()
 	enc := rivbin.NewEncoder(h)
 
 	enc.EncodeAll(
-		t.Version,
-		SpecifierBotNameTransferTransaction,
+		version,
+		specifier,
 		bnttx.Sender.Identifier,
 		bnttx.Receiver.Identifier,
 	)
@@ -1888,7 +2323,7 @@ func (bnttc BotNameTransferTransactionController) SignatureHash(t types.Transact
 
 	var hash crypto.Hash
 	h.Sum(hash[:0])
-	return hash, nil
+	return hash
 }
 
 // EncodeTransactionIDInput implements TransactionIDEncoder.EncodeTransactionIDInput
@@ -1900,29 +2335,294 @@ func (bnttc BotNameTransferTransactionController) EncodeTransactionIDInput(w io.
 	return rivbin.NewEncoder(w).EncodeAll(SpecifierBotNameTransferTransaction, bnttx)
 }
 
-// GetCustomMinerPayouts implements TransactionCustomMinerPayoutGetter.GetCustomMinerPayouts
-func (bnttc BotNameTransferTransactionController) GetCustomMinerPayouts(extension interface{}) ([]types.MinerPayout, error) {
+// GetCustomMinerPayoutsAt implements TransactionCustomMinerPayoutAtHeightGetter.GetCustomMinerPayoutsAt
+func (bnttc BotNameTransferTransactionController) GetCustomMinerPayoutsAt(extension interface{}, height types.BlockHeight) ([]types.MinerPayout, error) {
 	// (tx) extension (data) is expected to be a pointer to a valid BotNameTransferTransactionExtension
 	bnttxExtension, ok := extension.(*BotNameTransferTransactionExtension)
 	if !ok {
 		return nil, errors.New("invalid extension data for a Bot NameTransfer Transaction")
 	}
+	// the bot fees are defined by the consensus parameters that apply to the block at the given height
+	params, err := bnttc.ConsensusParameterGetter.GetConsensusParametersAt(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the consensus parameters at block height %d: %v", height, err)
+	}
 	return []types.MinerPayout{
 		{
-			Value:      bnttxExtension.RequiredBotFee(bnttc.OneCoin),
+			Value:      bnttxExtension.RequiredBotFee(params),
 			UnlockHash: bnttc.RegistryPoolAddress,
 		},
 	}, nil
 }
 
+type (
+	// BotKeyRotationTransactionController defines a tfchain-specific transaction controller,
+	// for a transaction type reserved at type 0x93. It allows the public key of an existing 3bot to be replaced.
+	BotKeyRotationTransactionController struct {
+		Registry BotRecordReadRegistry
+	}
+)
+
+var (
+	// ensure at compile time that BotKeyRotationTransactionController
+	// implements the desired interfaces
+	_ types.TransactionController                = BotKeyRotationTransactionController{}
+	_ types.TransactionExtensionSigner           = BotKeyRotationTransactionController{}
+	_ types.TransactionValidator                 = BotKeyRotationTransactionController{}
+	_ types.BlockStakeOutputValidator            = BotKeyRotationTransactionController{}
+	_ types.TransactionSignatureHasher           = BotKeyRotationTransactionController{}
+	_ types.TransactionIDEncoder                 = BotKeyRotationTransactionController{}
+	_ types.TransactionCommonExtensionDataGetter = BotKeyRotationTransactionController{}
+)
+
+// EncodeTransactionData implements TransactionController.EncodeTransactionData
+func (bkrtc BotKeyRotationTransactionController) EncodeTransactionData(w io.Writer, txData types.TransactionData) error {
+	bkrtx, err := BotKeyRotationTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a BotKeyRotationTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).Encode(bkrtx)
+}
+
+// DecodeTransactionData implements TransactionController.DecodeTransactionData
+func (bkrtc BotKeyRotationTransactionController) DecodeTransactionData(r io.Reader) (types.TransactionData, error) {
+	var bkrtx BotKeyRotationTransaction
+	err := rivbin.NewDecoder(r).Decode(&bkrtx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to binary-decode tx as a BotKeyRotationTx: %v", err)
+	}
+	// return bot key rotation tx as regular tfchain tx data
+	return bkrtx.TransactionData(), nil
+}
+
+// JSONEncodeTransactionData implements TransactionController.JSONEncodeTransactionData
+func (bkrtc BotKeyRotationTransactionController) JSONEncodeTransactionData(txData types.TransactionData) ([]byte, error) {
+	bkrtx, err := BotKeyRotationTransactionFromTransactionData(txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert txData to a BotKeyRotationTx: %v", err)
+	}
+	return json.Marshal(bkrtx)
+}
+
+// JSONDecodeTransactionData implements TransactionController.JSONDecodeTransactionData
+func (bkrtc BotKeyRotationTransactionController) JSONDecodeTransactionData(data []byte) (types.TransactionData, error) {
+	var bkrtx BotKeyRotationTransaction
+	err := json.Unmarshal(data, &bkrtx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to json-decode tx as a BotKeyRotationTx: %v", err)
+	}
+	// return bot key rotation tx as regular tfchain tx data
+	return bkrtx.TransactionData(), nil
+}
+
+// SignExtension implements TransactionExtensionSigner.SignExtension
+func (bkrtc BotKeyRotationTransactionController) SignExtension(extension interface{}, sign func(*types.UnlockFulfillmentProxy, types.UnlockConditionProxy, ...interface{}) error) (interface{}, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid BotKeyRotationTransactionExtension
+	bkrtxExtension, ok := extension.(*BotKeyRotationTransactionExtension)
+	if !ok {
+		return nil, errors.New("invalid extension data for a BotKeyRotationTx")
+	}
+
+	// sign as the current owner
+	condition, fulfillment, err := getConditionAndFulfillmentForBotOwner(bkrtc.Registry, bkrtxExtension.Identifier, bkrtxExtension.OwnerFulfillment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare the signing (as the owner) of the BotKeyRotationTx: %v", err)
+	}
+	err = sign(&fulfillment, condition, BotSignatureSpecifierOwner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign (as the owner) the BotKeyRotationTx: %v", err)
+	}
+	bkrtxExtension.OwnerFulfillment = fulfillment
+
+	// (or) sign using the new key
+	condition, fulfillment, err = getConditionAndFulfillmentForBotPublicKey(bkrtxExtension.NewIdentification.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare the signing (using the new key) of the BotKeyRotationTx: %v", err)
+	}
+	err = sign(&fulfillment, condition, BotSignatureSpecifierNewKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign (using the new key) the BotKeyRotationTx: %v", err)
+	}
+	signature := fulfillment.Fulfillment.(*types.SingleSignatureFulfillment).Signature
+	if len(signature) > 0 { // extract signature, only if we actually signed
+		bkrtxExtension.NewIdentification.Signature = signature
+	}
+
+	// and return the signed extension
+	return bkrtxExtension, nil
+}
+
+// ValidateTransaction implements TransactionValidator.ValidateTransaction
+func (bkrtc BotKeyRotationTransactionController) ValidateTransaction(t types.Transaction, ctx types.ValidationContext, constants types.TransactionValidationConstants) error {
+	// given the strict typing of 3bot transactions,
+	// it is guaranteed by its properties that it will always fit within a Block,
+	// and thus the TransactionFitsInABlock is not needed.
+
+	// get BotKeyRotationTx
+	bkrtx, err := BotKeyRotationTransactionFromTransaction(t)
+	if err != nil {
+		return fmt.Errorf("failed to use tx as a bot key rotation tx: %v", err)
+	}
+
+	// validate the miner fee
+	if bkrtx.TransactionFee.Cmp(constants.MinimumMinerFee) == -1 {
+		return types.ErrTooSmallMinerFee
+	}
+
+	// look up the record, using the given ID, to ensure it is registered,
+	// as well as for validation checks that follow
+	record, err := bkrtc.Registry.GetRecordForID(bkrtx.Identifier)
+	if err != nil {
+		return fmt.Errorf("invalid bot (%d) for key rotation: %v", bkrtx.Identifier, err)
+	}
+
+	// look up the new public key, to ensure it is not registered for another bot yet
+	keyRecord, err := bkrtc.Registry.GetRecordForKey(bkrtx.NewIdentification.PublicKey)
+	if err == nil {
+		if keyRecord.ID != record.ID {
+			return ErrBotKeyAlreadyRegistered
+		}
+	} else if err != ErrBotKeyNotFound {
+		return fmt.Errorf("unexpected error while validating non-existence of bot's new public key: %v", err)
+	}
+
+	// validate the new owner condition, if defined
+	if bkrtx.Owner != nil {
+		err = validateBotOwnerCondition(*bkrtx.Owner, ctx)
+		if err != nil {
+			return fmt.Errorf("invalid owner condition for bot (%d): %v", bkrtx.Identifier, err)
+		}
+	}
+
+	// validate the fulfillment of the current owner
+	err = validateBotRecordFulfillment(t, record, bkrtx.OwnerFulfillment, ctx, BotSignatureSpecifierOwner)
+	if err != nil {
+		return fmt.Errorf("failed to fulfill bot key rotation condition of the current owner: %v", err)
+	}
+	// validate the signature of the new key
+	err = validateBotSignature(t, bkrtx.NewIdentification.PublicKey, bkrtx.NewIdentification.Signature, ctx, BotSignatureSpecifierNewKey)
+	if err != nil {
+		return fmt.Errorf("failed to fulfill bot key rotation condition of the new key: %v", err)
+	}
+
+	// try to update the bot record
+	err = bkrtx.UpdateBotRecord(record)
+	if err != nil {
+		return fmt.Errorf("bot (%v) cannot be updated by key rotation: %v", bkrtx.Identifier, err)
+	}
+
+	// key rotation Tx is valid
+	return nil
+}
+
+// Rivine handles ValidateCoinOutputs,
+// which is possible as all our coin inputs are standard,
+// and the (single) miner fee is standard as well.
+
+// ValidateBlockStakeOutputs implements BlockStakeOutputValidator.ValidateBlockStakeOutputs
+func (bkrtc BotKeyRotationTransactionController) ValidateBlockStakeOutputs(t types.Transaction, ctx types.FundValidationContext, blockStakeInputs map[types.BlockStakeOutputID]types.BlockStakeOutput) (err error) {
+	return nil // always valid, no block stake inputs/outputs exist within a bot key rotation transaction
+}
+
+// SignatureHash implements TransactionSignatureHasher.SignatureHash
+func (bkrtc BotKeyRotationTransactionController) SignatureHash(t types.Transaction, extraObjects ...interface{}) (crypto.Hash, error) {
+	bkrtx, err := BotKeyRotationTransactionFromTransaction(t)
+	if err != nil {
+		return crypto.Hash{}, fmt.Errorf("failed to use tx as a BotKeyRotationTx: %v", err)
+	}
+
+	h := crypto.NewHash()
+	enc := rivbin.NewEncoder(h)
+
+	enc.EncodeAll(
+		t.Version,
+		SpecifierBotKeyRotationTransaction,
+		bkrtx.Identifier,
+	)
+
+	if len(extraObjects) > 0 {
+		enc.EncodeAll(extraObjects...)
+	}
+
+	enc.Encode(bkrtx.NewIdentification.PublicKey)
+	encodeOptionalBotOwner(enc, bkrtx.Owner)
+
+	enc.Encode(len(bkrtx.CoinInputs))
+	for _, ci := range bkrtx.CoinInputs {
+		enc.Encode(ci.ParentID)
+	}
+
+	enc.EncodeAll(
+		bkrtx.TransactionFee,
+		bkrtx.RefundCoinOutput,
+	)
+
+	var hash crypto.Hash
+	h.Sum(hash[:0])
+	return hash, nil
+}
+
+// EncodeTransactionIDInput implements TransactionIDEncoder.EncodeTransactionIDInput
+func (bkrtc BotKeyRotationTransactionController) EncodeTransactionIDInput(w io.Writer, txData types.TransactionData) error {
+	bkrtx, err := BotKeyRotationTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a BotKeyRotationTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).EncodeAll(SpecifierBotKeyRotationTransaction, bkrtx)
+}
+
+// GetCommonExtensionData implements TransactionCommonExtensionDataGetter.GetCommonExtensionData
+func (bkrtc BotKeyRotationTransactionController) GetCommonExtensionData(extension interface{}) (types.CommonTransactionExtensionData, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid BotKeyRotationTransactionExtension
+	bkrtxExtension, ok := extension.(*BotKeyRotationTransactionExtension)
+	if !ok {
+		return types.CommonTransactionExtensionData{}, errors.New("invalid extension data for a Bot Key Rotation Transaction")
+	}
+	data := types.CommonTransactionExtensionData{
+		UnlockConditions: []types.UnlockConditionProxy{
+			types.NewCondition(types.NewUnlockHashCondition(types.NewPubKeyUnlockHash(bkrtxExtension.NewIdentification.PublicKey))),
+		},
+	}
+	if bkrtxExtension.Owner != nil {
+		data.UnlockConditions = append(data.UnlockConditions, *bkrtxExtension.Owner)
+	}
+	return data, nil
+}
+
 func getConditionAndFulfillmentForBotID(registry BotRecordReadRegistry, id BotID) (types.UnlockConditionProxy, types.UnlockFulfillmentProxy, error) {
 	record, err := registry.GetRecordForID(id)
 	if err != nil {
 		return types.UnlockConditionProxy{}, types.UnlockFulfillmentProxy{}, err
 	}
+	if record.Owner != nil {
+		return types.UnlockConditionProxy{}, types.UnlockFulfillmentProxy{}, ErrBotOwnedByCondition
+	}
 	return getConditionAndFulfillmentForBotPublicKey(record.PublicKey)
 }
 
+// getConditionAndFulfillmentForBotOwner returns the owner condition of the bot linked to the given ID,
+// as well as the given fulfillment, prepared for signing should it not be defined yet.
+func getConditionAndFulfillmentForBotOwner(registry BotRecordReadRegistry, id BotID, fulfillment types.UnlockFulfillmentProxy) (types.UnlockConditionProxy, types.UnlockFulfillmentProxy, error) {
+	record, err := registry.GetRecordForID(id)
+	if err != nil {
+		return types.UnlockConditionProxy{}, types.UnlockFulfillmentProxy{}, err
+	}
+	condition := record.OwnerCondition()
+	if fulfillment.FulfillmentType() == types.FulfillmentTypeNil {
+		switch {
+		case record.Owner == nil:
+			fulfillment = types.NewFulfillment(types.NewSingleSignatureFulfillment(record.PublicKey))
+		case condition.ConditionType() == types.ConditionTypeMultiSignature:
+			fulfillment = types.NewFulfillment(types.NewMultiSignatureFulfillment(nil))
+		}
+		// the fulfillment of a PubKey-UnlockHash owner condition is left to the signer,
+		// as the public key cannot be derived from the condition
+	}
+	return condition, fulfillment, nil
+}
+
 func getConditionAndFulfillmentForBotPublicKey(pk types.PublicKey) (types.UnlockConditionProxy, types.UnlockFulfillmentProxy, error) {
 	// create a publicKeyUnlockHashCondition
 	condition := types.NewCondition(types.NewUnlockHashCondition(types.NewPubKeyUnlockHash(pk)))
@@ -1954,15 +2654,14 @@ func validateBotInMemoryTransactionDataRequirements(txData types.TransactionData
 }
 
 // ComputeMonthlyBotFees computes the total monthly fees required for the given months,
-// using the given oneCoin value as the currency's unit value.
-func ComputeMonthlyBotFees(months uint8, oneCoin types.Currency) types.Currency {
-	multiplier := uint64(months) * BotMonthlyFeeMultiplier
+// using the monthly fee defined by the given consensus parameters.
+func ComputeMonthlyBotFees(months uint8, params ConsensusParameters) types.Currency {
 	if months < 12 {
 		// return plain monthly fees without discounts
-		return oneCoin.Mul64(multiplier)
+		return params.BotMonthlyFee.Mul64(uint64(months))
 	}
-	fees := big.NewFloat(float64(multiplier))
-	fees.Mul(fees, new(big.Float).SetInt(oneCoin.Big()))
+	fees := big.NewFloat(float64(months))
+	fees.Mul(fees, new(big.Float).SetInt(params.BotMonthlyFee.Big()))
 	if months < 24 {
 		// return plain monthly fees with 30% discount applied to the total
 		i, _ := fees.Mul(fees, big.NewFloat(0.7)).Int(nil)
@@ -1973,6 +2672,14 @@ func ComputeMonthlyBotFees(months uint8, oneCoin types.Currency) types.Currency
 	return types.NewCurrency(i)
 }
 
+// ComputeBotServiceFees computes the fees to be paid for the given amount of services
+// added to a 3bot record. As is the case for names, services are paid once, when added,
+// such that the fees can be computed using only the information within the Tx
+// and the consensus parameters that apply to it.
+func ComputeBotServiceFees(services int, params ConsensusParameters) types.Currency {
+	return params.BotServiceFee.Mul64(uint64(services))
+}
+
 // BotMonthsAndFlagsData is a utility structure that is used to encode
 // the NrOfMonths (paid up front for a 3bot) as well as several flags
 // in a single byte.
@@ -2072,6 +2779,11 @@ func areBotNamesAvailable(registry BotRecordReadRegistry, names ...BotName) erro
 			// meaning the name is linked to a non-expired 3bot,
 			// and consequently the name is not available
 			return ErrBotNameAlreadyRegistered
+		case ErrBotNameInGracePeriod:
+			// the name is linked to an expired 3bot, still within its grace period,
+			// only that 3bot can keep it, by renewing itself
+			// (only returned by the registry once the grace period is activated)
+			return err
 		default:
 			return err // unexpected
 		}
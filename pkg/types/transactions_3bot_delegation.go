@@ -0,0 +1,427 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// Bot name delegation validation errors
+var (
+	ErrBotNameNotOwned             = errors.New("bot name is not owned by the given (active) bot")
+	ErrTooManyBotNameDelegates     = errors.New("too many delegates defined for a single bot name")
+	ErrBotNameDelegateExists       = errors.New("bot name is already delegated to the given bot")
+	ErrBotNameDelegateDoesNotExist = errors.New("bot name is not delegated to the given bot")
+)
+
+type (
+	// BotNameDelegationTransaction defines the Transaction (with version 0x97)
+	// used by the owner of a 3bot name to delegate the right to own sub names of that name
+	// to other 3bots, or to revoke that right from 3bots it was delegated to earlier.
+	//
+	// A delegation is only valid as long as the name is owned by the 3bot that delegated it,
+	// should the name be transferred, or expire, all its delegations are voided.
+	// Sub names already owned by a delegate are never affected by a revoked delegation.
+	//
+	// As delegates are identified by their 3bot identifier, only registered 3bots can be a delegate,
+	// and claim delegated sub names by updating their record, rather than as part of their registration.
+	BotNameDelegationTransaction struct {
+		// Owner is the 3bot that owns the name of which the delegates are updated.
+		Owner BotIdentifierFulfillmentPair `json:"owner"`
+		// Name is the name that is delegated, and has to be owned by the owner.
+		Name BotName `json:"name"`
+		// Delegates defines the identifiers of the 3bots that are to be added and/or removed
+		// as a delegate of the name. Note that after each Tx, no more than 10 bots can be
+		// a delegate of a single name.
+		Delegates BotNameDelegatesUpdate `json:"delegates"`
+
+		// TransactionFee defines the regular Tx fee.
+		TransactionFee types.Currency `json:"txfee"`
+
+		// CoinInputs are only used for the required (regular) Tx fee.
+		// At least one CoinInput is required.
+		CoinInputs []types.CoinInput `json:"coininputs"`
+		// RefundCoinOutput is an optional coin output that can be used
+		// to refund coins paid as inputs for the required fees.
+		RefundCoinOutput *types.CoinOutput `json:"refundcoinoutput,omitempty"`
+	}
+	// BotNameDelegatesUpdate contains all information required for an update
+	// to the delegates of a bot name.
+	BotNameDelegatesUpdate struct {
+		Add    []BotID `json:"add,omitempty"`
+		Remove []BotID `json:"remove,omitempty"`
+	}
+	// BotNameDelegationTransactionExtension defines the
+	// BotNameDelegationTransaction Extension Data
+	BotNameDelegationTransactionExtension struct {
+		Owner     BotIdentifierFulfillmentPair
+		Name      BotName
+		Delegates BotNameDelegatesUpdate
+	}
+)
+
+// BotNameDelegationTransactionFromTransaction creates a BotNameDelegationTransaction,
+// using a regular in-memory tfchain transaction.
+//
+// Past the (tx) Version validation it piggy-backs onto the
+// `BotNameDelegationTransactionFromTransactionData` constructor.
+func BotNameDelegationTransactionFromTransaction(tx types.Transaction) (BotNameDelegationTransaction, error) {
+	if tx.Version != TransactionVersionBotNameDelegation {
+		return BotNameDelegationTransaction{}, fmt.Errorf(
+			"a bot name delegation transaction requires tx version %d",
+			TransactionVersionBotNameDelegation)
+	}
+	return BotNameDelegationTransactionFromTransactionData(types.TransactionData{
+		CoinInputs:        tx.CoinInputs,
+		CoinOutputs:       tx.CoinOutputs,
+		BlockStakeInputs:  tx.BlockStakeInputs,
+		BlockStakeOutputs: tx.BlockStakeOutputs,
+		MinerFees:         tx.MinerFees,
+		ArbitraryData:     tx.ArbitraryData,
+		Extension:         tx.Extension,
+	})
+}
+
+// BotNameDelegationTransactionFromTransactionData creates a BotNameDelegationTransaction,
+// using the TransactionData from a regular in-memory tfchain transaction.
+func BotNameDelegationTransactionFromTransactionData(txData types.TransactionData) (BotNameDelegationTransaction, error) {
+	// validate the Transaction Data
+	err := validateBotInMemoryTransactionDataRequirements(txData)
+	if err != nil {
+		return BotNameDelegationTransaction{}, fmt.Errorf("BotNameDelegationTransaction: %v", err)
+	}
+
+	// (tx) extension (data) is expected to be a pointer to a valid BotNameDelegationTransactionExtension,
+	// which contains all the properties unique to a 3bot (name delegation) Tx
+	extensionData, ok := txData.Extension.(*BotNameDelegationTransactionExtension)
+	if !ok {
+		return BotNameDelegationTransaction{}, errors.New("invalid extension data for a BotNameDelegationTransaction")
+	}
+
+	// create the BotNameDelegationTransaction and return it,
+	// all should be good (at least the common requirements, it might still be invalid for version-specific reasons)
+	tx := BotNameDelegationTransaction{
+		Owner:          extensionData.Owner,
+		Name:           extensionData.Name,
+		Delegates:      extensionData.Delegates,
+		TransactionFee: txData.MinerFees[0],
+		CoinInputs:     txData.CoinInputs,
+	}
+	if len(txData.CoinOutputs) == 1 {
+		// take refund coin output
+		tx.RefundCoinOutput = &txData.CoinOutputs[0]
+	}
+	return tx, nil
+}
+
+// TransactionData returns this BotNameDelegationTransaction
+// as regular tfchain transaction data.
+func (bndtx *BotNameDelegationTransaction) TransactionData() types.TransactionData {
+	txData := types.TransactionData{
+		CoinInputs: bndtx.CoinInputs,
+		MinerFees:  []types.Currency{bndtx.TransactionFee},
+		Extension: &BotNameDelegationTransactionExtension{
+			Owner:     bndtx.Owner,
+			Name:      bndtx.Name,
+			Delegates: bndtx.Delegates,
+		},
+	}
+	if bndtx.RefundCoinOutput != nil {
+		txData.CoinOutputs = append(txData.CoinOutputs, *bndtx.RefundCoinOutput)
+	}
+	return txData
+}
+
+// Transaction returns this BotNameDelegationTransaction
+// as regular tfchain transaction, using TransactionVersionBotNameDelegation as the type.
+func (bndtx *BotNameDelegationTransaction) Transaction() types.Transaction {
+	txData := bndtx.TransactionData()
+	return types.Transaction{
+		Version:     TransactionVersionBotNameDelegation,
+		CoinInputs:  txData.CoinInputs,
+		CoinOutputs: txData.CoinOutputs,
+		MinerFees:   txData.MinerFees,
+		Extension:   txData.Extension,
+	}
+}
+
+// UpdateDelegates applies the delegates update of this Tx to the given (current) delegates,
+// returning the updated delegates. An error is returned if a delegate to be added already exists,
+// a delegate to be removed doesn't exist or if too many delegates would be the result of the update.
+func (bndtx *BotNameDelegationTransaction) UpdateDelegates(delegates []BotID) ([]BotID, error) {
+	updated := make([]BotID, 0, len(delegates)+len(bndtx.Delegates.Add))
+	for _, id := range delegates {
+		if !isBotIDInSlice(id, bndtx.Delegates.Remove) {
+			updated = append(updated, id)
+		}
+	}
+	if len(delegates)-len(updated) != len(bndtx.Delegates.Remove) {
+		return nil, ErrBotNameDelegateDoesNotExist
+	}
+	for _, id := range bndtx.Delegates.Add {
+		if isBotIDInSlice(id, updated) {
+			return nil, ErrBotNameDelegateExists
+		}
+		updated = append(updated, id)
+	}
+	if len(updated) > MaxDelegatesPerBotName {
+		return nil, ErrTooManyBotNameDelegates
+	}
+	return updated, nil
+}
+
+// MarshalSia implements SiaMarshaler.MarshalSia,
+// alias of MarshalRivine for backwards-compatibility reasons.
+func (bndtx BotNameDelegationTransaction) MarshalSia(w io.Writer) error {
+	return bndtx.MarshalRivine(w)
+}
+
+// UnmarshalSia implements SiaUnmarshaler.UnmarshalSia,
+// alias of UnmarshalRivine for backwards-compatibility reasons.
+func (bndtx *BotNameDelegationTransaction) UnmarshalSia(r io.Reader) error {
+	return bndtx.UnmarshalRivine(r)
+}
+
+// MarshalRivine implements RivineMarshaler.MarshalRivine
+func (bndtx BotNameDelegationTransaction) MarshalRivine(w io.Writer) error {
+	return rivbin.NewEncoder(w).EncodeAll(
+		bndtx.Owner.Identifier,
+		bndtx.Owner.Fulfillment,
+		bndtx.Name,
+		bndtx.Delegates.Add,
+		bndtx.Delegates.Remove,
+		bndtx.TransactionFee,
+		bndtx.CoinInputs,
+		bndtx.RefundCoinOutput,
+	)
+}
+
+// UnmarshalRivine implements RivineUnmarshaler.UnmarshalRivine
+func (bndtx *BotNameDelegationTransaction) UnmarshalRivine(r io.Reader) error {
+	return rivbin.NewDecoder(r).DecodeAll(
+		&bndtx.Owner.Identifier,
+		&bndtx.Owner.Fulfillment,
+		&bndtx.Name,
+		&bndtx.Delegates.Add,
+		&bndtx.Delegates.Remove,
+		&bndtx.TransactionFee,
+		&bndtx.CoinInputs,
+		&bndtx.RefundCoinOutput,
+	)
+}
+
+type (
+	// BotNameDelegationTransactionController defines a tfchain-specific transaction controller,
+	// for a transaction type reserved at type 0x97. It allows the owner of a name to delegate
+	// the right to own sub names of that name to other 3bots.
+	BotNameDelegationTransactionController struct {
+		Registry BotRecordReadRegistry
+	}
+)
+
+var (
+	// ensure at compile time that BotNameDelegationTransactionController
+	// implements the desired interfaces
+	_ types.TransactionController      = BotNameDelegationTransactionController{}
+	_ types.TransactionExtensionSigner = BotNameDelegationTransactionController{}
+	_ types.TransactionValidator       = BotNameDelegationTransactionController{}
+	_ types.BlockStakeOutputValidator  = BotNameDelegationTransactionController{}
+	_ types.TransactionSignatureHasher = BotNameDelegationTransactionController{}
+	_ types.TransactionIDEncoder       = BotNameDelegationTransactionController{}
+)
+
+// EncodeTransactionData implements TransactionController.EncodeTransactionData
+func (bndtc BotNameDelegationTransactionController) EncodeTransactionData(w io.Writer, txData types.TransactionData) error {
+	bndtx, err := BotNameDelegationTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a BotNameDelegationTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).Encode(bndtx)
+}
+
+// DecodeTransactionData implements TransactionController.DecodeTransactionData
+func (bndtc BotNameDelegationTransactionController) DecodeTransactionData(r io.Reader) (types.TransactionData, error) {
+	var bndtx BotNameDelegationTransaction
+	err := rivbin.NewDecoder(r).Decode(&bndtx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to binary-decode tx as a BotNameDelegationTx: %v", err)
+	}
+	// return bot name delegation tx as regular tfchain tx data
+	return bndtx.TransactionData(), nil
+}
+
+// JSONEncodeTransactionData implements TransactionController.JSONEncodeTransactionData
+func (bndtc BotNameDelegationTransactionController) JSONEncodeTransactionData(txData types.TransactionData) ([]byte, error) {
+	bndtx, err := BotNameDelegationTransactionFromTransactionData(txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert txData to a BotNameDelegationTx: %v", err)
+	}
+	return json.Marshal(bndtx)
+}
+
+// JSONDecodeTransactionData implements TransactionController.JSONDecodeTransactionData
+func (bndtc BotNameDelegationTransactionController) JSONDecodeTransactionData(data []byte) (types.TransactionData, error) {
+	var bndtx BotNameDelegationTransaction
+	err := json.Unmarshal(data, &bndtx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to json-decode tx as a BotNameDelegationTx: %v", err)
+	}
+	// return bot name delegation tx as regular tfchain tx data
+	return bndtx.TransactionData(), nil
+}
+
+// SignExtension implements TransactionExtensionSigner.SignExtension
+func (bndtc BotNameDelegationTransactionController) SignExtension(extension interface{}, sign func(*types.UnlockFulfillmentProxy, types.UnlockConditionProxy, ...interface{}) error) (interface{}, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid BotNameDelegationTransactionExtension
+	bndtxExtension, ok := extension.(*BotNameDelegationTransactionExtension)
+	if !ok {
+		return nil, errors.New("invalid extension data for a BotNameDelegationTx")
+	}
+
+	// sign as the owner
+	condition, fulfillment, err := getConditionAndFulfillmentForBotOwner(bndtc.Registry, bndtxExtension.Owner.Identifier, bndtxExtension.Owner.Fulfillment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare the signing (as the owner) of the BotNameDelegationTx: %v", err)
+	}
+	err = sign(&fulfillment, condition, BotSignatureSpecifierOwner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign (as the owner) the BotNameDelegationTx: %v", err)
+	}
+	bndtxExtension.Owner.Fulfillment = fulfillment
+
+	// and return the signed extension
+	return bndtxExtension, nil
+}
+
+// ValidateTransaction implements TransactionValidator.ValidateTransaction
+func (bndtc BotNameDelegationTransactionController) ValidateTransaction(t types.Transaction, ctx types.ValidationContext, constants types.TransactionValidationConstants) error {
+	// given the strict typing of 3bot transactions,
+	// it is guaranteed by its properties that it will always fit within a Block,
+	// and thus the TransactionFitsInABlock is not needed.
+
+	// get BotNameDelegationTx
+	bndtx, err := BotNameDelegationTransactionFromTransaction(t)
+	if err != nil {
+		return fmt.Errorf("failed to use tx as a bot name delegation tx: %v", err)
+	}
+
+	// validate the miner fee
+	if bndtx.TransactionFee.Cmp(constants.MinimumMinerFee) == -1 {
+		return types.ErrTooSmallMinerFee
+	}
+
+	// at least one delegate has to be added or removed, a nop-update is not allowed
+	if len(bndtx.Delegates.Add) == 0 && len(bndtx.Delegates.Remove) == 0 {
+		return errors.New("bot name delegation requires at least one delegate to be added or removed")
+	}
+	err = validateUniquenessOfBotIDs(append(append([]BotID{}, bndtx.Delegates.Add...), bndtx.Delegates.Remove...))
+	if err != nil {
+		return fmt.Errorf("invalid bot name delegation Tx: validateUniquenessOfBotIDs: %v", err)
+	}
+
+	// look up the record of the owner, using the given ID, to ensure it is registered,
+	// active and owns the name, as well as for validation checks that follow
+	record, err := bndtc.Registry.GetRecordForID(bndtx.Owner.Identifier)
+	if err != nil {
+		return fmt.Errorf("invalid owner (%d) of bot name delegation: %v", bndtx.Owner.Identifier, err)
+	}
+	if record.IsExpired(ctx.BlockTime) || !record.Names.Contains(bndtx.Name) {
+		return ErrBotNameNotOwned
+	}
+
+	// validate the fulfillment of the owner
+	err = validateBotRecordFulfillment(t, record, bndtx.Owner.Fulfillment, ctx, BotSignatureSpecifierOwner)
+	if err != nil {
+		return fmt.Errorf("failed to fulfill bot name delegation condition of the owner: %v", err)
+	}
+
+	// ensure all delegates to be added are registered bots, other than the owner itself
+	for _, id := range bndtx.Delegates.Add {
+		if id == bndtx.Owner.Identifier {
+			return errors.New("a bot name cannot be delegated to the bot that owns it")
+		}
+		_, err = bndtc.Registry.GetRecordForID(id)
+		if err != nil {
+			return fmt.Errorf("invalid delegate (%d) of bot name delegation: %v", id, err)
+		}
+	}
+
+	// try to update the current delegates of the name
+	delegates, err := bndtc.Registry.GetDelegatesForName(bndtx.Name)
+	if err != nil {
+		return fmt.Errorf("unexpected error while looking up delegates of bot name %v: %v", bndtx.Name, err)
+	}
+	_, err = bndtx.UpdateDelegates(delegates)
+	if err != nil {
+		return fmt.Errorf("delegates of bot name %v cannot be updated: %v", bndtx.Name, err)
+	}
+
+	// name delegation Tx is valid
+	return nil
+}
+
+// Rivine handles ValidateCoinOutputs,
+// which is possible as all our coin inputs are standard,
+// and the (single) miner fee is standard as well.
+
+// ValidateBlockStakeOutputs implements BlockStakeOutputValidator.ValidateBlockStakeOutputs
+func (bndtc BotNameDelegationTransactionController) ValidateBlockStakeOutputs(t types.Transaction, ctx types.FundValidationContext, blockStakeInputs map[types.BlockStakeOutputID]types.BlockStakeOutput) (err error) {
+	return nil // always valid, no block stake inputs/outputs exist within a bot name delegation transaction
+}
+
+// SignatureHash implements TransactionSignatureHasher.SignatureHash
+func (bndtc BotNameDelegationTransactionController) SignatureHash(t types.Transaction, extraObjects ...interface{}) (crypto.Hash, error) {
+	bndtx, err := BotNameDelegationTransactionFromTransaction(t)
+	if err != nil {
+		return crypto.Hash{}, fmt.Errorf("failed to use tx as a BotNameDelegationTx: %v", err)
+	}
+
+	h := crypto.NewHash()
+	enc := rivbin.NewEncoder(h)
+
+	enc.EncodeAll(
+		t.Version,
+		SpecifierBotNameDelegationTransaction,
+		bndtx.Owner.Identifier,
+	)
+
+	if len(extraObjects) > 0 {
+		enc.EncodeAll(extraObjects...)
+	}
+
+	enc.EncodeAll(
+		bndtx.Name,
+		bndtx.Delegates.Add,
+		bndtx.Delegates.Remove,
+	)
+
+	enc.Encode(len(bndtx.CoinInputs))
+	for _, ci := range bndtx.CoinInputs {
+		enc.Encode(ci.ParentID)
+	}
+
+	enc.EncodeAll(
+		bndtx.TransactionFee,
+		bndtx.RefundCoinOutput,
+	)
+
+	var hash crypto.Hash
+	h.Sum(hash[:0])
+	return hash, nil
+}
+
+// EncodeTransactionIDInput implements TransactionIDEncoder.EncodeTransactionIDInput
+func (bndtc BotNameDelegationTransactionController) EncodeTransactionIDInput(w io.Writer, txData types.TransactionData) error {
+	bndtx, err := BotNameDelegationTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a BotNameDelegationTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).EncodeAll(SpecifierBotNameDelegationTransaction, bndtx)
+}
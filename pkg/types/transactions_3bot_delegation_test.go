@@ -0,0 +1,369 @@
+package types
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/threefoldfoundation/tfchain/pkg/config"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// an example of a bot name delegation Tx, as documented in /doc/transactions.md
+const jsonEncodedBotNameDelegationTx = `{"version":151,"data":{"owner":{"id":1,"fulfillment":{"type":1,"data":{"publickey":"ed25519:cecc1507dc1ddd7295951c290888f095adb9044d1b73d696e6df065d683bd4fc","signature":"ecbbe8574511851ee7ef53516291b52c3f0c29a1f3ef56cf8626eb73636e17efbbbf9932316c17cada2f25fd215fa3a2a6281c44cdad9170d8ed33b4fce32f0c"}}},"name":"chatbot","delegates":{"add":[2,3],"remove":[4]},"txfee":"1000000000","coininputs":[{"parentid":"c6b161d192d8095efd4d9946f7d154bf335f51fdfdeca4bb0cb990b25ffd7e95","fulfillment":{"type":1,"data":{"publickey":"ed25519:cecc1507dc1ddd7295951c290888f095adb9044d1b73d696e6df065d683bd4fc","signature":"2321a921b7d2e9318014f0c7b54a1644005aba2947dd6e69189dc228e73f440604413fbe2fec38c8ba35b0fd16a97f4c906f6bfea399ab91973beb94ffea8e0f"}}}],"refundcoinoutput":{"value":"99999999000000000","condition":{"type":1,"data":{"unlockhash":"01822fd5fefd2748972ea828a5c56044dec9a2b2275229ce5b212f926cd52fba015846451e4e46"}}}}}`
+
+func TestBotNameDelegationTransactionBinaryEncodingAndID(t *testing.T) {
+	types.RegisterTransactionVersion(TransactionVersionBotNameDelegation, BotNameDelegationTransactionController{})
+	defer types.RegisterTransactionVersion(TransactionVersionBotNameDelegation, nil)
+
+	const hexEncodedTx = `970100000001c401cecc1507dc1ddd7295951c290888f095adb9044d1b73d696e6df065d683bd4fc80ecbbe8574511851ee7ef53516291b52c3f0c29a1f3ef56cf8626eb73636e17efbbbf9932316c17cada2f25fd215fa3a2a6281c44cdad9170d8ed33b4fce32f0c0e63686174626f740402000000030000000204000000083b9aca0002c6b161d192d8095efd4d9946f7d154bf335f51fdfdeca4bb0cb990b25ffd7e9501c401cecc1507dc1ddd7295951c290888f095adb9044d1b73d696e6df065d683bd4fc802321a921b7d2e9318014f0c7b54a1644005aba2947dd6e69189dc228e73f440604413fbe2fec38c8ba35b0fd16a97f4c906f6bfea399ab91973beb94ffea8e0f01100163457821ef3600014201822fd5fefd2748972ea828a5c56044dec9a2b2275229ce5b212f926cd52fba01`
+	var tx types.Transaction
+	err := json.Unmarshal([]byte(jsonEncodedBotNameDelegationTx), &tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := tx.ID()
+	b := siabin.Marshal(tx)
+	if output := hex.EncodeToString(b); output != hexEncodedTx {
+		t.Fatal(hexEncodedTx, "!=", output)
+	}
+
+	// go to bot name delegation Tx and back
+	bndtx, err := BotNameDelegationTransactionFromTransaction(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oTx := bndtx.Transaction()
+	oID := oTx.ID()
+	oB := siabin.Marshal(oTx)
+	if id != oID {
+		t.Fatal(id, "!=", oID)
+	}
+	if !bytes.Equal(b, oB) {
+		t.Fatal(hex.EncodeToString(b), "!=", hex.EncodeToString(oB))
+	}
+
+	// binary decode it again, resulting in the same JSON-encoded transaction
+	var decodedTx types.Transaction
+	err = siabin.Unmarshal(oB, &decodedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err = json.Marshal(decodedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output := string(b); output != jsonEncodedBotNameDelegationTx {
+		t.Fatal(jsonEncodedBotNameDelegationTx, "!=", output)
+	}
+}
+
+func TestBotNameDelegationTransactionSignAndValidate(t *testing.T) {
+	registry := &inMemoryBotRegistry{
+		idMapping: map[BotID]BotRecord{
+			1: botRecordFromJSON(t, `{
+	"id": 1,
+	"names": ["example"],
+	"publickey": "`+cryptoKeyPair.PublicKey.String()+`",
+	"expiration": 1538484360
+}`),
+			2: botRecordFromJSON(t, `{
+	"id": 2,
+	"addresses": ["93.184.216.34"],
+	"publickey": "ed25519:00bde9571b30e1742c41fcca8c730183402d967df5b17b5f4ced22c677806614",
+	"expiration": 1538484360
+}`),
+		},
+		delegateMapping: map[string][]BotID{},
+	}
+	types.RegisterTransactionVersion(TransactionVersionBotNameDelegation, BotNameDelegationTransactionController{
+		Registry: registry,
+	})
+	defer types.RegisterTransactionVersion(TransactionVersionBotNameDelegation, nil)
+
+	validationCtx := types.ValidationContext{
+		Confirmed:   true,
+		BlockHeight: 100,
+		BlockTime:   1538484000,
+	}
+	chainConstants := config.GetDevnetGenesis()
+	validationConstants := types.TransactionValidationConstants{
+		BlockSizeLimit:         chainConstants.BlockSizeLimit,
+		ArbitraryDataSizeLimit: chainConstants.ArbitraryDataSizeLimit,
+		MinimumMinerFee:        chainConstants.MinimumTransactionFee,
+	}
+	const unsignedJSONEncodedTx = `{
+	"version": 151,
+	"data": {
+		"owner": {
+			"id": 1,
+			"fulfillment": {
+				"type": 1,
+				"data": {
+					"publickey": "ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780",
+					"signature": ""
+				}
+			}
+		},
+		"name": "example",
+		"delegates": {
+			"add": [2]
+		},
+		"txfee": "1000000000",
+		"coininputs": [{
+			"parentid": "a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee563",
+			"fulfillment": {
+				"type": 1,
+				"data": {
+					"publickey": "ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780",
+					"signature": ""
+				}
+			}
+		}],
+		"refundcoinoutput": {
+			"value": "99999999000000000",
+			"condition": {
+				"type": 1,
+				"data": {
+					"unlockhash": "01822fd5fefd2748972ea828a5c56044dec9a2b2275229ce5b212f926cd52fba015846451e4e46"
+				}
+			}
+		}
+	}
+}`
+	decodeTx := func() BotNameDelegationTransaction {
+		t.Helper()
+		var tx types.Transaction
+		err := tx.UnmarshalJSON([]byte(unsignedJSONEncodedTx))
+		if err != nil {
+			t.Fatal(err)
+		}
+		bndtx, err := BotNameDelegationTransactionFromTransaction(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bndtx
+	}
+
+	// signs and validates the given delegation Tx
+	signAndValidate := func(bndtx BotNameDelegationTransaction) error {
+		t.Helper()
+		bndtx.Owner.Fulfillment = types.UnlockFulfillmentProxy{}
+		tx := bndtx.Transaction()
+		err := tx.SignExtension(func(fulfillment *types.UnlockFulfillmentProxy, condition types.UnlockConditionProxy, extraObjects ...interface{}) error {
+			return fulfillment.Sign(types.FulfillmentSignContext{
+				ExtraObjects: extraObjects,
+				Transaction:  tx,
+				Key:          cryptoKeyPair.PrivateKey,
+			})
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx.ValidateTransaction(validationCtx, validationConstants)
+	}
+
+	// delegating an owned name to another active bot is valid
+	bndtx := decodeTx()
+	err := signAndValidate(bndtx)
+	if err != nil {
+		t.Fatal("failed to validate bot name delegation:", err)
+	}
+
+	// a name that is not owned by the bot cannot be delegated
+	invalidTx := decodeTx()
+	err = invalidTx.Name.LoadString("other.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = signAndValidate(invalidTx)
+	if err != ErrBotNameNotOwned {
+		t.Fatal("unexpected error for a delegation of a name not owned:", err)
+	}
+
+	// a name cannot be delegated to its owner, or to an unknown bot
+	for _, id := range []BotID{1, 3} {
+		invalidTx = decodeTx()
+		invalidTx.Delegates.Add = []BotID{id}
+		err = signAndValidate(invalidTx)
+		if err == nil {
+			t.Fatal("succeeded to delegate a bot name to bot", id)
+		}
+	}
+
+	// a delegate can only be added once, and only removed if it exists
+	registry.delegateMapping["example"] = []BotID{2}
+	err = signAndValidate(bndtx)
+	if err == nil {
+		t.Fatal("succeeded to add an existing delegate")
+	}
+	bndtx.Delegates = BotNameDelegatesUpdate{Remove: []BotID{2}}
+	err = signAndValidate(bndtx)
+	if err != nil {
+		t.Fatal("failed to validate the removal of a bot name delegate:", err)
+	}
+	delete(registry.delegateMapping, "example")
+	err = signAndValidate(bndtx)
+	if err == nil {
+		t.Fatal("succeeded to remove a delegate that doesn't exist")
+	}
+
+	// a nop-update is invalid
+	bndtx.Delegates = BotNameDelegatesUpdate{}
+	err = signAndValidate(bndtx)
+	if err == nil {
+		t.Fatal("succeeded to validate a bot name delegation without delegates")
+	}
+}
+
+func TestBotNameDelegationTransactionUpdateDelegates(t *testing.T) {
+	testCases := []struct {
+		Delegates []BotID
+		Update    BotNameDelegatesUpdate
+		Expected  []BotID
+		Error     error
+	}{
+		{nil, BotNameDelegatesUpdate{Add: []BotID{2, 3}}, []BotID{2, 3}, nil},
+		{[]BotID{2, 3}, BotNameDelegatesUpdate{Add: []BotID{4}, Remove: []BotID{2}}, []BotID{3, 4}, nil},
+		{[]BotID{2, 3}, BotNameDelegatesUpdate{Remove: []BotID{2, 3}}, []BotID{}, nil},
+		{[]BotID{2}, BotNameDelegatesUpdate{Add: []BotID{2}}, nil, ErrBotNameDelegateExists},
+		{[]BotID{2}, BotNameDelegatesUpdate{Remove: []BotID{3}}, nil, ErrBotNameDelegateDoesNotExist},
+		{[]BotID{2, 3, 4, 5, 6, 7, 8, 9, 10}, BotNameDelegatesUpdate{Add: []BotID{11, 12}}, nil, ErrTooManyBotNameDelegates},
+	}
+	for idx, testCase := range testCases {
+		bndtx := BotNameDelegationTransaction{Delegates: testCase.Update}
+		delegates, err := bndtx.UpdateDelegates(testCase.Delegates)
+		if err != testCase.Error {
+			t.Error(idx, "unexpected error:", err, "!=", testCase.Error)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(delegates, testCase.Expected) {
+			t.Error(idx, "unexpected delegates:", delegates, "!=", testCase.Expected)
+		}
+	}
+}
+
+func TestValidateBotSubNames(t *testing.T) {
+	registry := &inMemoryBotRegistry{
+		idMapping: map[BotID]BotRecord{
+			1: botRecordFromJSON(t, `{
+	"id": 1,
+	"names": ["example"],
+	"publickey": "`+cryptoKeyPair.PublicKey.String()+`",
+	"expiration": 1538484360
+}`),
+		},
+		delegateMapping: map[string][]BotID{
+			"example": {2},
+		},
+	}
+	loadNames := func(strs ...string) (names BotNameSortedSet) {
+		t.Helper()
+		for _, str := range strs {
+			var name BotName
+			err := name.LoadString(str)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = names.AddName(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		return
+	}
+	testCases := []struct {
+		ID       BotID
+		Names    BotNameSortedSet
+		SubNames []string
+		Valid    bool
+	}{
+		// top-level names never require a parent
+		{3, loadNames(), []string{"mybot", "yourbot"}, true},
+		// the owner of a name can register its sub names
+		{1, loadNames("example"), []string{"myapp.example"}, true},
+		// parent and sub name can be registered by the same bot at once
+		{3, loadNames("mybot"), []string{"myapp.mybot"}, true},
+		// delegates can register sub names of a name they do not own
+		{2, loadNames(), []string{"myapp.example"}, true},
+		// other bots cannot
+		{3, loadNames(), []string{"myapp.example"}, false},
+		// a sub name requires its parent to be registered
+		{2, loadNames(), []string{"myapp.unknown"}, false},
+	}
+	for idx, testCase := range testCases {
+		subNames := make([]BotName, len(testCase.SubNames))
+		for nidx, str := range testCase.SubNames {
+			err := subNames[nidx].LoadString(str)
+			if err != nil {
+				t.Fatal(idx, err)
+			}
+		}
+		err := validateBotSubNames(registry, testCase.ID, testCase.Names, subNames...)
+		if testCase.Valid && err != nil {
+			t.Error(idx, "unexpected error:", err)
+		} else if !testCase.Valid && err == nil {
+			t.Error(idx, "expected an error, but none was returned")
+		}
+	}
+}
+
+func TestBotSubNameActivationHeight(t *testing.T) {
+	registry := &inMemoryBotRegistry{
+		idMapping: map[BotID]BotRecord{
+			1: botRecordFromJSON(t, `{
+	"id": 1,
+	"names": ["example"],
+	"publickey": "`+cryptoKeyPair.PublicKey.String()+`",
+	"expiration": 1538484360
+}`),
+			3: botRecordFromJSON(t, `{
+	"id": 3,
+	"names": ["mybot"],
+	"publickey": "`+cryptoKeyPair.PublicKey.String()+`",
+	"expiration": 1538484360
+}`),
+		},
+	}
+	params := GetDevnetGenesisConsensusParameters()
+	params.BotSubNameActivationHeight = 100
+	var name BotName
+	err := name.LoadString("myapp.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// bot 3 claims a sub name of a name it does not own, and which isn't delegated to it either
+	validateUpdate := func(height types.BlockHeight, confirmed bool) error {
+		record, err := registry.GetRecordForID(3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		brutx := BotRecordUpdateTransaction{
+			Identifier: 3,
+			Names:      BotRecordNameUpdate{Add: []BotName{name}},
+		}
+		return validateBotRecordUpdate(registry, staticConsensusParameterGetter(params), &brutx, record, types.ValidationContext{
+			Confirmed:   confirmed,
+			BlockHeight: height,
+			BlockTime:   1538484000,
+		})
+	}
+
+	// prior to the activation height, any available name can be claimed
+	err = validateUpdate(99, true)
+	if err != nil {
+		t.Fatal("failed to validate sub name claim prior to the activation height:", err)
+	}
+	// unconfirmed transactions are validated for the next block
+	err = validateUpdate(99, false)
+	if err == nil {
+		t.Error("succeeded to validate unconfirmed sub name claim for the activation height")
+	}
+	err = validateUpdate(100, true)
+	if err == nil {
+		t.Error("succeeded to validate sub name claim at the activation height")
+	}
+}
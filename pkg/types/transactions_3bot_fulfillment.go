@@ -0,0 +1,795 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+type (
+	// BotRecordUpdateWithFulfillmentTransaction defines the Transaction (with version 0x94)
+	// used to update a 3bot Record by the owner. It is identical to the BotRecordUpdateTransaction,
+	// except that the ownership is proven using a fulfillment of the owner condition of the 3bot,
+	// rather than a single signature, such that 3bots owned by a (multisig) condition can be updated as well.
+	BotRecordUpdateWithFulfillmentTransaction struct {
+		// Identifier of the 3bot, used to find the 3bot record to be updated,
+		// and verify that the Tx is authorized to do so.
+		Identifier BotID `json:"id"`
+
+		// Addresses can be used to add and/or remove network addresses
+		// to/from the existing 3bot record. Note that after each Tx,
+		// no more than 10 addresses can be linked to a single 3bot record.
+		Addresses BotRecordAddressUpdate `json:"addresses,omitempty"`
+
+		// Names can be used to add and/or remove names
+		// to/from the existing 3bot record. Note that after each Tx,
+		// no more than 5 names can be linked to a single 3bot record.
+		Names BotRecordNameUpdate `json:"names,omitempty"`
+
+		// NrOfMonths defines the optional amount of months that
+		// is desired to be paid upfront in this update.
+		// The NrOfMonths has to be within this inclusive range [0,24].
+		NrOfMonths uint8 `json:"nrofmonths"`
+
+		// TransactionFee defines the regular Tx fee.
+		TransactionFee types.Currency `json:"txfee"`
+
+		// CoinInputs are only used for the required fees,
+		// which contains the regular Tx fee as well as the additional fees,
+		// to be paid for a 3bot record update. At least one CoinInput is required.
+		CoinInputs []types.CoinInput `json:"coininputs"`
+		// RefundCoinOutput is an optional coin output that can be used
+		// to refund coins paid as inputs for the required fees.
+		RefundCoinOutput *types.CoinOutput `json:"refundcoinoutput,omitempty"`
+
+		// OwnerFulfillment is used to proof the ownership of the 3bot record to be updated,
+		// and has to fulfill the owner condition of the 3bot linked to the given (3bot) identifier.
+		OwnerFulfillment types.UnlockFulfillmentProxy `json:"ownerfulfillment"`
+	}
+	// BotRecordUpdateWithFulfillmentTransactionExtension defines the
+	// BotRecordUpdateWithFulfillmentTransaction Extension Data
+	BotRecordUpdateWithFulfillmentTransactionExtension struct {
+		Identifier       BotID
+		OwnerFulfillment types.UnlockFulfillmentProxy
+		AddressUpdate    BotRecordAddressUpdate
+		NameUpdate       BotRecordNameUpdate
+		NrOfMonths       uint8
+	}
+)
+
+// RequiredBotFee computes the required Bot Fee, that is to be applied as a required
+// additional fee on top of the regular required (minimum) Tx fee.
+func (brutxe *BotRecordUpdateWithFulfillmentTransactionExtension) RequiredBotFee(params ConsensusParameters) types.Currency {
+	return (&BotRecordUpdateTransactionExtension{
+		Identifier:    brutxe.Identifier,
+		AddressUpdate: brutxe.AddressUpdate,
+		NameUpdate:    brutxe.NameUpdate,
+		NrOfMonths:    brutxe.NrOfMonths,
+	}).RequiredBotFee(params)
+}
+
+// BotRecordUpdateWithFulfillmentTransactionFromTransaction creates a BotRecordUpdateWithFulfillmentTransaction,
+// using a regular in-memory tfchain transaction.
+//
+// Past the (tx) Version validation it piggy-backs onto the
+// `BotRecordUpdateWithFulfillmentTransactionFromTransactionData` constructor.
+func BotRecordUpdateWithFulfillmentTransactionFromTransaction(tx types.Transaction) (BotRecordUpdateWithFulfillmentTransaction, error) {
+	if tx.Version != TransactionVersionBotRecordUpdateWithFulfillment {
+		return BotRecordUpdateWithFulfillmentTransaction{}, fmt.Errorf(
+			"a bot record update (with fulfillment) transaction requires tx version %d",
+			TransactionVersionBotRecordUpdateWithFulfillment)
+	}
+	return BotRecordUpdateWithFulfillmentTransactionFromTransactionData(types.TransactionData{
+		CoinInputs:        tx.CoinInputs,
+		CoinOutputs:       tx.CoinOutputs,
+		BlockStakeInputs:  tx.BlockStakeInputs,
+		BlockStakeOutputs: tx.BlockStakeOutputs,
+		MinerFees:         tx.MinerFees,
+		ArbitraryData:     tx.ArbitraryData,
+		Extension:         tx.Extension,
+	})
+}
+
+// BotRecordUpdateWithFulfillmentTransactionFromTransactionData creates a BotRecordUpdateWithFulfillmentTransaction,
+// using the TransactionData from a regular in-memory tfchain transaction.
+func BotRecordUpdateWithFulfillmentTransactionFromTransactionData(txData types.TransactionData) (BotRecordUpdateWithFulfillmentTransaction, error) {
+	// validate the Transaction Data
+	err := validateBotInMemoryTransactionDataRequirements(txData)
+	if err != nil {
+		return BotRecordUpdateWithFulfillmentTransaction{}, fmt.Errorf("BotRecordUpdateWithFulfillmentTransaction: %v", err)
+	}
+
+	// (tx) extension (data) is expected to be a pointer to a valid BotRecordUpdateWithFulfillmentTransactionExtension,
+	// which contains all the properties unique to a 3bot (record update) Tx
+	extensionData, ok := txData.Extension.(*BotRecordUpdateWithFulfillmentTransactionExtension)
+	if !ok {
+		return BotRecordUpdateWithFulfillmentTransaction{}, errors.New("invalid extension data for a BotRecordUpdateWithFulfillmentTransaction")
+	}
+
+	// create the BotRecordUpdateWithFulfillmentTransaction and return it,
+	// all should be good (at least the common requirements, it might still be invalid for version-specific reasons)
+	tx := BotRecordUpdateWithFulfillmentTransaction{
+		Identifier:       extensionData.Identifier,
+		Addresses:        extensionData.AddressUpdate,
+		Names:            extensionData.NameUpdate,
+		NrOfMonths:       extensionData.NrOfMonths,
+		TransactionFee:   txData.MinerFees[0],
+		CoinInputs:       txData.CoinInputs,
+		OwnerFulfillment: extensionData.OwnerFulfillment,
+	}
+	if len(txData.CoinOutputs) == 1 {
+		// take refund coin output
+		tx.RefundCoinOutput = &txData.CoinOutputs[0]
+	}
+	return tx, nil
+}
+
+// TransactionData returns this BotRecordUpdateWithFulfillmentTransaction
+// as regular tfchain transaction data.
+func (brutx *BotRecordUpdateWithFulfillmentTransaction) TransactionData(oneCoin types.Currency) types.TransactionData {
+	txData := types.TransactionData{
+		CoinInputs: brutx.CoinInputs,
+		MinerFees:  []types.Currency{brutx.TransactionFee},
+		Extension: &BotRecordUpdateWithFulfillmentTransactionExtension{
+			Identifier:       brutx.Identifier,
+			OwnerFulfillment: brutx.OwnerFulfillment,
+			AddressUpdate:    brutx.Addresses,
+			NameUpdate:       brutx.Names,
+			NrOfMonths:       brutx.NrOfMonths,
+		},
+	}
+	if brutx.RefundCoinOutput != nil {
+		txData.CoinOutputs = append(txData.CoinOutputs, *brutx.RefundCoinOutput)
+	}
+	return txData
+}
+
+// Transaction returns this BotRecordUpdateWithFulfillmentTransaction
+// as regular tfchain transaction, using TransactionVersionBotRecordUpdateWithFulfillment as the type.
+func (brutx *BotRecordUpdateWithFulfillmentTransaction) Transaction(oneCoin types.Currency) types.Transaction {
+	txData := brutx.TransactionData(oneCoin)
+	return types.Transaction{
+		Version:     TransactionVersionBotRecordUpdateWithFulfillment,
+		CoinInputs:  txData.CoinInputs,
+		CoinOutputs: txData.CoinOutputs,
+		MinerFees:   txData.MinerFees,
+		Extension:   txData.Extension,
+	}
+}
+
+// RequiredBotFee computes the required Bot Fee, that is to be applied as a required
+// additional fee on top of the regular required (minimum) Tx fee.
+func (brutx *BotRecordUpdateWithFulfillmentTransaction) RequiredBotFee(params ConsensusParameters) types.Currency {
+	update := brutx.AsBotRecordUpdateTransaction()
+	return update.RequiredBotFee(params)
+}
+
+// AsBotRecordUpdateTransaction returns this Tx as a (signature-less) BotRecordUpdateTransaction,
+// such that the record update (and revert) logic can be shared between both versions.
+func (brutx *BotRecordUpdateWithFulfillmentTransaction) AsBotRecordUpdateTransaction() BotRecordUpdateTransaction {
+	return BotRecordUpdateTransaction{
+		Identifier:       brutx.Identifier,
+		Addresses:        brutx.Addresses,
+		Names:            brutx.Names,
+		NrOfMonths:       brutx.NrOfMonths,
+		TransactionFee:   brutx.TransactionFee,
+		CoinInputs:       brutx.CoinInputs,
+		RefundCoinOutput: brutx.RefundCoinOutput,
+	}
+}
+
+// MarshalSia implements SiaMarshaler.MarshalSia,
+// alias of MarshalRivine for backwards-compatibility reasons.
+func (brutx BotRecordUpdateWithFulfillmentTransaction) MarshalSia(w io.Writer) error {
+	return brutx.MarshalRivine(w)
+}
+
+// UnmarshalSia implements SiaUnmarshaler.UnmarshalSia,
+// alias of UnmarshalRivine for backwards-compatibility reasons.
+func (brutx *BotRecordUpdateWithFulfillmentTransaction) UnmarshalSia(r io.Reader) error {
+	return brutx.UnmarshalRivine(r)
+}
+
+// MarshalRivine implements RivineMarshaler.MarshalRivine
+func (brutx BotRecordUpdateWithFulfillmentTransaction) MarshalRivine(w io.Writer) error {
+	return rivbin.NewEncoder(w).EncodeAll(
+		brutx.Identifier,
+		brutx.Addresses.Add,
+		brutx.Addresses.Remove,
+		brutx.Names.Add,
+		brutx.Names.Remove,
+		brutx.NrOfMonths,
+		brutx.TransactionFee,
+		brutx.CoinInputs,
+		brutx.RefundCoinOutput,
+		brutx.OwnerFulfillment,
+	)
+}
+
+// UnmarshalRivine implements RivineUnmarshaler.UnmarshalRivine
+func (brutx *BotRecordUpdateWithFulfillmentTransaction) UnmarshalRivine(r io.Reader) error {
+	return rivbin.NewDecoder(r).DecodeAll(
+		&brutx.Identifier,
+		&brutx.Addresses.Add,
+		&brutx.Addresses.Remove,
+		&brutx.Names.Add,
+		&brutx.Names.Remove,
+		&brutx.NrOfMonths,
+		&brutx.TransactionFee,
+		&brutx.CoinInputs,
+		&brutx.RefundCoinOutput,
+		&brutx.OwnerFulfillment,
+	)
+}
+
+type (
+	// BotNameTransferWithFulfillmentTransaction defines the Transaction (with version 0x95)
+	// used to transfer one or multiple names from the active
+	// 3bot that up to the point of the Tx to another 3bot. It is identical to the BotNameTransferTransaction,
+	// except that the ownership of both bots is proven using fulfillments of their owner conditions,
+	// rather than single signatures, such that 3bots owned by a (multisig) condition can participate as well.
+	BotNameTransferWithFulfillmentTransaction struct {
+		// Sender is in this context the 3bot that owns and transfers the names
+		// defined in this Tx to the 3bot defined in this Tx as the Receiver.
+		// The Sender has to be different from the Receiver.
+		Sender BotIdentifierFulfillmentPair `json:"sender"`
+		// Receiver is in this context the 3bot that receives the names
+		// defined in this Tx from the 3bot defined in this Tx as the Sender.
+		// The Receiver has to be different from the Sender.
+		Receiver BotIdentifierFulfillmentPair `json:"receiver"`
+
+		// Names to be transferred from sender to receiver. Note that after each Tx,
+		// no more than 5 names can be linked to a single 3bot record.
+		Names []BotName `json:"names"`
+
+		// TransactionFee defines the regular Tx fee.
+		TransactionFee types.Currency `json:"txfee"`
+
+		// CoinInputs are only used for the required fees,
+		// which contains the regular Tx fee as well as the additional fees,
+		// to be paid for a 3bot name transfer. At least one CoinInput is required.
+		CoinInputs []types.CoinInput `json:"coininputs"`
+		// RefundCoinOutput is an optional coin output that can be used
+		// to refund coins paid as inputs for the required fees.
+		RefundCoinOutput *types.CoinOutput `json:"refundcoinoutput,omitempty"`
+	}
+	// BotIdentifierFulfillmentPair pairs a bot identifier and a fulfillment assumed
+	// to fulfill the owner condition of the bot linked to that ID.
+	BotIdentifierFulfillmentPair struct {
+		Identifier  BotID                        `json:"id"`
+		Fulfillment types.UnlockFulfillmentProxy `json:"fulfillment"`
+	}
+	// BotNameTransferWithFulfillmentTransactionExtension defines the
+	// BotNameTransferWithFulfillmentTransaction Extension Data
+	BotNameTransferWithFulfillmentTransactionExtension struct {
+		Sender   BotIdentifierFulfillmentPair
+		Receiver BotIdentifierFulfillmentPair
+		Names    []BotName
+	}
+)
+
+// RequiredBotFee computes the required Bot Fee, that is to be applied as a required
+// additional fee on top of the regular required (minimum) Tx fee.
+func (bnttxe *BotNameTransferWithFulfillmentTransactionExtension) RequiredBotFee(params ConsensusParameters) types.Currency {
+	return params.BotNameFee.Mul64(uint64(len(bnttxe.Names)))
+}
+
+// BotNameTransferWithFulfillmentTransactionFromTransaction creates a BotNameTransferWithFulfillmentTransaction,
+// using a regular in-memory tfchain transaction.
+//
+// Past the (tx) Version validation it piggy-backs onto the
+// `BotNameTransferWithFulfillmentTransactionFromTransactionData` constructor.
+func BotNameTransferWithFulfillmentTransactionFromTransaction(tx types.Transaction) (BotNameTransferWithFulfillmentTransaction, error) {
+	if tx.Version != TransactionVersionBotNameTransferWithFulfillment {
+		return BotNameTransferWithFulfillmentTransaction{}, fmt.Errorf(
+			"a bot name transfer (with fulfillment) transaction requires tx version %d",
+			TransactionVersionBotNameTransferWithFulfillment)
+	}
+	return BotNameTransferWithFulfillmentTransactionFromTransactionData(types.TransactionData{
+		CoinInputs:        tx.CoinInputs,
+		CoinOutputs:       tx.CoinOutputs,
+		BlockStakeInputs:  tx.BlockStakeInputs,
+		BlockStakeOutputs: tx.BlockStakeOutputs,
+		MinerFees:         tx.MinerFees,
+		ArbitraryData:     tx.ArbitraryData,
+		Extension:         tx.Extension,
+	})
+}
+
+// BotNameTransferWithFulfillmentTransactionFromTransactionData creates a BotNameTransferWithFulfillmentTransaction,
+// using the TransactionData from a regular in-memory tfchain transaction.
+func BotNameTransferWithFulfillmentTransactionFromTransactionData(txData types.TransactionData) (BotNameTransferWithFulfillmentTransaction, error) {
+	// validate the Transaction Data
+	err := validateBotInMemoryTransactionDataRequirements(txData)
+	if err != nil {
+		return BotNameTransferWithFulfillmentTransaction{}, fmt.Errorf("BotNameTransferWithFulfillmentTransaction: %v", err)
+	}
+
+	// (tx) extension (data) is expected to be a pointer to a valid BotNameTransferWithFulfillmentTransactionExtension,
+	// which contains all the properties unique to a 3bot (name transfer) Tx
+	extensionData, ok := txData.Extension.(*BotNameTransferWithFulfillmentTransactionExtension)
+	if !ok {
+		return BotNameTransferWithFulfillmentTransaction{}, errors.New("invalid extension data for a BotNameTransferWithFulfillmentTransaction")
+	}
+
+	// create the BotNameTransferWithFulfillmentTransaction and return it,
+	// all should be good (at least the common requirements, it might still be invalid for version-specific reasons)
+	tx := BotNameTransferWithFulfillmentTransaction{
+		Sender:         extensionData.Sender,
+		Receiver:       extensionData.Receiver,
+		Names:          extensionData.Names,
+		TransactionFee: txData.MinerFees[0],
+		CoinInputs:     txData.CoinInputs,
+	}
+	if len(txData.CoinOutputs) == 1 {
+		// take refund coin output
+		tx.RefundCoinOutput = &txData.CoinOutputs[0]
+	}
+	return tx, nil
+}
+
+// TransactionData returns this BotNameTransferWithFulfillmentTransaction
+// as regular tfchain transaction data.
+func (bnttx *BotNameTransferWithFulfillmentTransaction) TransactionData(oneCoin types.Currency) types.TransactionData {
+	txData := types.TransactionData{
+		CoinInputs: bnttx.CoinInputs,
+		MinerFees:  []types.Currency{bnttx.TransactionFee},
+		Extension: &BotNameTransferWithFulfillmentTransactionExtension{
+			Sender:   bnttx.Sender,
+			Receiver: bnttx.Receiver,
+			Names:    bnttx.Names,
+		},
+	}
+	if bnttx.RefundCoinOutput != nil {
+		txData.CoinOutputs = append(txData.CoinOutputs, *bnttx.RefundCoinOutput)
+	}
+	return txData
+}
+
+// Transaction returns this BotNameTransferWithFulfillmentTransaction
+// as regular tfchain transaction, using TransactionVersionBotNameTransferWithFulfillment as the type.
+func (bnttx *BotNameTransferWithFulfillmentTransaction) Transaction(oneCoin types.Currency) types.Transaction {
+	txData := bnttx.TransactionData(oneCoin)
+	return types.Transaction{
+		Version:     TransactionVersionBotNameTransferWithFulfillment,
+		CoinInputs:  txData.CoinInputs,
+		CoinOutputs: txData.CoinOutputs,
+		MinerFees:   txData.MinerFees,
+		Extension:   txData.Extension,
+	}
+}
+
+// RequiredBotFee computes the required Bot Fee, that is to be applied as a required
+// additional fee on top of the regular required (minimum) Tx fee.
+func (bnttx *BotNameTransferWithFulfillmentTransaction) RequiredBotFee(params ConsensusParameters) types.Currency {
+	return (&BotNameTransferWithFulfillmentTransactionExtension{
+		Sender:   bnttx.Sender,
+		Receiver: bnttx.Receiver,
+		Names:    bnttx.Names,
+	}).RequiredBotFee(params)
+}
+
+// AsBotNameTransferTransaction returns this Tx as a (signature-less) BotNameTransferTransaction,
+// such that the record update (and revert) logic can be shared between both versions.
+func (bnttx *BotNameTransferWithFulfillmentTransaction) AsBotNameTransferTransaction() BotNameTransferTransaction {
+	return BotNameTransferTransaction{
+		Sender:           BotIdentifierSignaturePair{Identifier: bnttx.Sender.Identifier},
+		Receiver:         BotIdentifierSignaturePair{Identifier: bnttx.Receiver.Identifier},
+		Names:            bnttx.Names,
+		TransactionFee:   bnttx.TransactionFee,
+		CoinInputs:       bnttx.CoinInputs,
+		RefundCoinOutput: bnttx.RefundCoinOutput,
+	}
+}
+
+// MarshalSia implements SiaMarshaler.MarshalSia,
+// alias of MarshalRivine for backwards-compatibility reasons.
+func (bnttx BotNameTransferWithFulfillmentTransaction) MarshalSia(w io.Writer) error {
+	return bnttx.MarshalRivine(w)
+}
+
+// UnmarshalSia implements SiaUnmarshaler.UnmarshalSia,
+// alias of UnmarshalRivine for backwards-compatibility reasons.
+func (bnttx *BotNameTransferWithFulfillmentTransaction) UnmarshalSia(r io.Reader) error {
+	return bnttx.UnmarshalRivine(r)
+}
+
+// MarshalRivine implements RivineMarshaler.MarshalRivine
+func (bnttx BotNameTransferWithFulfillmentTransaction) MarshalRivine(w io.Writer) error {
+	return rivbin.NewEncoder(w).EncodeAll(
+		bnttx.Sender.Identifier,
+		bnttx.Sender.Fulfillment,
+		bnttx.Receiver.Identifier,
+		bnttx.Receiver.Fulfillment,
+		bnttx.Names,
+		bnttx.TransactionFee,
+		bnttx.CoinInputs,
+		bnttx.RefundCoinOutput,
+	)
+}
+
+// UnmarshalRivine implements RivineUnmarshaler.UnmarshalRivine
+func (bnttx *BotNameTransferWithFulfillmentTransaction) UnmarshalRivine(r io.Reader) error {
+	return rivbin.NewDecoder(r).DecodeAll(
+		&bnttx.Sender.Identifier,
+		&bnttx.Sender.Fulfillment,
+		&bnttx.Receiver.Identifier,
+		&bnttx.Receiver.Fulfillment,
+		&bnttx.Names,
+		&bnttx.TransactionFee,
+		&bnttx.CoinInputs,
+		&bnttx.RefundCoinOutput,
+	)
+}
+
+type (
+	// BotUpdateRecordWithFulfillmentTransactionController defines a tfchain-specific transaction controller,
+	// for a transaction type reserved at type 0x94. It allows the update of the record of an existing 3bot,
+	// authorized by a fulfillment of the owner condition of that 3bot.
+	BotUpdateRecordWithFulfillmentTransactionController struct {
+		Registry            BotRecordReadRegistry
+		RegistryPoolAddress types.UnlockHash
+		OneCoin             types.Currency
+		// ConsensusParameterGetter is used to get the consensus parameters at a given block height,
+		// which define the bot fees to be paid to the registry pool address.
+		ConsensusParameterGetter ConsensusParameterGetter
+	}
+)
+
+var (
+	// ensure at compile time that BotUpdateRecordWithFulfillmentTransactionController
+	// implements the desired interfaces
+	_ types.TransactionController                      = BotUpdateRecordWithFulfillmentTransactionController{}
+	_ types.TransactionExtensionSigner                 = BotUpdateRecordWithFulfillmentTransactionController{}
+	_ types.TransactionValidator                       = BotUpdateRecordWithFulfillmentTransactionController{}
+	_ types.BlockStakeOutputValidator                  = BotUpdateRecordWithFulfillmentTransactionController{}
+	_ types.TransactionSignatureHasher                 = BotUpdateRecordWithFulfillmentTransactionController{}
+	_ types.TransactionIDEncoder                       = BotUpdateRecordWithFulfillmentTransactionController{}
+	_ types.TransactionCustomMinerPayoutAtHeightGetter = BotUpdateRecordWithFulfillmentTransactionController{}
+)
+
+// EncodeTransactionData implements TransactionController.EncodeTransactionData
+func (brutc BotUpdateRecordWithFulfillmentTransactionController) EncodeTransactionData(w io.Writer, txData types.TransactionData) error {
+	brutx, err := BotRecordUpdateWithFulfillmentTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a BotUpdateRecordWithFulfillmentTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).Encode(brutx)
+}
+
+// DecodeTransactionData implements TransactionController.DecodeTransactionData
+func (brutc BotUpdateRecordWithFulfillmentTransactionController) DecodeTransactionData(r io.Reader) (types.TransactionData, error) {
+	var brutx BotRecordUpdateWithFulfillmentTransaction
+	err := rivbin.NewDecoder(r).Decode(&brutx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to binary-decode tx as a BotUpdateRecordWithFulfillmentTx: %v", err)
+	}
+	// return bot record update tx as regular tfchain tx data
+	return brutx.TransactionData(brutc.OneCoin), nil
+}
+
+// JSONEncodeTransactionData implements TransactionController.JSONEncodeTransactionData
+func (brutc BotUpdateRecordWithFulfillmentTransactionController) JSONEncodeTransactionData(txData types.TransactionData) ([]byte, error) {
+	brutx, err := BotRecordUpdateWithFulfillmentTransactionFromTransactionData(txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert txData to a BotUpdateRecordWithFulfillmentTx: %v", err)
+	}
+	return json.Marshal(brutx)
+}
+
+// JSONDecodeTransactionData implements TransactionController.JSONDecodeTransactionData
+func (brutc BotUpdateRecordWithFulfillmentTransactionController) JSONDecodeTransactionData(data []byte) (types.TransactionData, error) {
+	var brutx BotRecordUpdateWithFulfillmentTransaction
+	err := json.Unmarshal(data, &brutx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to json-decode tx as a BotUpdateRecordWithFulfillmentTx: %v", err)
+	}
+	// return bot record update tx as regular tfchain tx data
+	return brutx.TransactionData(brutc.OneCoin), nil
+}
+
+// SignExtension implements TransactionExtensionSigner.SignExtension
+func (brutc BotUpdateRecordWithFulfillmentTransactionController) SignExtension(extension interface{}, sign func(*types.UnlockFulfillmentProxy, types.UnlockConditionProxy, ...interface{}) error) (interface{}, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid BotRecordUpdateWithFulfillmentTransactionExtension
+	brutxExtension, ok := extension.(*BotRecordUpdateWithFulfillmentTransactionExtension)
+	if !ok {
+		return nil, errors.New("invalid extension data for a BotUpdateRecordWithFulfillmentTx")
+	}
+
+	// get the owner condition and fulfillment for the bot, so we can sign
+	condition, fulfillment, err := getConditionAndFulfillmentForBotOwner(brutc.Registry, brutxExtension.Identifier, brutxExtension.OwnerFulfillment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare the signing of BotUpdateRecordWithFulfillmentTx: %v", err)
+	}
+
+	// sign the fulfillment
+	err = sign(&fulfillment, condition, BotSignatureSpecifierSender)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign BotUpdateRecordWithFulfillmentTx: %v", err)
+	}
+	brutxExtension.OwnerFulfillment = fulfillment
+
+	// and return the signed extension
+	return brutxExtension, nil
+}
+
+// ValidateTransaction implements TransactionValidator.ValidateTransaction
+func (brutc BotUpdateRecordWithFulfillmentTransactionController) ValidateTransaction(t types.Transaction, ctx types.ValidationContext, constants types.TransactionValidationConstants) error {
+	// given the strict typing of 3bot transactions,
+	// it is guaranteed by its properties that it will always fit within a Block,
+	// and thus the TransactionFitsInABlock is not needed.
+
+	// get BotRecordUpdateWithFulfillmentTx
+	brutx, err := BotRecordUpdateWithFulfillmentTransactionFromTransaction(t)
+	if err != nil {
+		return fmt.Errorf("failed to use tx as a bot record update (with fulfillment) tx: %v", err)
+	}
+
+	// validate the miner fee
+	if brutx.TransactionFee.Cmp(constants.MinimumMinerFee) == -1 {
+		return types.ErrTooSmallMinerFee
+	}
+
+	// look up the record, using the given ID, to ensure it is registered
+	record, err := brutc.Registry.GetRecordForID(brutx.Identifier)
+	if err != nil {
+		return fmt.Errorf("bot cannot be updated: GetRecordForID(%v): %v", brutx.Identifier, err)
+	}
+
+	// validate the fulfillment of the owner condition of the to-be-updated bot
+	err = validateBotRecordFulfillment(t, record, brutx.OwnerFulfillment, ctx, BotSignatureSpecifierSender)
+	if err != nil {
+		return fmt.Errorf("failed to fulfill bot record update condition: %v", err)
+	}
+
+	// validate the update itself
+	update := brutx.AsBotRecordUpdateTransaction()
+	return validateBotRecordUpdate(brutc.Registry, brutc.ConsensusParameterGetter, &update, record, ctx)
+}
+
+// ValidateBlockStakeOutputs implements BlockStakeOutputValidator.ValidateBlockStakeOutputs
+func (brutc BotUpdateRecordWithFulfillmentTransactionController) ValidateBlockStakeOutputs(t types.Transaction, ctx types.FundValidationContext, blockStakeInputs map[types.BlockStakeOutputID]types.BlockStakeOutput) (err error) {
+	return nil // always valid, no block stake inputs/outputs exist within a bot record update transaction
+}
+
+// SignatureHash implements TransactionSignatureHasher.SignatureHash
+func (brutc BotUpdateRecordWithFulfillmentTransactionController) SignatureHash(t types.Transaction, extraObjects ...interface{}) (crypto.Hash, error) {
+	brutx, err := BotRecordUpdateWithFulfillmentTransactionFromTransaction(t)
+	if err != nil {
+		return crypto.Hash{}, fmt.Errorf("failed to use tx as a BotRecordUpdateWithFulfillmentTx: %v", err)
+	}
+	update := brutx.AsBotRecordUpdateTransaction()
+	return botRecordUpdateSignatureHash(t.Version, SpecifierBotRecordUpdateWithFulfillmentTransaction, &update, extraObjects...), nil
+}
+
+// EncodeTransactionIDInput implements TransactionIDEncoder.EncodeTransactionIDInput
+func (brutc BotUpdateRecordWithFulfillmentTransactionController) EncodeTransactionIDInput(w io.Writer, txData types.TransactionData) error {
+	brutx, err := BotRecordUpdateWithFulfillmentTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a BotRecordUpdateWithFulfillmentTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).EncodeAll(SpecifierBotRecordUpdateWithFulfillmentTransaction, brutx)
+}
+
+// GetCustomMinerPayoutsAt implements TransactionCustomMinerPayoutAtHeightGetter.GetCustomMinerPayoutsAt
+func (brutc BotUpdateRecordWithFulfillmentTransactionController) GetCustomMinerPayoutsAt(extension interface{}, height types.BlockHeight) ([]types.MinerPayout, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid BotRecordUpdateWithFulfillmentTransactionExtension
+	brutxExtension, ok := extension.(*BotRecordUpdateWithFulfillmentTransactionExtension)
+	if !ok {
+		return nil, errors.New("invalid extension data for a Bot RecordUpdate (with fulfillment) Transaction")
+	}
+	// the bot fees are defined by the consensus parameters that apply to the block at the given height
+	params, err := brutc.ConsensusParameterGetter.GetConsensusParametersAt(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the consensus parameters at block height %d: %v", height, err)
+	}
+	return []types.MinerPayout{
+		{
+			Value:      brutxExtension.RequiredBotFee(params),
+			UnlockHash: brutc.RegistryPoolAddress,
+		},
+	}, nil
+}
+
+type (
+	// BotNameTransferWithFulfillmentTransactionController defines a tfchain-specific transaction controller,
+	// for a transaction type reserved at type 0x95. It allows the transfer of names and update of the record
+	// of the two existing 3bot that participate in this transfer, authorized by fulfillments
+	// of the owner conditions of both 3bots.
+	BotNameTransferWithFulfillmentTransactionController struct {
+		Registry            BotRecordReadRegistry
+		RegistryPoolAddress types.UnlockHash
+		OneCoin             types.Currency
+		// ConsensusParameterGetter is used to get the consensus parameters at a given block height,
+		// which define the bot fees to be paid to the registry pool address.
+		ConsensusParameterGetter ConsensusParameterGetter
+	}
+)
+
+var (
+	// ensure at compile time that BotNameTransferWithFulfillmentTransactionController
+	// implements the desired interfaces
+	_ types.TransactionController                      = BotNameTransferWithFulfillmentTransactionController{}
+	_ types.TransactionExtensionSigner                 = BotNameTransferWithFulfillmentTransactionController{}
+	_ types.TransactionValidator                       = BotNameTransferWithFulfillmentTransactionController{}
+	_ types.BlockStakeOutputValidator                  = BotNameTransferWithFulfillmentTransactionController{}
+	_ types.TransactionSignatureHasher                 = BotNameTransferWithFulfillmentTransactionController{}
+	_ types.TransactionIDEncoder                       = BotNameTransferWithFulfillmentTransactionController{}
+	_ types.TransactionCustomMinerPayoutAtHeightGetter = BotNameTransferWithFulfillmentTransactionController{}
+)
+
+// EncodeTransactionData implements TransactionController.EncodeTransactionData
+func (bnttc BotNameTransferWithFulfillmentTransactionController) EncodeTransactionData(w io.Writer, txData types.TransactionData) error {
+	bnttx, err := BotNameTransferWithFulfillmentTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a BotNameTransferWithFulfillmentTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).Encode(bnttx)
+}
+
+// DecodeTransactionData implements TransactionController.DecodeTransactionData
+func (bnttc BotNameTransferWithFulfillmentTransactionController) DecodeTransactionData(r io.Reader) (types.TransactionData, error) {
+	var bnttx BotNameTransferWithFulfillmentTransaction
+	err := rivbin.NewDecoder(r).Decode(&bnttx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to binary-decode tx as a BotNameTransferWithFulfillmentTx: %v", err)
+	}
+	// return bot name transfer tx as regular tfchain tx data
+	return bnttx.TransactionData(bnttc.OneCoin), nil
+}
+
+// JSONEncodeTransactionData implements TransactionController.JSONEncodeTransactionData
+func (bnttc BotNameTransferWithFulfillmentTransactionController) JSONEncodeTransactionData(txData types.TransactionData) ([]byte, error) {
+	bnttx, err := BotNameTransferWithFulfillmentTransactionFromTransactionData(txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert txData to a BotNameTransferWithFulfillmentTx: %v", err)
+	}
+	return json.Marshal(bnttx)
+}
+
+// JSONDecodeTransactionData implements TransactionController.JSONDecodeTransactionData
+func (bnttc BotNameTransferWithFulfillmentTransactionController) JSONDecodeTransactionData(data []byte) (types.TransactionData, error) {
+	var bnttx BotNameTransferWithFulfillmentTransaction
+	err := json.Unmarshal(data, &bnttx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to json-decode tx as a BotNameTransferWithFulfillmentTx: %v", err)
+	}
+	// return bot name transfer tx as regular tfchain tx data
+	return bnttx.TransactionData(bnttc.OneCoin), nil
+}
+
+// SignExtension implements TransactionExtensionSigner.SignExtension
+func (bnttc BotNameTransferWithFulfillmentTransactionController) SignExtension(extension interface{}, sign func(*types.UnlockFulfillmentProxy, types.UnlockConditionProxy, ...interface{}) error) (interface{}, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid BotNameTransferWithFulfillmentTransactionExtension
+	bnttxExtension, ok := extension.(*BotNameTransferWithFulfillmentTransactionExtension)
+	if !ok {
+		return nil, errors.New("invalid extension data for a BotNameTransferWithFulfillmentTx")
+	}
+
+	// sign the sender
+	condition, fulfillment, err := getConditionAndFulfillmentForBotOwner(bnttc.Registry, bnttxExtension.Sender.Identifier, bnttxExtension.Sender.Fulfillment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare the signing (as the sender) of the BotNameTransferWithFulfillmentTx: %v", err)
+	}
+	err = sign(&fulfillment, condition, BotSignatureSpecifierSender)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign (as the sender) the BotNameTransferWithFulfillmentTx: %v", err)
+	}
+	bnttxExtension.Sender.Fulfillment = fulfillment
+
+	// (or) sign the receiver
+	condition, fulfillment, err = getConditionAndFulfillmentForBotOwner(bnttc.Registry, bnttxExtension.Receiver.Identifier, bnttxExtension.Receiver.Fulfillment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare the signing (as the receiver) of the BotNameTransferWithFulfillmentTx: %v", err)
+	}
+	err = sign(&fulfillment, condition, BotSignatureSpecifierReceiver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign (as the receiver) the BotNameTransferWithFulfillmentTx: %v", err)
+	}
+	bnttxExtension.Receiver.Fulfillment = fulfillment
+
+	// and return the signed extension
+	return bnttxExtension, nil
+}
+
+// ValidateTransaction implements TransactionValidator.ValidateTransaction
+func (bnttc BotNameTransferWithFulfillmentTransactionController) ValidateTransaction(t types.Transaction, ctx types.ValidationContext, constants types.TransactionValidationConstants) error {
+	// given the strict typing of 3bot transactions,
+	// it is guaranteed by its properties that it will always fit within a Block,
+	// and thus the TransactionFitsInABlock is not needed.
+
+	// get BotNameTransferWithFulfillmentTx
+	bnttx, err := BotNameTransferWithFulfillmentTransactionFromTransaction(t)
+	if err != nil {
+		return fmt.Errorf("failed to use tx as a bot name transfer (with fulfillment) tx: %v", err)
+	}
+
+	// validate the miner fee
+	if bnttx.TransactionFee.Cmp(constants.MinimumMinerFee) == -1 {
+		return types.ErrTooSmallMinerFee
+	}
+
+	// validate the sender/receiver ID is different
+	if bnttx.Sender.Identifier == bnttx.Receiver.Identifier {
+		return errors.New("the identifiers of the sender and receiver bot have to be different")
+	}
+
+	// look up the records of the sender and receiver, to ensure they are registered,
+	// as well as for validation checks that follow
+	recordSender, err := bnttc.Registry.GetRecordForID(bnttx.Sender.Identifier)
+	if err != nil {
+		return fmt.Errorf("invalid sender (%d) of bot name transfer: %v", bnttx.Sender.Identifier, err)
+	}
+	recordReceiver, err := bnttc.Registry.GetRecordForID(bnttx.Receiver.Identifier)
+	if err != nil {
+		return fmt.Errorf("invalid receiver (%d) of bot name transfer: %v", bnttx.Receiver.Identifier, err)
+	}
+
+	// validate the fulfillment of the sender
+	err = validateBotRecordFulfillment(t, recordSender, bnttx.Sender.Fulfillment, ctx, BotSignatureSpecifierSender)
+	if err != nil {
+		return fmt.Errorf("failed to fulfill bot record name transfer condition of the sender: %v", err)
+	}
+	// validate the fulfillment of the receiver
+	err = validateBotRecordFulfillment(t, recordReceiver, bnttx.Receiver.Fulfillment, ctx, BotSignatureSpecifierReceiver)
+	if err != nil {
+		return fmt.Errorf("failed to fulfill bot record name transfer condition of the receiver: %v", err)
+	}
+
+	// validate the transfer itself
+	transfer := bnttx.AsBotNameTransferTransaction()
+	return validateBotNameTransfer(bnttc.Registry, bnttc.ConsensusParameterGetter, &transfer, recordSender, recordReceiver, ctx)
+}
+
+// ValidateBlockStakeOutputs implements BlockStakeOutputValidator.ValidateBlockStakeOutputs
+func (bnttc BotNameTransferWithFulfillmentTransactionController) ValidateBlockStakeOutputs(t types.Transaction, ctx types.FundValidationContext, blockStakeInputs map[types.BlockStakeOutputID]types.BlockStakeOutput) (err error) {
+	return nil // always valid, no block stake inputs/outputs exist within a bot name transfer transaction
+}
+
+// SignatureHash implements TransactionSignatureHasher.SignatureHash
+func (bnttc BotNameTransferWithFulfillmentTransactionController) SignatureHash(t types.Transaction, extraObjects ...interface{}) (crypto.Hash, error) {
+	bnttx, err := BotNameTransferWithFulfillmentTransactionFromTransaction(t)
+	if err != nil {
+		return crypto.Hash{}, fmt.Errorf("failed to use tx as a BotNameTransferWithFulfillmentTx: %v", err)
+	}
+	transfer := bnttx.AsBotNameTransferTransaction()
+	return botNameTransferSignatureHash(t.Version, SpecifierBotNameTransferWithFulfillmentTransaction, &transfer, extraObjects...), nil
+}
+
+// EncodeTransactionIDInput implements TransactionIDEncoder.EncodeTransactionIDInput
+func (bnttc BotNameTransferWithFulfillmentTransactionController) EncodeTransactionIDInput(w io.Writer, txData types.TransactionData) error {
+	bnttx, err := BotNameTransferWithFulfillmentTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a BotNameTransferWithFulfillmentTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).EncodeAll(SpecifierBotNameTransferWithFulfillmentTransaction, bnttx)
+}
+
+// GetCustomMinerPayoutsAt implements TransactionCustomMinerPayoutAtHeightGetter.GetCustomMinerPayoutsAt
+func (bnttc BotNameTransferWithFulfillmentTransactionController) GetCustomMinerPayoutsAt(extension interface{}, height types.BlockHeight) ([]types.MinerPayout, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid BotNameTransferWithFulfillmentTransactionExtension
+	bnttxExtension, ok := extension.(*BotNameTransferWithFulfillmentTransactionExtension)
+	if !ok {
+		return nil, errors.New("invalid extension data for a Bot NameTransfer (with fulfillment) Transaction")
+	}
+	// the bot fees are defined by the consensus parameters that apply to the block at the given height
+	params, err := bnttc.ConsensusParameterGetter.GetConsensusParametersAt(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the consensus parameters at block height %d: %v", height, err)
+	}
+	return []types.MinerPayout{
+		{
+			Value:      bnttxExtension.RequiredBotFee(params),
+			UnlockHash: bnttc.RegistryPoolAddress,
+		},
+	}, nil
+}
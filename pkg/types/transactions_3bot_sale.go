@@ -0,0 +1,585 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// Bot name sale validation errors
+var (
+	ErrBotNameSaleOfferExpired       = errors.New("bot name sale offer is expired")
+	ErrBotNameSaleOfferOutdated      = errors.New("bot name sale offer is outdated, the record of the seller was updated since it was made")
+	ErrBotNameSalePaymentNotDefined  = errors.New("bot name sale requires the payment of the seller as first coin output")
+	ErrBotNameSalePaymentMismatch    = errors.New("bot name sale payment does not match the price and payout address of the offer")
+	ErrBotNameSaleReceiverNotDefined = errors.New("bot name sale offer has not been completed by a receiver yet")
+)
+
+type (
+	// BotNameSaleTransaction defines the Transaction (with version 0x96)
+	// used to sell one or multiple names of an active 3bot to another 3bot.
+	// The receiving 3bot pays the price defined by the sending 3bot
+	// to the payout address of the sending 3bot, within the same Tx,
+	// such that the names and coins are transferred atomically.
+	//
+	// The sender signs only the offer, which defines the names, price, payout address,
+	// expiration height and the version of the sender's record, such that any 3bot can complete
+	// and submit the offer, by defining itself as receiver and funding the price as well as the required fees.
+	BotNameSaleTransaction struct {
+		// Offer defines the sale as defined and signed by the sender.
+		Offer BotNameSaleOffer `json:"offer"`
+		// Receiver is in this context the 3bot that buys the names
+		// defined in the offer of this Tx from the 3bot defined in that offer as the Sender.
+		// The Receiver has to be different from the Sender.
+		Receiver BotIdentifierFulfillmentPair `json:"receiver"`
+
+		// TransactionFee defines the regular Tx fee.
+		TransactionFee types.Currency `json:"txfee"`
+
+		// CoinInputs are used to pay the price of the offer,
+		// as well as the regular Tx fee and the additional fees,
+		// to be paid for a 3bot name sale. At least one CoinInput is required,
+		// but only once the offer is completed by the receiver.
+		CoinInputs []types.CoinInput `json:"coininputs"`
+		// RefundCoinOutput is an optional coin output that can be used
+		// to refund coins paid as inputs for the price and required fees.
+		RefundCoinOutput *types.CoinOutput `json:"refundcoinoutput,omitempty"`
+	}
+	// BotNameSaleOffer defines the part of a BotNameSaleTransaction that is defined,
+	// and signed, by the sender (seller) of the names.
+	BotNameSaleOffer struct {
+		// Sender is in this context the 3bot that owns and sells the names
+		// defined in this offer to the 3bot that completes it.
+		Sender BotIdentifierFulfillmentPair `json:"sender"`
+		// Names to be sold by sender to receiver. Note that after each Tx,
+		// no more than 5 names can be linked to a single 3bot record.
+		Names []BotName `json:"names"`
+		// Price to be paid by the receiver to the payout address of the sender.
+		Price types.Currency `json:"price"`
+		// PayoutAddress defines the address the price is paid to.
+		PayoutAddress types.UnlockHash `json:"payoutaddress"`
+		// ExpirationHeight defines the last block height at which the offer can be completed.
+		ExpirationHeight types.BlockHeight `json:"expirationheight"`
+		// SenderVersion is the identifier of the last transaction that created or updated
+		// the record of the sender at the time the offer was made. The offer can only be completed
+		// as long as that record isn't updated again, such that it cannot be replayed.
+		SenderVersion types.TransactionID `json:"senderversion"`
+	}
+	// BotNameSaleTransactionExtension defines the
+	// BotNameSaleTransaction Extension Data
+	BotNameSaleTransactionExtension struct {
+		Offer    BotNameSaleOffer
+		Receiver BotIdentifierFulfillmentPair
+	}
+)
+
+// PaymentCoinOutput returns the coin output that pays the price of this offer
+// to the payout address of the sender.
+func (offer *BotNameSaleOffer) PaymentCoinOutput() types.CoinOutput {
+	return types.CoinOutput{
+		Value:     offer.Price,
+		Condition: types.NewCondition(types.NewUnlockHashCondition(offer.PayoutAddress)),
+	}
+}
+
+// IsExpired returns true if this offer can no longer be completed at the given height.
+func (offer *BotNameSaleOffer) IsExpired(height types.BlockHeight) bool {
+	return height > offer.ExpirationHeight
+}
+
+// RequiredBotFee computes the required Bot Fee, that is to be applied as a required
+// additional fee on top of the regular required (minimum) Tx fee.
+func (bnstxe *BotNameSaleTransactionExtension) RequiredBotFee(params ConsensusParameters) types.Currency {
+	return params.BotNameFee.Mul64(uint64(len(bnstxe.Offer.Names)))
+}
+
+// BotNameSaleTransactionFromTransaction creates a BotNameSaleTransaction,
+// using a regular in-memory tfchain transaction.
+//
+// Past the (tx) Version validation it piggy-backs onto the
+// `BotNameSaleTransactionFromTransactionData` constructor.
+func BotNameSaleTransactionFromTransaction(tx types.Transaction) (BotNameSaleTransaction, error) {
+	if tx.Version != TransactionVersionBotNameSale {
+		return BotNameSaleTransaction{}, fmt.Errorf(
+			"a bot name sale transaction requires tx version %d",
+			TransactionVersionBotNameSale)
+	}
+	return BotNameSaleTransactionFromTransactionData(types.TransactionData{
+		CoinInputs:        tx.CoinInputs,
+		CoinOutputs:       tx.CoinOutputs,
+		BlockStakeInputs:  tx.BlockStakeInputs,
+		BlockStakeOutputs: tx.BlockStakeOutputs,
+		MinerFees:         tx.MinerFees,
+		ArbitraryData:     tx.ArbitraryData,
+		Extension:         tx.Extension,
+	})
+}
+
+// BotNameSaleTransactionFromTransactionData creates a BotNameSaleTransaction,
+// using the TransactionData from a regular in-memory tfchain transaction.
+func BotNameSaleTransactionFromTransactionData(txData types.TransactionData) (BotNameSaleTransaction, error) {
+	// validate the Transaction Data
+	err := validateBotNameSaleInMemoryTransactionDataRequirements(txData)
+	if err != nil {
+		return BotNameSaleTransaction{}, fmt.Errorf("BotNameSaleTransaction: %v", err)
+	}
+
+	// (tx) extension (data) is expected to be a pointer to a valid BotNameSaleTransactionExtension,
+	// which contains all the properties unique to a 3bot (name sale) Tx
+	extensionData, ok := txData.Extension.(*BotNameSaleTransactionExtension)
+	if !ok {
+		return BotNameSaleTransaction{}, errors.New("invalid extension data for a BotNameSaleTransaction")
+	}
+
+	// create the BotNameSaleTransaction and return it,
+	// all should be good (at least the common requirements, it might still be invalid for version-specific reasons),
+	// the first coin output is the payment, which is defined by the offer and validated as part of the coin outputs
+	tx := BotNameSaleTransaction{
+		Offer:          extensionData.Offer,
+		Receiver:       extensionData.Receiver,
+		TransactionFee: txData.MinerFees[0],
+		CoinInputs:     txData.CoinInputs,
+	}
+	if len(txData.CoinOutputs) == 2 {
+		// take refund coin output
+		tx.RefundCoinOutput = &txData.CoinOutputs[1]
+	}
+	return tx, nil
+}
+
+// TransactionData returns this BotNameSaleTransaction
+// as regular tfchain transaction data.
+func (bnstx *BotNameSaleTransaction) TransactionData(oneCoin types.Currency) types.TransactionData {
+	txData := types.TransactionData{
+		CoinInputs:  bnstx.CoinInputs,
+		CoinOutputs: []types.CoinOutput{bnstx.Offer.PaymentCoinOutput()},
+		MinerFees:   []types.Currency{bnstx.TransactionFee},
+		Extension: &BotNameSaleTransactionExtension{
+			Offer:    bnstx.Offer,
+			Receiver: bnstx.Receiver,
+		},
+	}
+	if bnstx.RefundCoinOutput != nil {
+		txData.CoinOutputs = append(txData.CoinOutputs, *bnstx.RefundCoinOutput)
+	}
+	return txData
+}
+
+// Transaction returns this BotNameSaleTransaction
+// as regular tfchain transaction, using TransactionVersionBotNameSale as the type.
+func (bnstx *BotNameSaleTransaction) Transaction(oneCoin types.Currency) types.Transaction {
+	txData := bnstx.TransactionData(oneCoin)
+	return types.Transaction{
+		Version:     TransactionVersionBotNameSale,
+		CoinInputs:  txData.CoinInputs,
+		CoinOutputs: txData.CoinOutputs,
+		MinerFees:   txData.MinerFees,
+		Extension:   txData.Extension,
+	}
+}
+
+// RequiredBotFee computes the required Bot Fee, that is to be applied as a required
+// additional fee on top of the regular required (minimum) Tx fee.
+func (bnstx *BotNameSaleTransaction) RequiredBotFee(params ConsensusParameters) types.Currency {
+	return (&BotNameSaleTransactionExtension{
+		Offer:    bnstx.Offer,
+		Receiver: bnstx.Receiver,
+	}).RequiredBotFee(params)
+}
+
+// AsBotNameTransferTransaction returns this Tx as a (signature-less) BotNameTransferTransaction,
+// such that the record update (and revert) logic can be shared with the name transfer transactions.
+func (bnstx *BotNameSaleTransaction) AsBotNameTransferTransaction() BotNameTransferTransaction {
+	return BotNameTransferTransaction{
+		Sender:           BotIdentifierSignaturePair{Identifier: bnstx.Offer.Sender.Identifier},
+		Receiver:         BotIdentifierSignaturePair{Identifier: bnstx.Receiver.Identifier},
+		Names:            bnstx.Offer.Names,
+		TransactionFee:   bnstx.TransactionFee,
+		CoinInputs:       bnstx.CoinInputs,
+		RefundCoinOutput: bnstx.RefundCoinOutput,
+	}
+}
+
+// MarshalSia implements SiaMarshaler.MarshalSia,
+// alias of MarshalRivine for backwards-compatibility reasons.
+func (bnstx BotNameSaleTransaction) MarshalSia(w io.Writer) error {
+	return bnstx.MarshalRivine(w)
+}
+
+// UnmarshalSia implements SiaUnmarshaler.UnmarshalSia,
+// alias of UnmarshalRivine for backwards-compatibility reasons.
+func (bnstx *BotNameSaleTransaction) UnmarshalSia(r io.Reader) error {
+	return bnstx.UnmarshalRivine(r)
+}
+
+// MarshalRivine implements RivineMarshaler.MarshalRivine
+func (bnstx BotNameSaleTransaction) MarshalRivine(w io.Writer) error {
+	return rivbin.NewEncoder(w).EncodeAll(
+		bnstx.Offer.Sender.Identifier,
+		bnstx.Offer.Sender.Fulfillment,
+		bnstx.Offer.Names,
+		bnstx.Offer.Price,
+		bnstx.Offer.PayoutAddress,
+		bnstx.Offer.ExpirationHeight,
+		bnstx.Offer.SenderVersion,
+		bnstx.Receiver.Identifier,
+		bnstx.Receiver.Fulfillment,
+		bnstx.TransactionFee,
+		bnstx.CoinInputs,
+		bnstx.RefundCoinOutput,
+	)
+}
+
+// UnmarshalRivine implements RivineUnmarshaler.UnmarshalRivine
+func (bnstx *BotNameSaleTransaction) UnmarshalRivine(r io.Reader) error {
+	return rivbin.NewDecoder(r).DecodeAll(
+		&bnstx.Offer.Sender.Identifier,
+		&bnstx.Offer.Sender.Fulfillment,
+		&bnstx.Offer.Names,
+		&bnstx.Offer.Price,
+		&bnstx.Offer.PayoutAddress,
+		&bnstx.Offer.ExpirationHeight,
+		&bnstx.Offer.SenderVersion,
+		&bnstx.Receiver.Identifier,
+		&bnstx.Receiver.Fulfillment,
+		&bnstx.TransactionFee,
+		&bnstx.CoinInputs,
+		&bnstx.RefundCoinOutput,
+	)
+}
+
+type (
+	// BotNameSaleTransactionController defines a tfchain-specific transaction controller,
+	// for a transaction type reserved at type 0x96. It allows the sale of one or multiple names
+	// from one active 3bot to another, paid for by the receiving 3bot within the same transaction.
+	BotNameSaleTransactionController struct {
+		Registry            BotRecordReadRegistry
+		RegistryPoolAddress types.UnlockHash
+		OneCoin             types.Currency
+		// ConsensusParameterGetter is used to get the consensus parameters at a given block height,
+		// which define the bot fees to be paid to the registry pool address.
+		ConsensusParameterGetter ConsensusParameterGetter
+	}
+)
+
+var (
+	// ensure at compile time that BotNameSaleTransactionController
+	// implements the desired interfaces
+	_ types.TransactionController                      = BotNameSaleTransactionController{}
+	_ types.TransactionExtensionSigner                 = BotNameSaleTransactionController{}
+	_ types.TransactionValidator                       = BotNameSaleTransactionController{}
+	_ types.CoinOutputValidator                        = BotNameSaleTransactionController{}
+	_ types.BlockStakeOutputValidator                  = BotNameSaleTransactionController{}
+	_ types.TransactionSignatureHasher                 = BotNameSaleTransactionController{}
+	_ types.TransactionIDEncoder                       = BotNameSaleTransactionController{}
+	_ types.TransactionCustomMinerPayoutAtHeightGetter = BotNameSaleTransactionController{}
+)
+
+// EncodeTransactionData implements TransactionController.EncodeTransactionData
+func (bnstc BotNameSaleTransactionController) EncodeTransactionData(w io.Writer, txData types.TransactionData) error {
+	bnstx, err := BotNameSaleTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a BotNameSaleTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).Encode(bnstx)
+}
+
+// DecodeTransactionData implements TransactionController.DecodeTransactionData
+func (bnstc BotNameSaleTransactionController) DecodeTransactionData(r io.Reader) (types.TransactionData, error) {
+	var bnstx BotNameSaleTransaction
+	err := rivbin.NewDecoder(r).Decode(&bnstx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to binary-decode tx as a BotNameSaleTx: %v", err)
+	}
+	// return bot name sale tx as regular tfchain tx data
+	return bnstx.TransactionData(bnstc.OneCoin), nil
+}
+
+// JSONEncodeTransactionData implements TransactionController.JSONEncodeTransactionData
+func (bnstc BotNameSaleTransactionController) JSONEncodeTransactionData(txData types.TransactionData) ([]byte, error) {
+	bnstx, err := BotNameSaleTransactionFromTransactionData(txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert txData to a BotNameSaleTx: %v", err)
+	}
+	return json.Marshal(bnstx)
+}
+
+// JSONDecodeTransactionData implements TransactionController.JSONDecodeTransactionData
+func (bnstc BotNameSaleTransactionController) JSONDecodeTransactionData(data []byte) (types.TransactionData, error) {
+	var bnstx BotNameSaleTransaction
+	err := json.Unmarshal(data, &bnstx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to json-decode tx as a BotNameSaleTx: %v", err)
+	}
+	// return bot name sale tx as regular tfchain tx data
+	return bnstx.TransactionData(bnstc.OneCoin), nil
+}
+
+// SignExtension implements TransactionExtensionSigner.SignExtension
+func (bnstc BotNameSaleTransactionController) SignExtension(extension interface{}, sign func(*types.UnlockFulfillmentProxy, types.UnlockConditionProxy, ...interface{}) error) (interface{}, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid BotNameSaleTransactionExtension
+	bnstxExtension, ok := extension.(*BotNameSaleTransactionExtension)
+	if !ok {
+		return nil, errors.New("invalid extension data for a BotNameSaleTx")
+	}
+
+	// sign the offer as the sender
+	condition, fulfillment, err := getConditionAndFulfillmentForBotOwner(bnstc.Registry, bnstxExtension.Offer.Sender.Identifier, bnstxExtension.Offer.Sender.Fulfillment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare the signing (as the sender) of the BotNameSaleTx: %v", err)
+	}
+	err = sign(&fulfillment, condition, BotSignatureSpecifierSender)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign (as the sender) the BotNameSaleTx: %v", err)
+	}
+	bnstxExtension.Offer.Sender.Fulfillment = fulfillment
+
+	// an offer that is not yet completed has no receiver (0 is never a valid bot ID),
+	// in which case only the offer can be signed
+	if bnstxExtension.Receiver.Identifier == 0 {
+		return bnstxExtension, nil
+	}
+
+	// (or) sign the receiver
+	condition, fulfillment, err = getConditionAndFulfillmentForBotOwner(bnstc.Registry, bnstxExtension.Receiver.Identifier, bnstxExtension.Receiver.Fulfillment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare the signing (as the receiver) of the BotNameSaleTx: %v", err)
+	}
+	err = sign(&fulfillment, condition, BotSignatureSpecifierReceiver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign (as the receiver) the BotNameSaleTx: %v", err)
+	}
+	bnstxExtension.Receiver.Fulfillment = fulfillment
+
+	// and return the signed extension
+	return bnstxExtension, nil
+}
+
+// ValidateTransaction implements TransactionValidator.ValidateTransaction
+func (bnstc BotNameSaleTransactionController) ValidateTransaction(t types.Transaction, ctx types.ValidationContext, constants types.TransactionValidationConstants) error {
+	// given the strict typing of 3bot transactions,
+	// it is guaranteed by its properties that it will always fit within a Block,
+	// and thus the TransactionFitsInABlock is not needed.
+
+	// get BotNameSaleTx
+	bnstx, err := BotNameSaleTransactionFromTransaction(t)
+	if err != nil {
+		return fmt.Errorf("failed to use tx as a bot name sale tx: %v", err)
+	}
+
+	// validate the miner fee
+	if bnstx.TransactionFee.Cmp(constants.MinimumMinerFee) == -1 {
+		return types.ErrTooSmallMinerFee
+	}
+
+	// a completed offer is required, funded by the receiver
+	if bnstx.Receiver.Identifier == 0 {
+		return ErrBotNameSaleReceiverNotDefined
+	}
+	if len(bnstx.CoinInputs) == 0 {
+		return errors.New("at least one coin input is required for a bot name sale transaction")
+	}
+
+	// validate the offer
+	if bnstx.Offer.IsExpired(getBlockHeightForContext(ctx)) {
+		return ErrBotNameSaleOfferExpired
+	}
+	if bnstx.Offer.Price.IsZero() {
+		return errors.New("a bot name sale requires a price, use a bot name transfer to transfer names for free")
+	}
+	if bnstx.Offer.PayoutAddress.Type == types.UnlockTypeNil {
+		return errors.New("a bot name sale requires a payout address")
+	}
+
+	// validate the sender/receiver ID is different
+	if bnstx.Offer.Sender.Identifier == bnstx.Receiver.Identifier {
+		return errors.New("the identifiers of the sender and receiver bot have to be different")
+	}
+
+	// look up the records of the sender and receiver, to ensure they are registered,
+	// as well as for validation checks that follow
+	recordSender, err := bnstc.Registry.GetRecordForID(bnstx.Offer.Sender.Identifier)
+	if err != nil {
+		return fmt.Errorf("invalid sender (%d) of bot name sale: %v", bnstx.Offer.Sender.Identifier, err)
+	}
+	// the offer is only valid for the version of the sender's record it was made for,
+	// such that it cannot be completed again once the names were sold (or otherwise updated)
+	senderTxIDs, err := bnstc.Registry.GetBotTransactionIdentifiers(bnstx.Offer.Sender.Identifier)
+	if err != nil {
+		return fmt.Errorf("failed to get the transactions of sender (%d) of bot name sale: %v", bnstx.Offer.Sender.Identifier, err)
+	}
+	if len(senderTxIDs) == 0 || senderTxIDs[len(senderTxIDs)-1] != bnstx.Offer.SenderVersion {
+		return ErrBotNameSaleOfferOutdated
+	}
+	recordReceiver, err := bnstc.Registry.GetRecordForID(bnstx.Receiver.Identifier)
+	if err != nil {
+		return fmt.Errorf("invalid receiver (%d) of bot name sale: %v", bnstx.Receiver.Identifier, err)
+	}
+
+	// validate the fulfillment of the sender
+	err = validateBotRecordFulfillment(t, recordSender, bnstx.Offer.Sender.Fulfillment, ctx, BotSignatureSpecifierSender)
+	if err != nil {
+		return fmt.Errorf("failed to fulfill bot record name sale condition of the sender: %v", err)
+	}
+	// validate the fulfillment of the receiver
+	err = validateBotRecordFulfillment(t, recordReceiver, bnstx.Receiver.Fulfillment, ctx, BotSignatureSpecifierReceiver)
+	if err != nil {
+		return fmt.Errorf("failed to fulfill bot record name sale condition of the receiver: %v", err)
+	}
+
+	// validate the transfer of the names itself
+	transfer := bnstx.AsBotNameTransferTransaction()
+	return validateBotNameTransfer(bnstc.Registry, bnstc.ConsensusParameterGetter, &transfer, recordSender, recordReceiver, ctx)
+}
+
+// ValidateCoinOutputs implements CoinOutputValidator.ValidateCoinOutputs,
+// ensuring that the price of the offer is paid to the payout address of the sender,
+// prior to applying the default coin output validation, which ensures the coin inputs
+// fund the payment, as well as all fees and the optional refund.
+func (bnstc BotNameSaleTransactionController) ValidateCoinOutputs(t types.Transaction, ctx types.FundValidationContext, coinInputs map[types.CoinOutputID]types.CoinOutput) error {
+	bnstx, err := BotNameSaleTransactionFromTransaction(t)
+	if err != nil {
+		return fmt.Errorf("failed to use tx as a bot name sale tx: %v", err)
+	}
+	if len(t.CoinOutputs) == 0 {
+		return ErrBotNameSalePaymentNotDefined
+	}
+	payment := bnstx.Offer.PaymentCoinOutput()
+	// the entire condition is compared, rather than only its unlock hash,
+	// as the payment could otherwise be locked (e.g. by a time lock) for the seller
+	if !t.CoinOutputs[0].Value.Equals(payment.Value) || !t.CoinOutputs[0].Condition.Equal(payment.Condition) {
+		return ErrBotNameSalePaymentMismatch
+	}
+	return types.DefaultCoinOutputValidation(t, ctx, coinInputs)
+}
+
+// ValidateBlockStakeOutputs implements BlockStakeOutputValidator.ValidateBlockStakeOutputs
+func (bnstc BotNameSaleTransactionController) ValidateBlockStakeOutputs(t types.Transaction, ctx types.FundValidationContext, blockStakeInputs map[types.BlockStakeOutputID]types.BlockStakeOutput) (err error) {
+	return nil // always valid, no block stake inputs/outputs exist within a bot name sale transaction
+}
+
+// SignatureHash implements TransactionSignatureHasher.SignatureHash
+func (bnstc BotNameSaleTransactionController) SignatureHash(t types.Transaction, extraObjects ...interface{}) (crypto.Hash, error) {
+	bnstx, err := BotNameSaleTransactionFromTransaction(t)
+	if err != nil {
+		return crypto.Hash{}, fmt.Errorf("failed to use tx as a BotNameSaleTx: %v", err)
+	}
+
+	h := crypto.NewHash()
+	enc := rivbin.NewEncoder(h)
+
+	// the sender signs only the offer, such that any receiver can complete it
+	if len(extraObjects) > 0 && extraObjects[0] == BotSignatureSpecifierSender {
+		enc.EncodeAll(
+			t.Version,
+			SpecifierBotNameSaleTransaction,
+			bnstx.Offer.Sender.Identifier,
+		)
+		enc.EncodeAll(extraObjects...)
+		enc.EncodeAll(
+			bnstx.Offer.Names,
+			bnstx.Offer.Price,
+			bnstx.Offer.PayoutAddress,
+			bnstx.Offer.ExpirationHeight,
+			bnstx.Offer.SenderVersion,
+		)
+		var hash crypto.Hash
+		h.Sum(hash[:0])
+		return hash, nil
+	}
+
+	// the receiver signs the complete transaction
+	enc.EncodeAll(
+		t.Version,
+		SpecifierBotNameSaleTransaction,
+		bnstx.Offer.Sender.Identifier,
+		bnstx.Receiver.Identifier,
+	)
+
+	if len(extraObjects) > 0 {
+		enc.EncodeAll(extraObjects...)
+	}
+
+	enc.EncodeAll(
+		bnstx.Offer.Names,
+		bnstx.Offer.Price,
+		bnstx.Offer.PayoutAddress,
+		bnstx.Offer.ExpirationHeight,
+		bnstx.Offer.SenderVersion,
+	)
+
+	enc.Encode(len(bnstx.CoinInputs))
+	for _, ci := range bnstx.CoinInputs {
+		enc.Encode(ci.ParentID)
+	}
+
+	enc.EncodeAll(
+		bnstx.TransactionFee,
+		bnstx.RefundCoinOutput,
+	)
+
+	var hash crypto.Hash
+	h.Sum(hash[:0])
+	return hash, nil
+}
+
+// EncodeTransactionIDInput implements TransactionIDEncoder.EncodeTransactionIDInput
+func (bnstc BotNameSaleTransactionController) EncodeTransactionIDInput(w io.Writer, txData types.TransactionData) error {
+	bnstx, err := BotNameSaleTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a BotNameSaleTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).EncodeAll(SpecifierBotNameSaleTransaction, bnstx)
+}
+
+// GetCustomMinerPayoutsAt implements TransactionCustomMinerPayoutAtHeightGetter.GetCustomMinerPayoutsAt
+func (bnstc BotNameSaleTransactionController) GetCustomMinerPayoutsAt(extension interface{}, height types.BlockHeight) ([]types.MinerPayout, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid BotNameSaleTransactionExtension
+	bnstxExtension, ok := extension.(*BotNameSaleTransactionExtension)
+	if !ok {
+		return nil, errors.New("invalid extension data for a Bot Name Sale Transaction")
+	}
+	// the bot fees are defined by the consensus parameters that apply to the block at the given height
+	params, err := bnstc.ConsensusParameterGetter.GetConsensusParametersAt(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the consensus parameters at block height %d: %v", height, err)
+	}
+	return []types.MinerPayout{
+		{
+			Value:      bnstxExtension.RequiredBotFee(params),
+			UnlockHash: bnstc.RegistryPoolAddress,
+		},
+	}, nil
+}
+
+func validateBotNameSaleInMemoryTransactionDataRequirements(txData types.TransactionData) error {
+	// exactly one miner fee is required, coin inputs are only required
+	// once the offer is completed, and thus checked as part of the tx validation
+	if len(txData.MinerFees) != 1 {
+		return errors.New("exactly one miner fee is required for a Bot Name Sale Transaction")
+	}
+	// no block stake inputs or block stake outputs are allowed
+	if len(txData.BlockStakeInputs) != 0 || len(txData.BlockStakeOutputs) != 0 {
+		return errors.New("no block stake inputs/outputs are allowed in a Bot Name Sale Transaction")
+	}
+	// no arbitrary data is allowed
+	if len(txData.ArbitraryData) > 0 {
+		return errors.New("no arbitrary data is allowed in a Bot Name Sale Transaction")
+	}
+	// validate that the coin outputs is within the expected range,
+	// the first coin output is the payment, the second one the optional refund
+	if len(txData.CoinOutputs) == 0 {
+		return ErrBotNameSalePaymentNotDefined
+	}
+	if len(txData.CoinOutputs) > 2 {
+		return errors.New("a Bot Name Sale Transaction can have maximum 2 Coin Outputs")
+	}
+	return nil
+}
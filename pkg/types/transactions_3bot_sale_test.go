@@ -0,0 +1,348 @@
+package types
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/threefoldfoundation/tfchain/pkg/config"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestBotNameSaleTransactionBinaryEncodingAndID(t *testing.T) {
+	types.RegisterTransactionVersion(TransactionVersionBotNameSale, BotNameSaleTransactionController{
+		OneCoin:                  config.GetCurrencyUnits().OneCoin,
+		ConsensusParameterGetter: staticConsensusParameterGetter(GetDevnetGenesisConsensusParameters()),
+	})
+	defer types.RegisterTransactionVersion(TransactionVersionBotNameSale, nil)
+
+	const (
+		jsonEncodedTx = `{"version":150,"data":{"offer":{"sender":{"id":1,"fulfillment":{"type":1,"data":{"publickey":"ed25519:cecc1507dc1ddd7295951c290888f095adb9044d1b73d696e6df065d683bd4fc","signature":"fd738ee09965c5a7bc51d4ebeef5660129896074555e22fe003adab24e0cd8472cfadd0e104f08b0ed45cf822684d9a7e808fe48f09a7df9941b8cfaa7a24306"}}},"names":["example.chatbot"],"price":"100000000000","payoutaddress":"019616785134d8be70a0866128fdbc8b48edbfbca7ea05cfb357cc003392a350a39eb5bc849604","expirationheight":150000,"senderversion":"a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee563"},"receiver":{"id":2,"fulfillment":{"type":1,"data":{"publickey":"ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780","signature":"c1060d5d22933d6848c7726df031ecf9525552d8774c4061e4b3572449395b0480f2f4c096ea15e6edc3d8ca3bae3e3c60dcb6385fb99646b689bed687a1d504"}}},"txfee":"1000000000","coininputs":[{"parentid":"c6b161d192d8095efd4d9946f7d154bf335f51fdfdeca4bb0cb990b25ffd7e95","fulfillment":{"type":1,"data":{"publickey":"ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780","signature":"784bae52f704ca6b64d5f29c3f055d71a6eac4dcde773908551b295782b3e628d02a3870f57f3f13f98af1fff50cc8e31b16b563dc40c54bb3f9e2696efe5403"}}}],"refundcoinoutput":{"value":"99999899000000000","condition":{"type":1,"data":{"unlockhash":"01822fd5fefd2748972ea828a5c56044dec9a2b2275229ce5b212f926cd52fba015846451e4e46"}}}}}`
+		hexEncodedTx  = `960100000001c401cecc1507dc1ddd7295951c290888f095adb9044d1b73d696e6df065d683bd4fc80fd738ee09965c5a7bc51d4ebeef5660129896074555e22fe003adab24e0cd8472cfadd0e104f08b0ed45cf822684d9a7e808fe48f09a7df9941b8cfaa7a24306021e6578616d706c652e63686174626f740a174876e800019616785134d8be70a0866128fdbc8b48edbfbca7ea05cfb357cc003392a350a3f049020000000000a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee5630200000001c401d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d778080c1060d5d22933d6848c7726df031ecf9525552d8774c4061e4b3572449395b0480f2f4c096ea15e6edc3d8ca3bae3e3c60dcb6385fb99646b689bed687a1d504083b9aca0002c6b161d192d8095efd4d9946f7d154bf335f51fdfdeca4bb0cb990b25ffd7e9501c401d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d778080784bae52f704ca6b64d5f29c3f055d71a6eac4dcde773908551b295782b3e628d02a3870f57f3f13f98af1fff50cc8e31b16b563dc40c54bb3f9e2696efe5403011001634560d9784e00014201822fd5fefd2748972ea828a5c56044dec9a2b2275229ce5b212f926cd52fba01`
+	)
+	var tx types.Transaction
+	err := json.Unmarshal([]byte(jsonEncodedTx), &tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := tx.ID()
+	b := siabin.Marshal(tx)
+	if output := hex.EncodeToString(b); output != hexEncodedTx {
+		t.Fatal(hexEncodedTx, "!=", output)
+	}
+
+	// go to bot name sale Tx and back
+	bnstx, err := BotNameSaleTransactionFromTransaction(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oTx := bnstx.Transaction(config.GetCurrencyUnits().OneCoin)
+	oID := oTx.ID()
+	oB := siabin.Marshal(oTx)
+	if id != oID {
+		t.Fatal(id, "!=", oID)
+	}
+	if !bytes.Equal(b, oB) {
+		t.Fatal(hex.EncodeToString(b), "!=", hex.EncodeToString(oB))
+	}
+
+	// binary decode it again, resulting in the same JSON-encoded transaction
+	var decodedTx types.Transaction
+	err = siabin.Unmarshal(oB, &decodedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err = json.Marshal(decodedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output := string(b); output != jsonEncodedTx {
+		t.Fatal(jsonEncodedTx, "!=", output)
+	}
+}
+
+func TestBotNameSaleOfferToAndFromJSON(t *testing.T) {
+	types.RegisterTransactionVersion(TransactionVersionBotNameSale, BotNameSaleTransactionController{
+		OneCoin:                  config.GetCurrencyUnits().OneCoin,
+		ConsensusParameterGetter: staticConsensusParameterGetter(GetDevnetGenesisConsensusParameters()),
+	})
+	defer types.RegisterTransactionVersion(TransactionVersionBotNameSale, nil)
+
+	// an open offer (no receiver and coin inputs yet) is only exchanged JSON-encoded,
+	// as the fulfillment of its receiver cannot be binary-decoded until it is defined
+	const jsonEncodedOffer = `{"version":150,"data":{"offer":{"sender":{"id":1,"fulfillment":{"type":1,"data":{"publickey":"ed25519:cecc1507dc1ddd7295951c290888f095adb9044d1b73d696e6df065d683bd4fc","signature":"fd738ee09965c5a7bc51d4ebeef5660129896074555e22fe003adab24e0cd8472cfadd0e104f08b0ed45cf822684d9a7e808fe48f09a7df9941b8cfaa7a24306"}}},"names":["example.chatbot"],"price":"100000000000","payoutaddress":"019616785134d8be70a0866128fdbc8b48edbfbca7ea05cfb357cc003392a350a39eb5bc849604","expirationheight":150000,"senderversion":"a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee563"},"receiver":{"id":0,"fulfillment":{}},"txfee":"1000000000","coininputs":null}}`
+	var tx types.Transaction
+	err := json.Unmarshal([]byte(jsonEncodedOffer), &tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bnstx, err := BotNameSaleTransactionFromTransaction(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bnstx.Receiver.Identifier != 0 || len(bnstx.CoinInputs) != 0 {
+		t.Fatal("unexpected receiver or coin inputs for an open offer:", bnstx.Receiver.Identifier, bnstx.CoinInputs)
+	}
+	b, err := json.Marshal(bnstx.Transaction(config.GetCurrencyUnits().OneCoin))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output := string(b); output != jsonEncodedOffer {
+		t.Fatal(jsonEncodedOffer, "!=", output)
+	}
+}
+
+func TestBotNameSaleTransactionSignAndValidate(t *testing.T) {
+	sellerKeyPair := newTestKeyPair(1)
+	registry := &inMemoryBotRegistry{
+		idMapping: map[BotID]BotRecord{
+			1: botRecordFromJSON(t, `{
+	"id": 1,
+	"names": ["example"],
+	"publickey": "ed25519:cecc1507dc1ddd7295951c290888f095adb9044d1b73d696e6df065d683bd4fc",
+	"expiration": 1538484360
+}`),
+			2: botRecordFromJSON(t, `{
+	"id": 2,
+	"addresses": ["93.184.216.34"],
+	"publickey": "ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780",
+	"expiration": 1538484360
+}`),
+		},
+		txMapping: map[BotID][]types.TransactionID{
+			1: {types.TransactionID(hs("a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee563"))},
+			2: {{2}},
+		},
+	}
+	oneCoin := config.GetCurrencyUnits().OneCoin
+	types.RegisterTransactionVersion(TransactionVersionBotNameSale, BotNameSaleTransactionController{
+		Registry:                 registry,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: staticConsensusParameterGetter(GetDevnetGenesisConsensusParameters()),
+	})
+	defer types.RegisterTransactionVersion(TransactionVersionBotNameSale, nil)
+
+	const unsignedJSONEncodedTx = `{
+	"version": 150,
+	"data": {
+		"offer": {
+			"sender": {
+				"id": 1,
+				"fulfillment": {
+					"type": 1,
+					"data": {
+						"publickey": "ed25519:cecc1507dc1ddd7295951c290888f095adb9044d1b73d696e6df065d683bd4fc",
+						"signature": ""
+					}
+				}
+			},
+			"names": ["example"],
+			"price": "100000000000",
+			"payoutaddress": "019616785134d8be70a0866128fdbc8b48edbfbca7ea05cfb357cc003392a350a39eb5bc849604",
+			"expirationheight": 150000,
+			"senderversion": "a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee563"
+		},
+		"receiver": {
+			"id": 2,
+			"fulfillment": {
+				"type": 1,
+				"data": {
+					"publickey": "ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780",
+					"signature": ""
+				}
+			}
+		},
+		"txfee": "1000000000",
+		"coininputs": [{
+			"parentid": "c6b161d192d8095efd4d9946f7d154bf335f51fdfdeca4bb0cb990b25ffd7e95",
+			"fulfillment": {
+				"type": 1,
+				"data": {
+					"publickey": "ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780",
+					"signature": ""
+				}
+			}
+		}],
+		"refundcoinoutput": {
+			"value": "99999899000000000",
+			"condition": {
+				"type": 1,
+				"data": {
+					"unlockhash": "01822fd5fefd2748972ea828a5c56044dec9a2b2275229ce5b212f926cd52fba015846451e4e46"
+				}
+			}
+		}
+	}
+}`
+	// decode the unsigned bot name sale, such that the offer can be modified prior to signing
+	decodeTx := func() BotNameSaleTransaction {
+		t.Helper()
+		var tx types.Transaction
+		err := tx.UnmarshalJSON([]byte(unsignedJSONEncodedTx))
+		if err != nil {
+			t.Fatal(err)
+		}
+		bnstx, err := BotNameSaleTransactionFromTransaction(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bnstx
+	}
+	// signs the extension using the given key pair,
+	// skipping any fulfillment that cannot be fulfilled by that key pair (as the wallet does)
+	signExtension := func(tx *types.Transaction, keyPair types.KeyPair) {
+		t.Helper()
+		err := tx.SignExtension(func(fulfillment *types.UnlockFulfillmentProxy, condition types.UnlockConditionProxy, extraObjects ...interface{}) error {
+			if condition.UnlockHash().Cmp(types.NewPubKeyUnlockHash(keyPair.PublicKey)) != 0 {
+				return nil
+			}
+			return fulfillment.Sign(types.FulfillmentSignContext{
+				ExtraObjects: extraObjects,
+				Transaction:  *tx,
+				Key:          keyPair.PrivateKey,
+			})
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the seller signs the offer, without knowing the receiver or coin inputs
+	signOffer := func(offer BotNameSaleOffer) BotNameSaleOffer {
+		t.Helper()
+		tx := (&BotNameSaleTransaction{
+			Offer:          offer,
+			TransactionFee: config.GetDevnetGenesis().MinimumTransactionFee,
+		}).Transaction(oneCoin)
+		signExtension(&tx, sellerKeyPair)
+		bnstx, err := BotNameSaleTransactionFromTransaction(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bnstx.Offer
+	}
+	// the buyer completes the (signed) offer
+	completeOffer := func(offer BotNameSaleOffer) types.Transaction {
+		t.Helper()
+		bnstx := decodeTx()
+		bnstx.Offer = offer
+		tx := bnstx.Transaction(oneCoin)
+		signExtension(&tx, cryptoKeyPair)
+		return tx
+	}
+
+	validationCtx := types.ValidationContext{
+		Confirmed:   true,
+		BlockHeight: 100,
+		BlockTime:   1538484000,
+	}
+	chainConstants := config.GetDevnetGenesis()
+	validationConstants := types.TransactionValidationConstants{
+		BlockSizeLimit:         chainConstants.BlockSizeLimit,
+		ArbitraryDataSizeLimit: chainConstants.ArbitraryDataSizeLimit,
+		MinimumMinerFee:        chainConstants.MinimumTransactionFee,
+	}
+
+	// a completed offer, signed by both seller and buyer, is valid
+	offer := signOffer(decodeTx().Offer)
+	tx := completeOffer(offer)
+	err := tx.ValidateTransaction(validationCtx, validationConstants)
+	if err != nil {
+		t.Fatal("failed to validate completed bot name sale:", err)
+	}
+
+	// an offer that is not yet completed is invalid
+	openTx := (&BotNameSaleTransaction{
+		Offer:          offer,
+		TransactionFee: validationConstants.MinimumMinerFee,
+		CoinInputs:     decodeTx().CoinInputs,
+	}).Transaction(oneCoin)
+	err = openTx.ValidateTransaction(validationCtx, validationConstants)
+	if err != ErrBotNameSaleReceiverNotDefined {
+		t.Fatal("unexpected error for an offer without receiver:", err)
+	}
+
+	// an expired offer is invalid
+	err = tx.ValidateTransaction(types.ValidationContext{
+		Confirmed:   true,
+		BlockHeight: offer.ExpirationHeight + 1,
+		BlockTime:   validationCtx.BlockTime,
+	}, validationConstants)
+	if err != ErrBotNameSaleOfferExpired {
+		t.Fatal("unexpected error for an expired offer:", err)
+	}
+	// unconfirmed transactions are validated for the next block,
+	// and thus expire once the expiration height is reached
+	err = tx.ValidateTransaction(types.ValidationContext{
+		BlockHeight: offer.ExpirationHeight,
+		BlockTime:   validationCtx.BlockTime,
+	}, validationConstants)
+	if err != ErrBotNameSaleOfferExpired {
+		t.Fatal("unexpected error for an unconfirmed offer at its expiration height:", err)
+	}
+
+	// changing the price, after the seller signed the offer, invalidates it
+	tamperedOffer := offer
+	tamperedOffer.Price = offer.Price.Div64(2)
+	err = completeOffer(tamperedOffer).ValidateTransaction(validationCtx, validationConstants)
+	if err == nil {
+		t.Fatal("succeeded to validate a bot name sale with a tampered price")
+	}
+
+	// changing the version of the seller's record, after the seller signed the offer, invalidates it
+	tamperedOffer = offer
+	tamperedOffer.SenderVersion = types.TransactionID{3}
+	registry.txMapping[1] = append(registry.txMapping[1], tamperedOffer.SenderVersion)
+	err = completeOffer(tamperedOffer).ValidateTransaction(validationCtx, validationConstants)
+	if err == nil {
+		t.Fatal("succeeded to validate a bot name sale with a tampered sender version")
+	}
+	// once the record of the seller is updated, the offer can no longer be completed,
+	// such that it cannot be replayed should the seller own the names once again
+	err = tx.ValidateTransaction(validationCtx, validationConstants)
+	if err != ErrBotNameSaleOfferOutdated {
+		t.Fatal("unexpected error for an outdated offer:", err)
+	}
+	registry.txMapping[1] = registry.txMapping[1][:1]
+
+	// the price has to be paid to the payout address of the seller
+	coinInputs := map[types.CoinOutputID]types.CoinOutput{
+		tx.CoinInputs[0].ParentID: {
+			Value:     tx.CoinOutputSumAt(validationCtx.BlockHeight),
+			Condition: types.NewCondition(types.NewUnlockHashCondition(types.NewPubKeyUnlockHash(cryptoKeyPair.PublicKey))),
+		},
+	}
+	err = tx.CoinInputs[0].Fulfillment.Sign(types.FulfillmentSignContext{
+		ExtraObjects: []interface{}{uint64(0)},
+		Transaction:  tx,
+		Key:          cryptoKeyPair.PrivateKey,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fundCtx := types.FundValidationContext{
+		BlockHeight: validationCtx.BlockHeight,
+		BlockTime:   validationCtx.BlockTime,
+	}
+	err = tx.ValidateCoinOutputs(fundCtx, coinInputs)
+	if err != nil {
+		t.Fatal("failed to validate coin outputs of completed bot name sale:", err)
+	}
+	payment := tx.CoinOutputs[0]
+	tx.CoinOutputs[0].Condition = types.NewCondition(types.NewUnlockHashCondition(types.NewPubKeyUnlockHash(cryptoKeyPair.PublicKey)))
+	err = tx.ValidateCoinOutputs(fundCtx, coinInputs)
+	if err != ErrBotNameSalePaymentMismatch {
+		t.Fatal("unexpected error for a payment to the wrong address:", err)
+	}
+	// the price cannot be paid to the payout address of the seller using locked coins
+	tx.CoinOutputs[0].Condition = types.NewCondition(types.NewTimeLockCondition(
+		uint64(validationCtx.BlockTime)+100*365*24*3600, payment.Condition.Condition.(types.MarshalableUnlockCondition)))
+	if tx.CoinOutputs[0].Condition.UnlockHash().Cmp(payment.Condition.UnlockHash()) != 0 {
+		t.Fatal("expected the time-locked payment to have the unlock hash of the payout address")
+	}
+	err = tx.ValidateCoinOutputs(fundCtx, coinInputs)
+	if err != ErrBotNameSalePaymentMismatch {
+		t.Fatal("unexpected error for a time-locked payment:", err)
+	}
+}
@@ -0,0 +1,482 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+type (
+	// BotRecordUpdateWithServicesTransaction defines the Transaction (with version 0x98)
+	// used to update a 3bot Record by the owner. It is identical to the BotRecordUpdateWithFulfillmentTransaction,
+	// except that it can also be used to add and/or remove the services offered by the 3bot.
+	BotRecordUpdateWithServicesTransaction struct {
+		// Identifier of the 3bot, used to find the 3bot record to be updated,
+		// and verify that the Tx is authorized to do so.
+		Identifier BotID `json:"id"`
+
+		// Addresses can be used to add and/or remove network addresses
+		// to/from the existing 3bot record. Note that after each Tx,
+		// no more than 10 addresses can be linked to a single 3bot record.
+		Addresses BotRecordAddressUpdate `json:"addresses,omitempty"`
+
+		// Names can be used to add and/or remove names
+		// to/from the existing 3bot record. Note that after each Tx,
+		// no more than 5 names can be linked to a single 3bot record.
+		Names BotRecordNameUpdate `json:"names,omitempty"`
+
+		// Services can be used to add and/or remove services
+		// to/from the existing 3bot record. Note that after each Tx,
+		// no more than 8 services can be linked to a single 3bot record.
+		Services BotRecordServiceUpdate `json:"services,omitempty"`
+
+		// NrOfMonths defines the optional amount of months that
+		// is desired to be paid upfront in this update.
+		// The NrOfMonths has to be within this inclusive range [0,24].
+		NrOfMonths uint8 `json:"nrofmonths"`
+
+		// TransactionFee defines the regular Tx fee.
+		TransactionFee types.Currency `json:"txfee"`
+
+		// CoinInputs are only used for the required fees,
+		// which contains the regular Tx fee as well as the additional fees,
+		// to be paid for a 3bot record update. At least one CoinInput is required.
+		CoinInputs []types.CoinInput `json:"coininputs"`
+		// RefundCoinOutput is an optional coin output that can be used
+		// to refund coins paid as inputs for the required fees.
+		RefundCoinOutput *types.CoinOutput `json:"refundcoinoutput,omitempty"`
+
+		// OwnerFulfillment is used to proof the ownership of the 3bot record to be updated,
+		// and has to fulfill the owner condition of the 3bot linked to the given (3bot) identifier.
+		OwnerFulfillment types.UnlockFulfillmentProxy `json:"ownerfulfillment"`
+	}
+	// BotRecordUpdateWithServicesTransactionExtension defines the
+	// BotRecordUpdateWithServicesTransaction Extension Data
+	BotRecordUpdateWithServicesTransactionExtension struct {
+		Identifier       BotID
+		OwnerFulfillment types.UnlockFulfillmentProxy
+		AddressUpdate    BotRecordAddressUpdate
+		NameUpdate       BotRecordNameUpdate
+		ServiceUpdate    BotRecordServiceUpdate
+		NrOfMonths       uint8
+	}
+)
+
+// RequiredBotFee computes the required Bot Fee, that is to be applied as a required
+// additional fee on top of the regular required (minimum) Tx fee.
+func (brutxe *BotRecordUpdateWithServicesTransactionExtension) RequiredBotFee(params ConsensusParameters) types.Currency {
+	return (&BotRecordUpdateTransaction{
+		Identifier: brutxe.Identifier,
+		Addresses:  brutxe.AddressUpdate,
+		Names:      brutxe.NameUpdate,
+		Services:   brutxe.ServiceUpdate,
+		NrOfMonths: brutxe.NrOfMonths,
+	}).RequiredBotFee(params)
+}
+
+// BotRecordUpdateWithServicesTransactionFromTransaction creates a BotRecordUpdateWithServicesTransaction,
+// using a regular in-memory tfchain transaction.
+//
+// Past the (tx) Version validation it piggy-backs onto the
+// `BotRecordUpdateWithServicesTransactionFromTransactionData` constructor.
+func BotRecordUpdateWithServicesTransactionFromTransaction(tx types.Transaction) (BotRecordUpdateWithServicesTransaction, error) {
+	if tx.Version != TransactionVersionBotRecordUpdateWithServices {
+		return BotRecordUpdateWithServicesTransaction{}, fmt.Errorf(
+			"a bot record update (with services) transaction requires tx version %d",
+			TransactionVersionBotRecordUpdateWithServices)
+	}
+	return BotRecordUpdateWithServicesTransactionFromTransactionData(types.TransactionData{
+		CoinInputs:        tx.CoinInputs,
+		CoinOutputs:       tx.CoinOutputs,
+		BlockStakeInputs:  tx.BlockStakeInputs,
+		BlockStakeOutputs: tx.BlockStakeOutputs,
+		MinerFees:         tx.MinerFees,
+		ArbitraryData:     tx.ArbitraryData,
+		Extension:         tx.Extension,
+	})
+}
+
+// BotRecordUpdateWithServicesTransactionFromTransactionData creates a BotRecordUpdateWithServicesTransaction,
+// using the TransactionData from a regular in-memory tfchain transaction.
+func BotRecordUpdateWithServicesTransactionFromTransactionData(txData types.TransactionData) (BotRecordUpdateWithServicesTransaction, error) {
+	// validate the Transaction Data
+	err := validateBotInMemoryTransactionDataRequirements(txData)
+	if err != nil {
+		return BotRecordUpdateWithServicesTransaction{}, fmt.Errorf("BotRecordUpdateWithServicesTransaction: %v", err)
+	}
+
+	// (tx) extension (data) is expected to be a pointer to a valid BotRecordUpdateWithServicesTransactionExtension,
+	// which contains all the properties unique to a 3bot (record update) Tx
+	extensionData, ok := txData.Extension.(*BotRecordUpdateWithServicesTransactionExtension)
+	if !ok {
+		return BotRecordUpdateWithServicesTransaction{}, errors.New("invalid extension data for a BotRecordUpdateWithServicesTransaction")
+	}
+
+	// create the BotRecordUpdateWithServicesTransaction and return it,
+	// all should be good (at least the common requirements, it might still be invalid for version-specific reasons)
+	tx := BotRecordUpdateWithServicesTransaction{
+		Identifier:       extensionData.Identifier,
+		Addresses:        extensionData.AddressUpdate,
+		Names:            extensionData.NameUpdate,
+		Services:         extensionData.ServiceUpdate,
+		NrOfMonths:       extensionData.NrOfMonths,
+		TransactionFee:   txData.MinerFees[0],
+		CoinInputs:       txData.CoinInputs,
+		OwnerFulfillment: extensionData.OwnerFulfillment,
+	}
+	if len(txData.CoinOutputs) == 1 {
+		// take refund coin output
+		tx.RefundCoinOutput = &txData.CoinOutputs[0]
+	}
+	return tx, nil
+}
+
+// TransactionData returns this BotRecordUpdateWithServicesTransaction
+// as regular tfchain transaction data.
+func (brutx *BotRecordUpdateWithServicesTransaction) TransactionData(oneCoin types.Currency) types.TransactionData {
+	txData := types.TransactionData{
+		CoinInputs: brutx.CoinInputs,
+		MinerFees:  []types.Currency{brutx.TransactionFee},
+		Extension: &BotRecordUpdateWithServicesTransactionExtension{
+			Identifier:       brutx.Identifier,
+			OwnerFulfillment: brutx.OwnerFulfillment,
+			AddressUpdate:    brutx.Addresses,
+			NameUpdate:       brutx.Names,
+			ServiceUpdate:    brutx.Services,
+			NrOfMonths:       brutx.NrOfMonths,
+		},
+	}
+	if brutx.RefundCoinOutput != nil {
+		txData.CoinOutputs = append(txData.CoinOutputs, *brutx.RefundCoinOutput)
+	}
+	return txData
+}
+
+// Transaction returns this BotRecordUpdateWithServicesTransaction
+// as regular tfchain transaction, using TransactionVersionBotRecordUpdateWithServices as the type.
+func (brutx *BotRecordUpdateWithServicesTransaction) Transaction(oneCoin types.Currency) types.Transaction {
+	txData := brutx.TransactionData(oneCoin)
+	return types.Transaction{
+		Version:     TransactionVersionBotRecordUpdateWithServices,
+		CoinInputs:  txData.CoinInputs,
+		CoinOutputs: txData.CoinOutputs,
+		MinerFees:   txData.MinerFees,
+		Extension:   txData.Extension,
+	}
+}
+
+// RequiredBotFee computes the required Bot Fee, that is to be applied as a required
+// additional fee on top of the regular required (minimum) Tx fee.
+func (brutx *BotRecordUpdateWithServicesTransaction) RequiredBotFee(params ConsensusParameters) types.Currency {
+	update := brutx.AsBotRecordUpdateTransaction()
+	return update.RequiredBotFee(params)
+}
+
+// AsBotRecordUpdateTransaction returns this Tx as a (signature-less) BotRecordUpdateTransaction,
+// such that the record update (and revert) logic can be shared between all versions.
+func (brutx *BotRecordUpdateWithServicesTransaction) AsBotRecordUpdateTransaction() BotRecordUpdateTransaction {
+	return BotRecordUpdateTransaction{
+		Identifier:       brutx.Identifier,
+		Addresses:        brutx.Addresses,
+		Names:            brutx.Names,
+		Services:         brutx.Services,
+		NrOfMonths:       brutx.NrOfMonths,
+		TransactionFee:   brutx.TransactionFee,
+		CoinInputs:       brutx.CoinInputs,
+		RefundCoinOutput: brutx.RefundCoinOutput,
+	}
+}
+
+// MarshalSia implements SiaMarshaler.MarshalSia,
+// alias of MarshalRivine for backwards-compatibility reasons.
+func (brutx BotRecordUpdateWithServicesTransaction) MarshalSia(w io.Writer) error {
+	return brutx.MarshalRivine(w)
+}
+
+// UnmarshalSia implements SiaUnmarshaler.UnmarshalSia,
+// alias of UnmarshalRivine for backwards-compatibility reasons.
+func (brutx *BotRecordUpdateWithServicesTransaction) UnmarshalSia(r io.Reader) error {
+	return brutx.UnmarshalRivine(r)
+}
+
+// MarshalRivine implements RivineMarshaler.MarshalRivine
+func (brutx BotRecordUpdateWithServicesTransaction) MarshalRivine(w io.Writer) error {
+	return rivbin.NewEncoder(w).EncodeAll(
+		brutx.Identifier,
+		brutx.Addresses.Add,
+		brutx.Addresses.Remove,
+		brutx.Names.Add,
+		brutx.Names.Remove,
+		brutx.Services,
+		brutx.NrOfMonths,
+		brutx.TransactionFee,
+		brutx.CoinInputs,
+		brutx.RefundCoinOutput,
+		brutx.OwnerFulfillment,
+	)
+}
+
+// UnmarshalRivine implements RivineUnmarshaler.UnmarshalRivine
+func (brutx *BotRecordUpdateWithServicesTransaction) UnmarshalRivine(r io.Reader) error {
+	return rivbin.NewDecoder(r).DecodeAll(
+		&brutx.Identifier,
+		&brutx.Addresses.Add,
+		&brutx.Addresses.Remove,
+		&brutx.Names.Add,
+		&brutx.Names.Remove,
+		&brutx.Services,
+		&brutx.NrOfMonths,
+		&brutx.TransactionFee,
+		&brutx.CoinInputs,
+		&brutx.RefundCoinOutput,
+		&brutx.OwnerFulfillment,
+	)
+}
+
+// MarshalSia implements SiaMarshaler.MarshalSia,
+// alias of MarshalRivine for backwards-compatibility reasons.
+func (update BotRecordServiceUpdate) MarshalSia(w io.Writer) error {
+	return update.MarshalRivine(w)
+}
+
+// UnmarshalSia implements SiaUnmarshaler.UnmarshalSia,
+// alias of UnmarshalRivine for backwards-compatibility reasons.
+func (update *BotRecordServiceUpdate) UnmarshalSia(r io.Reader) error {
+	return update.UnmarshalRivine(r)
+}
+
+// MarshalRivine implements RivineMarshaler.MarshalRivine
+//
+// The lengths of the services added and removed are combined in a single prefix byte,
+// followed by the added and removed services, one after the other.
+func (update BotRecordServiceUpdate) MarshalRivine(w io.Writer) error {
+	addLen, removeLen := len(update.Add), len(update.Remove)
+	if addLen > 15 || removeLen > 15 {
+		return ErrTooManyBotServices
+	}
+	enc := rivbin.NewEncoder(w)
+	err := enc.Encode(uint8(addLen) | (uint8(removeLen) << 4))
+	if err != nil {
+		return err
+	}
+	for _, service := range update.Add {
+		err = enc.Encode(service)
+		if err != nil {
+			return err
+		}
+	}
+	for _, service := range update.Remove {
+		err = enc.Encode(service)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnmarshalRivine implements RivineUnmarshaler.UnmarshalRivine
+func (update *BotRecordServiceUpdate) UnmarshalRivine(r io.Reader) error {
+	dec := rivbin.NewDecoder(r)
+	var pairLength uint8
+	err := dec.Decode(&pairLength)
+	if err != nil {
+		return err
+	}
+	addLen, removeLen := pairLength&15, pairLength>>4
+	update.Add, update.Remove = nil, nil
+	if addLen > 0 {
+		update.Add = make([]BotService, addLen)
+		for i := range update.Add {
+			err = dec.Decode(&update.Add[i])
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if removeLen > 0 {
+		update.Remove = make([]BotService, removeLen)
+		for i := range update.Remove {
+			err = dec.Decode(&update.Remove[i])
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type (
+	// BotUpdateRecordWithServicesTransactionController defines a tfchain-specific transaction controller,
+	// for a transaction type reserved at type 0x98. It allows the update of the record of an existing 3bot,
+	// including its services, authorized by a fulfillment of the owner condition of that 3bot.
+	BotUpdateRecordWithServicesTransactionController struct {
+		Registry            BotRecordReadRegistry
+		RegistryPoolAddress types.UnlockHash
+		OneCoin             types.Currency
+		// ConsensusParameterGetter is used to get the consensus parameters at a given block height,
+		// which define the bot fees to be paid to the registry pool address.
+		ConsensusParameterGetter ConsensusParameterGetter
+	}
+)
+
+var (
+	// ensure at compile time that BotUpdateRecordWithServicesTransactionController
+	// implements the desired interfaces
+	_ types.TransactionController                      = BotUpdateRecordWithServicesTransactionController{}
+	_ types.TransactionExtensionSigner                 = BotUpdateRecordWithServicesTransactionController{}
+	_ types.TransactionValidator                       = BotUpdateRecordWithServicesTransactionController{}
+	_ types.BlockStakeOutputValidator                  = BotUpdateRecordWithServicesTransactionController{}
+	_ types.TransactionSignatureHasher                 = BotUpdateRecordWithServicesTransactionController{}
+	_ types.TransactionIDEncoder                       = BotUpdateRecordWithServicesTransactionController{}
+	_ types.TransactionCustomMinerPayoutAtHeightGetter = BotUpdateRecordWithServicesTransactionController{}
+)
+
+// EncodeTransactionData implements TransactionController.EncodeTransactionData
+func (brutc BotUpdateRecordWithServicesTransactionController) EncodeTransactionData(w io.Writer, txData types.TransactionData) error {
+	brutx, err := BotRecordUpdateWithServicesTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a BotUpdateRecordWithServicesTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).Encode(brutx)
+}
+
+// DecodeTransactionData implements TransactionController.DecodeTransactionData
+func (brutc BotUpdateRecordWithServicesTransactionController) DecodeTransactionData(r io.Reader) (types.TransactionData, error) {
+	var brutx BotRecordUpdateWithServicesTransaction
+	err := rivbin.NewDecoder(r).Decode(&brutx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to binary-decode tx as a BotUpdateRecordWithServicesTx: %v", err)
+	}
+	// return bot record update tx as regular tfchain tx data
+	return brutx.TransactionData(brutc.OneCoin), nil
+}
+
+// JSONEncodeTransactionData implements TransactionController.JSONEncodeTransactionData
+func (brutc BotUpdateRecordWithServicesTransactionController) JSONEncodeTransactionData(txData types.TransactionData) ([]byte, error) {
+	brutx, err := BotRecordUpdateWithServicesTransactionFromTransactionData(txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert txData to a BotUpdateRecordWithServicesTx: %v", err)
+	}
+	return json.Marshal(brutx)
+}
+
+// JSONDecodeTransactionData implements TransactionController.JSONDecodeTransactionData
+func (brutc BotUpdateRecordWithServicesTransactionController) JSONDecodeTransactionData(data []byte) (types.TransactionData, error) {
+	var brutx BotRecordUpdateWithServicesTransaction
+	err := json.Unmarshal(data, &brutx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to json-decode tx as a BotUpdateRecordWithServicesTx: %v", err)
+	}
+	// return bot record update tx as regular tfchain tx data
+	return brutx.TransactionData(brutc.OneCoin), nil
+}
+
+// SignExtension implements TransactionExtensionSigner.SignExtension
+func (brutc BotUpdateRecordWithServicesTransactionController) SignExtension(extension interface{}, sign func(*types.UnlockFulfillmentProxy, types.UnlockConditionProxy, ...interface{}) error) (interface{}, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid BotRecordUpdateWithServicesTransactionExtension
+	brutxExtension, ok := extension.(*BotRecordUpdateWithServicesTransactionExtension)
+	if !ok {
+		return nil, errors.New("invalid extension data for a BotUpdateRecordWithServicesTx")
+	}
+
+	// get the owner condition and fulfillment for the bot, so we can sign
+	condition, fulfillment, err := getConditionAndFulfillmentForBotOwner(brutc.Registry, brutxExtension.Identifier, brutxExtension.OwnerFulfillment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare the signing of BotUpdateRecordWithServicesTx: %v", err)
+	}
+
+	// sign the fulfillment
+	err = sign(&fulfillment, condition, BotSignatureSpecifierSender)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign BotUpdateRecordWithServicesTx: %v", err)
+	}
+	brutxExtension.OwnerFulfillment = fulfillment
+
+	// and return the signed extension
+	return brutxExtension, nil
+}
+
+// ValidateTransaction implements TransactionValidator.ValidateTransaction
+func (brutc BotUpdateRecordWithServicesTransactionController) ValidateTransaction(t types.Transaction, ctx types.ValidationContext, constants types.TransactionValidationConstants) error {
+	// given the strict typing of 3bot transactions,
+	// it is guaranteed by its properties that it will always fit within a Block,
+	// and thus the TransactionFitsInABlock is not needed.
+
+	// get BotRecordUpdateWithServicesTx
+	brutx, err := BotRecordUpdateWithServicesTransactionFromTransaction(t)
+	if err != nil {
+		return fmt.Errorf("failed to use tx as a bot record update (with services) tx: %v", err)
+	}
+
+	// validate the miner fee
+	if brutx.TransactionFee.Cmp(constants.MinimumMinerFee) == -1 {
+		return types.ErrTooSmallMinerFee
+	}
+
+	// look up the record, using the given ID, to ensure it is registered
+	record, err := brutc.Registry.GetRecordForID(brutx.Identifier)
+	if err != nil {
+		return fmt.Errorf("bot cannot be updated: GetRecordForID(%v): %v", brutx.Identifier, err)
+	}
+
+	// validate the fulfillment of the owner condition of the to-be-updated bot
+	err = validateBotRecordFulfillment(t, record, brutx.OwnerFulfillment, ctx, BotSignatureSpecifierSender)
+	if err != nil {
+		return fmt.Errorf("failed to fulfill bot record update condition: %v", err)
+	}
+
+	// validate the update itself
+	update := brutx.AsBotRecordUpdateTransaction()
+	return validateBotRecordUpdate(brutc.Registry, brutc.ConsensusParameterGetter, &update, record, ctx)
+}
+
+// ValidateBlockStakeOutputs implements BlockStakeOutputValidator.ValidateBlockStakeOutputs
+func (brutc BotUpdateRecordWithServicesTransactionController) ValidateBlockStakeOutputs(t types.Transaction, ctx types.FundValidationContext, blockStakeInputs map[types.BlockStakeOutputID]types.BlockStakeOutput) (err error) {
+	return nil // always valid, no block stake inputs/outputs exist within a bot record update transaction
+}
+
+// SignatureHash implements TransactionSignatureHasher.SignatureHash
+func (brutc BotUpdateRecordWithServicesTransactionController) SignatureHash(t types.Transaction, extraObjects ...interface{}) (crypto.Hash, error) {
+	brutx, err := BotRecordUpdateWithServicesTransactionFromTransaction(t)
+	if err != nil {
+		return crypto.Hash{}, fmt.Errorf("failed to use tx as a BotRecordUpdateWithServicesTx: %v", err)
+	}
+	update := brutx.AsBotRecordUpdateTransaction()
+	return botRecordUpdateSignatureHash(t.Version, SpecifierBotRecordUpdateWithServicesTransaction, &update, extraObjects...), nil
+}
+
+// EncodeTransactionIDInput implements TransactionIDEncoder.EncodeTransactionIDInput
+func (brutc BotUpdateRecordWithServicesTransactionController) EncodeTransactionIDInput(w io.Writer, txData types.TransactionData) error {
+	brutx, err := BotRecordUpdateWithServicesTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a BotRecordUpdateWithServicesTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).EncodeAll(SpecifierBotRecordUpdateWithServicesTransaction, brutx)
+}
+
+// GetCustomMinerPayoutsAt implements TransactionCustomMinerPayoutAtHeightGetter.GetCustomMinerPayoutsAt
+func (brutc BotUpdateRecordWithServicesTransactionController) GetCustomMinerPayoutsAt(extension interface{}, height types.BlockHeight) ([]types.MinerPayout, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid BotRecordUpdateWithServicesTransactionExtension
+	brutxExtension, ok := extension.(*BotRecordUpdateWithServicesTransactionExtension)
+	if !ok {
+		return nil, errors.New("invalid extension data for a Bot RecordUpdate (with services) Transaction")
+	}
+	// the bot fees are defined by the consensus parameters that apply to the block at the given height
+	params, err := brutc.ConsensusParameterGetter.GetConsensusParametersAt(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the consensus parameters at block height %d: %v", height, err)
+	}
+	return []types.MinerPayout{
+		{
+			Value:      brutxExtension.RequiredBotFee(params),
+			UnlockHash: brutc.RegistryPoolAddress,
+		},
+	}, nil
+}
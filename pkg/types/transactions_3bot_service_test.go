@@ -0,0 +1,260 @@
+package types
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/threefoldfoundation/tfchain/pkg/config"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+// an example of a bot record update Tx with services, as documented in /doc/transactions.md
+const jsonEncodedBotRecordUpdateWithServicesTx = `{"version":152,"data":{"id":1,"addresses":{},"names":{},"services":{"add":[{"name":"http","protocol":"tcp","port":80,"version":"1.1"},{"name":"dns","protocol":"udp","port":53}]},"nrofmonths":0,"txfee":"1000000000","coininputs":[{"parentid":"c6b161d192d8095efd4d9946f7d154bf335f51fdfdeca4bb0cb990b25ffd7e95","fulfillment":{"type":1,"data":{"publickey":"ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780","signature":"2321a921b7d2e9318014f0c7b54a1644005aba2947dd6e69189dc228e73f440604413fbe2fec38c8ba35b0fd16a97f4c906f6bfea399ab91973beb94ffea8e0f"}}}],"refundcoinoutput":{"value":"99999999000000000","condition":{"type":1,"data":{"unlockhash":"01822fd5fefd2748972ea828a5c56044dec9a2b2275229ce5b212f926cd52fba015846451e4e46"}}},"ownerfulfillment":{"type":1,"data":{"publickey":"ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780","signature":"918c8f9a11c181723430d5ba926c810f0981e50607d8a200d7149f6134444893fdade1fb79d43b233aadc292c5b3f60627844de0cdffcde42e0fba8d81bb1c0b"}}}}`
+
+func TestBotRecordUpdateWithServicesTransactionBinaryEncodingAndID(t *testing.T) {
+	types.RegisterTransactionVersion(TransactionVersionBotRecordUpdateWithServices, BotUpdateRecordWithServicesTransactionController{})
+	defer types.RegisterTransactionVersion(TransactionVersionBotRecordUpdateWithServices, nil)
+
+	const hexEncodedTx = `980100000000000000028468747470500006312e3113646e73350000083b9aca0002c6b161d192d8095efd4d9946f7d154bf335f51fdfdeca4bb0cb990b25ffd7e9501c401d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780802321a921b7d2e9318014f0c7b54a1644005aba2947dd6e69189dc228e73f440604413fbe2fec38c8ba35b0fd16a97f4c906f6bfea399ab91973beb94ffea8e0f01100163457821ef3600014201822fd5fefd2748972ea828a5c56044dec9a2b2275229ce5b212f926cd52fba0101c401d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d778080918c8f9a11c181723430d5ba926c810f0981e50607d8a200d7149f6134444893fdade1fb79d43b233aadc292c5b3f60627844de0cdffcde42e0fba8d81bb1c0b`
+	var tx types.Transaction
+	err := json.Unmarshal([]byte(jsonEncodedBotRecordUpdateWithServicesTx), &tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := tx.ID()
+	b := siabin.Marshal(tx)
+	if output := hex.EncodeToString(b); output != hexEncodedTx {
+		t.Fatal(hexEncodedTx, "!=", output)
+	}
+
+	// go to bot record update Tx and back
+	brutx, err := BotRecordUpdateWithServicesTransactionFromTransaction(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oTx := brutx.Transaction(types.Currency{})
+	oID := oTx.ID()
+	oB := siabin.Marshal(oTx)
+	if id != oID {
+		t.Fatal(id, "!=", oID)
+	}
+	if !bytes.Equal(b, oB) {
+		t.Fatal(hex.EncodeToString(b), "!=", hex.EncodeToString(oB))
+	}
+
+	// binary decode it again, resulting in the same JSON-encoded transaction
+	var decodedTx types.Transaction
+	err = siabin.Unmarshal(oB, &decodedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err = json.Marshal(decodedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output := string(b); output != jsonEncodedBotRecordUpdateWithServicesTx {
+		t.Fatal(jsonEncodedBotRecordUpdateWithServicesTx, "!=", output)
+	}
+}
+
+func TestBotRecordServiceUpdateBinaryEncoding(t *testing.T) {
+	update := BotRecordServiceUpdate{
+		Add: []BotService{
+			{Name: "http", Protocol: BotServiceProtocolTCP, Port: 80},
+			{Name: "dns", Protocol: BotServiceProtocolUDP, Port: 53},
+		},
+		Remove: []BotService{
+			{Name: "http", Protocol: BotServiceProtocolTCP, Port: 443, Version: "2"},
+		},
+	}
+	expected := `12` + // 2 services added and 1 removed
+		`04687474705000` + // http/tcp:80
+		`13646e733500` + // dns/udp:53
+		`8468747470bb010232` // http/tcp:443@2
+	b := rivbin.Marshal(update)
+	if !bytes.Equal(b, mustDecodeHex(t, expected)) {
+		t.Fatalf("unexpected binary encoding of service update: %x", b)
+	}
+	var decoded BotRecordServiceUpdate
+	err := rivbin.Unmarshal(b, &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(update, decoded) {
+		t.Fatal(update, "!=", decoded)
+	}
+
+	// the old record update Tx cannot encode services
+	brutx := BotRecordUpdateTransaction{
+		Identifier:     1,
+		Services:       update,
+		TransactionFee: config.GetDevnetGenesis().MinimumTransactionFee,
+		CoinInputs: []types.CoinInput{{
+			ParentID:    types.CoinOutputID(hs("c6b161d192d8095efd4d9946f7d154bf335f51fdfdeca4bb0cb990b25ffd7e95")),
+			Fulfillment: types.NewFulfillment(types.NewSingleSignatureFulfillment(cryptoKeyPair.PublicKey)),
+		}},
+	}
+	err = rivbin.NewEncoder(new(bytes.Buffer)).Encode(brutx)
+	if err == nil {
+		t.Fatal("succeeded to binary-encode services as part of a bot record update (0x91) Tx")
+	}
+}
+
+func TestBotRecordUpdateWithServicesTransactionRequiredBotFee(t *testing.T) {
+	types.RegisterTransactionVersion(TransactionVersionBotRecordUpdateWithServices, BotUpdateRecordWithServicesTransactionController{})
+	defer types.RegisterTransactionVersion(TransactionVersionBotRecordUpdateWithServices, nil)
+
+	oneCoin := config.GetCurrencyUnits().OneCoin
+	params := GetDevnetGenesisConsensusParameters()
+	brutx := botRecordUpdateWithServicesTransactionFromJSON(t, jsonEncodedBotRecordUpdateWithServicesTx)
+	// 2 services are added, costing 10 TFT each
+	if fee := brutx.RequiredBotFee(params); !fee.Equals(oneCoin.Mul64(20)) {
+		t.Fatal("unexpected bot fee:", fee.String())
+	}
+	// removing services is free
+	brutx.Services.Remove = []BotService{{Name: "dns", Protocol: BotServiceProtocolUDP, Port: 53}}
+	if fee := brutx.RequiredBotFee(params); !fee.Equals(oneCoin.Mul64(20)) {
+		t.Fatal("unexpected bot fee:", fee.String())
+	}
+	// while services are paid on top of the regular update fees
+	brutx.NrOfMonths = 1
+	if fee := brutx.RequiredBotFee(params); !fee.Equals(oneCoin.Mul64(30)) {
+		t.Fatal("unexpected bot fee:", fee.String())
+	}
+}
+
+func TestBotRecordUpdateWithServicesTransactionSignAndValidate(t *testing.T) {
+	registry := &inMemoryBotRegistry{
+		idMapping: map[BotID]BotRecord{
+			1: botRecordFromJSON(t, `{
+	"id": 1,
+	"addresses": ["93.184.216.34"],
+	"names": ["example"],
+	"publickey": "`+cryptoKeyPair.PublicKey.String()+`",
+	"expiration": 1538484360
+}`),
+		},
+	}
+	types.RegisterTransactionVersion(TransactionVersionBotRecordUpdateWithServices, BotUpdateRecordWithServicesTransactionController{
+		Registry:                 registry,
+		ConsensusParameterGetter: staticConsensusParameterGetter(GetDevnetGenesisConsensusParameters()),
+	})
+	defer types.RegisterTransactionVersion(TransactionVersionBotRecordUpdateWithServices, nil)
+
+	validationCtx := types.ValidationContext{
+		Confirmed:   true,
+		BlockHeight: 100,
+		BlockTime:   1538484000,
+	}
+	chainConstants := config.GetDevnetGenesis()
+	validationConstants := types.TransactionValidationConstants{
+		BlockSizeLimit:         chainConstants.BlockSizeLimit,
+		ArbitraryDataSizeLimit: chainConstants.ArbitraryDataSizeLimit,
+		MinimumMinerFee:        chainConstants.MinimumTransactionFee,
+	}
+
+	// signs and validates the given update Tx
+	signAndValidate := func(brutx BotRecordUpdateWithServicesTransaction) error {
+		t.Helper()
+		brutx.OwnerFulfillment = types.UnlockFulfillmentProxy{}
+		tx := brutx.Transaction(types.Currency{})
+		err := tx.SignExtension(func(fulfillment *types.UnlockFulfillmentProxy, condition types.UnlockConditionProxy, extraObjects ...interface{}) error {
+			return fulfillment.Sign(types.FulfillmentSignContext{
+				ExtraObjects: extraObjects,
+				Transaction:  tx,
+				Key:          cryptoKeyPair.PrivateKey,
+			})
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx.ValidateTransaction(validationCtx, validationConstants)
+	}
+
+	// adding services to a bot is valid
+	brutx := botRecordUpdateWithServicesTransactionFromJSON(t, jsonEncodedBotRecordUpdateWithServicesTx)
+	err := signAndValidate(brutx)
+	if err != nil {
+		t.Fatal("failed to validate bot record update with services:", err)
+	}
+
+	// a service can only be removed if it exists
+	brutx.Services = BotRecordServiceUpdate{
+		Remove: []BotService{{Name: "http", Protocol: BotServiceProtocolTCP, Port: 80}},
+	}
+	err = signAndValidate(brutx)
+	if err == nil {
+		t.Fatal("succeeded to remove a service that doesn't exist")
+	}
+	// the in-memory registry shares the services slice with the validated record copies,
+	// hence the services are (re)defined prior to each validation that relies on them
+	setServices := func() {
+		record := registry.idMapping[1]
+		record.Services = BotServiceSortedSet{}
+		err := record.AddServices(BotService{Name: "http", Protocol: BotServiceProtocolTCP, Port: 80})
+		if err != nil {
+			t.Fatal(err)
+		}
+		registry.idMapping[1] = record
+	}
+	setServices()
+	err = signAndValidate(brutx)
+	if err != nil {
+		t.Fatal("failed to validate the removal of a bot service:", err)
+	}
+	setServices()
+
+	// a service can only be added once
+	brutx.Services = BotRecordServiceUpdate{
+		Add: []BotService{{Name: "http", Protocol: BotServiceProtocolTCP, Port: 8080}},
+	}
+	err = signAndValidate(brutx)
+	if err == nil {
+		t.Fatal("succeeded to add a service with the same name and protocol as an existing service")
+	}
+
+	// a bot cannot have more than 8 services
+	brutx.Services = BotRecordServiceUpdate{}
+	for _, name := range []string{"aaaaa", "bbbbb", "ccccc", "ddddd", "eeeee", "fffff", "ggggg", "hhhhh"} {
+		brutx.Services.Add = append(brutx.Services.Add, BotService{Name: name, Protocol: BotServiceProtocolTCP, Port: 1})
+	}
+	err = signAndValidate(brutx)
+	if err == nil {
+		t.Fatal("succeeded to add more than 8 services to a bot")
+	}
+
+	// invalid services are not accepted
+	brutx.Services = BotRecordServiceUpdate{
+		Add: []BotService{{Name: "http", Protocol: BotServiceProtocolTCP}},
+	}
+	err = signAndValidate(brutx)
+	if err == nil {
+		t.Fatal("succeeded to add a service without a port")
+	}
+
+	// a nop-update is invalid
+	brutx.Services = BotRecordServiceUpdate{}
+	err = signAndValidate(brutx)
+	if err == nil {
+		t.Fatal("succeeded to validate a bot record update without any update")
+	}
+}
+
+func botRecordUpdateWithServicesTransactionFromJSON(t *testing.T, str string) BotRecordUpdateWithServicesTransaction {
+	t.Helper()
+	var tx types.Transaction
+	err := json.Unmarshal([]byte(str), &tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	brutx, err := BotRecordUpdateWithServicesTransactionFromTransaction(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return brutx
+}
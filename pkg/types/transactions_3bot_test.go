@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/threefoldfoundation/tfchain/pkg/config"
+	"github.com/threefoldtech/rivine/crypto"
 	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
 	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
 	"github.com/threefoldtech/rivine/types"
@@ -19,8 +20,9 @@ import (
 func TestOutdatedBotRegisterationTransactionBinaryFormat(t *testing.T) {
 	// define tfchain-specific transaction versions
 	types.RegisterTransactionVersion(TransactionVersionBotRegistration, BotRegistrationTransactionController{
-		Registry: nil,
-		OneCoin:  config.GetCurrencyUnits().OneCoin,
+		Registry:                 nil,
+		OneCoin:                  config.GetCurrencyUnits().OneCoin,
+		ConsensusParameterGetter: staticConsensusParameterGetter(GetDevnetGenesisConsensusParameters()),
 	})
 	defer types.RegisterTransactionVersion(TransactionVersionBotRegistration, nil)
 
@@ -42,8 +44,9 @@ func TestOutdatedBotRegisterationTransactionBinaryFormat(t *testing.T) {
 func TestBotRegistrationTransactionBinaryEncodingAndID(t *testing.T) {
 	// define tfchain-specific transaction versions
 	types.RegisterTransactionVersion(TransactionVersionBotRegistration, BotRegistrationTransactionController{
-		Registry: nil,
-		OneCoin:  config.GetCurrencyUnits().OneCoin,
+		Registry:                 nil,
+		OneCoin:                  config.GetCurrencyUnits().OneCoin,
+		ConsensusParameterGetter: staticConsensusParameterGetter(GetDevnetGenesisConsensusParameters()),
 	})
 	defer types.RegisterTransactionVersion(TransactionVersionBotRegistration, nil)
 
@@ -72,11 +75,60 @@ func TestBotRegistrationTransactionBinaryEncodingAndID(t *testing.T) {
 	}
 }
 
+func TestBotRegistrationFeePayoutAtBlockHeight(t *testing.T) {
+	oneCoin := config.GetCurrencyUnits().OneCoin
+	// the registration fee is updated to 100 TFT from block height 11 onwards
+	getter := updatedConsensusParameterGetter{
+		Genesis:         GetDevnetGenesisConsensusParameters(),
+		EffectiveHeight: 11,
+		Update:          ConsensusParameterUpdate{Name: ConsensusParameterBotRegistrationFee, Value: oneCoin.Mul64(100)},
+	}
+	types.RegisterTransactionVersion(TransactionVersionBotRegistration, BotRegistrationTransactionController{
+		Registry:                 nil,
+		OneCoin:                  oneCoin,
+		ConsensusParameterGetter: getter,
+	})
+	defer types.RegisterTransactionVersion(TransactionVersionBotRegistration, nil)
+
+	const jsonEncodedTx = `{"version":144,"data":{"addresses":null,"names":["crazybot.foobar"],"nrofmonths":1,"txfee":"1000000000","coininputs":[{"parentid":"6678e3a75da2026da76753a60ac44f7e7737784015676b37cc2cdcf670dce2e5","fulfillment":{"type":1,"data":{"publickey":"ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780","signature":"cd07fbfd78be0edd1c9ca46bc18f91cde1ed05848083828c5d3848cd9671054527b630af72f7d95c0ddcd3a0f0c940eb8cfe4b085cb00efc8338b28f39155809"}}}],"refundcoinoutput":{"value":"99979897000000000","condition":{"type":1,"data":{"unlockhash":"017fda17489854109399aa8c1bfa6bdef40f93606744d95cc5055270d78b465e6acd263c96ab2b"}}},"identification":{"publickey":"ed25519:adc4090edbe28e3628f08a85d20b5055ea301cdb080d3b65a337a326e2e3556d","signature":"5211f813fb4e34ae348e2e746846bc72255512dc246ccafbb3bd3b916aac738bfe2737308d87cced4f9476be8715983cc6000e37f8e82e7b83f120776a358105"}}}`
+	var tx types.Transaction
+	err := json.Unmarshal([]byte(jsonEncodedTx), &tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the bot fee is defined by the consensus parameters effective at the height of the block,
+	// regardless of the consensus parameters that are active at the moment
+	for _, tc := range []struct {
+		Height types.BlockHeight
+		Fee    types.Currency
+	}{
+		{10, oneCoin.Mul64(90 + 10)},
+		{11, oneCoin.Mul64(100 + 10)},
+	} {
+		mps, err := tx.CustomMinerPayoutsAt(tc.Height)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(mps) != 1 {
+			t.Fatal("unexpected custom miner payouts:", mps)
+		}
+		if !mps[0].Value.Equals(tc.Fee) {
+			t.Error("unexpected bot fee at height", tc.Height, ":", mps[0].Value.String(), "!=", tc.Fee.String())
+		}
+		expectedSum := tc.Fee.Add(tx.MinerFees[0]).Add(tx.CoinOutputs[0].Value)
+		if sum := tx.CoinOutputSumAt(tc.Height); !sum.Equals(expectedSum) {
+			t.Error("unexpected coin output sum at height", tc.Height, ":", sum.String(), "!=", expectedSum.String())
+		}
+	}
+}
+
 func TestBotRegistrationExtractedFromBlockConsensusDB(t *testing.T) {
 	// define tfchain-specific transaction versions
 	types.RegisterTransactionVersion(TransactionVersionBotRegistration, BotRegistrationTransactionController{
-		Registry: nil,
-		OneCoin:  config.GetCurrencyUnits().OneCoin,
+		Registry:                 nil,
+		OneCoin:                  config.GetCurrencyUnits().OneCoin,
+		ConsensusParameterGetter: staticConsensusParameterGetter(GetDevnetGenesisConsensusParameters()),
 	})
 	defer types.RegisterTransactionVersion(TransactionVersionBotRegistration, nil)
 
@@ -116,8 +168,9 @@ var cryptoKeyPair = types.KeyPair{
 func TestBotRegistrationTransactionUniqueSignatures(t *testing.T) {
 	// define tfchain-specific transaction versions
 	types.RegisterTransactionVersion(TransactionVersionBotRegistration, BotRegistrationTransactionController{
-		Registry: nil,
-		OneCoin:  config.GetCurrencyUnits().OneCoin,
+		Registry:                 nil,
+		OneCoin:                  config.GetCurrencyUnits().OneCoin,
+		ConsensusParameterGetter: staticConsensusParameterGetter(GetDevnetGenesisConsensusParameters()),
 	})
 	defer types.RegisterTransactionVersion(TransactionVersionBotRegistration, nil)
 
@@ -244,7 +297,8 @@ func TestBotRecordUpdateTransactionUniqueSignatures(t *testing.T) {
 }`),
 			},
 		},
-		OneCoin: config.GetCurrencyUnits().OneCoin,
+		OneCoin:                  config.GetCurrencyUnits().OneCoin,
+		ConsensusParameterGetter: staticConsensusParameterGetter(GetDevnetGenesisConsensusParameters()),
 	})
 	defer types.RegisterTransactionVersion(TransactionVersionBotRecordUpdate, nil)
 
@@ -376,7 +430,8 @@ func TestBotNameTransferTransactionUniqueSignatures(t *testing.T) {
 }`),
 			},
 		},
-		OneCoin: config.GetCurrencyUnits().OneCoin,
+		OneCoin:                  config.GetCurrencyUnits().OneCoin,
+		ConsensusParameterGetter: staticConsensusParameterGetter(GetDevnetGenesisConsensusParameters()),
 	})
 	defer types.RegisterTransactionVersion(TransactionVersionBotNameTransfer, nil)
 
@@ -497,8 +552,276 @@ func TestBotNameTransferTransactionUniqueSignatures(t *testing.T) {
 	}
 }
 
+func TestBotKeyRotationTransactionSignAndValidate(t *testing.T) {
+	sk, pk := crypto.GenerateKeyPair()
+	newKeyPair := types.KeyPair{
+		PublicKey:  types.Ed25519PublicKey(pk),
+		PrivateKey: sk[:],
+	}
+
+	registry := &inMemoryBotRegistry{
+		idMapping: map[BotID]BotRecord{
+			1: botRecordFromJSON(t, `{
+	"id": 1,
+	"addresses": ["93.184.216.34"],
+	"names": ["example"],
+	"publickey": "`+cryptoKeyPair.PublicKey.String()+`",
+	"expiration": 1538484360
+}`),
+		},
+	}
+	// define tfchain-specific transaction versions
+	types.RegisterTransactionVersion(TransactionVersionBotKeyRotation, BotKeyRotationTransactionController{
+		Registry: registry,
+	})
+	defer types.RegisterTransactionVersion(TransactionVersionBotKeyRotation, nil)
+
+	var tx types.Transaction
+	err := tx.UnmarshalJSON([]byte(fmt.Sprintf(`{
+	"version": 147,
+	"data": {
+		"id": 1,
+		"ownerfulfillment": {
+			"type": 0
+		},
+		"newidentification": {
+			"publickey": "%[2]s",
+			"signature": ""
+		},
+		"txfee": "1000000000",
+		"coininputs": [
+			{
+				"parentid": "c6b161d192d8095efd4d9946f7d154bf335f51fdfdeca4bb0cb990b25ffd7e95",
+				"fulfillment": {
+					"type": 1,
+					"data": {
+						"publickey": "%[1]s",
+						"signature": ""
+					}
+				}
+			}
+		],
+		"refundcoinoutput": {
+			"value": "99999626000000000",
+			"condition": {
+				"type": 1,
+				"data": {
+					"unlockhash": "01822fd5fefd2748972ea828a5c56044dec9a2b2275229ce5b212f926cd52fba015846451e4e46"
+				}
+			}
+		}
+	}
+}`, cryptoKeyPair.PublicKey.String(), newKeyPair.PublicKey.String())))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// sign extension, using both the current (owner) and new key
+	err = tx.SignExtension(func(fulfillment *types.UnlockFulfillmentProxy, condition types.UnlockConditionProxy, extraObjects ...interface{}) error {
+		var key interface{}
+		switch uh := condition.UnlockHash(); {
+		case uh.Cmp(types.NewPubKeyUnlockHash(cryptoKeyPair.PublicKey)) == 0:
+			key = cryptoKeyPair.PrivateKey
+		case uh.Cmp(types.NewPubKeyUnlockHash(newKeyPair.PublicKey)) == 0:
+			key = newKeyPair.PrivateKey
+		default:
+			b, _ := json.Marshal(condition)
+			t.Fatalf("unexpected extension fulfill condition: %v", string(b))
+		}
+		return fulfillment.Sign(types.FulfillmentSignContext{
+			ExtraObjects: extraObjects,
+			Transaction:  tx,
+			Key:          key,
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ext := tx.Extension.(*BotKeyRotationTransactionExtension)
+	ownerFulfillment, ok := ext.OwnerFulfillment.Fulfillment.(*types.SingleSignatureFulfillment)
+	if !ok {
+		t.Fatalf("extension: unexpected owner fulfillment: %v", ext.OwnerFulfillment.FulfillmentType())
+	}
+	if len(ownerFulfillment.Signature) == 0 || len(ext.NewIdentification.Signature) == 0 {
+		t.Fatal("extension: signature is empty")
+	}
+	if bytes.Equal(ownerFulfillment.Signature, ext.NewIdentification.Signature) {
+		t.Fatal("extension: signatures are not unique")
+	}
+
+	validationCtx := types.ValidationContext{
+		Confirmed:   true,
+		BlockHeight: 1,
+		BlockTime:   1538484000,
+	}
+	chainConstants := config.GetDevnetGenesis()
+	validationConstants := types.TransactionValidationConstants{
+		BlockSizeLimit:         chainConstants.BlockSizeLimit,
+		ArbitraryDataSizeLimit: chainConstants.ArbitraryDataSizeLimit,
+		MinimumMinerFee:        chainConstants.MinimumTransactionFee,
+	}
+	// a correctly signed key rotation is valid
+	err = tx.ValidateTransaction(validationCtx, validationConstants)
+	if err != nil {
+		t.Fatal("failed to validate key rotation tx:", err)
+	}
+
+	// rotating to a key that is already used by another bot is invalid
+	registry.idMapping[2] = BotRecord{
+		ID:        2,
+		PublicKey: newKeyPair.PublicKey,
+	}
+	err = tx.ValidateTransaction(validationCtx, validationConstants)
+	if err != ErrBotKeyAlreadyRegistered {
+		t.Fatal("unexpected error while validating key rotation to a registered key:", err)
+	}
+	delete(registry.idMapping, 2)
+
+	// a key rotation signed by the current key only is invalid
+	newSignature := ext.NewIdentification.Signature
+	ext.NewIdentification.Signature = ownerFulfillment.Signature
+	err = tx.ValidateTransaction(validationCtx, validationConstants)
+	if err == nil {
+		t.Fatal("succeeded to validate key rotation tx not signed by the new key")
+	}
+	ext.NewIdentification.Signature = newSignature
+
+	// the owner cannot be changed without signing it
+	ext.Owner = &types.UnlockConditionProxy{
+		Condition: types.NewMultiSignatureCondition(types.UnlockHashSlice{
+			types.NewPubKeyUnlockHash(cryptoKeyPair.PublicKey),
+			types.NewPubKeyUnlockHash(newKeyPair.PublicKey),
+		}, 2),
+	}
+	err = tx.ValidateTransaction(validationCtx, validationConstants)
+	if err == nil {
+		t.Fatal("succeeded to validate key rotation tx with an unsigned owner")
+	}
+}
+
+func TestBotRecordUpdateWithFulfillmentTransactionMultiSigOwner(t *testing.T) {
+	keyPairs := make([]types.KeyPair, 3)
+	unlockHashes := make(types.UnlockHashSlice, 3)
+	for i := range keyPairs {
+		sk, pk := crypto.GenerateKeyPair()
+		keyPairs[i] = types.KeyPair{
+			PublicKey:  types.Ed25519PublicKey(pk),
+			PrivateKey: sk[:],
+		}
+		unlockHashes[i] = types.NewPubKeyUnlockHash(keyPairs[i].PublicKey)
+	}
+	record := botRecordFromJSON(t, `{
+	"id": 1,
+	"addresses": ["93.184.216.34"],
+	"names": ["example"],
+	"publickey": "`+keyPairs[0].PublicKey.String()+`",
+	"expiration": 1538484360
+}`)
+	record.Owner = &types.UnlockConditionProxy{
+		Condition: types.NewMultiSignatureCondition(unlockHashes, 2),
+	}
+	registry := &inMemoryBotRegistry{
+		idMapping: map[BotID]BotRecord{1: record},
+	}
+	// define tfchain-specific transaction versions
+	types.RegisterTransactionVersion(TransactionVersionBotRecordUpdate, BotUpdateRecordTransactionController{
+		Registry:                 registry,
+		ConsensusParameterGetter: staticConsensusParameterGetter(GetDevnetGenesisConsensusParameters()),
+	})
+	defer types.RegisterTransactionVersion(TransactionVersionBotRecordUpdate, nil)
+	types.RegisterTransactionVersion(TransactionVersionBotRecordUpdateWithFulfillment, BotUpdateRecordWithFulfillmentTransactionController{
+		Registry:                 registry,
+		ConsensusParameterGetter: staticConsensusParameterGetter(GetDevnetGenesisConsensusParameters()),
+	})
+	defer types.RegisterTransactionVersion(TransactionVersionBotRecordUpdateWithFulfillment, nil)
+
+	addr, err := NewNetworkAddress("example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	brutx := BotRecordUpdateWithFulfillmentTransaction{
+		Identifier:     1,
+		Addresses:      BotRecordAddressUpdate{Add: []NetworkAddress{addr}},
+		TransactionFee: config.GetDevnetGenesis().MinimumTransactionFee,
+		CoinInputs: []types.CoinInput{
+			{
+				ParentID:    types.CoinOutputID(hs("a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee563")),
+				Fulfillment: types.NewFulfillment(types.NewSingleSignatureFulfillment(cryptoKeyPair.PublicKey)),
+			},
+		},
+	}
+
+	validationCtx := types.ValidationContext{
+		Confirmed:   true,
+		BlockHeight: 1,
+		BlockTime:   1538484000,
+	}
+	chainConstants := config.GetDevnetGenesis()
+	validationConstants := types.TransactionValidationConstants{
+		BlockSizeLimit:         chainConstants.BlockSizeLimit,
+		ArbitraryDataSizeLimit: chainConstants.ArbitraryDataSizeLimit,
+		MinimumMinerFee:        chainConstants.MinimumTransactionFee,
+	}
+	signAndValidate := func(keyPairs ...types.KeyPair) error {
+		tx := brutx.Transaction(types.Currency{})
+		for _, keyPair := range keyPairs {
+			err := tx.SignExtension(func(fulfillment *types.UnlockFulfillmentProxy, condition types.UnlockConditionProxy, extraObjects ...interface{}) error {
+				if condition.ConditionType() != types.ConditionTypeMultiSignature {
+					b, _ := json.Marshal(condition)
+					t.Fatalf("unexpected extension fulfill condition: %v", string(b))
+				}
+				return fulfillment.Sign(types.FulfillmentSignContext{
+					ExtraObjects: extraObjects,
+					Transaction:  tx,
+					Key:          keyPair,
+				})
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return tx.ValidateTransaction(validationCtx, validationConstants)
+	}
+
+	// an update signed by 2 out of 3 owner keys is valid
+	err = signAndValidate(keyPairs[0], keyPairs[2])
+	if err != nil {
+		t.Fatal("failed to validate update signed by 2 out of 3 owner keys:", err)
+	}
+	// an update signed by only 1 out of 3 owner keys is invalid
+	err = signAndValidate(keyPairs[1])
+	if err == nil {
+		t.Fatal("succeeded to validate update signed by only 1 out of 3 owner keys")
+	}
+	// an update signed by a key not part of the owner condition is invalid
+	err = signAndValidate(keyPairs[1], cryptoKeyPair)
+	if err == nil {
+		t.Fatal("succeeded to validate update signed by a key not part of the owner condition")
+	}
+
+	// a (legacy) signature-based update is invalid for a bot owned by a condition
+	legacyTx := (&BotRecordUpdateTransaction{
+		Identifier:     1,
+		Addresses:      brutx.Addresses,
+		TransactionFee: brutx.TransactionFee,
+		CoinInputs:     brutx.CoinInputs,
+	}).Transaction(types.Currency{})
+	err = legacyTx.SignExtension(func(*types.UnlockFulfillmentProxy, types.UnlockConditionProxy, ...interface{}) error {
+		return nil
+	})
+	if err == nil {
+		t.Fatal("succeeded to sign (legacy) update for a bot owned by a condition")
+	}
+	err = legacyTx.ValidateTransaction(validationCtx, validationConstants)
+	if err == nil {
+		t.Fatal("succeeded to validate (legacy) update for a bot owned by a condition")
+	}
+}
+
 type inMemoryBotRegistry struct {
-	idMapping map[BotID]BotRecord
+	idMapping       map[BotID]BotRecord
+	delegateMapping map[string][]BotID
+	txMapping       map[BotID][]types.TransactionID
 }
 
 func botRecordFromJSON(t *testing.T, str string) BotRecord {
@@ -522,13 +845,27 @@ func (reg *inMemoryBotRegistry) GetRecordForID(id BotID) (*BotRecord, error) {
 }
 
 func (reg *inMemoryBotRegistry) GetRecordForKey(key types.PublicKey) (*BotRecord, error) {
-	panic("NOT IMPLEMENTED")
+	for _, record := range reg.idMapping {
+		if record.PublicKey.Algorithm == key.Algorithm && bytes.Equal(record.PublicKey.Key, key.Key) {
+			return &record, nil
+		}
+	}
+	return nil, ErrBotKeyNotFound
 }
 
 func (reg *inMemoryBotRegistry) GetRecordForName(name BotName) (*BotRecord, error) {
-	panic("NOT IMPLEMENTED")
+	for _, record := range reg.idMapping {
+		if record.Names.Contains(name) {
+			return &record, nil
+		}
+	}
+	return nil, ErrBotNameNotFound
+}
+
+func (reg *inMemoryBotRegistry) GetDelegatesForName(name BotName) ([]BotID, error) {
+	return reg.delegateMapping[name.String()], nil
 }
 
 func (reg *inMemoryBotRegistry) GetBotTransactionIdentifiers(id BotID) ([]types.TransactionID, error) {
-	panic("NOT IMPLEMENTED")
+	return reg.txMapping[id], nil
 }
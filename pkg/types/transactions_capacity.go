@@ -0,0 +1,458 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+const (
+	// TransactionVersionCapacityRegistration defines the Transaction version
+	// for a CapacityRegistrationTransaction, used to register the capacity of a node,
+	// linking that node to a farm.
+	TransactionVersionCapacityRegistration types.TransactionVersion = iota + 160
+)
+
+// These Specifiers are used internally when calculating a Transaction's ID.
+// See Rivine's Specifier for more details.
+var (
+	SpecifierCapacityRegistrationTransaction = types.Specifier{'c', 'a', 'p', 'a', 'c', 'i', 't', 'y', ' ', 'r', 'e', 'g', ' ', 't', 'x'}
+)
+
+// Specifiers used to ensure the farmer-signatures are unique within each Tx.
+var (
+	CapacityRegistrationSignatureSpecifier = [...]byte{'f', 'a', 'r', 'm', 'e', 'r'}
+)
+
+type (
+	// FarmerConditionGetter allows you to get the condition which has to be fulfilled,
+	// in order to act as an authorized farmer of a given farm.
+	//
+	// For the daemon this interface is implemented directly by the TransactionDB,
+	// while for a client this could come via the REST API from a tfchain daemon in a more indirect way.
+	FarmerConditionGetter interface {
+		// GetFarmerCondition returns the condition that has to be fulfilled
+		// in order to register capacity for the given farm.
+		GetFarmerCondition(farm FarmID) (types.UnlockConditionProxy, error)
+	}
+)
+
+// public capacity registry errors
+var (
+	ErrFarmNotFound = errors.New("farm not found")
+	ErrNodeNotFound = errors.New("node not found")
+)
+
+type (
+	// CapacityRegistrationTransaction defines the Transaction (with version 0xA0)
+	// used to register the capacity of a node, linking that node to a farm.
+	// It can only be created by an authorized farmer of that farm.
+	CapacityRegistrationTransaction struct {
+		// Farm the node (and its capacity) is registered for.
+		Farm FarmID `json:"farm"`
+		// Node defines the public key that identifies the node.
+		Node types.PublicKey `json:"node"`
+		// Capacity defines the entire capacity specification of the node.
+		Capacity CapacitySpecification `json:"capacity"`
+
+		// FarmerFulfillment defines the fulfillment which is used in order to
+		// fulfill the authorized-farmer condition of the farm.
+		FarmerFulfillment types.UnlockFulfillmentProxy `json:"farmerfulfillment"`
+
+		// TransactionFee defines the regular Tx fee.
+		TransactionFee types.Currency `json:"txfee"`
+
+		// CoinInputs are only used for the required fees,
+		// at least one CoinInput is required.
+		CoinInputs []types.CoinInput `json:"coininputs"`
+		// RefundCoinOutput is an optional coin output that can be used
+		// to refund coins paid as inputs for the required fees.
+		RefundCoinOutput *types.CoinOutput `json:"refundcoinoutput,omitempty"`
+
+		// ArbitraryData can be used for any purpose, and is ignored by the daemon.
+		ArbitraryData []byte `json:"arbitrarydata,omitempty"`
+	}
+	// CapacityRegistrationTransactionExtension defines the CapacityRegistrationTx Extension Data
+	CapacityRegistrationTransactionExtension struct {
+		Farm              FarmID
+		Node              types.PublicKey
+		Capacity          CapacitySpecification
+		FarmerFulfillment types.UnlockFulfillmentProxy
+	}
+)
+
+// CapacityRegistrationTransactionFromTransaction creates a CapacityRegistrationTransaction,
+// using a regular in-memory tfchain transaction.
+//
+// Past the (tx) Version validation it piggy-backs onto the
+// `CapacityRegistrationTransactionFromTransactionData` constructor.
+func CapacityRegistrationTransactionFromTransaction(tx types.Transaction) (CapacityRegistrationTransaction, error) {
+	if tx.Version != TransactionVersionCapacityRegistration {
+		return CapacityRegistrationTransaction{}, fmt.Errorf(
+			"a capacity registration transaction requires tx version %d",
+			TransactionVersionCapacityRegistration)
+	}
+	return CapacityRegistrationTransactionFromTransactionData(types.TransactionData{
+		CoinInputs:        tx.CoinInputs,
+		CoinOutputs:       tx.CoinOutputs,
+		BlockStakeInputs:  tx.BlockStakeInputs,
+		BlockStakeOutputs: tx.BlockStakeOutputs,
+		MinerFees:         tx.MinerFees,
+		ArbitraryData:     tx.ArbitraryData,
+		Extension:         tx.Extension,
+	})
+}
+
+// CapacityRegistrationTransactionFromTransactionData creates a CapacityRegistrationTransaction,
+// using the TransactionData from a regular in-memory tfchain transaction.
+func CapacityRegistrationTransactionFromTransactionData(txData types.TransactionData) (CapacityRegistrationTransaction, error) {
+	// validate the Transaction Data
+
+	// at least one coin input as well as one miner fee is required
+	if len(txData.CoinInputs) == 0 || len(txData.MinerFees) != 1 {
+		return CapacityRegistrationTransaction{}, errors.New("at least one coin input and exactly one miner fee is required for a Capacity Registration Transaction")
+	}
+	// no block stake inputs or block stake outputs are allowed
+	if len(txData.BlockStakeInputs) != 0 || len(txData.BlockStakeOutputs) != 0 {
+		return CapacityRegistrationTransaction{}, errors.New("no block stake inputs/outputs are allowed in a Capacity Registration Transaction")
+	}
+	// validate that the coin outputs is within the expected range
+	if len(txData.CoinOutputs) > 1 {
+		return CapacityRegistrationTransaction{}, errors.New("a Capacity Registration Transaction can only have one coin output")
+	}
+
+	// (tx) extension (data) is expected to be a pointer to a valid CapacityRegistrationTransactionExtension,
+	// which contains all the properties unique to a capacity registration Tx
+	extensionData, ok := txData.Extension.(*CapacityRegistrationTransactionExtension)
+	if !ok {
+		return CapacityRegistrationTransaction{}, errors.New("invalid extension data for a Capacity Registration Transaction")
+	}
+
+	// create the CapacityRegistrationTransaction and return it,
+	// further validation will/has-to be done using the Transaction Type, if required
+	tx := CapacityRegistrationTransaction{
+		Farm:              extensionData.Farm,
+		Node:              extensionData.Node,
+		Capacity:          extensionData.Capacity,
+		FarmerFulfillment: extensionData.FarmerFulfillment,
+		TransactionFee:    txData.MinerFees[0],
+		CoinInputs:        txData.CoinInputs,
+		ArbitraryData:     txData.ArbitraryData,
+	}
+	if len(txData.CoinOutputs) == 1 {
+		// take refund coin output if it exists
+		tx.RefundCoinOutput = &txData.CoinOutputs[0]
+	}
+	return tx, nil
+}
+
+// TransactionData returns this CapacityRegistrationTransaction
+// as regular tfchain transaction data.
+func (crtx *CapacityRegistrationTransaction) TransactionData() types.TransactionData {
+	txData := types.TransactionData{
+		CoinInputs:    crtx.CoinInputs,
+		MinerFees:     []types.Currency{crtx.TransactionFee},
+		ArbitraryData: crtx.ArbitraryData,
+		Extension: &CapacityRegistrationTransactionExtension{
+			Farm:              crtx.Farm,
+			Node:              crtx.Node,
+			Capacity:          crtx.Capacity,
+			FarmerFulfillment: crtx.FarmerFulfillment,
+		},
+	}
+	if crtx.RefundCoinOutput != nil {
+		txData.CoinOutputs = append(txData.CoinOutputs, *crtx.RefundCoinOutput)
+	}
+	return txData
+}
+
+// Transaction returns this CapacityRegistrationTransaction
+// as regular tfchain transaction, using TransactionVersionCapacityRegistration as the type.
+func (crtx *CapacityRegistrationTransaction) Transaction() types.Transaction {
+	tx := types.Transaction{
+		Version:       TransactionVersionCapacityRegistration,
+		CoinInputs:    crtx.CoinInputs,
+		MinerFees:     []types.Currency{crtx.TransactionFee},
+		ArbitraryData: crtx.ArbitraryData,
+		Extension: &CapacityRegistrationTransactionExtension{
+			Farm:              crtx.Farm,
+			Node:              crtx.Node,
+			Capacity:          crtx.Capacity,
+			FarmerFulfillment: crtx.FarmerFulfillment,
+		},
+	}
+	if crtx.RefundCoinOutput != nil {
+		tx.CoinOutputs = append(tx.CoinOutputs, *crtx.RefundCoinOutput)
+	}
+	return tx
+}
+
+// MarshalSia implements SiaMarshaler.MarshalSia,
+// alias of MarshalRivine for backwards-compatibility reasons.
+func (crtx CapacityRegistrationTransaction) MarshalSia(w io.Writer) error {
+	return crtx.MarshalRivine(w)
+}
+
+// UnmarshalSia implements SiaUnmarshaler.UnmarshalSia,
+// alias of UnmarshalRivine for backwards-compatibility reasons.
+func (crtx *CapacityRegistrationTransaction) UnmarshalSia(r io.Reader) error {
+	return crtx.UnmarshalRivine(r)
+}
+
+// MarshalRivine implements RivineMarshaler.MarshalRivine
+func (crtx CapacityRegistrationTransaction) MarshalRivine(w io.Writer) error {
+	return rivbin.NewEncoder(w).EncodeAll(
+		crtx.Farm,
+		crtx.Node,
+		crtx.Capacity,
+		crtx.FarmerFulfillment,
+		crtx.TransactionFee,
+		crtx.CoinInputs,
+		crtx.RefundCoinOutput,
+		crtx.ArbitraryData,
+	)
+}
+
+// UnmarshalRivine implements RivineUnmarshaler.UnmarshalRivine
+func (crtx *CapacityRegistrationTransaction) UnmarshalRivine(r io.Reader) error {
+	return rivbin.NewDecoder(r).DecodeAll(
+		&crtx.Farm,
+		&crtx.Node,
+		&crtx.Capacity,
+		&crtx.FarmerFulfillment,
+		&crtx.TransactionFee,
+		&crtx.CoinInputs,
+		&crtx.RefundCoinOutput,
+		&crtx.ArbitraryData,
+	)
+}
+
+type (
+	// CapacityRegistrationTransactionController defines a tfchain-specific transaction controller,
+	// for a transaction type reserved at type 0xA0. It allows the registration of the capacity of a node.
+	CapacityRegistrationTransactionController struct {
+		// FarmerConditionGetter is used to get the authorized-farmer condition of a farm.
+		//
+		// The found condition defines the condition that has to be fulfilled
+		// in order to register capacity for that farm.
+		FarmerConditionGetter FarmerConditionGetter
+	}
+)
+
+var (
+	// ensure at compile time that CapacityRegistrationTransactionController
+	// implements the desired interfaces
+	_ types.TransactionController      = CapacityRegistrationTransactionController{}
+	_ types.TransactionValidator       = CapacityRegistrationTransactionController{}
+	_ types.BlockStakeOutputValidator  = CapacityRegistrationTransactionController{}
+	_ types.TransactionSignatureHasher = CapacityRegistrationTransactionController{}
+	_ types.TransactionExtensionSigner = CapacityRegistrationTransactionController{}
+	_ types.TransactionIDEncoder       = CapacityRegistrationTransactionController{}
+)
+
+// EncodeTransactionData implements TransactionController.EncodeTransactionData
+func (crtc CapacityRegistrationTransactionController) EncodeTransactionData(w io.Writer, txData types.TransactionData) error {
+	crtx, err := CapacityRegistrationTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a CapacityRegistrationTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).Encode(crtx)
+}
+
+// DecodeTransactionData implements TransactionController.DecodeTransactionData
+func (crtc CapacityRegistrationTransactionController) DecodeTransactionData(r io.Reader) (types.TransactionData, error) {
+	var crtx CapacityRegistrationTransaction
+	err := rivbin.NewDecoder(r).Decode(&crtx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to binary-decode tx as a CapacityRegistrationTx: %v", err)
+	}
+	// return capacity registration tx as regular tfchain tx data
+	return crtx.TransactionData(), nil
+}
+
+// JSONEncodeTransactionData implements TransactionController.JSONEncodeTransactionData
+func (crtc CapacityRegistrationTransactionController) JSONEncodeTransactionData(txData types.TransactionData) ([]byte, error) {
+	crtx, err := CapacityRegistrationTransactionFromTransactionData(txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert txData to a CapacityRegistrationTx: %v", err)
+	}
+	return json.Marshal(crtx)
+}
+
+// JSONDecodeTransactionData implements TransactionController.JSONDecodeTransactionData
+func (crtc CapacityRegistrationTransactionController) JSONDecodeTransactionData(data []byte) (types.TransactionData, error) {
+	var crtx CapacityRegistrationTransaction
+	err := json.Unmarshal(data, &crtx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to json-decode tx as a CapacityRegistrationTx: %v", err)
+	}
+	// return capacity registration tx as regular tfchain tx data
+	return crtx.TransactionData(), nil
+}
+
+// ValidateTransaction implements TransactionValidator.ValidateTransaction
+func (crtc CapacityRegistrationTransactionController) ValidateTransaction(t types.Transaction, ctx types.ValidationContext, constants types.TransactionValidationConstants) error {
+	// check tx fits within a block
+	err := types.TransactionFitsInABlock(t, constants.BlockSizeLimit)
+	if err != nil {
+		return err
+	}
+
+	// get CapacityRegistration Tx
+	crtx, err := CapacityRegistrationTransactionFromTransaction(t)
+	if err != nil {
+		return fmt.Errorf("failed to use tx as a capacity registration tx: %v", err)
+	}
+
+	// validate the farm, node and capacity
+	if crtx.Farm < MinFarmID {
+		return fmt.Errorf("invalid capacity registration tx: farmID has to be at least %d", MinFarmID)
+	}
+	if crtx.Node.Algorithm != types.SignatureAlgoEd25519 || len(crtx.Node.Key) != crypto.PublicKeySize {
+		return errors.New("invalid capacity registration tx: node has to be identified by an ed25519 public key")
+	}
+	err = crtx.Capacity.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid capacity registration tx: %v", err)
+	}
+
+	// get the authorized-farmer condition of the farm
+	farmerCondition, err := crtc.FarmerConditionGetter.GetFarmerCondition(crtx.Farm)
+	if err != nil {
+		return fmt.Errorf("failed to get the farmer condition of farm %d: %v", crtx.Farm, err)
+	}
+	// check if FarmerFulfillment fulfills the farmer condition
+	err = farmerCondition.Fulfill(crtx.FarmerFulfillment, types.FulfillContext{
+		ExtraObjects: []interface{}{CapacityRegistrationSignatureSpecifier},
+		BlockHeight:  ctx.BlockHeight,
+		BlockTime:    ctx.BlockTime,
+		Transaction:  t,
+	})
+	if err != nil {
+		return fmt.Errorf("unauthorized capacity registration tx: failed to fulfill farmer condition: %v", err)
+	}
+
+	// validate the miner fee
+	if crtx.TransactionFee.Cmp(constants.MinimumMinerFee) < 0 {
+		return types.ErrTooSmallMinerFee
+	}
+
+	// validate the arbitrary data
+	err = types.ArbitraryDataFits(crtx.ArbitraryData, constants.ArbitraryDataSizeLimit)
+	if err != nil {
+		return err
+	}
+
+	// prevent double spending
+	spendCoins := make(map[types.CoinOutputID]struct{})
+	for _, ci := range crtx.CoinInputs {
+		if _, found := spendCoins[ci.ParentID]; found {
+			return types.ErrDoubleSpend
+		}
+		spendCoins[ci.ParentID] = struct{}{}
+	}
+
+	// check if optional coin output is using standard condition
+	if crtx.RefundCoinOutput != nil {
+		err = crtx.RefundCoinOutput.Condition.IsStandardCondition(ctx)
+		if err != nil {
+			return err
+		}
+		// ensure the value is not 0
+		if crtx.RefundCoinOutput.Value.IsZero() {
+			return types.ErrZeroOutput
+		}
+	}
+	// check if all fulfillments are standard
+	for _, sci := range crtx.CoinInputs {
+		err = sci.Fulfillment.IsStandardFulfillment(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Tx is valid
+	return nil
+}
+
+// ValidateCoinOutputs is not implemented here for CapacityRegistrationTransactionController,
+// instead we can rely on the default ValidateCoinOutputs logic provided by Rivine.
+
+// ValidateBlockStakeOutputs implements BlockStakeOutputValidator.ValidateBlockStakeOutputs
+func (crtc CapacityRegistrationTransactionController) ValidateBlockStakeOutputs(t types.Transaction, ctx types.FundValidationContext, blockStakeInputs map[types.BlockStakeOutputID]types.BlockStakeOutput) (err error) {
+	return nil // always valid, no block stake inputs/outputs exist within a capacity registration transaction
+}
+
+// SignatureHash implements TransactionSignatureHasher.SignatureHash
+func (crtc CapacityRegistrationTransactionController) SignatureHash(t types.Transaction, extraObjects ...interface{}) (crypto.Hash, error) {
+	crtx, err := CapacityRegistrationTransactionFromTransaction(t)
+	if err != nil {
+		return crypto.Hash{}, fmt.Errorf("failed to use tx as a CapacityRegistrationTx: %v", err)
+	}
+
+	h := crypto.NewHash()
+	enc := rivbin.NewEncoder(h)
+
+	enc.EncodeAll(
+		t.Version,
+		SpecifierCapacityRegistrationTransaction,
+		crtx.Farm,
+		crtx.Node,
+		crtx.Capacity,
+	)
+
+	if len(extraObjects) > 0 {
+		enc.EncodeAll(extraObjects...)
+	}
+
+	enc.Encode(len(crtx.CoinInputs))
+	for _, ci := range crtx.CoinInputs {
+		enc.Encode(ci.ParentID)
+	}
+
+	enc.EncodeAll(
+		crtx.TransactionFee,
+		crtx.RefundCoinOutput,
+		crtx.ArbitraryData,
+	)
+
+	var hash crypto.Hash
+	h.Sum(hash[:0])
+	return hash, nil
+}
+
+// SignExtension implements TransactionExtensionSigner.SignExtension
+func (crtc CapacityRegistrationTransactionController) SignExtension(extension interface{}, sign func(*types.UnlockFulfillmentProxy, types.UnlockConditionProxy, ...interface{}) error) (interface{}, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid CapacityRegistrationTransactionExtension
+	crtxExtension, ok := extension.(*CapacityRegistrationTransactionExtension)
+	if !ok {
+		return nil, errors.New("invalid extension data for a Capacity Registration Transaction")
+	}
+
+	// get the farmer condition and use it to sign
+	farmerCondition, err := crtc.FarmerConditionGetter.GetFarmerCondition(crtxExtension.Farm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the farmer condition of farm %d: %v", crtxExtension.Farm, err)
+	}
+	err = sign(&crtxExtension.FarmerFulfillment, farmerCondition, CapacityRegistrationSignatureSpecifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign farmer fulfillment of CapacityRegistrationTx: %v", err)
+	}
+	return crtxExtension, nil
+}
+
+// EncodeTransactionIDInput implements TransactionIDEncoder.EncodeTransactionIDInput
+func (crtc CapacityRegistrationTransactionController) EncodeTransactionIDInput(w io.Writer, txData types.TransactionData) error {
+	crtx, err := CapacityRegistrationTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a CapacityRegistrationTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).EncodeAll(SpecifierCapacityRegistrationTransaction, crtx)
+}
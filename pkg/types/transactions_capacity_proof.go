@@ -0,0 +1,469 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+const (
+	// TransactionVersionCapacityProof defines the Transaction version
+	// for a CapacityProofTransaction, used by a node to respond to a capacity challenge.
+	TransactionVersionCapacityProof types.TransactionVersion = iota + 163
+)
+
+// These Specifiers are used internally when calculating a Transaction's ID.
+// See Rivine's Specifier for more details.
+var (
+	SpecifierCapacityProofTransaction = types.Specifier{'c', 'a', 'p', 'a', 'c', 'i', 't', 'y', ' ', 'p', 'r', 'o', 'o', 'f', ' ', 't'}
+)
+
+// Specifiers used to ensure the node-signatures are unique within each Tx.
+var (
+	CapacityProofSignatureSpecifier = [...]byte{'n', 'o', 'd', 'e'}
+)
+
+type (
+	// CapacityProofReadRegistry defines the public READ API expected from
+	// the registry used to validate capacity proof transactions.
+	//
+	// For the daemon this interface is implemented directly by the TransactionDB,
+	// while for a client this could come via the REST API from a tfchain daemon in a more indirect way.
+	CapacityProofReadRegistry interface {
+		// GetCapacityChallenge returns the challenge for the given node,
+		// derived from the challenge block at the given height.
+		GetCapacityChallenge(height types.BlockHeight, node types.PublicKey) (CapacityChallenge, error)
+		// GetCapacityRecordForNode returns the latest capacity record registered for the given node.
+		GetCapacityRecordForNode(node types.PublicKey) (*CapacityRecord, error)
+		// GetCapacityProofResultForNode returns the result of the given node
+		// for the challenge of the challenge block at the given height.
+		GetCapacityProofResultForNode(node types.PublicKey, height types.BlockHeight) (*CapacityProofResult, error)
+	}
+)
+
+type (
+	// CapacityProofTransaction defines the Transaction (with version 0xA3)
+	// used by a node to respond to a capacity challenge, proving it is still online,
+	// reporting the capacity it currently provides.
+	CapacityProofTransaction struct {
+		// Node defines the public key that identifies the node.
+		Node types.PublicKey `json:"node"`
+		// ChallengeHeight defines the height of the challenge block
+		// of the challenge this transaction responds to.
+		ChallengeHeight types.BlockHeight `json:"challengeheight"`
+		// Capacity defines the capacity currently provided by the node,
+		// in order to pass the challenge it has to cover the registered capacity.
+		Capacity CapacitySpecification `json:"capacity"`
+		// NodeFulfillment defines the fulfillment which is used in order to prove
+		// the response is created by the node, signing the seed of the challenge.
+		NodeFulfillment types.UnlockFulfillmentProxy `json:"nodefulfillment"`
+
+		// TransactionFee defines the regular Tx fee.
+		TransactionFee types.Currency `json:"txfee"`
+
+		// CoinInputs are only used for the required fees,
+		// at least one CoinInput is required.
+		CoinInputs []types.CoinInput `json:"coininputs"`
+		// RefundCoinOutput is an optional coin output that can be used
+		// to refund coins paid as inputs for the required fees.
+		RefundCoinOutput *types.CoinOutput `json:"refundcoinoutput,omitempty"`
+	}
+	// CapacityProofTransactionExtension defines the CapacityProofTransaction Extension Data
+	CapacityProofTransactionExtension struct {
+		Node            types.PublicKey
+		ChallengeHeight types.BlockHeight
+		Capacity        CapacitySpecification
+		NodeFulfillment types.UnlockFulfillmentProxy
+	}
+)
+
+// CapacityProofTransactionFromTransaction creates a CapacityProofTransaction,
+// using a regular in-memory tfchain transaction.
+//
+// Past the (tx) Version validation it piggy-backs onto the
+// `CapacityProofTransactionFromTransactionData` constructor.
+func CapacityProofTransactionFromTransaction(tx types.Transaction) (CapacityProofTransaction, error) {
+	if tx.Version != TransactionVersionCapacityProof {
+		return CapacityProofTransaction{}, fmt.Errorf(
+			"a capacity proof transaction requires tx version %d",
+			TransactionVersionCapacityProof)
+	}
+	return CapacityProofTransactionFromTransactionData(types.TransactionData{
+		CoinInputs:        tx.CoinInputs,
+		CoinOutputs:       tx.CoinOutputs,
+		BlockStakeInputs:  tx.BlockStakeInputs,
+		BlockStakeOutputs: tx.BlockStakeOutputs,
+		MinerFees:         tx.MinerFees,
+		ArbitraryData:     tx.ArbitraryData,
+		Extension:         tx.Extension,
+	})
+}
+
+// CapacityProofTransactionFromTransactionData creates a CapacityProofTransaction,
+// using the TransactionData from a regular in-memory tfchain transaction.
+func CapacityProofTransactionFromTransactionData(txData types.TransactionData) (CapacityProofTransaction, error) {
+	// validate the Transaction Data
+
+	// at least one coin input as well as one miner fee is required
+	if len(txData.CoinInputs) == 0 || len(txData.MinerFees) != 1 {
+		return CapacityProofTransaction{}, errors.New("at least one coin input and exactly one miner fee is required for a Capacity Proof Transaction")
+	}
+	// no block stake inputs or block stake outputs are allowed
+	if len(txData.BlockStakeInputs) != 0 || len(txData.BlockStakeOutputs) != 0 {
+		return CapacityProofTransaction{}, errors.New("no block stake inputs/outputs are allowed in a Capacity Proof Transaction")
+	}
+	// no arbitrary data is allowed
+	if len(txData.ArbitraryData) > 0 {
+		return CapacityProofTransaction{}, errors.New("no arbitrary data is allowed in a Capacity Proof Transaction")
+	}
+	// validate that the coin outputs is within the expected range
+	if len(txData.CoinOutputs) > 1 {
+		return CapacityProofTransaction{}, errors.New("a Capacity Proof Transaction can only have one coin output")
+	}
+
+	// (tx) extension (data) is expected to be a pointer to a valid CapacityProofTransactionExtension,
+	// which contains all the properties unique to a capacity proof Tx
+	extensionData, ok := txData.Extension.(*CapacityProofTransactionExtension)
+	if !ok {
+		return CapacityProofTransaction{}, errors.New("invalid extension data for a Capacity Proof Transaction")
+	}
+
+	// create the CapacityProofTransaction and return it,
+	// further validation will/has-to be done using the Transaction Type, if required
+	tx := CapacityProofTransaction{
+		Node:            extensionData.Node,
+		ChallengeHeight: extensionData.ChallengeHeight,
+		Capacity:        extensionData.Capacity,
+		NodeFulfillment: extensionData.NodeFulfillment,
+		TransactionFee:  txData.MinerFees[0],
+		CoinInputs:      txData.CoinInputs,
+	}
+	if len(txData.CoinOutputs) == 1 {
+		// take refund coin output if it exists
+		tx.RefundCoinOutput = &txData.CoinOutputs[0]
+	}
+	return tx, nil
+}
+
+// TransactionData returns this CapacityProofTransaction
+// as regular tfchain transaction data.
+func (cptx *CapacityProofTransaction) TransactionData() types.TransactionData {
+	txData := types.TransactionData{
+		CoinInputs: cptx.CoinInputs,
+		MinerFees:  []types.Currency{cptx.TransactionFee},
+		Extension: &CapacityProofTransactionExtension{
+			Node:            cptx.Node,
+			ChallengeHeight: cptx.ChallengeHeight,
+			Capacity:        cptx.Capacity,
+			NodeFulfillment: cptx.NodeFulfillment,
+		},
+	}
+	if cptx.RefundCoinOutput != nil {
+		txData.CoinOutputs = append(txData.CoinOutputs, *cptx.RefundCoinOutput)
+	}
+	return txData
+}
+
+// Transaction returns this CapacityProofTransaction
+// as regular tfchain transaction, using TransactionVersionCapacityProof as the type.
+func (cptx *CapacityProofTransaction) Transaction() types.Transaction {
+	tx := types.Transaction{
+		Version:    TransactionVersionCapacityProof,
+		CoinInputs: cptx.CoinInputs,
+		MinerFees:  []types.Currency{cptx.TransactionFee},
+		Extension: &CapacityProofTransactionExtension{
+			Node:            cptx.Node,
+			ChallengeHeight: cptx.ChallengeHeight,
+			Capacity:        cptx.Capacity,
+			NodeFulfillment: cptx.NodeFulfillment,
+		},
+	}
+	if cptx.RefundCoinOutput != nil {
+		tx.CoinOutputs = append(tx.CoinOutputs, *cptx.RefundCoinOutput)
+	}
+	return tx
+}
+
+// MarshalSia implements SiaMarshaler.MarshalSia,
+// alias of MarshalRivine for backwards-compatibility reasons.
+func (cptx CapacityProofTransaction) MarshalSia(w io.Writer) error {
+	return cptx.MarshalRivine(w)
+}
+
+// UnmarshalSia implements SiaUnmarshaler.UnmarshalSia,
+// alias of UnmarshalRivine for backwards-compatibility reasons.
+func (cptx *CapacityProofTransaction) UnmarshalSia(r io.Reader) error {
+	return cptx.UnmarshalRivine(r)
+}
+
+// MarshalRivine implements RivineMarshaler.MarshalRivine
+func (cptx CapacityProofTransaction) MarshalRivine(w io.Writer) error {
+	return rivbin.NewEncoder(w).EncodeAll(
+		cptx.Node,
+		cptx.ChallengeHeight,
+		cptx.Capacity,
+		cptx.NodeFulfillment,
+		cptx.TransactionFee,
+		cptx.CoinInputs,
+		cptx.RefundCoinOutput,
+	)
+}
+
+// UnmarshalRivine implements RivineUnmarshaler.UnmarshalRivine
+func (cptx *CapacityProofTransaction) UnmarshalRivine(r io.Reader) error {
+	return rivbin.NewDecoder(r).DecodeAll(
+		&cptx.Node,
+		&cptx.ChallengeHeight,
+		&cptx.Capacity,
+		&cptx.NodeFulfillment,
+		&cptx.TransactionFee,
+		&cptx.CoinInputs,
+		&cptx.RefundCoinOutput,
+	)
+}
+
+// NodeCondition returns the condition that has to be fulfilled by the node,
+// in order to prove a capacity proof is created by the node identified by the given public key.
+func NodeCondition(node types.PublicKey) types.UnlockConditionProxy {
+	return types.NewCondition(types.NewUnlockHashCondition(types.NewPubKeyUnlockHash(node)))
+}
+
+type (
+	// CapacityProofTransactionController defines a tfchain-specific transaction controller,
+	// for a transaction type reserved at type 0xA3. It allows a node to respond to a capacity challenge.
+	CapacityProofTransactionController struct {
+		// Registry is used to get the challenges, capacity records and previous results of nodes.
+		Registry CapacityProofReadRegistry
+	}
+)
+
+var (
+	// ensure at compile time that CapacityProofTransactionController
+	// implements the desired interfaces
+	_ types.TransactionController      = CapacityProofTransactionController{}
+	_ types.TransactionExtensionSigner = CapacityProofTransactionController{}
+	_ types.TransactionValidator       = CapacityProofTransactionController{}
+	_ types.BlockStakeOutputValidator  = CapacityProofTransactionController{}
+	_ types.TransactionSignatureHasher = CapacityProofTransactionController{}
+	_ types.TransactionIDEncoder       = CapacityProofTransactionController{}
+)
+
+// EncodeTransactionData implements TransactionController.EncodeTransactionData
+func (cptc CapacityProofTransactionController) EncodeTransactionData(w io.Writer, txData types.TransactionData) error {
+	cptx, err := CapacityProofTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a CapacityProofTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).Encode(cptx)
+}
+
+// DecodeTransactionData implements TransactionController.DecodeTransactionData
+func (cptc CapacityProofTransactionController) DecodeTransactionData(r io.Reader) (types.TransactionData, error) {
+	var cptx CapacityProofTransaction
+	err := rivbin.NewDecoder(r).Decode(&cptx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to binary-decode tx as a CapacityProofTx: %v", err)
+	}
+	// return capacity proof tx as regular tfchain tx data
+	return cptx.TransactionData(), nil
+}
+
+// JSONEncodeTransactionData implements TransactionController.JSONEncodeTransactionData
+func (cptc CapacityProofTransactionController) JSONEncodeTransactionData(txData types.TransactionData) ([]byte, error) {
+	cptx, err := CapacityProofTransactionFromTransactionData(txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert txData to a CapacityProofTx: %v", err)
+	}
+	return json.Marshal(cptx)
+}
+
+// JSONDecodeTransactionData implements TransactionController.JSONDecodeTransactionData
+func (cptc CapacityProofTransactionController) JSONDecodeTransactionData(data []byte) (types.TransactionData, error) {
+	var cptx CapacityProofTransaction
+	err := json.Unmarshal(data, &cptx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to json-decode tx as a CapacityProofTx: %v", err)
+	}
+	// return capacity proof tx as regular tfchain tx data
+	return cptx.TransactionData(), nil
+}
+
+// ValidateTransaction implements TransactionValidator.ValidateTransaction
+func (cptc CapacityProofTransactionController) ValidateTransaction(t types.Transaction, ctx types.ValidationContext, constants types.TransactionValidationConstants) error {
+	// check tx fits within a block
+	err := types.TransactionFitsInABlock(t, constants.BlockSizeLimit)
+	if err != nil {
+		return err
+	}
+
+	// get CapacityProofTx
+	cptx, err := CapacityProofTransactionFromTransaction(t)
+	if err != nil {
+		return fmt.Errorf("failed to use tx as a capacity proof tx: %v", err)
+	}
+
+	// ensure the node is registered
+	_, err = cptc.Registry.GetCapacityRecordForNode(cptx.Node)
+	if err != nil {
+		return fmt.Errorf("invalid capacity proof tx: node %v: %v", cptx.Node, err)
+	}
+
+	// get the challenge, and ensure it can still be responded to
+	challenge, err := cptc.Registry.GetCapacityChallenge(cptx.ChallengeHeight, cptx.Node)
+	if err != nil {
+		return fmt.Errorf("invalid capacity proof tx: challenge at height %d: %v", cptx.ChallengeHeight, err)
+	}
+	height := ctx.BlockHeight
+	if !ctx.Confirmed {
+		// unconfirmed transactions are validated using the height of the current block,
+		// while they can only be part of the next block at the earliest
+		height++
+	}
+	if !challenge.IsOpen(height) {
+		return fmt.Errorf("invalid capacity proof tx: challenge at height %d cannot be responded to at height %d", cptx.ChallengeHeight, height)
+	}
+
+	// ensure the node did not respond to this challenge already
+	_, err = cptc.Registry.GetCapacityProofResultForNode(cptx.Node, cptx.ChallengeHeight)
+	if err == nil {
+		return fmt.Errorf("invalid capacity proof tx: node %v already responded to challenge at height %d", cptx.Node, cptx.ChallengeHeight)
+	}
+	if err != ErrCapacityProofResultNotFound {
+		return fmt.Errorf("unexpected error while validating non-existence of capacity proof result: %v", err)
+	}
+
+	// check if NodeFulfillment fulfills the node condition, signing the challenge seed
+	err = NodeCondition(cptx.Node).Fulfill(cptx.NodeFulfillment, types.FulfillContext{
+		ExtraObjects: []interface{}{CapacityProofSignatureSpecifier, challenge.Seed},
+		BlockHeight:  ctx.BlockHeight,
+		BlockTime:    ctx.BlockTime,
+		Transaction:  t,
+	})
+	if err != nil {
+		return fmt.Errorf("unauthorized capacity proof tx: failed to fulfill node condition: %v", err)
+	}
+
+	// validate the reported capacity,
+	// not covering the registered capacity is allowed, as it is stored as a failed result
+	err = cptx.Capacity.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid capacity proof tx: %v", err)
+	}
+
+	// validate the miner fee
+	if cptx.TransactionFee.Cmp(constants.MinimumMinerFee) < 0 {
+		return types.ErrTooSmallMinerFee
+	}
+
+	// prevent double spending
+	spendCoins := make(map[types.CoinOutputID]struct{})
+	for _, ci := range cptx.CoinInputs {
+		if _, found := spendCoins[ci.ParentID]; found {
+			return types.ErrDoubleSpend
+		}
+		spendCoins[ci.ParentID] = struct{}{}
+	}
+
+	// check if optional coin output is using standard condition
+	if cptx.RefundCoinOutput != nil {
+		err = cptx.RefundCoinOutput.Condition.IsStandardCondition(ctx)
+		if err != nil {
+			return err
+		}
+		// ensure the value is not 0
+		if cptx.RefundCoinOutput.Value.IsZero() {
+			return types.ErrZeroOutput
+		}
+	}
+	// check if all fulfillments are standard
+	for _, sci := range cptx.CoinInputs {
+		err = sci.Fulfillment.IsStandardFulfillment(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Tx is valid
+	return nil
+}
+
+// ValidateCoinOutputs is not implemented here for CapacityProofTransactionController,
+// instead we can rely on the default ValidateCoinOutputs logic provided by Rivine.
+
+// ValidateBlockStakeOutputs implements BlockStakeOutputValidator.ValidateBlockStakeOutputs
+func (cptc CapacityProofTransactionController) ValidateBlockStakeOutputs(t types.Transaction, ctx types.FundValidationContext, blockStakeInputs map[types.BlockStakeOutputID]types.BlockStakeOutput) (err error) {
+	return nil // always valid, no block stake inputs/outputs exist within a capacity proof transaction
+}
+
+// SignatureHash implements TransactionSignatureHasher.SignatureHash
+func (cptc CapacityProofTransactionController) SignatureHash(t types.Transaction, extraObjects ...interface{}) (crypto.Hash, error) {
+	cptx, err := CapacityProofTransactionFromTransaction(t)
+	if err != nil {
+		return crypto.Hash{}, fmt.Errorf("failed to use tx as a CapacityProofTx: %v", err)
+	}
+
+	h := crypto.NewHash()
+	enc := rivbin.NewEncoder(h)
+
+	enc.EncodeAll(
+		t.Version,
+		SpecifierCapacityProofTransaction,
+		cptx.Node,
+		cptx.ChallengeHeight,
+		cptx.Capacity,
+	)
+
+	if len(extraObjects) > 0 {
+		enc.EncodeAll(extraObjects...)
+	}
+
+	enc.Encode(len(cptx.CoinInputs))
+	for _, ci := range cptx.CoinInputs {
+		enc.Encode(ci.ParentID)
+	}
+
+	enc.EncodeAll(
+		cptx.TransactionFee,
+		cptx.RefundCoinOutput,
+	)
+
+	var hash crypto.Hash
+	h.Sum(hash[:0])
+	return hash, nil
+}
+
+// SignExtension implements TransactionExtensionSigner.SignExtension
+func (cptc CapacityProofTransactionController) SignExtension(extension interface{}, sign func(*types.UnlockFulfillmentProxy, types.UnlockConditionProxy, ...interface{}) error) (interface{}, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid CapacityProofTransactionExtension
+	cptxExtension, ok := extension.(*CapacityProofTransactionExtension)
+	if !ok {
+		return nil, errors.New("invalid extension data for a Capacity Proof Transaction")
+	}
+
+	// get the challenge and sign its seed using the node condition
+	challenge, err := cptc.Registry.GetCapacityChallenge(cptxExtension.ChallengeHeight, cptxExtension.Node)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the capacity challenge at height %d: %v", cptxExtension.ChallengeHeight, err)
+	}
+	err = sign(&cptxExtension.NodeFulfillment, NodeCondition(cptxExtension.Node), CapacityProofSignatureSpecifier, challenge.Seed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign node fulfillment of CapacityProofTx: %v", err)
+	}
+	return cptxExtension, nil
+}
+
+// EncodeTransactionIDInput implements TransactionIDEncoder.EncodeTransactionIDInput
+func (cptc CapacityProofTransactionController) EncodeTransactionIDInput(w io.Writer, txData types.TransactionData) error {
+	cptx, err := CapacityProofTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a CapacityProofTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).EncodeAll(SpecifierCapacityProofTransaction, cptx)
+}
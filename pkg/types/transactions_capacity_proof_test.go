@@ -0,0 +1,311 @@
+package types
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/threefoldfoundation/tfchain/pkg/config"
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestCapacityProofTransactionBinaryEncodingAndID(t *testing.T) {
+	types.RegisterTransactionVersion(TransactionVersionCapacityProof, CapacityProofTransactionController{})
+	defer types.RegisterTransactionVersion(TransactionVersionCapacityProof, nil)
+
+	const (
+		jsonEncodedTx = `{"version":163,"data":{"node":"ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780","challengeheight":720,"capacity":{"cru":4,"mru":16,"hru":2000,"sru":250},"nodefulfillment":{"type":1,"data":{"publickey":"ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780","signature":"6a941c7ca4116f5943fa36e8e3e6b3c4fe4faed5ea55576bf61e58c25739f8b218daf5e141953d82b4626665b8c826ed72af3ad7989808af7486de3ace2c3e07"}},"txfee":"1000000000","coininputs":[{"parentid":"a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee563","fulfillment":{"type":1,"data":{"publickey":"ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780","signature":"6fcfc5031910936111bdf61f62483891037060cbe8c797f669a0d34fcf775138481f0e77cee2630338b9a0df7c0b7de594cf0041b48c7b5e63a25bd31e9afd02"}}}],"refundcoinoutput":{"value":"99999999000000000","condition":{"type":1,"data":{"unlockhash":"015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e6791584fbdac553e6f"}}}}}`
+		hexEncodedTx  = `a301d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780d00200000000000004000000000000001000000000000000d007000000000000fa0000000000000001c401d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780806a941c7ca4116f5943fa36e8e3e6b3c4fe4faed5ea55576bf61e58c25739f8b218daf5e141953d82b4626665b8c826ed72af3ad7989808af7486de3ace2c3e07083b9aca0002a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee56301c401d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780806fcfc5031910936111bdf61f62483891037060cbe8c797f669a0d34fcf775138481f0e77cee2630338b9a0df7c0b7de594cf0041b48c7b5e63a25bd31e9afd0201100163457821ef36000142015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e679158`
+	)
+	var tx types.Transaction
+	err := json.Unmarshal([]byte(jsonEncodedTx), &tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := tx.ID()
+	b := siabin.Marshal(tx)
+	if output := hex.EncodeToString(b); output != hexEncodedTx {
+		t.Fatal(hexEncodedTx, "!=", output)
+	}
+
+	// go to capacity proof Tx and back
+	cptx, err := CapacityProofTransactionFromTransaction(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oTx := cptx.Transaction()
+	oID := oTx.ID()
+	oB := siabin.Marshal(oTx)
+	if id != oID {
+		t.Fatal(id, "!=", oID)
+	}
+	if !bytes.Equal(b, oB) {
+		t.Fatal(hex.EncodeToString(b), "!=", hex.EncodeToString(oB))
+	}
+
+	// binary decode it again, resulting in the same JSON-encoded transaction
+	var decodedTx types.Transaction
+	err = siabin.Unmarshal(oB, &decodedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err = json.Marshal(decodedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output := string(b); output != jsonEncodedTx {
+		t.Fatal(jsonEncodedTx, "!=", output)
+	}
+}
+
+func TestCapacityProofTransactionValidation(t *testing.T) {
+	nodeKey := hsk("788c0aaeec8e0d916a712535826fa2d47d19fd7b341242f05de0d2e6e7e06104d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780")
+	node := types.PublicKey{
+		Algorithm: types.SignatureAlgoEd25519,
+		Key:       hbs("d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780"),
+	}
+	registry := &inMemoryCapacityProofRegistry{
+		challenges: map[types.BlockHeight]types.BlockID{
+			720: types.BlockID(hs("4f3e7c0e7a3b5e7a2c9d1f6e1a1f3b3a0c2c1f7c6a3e4c9d3f6b2a1c0d9e8f7a")),
+		},
+		records: map[string]CapacityRecord{
+			node.String(): {
+				Farm:     1,
+				Node:     node,
+				Capacity: CapacitySpecification{CRU: 4, MRU: 16, HRU: 2000, SRU: 250},
+			},
+		},
+		results: map[types.BlockHeight]CapacityProofResult{},
+	}
+	types.RegisterTransactionVersion(TransactionVersionCapacityProof, CapacityProofTransactionController{
+		Registry: registry,
+	})
+	defer types.RegisterTransactionVersion(TransactionVersionCapacityProof, nil)
+
+	chainConstants := config.GetDevnetGenesis()
+	validationConstants := types.TransactionValidationConstants{
+		BlockSizeLimit:         chainConstants.BlockSizeLimit,
+		ArbitraryDataSizeLimit: chainConstants.ArbitraryDataSizeLimit,
+		MinimumMinerFee:        chainConstants.MinimumTransactionFee,
+	}
+	const unsignedJSONEncodedTx = `{
+	"version": 163,
+	"data": {
+		"node": "ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780",
+		"challengeheight": 720,
+		"capacity": {
+			"cru": 4,
+			"mru": 16,
+			"hru": 2000,
+			"sru": 250
+		},
+		"nodefulfillment": {
+			"type": 1,
+			"data": {
+				"publickey": "ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780",
+				"signature": ""
+			}
+		},
+		"txfee": "1000000000",
+		"coininputs": [{
+			"parentid": "a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee563",
+			"fulfillment": {
+				"type": 1,
+				"data": {
+					"publickey": "ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780",
+					"signature": ""
+				}
+			}
+		}],
+		"refundcoinoutput": {
+			"value": "99999999000000000",
+			"condition": {
+				"type": 1,
+				"data": {
+					"unlockhash": "015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e6791584fbdac553e6f"
+				}
+			}
+		}
+	}
+}`
+	decodeTx := func() CapacityProofTransaction {
+		t.Helper()
+		var tx types.Transaction
+		err := tx.UnmarshalJSON([]byte(unsignedJSONEncodedTx))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cptx, err := CapacityProofTransactionFromTransaction(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cptx
+	}
+	signAndValidate := func(cptx CapacityProofTransaction, key interface{}, height types.BlockHeight, confirmed bool) error {
+		tx := cptx.Transaction()
+		err := tx.SignExtension(func(fulfillment *types.UnlockFulfillmentProxy, condition types.UnlockConditionProxy, eo ...interface{}) error {
+			return fulfillment.Sign(types.FulfillmentSignContext{
+				ExtraObjects: eo,
+				Transaction:  tx,
+				Key:          key,
+			})
+		})
+		if err != nil {
+			return err
+		}
+		err = tx.CoinInputs[0].Fulfillment.Sign(types.FulfillmentSignContext{
+			ExtraObjects: []interface{}{uint64(0)},
+			Transaction:  tx,
+			Key:          nodeKey,
+		})
+		if err != nil {
+			return err
+		}
+		return tx.ValidateTransaction(types.ValidationContext{
+			Confirmed:   confirmed,
+			BlockHeight: height,
+			BlockTime:   1534271219,
+		}, validationConstants)
+	}
+
+	// signed by the node within the response window, should succeed
+	err := signAndValidate(decodeTx(), nodeKey, 721, true)
+	if err != nil {
+		t.Fatalf("failed to validate valid capacity proof: %v", err)
+	}
+	err = signAndValidate(decodeTx(), nodeKey, 720, false)
+	if err != nil {
+		t.Fatalf("failed to validate valid unconfirmed capacity proof: %v", err)
+	}
+	err = signAndValidate(decodeTx(), nodeKey, 720+CapacityProofResponseWindow, true)
+	if err != nil {
+		t.Fatalf("failed to validate valid capacity proof at the end of the response window: %v", err)
+	}
+
+	// reporting less capacity than registered, is valid but fails the challenge
+	cptx := decodeTx()
+	cptx.Capacity.CRU = 2
+	err = signAndValidate(cptx, nodeKey, 721, true)
+	if err != nil {
+		t.Fatalf("failed to validate capacity proof reporting less capacity: %v", err)
+	}
+
+	// signed by some random key, should fail
+	err = signAndValidate(decodeTx(), func() crypto.SecretKey { sk, _ := crypto.GenerateKeyPair(); return sk }(), 721, true)
+	if err == nil {
+		t.Error("succeeded to validate capacity proof signed by an unauthorized key")
+	}
+
+	// outside of the response window, should fail
+	err = signAndValidate(decodeTx(), nodeKey, 720, true)
+	if err == nil {
+		t.Error("succeeded to validate capacity proof as part of the challenge block")
+	}
+	err = signAndValidate(decodeTx(), nodeKey, 720+CapacityProofResponseWindow+1, true)
+	if err == nil {
+		t.Error("succeeded to validate capacity proof after the response window")
+	}
+
+	// unknown challenge, should fail
+	cptx = decodeTx()
+	cptx.ChallengeHeight = 1440
+	err = signAndValidate(cptx, nodeKey, 1441, true)
+	if err == nil {
+		t.Error("succeeded to validate capacity proof for an unknown challenge")
+	}
+
+	// nil capacity, should fail
+	cptx = decodeTx()
+	cptx.Capacity = CapacitySpecification{}
+	err = signAndValidate(cptx, nodeKey, 721, true)
+	if err == nil {
+		t.Error("succeeded to validate capacity proof with a nil capacity")
+	}
+
+	// already responded, should fail
+	registry.results[720] = CapacityProofResult{ChallengeHeight: 720, Passed: true}
+	err = signAndValidate(decodeTx(), nodeKey, 721, true)
+	if err == nil {
+		t.Error("succeeded to validate capacity proof for a challenge that was already responded to")
+	}
+	delete(registry.results, 720)
+
+	// unregistered node, should fail
+	delete(registry.records, node.String())
+	err = signAndValidate(decodeTx(), nodeKey, 721, true)
+	if err == nil {
+		t.Error("succeeded to validate capacity proof for an unregistered node")
+	}
+}
+
+func TestCapacityProofVerified(t *testing.T) {
+	passed := CapacityProofResult{Passed: true}
+	failed := CapacityProofResult{Passed: false}
+	testCases := []struct {
+		Results  []CapacityProofResult
+		Verified bool
+	}{
+		{nil, true},
+		{[]CapacityProofResult{failed}, true},
+		{[]CapacityProofResult{failed, failed}, true},
+		{[]CapacityProofResult{failed, failed, failed}, false},
+		{[]CapacityProofResult{passed, failed, failed, failed}, false},
+		{[]CapacityProofResult{failed, failed, failed, passed}, true},
+		{[]CapacityProofResult{failed, failed, passed, failed, failed}, true},
+	}
+	for idx, testCase := range testCases {
+		if verified := CapacityProofVerified(testCase.Results); verified != testCase.Verified {
+			t.Error(idx, "unexpected verified status:", verified, "!=", testCase.Verified)
+		}
+	}
+}
+
+func TestCapacityChallengeIsOpen(t *testing.T) {
+	if IsCapacityChallengeHeight(0) || IsCapacityChallengeHeight(719) || !IsCapacityChallengeHeight(720) {
+		t.Fatal("unexpected capacity challenge heights")
+	}
+	challenge := NewCapacityChallenge(720, types.BlockID{}, types.PublicKey{})
+	for _, height := range []types.BlockHeight{0, 719, 720, 720 + CapacityProofResponseWindow + 1} {
+		if challenge.IsOpen(height) {
+			t.Error("challenge is unexpectedly open at height", height)
+		}
+	}
+	for _, height := range []types.BlockHeight{721, 750, 720 + CapacityProofResponseWindow} {
+		if !challenge.IsOpen(height) {
+			t.Error("challenge is unexpectedly closed at height", height)
+		}
+	}
+}
+
+type inMemoryCapacityProofRegistry struct {
+	challenges map[types.BlockHeight]types.BlockID
+	records    map[string]CapacityRecord
+	results    map[types.BlockHeight]CapacityProofResult
+}
+
+func (registry *inMemoryCapacityProofRegistry) GetCapacityChallenge(height types.BlockHeight, node types.PublicKey) (CapacityChallenge, error) {
+	blockID, ok := registry.challenges[height]
+	if !ok {
+		return CapacityChallenge{}, ErrCapacityChallengeNotFound
+	}
+	return NewCapacityChallenge(height, blockID, node), nil
+}
+
+func (registry *inMemoryCapacityProofRegistry) GetCapacityRecordForNode(node types.PublicKey) (*CapacityRecord, error) {
+	record, ok := registry.records[node.String()]
+	if !ok {
+		return nil, ErrNodeNotFound
+	}
+	return &record, nil
+}
+
+func (registry *inMemoryCapacityProofRegistry) GetCapacityProofResultForNode(node types.PublicKey, height types.BlockHeight) (*CapacityProofResult, error) {
+	result, ok := registry.results[height]
+	if !ok {
+		return nil, ErrCapacityProofResultNotFound
+	}
+	return &result, nil
+}
@@ -0,0 +1,338 @@
+package types
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/threefoldfoundation/tfchain/pkg/config"
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestCapacitySpecificationValidate(t *testing.T) {
+	testCases := []struct {
+		Capacity      CapacitySpecification
+		ExpectedError error
+	}{
+		{CapacitySpecification{}, ErrNilCapacity},
+		{CapacitySpecification{MRU: 4, SRU: 100}, ErrInvalidCapacity},
+		{CapacitySpecification{CRU: 1}, nil},
+		{CapacitySpecification{CRU: 4, MRU: 16, HRU: 2000, SRU: 250}, nil},
+	}
+	for idx, testCase := range testCases {
+		err := testCase.Capacity.Validate()
+		if err != testCase.ExpectedError {
+			t.Error(idx, testCase.Capacity, "unexpected error:", err, "!=", testCase.ExpectedError)
+		}
+	}
+}
+
+func TestFarmIDStringLoading(t *testing.T) {
+	var id FarmID
+	for _, invalid := range []string{"", "0", "-1", "foo", "4294967296"} {
+		if err := id.LoadString(invalid); err == nil {
+			t.Error("expected LoadString to fail for", invalid)
+		}
+	}
+	err := id.LoadString("42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 42 || id.String() != "42" {
+		t.Fatal("unexpected farm ID", id)
+	}
+}
+
+func TestJSONExampleCapacityRegistrationTransaction(t *testing.T) {
+	// define tfchain-specific transaction versions
+	types.RegisterTransactionVersion(TransactionVersionCapacityRegistration, CapacityRegistrationTransactionController{})
+	defer types.RegisterTransactionVersion(TransactionVersionCapacityRegistration, nil)
+
+	const jsonEncodedExample = `{
+	"version": 160,
+	"data": {
+		"farm": 1,
+		"node": "ed25519:a271b9d4c1258f070e1e8d95250e6d29f683649829c2227564edd5ddeb75819d",
+		"capacity": {
+			"cru": 4,
+			"mru": 16,
+			"hru": 2000,
+			"sru": 250
+		},
+		"farmerfulfillment": {
+			"type": 1,
+			"data": {
+				"publickey": "ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780",
+				"signature": "b1f9671f1272eb23ba1a59c10aad203b533400fa7f339f14ec02f666e1046f7b7cbd876629f06b3251c2be89d98f0764ee9f63071186ed53e23d27b50b7f8901"
+			}
+		},
+		"txfee": "1000000000",
+		"coininputs": [{
+			"parentid": "a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee563",
+			"fulfillment": {
+				"type": 1,
+				"data": {
+					"publickey": "ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780",
+					"signature": "2d483fd77263183f779c4088dc692333e3d22df3cd5f447fe32a88aec92d645c1d5905498d7e109f6f6bdf1e762866bf9697bf6fb6e09fbf6288c093bf2c5e0e"
+				}
+			}
+		}],
+		"refundcoinoutput": {
+			"value": "99999999000000000",
+			"condition": {
+				"type": 1,
+				"data": {
+					"unlockhash": "01370af706b547dd4e562a047e6265d7e7750771f9bff633b1a12dbd59b11712c6ef65edb1690d"
+				}
+			}
+		},
+		"arbitrarydata": "ZmFybSAxLCByYWNrIDM="
+	}
+}`
+
+	var tx types.Transaction
+	err := json.Unmarshal([]byte(jsonEncodedExample), &tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := string(b)
+	buffer := bytes.NewBuffer(nil)
+	err = json.Compact(buffer, []byte(jsonEncodedExample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := string(buffer.Bytes())
+	if expectedOutput != output {
+		t.Fatal(expectedOutput, "!=", output)
+	}
+}
+
+func TestBinaryExampleCapacityRegistrationTransaction(t *testing.T) {
+	// define tfchain-specific transaction versions
+	types.RegisterTransactionVersion(TransactionVersionCapacityRegistration, CapacityRegistrationTransactionController{})
+	defer types.RegisterTransactionVersion(TransactionVersionCapacityRegistration, nil)
+
+	const hexEncodedExample = `a00100000001a271b9d4c1258f070e1e8d95250e6d29f683649829c2227564edd5ddeb75819d04000000000000001000000000000000d007000000000000fa0000000000000001c401d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d778080b1f9671f1272eb23ba1a59c10aad203b533400fa7f339f14ec02f666e1046f7b7cbd876629f06b3251c2be89d98f0764ee9f63071186ed53e23d27b50b7f8901083b9aca0002a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee56301c401d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780802d483fd77263183f779c4088dc692333e3d22df3cd5f447fe32a88aec92d645c1d5905498d7e109f6f6bdf1e762866bf9697bf6fb6e09fbf6288c093bf2c5e0e01100163457821ef3600014201370af706b547dd4e562a047e6265d7e7750771f9bff633b1a12dbd59b11712c61c6661726d20312c207261636b2033`
+
+	b, err := hex.DecodeString(hexEncodedExample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tx types.Transaction
+	err = siabin.Unmarshal(b, &tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b = siabin.Marshal(tx)
+	output := hex.EncodeToString(b)
+	if hexEncodedExample != output {
+		t.Fatal(hexEncodedExample, "!=", output)
+	}
+}
+
+func TestCapacityRegistrationTransactionBinaryEncodingAndID(t *testing.T) {
+	types.RegisterTransactionVersion(TransactionVersionCapacityRegistration, CapacityRegistrationTransactionController{})
+	defer types.RegisterTransactionVersion(TransactionVersionCapacityRegistration, nil)
+
+	const input = `{"version":160,"data":{"farm":1,"node":"ed25519:a271b9d4c1258f070e1e8d95250e6d29f683649829c2227564edd5ddeb75819d","capacity":{"cru":4,"mru":16,"hru":2000,"sru":250},"farmerfulfillment":{"type":1,"data":{"publickey":"ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780","signature":"b1f9671f1272eb23ba1a59c10aad203b533400fa7f339f14ec02f666e1046f7b7cbd876629f06b3251c2be89d98f0764ee9f63071186ed53e23d27b50b7f8901"}},"txfee":"1000000000","coininputs":[{"parentid":"a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee563","fulfillment":{"type":1,"data":{"publickey":"ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780","signature":"2d483fd77263183f779c4088dc692333e3d22df3cd5f447fe32a88aec92d645c1d5905498d7e109f6f6bdf1e762866bf9697bf6fb6e09fbf6288c093bf2c5e0e"}}}],"refundcoinoutput":{"value":"99999999000000000","condition":{"type":1,"data":{"unlockhash":"01370af706b547dd4e562a047e6265d7e7750771f9bff633b1a12dbd59b11712c6ef65edb1690d"}}},"arbitrarydata":"ZmFybSAxLCByYWNrIDM="}}`
+	var tx types.Transaction
+	err := json.Unmarshal([]byte(input), &tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := tx.ID()
+	b := siabin.Marshal(tx)
+
+	// go to capacity registration Tx and back
+	crtx, err := CapacityRegistrationTransactionFromTransaction(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oTx := crtx.Transaction()
+	oID := oTx.ID()
+	oB := siabin.Marshal(oTx)
+	if id != oID {
+		t.Fatal(id, "!=", oID)
+	}
+	if !bytes.Equal(b, oB) {
+		t.Fatal(hex.EncodeToString(b), "!=", hex.EncodeToString(oB))
+	}
+
+	// binary decode it again, resulting in the same capacity registration Tx
+	var decodedTx types.Transaction
+	err = siabin.Unmarshal(oB, &decodedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decodedCRTX, err := CapacityRegistrationTransactionFromTransaction(decodedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(crtx, decodedCRTX) {
+		t.Fatal(crtx, "!=", decodedCRTX)
+	}
+}
+
+func TestCapacityRegistrationTransactionValidation(t *testing.T) {
+	farmerCondition := types.NewCondition(types.NewUnlockHashCondition(
+		unlockHashFromHex("015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e6791584fbdac553e6f")))
+	types.RegisterTransactionVersion(TransactionVersionCapacityRegistration, CapacityRegistrationTransactionController{
+		FarmerConditionGetter: inMemoryFarmerConditionGetter{1: farmerCondition},
+	})
+	defer types.RegisterTransactionVersion(TransactionVersionCapacityRegistration, nil)
+
+	farmerKey := hsk("788c0aaeec8e0d916a712535826fa2d47d19fd7b341242f05de0d2e6e7e06104d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780")
+
+	chainConstants := config.GetDevnetGenesis()
+	validationConstants := types.TransactionValidationConstants{
+		BlockSizeLimit:         chainConstants.BlockSizeLimit,
+		ArbitraryDataSizeLimit: chainConstants.ArbitraryDataSizeLimit,
+		MinimumMinerFee:        chainConstants.MinimumTransactionFee,
+	}
+	const unsignedJSONEncodedTx = `{
+	"version": 160,
+	"data": {
+		"farm": 1,
+		"node": "ed25519:a271b9d4c1258f070e1e8d95250e6d29f683649829c2227564edd5ddeb75819d",
+		"capacity": {
+			"cru": 4,
+			"mru": 16,
+			"hru": 2000,
+			"sru": 250
+		},
+		"farmerfulfillment": {
+			"type": 1,
+			"data": {
+				"publickey": "ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780",
+				"signature": ""
+			}
+		},
+		"txfee": "1000000000",
+		"coininputs": [{
+			"parentid": "a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee563",
+			"fulfillment": {
+				"type": 1,
+				"data": {
+					"publickey": "ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780",
+					"signature": ""
+				}
+			}
+		}],
+		"refundcoinoutput": {
+			"value": "99999999000000000",
+			"condition": {
+				"type": 1,
+				"data": {
+					"unlockhash": "01370af706b547dd4e562a047e6265d7e7750771f9bff633b1a12dbd59b11712c6ef65edb1690d"
+				}
+			}
+		},
+		"arbitrarydata": "ZmFybSAxLCByYWNrIDM="
+	}
+}`
+	// decode the unsigned capacity registration, such that each test case can modify it prior to signing
+	decodeTx := func() CapacityRegistrationTransaction {
+		t.Helper()
+		var tx types.Transaction
+		err := tx.UnmarshalJSON([]byte(unsignedJSONEncodedTx))
+		if err != nil {
+			t.Fatal(err)
+		}
+		crtx, err := CapacityRegistrationTransactionFromTransaction(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return crtx
+	}
+	signAndValidate := func(crtx CapacityRegistrationTransaction, key interface{}) error {
+		t.Helper()
+		tx := crtx.Transaction()
+		err := tx.SignExtension(func(fulfillment *types.UnlockFulfillmentProxy, condition types.UnlockConditionProxy, eo ...interface{}) error {
+			return fulfillment.Sign(types.FulfillmentSignContext{
+				ExtraObjects: eo,
+				Transaction:  tx,
+				Key:          key,
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("failed to sign: %v", err)
+		}
+		err = tx.CoinInputs[0].Fulfillment.Sign(types.FulfillmentSignContext{
+			ExtraObjects: []interface{}{uint64(0)},
+			Transaction:  tx,
+			Key:          farmerKey,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to sign coin input: %v", err)
+		}
+		return tx.ValidateTransaction(types.ValidationContext{
+			Confirmed:   true,
+			BlockHeight: 4072,
+			BlockTime:   1534271219,
+		}, validationConstants)
+	}
+
+	// signed by the authorized farmer, should succeed
+	err := signAndValidate(decodeTx(), farmerKey)
+	if err != nil {
+		t.Fatalf("failed to validate valid capacity registration: %v", err)
+	}
+
+	// signed by some random key, should fail
+	err = signAndValidate(decodeTx(), func() crypto.SecretKey { sk, _ := crypto.GenerateKeyPair(); return sk }())
+	if err == nil {
+		t.Error("succeeded to validate capacity registration signed by an unauthorized key")
+	}
+
+	// invalid capacity, should fail
+	crtx := decodeTx()
+	crtx.Capacity = CapacitySpecification{MRU: 16}
+	err = signAndValidate(crtx, farmerKey)
+	if err == nil {
+		t.Error("succeeded to validate capacity registration with invalid capacity")
+	}
+
+	// unknown farm, should fail
+	crtx = decodeTx()
+	crtx.Farm = 2
+	err = signAndValidate(crtx, farmerKey)
+	if err == nil {
+		t.Error("succeeded to validate capacity registration for an unknown farm")
+	}
+
+	// invalid node key, should fail
+	crtx = decodeTx()
+	crtx.Node.Key = crtx.Node.Key[:16]
+	err = signAndValidate(crtx, farmerKey)
+	if err == nil {
+		t.Error("succeeded to validate capacity registration for an invalid node key")
+	}
+
+	// too small fee, should fail
+	crtx = decodeTx()
+	crtx.TransactionFee = types.NewCurrency64(1)
+	err = signAndValidate(crtx, farmerKey)
+	if err == nil {
+		t.Error("succeeded to validate capacity registration with a too small fee")
+	}
+}
+
+type inMemoryFarmerConditionGetter map[FarmID]types.UnlockConditionProxy
+
+func (getter inMemoryFarmerConditionGetter) GetFarmerCondition(farm FarmID) (types.UnlockConditionProxy, error) {
+	condition, ok := getter[farm]
+	if !ok {
+		return types.UnlockConditionProxy{}, ErrFarmNotFound
+	}
+	return condition, nil
+}
@@ -0,0 +1,502 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+const (
+	// TransactionVersionCoinBurn defines the Transaction version
+	// for a CoinBurn Transaction, used to destroy coins.
+	//
+	// See the `CoinBurnTransactionController` and `CoinBurnTransaction`
+	// types for more information.
+	TransactionVersionCoinBurn types.TransactionVersion = iota + 130
+)
+
+// These Specifiers are used internally when calculating a Transaction's ID.
+// See Rivine's Specifier for more details.
+var (
+	SpecifierCoinBurnTransaction = types.Specifier{'c', 'o', 'i', 'n', ' ', 'b', 'u', 'r', 'n', ' ', 't', 'x'}
+)
+
+// Specifiers used to ensure the burn-authorization signatures are unique within each Tx.
+var (
+	CoinBurnSignatureSpecifier = [...]byte{'b', 'u', 'r', 'n'}
+)
+
+const (
+	// MaxCoinBurnReasonLength defines the maximum length (in bytes)
+	// of the reason of a CoinBurn Transaction.
+	MaxCoinBurnReasonLength = 128
+)
+
+type (
+	// CoinBurnTransaction defines the Transaction (with version 0x82)
+	// used to destroy coins. The burned coins are consumed as coin inputs,
+	// without being registered as a coin output, and are as such removed
+	// from the total pool of coins available in the tfchain network.
+	CoinBurnTransaction struct {
+		// Value defines the amount of coins burned.
+		Value types.Currency `json:"value"`
+		// Reason defines the (mandatory) reason why the coins are burned,
+		// which is recorded on-chain together with the burned value.
+		Reason []byte `json:"reason"`
+
+		// BurnFulfillment defines the optional fulfillment which is used in order to
+		// fulfill the burn-authorization condition of the network,
+		// required if and only if such a condition is defined.
+		BurnFulfillment *types.UnlockFulfillmentProxy `json:"burnfulfillment,omitempty"`
+
+		// TransactionFee defines the regular Tx fee.
+		TransactionFee types.Currency `json:"txfee"`
+
+		// CoinInputs are used for the burned value as well as the required fees,
+		// at least one CoinInput is required.
+		CoinInputs []types.CoinInput `json:"coininputs"`
+		// RefundCoinOutput is an optional coin output that can be used
+		// to refund coins paid as inputs for the burned value and required fees.
+		RefundCoinOutput *types.CoinOutput `json:"refundcoinoutput,omitempty"`
+	}
+	// CoinBurnTransactionExtension defines the CoinBurnTx Extension Data
+	CoinBurnTransactionExtension struct {
+		Value           types.Currency
+		Reason          []byte
+		BurnFulfillment *types.UnlockFulfillmentProxy
+	}
+)
+
+// CoinBurnTransactionFromTransaction creates a CoinBurnTransaction,
+// using a regular in-memory tfchain transaction.
+//
+// Past the (tx) Version validation it piggy-backs onto the
+// `CoinBurnTransactionFromTransactionData` constructor.
+func CoinBurnTransactionFromTransaction(tx types.Transaction) (CoinBurnTransaction, error) {
+	if tx.Version != TransactionVersionCoinBurn {
+		return CoinBurnTransaction{}, fmt.Errorf(
+			"a coin burn transaction requires tx version %d",
+			TransactionVersionCoinBurn)
+	}
+	return CoinBurnTransactionFromTransactionData(types.TransactionData{
+		CoinInputs:        tx.CoinInputs,
+		CoinOutputs:       tx.CoinOutputs,
+		BlockStakeInputs:  tx.BlockStakeInputs,
+		BlockStakeOutputs: tx.BlockStakeOutputs,
+		MinerFees:         tx.MinerFees,
+		ArbitraryData:     tx.ArbitraryData,
+		Extension:         tx.Extension,
+	})
+}
+
+// CoinBurnTransactionFromTransactionData creates a CoinBurnTransaction,
+// using the TransactionData from a regular in-memory tfchain transaction.
+func CoinBurnTransactionFromTransactionData(txData types.TransactionData) (CoinBurnTransaction, error) {
+	// validate the Transaction Data
+
+	// at least one coin input as well as one miner fee is required
+	if len(txData.CoinInputs) == 0 || len(txData.MinerFees) != 1 {
+		return CoinBurnTransaction{}, errors.New("at least one coin input and exactly one miner fee is required for a Coin Burn Transaction")
+	}
+	// no block stake inputs or block stake outputs are allowed
+	if len(txData.BlockStakeInputs) != 0 || len(txData.BlockStakeOutputs) != 0 {
+		return CoinBurnTransaction{}, errors.New("no block stake inputs/outputs are allowed in a Coin Burn Transaction")
+	}
+	// no arbitrary data is allowed, the reason is to be used instead
+	if len(txData.ArbitraryData) > 0 {
+		return CoinBurnTransaction{}, errors.New("no arbitrary data is allowed in a Coin Burn Transaction")
+	}
+	// validate that the coin outputs is within the expected range
+	if len(txData.CoinOutputs) > 1 {
+		return CoinBurnTransaction{}, errors.New("a Coin Burn Transaction can only have one coin output")
+	}
+
+	// (tx) extension (data) is expected to be a pointer to a valid CoinBurnTransactionExtension,
+	// which contains all the properties unique to a coin burn Tx
+	extensionData, ok := txData.Extension.(*CoinBurnTransactionExtension)
+	if !ok {
+		return CoinBurnTransaction{}, errors.New("invalid extension data for a Coin Burn Transaction")
+	}
+
+	// create the CoinBurnTransaction and return it,
+	// further validation will/has-to be done using the Transaction Type, if required
+	tx := CoinBurnTransaction{
+		Value:           extensionData.Value,
+		Reason:          extensionData.Reason,
+		BurnFulfillment: extensionData.BurnFulfillment,
+		TransactionFee:  txData.MinerFees[0],
+		CoinInputs:      txData.CoinInputs,
+	}
+	if len(txData.CoinOutputs) == 1 {
+		// take refund coin output if it exists
+		tx.RefundCoinOutput = &txData.CoinOutputs[0]
+	}
+	return tx, nil
+}
+
+// TransactionData returns this CoinBurnTransaction
+// as regular tfchain transaction data.
+func (cbtx *CoinBurnTransaction) TransactionData() types.TransactionData {
+	txData := types.TransactionData{
+		CoinInputs: cbtx.CoinInputs,
+		MinerFees:  []types.Currency{cbtx.TransactionFee},
+		Extension: &CoinBurnTransactionExtension{
+			Value:           cbtx.Value,
+			Reason:          cbtx.Reason,
+			BurnFulfillment: cbtx.BurnFulfillment,
+		},
+	}
+	if cbtx.RefundCoinOutput != nil {
+		txData.CoinOutputs = append(txData.CoinOutputs, *cbtx.RefundCoinOutput)
+	}
+	return txData
+}
+
+// Transaction returns this CoinBurnTransaction
+// as regular tfchain transaction, using TransactionVersionCoinBurn as the type.
+func (cbtx *CoinBurnTransaction) Transaction() types.Transaction {
+	tx := types.Transaction{
+		Version:    TransactionVersionCoinBurn,
+		CoinInputs: cbtx.CoinInputs,
+		MinerFees:  []types.Currency{cbtx.TransactionFee},
+		Extension: &CoinBurnTransactionExtension{
+			Value:           cbtx.Value,
+			Reason:          cbtx.Reason,
+			BurnFulfillment: cbtx.BurnFulfillment,
+		},
+	}
+	if cbtx.RefundCoinOutput != nil {
+		tx.CoinOutputs = append(tx.CoinOutputs, *cbtx.RefundCoinOutput)
+	}
+	return tx
+}
+
+// MarshalSia implements SiaMarshaler.MarshalSia,
+// alias of MarshalRivine for backwards-compatibility reasons.
+func (cbtx CoinBurnTransaction) MarshalSia(w io.Writer) error {
+	return cbtx.MarshalRivine(w)
+}
+
+// UnmarshalSia implements SiaUnmarshaler.UnmarshalSia,
+// alias of UnmarshalRivine for backwards-compatibility reasons.
+func (cbtx *CoinBurnTransaction) UnmarshalSia(r io.Reader) error {
+	return cbtx.UnmarshalRivine(r)
+}
+
+// MarshalRivine implements RivineMarshaler.MarshalRivine
+func (cbtx CoinBurnTransaction) MarshalRivine(w io.Writer) error {
+	enc := rivbin.NewEncoder(w)
+	err := enc.EncodeAll(
+		cbtx.Value,
+		cbtx.Reason,
+	)
+	if err != nil {
+		return err
+	}
+	// the (optional) burn fulfillment is encoded manually,
+	// as a nil fulfillment proxy pointer cannot be encoded as-is
+	if cbtx.BurnFulfillment == nil {
+		err = enc.Encode(false)
+	} else {
+		err = enc.EncodeAll(true, *cbtx.BurnFulfillment)
+	}
+	if err != nil {
+		return err
+	}
+	return enc.EncodeAll(
+		cbtx.TransactionFee,
+		cbtx.CoinInputs,
+		cbtx.RefundCoinOutput,
+	)
+}
+
+// UnmarshalRivine implements RivineUnmarshaler.UnmarshalRivine
+func (cbtx *CoinBurnTransaction) UnmarshalRivine(r io.Reader) error {
+	dec := rivbin.NewDecoder(r)
+	var hasBurnFulfillment bool
+	err := dec.DecodeAll(
+		&cbtx.Value,
+		&cbtx.Reason,
+		&hasBurnFulfillment,
+	)
+	if err != nil {
+		return err
+	}
+	cbtx.BurnFulfillment = nil
+	if hasBurnFulfillment {
+		cbtx.BurnFulfillment = new(types.UnlockFulfillmentProxy)
+		err = dec.Decode(cbtx.BurnFulfillment)
+		if err != nil {
+			return err
+		}
+	}
+	return dec.DecodeAll(
+		&cbtx.TransactionFee,
+		&cbtx.CoinInputs,
+		&cbtx.RefundCoinOutput,
+	)
+}
+
+type (
+	// CoinBurnTransactionController defines a tfchain-specific transaction controller,
+	// for a transaction type reserved at type 0x82. It allows the burning of coins.
+	CoinBurnTransactionController struct {
+		// BurnCondition is the (optional) burn-authorization condition of the network.
+		//
+		// If defined, the burn fulfillment of each coin burn transaction
+		// has to fulfill this condition, otherwise anyone can burn (their own) coins.
+		BurnCondition types.UnlockConditionProxy
+	}
+)
+
+var (
+	// ensure at compile time that CoinBurnTransactionController
+	// implements the desired interfaces
+	_ types.TransactionController      = CoinBurnTransactionController{}
+	_ types.TransactionValidator       = CoinBurnTransactionController{}
+	_ types.CoinOutputValidator        = CoinBurnTransactionController{}
+	_ types.BlockStakeOutputValidator  = CoinBurnTransactionController{}
+	_ types.TransactionSignatureHasher = CoinBurnTransactionController{}
+	_ types.TransactionExtensionSigner = CoinBurnTransactionController{}
+	_ types.TransactionIDEncoder       = CoinBurnTransactionController{}
+)
+
+// EncodeTransactionData implements TransactionController.EncodeTransactionData
+func (cbtc CoinBurnTransactionController) EncodeTransactionData(w io.Writer, txData types.TransactionData) error {
+	cbtx, err := CoinBurnTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a CoinBurnTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).Encode(cbtx)
+}
+
+// DecodeTransactionData implements TransactionController.DecodeTransactionData
+func (cbtc CoinBurnTransactionController) DecodeTransactionData(r io.Reader) (types.TransactionData, error) {
+	var cbtx CoinBurnTransaction
+	err := rivbin.NewDecoder(r).Decode(&cbtx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to binary-decode tx as a CoinBurnTx: %v", err)
+	}
+	// return coin burn tx as regular tfchain tx data
+	return cbtx.TransactionData(), nil
+}
+
+// JSONEncodeTransactionData implements TransactionController.JSONEncodeTransactionData
+func (cbtc CoinBurnTransactionController) JSONEncodeTransactionData(txData types.TransactionData) ([]byte, error) {
+	cbtx, err := CoinBurnTransactionFromTransactionData(txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert txData to a CoinBurnTx: %v", err)
+	}
+	return json.Marshal(cbtx)
+}
+
+// JSONDecodeTransactionData implements TransactionController.JSONDecodeTransactionData
+func (cbtc CoinBurnTransactionController) JSONDecodeTransactionData(data []byte) (types.TransactionData, error) {
+	var cbtx CoinBurnTransaction
+	err := json.Unmarshal(data, &cbtx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to json-decode tx as a CoinBurnTx: %v", err)
+	}
+	// return coin burn tx as regular tfchain tx data
+	return cbtx.TransactionData(), nil
+}
+
+// ValidateTransaction implements TransactionValidator.ValidateTransaction
+func (cbtc CoinBurnTransactionController) ValidateTransaction(t types.Transaction, ctx types.ValidationContext, constants types.TransactionValidationConstants) error {
+	// check tx fits within a block
+	err := types.TransactionFitsInABlock(t, constants.BlockSizeLimit)
+	if err != nil {
+		return err
+	}
+
+	// get CoinBurnTx
+	cbtx, err := CoinBurnTransactionFromTransaction(t)
+	if err != nil {
+		return fmt.Errorf("failed to use tx as a coin burn tx: %v", err)
+	}
+
+	// validate the burned value and reason
+	if cbtx.Value.IsZero() {
+		return errors.New("invalid coin burn tx: no coins are burned")
+	}
+	if len(cbtx.Reason) == 0 {
+		return errors.New("invalid coin burn tx: a reason is required")
+	}
+	if len(cbtx.Reason) > MaxCoinBurnReasonLength {
+		return fmt.Errorf("invalid coin burn tx: the reason cannot be longer than %d bytes", MaxCoinBurnReasonLength)
+	}
+
+	// validate the burn authorization, if required
+	if cbtc.BurnCondition.ConditionType() == types.ConditionTypeNil {
+		if cbtx.BurnFulfillment != nil {
+			return errors.New("invalid coin burn tx: no burn fulfillment is allowed as no burn condition is defined")
+		}
+	} else {
+		if cbtx.BurnFulfillment == nil {
+			return errors.New("unauthorized coin burn tx: a burn fulfillment is required")
+		}
+		// check if BurnFulfillment fulfills the burn condition
+		err = cbtc.BurnCondition.Fulfill(*cbtx.BurnFulfillment, types.FulfillContext{
+			ExtraObjects: []interface{}{CoinBurnSignatureSpecifier},
+			BlockHeight:  ctx.BlockHeight,
+			BlockTime:    ctx.BlockTime,
+			Transaction:  t,
+		})
+		if err != nil {
+			return fmt.Errorf("unauthorized coin burn tx: failed to fulfill burn condition: %v", err)
+		}
+	}
+
+	// validate the miner fee
+	if cbtx.TransactionFee.Cmp(constants.MinimumMinerFee) < 0 {
+		return types.ErrTooSmallMinerFee
+	}
+
+	// prevent double spending
+	spendCoins := make(map[types.CoinOutputID]struct{})
+	for _, ci := range cbtx.CoinInputs {
+		if _, found := spendCoins[ci.ParentID]; found {
+			return types.ErrDoubleSpend
+		}
+		spendCoins[ci.ParentID] = struct{}{}
+	}
+
+	// check if optional coin output is using standard condition
+	if cbtx.RefundCoinOutput != nil {
+		err = cbtx.RefundCoinOutput.Condition.IsStandardCondition(ctx)
+		if err != nil {
+			return err
+		}
+		// ensure the value is not 0
+		if cbtx.RefundCoinOutput.Value.IsZero() {
+			return types.ErrZeroOutput
+		}
+	}
+	// check if all fulfillments are standard
+	for _, sci := range cbtx.CoinInputs {
+		err = sci.Fulfillment.IsStandardFulfillment(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Tx is valid
+	return nil
+}
+
+// ValidateCoinOutputs implements CoinOutputValidator.ValidateCoinOutputs,
+// implemented here, overwriting the default logic, as the burned value is not registered as a coin output,
+// instead those coins are destroyed
+func (cbtc CoinBurnTransactionController) ValidateCoinOutputs(t types.Transaction, ctx types.FundValidationContext, coinInputs map[types.CoinOutputID]types.CoinOutput) error {
+	cbtx, err := CoinBurnTransactionFromTransaction(t)
+	if err != nil {
+		return fmt.Errorf("failed to convert Tx to a CoinBurnTx: %v", err)
+	}
+
+	var inputSum types.Currency
+	for index, sci := range cbtx.CoinInputs {
+		sco, ok := coinInputs[sci.ParentID]
+		if !ok {
+			return types.MissingCoinOutputError{ID: sci.ParentID}
+		}
+		// check if the referenced output's condition has been fulfilled
+		err = sco.Condition.Fulfill(sci.Fulfillment, types.FulfillContext{
+			ExtraObjects: []interface{}{uint64(index)},
+			BlockHeight:  ctx.BlockHeight,
+			BlockTime:    ctx.BlockTime,
+			Transaction:  t,
+		})
+		if err != nil {
+			return err
+		}
+		inputSum = inputSum.Add(sco.Value)
+	}
+
+	expectedTotal := cbtx.TransactionFee.Add(cbtx.Value)
+	if cbtx.RefundCoinOutput != nil {
+		expectedTotal = expectedTotal.Add(cbtx.RefundCoinOutput.Value)
+	}
+	if !inputSum.Equals(expectedTotal) {
+		return types.ErrCoinInputOutputMismatch
+	}
+	return nil
+}
+
+// ValidateBlockStakeOutputs implements BlockStakeOutputValidator.ValidateBlockStakeOutputs
+func (cbtc CoinBurnTransactionController) ValidateBlockStakeOutputs(t types.Transaction, ctx types.FundValidationContext, blockStakeInputs map[types.BlockStakeOutputID]types.BlockStakeOutput) (err error) {
+	return nil // always valid, no block stake inputs/outputs exist within a coin burn transaction
+}
+
+// SignatureHash implements TransactionSignatureHasher.SignatureHash
+func (cbtc CoinBurnTransactionController) SignatureHash(t types.Transaction, extraObjects ...interface{}) (crypto.Hash, error) {
+	cbtx, err := CoinBurnTransactionFromTransaction(t)
+	if err != nil {
+		return crypto.Hash{}, fmt.Errorf("failed to use tx as a CoinBurnTx: %v", err)
+	}
+
+	h := crypto.NewHash()
+	enc := rivbin.NewEncoder(h)
+
+	enc.EncodeAll(
+		t.Version,
+		SpecifierCoinBurnTransaction,
+		cbtx.Value,
+		cbtx.Reason,
+	)
+
+	if len(extraObjects) > 0 {
+		enc.EncodeAll(extraObjects...)
+	}
+
+	enc.Encode(len(cbtx.CoinInputs))
+	for _, ci := range cbtx.CoinInputs {
+		enc.Encode(ci.ParentID)
+	}
+
+	enc.EncodeAll(
+		cbtx.TransactionFee,
+		cbtx.RefundCoinOutput,
+	)
+
+	var hash crypto.Hash
+	h.Sum(hash[:0])
+	return hash, nil
+}
+
+// SignExtension implements TransactionExtensionSigner.SignExtension
+func (cbtc CoinBurnTransactionController) SignExtension(extension interface{}, sign func(*types.UnlockFulfillmentProxy, types.UnlockConditionProxy, ...interface{}) error) (interface{}, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid CoinBurnTransactionExtension
+	cbtxExtension, ok := extension.(*CoinBurnTransactionExtension)
+	if !ok {
+		return nil, errors.New("invalid extension data for a Coin Burn Transaction")
+	}
+	if cbtc.BurnCondition.ConditionType() == types.ConditionTypeNil {
+		return cbtxExtension, nil // no burn authorization is required
+	}
+
+	// sign the burn fulfillment using the burn condition
+	if cbtxExtension.BurnFulfillment == nil {
+		cbtxExtension.BurnFulfillment = &types.UnlockFulfillmentProxy{}
+	}
+	err := sign(cbtxExtension.BurnFulfillment, cbtc.BurnCondition, CoinBurnSignatureSpecifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign burn fulfillment of CoinBurnTx: %v", err)
+	}
+	return cbtxExtension, nil
+}
+
+// EncodeTransactionIDInput implements TransactionIDEncoder.EncodeTransactionIDInput
+func (cbtc CoinBurnTransactionController) EncodeTransactionIDInput(w io.Writer, txData types.TransactionData) error {
+	cbtx, err := CoinBurnTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a CoinBurnTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).EncodeAll(SpecifierCoinBurnTransaction, cbtx)
+}
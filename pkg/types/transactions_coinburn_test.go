@@ -0,0 +1,230 @@
+package types
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/threefoldfoundation/tfchain/pkg/config"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestCoinBurnTransactionBinaryEncodingAndID(t *testing.T) {
+	types.RegisterTransactionVersion(TransactionVersionCoinBurn, CoinBurnTransactionController{})
+	defer types.RegisterTransactionVersion(TransactionVersionCoinBurn, nil)
+
+	testCases := []struct {
+		JSONEncoded string
+		HexEncoded  string
+	}{
+		// coin burn without a burn fulfillment
+		{
+			`{"version":130,"data":{"value":"50000000000","reason":"YnVybiB1bmNsYWltZWQgYWlyZHJvcA==","txfee":"1000000000","coininputs":[{"parentid":"a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee563","fulfillment":{"type":1,"data":{"publickey":"ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780","signature":"dadb463f5c709f289c44c539effa8743c3612c8e846a1cd641d594c266f07841dc9298076c4339feb7ac4db45d6dafc6574bc49360b0c1b44b9121c0c4be0a04"}}}],"refundcoinoutput":{"value":"49000000000","condition":{"type":1,"data":{"unlockhash":"01370af706b547dd4e562a047e6265d7e7750771f9bff633b1a12dbd59b11712c6ef65edb1690d"}}}}}`,
+			`820a0ba43b74002c6275726e20756e636c61696d65642061697264726f7000083b9aca0002a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee56301c401d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d778080dadb463f5c709f289c44c539effa8743c3612c8e846a1cd641d594c266f07841dc9298076c4339feb7ac4db45d6dafc6574bc49360b0c1b44b9121c0c4be0a04010a0b68a0aa00014201370af706b547dd4e562a047e6265d7e7750771f9bff633b1a12dbd59b11712c6`,
+		},
+		// coin burn authorized by a burn fulfillment
+		{
+			`{"version":130,"data":{"value":"50000000000","reason":"YnVybiB1bmNsYWltZWQgYWlyZHJvcA==","burnfulfillment":{"type":1,"data":{"publickey":"ed25519:cecc1507dc1ddd7295951c290888f095adb9044d1b73d696e6df065d683bd4fc","signature":""}},"txfee":"1000000000","coininputs":[{"parentid":"a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee563","fulfillment":{"type":1,"data":{"publickey":"ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780","signature":"dadb463f5c709f289c44c539effa8743c3612c8e846a1cd641d594c266f07841dc9298076c4339feb7ac4db45d6dafc6574bc49360b0c1b44b9121c0c4be0a04"}}}],"refundcoinoutput":{"value":"49000000000","condition":{"type":1,"data":{"unlockhash":"01370af706b547dd4e562a047e6265d7e7750771f9bff633b1a12dbd59b11712c6ef65edb1690d"}}}}}`,
+			`820a0ba43b74002c6275726e20756e636c61696d65642061697264726f7001014401cecc1507dc1ddd7295951c290888f095adb9044d1b73d696e6df065d683bd4fc00083b9aca0002a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee56301c401d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d778080dadb463f5c709f289c44c539effa8743c3612c8e846a1cd641d594c266f07841dc9298076c4339feb7ac4db45d6dafc6574bc49360b0c1b44b9121c0c4be0a04010a0b68a0aa00014201370af706b547dd4e562a047e6265d7e7750771f9bff633b1a12dbd59b11712c6`,
+		},
+	}
+	for idx, testCase := range testCases {
+		var tx types.Transaction
+		err := json.Unmarshal([]byte(testCase.JSONEncoded), &tx)
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		id := tx.ID()
+		b := siabin.Marshal(tx)
+		if output := hex.EncodeToString(b); output != testCase.HexEncoded {
+			t.Fatal(idx, testCase.HexEncoded, "!=", output)
+		}
+
+		// go to coin burn Tx and back
+		cbtx, err := CoinBurnTransactionFromTransaction(tx)
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		oTx := cbtx.Transaction()
+		oID := oTx.ID()
+		oB := siabin.Marshal(oTx)
+		if id != oID {
+			t.Fatal(idx, id, "!=", oID)
+		}
+		if !bytes.Equal(b, oB) {
+			t.Fatal(idx, hex.EncodeToString(b), "!=", hex.EncodeToString(oB))
+		}
+
+		// binary decode it again, resulting in the same JSON-encoded transaction
+		var decodedTx types.Transaction
+		err = siabin.Unmarshal(oB, &decodedTx)
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		b, err = json.Marshal(decodedTx)
+		if err != nil {
+			t.Fatal(idx, err)
+		}
+		if output := string(b); output != testCase.JSONEncoded {
+			t.Fatal(idx, testCase.JSONEncoded, "!=", output)
+		}
+	}
+}
+
+func TestCoinBurnTransactionValidation(t *testing.T) {
+	ownerKey := hsk("788c0aaeec8e0d916a712535826fa2d47d19fd7b341242f05de0d2e6e7e06104d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780")
+	ownerCondition := types.NewCondition(types.NewUnlockHashCondition(types.NewPubKeyUnlockHash(cryptoKeyPair.PublicKey)))
+	otherKeyPair := newTestKeyPair(1)
+
+	chainConstants := config.GetDevnetGenesis()
+	validationConstants := types.TransactionValidationConstants{
+		BlockSizeLimit:         chainConstants.BlockSizeLimit,
+		ArbitraryDataSizeLimit: chainConstants.ArbitraryDataSizeLimit,
+		MinimumMinerFee:        chainConstants.MinimumTransactionFee,
+	}
+	const unsignedJSONEncodedTx = `{
+	"version": 130,
+	"data": {
+		"value": "50000000000",
+		"reason": "YnVybiB1bmNsYWltZWQgYWlyZHJvcA==",
+		"txfee": "1000000000",
+		"coininputs": [{
+			"parentid": "a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee563",
+			"fulfillment": {
+				"type": 1,
+				"data": {
+					"publickey": "ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780",
+					"signature": ""
+				}
+			}
+		}],
+		"refundcoinoutput": {
+			"value": "49000000000",
+			"condition": {
+				"type": 1,
+				"data": {
+					"unlockhash": "01370af706b547dd4e562a047e6265d7e7750771f9bff633b1a12dbd59b11712c6ef65edb1690d"
+				}
+			}
+		}
+	}
+}`
+	// decode the unsigned coin burn, such that each test case can modify it prior to signing
+	decodeTx := func() CoinBurnTransaction {
+		t.Helper()
+		types.RegisterTransactionVersion(TransactionVersionCoinBurn, CoinBurnTransactionController{})
+		defer types.RegisterTransactionVersion(TransactionVersionCoinBurn, nil)
+
+		var tx types.Transaction
+		err := tx.UnmarshalJSON([]byte(unsignedJSONEncodedTx))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cbtx, err := CoinBurnTransactionFromTransaction(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cbtx
+	}
+	coinInputs := map[types.CoinOutputID]types.CoinOutput{
+		types.CoinOutputID(hs("a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee563")): {
+			Value:     types.NewCurrency64(100000000000),
+			Condition: ownerCondition,
+		},
+	}
+	signAndValidate := func(cbtx CoinBurnTransaction, burnCondition types.UnlockConditionProxy, burnKey types.KeyPair) error {
+		t.Helper()
+		types.RegisterTransactionVersion(TransactionVersionCoinBurn, CoinBurnTransactionController{
+			BurnCondition: burnCondition,
+		})
+		defer types.RegisterTransactionVersion(TransactionVersionCoinBurn, nil)
+
+		tx := cbtx.Transaction()
+		err := tx.SignExtension(func(fulfillment *types.UnlockFulfillmentProxy, condition types.UnlockConditionProxy, eo ...interface{}) error {
+			*fulfillment = types.NewFulfillment(types.NewSingleSignatureFulfillment(burnKey.PublicKey))
+			return fulfillment.Sign(types.FulfillmentSignContext{
+				ExtraObjects: eo,
+				Transaction:  tx,
+				Key:          burnKey.PrivateKey,
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("failed to sign: %v", err)
+		}
+		err = tx.CoinInputs[0].Fulfillment.Sign(types.FulfillmentSignContext{
+			ExtraObjects: []interface{}{uint64(0)},
+			Transaction:  tx,
+			Key:          ownerKey,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to sign coin input: %v", err)
+		}
+		err = tx.ValidateTransaction(types.ValidationContext{
+			Confirmed:   true,
+			BlockHeight: 4072,
+			BlockTime:   1534271219,
+		}, validationConstants)
+		if err != nil {
+			return err
+		}
+		return tx.ValidateCoinOutputs(types.FundValidationContext{
+			BlockHeight: 4072,
+			BlockTime:   1534271219,
+		}, coinInputs)
+	}
+
+	// without a burn condition, anyone can burn their own coins
+	err := signAndValidate(decodeTx(), types.UnlockConditionProxy{}, types.KeyPair{})
+	if err != nil {
+		t.Fatalf("failed to validate valid coin burn: %v", err)
+	}
+	// with a burn condition, the burn has to be authorized
+	burnCondition := types.NewCondition(types.NewUnlockHashCondition(types.NewPubKeyUnlockHash(otherKeyPair.PublicKey)))
+	err = signAndValidate(decodeTx(), burnCondition, otherKeyPair)
+	if err != nil {
+		t.Fatalf("failed to validate authorized coin burn: %v", err)
+	}
+	err = signAndValidate(decodeTx(), burnCondition, cryptoKeyPair)
+	if err == nil {
+		t.Error("succeeded to validate coin burn signed by an unauthorized key")
+	}
+
+	// the reason is mandatory and limited in length
+	cbtx := decodeTx()
+	cbtx.Reason = nil
+	err = signAndValidate(cbtx, types.UnlockConditionProxy{}, types.KeyPair{})
+	if err == nil {
+		t.Error("succeeded to validate coin burn without a reason")
+	}
+	cbtx.Reason = make([]byte, MaxCoinBurnReasonLength+1)
+	err = signAndValidate(cbtx, types.UnlockConditionProxy{}, types.KeyPair{})
+	if err == nil {
+		t.Error("succeeded to validate coin burn with a too long reason")
+	}
+
+	// the burned value has to be defined and match the coin inputs
+	cbtx = decodeTx()
+	cbtx.Value = types.Currency{}
+	cbtx.RefundCoinOutput.Value = types.NewCurrency64(99000000000)
+	err = signAndValidate(cbtx, types.UnlockConditionProxy{}, types.KeyPair{})
+	if err == nil {
+		t.Error("succeeded to validate coin burn that burns no coins")
+	}
+	cbtx = decodeTx()
+	cbtx.Value = cbtx.Value.Add(types.NewCurrency64(1))
+	err = signAndValidate(cbtx, types.UnlockConditionProxy{}, types.KeyPair{})
+	if err != types.ErrCoinInputOutputMismatch {
+		t.Error("unexpected error while validating coin burn that burns more than its inputs:", err)
+	}
+
+	// too small fee, should fail
+	cbtx = decodeTx()
+	cbtx.TransactionFee = types.NewCurrency64(1)
+	err = signAndValidate(cbtx, types.UnlockConditionProxy{}, types.KeyPair{})
+	if err == nil {
+		t.Error("succeeded to validate coin burn with a too small fee")
+	}
+}
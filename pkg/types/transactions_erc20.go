@@ -41,8 +41,9 @@ var (
 )
 
 var (
-	// ERC20ConversionMinimumValue defines the minimum value of TFT
-	// you can convert to ERC20 funds using the ERC20ConvertTransaction
+	// ERC20ConversionMinimumValue defines the genesis minimum value of TFT
+	// you can convert to ERC20 funds using the ERC20ConvertTransaction,
+	// which can be updated on-chain using a ConsensusParameterUpdate Transaction.
 	ERC20ConversionMinimumValue = config.GetCurrencyUnits().OneCoin.Mul64(1000)
 )
 
@@ -354,7 +355,11 @@ func (etctx *ERC20ConvertTransaction) UnmarshalRivine(r io.Reader) error {
 type (
 	// ERC20ConvertTransactionController defines a tfchain-specific transaction controller,
 	// for a transaction type reserved at type 0xD0. It allows the conversion of TFT to ERC20-funds.
-	ERC20ConvertTransactionController struct{}
+	ERC20ConvertTransactionController struct {
+		// ConsensusParameterGetter is used to get the consensus parameters at the context-defined block height,
+		// which define the minimum value of TFT that can be converted.
+		ConsensusParameterGetter ConsensusParameterGetter
+	}
 )
 
 var (
@@ -425,8 +430,12 @@ func (etctc ERC20ConvertTransactionController) ValidateTransaction(t types.Trans
 	}
 
 	// ensure the value is a valid minimum
-	if etctx.Value.Cmp(ERC20ConversionMinimumValue) < 0 {
-		return errors.New("ERC20 requires a minimum value of 1000 TFT to be converted")
+	params, err := getConsensusParametersForContext(etctc.ConsensusParameterGetter, ctx)
+	if err != nil {
+		return err
+	}
+	if etctx.Value.Cmp(params.ERC20ConversionMinimum) < 0 {
+		return fmt.Errorf("ERC20 requires a minimum value of %s to be converted", params.ERC20ConversionMinimum.String())
 	}
 
 	// validate the miner fee
@@ -949,8 +958,9 @@ func (etctc ERC20CoinCreationTransactionController) EncodeTransactionIDInput(w i
 
 const (
 	// HardcodedERC20AddressRegistrationFeeOneCoinMultiplier defines the hardcoded multiplier
-	// (to be multiplied with the OneCoin Currency Value of the network), that defines the constant (hardcoded)
-	// Registration Fee to be paid for the Registration of an ERC20 Withdrawal address.
+	// (to be multiplied with the OneCoin Currency Value of the network), that defines the genesis
+	// Registration Fee to be paid for the Registration of an ERC20 Withdrawal address,
+	// which can be updated on-chain using a ConsensusParameterUpdate Transaction.
 	HardcodedERC20AddressRegistrationFeeOneCoinMultiplier = 10
 )
 
@@ -1225,9 +1235,11 @@ type (
 	// ERC20AddressRegistrationTransactionController defines a tfchain-specific transaction controller,
 	// for a transaction type reserved at type 0xD2. It allows the registration of an ERC20 Address.
 	ERC20AddressRegistrationTransactionController struct {
-		Registry             ERC20Registry
-		OneCoin              types.Currency
-		BridgeFeePoolAddress types.UnlockHash
+		Registry ERC20Registry
+		// ConsensusParameterGetter is used to get the consensus parameters at the context-defined block height,
+		// which define the registration fee to be paid.
+		ConsensusParameterGetter ConsensusParameterGetter
+		BridgeFeePoolAddress     types.UnlockHash
 	}
 )
 
@@ -1335,7 +1347,11 @@ func (eartc ERC20AddressRegistrationTransactionController) ValidateTransaction(t
 	}
 
 	// validate the registration fee
-	if eartx.RegistrationFee.Cmp(eartc.OneCoin.Mul64(HardcodedERC20AddressRegistrationFeeOneCoinMultiplier)) != 0 {
+	params, err := getConsensusParametersForContext(eartc.ConsensusParameterGetter, ctx)
+	if err != nil {
+		return err
+	}
+	if eartx.RegistrationFee.Cmp(params.ERC20AddressRegistrationFee) != 0 {
 		return errors.New("invalid ERC20 Address Registration fee")
 	}
 
@@ -0,0 +1,954 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+const (
+	// TransactionVersionFarmRegistration defines the Transaction version
+	// for a FarmRegistration Transaction, used to register a new farm,
+	// which can only be done by the foundation.
+	TransactionVersionFarmRegistration types.TransactionVersion = iota + 161
+	// TransactionVersionFarmUpdate defines the Transaction version
+	// for a FarmUpdate Transaction, used to update a farm record by its owner.
+	TransactionVersionFarmUpdate
+)
+
+// These Specifiers are used internally when calculating a Transaction's ID.
+// See Rivine's Specifier for more details.
+var (
+	SpecifierFarmRegistrationTransaction = types.Specifier{'f', 'a', 'r', 'm', ' ', 'r', 'e', 'g', 'i', 's', 't', 'e', 'r', ' ', 't', 'x'}
+	SpecifierFarmUpdateTransaction       = types.Specifier{'f', 'a', 'r', 'm', ' ', 'u', 'p', 'd', 'a', 't', 'e', ' ', 't', 'x'}
+)
+
+// Specifiers used to ensure the farm-related signatures are unique within each Tx.
+var (
+	FarmSignatureSpecifierFoundation = [...]byte{'f', 'o', 'u', 'n', 'd', 'a', 't', 'i', 'o', 'n'}
+	FarmSignatureSpecifierOwner      = [...]byte{'o', 'w', 'n', 'e', 'r'}
+)
+
+// Farm validation errors
+var (
+	ErrFarmNameAlreadyRegistered = errors.New("farm name is already registered")
+)
+
+type (
+	// FoundationConditionGetter allows you to get the foundation condition at a given block height.
+	// The foundation condition has to be fulfilled in order to register new farms.
+	//
+	// For the daemon this interface is implemented directly by the TransactionDB,
+	// while for a client this could come via the REST API from a tfchain daemon in a more indirect way.
+	FoundationConditionGetter interface {
+		// GetActiveFoundationCondition returns the active foundation condition.
+		GetActiveFoundationCondition() (types.UnlockConditionProxy, error)
+		// GetFoundationConditionAt returns the foundation condition at a given block height.
+		GetFoundationConditionAt(height types.BlockHeight) (types.UnlockConditionProxy, error)
+	}
+
+	// FarmRecordReadRegistry defines the public READ API expected from a farm record Read-Only registry.
+	FarmRecordReadRegistry interface {
+		// GetFarm returns the record mapped to the given FarmID.
+		GetFarm(id FarmID) (*FarmRecord, error)
+		// GetFarmForName returns the record mapped to the given farm name.
+		GetFarmForName(name string) (*FarmRecord, error)
+	}
+)
+
+// public FarmRecordReadRegistry errors
+var (
+	ErrFarmNameNotFound = errors.New("farm name not found")
+)
+
+type (
+	// FarmRegistrationTransaction defines the Transaction (with version 0xA1)
+	// used to register a new farm. It can only be created by the foundation.
+	FarmRegistrationTransaction struct {
+		// Name of the farm, has to be unique for all farms.
+		Name string `json:"name"`
+		// Owner defines the condition that has to be fulfilled in order to update the farm.
+		// It has to be either an UnlockHash or a MultiSignature condition.
+		Owner types.UnlockConditionProxy `json:"owner"`
+		// AuthorizedAddresses defines the (public key) addresses that are authorized to
+		// register capacity for the farm, at least one and maximum 10 addresses have to be defined.
+		AuthorizedAddresses []types.UnlockHash `json:"authorizedaddresses"`
+
+		// FoundationFulfillment defines the fulfillment which is used in order to
+		// fulfill the globally defined foundation condition.
+		FoundationFulfillment types.UnlockFulfillmentProxy `json:"foundationfulfillment"`
+
+		// TransactionFee defines the regular Tx fee.
+		TransactionFee types.Currency `json:"txfee"`
+
+		// CoinInputs are only used for the required fees,
+		// at least one CoinInput is required.
+		CoinInputs []types.CoinInput `json:"coininputs"`
+		// RefundCoinOutput is an optional coin output that can be used
+		// to refund coins paid as inputs for the required fees.
+		RefundCoinOutput *types.CoinOutput `json:"refundcoinoutput,omitempty"`
+	}
+	// FarmRegistrationTransactionExtension defines the FarmRegistrationTransaction Extension Data
+	FarmRegistrationTransactionExtension struct {
+		Name                  string
+		Owner                 types.UnlockConditionProxy
+		AuthorizedAddresses   []types.UnlockHash
+		FoundationFulfillment types.UnlockFulfillmentProxy
+	}
+)
+
+// FarmRegistrationTransactionFromTransaction creates a FarmRegistrationTransaction,
+// using a regular in-memory tfchain transaction.
+//
+// Past the (tx) Version validation it piggy-backs onto the
+// `FarmRegistrationTransactionFromTransactionData` constructor.
+func FarmRegistrationTransactionFromTransaction(tx types.Transaction) (FarmRegistrationTransaction, error) {
+	if tx.Version != TransactionVersionFarmRegistration {
+		return FarmRegistrationTransaction{}, fmt.Errorf(
+			"a farm registration transaction requires tx version %d",
+			TransactionVersionFarmRegistration)
+	}
+	return FarmRegistrationTransactionFromTransactionData(types.TransactionData{
+		CoinInputs:        tx.CoinInputs,
+		CoinOutputs:       tx.CoinOutputs,
+		BlockStakeInputs:  tx.BlockStakeInputs,
+		BlockStakeOutputs: tx.BlockStakeOutputs,
+		MinerFees:         tx.MinerFees,
+		ArbitraryData:     tx.ArbitraryData,
+		Extension:         tx.Extension,
+	})
+}
+
+// FarmRegistrationTransactionFromTransactionData creates a FarmRegistrationTransaction,
+// using the TransactionData from a regular in-memory tfchain transaction.
+func FarmRegistrationTransactionFromTransactionData(txData types.TransactionData) (FarmRegistrationTransaction, error) {
+	// validate the Transaction Data
+	err := validateFarmInMemoryTransactionDataRequirements(txData)
+	if err != nil {
+		return FarmRegistrationTransaction{}, fmt.Errorf("FarmRegistrationTransaction: %v", err)
+	}
+
+	// (tx) extension (data) is expected to be a pointer to a valid FarmRegistrationTransactionExtension,
+	// which contains all the properties unique to a farm (registration) Tx
+	extensionData, ok := txData.Extension.(*FarmRegistrationTransactionExtension)
+	if !ok {
+		return FarmRegistrationTransaction{}, errors.New("invalid extension data for a FarmRegistrationTransaction")
+	}
+
+	// create the FarmRegistrationTransaction and return it,
+	// further validation will/has-to be done using the Transaction Type, if required
+	tx := FarmRegistrationTransaction{
+		Name:                  extensionData.Name,
+		Owner:                 extensionData.Owner,
+		AuthorizedAddresses:   extensionData.AuthorizedAddresses,
+		FoundationFulfillment: extensionData.FoundationFulfillment,
+		TransactionFee:        txData.MinerFees[0],
+		CoinInputs:            txData.CoinInputs,
+	}
+	if len(txData.CoinOutputs) == 1 {
+		// take refund coin output
+		tx.RefundCoinOutput = &txData.CoinOutputs[0]
+	}
+	return tx, nil
+}
+
+// TransactionData returns this FarmRegistrationTransaction
+// as regular tfchain transaction data.
+func (frtx *FarmRegistrationTransaction) TransactionData() types.TransactionData {
+	txData := types.TransactionData{
+		CoinInputs: frtx.CoinInputs,
+		MinerFees:  []types.Currency{frtx.TransactionFee},
+		Extension: &FarmRegistrationTransactionExtension{
+			Name:                  frtx.Name,
+			Owner:                 frtx.Owner,
+			AuthorizedAddresses:   frtx.AuthorizedAddresses,
+			FoundationFulfillment: frtx.FoundationFulfillment,
+		},
+	}
+	if frtx.RefundCoinOutput != nil {
+		txData.CoinOutputs = append(txData.CoinOutputs, *frtx.RefundCoinOutput)
+	}
+	return txData
+}
+
+// Transaction returns this FarmRegistrationTransaction
+// as regular tfchain transaction, using TransactionVersionFarmRegistration as the type.
+func (frtx *FarmRegistrationTransaction) Transaction() types.Transaction {
+	tx := types.Transaction{
+		Version:    TransactionVersionFarmRegistration,
+		CoinInputs: frtx.CoinInputs,
+		MinerFees:  []types.Currency{frtx.TransactionFee},
+		Extension: &FarmRegistrationTransactionExtension{
+			Name:                  frtx.Name,
+			Owner:                 frtx.Owner,
+			AuthorizedAddresses:   frtx.AuthorizedAddresses,
+			FoundationFulfillment: frtx.FoundationFulfillment,
+		},
+	}
+	if frtx.RefundCoinOutput != nil {
+		tx.CoinOutputs = append(tx.CoinOutputs, *frtx.RefundCoinOutput)
+	}
+	return tx
+}
+
+// FarmRecord returns the farm record as it is to be created by this FarmRegistrationTransaction,
+// using the given FarmID, as assigned by the TransactionDB.
+func (frtx *FarmRegistrationTransaction) FarmRecord(id FarmID) (FarmRecord, error) {
+	record := FarmRecord{
+		ID:    id,
+		Name:  frtx.Name,
+		Owner: frtx.Owner,
+	}
+	err := record.AddAuthorizedAddresses(frtx.AuthorizedAddresses...)
+	if err != nil {
+		return FarmRecord{}, err
+	}
+	return record, nil
+}
+
+// MarshalSia implements SiaMarshaler.MarshalSia,
+// alias of MarshalRivine for backwards-compatibility reasons.
+func (frtx FarmRegistrationTransaction) MarshalSia(w io.Writer) error {
+	return frtx.MarshalRivine(w)
+}
+
+// UnmarshalSia implements SiaUnmarshaler.UnmarshalSia,
+// alias of UnmarshalRivine for backwards-compatibility reasons.
+func (frtx *FarmRegistrationTransaction) UnmarshalSia(r io.Reader) error {
+	return frtx.UnmarshalRivine(r)
+}
+
+// MarshalRivine implements RivineMarshaler.MarshalRivine
+func (frtx FarmRegistrationTransaction) MarshalRivine(w io.Writer) error {
+	return rivbin.NewEncoder(w).EncodeAll(
+		frtx.Name,
+		frtx.Owner,
+		frtx.AuthorizedAddresses,
+		frtx.FoundationFulfillment,
+		frtx.TransactionFee,
+		frtx.CoinInputs,
+		frtx.RefundCoinOutput,
+	)
+}
+
+// UnmarshalRivine implements RivineUnmarshaler.UnmarshalRivine
+func (frtx *FarmRegistrationTransaction) UnmarshalRivine(r io.Reader) error {
+	return rivbin.NewDecoder(r).DecodeAll(
+		&frtx.Name,
+		&frtx.Owner,
+		&frtx.AuthorizedAddresses,
+		&frtx.FoundationFulfillment,
+		&frtx.TransactionFee,
+		&frtx.CoinInputs,
+		&frtx.RefundCoinOutput,
+	)
+}
+
+type (
+	// FarmUpdateTransaction defines the Transaction (with version 0xA2)
+	// used to update a farm record by its owner.
+	FarmUpdateTransaction struct {
+		// Identifier of the farm, used to find the farm record to be updated,
+		// and verify that the Tx is authorized to do so.
+		Identifier FarmID `json:"id"`
+
+		// Name is an optional new name for the farm,
+		// which has to be unique for all farms.
+		Name string `json:"name,omitempty"`
+
+		// AuthorizedAddresses can be used to add and/or remove authorized addresses
+		// to/from the existing farm record. Note that after each Tx,
+		// at least one and no more than 10 addresses can be authorized for a farm.
+		AuthorizedAddresses FarmAuthorizedAddressesUpdate `json:"authorizedaddresses,omitempty"`
+
+		// OwnerFulfillment defines the fulfillment which is used in order to
+		// fulfill the owner condition of the farm to be updated.
+		OwnerFulfillment types.UnlockFulfillmentProxy `json:"ownerfulfillment"`
+
+		// TransactionFee defines the regular Tx fee.
+		TransactionFee types.Currency `json:"txfee"`
+
+		// CoinInputs are only used for the required fees,
+		// at least one CoinInput is required.
+		CoinInputs []types.CoinInput `json:"coininputs"`
+		// RefundCoinOutput is an optional coin output that can be used
+		// to refund coins paid as inputs for the required fees.
+		RefundCoinOutput *types.CoinOutput `json:"refundcoinoutput,omitempty"`
+	}
+	// FarmAuthorizedAddressesUpdate contains all information required for an update
+	// to the authorized addresses of a farm's record.
+	FarmAuthorizedAddressesUpdate struct {
+		Add    []types.UnlockHash `json:"add,omitempty"`
+		Remove []types.UnlockHash `json:"remove,omitempty"`
+	}
+	// FarmUpdateTransactionExtension defines the FarmUpdateTransaction Extension Data
+	FarmUpdateTransactionExtension struct {
+		Identifier       FarmID
+		Name             string
+		AddressUpdate    FarmAuthorizedAddressesUpdate
+		OwnerFulfillment types.UnlockFulfillmentProxy
+	}
+)
+
+// FarmUpdateTransactionFromTransaction creates a FarmUpdateTransaction,
+// using a regular in-memory tfchain transaction.
+//
+// Past the (tx) Version validation it piggy-backs onto the
+// `FarmUpdateTransactionFromTransactionData` constructor.
+func FarmUpdateTransactionFromTransaction(tx types.Transaction) (FarmUpdateTransaction, error) {
+	if tx.Version != TransactionVersionFarmUpdate {
+		return FarmUpdateTransaction{}, fmt.Errorf(
+			"a farm update transaction requires tx version %d",
+			TransactionVersionFarmUpdate)
+	}
+	return FarmUpdateTransactionFromTransactionData(types.TransactionData{
+		CoinInputs:        tx.CoinInputs,
+		CoinOutputs:       tx.CoinOutputs,
+		BlockStakeInputs:  tx.BlockStakeInputs,
+		BlockStakeOutputs: tx.BlockStakeOutputs,
+		MinerFees:         tx.MinerFees,
+		ArbitraryData:     tx.ArbitraryData,
+		Extension:         tx.Extension,
+	})
+}
+
+// FarmUpdateTransactionFromTransactionData creates a FarmUpdateTransaction,
+// using the TransactionData from a regular in-memory tfchain transaction.
+func FarmUpdateTransactionFromTransactionData(txData types.TransactionData) (FarmUpdateTransaction, error) {
+	// validate the Transaction Data
+	err := validateFarmInMemoryTransactionDataRequirements(txData)
+	if err != nil {
+		return FarmUpdateTransaction{}, fmt.Errorf("FarmUpdateTransaction: %v", err)
+	}
+
+	// (tx) extension (data) is expected to be a pointer to a valid FarmUpdateTransactionExtension,
+	// which contains all the properties unique to a farm (update) Tx
+	extensionData, ok := txData.Extension.(*FarmUpdateTransactionExtension)
+	if !ok {
+		return FarmUpdateTransaction{}, errors.New("invalid extension data for a FarmUpdateTransaction")
+	}
+
+	// create the FarmUpdateTransaction and return it,
+	// further validation will/has-to be done using the Transaction Type, if required
+	tx := FarmUpdateTransaction{
+		Identifier:          extensionData.Identifier,
+		Name:                extensionData.Name,
+		AuthorizedAddresses: extensionData.AddressUpdate,
+		OwnerFulfillment:    extensionData.OwnerFulfillment,
+		TransactionFee:      txData.MinerFees[0],
+		CoinInputs:          txData.CoinInputs,
+	}
+	if len(txData.CoinOutputs) == 1 {
+		// take refund coin output
+		tx.RefundCoinOutput = &txData.CoinOutputs[0]
+	}
+	return tx, nil
+}
+
+// TransactionData returns this FarmUpdateTransaction
+// as regular tfchain transaction data.
+func (futx *FarmUpdateTransaction) TransactionData() types.TransactionData {
+	txData := types.TransactionData{
+		CoinInputs: futx.CoinInputs,
+		MinerFees:  []types.Currency{futx.TransactionFee},
+		Extension: &FarmUpdateTransactionExtension{
+			Identifier:       futx.Identifier,
+			Name:             futx.Name,
+			AddressUpdate:    futx.AuthorizedAddresses,
+			OwnerFulfillment: futx.OwnerFulfillment,
+		},
+	}
+	if futx.RefundCoinOutput != nil {
+		txData.CoinOutputs = append(txData.CoinOutputs, *futx.RefundCoinOutput)
+	}
+	return txData
+}
+
+// Transaction returns this FarmUpdateTransaction
+// as regular tfchain transaction, using TransactionVersionFarmUpdate as the type.
+func (futx *FarmUpdateTransaction) Transaction() types.Transaction {
+	tx := types.Transaction{
+		Version:    TransactionVersionFarmUpdate,
+		CoinInputs: futx.CoinInputs,
+		MinerFees:  []types.Currency{futx.TransactionFee},
+		Extension: &FarmUpdateTransactionExtension{
+			Identifier:       futx.Identifier,
+			Name:             futx.Name,
+			AddressUpdate:    futx.AuthorizedAddresses,
+			OwnerFulfillment: futx.OwnerFulfillment,
+		},
+	}
+	if futx.RefundCoinOutput != nil {
+		tx.CoinOutputs = append(tx.CoinOutputs, *futx.RefundCoinOutput)
+	}
+	return tx
+}
+
+// UpdateFarmRecord updates the given farm record, using the properties of this FarmUpdateTransaction.
+func (futx *FarmUpdateTransaction) UpdateFarmRecord(record *FarmRecord) error {
+	if record.ID != futx.Identifier {
+		return fmt.Errorf("farm update is meant for farm %d, not farm %d", futx.Identifier, record.ID)
+	}
+	if futx.Name != "" {
+		record.Name = futx.Name
+	}
+	// remove addresses first, such that an address can be removed and added again,
+	// and such that the maximum is only checked against the final amount of addresses
+	err := record.RemoveAuthorizedAddresses(futx.AuthorizedAddresses.Remove...)
+	if err != nil {
+		return err
+	}
+	err = record.AddAuthorizedAddresses(futx.AuthorizedAddresses.Add...)
+	if err != nil {
+		return err
+	}
+	if len(record.AuthorizedAddresses) == 0 {
+		return ErrNoAuthorizedAddresses
+	}
+	return nil
+}
+
+// MarshalSia implements SiaMarshaler.MarshalSia,
+// alias of MarshalRivine for backwards-compatibility reasons.
+func (futx FarmUpdateTransaction) MarshalSia(w io.Writer) error {
+	return futx.MarshalRivine(w)
+}
+
+// UnmarshalSia implements SiaUnmarshaler.UnmarshalSia,
+// alias of UnmarshalRivine for backwards-compatibility reasons.
+func (futx *FarmUpdateTransaction) UnmarshalSia(r io.Reader) error {
+	return futx.UnmarshalRivine(r)
+}
+
+// MarshalRivine implements RivineMarshaler.MarshalRivine
+func (futx FarmUpdateTransaction) MarshalRivine(w io.Writer) error {
+	return rivbin.NewEncoder(w).EncodeAll(
+		futx.Identifier,
+		futx.Name,
+		futx.AuthorizedAddresses.Add,
+		futx.AuthorizedAddresses.Remove,
+		futx.OwnerFulfillment,
+		futx.TransactionFee,
+		futx.CoinInputs,
+		futx.RefundCoinOutput,
+	)
+}
+
+// UnmarshalRivine implements RivineUnmarshaler.UnmarshalRivine
+func (futx *FarmUpdateTransaction) UnmarshalRivine(r io.Reader) error {
+	return rivbin.NewDecoder(r).DecodeAll(
+		&futx.Identifier,
+		&futx.Name,
+		&futx.AuthorizedAddresses.Add,
+		&futx.AuthorizedAddresses.Remove,
+		&futx.OwnerFulfillment,
+		&futx.TransactionFee,
+		&futx.CoinInputs,
+		&futx.RefundCoinOutput,
+	)
+}
+
+// Farm Tx controllers
+
+type (
+	// FarmRegistrationTransactionController defines a tfchain-specific transaction controller,
+	// for a transaction type reserved at type 0xA1. It allows the registration of a new farm.
+	FarmRegistrationTransactionController struct {
+		// FoundationConditionGetter is used to get the foundation condition,
+		// which has to be fulfilled in order to register a farm.
+		FoundationConditionGetter FoundationConditionGetter
+		// Registry is used to ensure the farm name is still available.
+		Registry FarmRecordReadRegistry
+	}
+)
+
+var (
+	// ensure at compile time that FarmRegistrationTransactionController
+	// implements the desired interfaces
+	_ types.TransactionController      = FarmRegistrationTransactionController{}
+	_ types.TransactionExtensionSigner = FarmRegistrationTransactionController{}
+	_ types.TransactionValidator       = FarmRegistrationTransactionController{}
+	_ types.BlockStakeOutputValidator  = FarmRegistrationTransactionController{}
+	_ types.TransactionSignatureHasher = FarmRegistrationTransactionController{}
+	_ types.TransactionIDEncoder       = FarmRegistrationTransactionController{}
+)
+
+// EncodeTransactionData implements TransactionController.EncodeTransactionData
+func (frtc FarmRegistrationTransactionController) EncodeTransactionData(w io.Writer, txData types.TransactionData) error {
+	frtx, err := FarmRegistrationTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a FarmRegistrationTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).Encode(frtx)
+}
+
+// DecodeTransactionData implements TransactionController.DecodeTransactionData
+func (frtc FarmRegistrationTransactionController) DecodeTransactionData(r io.Reader) (types.TransactionData, error) {
+	var frtx FarmRegistrationTransaction
+	err := rivbin.NewDecoder(r).Decode(&frtx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to binary-decode tx as a FarmRegistrationTx: %v", err)
+	}
+	// return farm registration tx as regular tfchain tx data
+	return frtx.TransactionData(), nil
+}
+
+// JSONEncodeTransactionData implements TransactionController.JSONEncodeTransactionData
+func (frtc FarmRegistrationTransactionController) JSONEncodeTransactionData(txData types.TransactionData) ([]byte, error) {
+	frtx, err := FarmRegistrationTransactionFromTransactionData(txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert txData to a FarmRegistrationTx: %v", err)
+	}
+	return json.Marshal(frtx)
+}
+
+// JSONDecodeTransactionData implements TransactionController.JSONDecodeTransactionData
+func (frtc FarmRegistrationTransactionController) JSONDecodeTransactionData(data []byte) (types.TransactionData, error) {
+	var frtx FarmRegistrationTransaction
+	err := json.Unmarshal(data, &frtx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to json-decode tx as a FarmRegistrationTx: %v", err)
+	}
+	// return farm registration tx as regular tfchain tx data
+	return frtx.TransactionData(), nil
+}
+
+// SignExtension implements TransactionExtensionSigner.SignExtension
+func (frtc FarmRegistrationTransactionController) SignExtension(extension interface{}, sign func(*types.UnlockFulfillmentProxy, types.UnlockConditionProxy, ...interface{}) error) (interface{}, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid FarmRegistrationTransactionExtension
+	frtxExtension, ok := extension.(*FarmRegistrationTransactionExtension)
+	if !ok {
+		return nil, errors.New("invalid extension data for a FarmRegistrationTx")
+	}
+
+	// get the active foundation condition and use it to sign
+	foundationCondition, err := frtc.FoundationConditionGetter.GetActiveFoundationCondition()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the active foundation condition: %v", err)
+	}
+	err = sign(&frtxExtension.FoundationFulfillment, foundationCondition, FarmSignatureSpecifierFoundation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign foundation fulfillment of FarmRegistrationTx: %v", err)
+	}
+	return frtxExtension, nil
+}
+
+// ValidateTransaction implements TransactionValidator.ValidateTransaction
+func (frtc FarmRegistrationTransactionController) ValidateTransaction(t types.Transaction, ctx types.ValidationContext, constants types.TransactionValidationConstants) error {
+	// check tx fits within a block
+	err := types.TransactionFitsInABlock(t, constants.BlockSizeLimit)
+	if err != nil {
+		return err
+	}
+
+	// get FarmRegistrationTx
+	frtx, err := FarmRegistrationTransactionFromTransaction(t)
+	if err != nil {
+		return fmt.Errorf("failed to use tx as a farm registration tx: %v", err)
+	}
+
+	// get the foundation condition for the context-defined block height
+	foundationCondition, err := frtc.FoundationConditionGetter.GetFoundationConditionAt(ctx.BlockHeight)
+	if err != nil {
+		return fmt.Errorf("failed to get foundation condition at block height %d: %v", ctx.BlockHeight, err)
+	}
+	// check if FoundationFulfillment fulfills the foundation condition
+	err = foundationCondition.Fulfill(frtx.FoundationFulfillment, types.FulfillContext{
+		ExtraObjects: []interface{}{FarmSignatureSpecifierFoundation},
+		BlockHeight:  ctx.BlockHeight,
+		BlockTime:    ctx.BlockTime,
+		Transaction:  t,
+	})
+	if err != nil {
+		return fmt.Errorf("unauthorized farm registration tx: failed to fulfill foundation condition: %v", err)
+	}
+
+	// validate the name, and ensure it is still available
+	err = ValidateFarmName(frtx.Name)
+	if err != nil {
+		return fmt.Errorf("invalid farm registration tx: %v", err)
+	}
+	_, err = frtc.Registry.GetFarmForName(frtx.Name)
+	if err == nil {
+		return ErrFarmNameAlreadyRegistered
+	}
+	if err != ErrFarmNameNotFound {
+		return fmt.Errorf("unexpected error while validating non-existence of farm name %q: %v", frtx.Name, err)
+	}
+
+	// validate the owner condition
+	err = validateFarmOwnerCondition(frtx.Owner)
+	if err != nil {
+		return fmt.Errorf("invalid farm registration tx: %v", err)
+	}
+
+	// validate the authorized addresses, by creating the record that would be registered
+	if len(frtx.AuthorizedAddresses) == 0 {
+		return ErrNoAuthorizedAddresses
+	}
+	_, err = frtx.FarmRecord(MinFarmID)
+	if err != nil {
+		return fmt.Errorf("invalid farm registration tx: %v", err)
+	}
+
+	// validate the miner fee
+	if frtx.TransactionFee.Cmp(constants.MinimumMinerFee) < 0 {
+		return types.ErrTooSmallMinerFee
+	}
+
+	// prevent double spending
+	spendCoins := make(map[types.CoinOutputID]struct{})
+	for _, ci := range frtx.CoinInputs {
+		if _, found := spendCoins[ci.ParentID]; found {
+			return types.ErrDoubleSpend
+		}
+		spendCoins[ci.ParentID] = struct{}{}
+	}
+
+	// check if optional coin output is using standard condition
+	if frtx.RefundCoinOutput != nil {
+		err = frtx.RefundCoinOutput.Condition.IsStandardCondition(ctx)
+		if err != nil {
+			return err
+		}
+		// ensure the value is not 0
+		if frtx.RefundCoinOutput.Value.IsZero() {
+			return types.ErrZeroOutput
+		}
+	}
+	// check if all fulfillments are standard
+	for _, sci := range frtx.CoinInputs {
+		err = sci.Fulfillment.IsStandardFulfillment(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Tx is valid
+	return nil
+}
+
+// ValidateBlockStakeOutputs implements BlockStakeOutputValidator.ValidateBlockStakeOutputs
+func (frtc FarmRegistrationTransactionController) ValidateBlockStakeOutputs(t types.Transaction, ctx types.FundValidationContext, blockStakeInputs map[types.BlockStakeOutputID]types.BlockStakeOutput) (err error) {
+	return nil // always valid, no block stake inputs/outputs exist within a farm registration transaction
+}
+
+// SignatureHash implements TransactionSignatureHasher.SignatureHash
+func (frtc FarmRegistrationTransactionController) SignatureHash(t types.Transaction, extraObjects ...interface{}) (crypto.Hash, error) {
+	frtx, err := FarmRegistrationTransactionFromTransaction(t)
+	if err != nil {
+		return crypto.Hash{}, fmt.Errorf("failed to use tx as a FarmRegistrationTx: %v", err)
+	}
+
+	h := crypto.NewHash()
+	enc := rivbin.NewEncoder(h)
+
+	enc.EncodeAll(
+		t.Version,
+		SpecifierFarmRegistrationTransaction,
+	)
+
+	if len(extraObjects) > 0 {
+		enc.EncodeAll(extraObjects...)
+	}
+
+	enc.EncodeAll(
+		frtx.Name,
+		frtx.Owner,
+		frtx.AuthorizedAddresses,
+	)
+
+	enc.Encode(len(frtx.CoinInputs))
+	for _, ci := range frtx.CoinInputs {
+		enc.Encode(ci.ParentID)
+	}
+
+	enc.EncodeAll(
+		frtx.TransactionFee,
+		frtx.RefundCoinOutput,
+	)
+
+	var hash crypto.Hash
+	h.Sum(hash[:0])
+	return hash, nil
+}
+
+// EncodeTransactionIDInput implements TransactionIDEncoder.EncodeTransactionIDInput
+func (frtc FarmRegistrationTransactionController) EncodeTransactionIDInput(w io.Writer, txData types.TransactionData) error {
+	frtx, err := FarmRegistrationTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a FarmRegistrationTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).EncodeAll(SpecifierFarmRegistrationTransaction, frtx)
+}
+
+type (
+	// FarmUpdateTransactionController defines a tfchain-specific transaction controller,
+	// for a transaction type reserved at type 0xA2. It allows the update of the record of an existing farm.
+	FarmUpdateTransactionController struct {
+		// Registry is used to get the farm record to be updated,
+		// and to ensure a new farm name is still available.
+		Registry FarmRecordReadRegistry
+	}
+)
+
+var (
+	// ensure at compile time that FarmUpdateTransactionController
+	// implements the desired interfaces
+	_ types.TransactionController      = FarmUpdateTransactionController{}
+	_ types.TransactionExtensionSigner = FarmUpdateTransactionController{}
+	_ types.TransactionValidator       = FarmUpdateTransactionController{}
+	_ types.BlockStakeOutputValidator  = FarmUpdateTransactionController{}
+	_ types.TransactionSignatureHasher = FarmUpdateTransactionController{}
+	_ types.TransactionIDEncoder       = FarmUpdateTransactionController{}
+)
+
+// EncodeTransactionData implements TransactionController.EncodeTransactionData
+func (futc FarmUpdateTransactionController) EncodeTransactionData(w io.Writer, txData types.TransactionData) error {
+	futx, err := FarmUpdateTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a FarmUpdateTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).Encode(futx)
+}
+
+// DecodeTransactionData implements TransactionController.DecodeTransactionData
+func (futc FarmUpdateTransactionController) DecodeTransactionData(r io.Reader) (types.TransactionData, error) {
+	var futx FarmUpdateTransaction
+	err := rivbin.NewDecoder(r).Decode(&futx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to binary-decode tx as a FarmUpdateTx: %v", err)
+	}
+	// return farm update tx as regular tfchain tx data
+	return futx.TransactionData(), nil
+}
+
+// JSONEncodeTransactionData implements TransactionController.JSONEncodeTransactionData
+func (futc FarmUpdateTransactionController) JSONEncodeTransactionData(txData types.TransactionData) ([]byte, error) {
+	futx, err := FarmUpdateTransactionFromTransactionData(txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert txData to a FarmUpdateTx: %v", err)
+	}
+	return json.Marshal(futx)
+}
+
+// JSONDecodeTransactionData implements TransactionController.JSONDecodeTransactionData
+func (futc FarmUpdateTransactionController) JSONDecodeTransactionData(data []byte) (types.TransactionData, error) {
+	var futx FarmUpdateTransaction
+	err := json.Unmarshal(data, &futx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to json-decode tx as a FarmUpdateTx: %v", err)
+	}
+	// return farm update tx as regular tfchain tx data
+	return futx.TransactionData(), nil
+}
+
+// SignExtension implements TransactionExtensionSigner.SignExtension
+func (futc FarmUpdateTransactionController) SignExtension(extension interface{}, sign func(*types.UnlockFulfillmentProxy, types.UnlockConditionProxy, ...interface{}) error) (interface{}, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid FarmUpdateTransactionExtension
+	futxExtension, ok := extension.(*FarmUpdateTransactionExtension)
+	if !ok {
+		return nil, errors.New("invalid extension data for a FarmUpdateTx")
+	}
+
+	// get the owner condition of the farm and use it to sign
+	record, err := futc.Registry.GetFarm(futxExtension.Identifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare the signing of FarmUpdateTx: %v", err)
+	}
+	err = sign(&futxExtension.OwnerFulfillment, record.Owner, FarmSignatureSpecifierOwner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign owner fulfillment of FarmUpdateTx: %v", err)
+	}
+	return futxExtension, nil
+}
+
+// ValidateTransaction implements TransactionValidator.ValidateTransaction
+func (futc FarmUpdateTransactionController) ValidateTransaction(t types.Transaction, ctx types.ValidationContext, constants types.TransactionValidationConstants) error {
+	// check tx fits within a block
+	err := types.TransactionFitsInABlock(t, constants.BlockSizeLimit)
+	if err != nil {
+		return err
+	}
+
+	// get FarmUpdateTx
+	futx, err := FarmUpdateTransactionFromTransaction(t)
+	if err != nil {
+		return fmt.Errorf("failed to use tx as a farm update tx: %v", err)
+	}
+
+	// look up the record, using the given ID, to ensure it is registered
+	record, err := futc.Registry.GetFarm(futx.Identifier)
+	if err != nil {
+		return fmt.Errorf("farm cannot be updated: GetFarm(%v): %v", futx.Identifier, err)
+	}
+
+	// check if OwnerFulfillment fulfills the owner condition of the farm
+	err = record.Owner.Fulfill(futx.OwnerFulfillment, types.FulfillContext{
+		ExtraObjects: []interface{}{FarmSignatureSpecifierOwner},
+		BlockHeight:  ctx.BlockHeight,
+		BlockTime:    ctx.BlockTime,
+		Transaction:  t,
+	})
+	if err != nil {
+		return fmt.Errorf("unauthorized farm update tx: failed to fulfill owner condition: %v", err)
+	}
+
+	// at least something has to be updated, a nop-update is not allowed
+	if futx.Name == "" && len(futx.AuthorizedAddresses.Add) == 0 && len(futx.AuthorizedAddresses.Remove) == 0 {
+		return errors.New("farm record updates requires a name or authorized address to be defined")
+	}
+
+	// validate the name, if defined, and ensure it is still available
+	if futx.Name != "" {
+		if futx.Name == record.Name {
+			return errors.New("farm record update defines the name the farm already has")
+		}
+		err = ValidateFarmName(futx.Name)
+		if err != nil {
+			return fmt.Errorf("invalid farm update tx: %v", err)
+		}
+		_, err = futc.Registry.GetFarmForName(futx.Name)
+		if err == nil {
+			return ErrFarmNameAlreadyRegistered
+		}
+		if err != ErrFarmNameNotFound {
+			return fmt.Errorf("unexpected error while validating non-existence of farm name %q: %v", futx.Name, err)
+		}
+	}
+
+	// try to update the record, to spot any errors should that happen for real
+	err = futx.UpdateFarmRecord(record)
+	if err != nil {
+		return fmt.Errorf("farm cannot be updated: UpdateFarmRecord: %v", err)
+	}
+
+	// validate the miner fee
+	if futx.TransactionFee.Cmp(constants.MinimumMinerFee) < 0 {
+		return types.ErrTooSmallMinerFee
+	}
+
+	// prevent double spending
+	spendCoins := make(map[types.CoinOutputID]struct{})
+	for _, ci := range futx.CoinInputs {
+		if _, found := spendCoins[ci.ParentID]; found {
+			return types.ErrDoubleSpend
+		}
+		spendCoins[ci.ParentID] = struct{}{}
+	}
+
+	// check if optional coin output is using standard condition
+	if futx.RefundCoinOutput != nil {
+		err = futx.RefundCoinOutput.Condition.IsStandardCondition(ctx)
+		if err != nil {
+			return err
+		}
+		// ensure the value is not 0
+		if futx.RefundCoinOutput.Value.IsZero() {
+			return types.ErrZeroOutput
+		}
+	}
+	// check if all fulfillments are standard
+	for _, sci := range futx.CoinInputs {
+		err = sci.Fulfillment.IsStandardFulfillment(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Tx is valid
+	return nil
+}
+
+// ValidateBlockStakeOutputs implements BlockStakeOutputValidator.ValidateBlockStakeOutputs
+func (futc FarmUpdateTransactionController) ValidateBlockStakeOutputs(t types.Transaction, ctx types.FundValidationContext, blockStakeInputs map[types.BlockStakeOutputID]types.BlockStakeOutput) (err error) {
+	return nil // always valid, no block stake inputs/outputs exist within a farm update transaction
+}
+
+// SignatureHash implements TransactionSignatureHasher.SignatureHash
+func (futc FarmUpdateTransactionController) SignatureHash(t types.Transaction, extraObjects ...interface{}) (crypto.Hash, error) {
+	futx, err := FarmUpdateTransactionFromTransaction(t)
+	if err != nil {
+		return crypto.Hash{}, fmt.Errorf("failed to use tx as a FarmUpdateTx: %v", err)
+	}
+
+	h := crypto.NewHash()
+	enc := rivbin.NewEncoder(h)
+
+	enc.EncodeAll(
+		t.Version,
+		SpecifierFarmUpdateTransaction,
+		futx.Identifier,
+	)
+
+	if len(extraObjects) > 0 {
+		enc.EncodeAll(extraObjects...)
+	}
+
+	enc.EncodeAll(
+		futx.Name,
+		futx.AuthorizedAddresses.Add,
+		futx.AuthorizedAddresses.Remove,
+	)
+
+	enc.Encode(len(futx.CoinInputs))
+	for _, ci := range futx.CoinInputs {
+		enc.Encode(ci.ParentID)
+	}
+
+	enc.EncodeAll(
+		futx.TransactionFee,
+		futx.RefundCoinOutput,
+	)
+
+	var hash crypto.Hash
+	h.Sum(hash[:0])
+	return hash, nil
+}
+
+// EncodeTransactionIDInput implements TransactionIDEncoder.EncodeTransactionIDInput
+func (futc FarmUpdateTransactionController) EncodeTransactionIDInput(w io.Writer, txData types.TransactionData) error {
+	futx, err := FarmUpdateTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a FarmUpdateTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).EncodeAll(SpecifierFarmUpdateTransaction, futx)
+}
+
+func validateFarmInMemoryTransactionDataRequirements(txData types.TransactionData) error {
+	// at least one coin input as well as one miner fee is required
+	if len(txData.CoinInputs) == 0 || len(txData.MinerFees) != 1 {
+		return errors.New("at least one coin input and exactly one miner fee is required for a Farm Transaction")
+	}
+	// no block stake inputs or block stake outputs are allowed
+	if len(txData.BlockStakeInputs) != 0 || len(txData.BlockStakeOutputs) != 0 {
+		return errors.New("no block stake inputs/outputs are allowed in a Farm Transaction")
+	}
+	// no arbitrary data is allowed
+	if len(txData.ArbitraryData) > 0 {
+		return errors.New("no arbitrary data is allowed in a Farm Transaction")
+	}
+	// validate that the coin outputs is within the expected range
+	if len(txData.CoinOutputs) > 1 {
+		return errors.New("a Farm Transaction can have maximum 1 Coin Output")
+	}
+	return nil
+}
+
+func validateFarmOwnerCondition(condition types.UnlockConditionProxy) error {
+	switch condition.ConditionType() {
+	case types.ConditionTypeUnlockHash, types.ConditionTypeMultiSignature:
+		return nil
+	default:
+		return fmt.Errorf("farm owner condition has to be an UnlockHash or MultiSignature condition, not type %d", condition.ConditionType())
+	}
+}
@@ -0,0 +1,476 @@
+package types
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/threefoldfoundation/tfchain/pkg/config"
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestFarmRegistrationTransactionBinaryEncodingAndID(t *testing.T) {
+	types.RegisterTransactionVersion(TransactionVersionFarmRegistration, FarmRegistrationTransactionController{})
+	defer types.RegisterTransactionVersion(TransactionVersionFarmRegistration, nil)
+
+	const (
+		jsonEncodedTx = `{"version":161,"data":{"name":"myfarm","owner":{"type":1,"data":{"unlockhash":"01370af706b547dd4e562a047e6265d7e7750771f9bff633b1a12dbd59b11712c6ef65edb1690d"}},"authorizedaddresses":["01370af706b547dd4e562a047e6265d7e7750771f9bff633b1a12dbd59b11712c6ef65edb1690d"],"foundationfulfillment":{"type":1,"data":{"publickey":"ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780","signature":"49feaed4abf22123ac3694c4eb88caa8a38908898dec5033ca116bb7b828fe199aea8b733a7de5412ca81594e34c7d4183be4637d7956f0ffdd5837dc7225e07"}},"txfee":"1000000000","coininputs":[{"parentid":"a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee563","fulfillment":{"type":1,"data":{"publickey":"ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780","signature":"3b22729cab432f96b78c2e3bc5e2917fb6c1b3c8eae6117e049486f318c8a9fa38d39407e472cbe42b5ad969674c85e8244ab5b30d007257f68a315f9756c105"}}}],"refundcoinoutput":{"value":"99999999000000000","condition":{"type":1,"data":{"unlockhash":"015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e6791584fbdac553e6f"}}}}}`
+		hexEncodedTx  = `a10c6d796661726d014201370af706b547dd4e562a047e6265d7e7750771f9bff633b1a12dbd59b11712c60201370af706b547dd4e562a047e6265d7e7750771f9bff633b1a12dbd59b11712c601c401d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d77808049feaed4abf22123ac3694c4eb88caa8a38908898dec5033ca116bb7b828fe199aea8b733a7de5412ca81594e34c7d4183be4637d7956f0ffdd5837dc7225e07083b9aca0002a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee56301c401d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780803b22729cab432f96b78c2e3bc5e2917fb6c1b3c8eae6117e049486f318c8a9fa38d39407e472cbe42b5ad969674c85e8244ab5b30d007257f68a315f9756c10501100163457821ef36000142015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e679158`
+	)
+	var tx types.Transaction
+	err := json.Unmarshal([]byte(jsonEncodedTx), &tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := tx.ID()
+	b := siabin.Marshal(tx)
+	if output := hex.EncodeToString(b); output != hexEncodedTx {
+		t.Fatal(hexEncodedTx, "!=", output)
+	}
+
+	// go to farm registration Tx and back
+	frtx, err := FarmRegistrationTransactionFromTransaction(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oTx := frtx.Transaction()
+	oID := oTx.ID()
+	oB := siabin.Marshal(oTx)
+	if id != oID {
+		t.Fatal(id, "!=", oID)
+	}
+	if !bytes.Equal(b, oB) {
+		t.Fatal(hex.EncodeToString(b), "!=", hex.EncodeToString(oB))
+	}
+
+	// binary decode it again, resulting in the same JSON-encoded transaction
+	var decodedTx types.Transaction
+	err = siabin.Unmarshal(oB, &decodedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err = json.Marshal(decodedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output := string(b); output != jsonEncodedTx {
+		t.Fatal(jsonEncodedTx, "!=", output)
+	}
+}
+
+func TestFarmUpdateTransactionBinaryEncodingAndID(t *testing.T) {
+	types.RegisterTransactionVersion(TransactionVersionFarmUpdate, FarmUpdateTransactionController{})
+	defer types.RegisterTransactionVersion(TransactionVersionFarmUpdate, nil)
+
+	const (
+		jsonEncodedTx = `{"version":162,"data":{"id":1,"name":"mynewfarm","authorizedaddresses":{"add":["01370af706b547dd4e562a047e6265d7e7750771f9bff633b1a12dbd59b11712c6ef65edb1690d"]},"ownerfulfillment":{"type":1,"data":{"publickey":"ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780","signature":"6f6bd33750ad8b86fb4e2ff505c6ed640d76a7b263279297a471d2ead21610e0cfe9867c0860fa2988ccdf472ad8abf348c6c5b8cb76dbb2e6a33a43081ae006"}},"txfee":"1000000000","coininputs":[{"parentid":"a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee563","fulfillment":{"type":1,"data":{"publickey":"ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780","signature":"8f8ecefc49b09156f312217b4835597b67c5bba9c0d3ecb4df197d37ecb6de50a008cc84b717b2a8e1bbcdbe3afe1f40b366af624c9f74c4b4f3c272f236460d"}}}],"refundcoinoutput":{"value":"99999999000000000","condition":{"type":1,"data":{"unlockhash":"015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e6791584fbdac553e6f"}}}}}`
+		hexEncodedTx  = `a201000000126d796e65776661726d0201370af706b547dd4e562a047e6265d7e7750771f9bff633b1a12dbd59b11712c60001c401d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780806f6bd33750ad8b86fb4e2ff505c6ed640d76a7b263279297a471d2ead21610e0cfe9867c0860fa2988ccdf472ad8abf348c6c5b8cb76dbb2e6a33a43081ae006083b9aca0002a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee56301c401d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780808f8ecefc49b09156f312217b4835597b67c5bba9c0d3ecb4df197d37ecb6de50a008cc84b717b2a8e1bbcdbe3afe1f40b366af624c9f74c4b4f3c272f236460d01100163457821ef36000142015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e679158`
+	)
+	var tx types.Transaction
+	err := json.Unmarshal([]byte(jsonEncodedTx), &tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := tx.ID()
+	b := siabin.Marshal(tx)
+	if output := hex.EncodeToString(b); output != hexEncodedTx {
+		t.Fatal(hexEncodedTx, "!=", output)
+	}
+
+	// go to farm update Tx and back
+	futx, err := FarmUpdateTransactionFromTransaction(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oTx := futx.Transaction()
+	oID := oTx.ID()
+	oB := siabin.Marshal(oTx)
+	if id != oID {
+		t.Fatal(id, "!=", oID)
+	}
+	if !bytes.Equal(b, oB) {
+		t.Fatal(hex.EncodeToString(b), "!=", hex.EncodeToString(oB))
+	}
+
+	// binary decode it again, resulting in the same JSON-encoded transaction
+	var decodedTx types.Transaction
+	err = siabin.Unmarshal(oB, &decodedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err = json.Marshal(decodedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output := string(b); output != jsonEncodedTx {
+		t.Fatal(jsonEncodedTx, "!=", output)
+	}
+}
+
+func TestFarmRegistrationTransactionValidation(t *testing.T) {
+	foundationCondition := types.NewCondition(types.NewUnlockHashCondition(
+		unlockHashFromHex("015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e6791584fbdac553e6f")))
+	registry := inMemoryFarmRecordRegistry{
+		1: FarmRecord{ID: 1, Name: "existingfarm"},
+	}
+	types.RegisterTransactionVersion(TransactionVersionFarmRegistration, FarmRegistrationTransactionController{
+		FoundationConditionGetter: inMemoryFoundationConditionGetter{condition: foundationCondition},
+		Registry:                  registry,
+	})
+	defer types.RegisterTransactionVersion(TransactionVersionFarmRegistration, nil)
+
+	foundationKey := hsk("788c0aaeec8e0d916a712535826fa2d47d19fd7b341242f05de0d2e6e7e06104d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780")
+	chainConstants := config.GetDevnetGenesis()
+	validationConstants := types.TransactionValidationConstants{
+		BlockSizeLimit:         chainConstants.BlockSizeLimit,
+		ArbitraryDataSizeLimit: chainConstants.ArbitraryDataSizeLimit,
+		MinimumMinerFee:        chainConstants.MinimumTransactionFee,
+	}
+	const unsignedJSONEncodedTx = `{
+	"version": 161,
+	"data": {
+		"name": "myfarm",
+		"owner": {
+			"type": 1,
+			"data": {
+				"unlockhash": "01370af706b547dd4e562a047e6265d7e7750771f9bff633b1a12dbd59b11712c6ef65edb1690d"
+			}
+		},
+		"authorizedaddresses": [
+			"01370af706b547dd4e562a047e6265d7e7750771f9bff633b1a12dbd59b11712c6ef65edb1690d"
+		],
+		"foundationfulfillment": {
+			"type": 1,
+			"data": {
+				"publickey": "ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780",
+				"signature": ""
+			}
+		},
+		"txfee": "1000000000",
+		"coininputs": [{
+			"parentid": "a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee563",
+			"fulfillment": {
+				"type": 1,
+				"data": {
+					"publickey": "ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780",
+					"signature": ""
+				}
+			}
+		}],
+		"refundcoinoutput": {
+			"value": "99999999000000000",
+			"condition": {
+				"type": 1,
+				"data": {
+					"unlockhash": "015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e6791584fbdac553e6f"
+				}
+			}
+		}
+	}
+}`
+	// decode the unsigned farm registration, such that each test case can modify it prior to signing
+	decodeTx := func() FarmRegistrationTransaction {
+		t.Helper()
+		var tx types.Transaction
+		err := tx.UnmarshalJSON([]byte(unsignedJSONEncodedTx))
+		if err != nil {
+			t.Fatal(err)
+		}
+		frtx, err := FarmRegistrationTransactionFromTransaction(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return frtx
+	}
+	signAndValidate := func(frtx FarmRegistrationTransaction, key interface{}) error {
+		t.Helper()
+		tx := frtx.Transaction()
+		return signAndValidateFarmTransaction(tx, key, foundationKey, validationConstants)
+	}
+
+	// signed by the foundation, should succeed
+	err := signAndValidate(decodeTx(), foundationKey)
+	if err != nil {
+		t.Fatalf("failed to validate valid farm registration: %v", err)
+	}
+
+	// signed by some random key, should fail
+	err = signAndValidate(decodeTx(), func() crypto.SecretKey { sk, _ := crypto.GenerateKeyPair(); return sk }())
+	if err == nil {
+		t.Error("succeeded to validate farm registration signed by an unauthorized key")
+	}
+
+	// name already taken, should fail
+	frtx := decodeTx()
+	frtx.Name = "existingfarm"
+	err = signAndValidate(frtx, foundationKey)
+	if err != ErrFarmNameAlreadyRegistered {
+		t.Errorf("unexpected error for farm registration with a taken name: %v", err)
+	}
+
+	// invalid name, should fail
+	frtx = decodeTx()
+	frtx.Name = "my farm"
+	err = signAndValidate(frtx, foundationKey)
+	if err == nil {
+		t.Error("succeeded to validate farm registration with an invalid name")
+	}
+
+	// no authorized addresses, should fail
+	frtx = decodeTx()
+	frtx.AuthorizedAddresses = nil
+	err = signAndValidate(frtx, foundationKey)
+	if err == nil {
+		t.Error("succeeded to validate farm registration without authorized addresses")
+	}
+
+	// duplicate authorized addresses, should fail
+	frtx = decodeTx()
+	frtx.AuthorizedAddresses = append(frtx.AuthorizedAddresses, frtx.AuthorizedAddresses[0])
+	err = signAndValidate(frtx, foundationKey)
+	if err == nil {
+		t.Error("succeeded to validate farm registration with duplicate authorized addresses")
+	}
+
+	// unsupported owner condition, should fail
+	frtx = decodeTx()
+	frtx.Owner = types.NewCondition(types.NewTimeLockCondition(42, types.NewUnlockHashCondition(
+		unlockHashFromHex("015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e6791584fbdac553e6f"))))
+	err = signAndValidate(frtx, foundationKey)
+	if err == nil {
+		t.Error("succeeded to validate farm registration with a time lock owner condition")
+	}
+}
+
+func TestFarmUpdateTransactionValidation(t *testing.T) {
+	ownerKey := hsk("788c0aaeec8e0d916a712535826fa2d47d19fd7b341242f05de0d2e6e7e06104d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780")
+	registry := inMemoryFarmRecordRegistry{
+		1: FarmRecord{
+			ID:   1,
+			Name: "myfarm",
+			Owner: types.NewCondition(types.NewUnlockHashCondition(
+				unlockHashFromHex("015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e6791584fbdac553e6f"))),
+			AuthorizedAddresses: types.UnlockHashSlice{
+				unlockHashFromHex("015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e6791584fbdac553e6f"),
+			},
+		},
+		2: FarmRecord{ID: 2, Name: "otherfarm"},
+	}
+	types.RegisterTransactionVersion(TransactionVersionFarmUpdate, FarmUpdateTransactionController{
+		Registry: registry,
+	})
+	defer types.RegisterTransactionVersion(TransactionVersionFarmUpdate, nil)
+
+	chainConstants := config.GetDevnetGenesis()
+	validationConstants := types.TransactionValidationConstants{
+		BlockSizeLimit:         chainConstants.BlockSizeLimit,
+		ArbitraryDataSizeLimit: chainConstants.ArbitraryDataSizeLimit,
+		MinimumMinerFee:        chainConstants.MinimumTransactionFee,
+	}
+	const unsignedJSONEncodedTx = `{
+	"version": 162,
+	"data": {
+		"id": 1,
+		"name": "mynewfarm",
+		"authorizedaddresses": {
+			"add": [
+				"01370af706b547dd4e562a047e6265d7e7750771f9bff633b1a12dbd59b11712c6ef65edb1690d"
+			]
+		},
+		"ownerfulfillment": {
+			"type": 1,
+			"data": {
+				"publickey": "ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780",
+				"signature": ""
+			}
+		},
+		"txfee": "1000000000",
+		"coininputs": [{
+			"parentid": "a3c8f44d64c0636018a929d2caeec09fb9698bfdcbfa3a8225585a51e09ee563",
+			"fulfillment": {
+				"type": 1,
+				"data": {
+					"publickey": "ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780",
+					"signature": ""
+				}
+			}
+		}],
+		"refundcoinoutput": {
+			"value": "99999999000000000",
+			"condition": {
+				"type": 1,
+				"data": {
+					"unlockhash": "015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e6791584fbdac553e6f"
+				}
+			}
+		}
+	}
+}`
+	// decode the unsigned farm update, such that each test case can modify it prior to signing
+	decodeTx := func() FarmUpdateTransaction {
+		t.Helper()
+		var tx types.Transaction
+		err := tx.UnmarshalJSON([]byte(unsignedJSONEncodedTx))
+		if err != nil {
+			t.Fatal(err)
+		}
+		futx, err := FarmUpdateTransactionFromTransaction(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return futx
+	}
+	signAndValidate := func(futx FarmUpdateTransaction, key interface{}) error {
+		t.Helper()
+		tx := futx.Transaction()
+		return signAndValidateFarmTransaction(tx, key, ownerKey, validationConstants)
+	}
+
+	// signed by the owner, should succeed
+	err := signAndValidate(decodeTx(), ownerKey)
+	if err != nil {
+		t.Fatalf("failed to validate valid farm update: %v", err)
+	}
+
+	// signed by some random key, should fail
+	err = signAndValidate(decodeTx(), func() crypto.SecretKey { sk, _ := crypto.GenerateKeyPair(); return sk }())
+	if err == nil {
+		t.Error("succeeded to validate farm update signed by an unauthorized key")
+	}
+
+	// unknown farm, should fail
+	futx := decodeTx()
+	futx.Identifier = 3
+	err = signAndValidate(futx, ownerKey)
+	if err == nil {
+		t.Error("succeeded to validate farm update for an unknown farm")
+	}
+
+	// nop update, should fail
+	futx = decodeTx()
+	futx.Name = ""
+	futx.AuthorizedAddresses = FarmAuthorizedAddressesUpdate{}
+	err = signAndValidate(futx, ownerKey)
+	if err == nil {
+		t.Error("succeeded to validate a nop farm update")
+	}
+
+	// name already taken, should fail
+	futx = decodeTx()
+	futx.Name = "otherfarm"
+	err = signAndValidate(futx, ownerKey)
+	if err != ErrFarmNameAlreadyRegistered {
+		t.Errorf("unexpected error for farm update with a taken name: %v", err)
+	}
+
+	// removing all authorized addresses, should fail
+	futx = decodeTx()
+	futx.AuthorizedAddresses = FarmAuthorizedAddressesUpdate{
+		Remove: []types.UnlockHash{
+			unlockHashFromHex("015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e6791584fbdac553e6f"),
+		},
+	}
+	err = signAndValidate(futx, ownerKey)
+	if err == nil {
+		t.Error("succeeded to validate farm update which removes all authorized addresses")
+	}
+}
+
+func TestFarmUpdateTransactionUpdateFarmRecord(t *testing.T) {
+	addrA := unlockHashFromHex("015a080a9259b9d4aaa550e2156f49b1a79a64c7ea463d810d4493e8242e6791584fbdac553e6f")
+	addrB := unlockHashFromHex("01370af706b547dd4e562a047e6265d7e7750771f9bff633b1a12dbd59b11712c6ef65edb1690d")
+	record := FarmRecord{
+		ID:                  1,
+		Name:                "myfarm",
+		AuthorizedAddresses: types.UnlockHashSlice{addrA},
+	}
+	original := record
+	futx := FarmUpdateTransaction{
+		Identifier: 1,
+		Name:       "mynewfarm",
+		AuthorizedAddresses: FarmAuthorizedAddressesUpdate{
+			Add:    []types.UnlockHash{addrB},
+			Remove: []types.UnlockHash{addrA},
+		},
+	}
+	err := futx.UpdateFarmRecord(&record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Name != "mynewfarm" {
+		t.Error("unexpected name:", record.Name)
+	}
+	if len(record.AuthorizedAddresses) != 1 || record.AuthorizedAddresses[0].Cmp(addrB) != 0 {
+		t.Error("unexpected authorized addresses:", record.AuthorizedAddresses)
+	}
+	if original.AuthorizedAddresses[0].Cmp(addrA) != 0 {
+		t.Error("update modified the authorized addresses of the original record")
+	}
+
+	futx.Identifier = 2
+	err = futx.UpdateFarmRecord(&original)
+	if err == nil {
+		t.Error("succeeded to update the record of another farm")
+	}
+}
+
+func signAndValidateFarmTransaction(tx types.Transaction, key, coinInputKey interface{}, constants types.TransactionValidationConstants) error {
+	err := tx.SignExtension(func(fulfillment *types.UnlockFulfillmentProxy, condition types.UnlockConditionProxy, eo ...interface{}) error {
+		return fulfillment.Sign(types.FulfillmentSignContext{
+			ExtraObjects: eo,
+			Transaction:  tx,
+			Key:          key,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sign: %v", err)
+	}
+	err = tx.CoinInputs[0].Fulfillment.Sign(types.FulfillmentSignContext{
+		ExtraObjects: []interface{}{uint64(0)},
+		Transaction:  tx,
+		Key:          coinInputKey,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sign coin input: %v", err)
+	}
+	return tx.ValidateTransaction(types.ValidationContext{
+		Confirmed:   true,
+		BlockHeight: 4072,
+		BlockTime:   1534271219,
+	}, constants)
+}
+
+type inMemoryFoundationConditionGetter struct {
+	condition types.UnlockConditionProxy
+}
+
+func (getter inMemoryFoundationConditionGetter) GetActiveFoundationCondition() (types.UnlockConditionProxy, error) {
+	return getter.condition, nil
+}
+
+func (getter inMemoryFoundationConditionGetter) GetFoundationConditionAt(height types.BlockHeight) (types.UnlockConditionProxy, error) {
+	return getter.condition, nil
+}
+
+type inMemoryFarmRecordRegistry map[FarmID]FarmRecord
+
+func (registry inMemoryFarmRecordRegistry) GetFarm(id FarmID) (*FarmRecord, error) {
+	record, ok := registry[id]
+	if !ok {
+		return nil, ErrFarmNotFound
+	}
+	return &record, nil
+}
+
+func (registry inMemoryFarmRecordRegistry) GetFarmForName(name string) (*FarmRecord, error) {
+	for _, record := range registry {
+		if record.Name == name {
+			return &record, nil
+		}
+	}
+	return nil, ErrFarmNameNotFound
+}
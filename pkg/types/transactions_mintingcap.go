@@ -0,0 +1,400 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+const (
+	// TransactionVersionMintingCapDefinition defines the Transaction version
+	// for a MintingCapDefinition Transaction, used to (re)define the minting cap.
+	//
+	// See the `MintingCapDefinitionTransactionController` and `MintingCapDefinitionTransaction`
+	// types for more information.
+	TransactionVersionMintingCapDefinition types.TransactionVersion = iota + 131
+)
+
+// These Specifiers are used internally when calculating a Transaction's ID.
+// See Rivine's Specifier for more details.
+var (
+	SpecifierMintingCapDefinitionTransaction = types.Specifier{'m', 'i', 'n', 't', ' ', 'c', 'a', 'p', ' ', 'd', 'e', 'f', ' ', 't', 'x'}
+)
+
+type (
+	// MintingCapGetter allows you to get the minting caps defined within a window of time,
+	// as well as the amount of coins minted within that window.
+	//
+	// For the daemon this interface could be implemented directly by the DB object
+	// that keeps track of the minting cap state, while for a client this could
+	// come via the REST API from a tfchain daemon in a more indirect way.
+	MintingCapGetter interface {
+		// GetMintingCapSince returns the lowest minting cap that applied since the given block time,
+		// taking into account only the blocks prior to the given (consensus) block height.
+		// False is returned in case no minting cap applied (yet) since that time.
+		GetMintingCapSince(height types.BlockHeight, since types.Timestamp) (types.Currency, bool, error)
+		// GetMintedCoinsSince returns the amount of coins created by coin creation transactions since the given block time,
+		// taking into account only the blocks prior to the given (consensus) block height.
+		GetMintedCoinsSince(height types.BlockHeight, since types.Timestamp) (types.Currency, error)
+	}
+)
+
+// ErrMintingCapExceeded is returned in case a coin creation transaction
+// would create more coins than allowed by the minting cap.
+var ErrMintingCapExceeded = errors.New("minting cap exceeded")
+
+type (
+	// MintingCapDefinitionTransaction is to be created only by the defined Coin Minters,
+	// as a medium in order to (re)define the maximum amount of coins that can be created,
+	// using CoinCreation Transactions, within the rolling window of the network.
+	MintingCapDefinitionTransaction struct {
+		// Nonce used to ensure the uniqueness of a MintingCapDefinitionTransaction's ID and signature.
+		Nonce TransactionNonce `json:"nonce"`
+		// MintFulfillment defines the fulfillment which is used in order to
+		// fulfill the globally defined MintCondition.
+		MintFulfillment types.UnlockFulfillmentProxy `json:"mintfulfillment"`
+		// MintingCap defines the new maximum amount of coins,
+		// including their miner fees, that can be created within the rolling window of the network.
+		MintingCap types.Currency `json:"mintingcap"`
+		// Minerfees, a fee paid for this minting cap definition transaction.
+		MinerFees []types.Currency `json:"minerfees"`
+		// ArbitraryData can be used for any purpose,
+		// but is mostly to be used in order to define the reason
+		// of the (re)definition of the minting cap.
+		ArbitraryData []byte `json:"arbitrarydata,omitempty"`
+	}
+	// MintingCapDefinitionTransactionExtension defines the MintingCapDefinitionTx Extension Data
+	MintingCapDefinitionTransactionExtension struct {
+		Nonce           TransactionNonce
+		MintFulfillment types.UnlockFulfillmentProxy
+		MintingCap      types.Currency
+	}
+)
+
+// MintingCapDefinitionTransactionFromTransaction creates a MintingCapDefinitionTransaction,
+// using a regular in-memory tfchain transaction.
+//
+// Past the (tx) Version validation it piggy-backs onto the
+// `MintingCapDefinitionTransactionFromTransactionData` constructor.
+func MintingCapDefinitionTransactionFromTransaction(tx types.Transaction) (MintingCapDefinitionTransaction, error) {
+	if tx.Version != TransactionVersionMintingCapDefinition {
+		return MintingCapDefinitionTransaction{}, fmt.Errorf(
+			"a minting cap definition transaction requires tx version %d",
+			TransactionVersionMintingCapDefinition)
+	}
+	return MintingCapDefinitionTransactionFromTransactionData(types.TransactionData{
+		CoinInputs:        tx.CoinInputs,
+		CoinOutputs:       tx.CoinOutputs,
+		BlockStakeInputs:  tx.BlockStakeInputs,
+		BlockStakeOutputs: tx.BlockStakeOutputs,
+		MinerFees:         tx.MinerFees,
+		ArbitraryData:     tx.ArbitraryData,
+		Extension:         tx.Extension,
+	})
+}
+
+// MintingCapDefinitionTransactionFromTransactionData creates a MintingCapDefinitionTransaction,
+// using the TransactionData from a regular in-memory tfchain transaction.
+func MintingCapDefinitionTransactionFromTransactionData(txData types.TransactionData) (MintingCapDefinitionTransaction, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid MintingCapDefinitionTransactionExtension,
+	// which contains the nonce, the mintFulfillment that can be used to fulfill the currently globally defined mint condition,
+	// as well as the new minting cap.
+	extensionData, ok := txData.Extension.(*MintingCapDefinitionTransactionExtension)
+	if !ok {
+		return MintingCapDefinitionTransaction{}, errors.New("invalid extension data for a MintingCapDefinitionTransaction")
+	}
+	// at least one miner fee is required
+	if len(txData.MinerFees) == 0 {
+		return MintingCapDefinitionTransaction{}, errors.New("at least one miner fee is required for a MintingCapDefinitionTransaction")
+	}
+	// no coin inputs/outputs, block stake inputs or block stake outputs are allowed
+	if len(txData.CoinInputs) != 0 || len(txData.CoinOutputs) != 0 || len(txData.BlockStakeInputs) != 0 || len(txData.BlockStakeOutputs) != 0 {
+		return MintingCapDefinitionTransaction{}, errors.New(
+			"no coin inputs/outputs and block stake inputs/outputs are allowed in a MintingCapDefinitionTransaction")
+	}
+	// return the MintingCapDefinitionTransaction, with the data extracted from the TransactionData
+	return MintingCapDefinitionTransaction{
+		Nonce:           extensionData.Nonce,
+		MintFulfillment: extensionData.MintFulfillment,
+		MintingCap:      extensionData.MintingCap,
+		MinerFees:       txData.MinerFees,
+		// ArbitraryData is optional
+		ArbitraryData: txData.ArbitraryData,
+	}, nil
+}
+
+// TransactionData returns this MintingCapDefinitionTransaction
+// as regular tfchain transaction data.
+func (mcdtx *MintingCapDefinitionTransaction) TransactionData() types.TransactionData {
+	return types.TransactionData{
+		MinerFees:     mcdtx.MinerFees,
+		ArbitraryData: mcdtx.ArbitraryData,
+		Extension: &MintingCapDefinitionTransactionExtension{
+			Nonce:           mcdtx.Nonce,
+			MintFulfillment: mcdtx.MintFulfillment,
+			MintingCap:      mcdtx.MintingCap,
+		},
+	}
+}
+
+// Transaction returns this MintingCapDefinitionTransaction
+// as regular tfchain transaction, using TransactionVersionMintingCapDefinition as the type.
+func (mcdtx *MintingCapDefinitionTransaction) Transaction() types.Transaction {
+	return types.Transaction{
+		Version:       TransactionVersionMintingCapDefinition,
+		MinerFees:     mcdtx.MinerFees,
+		ArbitraryData: mcdtx.ArbitraryData,
+		Extension: &MintingCapDefinitionTransactionExtension{
+			Nonce:           mcdtx.Nonce,
+			MintFulfillment: mcdtx.MintFulfillment,
+			MintingCap:      mcdtx.MintingCap,
+		},
+	}
+}
+
+type (
+	// MintingCapDefinitionTransactionController defines a tfchain-specific transaction controller,
+	// for a transaction type reserved at type 0x83. It allows the coin minters to (re)define the minting cap.
+	MintingCapDefinitionTransactionController struct {
+		// MintConditionGetter is used to get a mint condition at the context-defined block height.
+		//
+		// The found MintCondition defines the condition that has to be fulfilled
+		// in order to (re)define the minting cap.
+		MintConditionGetter MintConditionGetter
+		// MintingCapWindow defines the rolling window (in seconds of block time)
+		// to which the minting cap applies, minting caps are disabled if it is 0.
+		MintingCapWindow types.Timestamp
+	}
+)
+
+var (
+	// ensure at compile time that MintingCapDefinitionTransactionController
+	// implements the desired interfaces
+	_ types.TransactionController      = MintingCapDefinitionTransactionController{}
+	_ types.TransactionExtensionSigner = MintingCapDefinitionTransactionController{}
+	_ types.TransactionValidator       = MintingCapDefinitionTransactionController{}
+	_ types.CoinOutputValidator        = MintingCapDefinitionTransactionController{}
+	_ types.BlockStakeOutputValidator  = MintingCapDefinitionTransactionController{}
+	_ types.TransactionSignatureHasher = MintingCapDefinitionTransactionController{}
+	_ types.TransactionIDEncoder       = MintingCapDefinitionTransactionController{}
+)
+
+// EncodeTransactionData implements TransactionController.EncodeTransactionData
+func (mcdtc MintingCapDefinitionTransactionController) EncodeTransactionData(w io.Writer, txData types.TransactionData) error {
+	mcdtx, err := MintingCapDefinitionTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a MintingCapDefinitionTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).Encode(mcdtx)
+}
+
+// DecodeTransactionData implements TransactionController.DecodeTransactionData
+func (mcdtc MintingCapDefinitionTransactionController) DecodeTransactionData(r io.Reader) (types.TransactionData, error) {
+	var mcdtx MintingCapDefinitionTransaction
+	err := rivbin.NewDecoder(r).Decode(&mcdtx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to binary-decode tx as a MintingCapDefinitionTx: %v", err)
+	}
+	// return minting cap definition tx as regular tfchain tx data
+	return mcdtx.TransactionData(), nil
+}
+
+// JSONEncodeTransactionData implements TransactionController.JSONEncodeTransactionData
+func (mcdtc MintingCapDefinitionTransactionController) JSONEncodeTransactionData(txData types.TransactionData) ([]byte, error) {
+	mcdtx, err := MintingCapDefinitionTransactionFromTransactionData(txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert txData to a MintingCapDefinitionTx: %v", err)
+	}
+	return json.Marshal(mcdtx)
+}
+
+// JSONDecodeTransactionData implements TransactionController.JSONDecodeTransactionData
+func (mcdtc MintingCapDefinitionTransactionController) JSONDecodeTransactionData(data []byte) (types.TransactionData, error) {
+	var mcdtx MintingCapDefinitionTransaction
+	err := json.Unmarshal(data, &mcdtx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to json-decode tx as a MintingCapDefinitionTx: %v", err)
+	}
+	// return minting cap definition tx as regular tfchain tx data
+	return mcdtx.TransactionData(), nil
+}
+
+// SignExtension implements TransactionExtensionSigner.SignExtension
+func (mcdtc MintingCapDefinitionTransactionController) SignExtension(extension interface{}, sign func(*types.UnlockFulfillmentProxy, types.UnlockConditionProxy, ...interface{}) error) (interface{}, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid MintingCapDefinitionTransactionExtension,
+	// which contains the nonce and the mintFulfillment that can be used to fulfill the globally defined mint condition
+	mcdTxExtension, ok := extension.(*MintingCapDefinitionTransactionExtension)
+	if !ok {
+		return nil, errors.New("invalid extension data for a MintingCapDefinitionTx")
+	}
+
+	// get the active mint condition and use it to sign
+	mintCondition, err := mcdtc.MintConditionGetter.GetActiveMintCondition()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the active mint condition: %v", err)
+	}
+	err = sign(&mcdTxExtension.MintFulfillment, mintCondition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign mint fulfillment of MintingCapDefinitionTx: %v", err)
+	}
+	return mcdTxExtension, nil
+}
+
+// ValidateTransaction implements TransactionValidator.ValidateTransaction
+func (mcdtc MintingCapDefinitionTransactionController) ValidateTransaction(t types.Transaction, ctx types.ValidationContext, constants types.TransactionValidationConstants) (err error) {
+	err = types.TransactionFitsInABlock(t, constants.BlockSizeLimit)
+	if err != nil {
+		return err
+	}
+
+	// get MintingCapDefinitionTx
+	mcdtx, err := MintingCapDefinitionTransactionFromTransaction(t)
+	if err != nil {
+		return fmt.Errorf("failed to use tx as a minting cap definition tx: %v", err)
+	}
+
+	// a minting cap without a window to apply it to is meaningless
+	if mcdtc.MintingCapWindow == 0 {
+		return errors.New("minting caps are not enabled on this network")
+	}
+
+	// get MintCondition
+	mintCondition, err := mcdtc.MintConditionGetter.GetMintConditionAt(ctx.BlockHeight)
+	if err != nil {
+		return fmt.Errorf("failed to get mint condition at block height %d: %v", ctx.BlockHeight, err)
+	}
+
+	// check if MintFulfillment fulfills the Globally defined MintCondition for the context-defined block height
+	err = mintCondition.Fulfill(mcdtx.MintFulfillment, types.FulfillContext{
+		BlockHeight: ctx.BlockHeight,
+		BlockTime:   ctx.BlockTime,
+		Transaction: t,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fulfill mint condition: %v", err)
+	}
+	// ensure the Nonce is not Nil
+	if mcdtx.Nonce == (TransactionNonce{}) {
+		return errors.New("nil nonce is not allowed for a minting cap definition transaction")
+	}
+
+	// validate the rest of the content
+	err = types.ArbitraryDataFits(mcdtx.ArbitraryData, constants.ArbitraryDataSizeLimit)
+	if err != nil {
+		return
+	}
+	for _, fee := range mcdtx.MinerFees {
+		if fee.Cmp(constants.MinimumMinerFee) == -1 {
+			return types.ErrTooSmallMinerFee
+		}
+	}
+	return
+}
+
+// ValidateCoinOutputs implements CoinOutputValidator.ValidateCoinOutputs
+func (mcdtc MintingCapDefinitionTransactionController) ValidateCoinOutputs(t types.Transaction, ctx types.FundValidationContext, coinInputs map[types.CoinOutputID]types.CoinOutput) (err error) {
+	return nil // always valid, no coin inputs/outputs exist within a minting cap definition transaction
+}
+
+// ValidateBlockStakeOutputs implements BlockStakeOutputValidator.ValidateBlockStakeOutputs
+func (mcdtc MintingCapDefinitionTransactionController) ValidateBlockStakeOutputs(t types.Transaction, ctx types.FundValidationContext, blockStakeInputs map[types.BlockStakeOutputID]types.BlockStakeOutput) (err error) {
+	return nil // always valid, no block stake inputs/outputs exist within a minting cap definition transaction
+}
+
+// SignatureHash implements TransactionSignatureHasher.SignatureHash
+func (mcdtc MintingCapDefinitionTransactionController) SignatureHash(t types.Transaction, extraObjects ...interface{}) (crypto.Hash, error) {
+	mcdtx, err := MintingCapDefinitionTransactionFromTransaction(t)
+	if err != nil {
+		return crypto.Hash{}, fmt.Errorf("failed to use tx as a MintingCapDefinitionTx: %v", err)
+	}
+
+	h := crypto.NewHash()
+	enc := rivbin.NewEncoder(h)
+
+	enc.EncodeAll(
+		t.Version,
+		SpecifierMintingCapDefinitionTransaction,
+		mcdtx.Nonce,
+	)
+
+	if len(extraObjects) > 0 {
+		enc.EncodeAll(extraObjects...)
+	}
+
+	enc.EncodeAll(
+		mcdtx.MintingCap,
+		mcdtx.MinerFees,
+		mcdtx.ArbitraryData,
+	)
+
+	var hash crypto.Hash
+	h.Sum(hash[:0])
+	return hash, nil
+}
+
+// EncodeTransactionIDInput implements TransactionIDEncoder.EncodeTransactionIDInput
+func (mcdtc MintingCapDefinitionTransactionController) EncodeTransactionIDInput(w io.Writer, txData types.TransactionData) error {
+	mcdtx, err := MintingCapDefinitionTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a MintingCapDefinitionTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).EncodeAll(SpecifierMintingCapDefinitionTransaction, mcdtx)
+}
+
+// validateMintingCap ensures that the coins created by the given coin creation transaction,
+// together with all coins minted within the (rolling) minting cap window prior to it,
+// do not exceed the lowest minting cap that applied within that same window.
+//
+// Using the lowest cap of the window ensures that lowering the cap takes effect immediately,
+// while raising it only takes (full) effect once an entire window has passed.
+//
+// Only the chain state prior to the block (or transaction pool) of the coin creation transaction
+// is taken into account, such that its validity does not depend on the other transactions
+// this node happened to validate before it.
+func validateMintingCap(getter MintingCapGetter, window types.Timestamp, cctx CoinCreationTransaction, ctx types.ValidationContext) error {
+	if window == 0 {
+		return nil // minting caps are disabled
+	}
+	// unconfirmed transactions are validated on top of the last block,
+	// and thus have to take that block into account as well
+	height := ctx.BlockHeight
+	if !ctx.Confirmed {
+		height++
+	}
+	var since types.Timestamp
+	if ctx.BlockTime > window {
+		since = ctx.BlockTime - window
+	}
+	mintingCap, capped, err := getter.GetMintingCapSince(height, since)
+	if err != nil {
+		return fmt.Errorf("failed to get the minting cap since %d: %v", since, err)
+	}
+	if !capped {
+		return nil // no minting cap applies (yet)
+	}
+	minted, err := getter.GetMintedCoinsSince(height, since)
+	if err != nil {
+		return fmt.Errorf("failed to get the coins minted since %d: %v", since, err)
+	}
+	// all coins are created by the coin creation transaction, including its miner fees
+	var created types.Currency
+	for _, co := range cctx.CoinOutputs {
+		created = created.Add(co.Value)
+	}
+	for _, fee := range cctx.MinerFees {
+		created = created.Add(fee)
+	}
+	minted = minted.Add(created)
+	if minted.Cmp(mintingCap) > 0 {
+		return fmt.Errorf("%v: %s coins would be minted within the window, while the cap is %s",
+			ErrMintingCapExceeded, minted.String(), mintingCap.String())
+	}
+	return nil
+}
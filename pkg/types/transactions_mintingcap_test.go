@@ -0,0 +1,196 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/threefoldfoundation/tfchain/pkg/config"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestMintingCapDefinitionTransactionToAndFromJSONAndBinary(t *testing.T) {
+	types.RegisterTransactionVersion(TransactionVersionMintingCapDefinition, MintingCapDefinitionTransactionController{})
+	defer types.RegisterTransactionVersion(TransactionVersionMintingCapDefinition, nil)
+
+	mcdtx := MintingCapDefinitionTransaction{
+		Nonce:           TransactionNonce{1, 2, 3, 4, 5, 6, 7, 8},
+		MintFulfillment: types.NewFulfillment(types.NewSingleSignatureFulfillment(cryptoKeyPair.PublicKey)),
+		MintingCap:      types.NewCurrency64(100000000000000),
+		MinerFees:       []types.Currency{config.GetDevnetGenesis().MinimumTransactionFee},
+		ArbitraryData:   []byte("cap minting at 100 000 TFT"),
+	}
+	tx := mcdtx.Transaction()
+
+	b, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jsonDecodedTx types.Transaction
+	err = json.Unmarshal(b, &jsonDecodedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var binaryDecodedTx types.Transaction
+	err = siabin.Unmarshal(siabin.Marshal(tx), &binaryDecodedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, decodedTx := range []types.Transaction{jsonDecodedTx, binaryDecodedTx} {
+		if decodedTx.ID() != tx.ID() {
+			t.Fatal("unexpected transaction ID", decodedTx.ID(), "!=", tx.ID())
+		}
+		decodedMCDTX, err := MintingCapDefinitionTransactionFromTransaction(decodedTx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		output, err := json.Marshal(decodedMCDTX)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectedOutput, err := json.Marshal(mcdtx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(expectedOutput) != string(output) {
+			t.Fatal(string(expectedOutput), "!=", string(output))
+		}
+	}
+}
+
+func TestMintingCapDefinitionTransactionRequiresWindow(t *testing.T) {
+	mcdtx := MintingCapDefinitionTransaction{
+		Nonce:           TransactionNonce{1},
+		MintFulfillment: types.NewFulfillment(types.NewSingleSignatureFulfillment(cryptoKeyPair.PublicKey)),
+		MintingCap:      types.NewCurrency64(100000000000000),
+		MinerFees:       []types.Currency{config.GetDevnetGenesis().MinimumTransactionFee},
+	}
+	err := MintingCapDefinitionTransactionController{}.ValidateTransaction(mcdtx.Transaction(), types.ValidationContext{
+		Confirmed:   true,
+		BlockHeight: 4072,
+		BlockTime:   1534271219,
+	}, types.TransactionValidationConstants{
+		BlockSizeLimit:         config.GetDevnetGenesis().BlockSizeLimit,
+		ArbitraryDataSizeLimit: config.GetDevnetGenesis().ArbitraryDataSizeLimit,
+		MinimumMinerFee:        config.GetDevnetGenesis().MinimumTransactionFee,
+	})
+	if err == nil {
+		t.Fatal("succeeded to validate a minting cap definition on a network without minting cap window")
+	}
+}
+
+func TestValidateMintingCap(t *testing.T) {
+	const window = types.Timestamp(1000)
+	fee := config.GetDevnetGenesis().MinimumTransactionFee
+	cctx := CoinCreationTransaction{
+		CoinOutputs: []types.CoinOutput{{Value: types.NewCurrency64(40)}},
+		MinerFees:   []types.Currency{fee},
+	}
+	confirmedCtx := types.ValidationContext{
+		Confirmed:   true,
+		BlockHeight: 10,
+		BlockTime:   5000,
+	}
+
+	// without a window, no minting cap is checked
+	getter := &stubMintingCapGetter{capped: true}
+	err := validateMintingCap(getter, 0, cctx, confirmedCtx)
+	if err != nil {
+		t.Fatal("unexpected error while minting caps are disabled:", err)
+	}
+	// without a cap, all coin creations are valid
+	getter = &stubMintingCapGetter{minted: types.NewCurrency64(1000000)}
+	err = validateMintingCap(getter, window, cctx, confirmedCtx)
+	if err != nil {
+		t.Fatal("unexpected error while no minting cap applies:", err)
+	}
+	if getter.height != 10 || getter.since != 4000 {
+		t.Fatal("unexpected height and time used to get the minting cap:", getter.height, getter.since)
+	}
+
+	// the coin creation, including its fees, can create coins up to the cap
+	getter = &stubMintingCapGetter{
+		capped:     true,
+		mintingCap: types.NewCurrency64(100).Add(fee),
+		minted:     types.NewCurrency64(60),
+	}
+	err = validateMintingCap(getter, window, cctx, confirmedCtx)
+	if err != nil {
+		t.Fatal("unexpected error while minting up to the cap:", err)
+	}
+	getter.minted = getter.minted.Add(types.NewCurrency64(1))
+	err = validateMintingCap(getter, window, cctx, confirmedCtx)
+	if err == nil {
+		t.Fatal("succeeded to mint more coins than allowed by the cap")
+	}
+
+	// unconfirmed coin creations take the last block into account as well
+	err = validateMintingCap(getter, window, cctx, types.ValidationContext{
+		BlockHeight: 10,
+		BlockTime:   500,
+	})
+	if err == nil {
+		t.Fatal("succeeded to mint more coins than allowed by the cap")
+	}
+	if getter.height != 11 || getter.since != 0 {
+		t.Fatal("unexpected height and time used to get the minting cap:", getter.height, getter.since)
+	}
+}
+
+func TestValidateMintingCapOnlyDependsOnChainState(t *testing.T) {
+	const window = types.Timestamp(1000)
+	fee := config.GetDevnetGenesis().MinimumTransactionFee
+	cctx := CoinCreationTransaction{
+		CoinOutputs: []types.CoinOutput{{Value: types.NewCurrency64(40)}},
+		MinerFees:   []types.Currency{fee},
+	}
+	// the cap allows a single coin creation, but not two of them
+	getter := &stubMintingCapGetter{
+		capped:     true,
+		mintingCap: types.NewCurrency64(60).Add(fee),
+	}
+	for _, ctx := range []types.ValidationContext{
+		{Confirmed: true, BlockHeight: 10, BlockTime: 5000},
+		{BlockHeight: 10, BlockTime: 5000},
+	} {
+		// validating coin creations within the same context,
+		// does not influence the validation of the ones that follow
+		for i := 0; i < 2; i++ {
+			err := validateMintingCap(getter, window, cctx, ctx)
+			if err != nil {
+				t.Fatal("unexpected error for coin creation", i, "validated within", ctx, ":", err)
+			}
+		}
+	}
+
+	// once the coins are minted on chain, they do count against the cap
+	getter.minted = types.NewCurrency64(40).Add(fee)
+	err := validateMintingCap(getter, window, cctx, types.ValidationContext{
+		Confirmed:   true,
+		BlockHeight: 11,
+		BlockTime:   5120,
+	})
+	if err == nil {
+		t.Fatal("succeeded to mint more coins than allowed by the cap")
+	}
+}
+
+type stubMintingCapGetter struct {
+	mintingCap types.Currency
+	capped     bool
+	minted     types.Currency
+
+	height types.BlockHeight
+	since  types.Timestamp
+}
+
+func (getter *stubMintingCapGetter) GetMintingCapSince(height types.BlockHeight, since types.Timestamp) (types.Currency, bool, error) {
+	getter.height, getter.since = height, since
+	return getter.mintingCap, getter.capped, nil
+}
+
+func (getter *stubMintingCapGetter) GetMintedCoinsSince(height types.BlockHeight, since types.Timestamp) (types.Currency, error) {
+	getter.height, getter.since = height, since
+	return getter.minted, nil
+}
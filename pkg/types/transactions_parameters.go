@@ -0,0 +1,578 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/threefoldfoundation/tfchain/pkg/config"
+	"github.com/threefoldtech/rivine/crypto"
+	"github.com/threefoldtech/rivine/pkg/encoding/rivbin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+const (
+	// TransactionVersionConsensusParameterUpdate defines the Transaction version
+	// for a ConsensusParameterUpdate Transaction, used to update consensus parameters,
+	// such as the 3bot and ERC20 fees, from a given block height onwards.
+	//
+	// See the `ConsensusParameterUpdateTransactionController` and `ConsensusParameterUpdateTransaction`
+	// types for more information.
+	TransactionVersionConsensusParameterUpdate types.TransactionVersion = iota + 132
+)
+
+// These Specifiers are used internally when calculating a Transaction's ID.
+// See Rivine's Specifier for more details.
+var (
+	SpecifierConsensusParameterUpdateTransaction = types.Specifier{'p', 'a', 'r', 'a', 'm', ' ', 'u', 'p', 'd', 'a', 't', 'e', ' ', 't', 'x'}
+)
+
+// ConsensusParameterName is the name of a consensus parameter,
+// as used by a ConsensusParameterUpdate to identify the parameter it updates.
+type ConsensusParameterName string
+
+// All consensus parameters which can be updated using a ConsensusParameterUpdateTransaction.
+const (
+	ConsensusParameterBotRegistrationFee             ConsensusParameterName = "botregistrationfee"
+	ConsensusParameterBotMonthlyFee                  ConsensusParameterName = "botmonthlyfee"
+	ConsensusParameterBotNameFee                     ConsensusParameterName = "botnamefee"
+	ConsensusParameterBotNetworkAddressFee           ConsensusParameterName = "botnetworkaddressfee"
+	ConsensusParameterBotServiceFee                  ConsensusParameterName = "botservicefee"
+	ConsensusParameterERC20ConversionMinimum         ConsensusParameterName = "erc20conversionminimum"
+	ConsensusParameterERC20AddressRegistrationFee    ConsensusParameterName = "erc20addressregistrationfee"
+	ConsensusParameterTransactionFeeCheckHeight      ConsensusParameterName = "txfeecheckheight"
+	ConsensusParameterBotSubNameActivationHeight     ConsensusParameterName = "botsubnameactivationheight"
+	ConsensusParameterBotGracePeriodActivationHeight ConsensusParameterName = "botgraceperiodactivationheight"
+)
+
+// UnactivatedBlockHeight is the activation height of a consensus rule
+// that is not (yet) activated, until its activation height is updated.
+const UnactivatedBlockHeight = types.BlockHeight(math.MaxUint64)
+
+type (
+	// ConsensusParameters defines the consensus parameters of a tfchain network,
+	// which apply from a given block height onwards. The genesis parameters of a network
+	// are defined in code, and can be updated by the coin minters using ConsensusParameterUpdate Transactions.
+	ConsensusParameters struct {
+		// BotRegistrationFee is the fee paid once for the registration of a 3bot.
+		BotRegistrationFee types.Currency `json:"botregistrationfee"`
+		// BotMonthlyFee is the fee paid for every month a 3bot is active,
+		// see ComputeMonthlyBotFees for the discounts that apply.
+		BotMonthlyFee types.Currency `json:"botmonthlyfee"`
+		// BotNameFee is the fee paid for every (additional) name claimed by a 3bot.
+		BotNameFee types.Currency `json:"botnamefee"`
+		// BotNetworkAddressFee is the fee paid for an update of the network addresses of a 3bot.
+		BotNetworkAddressFee types.Currency `json:"botnetworkaddressfee"`
+		// BotServiceFee is the fee paid for every service added to a 3bot.
+		BotServiceFee types.Currency `json:"botservicefee"`
+		// ERC20ConversionMinimum is the minimum value of TFT that can be converted into ERC20 funds.
+		ERC20ConversionMinimum types.Currency `json:"erc20conversionminimum"`
+		// ERC20AddressRegistrationFee is the fee paid for the registration of an ERC20 withdrawal address.
+		ERC20AddressRegistrationFee types.Currency `json:"erc20addressregistrationfee"`
+		// TransactionFeeCheckHeight is the block height from which the minimum miner fee
+		// is enforced by the consensus for regular transactions,
+		// prior to it the miner fee only has to be bigger than 0.
+		TransactionFeeCheckHeight types.BlockHeight `json:"txfeecheckheight"`
+		// BotSubNameActivationHeight is the block height from which a 3bot can only claim a sub name,
+		// in case it owns the parent name, or the parent name is delegated to it by the 3bot that owns it,
+		// prior to it any available name can be claimed.
+		BotSubNameActivationHeight types.BlockHeight `json:"botsubnameactivationheight"`
+		// BotGracePeriodActivationHeight is the block height from which the names of an expired 3bot
+		// remain reserved for that 3bot during the BotNameGracePeriod,
+		// prior to it the names are released as soon as the 3bot expires.
+		BotGracePeriodActivationHeight types.BlockHeight `json:"botgraceperiodactivationheight"`
+	}
+
+	// ConsensusParameterUpdate updates a single (named) consensus parameter to a new value.
+	// Block heights are defined as a currency value as well.
+	ConsensusParameterUpdate struct {
+		Name  ConsensusParameterName `json:"name"`
+		Value types.Currency         `json:"value"`
+	}
+)
+
+// Apply the given update to these consensus parameters,
+// returning an error in case the update is not valid.
+func (cp *ConsensusParameters) Apply(update ConsensusParameterUpdate) error {
+	switch update.Name {
+	case ConsensusParameterBotRegistrationFee:
+		cp.BotRegistrationFee = update.Value
+	case ConsensusParameterBotMonthlyFee:
+		cp.BotMonthlyFee = update.Value
+	case ConsensusParameterBotNameFee:
+		cp.BotNameFee = update.Value
+	case ConsensusParameterBotNetworkAddressFee:
+		cp.BotNetworkAddressFee = update.Value
+	case ConsensusParameterBotServiceFee:
+		cp.BotServiceFee = update.Value
+	case ConsensusParameterERC20ConversionMinimum:
+		cp.ERC20ConversionMinimum = update.Value
+	case ConsensusParameterERC20AddressRegistrationFee:
+		cp.ERC20AddressRegistrationFee = update.Value
+	case ConsensusParameterTransactionFeeCheckHeight:
+		return applyBlockHeightUpdate(update, &cp.TransactionFeeCheckHeight)
+	case ConsensusParameterBotSubNameActivationHeight:
+		return applyBlockHeightUpdate(update, &cp.BotSubNameActivationHeight)
+	case ConsensusParameterBotGracePeriodActivationHeight:
+		return applyBlockHeightUpdate(update, &cp.BotGracePeriodActivationHeight)
+	default:
+		return fmt.Errorf("unknown consensus parameter %q", update.Name)
+	}
+	return nil
+}
+
+func applyBlockHeightUpdate(update ConsensusParameterUpdate, height *types.BlockHeight) error {
+	value, err := update.Value.Uint64()
+	if err != nil {
+		return fmt.Errorf("invalid value for consensus parameter %q: %v", update.Name, err)
+	}
+	*height = types.BlockHeight(value)
+	return nil
+}
+
+// IsBlockHeight returns true if the consensus parameter defines a block height,
+// rather than a currency value.
+func (name ConsensusParameterName) IsBlockHeight() bool {
+	switch name {
+	case ConsensusParameterTransactionFeeCheckHeight, ConsensusParameterBotSubNameActivationHeight, ConsensusParameterBotGracePeriodActivationHeight:
+		return true
+	default:
+		return false
+	}
+}
+
+// GetStandardnetGenesisConsensusParameters returns the consensus parameters
+// that apply to the standard network, until updated on-chain.
+func GetStandardnetGenesisConsensusParameters() ConsensusParameters {
+	const (
+		secondsInOneDay                         = 86400 + config.StandardNetworkBlockFrequency // round up
+		daysFromStartOfBlockchainUntil2ndOfJuly = 74
+		txnFeeCheckBlockHeight                  = daysFromStartOfBlockchainUntil2ndOfJuly *
+			(secondsInOneDay / config.StandardNetworkBlockFrequency)
+	)
+	return newGenesisConsensusParameters(txnFeeCheckBlockHeight, UnactivatedBlockHeight)
+}
+
+// GetTestnetGenesisConsensusParameters returns the consensus parameters
+// that apply to the test network, until updated on-chain.
+func GetTestnetGenesisConsensusParameters() ConsensusParameters {
+	const (
+		secondsInOneDay                         = 86400 + config.TestNetworkBlockFrequency // round up
+		daysFromStartOfBlockchainUntil2ndOfJuly = 90
+		txnFeeCheckBlockHeight                  = daysFromStartOfBlockchainUntil2ndOfJuly *
+			(secondsInOneDay / config.TestNetworkBlockFrequency)
+	)
+	return newGenesisConsensusParameters(txnFeeCheckBlockHeight, UnactivatedBlockHeight)
+}
+
+// GetDevnetGenesisConsensusParameters returns the consensus parameters
+// that apply to the dev network, until updated on-chain.
+func GetDevnetGenesisConsensusParameters() ConsensusParameters {
+	return newGenesisConsensusParameters(0, 0)
+}
+
+// newGenesisConsensusParameters creates the genesis consensus parameters of a network,
+// the given activation height applies to all consensus rules introduced after the launch of the networks.
+func newGenesisConsensusParameters(txnFeeCheckBlockHeight, activationHeight types.BlockHeight) ConsensusParameters {
+	oneCoin := config.GetCurrencyUnits().OneCoin
+	return ConsensusParameters{
+		BotRegistrationFee:             oneCoin.Mul64(BotRegistrationFeeMultiplier),
+		BotMonthlyFee:                  oneCoin.Mul64(BotMonthlyFeeMultiplier),
+		BotNameFee:                     oneCoin.Mul64(BotFeePerAdditionalNameMultiplier),
+		BotNetworkAddressFee:           oneCoin.Mul64(BotFeeForNetworkAddressInfoChangeMultiplier),
+		BotServiceFee:                  oneCoin.Mul64(BotFeePerAdditionalServiceMultiplier),
+		ERC20ConversionMinimum:         ERC20ConversionMinimumValue,
+		ERC20AddressRegistrationFee:    oneCoin.Mul64(HardcodedERC20AddressRegistrationFeeOneCoinMultiplier),
+		TransactionFeeCheckHeight:      txnFeeCheckBlockHeight,
+		BotSubNameActivationHeight:     activationHeight,
+		BotGracePeriodActivationHeight: activationHeight,
+	}
+}
+
+// BotNameGracePeriodAt returns the grace period of expired 3bots that applies at the given block height,
+// which is 0 prior to the BotGracePeriodActivationHeight, releasing the names of a 3bot as soon as it expires.
+func (cp *ConsensusParameters) BotNameGracePeriodAt(height types.BlockHeight) CompactTimestamp {
+	if height < cp.BotGracePeriodActivationHeight {
+		return 0
+	}
+	return BotNameGracePeriod
+}
+
+type (
+	// ConsensusParameterGetter allows you to get the consensus parameters,
+	// either those that apply to the next block, or those that apply at a given block height.
+	//
+	// For the daemon this interface could be implemented directly by the DB object
+	// that keeps track of the consensus parameter updates, while for a client this could
+	// come via the REST API from a tfchain daemon in a more indirect way.
+	ConsensusParameterGetter interface {
+		// GetActiveConsensusParameters returns the consensus parameters that apply to the next block.
+		GetActiveConsensusParameters() (ConsensusParameters, error)
+		// GetConsensusParametersAt returns the consensus parameters that apply at the given block height.
+		GetConsensusParametersAt(height types.BlockHeight) (ConsensusParameters, error)
+	}
+)
+
+// getBlockHeightForContext returns the height of the block that a transaction validated within the given context
+// is (to be) part of. Unconfirmed transactions are validated on top of the last block, and thus for the next block.
+func getBlockHeightForContext(ctx types.ValidationContext) types.BlockHeight {
+	if !ctx.Confirmed {
+		return ctx.BlockHeight + 1
+	}
+	return ctx.BlockHeight
+}
+
+// getConsensusParametersForContext returns the consensus parameters that apply to a transaction
+// validated within the given context.
+func getConsensusParametersForContext(getter ConsensusParameterGetter, ctx types.ValidationContext) (ConsensusParameters, error) {
+	height := getBlockHeightForContext(ctx)
+	params, err := getter.GetConsensusParametersAt(height)
+	if err != nil {
+		return ConsensusParameters{}, fmt.Errorf("failed to get the consensus parameters at block height %d: %v", height, err)
+	}
+	return params, nil
+}
+
+// isActivatedForContext returns true if a consensus rule, activated at the given block height,
+// applies to a transaction validated within the given context.
+func isActivatedForContext(activationHeight types.BlockHeight, ctx types.ValidationContext) bool {
+	return getBlockHeightForContext(ctx) >= activationHeight
+}
+
+type (
+	// ConsensusParameterUpdateTransaction is to be created only by the defined Coin Minters,
+	// as a medium in order to update one or multiple consensus parameters,
+	// effective from a future block height onwards.
+	ConsensusParameterUpdateTransaction struct {
+		// Nonce used to ensure the uniqueness of a ConsensusParameterUpdateTransaction's ID and signature.
+		Nonce TransactionNonce `json:"nonce"`
+		// MintFulfillment defines the fulfillment which is used in order to
+		// fulfill the globally defined MintCondition.
+		MintFulfillment types.UnlockFulfillmentProxy `json:"mintfulfillment"`
+		// EffectiveHeight defines the block height from which the updates apply,
+		// it has to be higher than the height of the block the transaction is part of.
+		EffectiveHeight types.BlockHeight `json:"effectiveheight"`
+		// Updates defines the (named) consensus parameters to update, and their new values.
+		Updates []ConsensusParameterUpdate `json:"updates"`
+		// Minerfees, a fee paid for this consensus parameter update transaction.
+		MinerFees []types.Currency `json:"minerfees"`
+		// ArbitraryData can be used for any purpose,
+		// but is mostly to be used in order to define the reason
+		// of the update of the consensus parameters.
+		ArbitraryData []byte `json:"arbitrarydata,omitempty"`
+	}
+	// ConsensusParameterUpdateTransactionExtension defines the ConsensusParameterUpdateTx Extension Data
+	ConsensusParameterUpdateTransactionExtension struct {
+		Nonce           TransactionNonce
+		MintFulfillment types.UnlockFulfillmentProxy
+		EffectiveHeight types.BlockHeight
+		Updates         []ConsensusParameterUpdate
+	}
+)
+
+// ConsensusParameterUpdateTransactionFromTransaction creates a ConsensusParameterUpdateTransaction,
+// using a regular in-memory tfchain transaction.
+//
+// Past the (tx) Version validation it piggy-backs onto the
+// `ConsensusParameterUpdateTransactionFromTransactionData` constructor.
+func ConsensusParameterUpdateTransactionFromTransaction(tx types.Transaction) (ConsensusParameterUpdateTransaction, error) {
+	if tx.Version != TransactionVersionConsensusParameterUpdate {
+		return ConsensusParameterUpdateTransaction{}, fmt.Errorf(
+			"a consensus parameter update transaction requires tx version %d",
+			TransactionVersionConsensusParameterUpdate)
+	}
+	return ConsensusParameterUpdateTransactionFromTransactionData(types.TransactionData{
+		CoinInputs:        tx.CoinInputs,
+		CoinOutputs:       tx.CoinOutputs,
+		BlockStakeInputs:  tx.BlockStakeInputs,
+		BlockStakeOutputs: tx.BlockStakeOutputs,
+		MinerFees:         tx.MinerFees,
+		ArbitraryData:     tx.ArbitraryData,
+		Extension:         tx.Extension,
+	})
+}
+
+// ConsensusParameterUpdateTransactionFromTransactionData creates a ConsensusParameterUpdateTransaction,
+// using the TransactionData from a regular in-memory tfchain transaction.
+func ConsensusParameterUpdateTransactionFromTransactionData(txData types.TransactionData) (ConsensusParameterUpdateTransaction, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid ConsensusParameterUpdateTransactionExtension,
+	// which contains the nonce, the mintFulfillment that can be used to fulfill the currently globally defined mint condition,
+	// as well as the effective height and the parameter updates.
+	extensionData, ok := txData.Extension.(*ConsensusParameterUpdateTransactionExtension)
+	if !ok {
+		return ConsensusParameterUpdateTransaction{}, errors.New("invalid extension data for a ConsensusParameterUpdateTransaction")
+	}
+	// at least one miner fee is required
+	if len(txData.MinerFees) == 0 {
+		return ConsensusParameterUpdateTransaction{}, errors.New("at least one miner fee is required for a ConsensusParameterUpdateTransaction")
+	}
+	// no coin inputs/outputs, block stake inputs or block stake outputs are allowed
+	if len(txData.CoinInputs) != 0 || len(txData.CoinOutputs) != 0 || len(txData.BlockStakeInputs) != 0 || len(txData.BlockStakeOutputs) != 0 {
+		return ConsensusParameterUpdateTransaction{}, errors.New(
+			"no coin inputs/outputs and block stake inputs/outputs are allowed in a ConsensusParameterUpdateTransaction")
+	}
+	// return the ConsensusParameterUpdateTransaction, with the data extracted from the TransactionData
+	return ConsensusParameterUpdateTransaction{
+		Nonce:           extensionData.Nonce,
+		MintFulfillment: extensionData.MintFulfillment,
+		EffectiveHeight: extensionData.EffectiveHeight,
+		Updates:         extensionData.Updates,
+		MinerFees:       txData.MinerFees,
+		// ArbitraryData is optional
+		ArbitraryData: txData.ArbitraryData,
+	}, nil
+}
+
+// TransactionData returns this ConsensusParameterUpdateTransaction
+// as regular tfchain transaction data.
+func (cputx *ConsensusParameterUpdateTransaction) TransactionData() types.TransactionData {
+	return types.TransactionData{
+		MinerFees:     cputx.MinerFees,
+		ArbitraryData: cputx.ArbitraryData,
+		Extension: &ConsensusParameterUpdateTransactionExtension{
+			Nonce:           cputx.Nonce,
+			MintFulfillment: cputx.MintFulfillment,
+			EffectiveHeight: cputx.EffectiveHeight,
+			Updates:         cputx.Updates,
+		},
+	}
+}
+
+// Transaction returns this ConsensusParameterUpdateTransaction
+// as regular tfchain transaction, using TransactionVersionConsensusParameterUpdate as the type.
+func (cputx *ConsensusParameterUpdateTransaction) Transaction() types.Transaction {
+	return types.Transaction{
+		Version:       TransactionVersionConsensusParameterUpdate,
+		MinerFees:     cputx.MinerFees,
+		ArbitraryData: cputx.ArbitraryData,
+		Extension: &ConsensusParameterUpdateTransactionExtension{
+			Nonce:           cputx.Nonce,
+			MintFulfillment: cputx.MintFulfillment,
+			EffectiveHeight: cputx.EffectiveHeight,
+			Updates:         cputx.Updates,
+		},
+	}
+}
+
+type (
+	// ConsensusParameterUpdateTransactionController defines a tfchain-specific transaction controller,
+	// for a transaction type reserved at type 0x84. It allows the coin minters to update consensus parameters.
+	ConsensusParameterUpdateTransactionController struct {
+		// MintConditionGetter is used to get a mint condition at the context-defined block height.
+		//
+		// The found MintCondition defines the condition that has to be fulfilled
+		// in order to update the consensus parameters.
+		MintConditionGetter MintConditionGetter
+	}
+)
+
+var (
+	// ensure at compile time that ConsensusParameterUpdateTransactionController
+	// implements the desired interfaces
+	_ types.TransactionController      = ConsensusParameterUpdateTransactionController{}
+	_ types.TransactionExtensionSigner = ConsensusParameterUpdateTransactionController{}
+	_ types.TransactionValidator       = ConsensusParameterUpdateTransactionController{}
+	_ types.CoinOutputValidator        = ConsensusParameterUpdateTransactionController{}
+	_ types.BlockStakeOutputValidator  = ConsensusParameterUpdateTransactionController{}
+	_ types.TransactionSignatureHasher = ConsensusParameterUpdateTransactionController{}
+	_ types.TransactionIDEncoder       = ConsensusParameterUpdateTransactionController{}
+)
+
+// EncodeTransactionData implements TransactionController.EncodeTransactionData
+func (cputc ConsensusParameterUpdateTransactionController) EncodeTransactionData(w io.Writer, txData types.TransactionData) error {
+	cputx, err := ConsensusParameterUpdateTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a ConsensusParameterUpdateTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).Encode(cputx)
+}
+
+// DecodeTransactionData implements TransactionController.DecodeTransactionData
+func (cputc ConsensusParameterUpdateTransactionController) DecodeTransactionData(r io.Reader) (types.TransactionData, error) {
+	var cputx ConsensusParameterUpdateTransaction
+	err := rivbin.NewDecoder(r).Decode(&cputx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to binary-decode tx as a ConsensusParameterUpdateTx: %v", err)
+	}
+	// return consensus parameter update tx as regular tfchain tx data
+	return cputx.TransactionData(), nil
+}
+
+// JSONEncodeTransactionData implements TransactionController.JSONEncodeTransactionData
+func (cputc ConsensusParameterUpdateTransactionController) JSONEncodeTransactionData(txData types.TransactionData) ([]byte, error) {
+	cputx, err := ConsensusParameterUpdateTransactionFromTransactionData(txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert txData to a ConsensusParameterUpdateTx: %v", err)
+	}
+	return json.Marshal(cputx)
+}
+
+// JSONDecodeTransactionData implements TransactionController.JSONDecodeTransactionData
+func (cputc ConsensusParameterUpdateTransactionController) JSONDecodeTransactionData(data []byte) (types.TransactionData, error) {
+	var cputx ConsensusParameterUpdateTransaction
+	err := json.Unmarshal(data, &cputx)
+	if err != nil {
+		return types.TransactionData{}, fmt.Errorf(
+			"failed to json-decode tx as a ConsensusParameterUpdateTx: %v", err)
+	}
+	// return consensus parameter update tx as regular tfchain tx data
+	return cputx.TransactionData(), nil
+}
+
+// SignExtension implements TransactionExtensionSigner.SignExtension
+func (cputc ConsensusParameterUpdateTransactionController) SignExtension(extension interface{}, sign func(*types.UnlockFulfillmentProxy, types.UnlockConditionProxy, ...interface{}) error) (interface{}, error) {
+	// (tx) extension (data) is expected to be a pointer to a valid ConsensusParameterUpdateTransactionExtension,
+	// which contains the nonce and the mintFulfillment that can be used to fulfill the globally defined mint condition
+	cpuTxExtension, ok := extension.(*ConsensusParameterUpdateTransactionExtension)
+	if !ok {
+		return nil, errors.New("invalid extension data for a ConsensusParameterUpdateTx")
+	}
+
+	// get the active mint condition and use it to sign
+	mintCondition, err := cputc.MintConditionGetter.GetActiveMintCondition()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the active mint condition: %v", err)
+	}
+	err = sign(&cpuTxExtension.MintFulfillment, mintCondition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign mint fulfillment of ConsensusParameterUpdateTx: %v", err)
+	}
+	return cpuTxExtension, nil
+}
+
+// ValidateTransaction implements TransactionValidator.ValidateTransaction
+func (cputc ConsensusParameterUpdateTransactionController) ValidateTransaction(t types.Transaction, ctx types.ValidationContext, constants types.TransactionValidationConstants) (err error) {
+	err = types.TransactionFitsInABlock(t, constants.BlockSizeLimit)
+	if err != nil {
+		return err
+	}
+
+	// get ConsensusParameterUpdateTx
+	cputx, err := ConsensusParameterUpdateTransactionFromTransaction(t)
+	if err != nil {
+		return fmt.Errorf("failed to use tx as a consensus parameter update tx: %v", err)
+	}
+
+	// the updates can only apply to future blocks,
+	// such that a block is always validated against parameters known prior to it
+	height := ctx.BlockHeight
+	if !ctx.Confirmed {
+		height++
+	}
+	if cputx.EffectiveHeight <= height {
+		return fmt.Errorf("consensus parameter updates have to be effective from a future block height: %d <= %d",
+			cputx.EffectiveHeight, height)
+	}
+	// validate the updates themselves
+	err = validateConsensusParameterUpdates(cputx.Updates)
+	if err != nil {
+		return err
+	}
+
+	// get MintCondition
+	mintCondition, err := cputc.MintConditionGetter.GetMintConditionAt(ctx.BlockHeight)
+	if err != nil {
+		return fmt.Errorf("failed to get mint condition at block height %d: %v", ctx.BlockHeight, err)
+	}
+
+	// check if MintFulfillment fulfills the Globally defined MintCondition for the context-defined block height
+	err = mintCondition.Fulfill(cputx.MintFulfillment, types.FulfillContext{
+		BlockHeight: ctx.BlockHeight,
+		BlockTime:   ctx.BlockTime,
+		Transaction: t,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fulfill mint condition: %v", err)
+	}
+	// ensure the Nonce is not Nil
+	if cputx.Nonce == (TransactionNonce{}) {
+		return errors.New("nil nonce is not allowed for a consensus parameter update transaction")
+	}
+
+	// validate the rest of the content
+	err = types.ArbitraryDataFits(cputx.ArbitraryData, constants.ArbitraryDataSizeLimit)
+	if err != nil {
+		return
+	}
+	for _, fee := range cputx.MinerFees {
+		if fee.Cmp(constants.MinimumMinerFee) == -1 {
+			return types.ErrTooSmallMinerFee
+		}
+	}
+	return
+}
+
+// validateConsensusParameterUpdates ensures at least one update is defined,
+// and that all updates are valid and update a unique (known) parameter.
+func validateConsensusParameterUpdates(updates []ConsensusParameterUpdate) error {
+	if len(updates) == 0 {
+		return errors.New("a consensus parameter update transaction requires at least one update")
+	}
+	var params ConsensusParameters
+	names := make(map[ConsensusParameterName]struct{}, len(updates))
+	for _, update := range updates {
+		if _, found := names[update.Name]; found {
+			return fmt.Errorf("consensus parameter %q is updated more than once", update.Name)
+		}
+		names[update.Name] = struct{}{}
+		err := params.Apply(update)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateCoinOutputs implements CoinOutputValidator.ValidateCoinOutputs
+func (cputc ConsensusParameterUpdateTransactionController) ValidateCoinOutputs(t types.Transaction, ctx types.FundValidationContext, coinInputs map[types.CoinOutputID]types.CoinOutput) (err error) {
+	return nil // always valid, no coin inputs/outputs exist within a consensus parameter update transaction
+}
+
+// ValidateBlockStakeOutputs implements BlockStakeOutputValidator.ValidateBlockStakeOutputs
+func (cputc ConsensusParameterUpdateTransactionController) ValidateBlockStakeOutputs(t types.Transaction, ctx types.FundValidationContext, blockStakeInputs map[types.BlockStakeOutputID]types.BlockStakeOutput) (err error) {
+	return nil // always valid, no block stake inputs/outputs exist within a consensus parameter update transaction
+}
+
+// SignatureHash implements TransactionSignatureHasher.SignatureHash
+func (cputc ConsensusParameterUpdateTransactionController) SignatureHash(t types.Transaction, extraObjects ...interface{}) (crypto.Hash, error) {
+	cputx, err := ConsensusParameterUpdateTransactionFromTransaction(t)
+	if err != nil {
+		return crypto.Hash{}, fmt.Errorf("failed to use tx as a ConsensusParameterUpdateTx: %v", err)
+	}
+
+	h := crypto.NewHash()
+	enc := rivbin.NewEncoder(h)
+
+	enc.EncodeAll(
+		t.Version,
+		SpecifierConsensusParameterUpdateTransaction,
+		cputx.Nonce,
+	)
+
+	if len(extraObjects) > 0 {
+		enc.EncodeAll(extraObjects...)
+	}
+
+	enc.EncodeAll(
+		cputx.EffectiveHeight,
+		cputx.Updates,
+		cputx.MinerFees,
+		cputx.ArbitraryData,
+	)
+
+	var hash crypto.Hash
+	h.Sum(hash[:0])
+	return hash, nil
+}
+
+// EncodeTransactionIDInput implements TransactionIDEncoder.EncodeTransactionIDInput
+func (cputc ConsensusParameterUpdateTransactionController) EncodeTransactionIDInput(w io.Writer, txData types.TransactionData) error {
+	cputx, err := ConsensusParameterUpdateTransactionFromTransactionData(txData)
+	if err != nil {
+		return fmt.Errorf("failed to convert txData to a ConsensusParameterUpdateTx: %v", err)
+	}
+	return rivbin.NewEncoder(w).EncodeAll(SpecifierConsensusParameterUpdateTransaction, cputx)
+}
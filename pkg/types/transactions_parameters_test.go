@@ -0,0 +1,277 @@
+package types
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/threefoldfoundation/tfchain/pkg/config"
+	"github.com/threefoldtech/rivine/pkg/encoding/siabin"
+	"github.com/threefoldtech/rivine/types"
+)
+
+func TestConsensusParameterUpdateTransactionBinaryEncodingAndID(t *testing.T) {
+	types.RegisterTransactionVersion(TransactionVersionConsensusParameterUpdate, ConsensusParameterUpdateTransactionController{})
+	defer types.RegisterTransactionVersion(TransactionVersionConsensusParameterUpdate, nil)
+
+	const (
+		jsonEncodedTx = `{"version":132,"data":{"nonce":"FoAiO8vN2eU=","mintfulfillment":{"type":1,"data":{"publickey":"ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780","signature":"c347b6e216291164edf57aaed430c6e09d71a031a015345c6107a18489605d12eac5292cc0d253a1757072e88a77632ced610c3e82d9929d6204fda53cc2ad0c"}},"effectiveheight":300000,"updates":[{"name":"botmonthlyfee","value":"5000000000"},{"name":"erc20conversionminimum","value":"500000000000"}],"minerfees":["1000000000"],"arbitrarydata":"aGFsdmUgdGhlIG1vbnRobHkgM2JvdCBmZWU="}}`
+		hexEncodedTx  = `841680223bcbcdd9e501c401d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d778080c347b6e216291164edf57aaed430c6e09d71a031a015345c6107a18489605d12eac5292cc0d253a1757072e88a77632ced610c3e82d9929d6204fda53cc2ad0ce093040000000000041a626f746d6f6e74686c796665650a012a05f2002c6572633230636f6e76657273696f6e6d696e696d756d0a746a52880002083b9aca003468616c766520746865206d6f6e74686c792033626f7420666565`
+	)
+	var tx types.Transaction
+	err := json.Unmarshal([]byte(jsonEncodedTx), &tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := tx.ID()
+	b := siabin.Marshal(tx)
+	if output := hex.EncodeToString(b); output != hexEncodedTx {
+		t.Fatal(hexEncodedTx, "!=", output)
+	}
+
+	// go to consensus parameter update Tx and back
+	cputx, err := ConsensusParameterUpdateTransactionFromTransaction(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oTx := cputx.Transaction()
+	oID := oTx.ID()
+	oB := siabin.Marshal(oTx)
+	if id != oID {
+		t.Fatal(id, "!=", oID)
+	}
+	if !bytes.Equal(b, oB) {
+		t.Fatal(hex.EncodeToString(b), "!=", hex.EncodeToString(oB))
+	}
+
+	// binary decode it again, resulting in the same JSON-encoded transaction
+	var decodedTx types.Transaction
+	err = siabin.Unmarshal(oB, &decodedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err = json.Marshal(decodedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output := string(b); output != jsonEncodedTx {
+		t.Fatal(jsonEncodedTx, "!=", output)
+	}
+}
+
+func TestConsensusParameterUpdateTransactionValidation(t *testing.T) {
+	types.RegisterTransactionVersion(TransactionVersionConsensusParameterUpdate, ConsensusParameterUpdateTransactionController{
+		MintConditionGetter: newInMemoryMintConditionGetter(types.NewCondition(types.NewUnlockHashCondition(
+			types.NewPubKeyUnlockHash(cryptoKeyPair.PublicKey)))),
+	})
+	defer types.RegisterTransactionVersion(TransactionVersionConsensusParameterUpdate, nil)
+
+	validationConstants := types.TransactionValidationConstants{
+		BlockSizeLimit:         config.GetDevnetGenesis().BlockSizeLimit,
+		ArbitraryDataSizeLimit: config.GetDevnetGenesis().ArbitraryDataSizeLimit,
+		MinimumMinerFee:        config.GetDevnetGenesis().MinimumTransactionFee,
+	}
+	const unsignedJSONEncodedTx = `{
+	"version": 132,
+	"data": {
+		"nonce": "FoAiO8vN2eU=",
+		"mintfulfillment": {
+			"type": 1,
+			"data": {
+				"publickey": "ed25519:d285f92d6d449d9abb27f4c6cf82713cec0696d62b8c123f1627e054dc6d7780",
+				"signature": ""
+			}
+		},
+		"effectiveheight": 300000,
+		"updates": [
+			{
+				"name": "botmonthlyfee",
+				"value": "5000000000"
+			},
+			{
+				"name": "erc20conversionminimum",
+				"value": "500000000000"
+			}
+		],
+		"minerfees": ["1000000000"],
+		"arbitrarydata": "aGFsdmUgdGhlIG1vbnRobHkgM2JvdCBmZWU="
+	}
+}`
+	// decode the unsigned consensus parameter update, such that each test case can modify it prior to signing
+	decodeTx := func() ConsensusParameterUpdateTransaction {
+		t.Helper()
+		var tx types.Transaction
+		err := tx.UnmarshalJSON([]byte(unsignedJSONEncodedTx))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cputx, err := ConsensusParameterUpdateTransactionFromTransaction(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cputx
+	}
+	signAndValidate := func(cputx ConsensusParameterUpdateTransaction, ctx types.ValidationContext) error {
+		t.Helper()
+		tx := cputx.Transaction()
+		err := tx.SignExtension(func(fulfillment *types.UnlockFulfillmentProxy, condition types.UnlockConditionProxy, eo ...interface{}) error {
+			return fulfillment.Sign(types.FulfillmentSignContext{
+				ExtraObjects: eo,
+				Transaction:  tx,
+				Key:          cryptoKeyPair.PrivateKey,
+			})
+		})
+		if err != nil {
+			t.Fatal("failed to sign:", err)
+		}
+		return tx.ValidateTransaction(ctx, validationConstants)
+	}
+	confirmedCtx := types.ValidationContext{
+		Confirmed:   true,
+		BlockHeight: 4072,
+		BlockTime:   1534271219,
+	}
+
+	err := signAndValidate(decodeTx(), confirmedCtx)
+	if err != nil {
+		t.Fatal("failed to validate valid consensus parameter update:", err)
+	}
+
+	// updates have to be effective from a future block
+	cputx := decodeTx()
+	cputx.EffectiveHeight = confirmedCtx.BlockHeight
+	err = signAndValidate(cputx, confirmedCtx)
+	if err == nil {
+		t.Error("succeeded to validate consensus parameter update effective from its own block")
+	}
+	// unconfirmed transactions are validated for the next block
+	cputx = decodeTx()
+	cputx.EffectiveHeight = confirmedCtx.BlockHeight + 1
+	err = signAndValidate(cputx, types.ValidationContext{
+		BlockHeight: confirmedCtx.BlockHeight,
+		BlockTime:   confirmedCtx.BlockTime,
+	})
+	if err == nil {
+		t.Error("succeeded to validate unconfirmed consensus parameter update effective from the next block")
+	}
+
+	// at least one update is required
+	cputx = decodeTx()
+	cputx.Updates = nil
+	err = signAndValidate(cputx, confirmedCtx)
+	if err == nil {
+		t.Error("succeeded to validate consensus parameter update without updates")
+	}
+	// only known parameters can be updated
+	cputx = decodeTx()
+	cputx.Updates = []ConsensusParameterUpdate{{Name: "foo", Value: types.NewCurrency64(1)}}
+	err = signAndValidate(cputx, confirmedCtx)
+	if err == nil {
+		t.Error("succeeded to validate consensus parameter update of an unknown parameter")
+	}
+	// each parameter can only be updated once
+	cputx = decodeTx()
+	cputx.Updates = []ConsensusParameterUpdate{
+		{Name: ConsensusParameterBotNameFee, Value: types.NewCurrency64(1)},
+		{Name: ConsensusParameterBotNameFee, Value: types.NewCurrency64(2)},
+	}
+	err = signAndValidate(cputx, confirmedCtx)
+	if err == nil {
+		t.Error("succeeded to validate consensus parameter update which updates a parameter twice")
+	}
+
+	// the update has to be authorized by the mint condition
+	cputx = decodeTx()
+	cputx.MintFulfillment = types.NewFulfillment(types.NewSingleSignatureFulfillment(newTestKeyPair(1).PublicKey))
+	tx := cputx.Transaction()
+	err = tx.ValidateTransaction(confirmedCtx, validationConstants)
+	if err == nil {
+		t.Error("succeeded to validate unauthorized consensus parameter update")
+	}
+}
+
+func TestConsensusParametersApply(t *testing.T) {
+	params := GetDevnetGenesisConsensusParameters()
+	err := params.Apply(ConsensusParameterUpdate{Name: ConsensusParameterBotMonthlyFee, Value: types.NewCurrency64(42)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !params.BotMonthlyFee.Equals64(42) {
+		t.Error("unexpected bot monthly fee:", params.BotMonthlyFee.String())
+	}
+	err = params.Apply(ConsensusParameterUpdate{Name: ConsensusParameterTransactionFeeCheckHeight, Value: types.NewCurrency64(1000)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.TransactionFeeCheckHeight != 1000 {
+		t.Error("unexpected transaction fee check height:", params.TransactionFeeCheckHeight)
+	}
+	err = params.Apply(ConsensusParameterUpdate{Name: ConsensusParameterTransactionFeeCheckHeight, Value: types.NewCurrency64(1).Mul64(1 << 32).Mul64(1 << 32)})
+	if err == nil {
+		t.Error("succeeded to apply a block height which does not fit in 64 bits")
+	}
+	err = params.Apply(ConsensusParameterUpdate{Name: "foo", Value: types.NewCurrency64(1)})
+	if err == nil {
+		t.Error("succeeded to apply an update of an unknown consensus parameter")
+	}
+}
+
+func TestGenesisConsensusParametersMatchRequiredBotFees(t *testing.T) {
+	oneCoin := config.GetCurrencyUnits().OneCoin
+	params := GetStandardnetGenesisConsensusParameters()
+	brtx := BotRegistrationTransaction{
+		Names:      []BotName{mustNewBotName(t, "chatbot.example"), mustNewBotName(t, "chatbot.example2")},
+		NrOfMonths: 12,
+	}
+	// 90 TFT registration, 12 months with a 30% discount, and 1 additional name
+	if fee := brtx.RequiredBotFee(params); !fee.Equals(oneCoin.Mul64(90 + 84 + 50)) {
+		t.Error("unexpected bot fee:", fee.String())
+	}
+	// the fees follow the consensus parameters
+	err := params.Apply(ConsensusParameterUpdate{Name: ConsensusParameterBotRegistrationFee, Value: oneCoin.Mul64(100)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee := brtx.RequiredBotFee(params); !fee.Equals(oneCoin.Mul64(100 + 84 + 50)) {
+		t.Error("unexpected bot fee:", fee.String())
+	}
+}
+
+// staticConsensusParameterGetter is a ConsensusParameterGetter
+// which returns the same consensus parameters at any block height.
+type staticConsensusParameterGetter ConsensusParameters
+
+// GetActiveConsensusParameters implements ConsensusParameterGetter.GetActiveConsensusParameters
+func (getter staticConsensusParameterGetter) GetActiveConsensusParameters() (ConsensusParameters, error) {
+	return ConsensusParameters(getter), nil
+}
+
+// GetConsensusParametersAt implements ConsensusParameterGetter.GetConsensusParametersAt
+func (getter staticConsensusParameterGetter) GetConsensusParametersAt(height types.BlockHeight) (ConsensusParameters, error) {
+	return ConsensusParameters(getter), nil
+}
+
+// updatedConsensusParameterGetter is a ConsensusParameterGetter
+// which returns the genesis consensus parameters for any block height
+// prior to the effective height of the update, and the updated consensus parameters otherwise.
+type updatedConsensusParameterGetter struct {
+	Genesis         ConsensusParameters
+	EffectiveHeight types.BlockHeight
+	Update          ConsensusParameterUpdate
+}
+
+// GetActiveConsensusParameters implements ConsensusParameterGetter.GetActiveConsensusParameters
+func (getter updatedConsensusParameterGetter) GetActiveConsensusParameters() (ConsensusParameters, error) {
+	return getter.GetConsensusParametersAt(getter.EffectiveHeight)
+}
+
+// GetConsensusParametersAt implements ConsensusParameterGetter.GetConsensusParametersAt
+func (getter updatedConsensusParameterGetter) GetConsensusParametersAt(height types.BlockHeight) (ConsensusParameters, error) {
+	params := getter.Genesis
+	if height < getter.EffectiveHeight {
+		return params, nil
+	}
+	err := params.Apply(getter.Update)
+	return params, err
+}
@@ -74,7 +74,7 @@ func TestMinimumFeeValidationForTransactions(t *testing.T) {
 		ArbitraryDataSizeLimit: constants.ArbitraryDataSizeLimit,
 		MinimumMinerFee:        constants.MinimumTransactionFee,
 	}
-	RegisterTransactionTypesForStandardNetwork(nil, NopERC20TransactionValidator{}, types.Currency{}, config.DaemonNetworkConfig{}) // no MintConditionGetter is required for this test
+	RegisterTransactionTypesForStandardNetwork(newConsensusParameterReadDB(GetStandardnetGenesisConsensusParameters()), NopERC20TransactionValidator{}, types.Currency{}, config.DaemonNetworkConfig{}) // only consensus parameters are required for this test
 	testMinimumFeeValidationForTransactions(t, "standard", validationConstants)
 	constants = config.GetTestnetGenesis()
 	validationConstants = types.TransactionValidationConstants{
@@ -82,7 +82,7 @@ func TestMinimumFeeValidationForTransactions(t *testing.T) {
 		ArbitraryDataSizeLimit: constants.ArbitraryDataSizeLimit,
 		MinimumMinerFee:        constants.MinimumTransactionFee,
 	}
-	RegisterTransactionTypesForTestNetwork(nil, NopERC20TransactionValidator{}, types.Currency{}, config.DaemonNetworkConfig{}) // no MintConditionGetter is required for this test
+	RegisterTransactionTypesForTestNetwork(newConsensusParameterReadDB(GetTestnetGenesisConsensusParameters()), NopERC20TransactionValidator{}, types.Currency{}, config.DaemonNetworkConfig{}) // only consensus parameters are required for this test
 	testMinimumFeeValidationForTransactions(t, "test", validationConstants)
 	constants = config.GetDevnetGenesis()
 	validationConstants = types.TransactionValidationConstants{
@@ -90,7 +90,7 @@ func TestMinimumFeeValidationForTransactions(t *testing.T) {
 		ArbitraryDataSizeLimit: constants.ArbitraryDataSizeLimit,
 		MinimumMinerFee:        constants.MinimumTransactionFee,
 	}
-	RegisterTransactionTypesForDevNetwork(nil, NopERC20TransactionValidator{}, types.Currency{}, config.DaemonNetworkConfig{}) // no MintConditionGetter is required for this test
+	RegisterTransactionTypesForDevNetwork(newConsensusParameterReadDB(GetDevnetGenesisConsensusParameters()), NopERC20TransactionValidator{}, types.Currency{}, config.DaemonNetworkConfig{}) // only consensus parameters are required for this test
 	testMinimumFeeValidationForTransactions(t, "dev", validationConstants)
 }
 
@@ -2163,6 +2163,24 @@ type (
 	}
 )
 
+// consensusParameterReadDB is a TFChainReadDB which only implements the ConsensusParameterGetter,
+// returning the same consensus parameters at any block height.
+type consensusParameterReadDB struct {
+	MintConditionGetter
+	MintingCapGetter
+	BotRecordReadRegistry
+	ERC20Registry
+	FarmerConditionGetter
+	FoundationConditionGetter
+	FarmRecordReadRegistry
+	CapacityProofReadRegistry
+	staticConsensusParameterGetter
+}
+
+func newConsensusParameterReadDB(params ConsensusParameters) TFChainReadDB {
+	return consensusParameterReadDB{staticConsensusParameterGetter: staticConsensusParameterGetter(params)}
+}
+
 // newInMemoryMintConditionGetter creates a new inMemoryMintConditionGetterr,
 // applying all given mint conditions using their index-as-given-in-order as the represenative block height.
 func newInMemoryMintConditionGetter(mintConditions ...types.UnlockConditionProxy) *inMemoryMintConditionGetter {
@@ -2308,7 +2326,9 @@ func TestComputeMonthlyBotFees(t *testing.T) {
 		{math.MaxUint8, types.NewCurrency64(12750)},
 	}
 	for idx, testCase := range testCases {
-		fee := ComputeMonthlyBotFees(testCase.NrOfMonths, oneCoin)
+		fee := ComputeMonthlyBotFees(testCase.NrOfMonths, ConsensusParameters{
+			BotMonthlyFee: oneCoin.Mul64(BotMonthlyFeeMultiplier),
+		})
 		if !fee.Equals(testCase.ExpectedFee) {
 			t.Error(idx, testCase.NrOfMonths, "unexpected result", fee, "!=", testCase.ExpectedFee)
 		}